@@ -1,12 +1,14 @@
 package goex
 
+import "context"
+
 // api interface
 
 type API interface {
-	LimitBuy(amount, price string, currency CurrencyPair) (*Order, error)
-	LimitSell(amount, price string, currency CurrencyPair) (*Order, error)
-	MarketBuy(amount, price string, currency CurrencyPair) (*Order, error)
-	MarketSell(amount, price string, currency CurrencyPair) (*Order, error)
+	LimitBuy(amount, price Decimal, currency CurrencyPair) (*Order, error)
+	LimitSell(amount, price Decimal, currency CurrencyPair) (*Order, error)
+	MarketBuy(amount, price Decimal, currency CurrencyPair) (*Order, error)
+	MarketSell(amount, price Decimal, currency CurrencyPair) (*Order, error)
 	CancelOrder(orderId string, currency CurrencyPair) (bool, error)
 	GetOneOrder(orderId string, currency CurrencyPair) (*Order, error)
 	GetUnfinishOrders(currency CurrencyPair) ([]Order, error)
@@ -15,9 +17,33 @@ type API interface {
 
 	GetTicker(currency CurrencyPair) (*Ticker, error)
 	GetDepth(size int, currency CurrencyPair) (*Depth, error)
+	//size根K线，从since(毫秒)起，since为0时取最新的；旧的在前，Timestamp为秒
 	GetKlineRecords(currency CurrencyPair, period, size, since int) ([]Kline, error)
-	//非个人，整个交易所的交易记录
+	//非个人，整个交易所的交易记录；旧的在前，Date为毫秒。since为毫秒，TradesSince()为TRADES_SINCE_ID的交易所为成交ID
 	GetTrades(currencyPair CurrencyPair, since int64) ([]Trade, error)
 
+	GetExchangeName() string
+	Capabilities() Capabilities
+}
+
+// APIWithContext mirrors API, but every call takes a context.Context so the
+// caller can cancel it or give it a deadline.
+type APIWithContext interface {
+	LimitBuyCtx(ctx context.Context, amount, price Decimal, currency CurrencyPair) (*Order, error)
+	LimitSellCtx(ctx context.Context, amount, price Decimal, currency CurrencyPair) (*Order, error)
+	MarketBuyCtx(ctx context.Context, amount, price Decimal, currency CurrencyPair) (*Order, error)
+	MarketSellCtx(ctx context.Context, amount, price Decimal, currency CurrencyPair) (*Order, error)
+	CancelOrderCtx(ctx context.Context, orderId string, currency CurrencyPair) (bool, error)
+	GetOneOrderCtx(ctx context.Context, orderId string, currency CurrencyPair) (*Order, error)
+	GetUnfinishOrdersCtx(ctx context.Context, currency CurrencyPair) ([]Order, error)
+	GetOrderHistorysCtx(ctx context.Context, currency CurrencyPair, currentPage, pageSize int) ([]Order, error)
+	GetAccountCtx(ctx context.Context) (*Account, error)
+
+	GetTickerCtx(ctx context.Context, currency CurrencyPair) (*Ticker, error)
+	GetDepthCtx(ctx context.Context, size int, currency CurrencyPair) (*Depth, error)
+	GetKlineRecordsCtx(ctx context.Context, currency CurrencyPair, period, size, since int) ([]Kline, error)
+	//非个人，整个交易所的交易记录
+	GetTradesCtx(ctx context.Context, currencyPair CurrencyPair, since int64) ([]Trade, error)
+
 	GetExchangeName() string
 }
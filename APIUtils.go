@@ -1,6 +1,7 @@
 package goex
 
 import (
+	"context"
 	"fmt"
 	"log"
 	"reflect"
@@ -12,6 +13,8 @@ import (
   @method 调用的函数，比如: api.GetTicker ,注意：不是api.GetTicker(...)
   @params 参数,顺序一定要按照实际调用函数入参顺序一样
   @return 返回
+
+  Deprecated: RE panics when the retries run out and retries orders as well, use RetryPolicy or RetryAPI.
 */
 func RE(retry int, method interface{}, params ...interface{}) interface{} {
 
@@ -66,21 +69,26 @@ func CancelAllUnfinishedOrders(api API, currencyPair CurrencyPair) int {
 		return -1
 	}
 
-	orders := RE(10, api.GetUnfinishOrders, currencyPair)
-	if orders != nil {
-		c := 0
-		for _, ord := range orders.([]Order) {
-			_, err := api.CancelOrder(fmt.Sprintf("%d", ord.OrderID), currencyPair)
-			if err != nil {
-				log.Println(err)
-			}
-			c++
-			time.Sleep(100 * time.Millisecond) //控制频率
-		}
+	var orders []Order
+	err := DefaultRetryPolicy.Do(context.Background(), func() (err error) {
+		orders, err = api.GetUnfinishOrders(currencyPair)
+		return err
+	})
+	if err != nil {
+		log.Println(err)
+		return 0
+	}
 
-		return c
+	c := 0
+	for _, ord := range orders {
+		_, err := api.CancelOrder(fmt.Sprintf("%d", ord.OrderID), currencyPair)
+		if err != nil {
+			log.Println(err)
+		}
+		c++
+		time.Sleep(100 * time.Millisecond) //控制频率
 	}
-	return 0
+	return c
 }
 
 /**
@@ -92,14 +100,21 @@ func CancelAllUnfinishedFutureOrders(api FutureRestAPI, contractType string, cur
 		return
 	}
 
-	orders := RE(10, api.GetUnfinishFutureOrders, currencyPair, contractType)
-	if orders != nil {
-		for _, ord := range orders.([]Order) {
-			_, err := api.FutureCancelOrder(currencyPair, contractType, fmt.Sprintf("%d", ord.OrderID))
-			if err != nil {
-				log.Println(err)
-			}
-			time.Sleep(100 * time.Millisecond) //控制频率
+	var orders []FutureOrder
+	err := DefaultRetryPolicy.Do(context.Background(), func() (err error) {
+		orders, err = api.GetUnfinishFutureOrders(currencyPair, contractType)
+		return err
+	})
+	if err != nil {
+		log.Println(err)
+		return
+	}
+
+	for _, ord := range orders {
+		_, err := api.FutureCancelOrder(currencyPair, contractType, fmt.Sprintf("%d", ord.OrderID))
+		if err != nil {
+			log.Println(err)
 		}
+		time.Sleep(100 * time.Millisecond) //控制频率
 	}
 }
@@ -1,5 +1,7 @@
 package goex
 
+import "fmt"
+
 type ApiError struct {
 	ErrCode,
 	ErrMsg,
@@ -7,9 +9,24 @@ type ApiError struct {
 }
 
 func (e ApiError) Error() string {
+	if e.OriginErrMsg != "" && e.OriginErrMsg != e.ErrMsg {
+		return fmt.Sprintf("%s: %s", e.ErrMsg, e.OriginErrMsg)
+	}
 	return e.ErrMsg
 }
 
+// Is reports whether target is an ApiError of the same kind, so errors.Is(err, EX_ERR_API_LIMIT)
+// matches regardless of the exchange payload carried in OriginErrMsg.
+func (e ApiError) Is(target error) bool {
+	switch t := target.(type) {
+	case ApiError:
+		return e.ErrCode == t.ErrCode
+	case *ApiError:
+		return t != nil && e.ErrCode == t.ErrCode
+	}
+	return false
+}
+
 var (
 	API_ERR                      = ApiError{ErrCode: "EX_ERR_0000", ErrMsg: "unknown error"}
 	HTTP_ERR_CODE                = ApiError{ErrCode: "HTTP_ERR_0001", ErrMsg: "http request error"}
@@ -22,4 +39,29 @@ var (
 	EX_ERR_CANCEL_ORDER_FAIL     = ApiError{ErrCode: "EX_ERR_0006", ErrMsg: "cancel order failure"}
 	EX_ERR_INVALID_CURRENCY_PAIR = ApiError{ErrCode: "EX_ERR_0007", ErrMsg: "invalid currency pair"}
 	EX_ERR_NOT_FIND_ORDER        = ApiError{ErrCode: "EX_ERR_0008", ErrMsg: "not find order"}
+	EX_ERR_NONCE                 = ApiError{ErrCode: "EX_ERR_0009", ErrMsg: "invalid nonce"}
+	EX_ERR_INVALID_PRECISION     = ApiError{ErrCode: "EX_ERR_0010", ErrMsg: "invalid price or amount precision"}
+	EX_ERR_MAINTENANCE           = ApiError{ErrCode: "EX_ERR_0011", ErrMsg: "exchange under maintenance"}
+
+	//returned by adapters for calls the exchange (or the adapter) does not support, see Capabilities
+	ErrNotSupported = ApiError{ErrCode: "EX_ERR_0012", ErrMsg: "not supported"}
+
+	//OrderBook went out of sync and is waiting for a snapshot
+	EX_ERR_ORDERBOOK_SEQ_GAP  = ApiError{ErrCode: "EX_ERR_0013", ErrMsg: "order book sequence gap"}
+	EX_ERR_ORDERBOOK_CHECKSUM = ApiError{ErrCode: "EX_ERR_0014", ErrMsg: "order book checksum mismatch"}
+
+	//an order outside the pair's SymbolInfo limits
+	EX_ERR_INVALID_ORDER_SIZE = ApiError{ErrCode: "EX_ERR_0015", ErrMsg: "order amount, price or value out of range"}
+	//a limit price too far from the last price, see ValidatedAPI
+	EX_ERR_PRICE_OUT_OF_BAND = ApiError{ErrCode: "EX_ERR_0016", ErrMsg: "price too far from the market"}
+	//a client order id not matching ValidClientOrderID or already used
+	EX_ERR_INVALID_CLIENT_ORDER_ID = ApiError{ErrCode: "EX_ERR_0017", ErrMsg: "invalid client order id"}
+	//no withdrawal with the id given to GetWithdraw
+	EX_ERR_NOT_FIND_WITHDRAW = ApiError{ErrCode: "EX_ERR_0018", ErrMsg: "not find withdraw"}
+	//the FeeSchedule does not know the fee of the currency
+	EX_ERR_UNKNOWN_FEE = ApiError{ErrCode: "EX_ERR_0019", ErrMsg: "unknown withdraw fee"}
+	//a withdrawal amount that does not cover the fee taken from it
+	EX_ERR_INVALID_WITHDRAW_AMOUNT = ApiError{ErrCode: "EX_ERR_0020", ErrMsg: "withdraw amount not above the fee"}
+	//a subscription to a StreamAPI after its Close
+	EX_ERR_STREAM_CLOSED = ApiError{ErrCode: "EX_ERR_0021", ErrMsg: "stream closed"}
 )
@@ -0,0 +1,31 @@
+package goex
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestApiError_Is(t *testing.T) {
+	err := EX_ERR_API_LIMIT
+	err.OriginErrMsg = `{"code":-1003,"msg":"Too many requests."}`
+
+	assert.True(t, EX_ERR_API_LIMIT.Is(err))
+	assert.True(t, err.Is(EX_ERR_API_LIMIT))
+	assert.True(t, err.Is(&EX_ERR_API_LIMIT))
+	assert.True(t, errors.Is(err, EX_ERR_API_LIMIT))
+	assert.False(t, err.Is(EX_ERR_NONCE))
+	assert.False(t, err.Is((*ApiError)(nil)))
+	assert.False(t, err.Is(errors.New("api limited")))
+	assert.False(t, EX_ERR_API_LIMIT.Is(nil))
+}
+
+func TestApiError_Error(t *testing.T) {
+	err := EX_ERR_NOT_FIND_ORDER
+	assert.Equal(t, "not find order", err.Error())
+	err.OriginErrMsg = "EOrder:Unknown order"
+	assert.Equal(t, "not find order: EOrder:Unknown order", err.Error())
+	err.OriginErrMsg = err.ErrMsg
+	assert.Equal(t, "not find order", err.Error())
+}
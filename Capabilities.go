@@ -0,0 +1,46 @@
+package goex
+
+import "strings"
+
+// Capabilities describes the optional features an adapter supports.
+// Calls for an unsupported feature return ErrNotSupported.
+type Capabilities struct {
+	MarketOrder  bool //MarketBuy / MarketSell
+	Kline        bool //GetKlineRecords
+	Trades       bool //GetTrades
+	OrderHistory bool //GetOrderHistorys
+	Withdraw     bool //implements WithdrawAPI
+	Deposit      bool //implements DepositAPI
+	Future       bool //implements FutureRestAPI
+	Margin       bool //margin or lending trading
+	ClientOrder  bool //implements ClientOrderAPI
+	TradeFee     bool //implements TradeFeeAPI
+	MyTrades     bool //implements MyTradesAPI
+	OrderCursor  bool //implements OrderHistoryAPI
+}
+
+func (c Capabilities) String() string {
+	var s []string
+	for _, f := range []struct {
+		name string
+		ok   bool
+	}{
+		{"MarketOrder", c.MarketOrder},
+		{"Kline", c.Kline},
+		{"Trades", c.Trades},
+		{"OrderHistory", c.OrderHistory},
+		{"Withdraw", c.Withdraw},
+		{"Deposit", c.Deposit},
+		{"Future", c.Future},
+		{"Margin", c.Margin},
+		{"ClientOrder", c.ClientOrder},
+		{"TradeFee", c.TradeFee},
+		{"MyTrades", c.MyTrades},
+		{"OrderCursor", c.OrderCursor},
+	} {
+		if f.ok {
+			s = append(s, f.name)
+		}
+	}
+	return "[" + strings.Join(s, ",") + "]"
+}
@@ -0,0 +1,147 @@
+package goex
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+	"regexp"
+	"strconv"
+	"sync"
+)
+
+//ClientOrderAPI is implemented by adapters that tag an order with an id chosen by the caller. Their
+//GetOneOrder and CancelOrder accept the client order id in place of the exchange's order id, so an order
+//whose response was lost to a timeout can still be found.
+type ClientOrderAPI interface {
+	PlaceOrderWithClientID(clientOrderId string, side TradeSide, amount, price Decimal, currency CurrencyPair) (*Order, error)
+}
+
+var clientOrderIdRegexp = regexp.MustCompile(`^[A-Za-z][A-Za-z0-9_-]{0,31}$`)
+
+//ValidClientOrderID checks a client order id: it starts with a letter and has at most 32 letters, digits,
+//'_' or '-', which every supporting exchange accepts. It can't tell a client order id from an exchange's own
+//order id, Kraken's txids or uuids pass it too.
+func ValidClientOrderID(id string) bool {
+	return clientOrderIdRegexp.MatchString(id)
+}
+
+//NewClientOrderID returns a random client order id
+func NewClientOrderID() string {
+	b := make([]byte, 15)
+	rand.Read(b)
+	return "x" + hex.EncodeToString(b)
+}
+
+//PlaceOrder places an order of the side with LimitBuy, LimitSell, MarketBuy or MarketSell
+func PlaceOrder(api API, side TradeSide, amount, price Decimal, currency CurrencyPair) (*Order, error) {
+	switch side {
+	case BUY:
+		return api.LimitBuy(amount, price, currency)
+	case SELL:
+		return api.LimitSell(amount, price, currency)
+	case BUY_MARKET:
+		return api.MarketBuy(amount, price, currency)
+	case SELL_MARKET:
+		return api.MarketSell(amount, price, currency)
+	}
+	errCode := EX_ERR_PLACE_ORDER_FAIL
+	errCode.OriginErrMsg = "unknown side " + side.String()
+	return nil, errCode
+}
+
+//ClientOrderIDs gives client order ids to any API. When the wrapped API implements ClientOrderAPI the ids
+//are passed to the exchange, otherwise they are mapped to the exchange's order ids here, in memory. The mapping
+//only knows orders whose response arrived: an id whose order may have been placed stays reserved but can not be
+//looked up, check GetUnfinishOrders for it.
+type ClientOrderIDs struct {
+	API
+	l   sync.Mutex
+	ids map[string]string //client order id -> exchange order id, "" while unknown
+}
+
+func NewClientOrderIDs(api API) *ClientOrderIDs {
+	return &ClientOrderIDs{API: api, ids: make(map[string]string)}
+}
+
+func (c *ClientOrderIDs) PlaceOrderWithClientID(clientOrderId string, side TradeSide, amount, price Decimal, currency CurrencyPair) (*Order, error) {
+	if !ValidClientOrderID(clientOrderId) {
+		errCode := EX_ERR_INVALID_CLIENT_ORDER_ID
+		errCode.OriginErrMsg = clientOrderId
+		return nil, errCode
+	}
+	if native, ok := c.API.(ClientOrderAPI); ok {
+		return native.PlaceOrderWithClientID(clientOrderId, side, amount, price, currency)
+	}
+
+	c.l.Lock()
+	_, used := c.ids[clientOrderId]
+	if !used {
+		c.ids[clientOrderId] = ""
+	}
+	c.l.Unlock()
+	if used {
+		errCode := EX_ERR_INVALID_CLIENT_ORDER_ID
+		errCode.OriginErrMsg = "duplicate client order id " + clientOrderId
+		return nil, errCode
+	}
+
+	order, err := PlaceOrder(c.API, side, amount, price, currency)
+
+	c.l.Lock()
+	defer c.l.Unlock()
+	if err != nil {
+		//the id can be used again only if the order certainly was not placed
+		if IsNotExecutedError(err) {
+			delete(c.ids, clientOrderId)
+		}
+		return nil, err
+	}
+	c.ids[clientOrderId] = exchangeOrderId(order)
+	order.ClientOrderID = clientOrderId
+	return order, nil
+}
+
+//orderId translates a client order id placed here to the exchange's, other ids are returned unchanged
+func (c *ClientOrderIDs) orderId(id string) (string, error) {
+	if _, ok := c.API.(ClientOrderAPI); ok {
+		return id, nil
+	}
+	c.l.Lock()
+	orderId, isClient := c.ids[id]
+	c.l.Unlock()
+	if !isClient {
+		return id, nil
+	}
+	if orderId == "" {
+		errCode := EX_ERR_NOT_FIND_ORDER
+		errCode.OriginErrMsg = "unknown client order id " + id
+		return "", errCode
+	}
+	return orderId, nil
+}
+
+func (c *ClientOrderIDs) GetOneOrder(orderId string, currency CurrencyPair) (*Order, error) {
+	id, err := c.orderId(orderId)
+	if err != nil {
+		return nil, err
+	}
+	order, err := c.API.GetOneOrder(id, currency)
+	if err == nil && id != orderId {
+		order.ClientOrderID = orderId
+	}
+	return order, err
+}
+
+func (c *ClientOrderIDs) CancelOrder(orderId string, currency CurrencyPair) (bool, error) {
+	id, err := c.orderId(orderId)
+	if err != nil {
+		return false, err
+	}
+	return c.API.CancelOrder(id, currency)
+}
+
+func exchangeOrderId(order *Order) string {
+	if order.OrderID2 != "" {
+		return order.OrderID2
+	}
+	return strconv.Itoa(order.OrderID)
+}
@@ -0,0 +1,108 @@
+package goex
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+//ordersAPI numbers the orders it places, err fails the next one
+type ordersAPI struct {
+	API
+	orders []Order
+	err    error
+}
+
+func (o *ordersAPI) LimitBuy(amount, price Decimal, currency CurrencyPair) (*Order, error) {
+	if err := o.err; err != nil {
+		o.err = nil
+		return nil, err
+	}
+	o.orders = append(o.orders, Order{OrderID: 100 + len(o.orders), Amount: amount, Price: price, Currency: currency, Side: BUY})
+	order := o.orders[len(o.orders)-1]
+	return &order, nil
+}
+
+func (o *ordersAPI) GetOneOrder(orderId string, currency CurrencyPair) (*Order, error) {
+	for _, order := range o.orders {
+		if exchangeOrderId(&order) == orderId {
+			return &order, nil
+		}
+	}
+	return nil, EX_ERR_NOT_FIND_ORDER
+}
+
+func (o *ordersAPI) CancelOrder(orderId string, currency CurrencyPair) (bool, error) {
+	_, err := o.GetOneOrder(orderId, currency)
+	return err == nil, err
+}
+
+func TestValidClientOrderID(t *testing.T) {
+	assert.True(t, ValidClientOrderID("myOrder1"))
+	assert.True(t, ValidClientOrderID("grid_btc-3"))
+	assert.True(t, ValidClientOrderID(NewClientOrderID()))
+	assert.False(t, ValidClientOrderID("1234567"))
+	assert.False(t, ValidClientOrderID(""))
+	assert.False(t, ValidClientOrderID("my order"))
+	assert.False(t, ValidClientOrderID("x123456789012345678901234567890123"))
+	assert.NotEqual(t, NewClientOrderID(), NewClientOrderID())
+}
+
+func TestClientOrderIDs(t *testing.T) {
+	inner := new(ordersAPI)
+	api := NewClientOrderIDs(inner)
+
+	order, err := api.PlaceOrderWithClientID("myOrder1", BUY, RequireDecimal("1"), RequireDecimal("6100"), BTC_USDT)
+	assert.Nil(t, err)
+	assert.Equal(t, 100, order.OrderID)
+	assert.Equal(t, "myOrder1", order.ClientOrderID)
+
+	order, err = api.GetOneOrder("myOrder1", BTC_USDT)
+	assert.Nil(t, err)
+	assert.Equal(t, 100, order.OrderID)
+	assert.Equal(t, "myOrder1", order.ClientOrderID)
+	ok, err := api.CancelOrder("myOrder1", BTC_USDT)
+	assert.True(t, ok)
+	assert.Nil(t, err)
+
+	//exchange order ids pass through
+	order, err = api.GetOneOrder("100", BTC_USDT)
+	assert.Nil(t, err)
+	assert.Equal(t, "", order.ClientOrderID)
+
+	//even when they look like client order ids
+	inner.orders = append(inner.orders, Order{OrderID2: "O6EAJC-YAC3C-XDEEXQ", Currency: BTC_USDT})
+	order, err = api.GetOneOrder("O6EAJC-YAC3C-XDEEXQ", BTC_USDT)
+	assert.Nil(t, err)
+	assert.Equal(t, "O6EAJC-YAC3C-XDEEXQ", order.OrderID2)
+	inner.orders = inner.orders[:1]
+
+	_, err = api.PlaceOrderWithClientID("myOrder1", BUY, RequireDecimal("1"), RequireDecimal("6100"), BTC_USDT)
+	assert.True(t, EX_ERR_INVALID_CLIENT_ORDER_ID.Is(err))
+	_, err = api.PlaceOrderWithClientID("1", BUY, RequireDecimal("1"), RequireDecimal("6100"), BTC_USDT)
+	assert.True(t, EX_ERR_INVALID_CLIENT_ORDER_ID.Is(err))
+	_, err = api.GetOneOrder("myOrder2", BTC_USDT)
+	assert.True(t, EX_ERR_NOT_FIND_ORDER.Is(err))
+	assert.Len(t, inner.orders, 1)
+}
+
+func TestClientOrderIDs_failedOrder(t *testing.T) {
+	inner := new(ordersAPI)
+	api := NewClientOrderIDs(inner)
+
+	//refused by the rate limit, the id is free again
+	inner.err = EX_ERR_API_LIMIT
+	_, err := api.PlaceOrderWithClientID("myOrder1", BUY, RequireDecimal("1"), RequireDecimal("6100"), BTC_USDT)
+	assert.Equal(t, EX_ERR_API_LIMIT, err)
+	_, err = api.PlaceOrderWithClientID("myOrder1", BUY, RequireDecimal("1"), RequireDecimal("6100"), BTC_USDT)
+	assert.Nil(t, err)
+
+	//the order may have been placed, its id can not be used again
+	inner.err = EX_ERR_MAINTENANCE
+	_, err = api.PlaceOrderWithClientID("myOrder2", BUY, RequireDecimal("1"), RequireDecimal("6100"), BTC_USDT)
+	assert.Equal(t, EX_ERR_MAINTENANCE, err)
+	_, err = api.PlaceOrderWithClientID("myOrder2", BUY, RequireDecimal("1"), RequireDecimal("6100"), BTC_USDT)
+	assert.True(t, EX_ERR_INVALID_CLIENT_ORDER_ID.Is(err))
+	_, err = api.GetOneOrder("myOrder2", BTC_USDT)
+	assert.True(t, EX_ERR_NOT_FIND_ORDER.Is(err))
+}
@@ -0,0 +1,374 @@
+package goex
+
+import (
+	"errors"
+	"math"
+	"math/big"
+	"strconv"
+	"strings"
+)
+
+// Decimal is an exact decimal number of any number of significant digits, used for prices and amounts
+// so they reach the exchanges and come back from them without float64 rounding. Only Div rounds, to
+// DivisionPrecision decimal places, everything else is exact.
+// It is always kept normalized, two equal values compare equal with == and can be used as map keys.
+// The zero value is 0.
+type Decimal struct {
+	mant string //the digits with an optional "-", no leading or trailing zeros, "" for 0
+	exp  int32  //value = mant * 10^exp
+}
+
+// DivisionPrecision is the number of decimal places Div rounds its quotient to
+var DivisionPrecision int32 = 18
+
+var (
+	bigTen = big.NewInt(10)
+
+	errDecimalSyntax = errors.New("invalid decimal syntax")
+)
+
+// NewDecimal returns mant * 10^exp
+func NewDecimal(mant int64, exp int32) Decimal {
+	return normalizeDecimal(strconv.FormatInt(mant, 10), exp)
+}
+
+func NewDecimalFromInt(i int64) Decimal {
+	return NewDecimal(i, 0)
+}
+
+// NewDecimalFromString parses s, as "12.3400", "-0.001" or "1.5e-8". Every digit of s is kept.
+func NewDecimalFromString(s string) (Decimal, error) {
+	str := strings.TrimSpace(s)
+	exp := int64(0)
+	if i := strings.IndexAny(str, "eE"); i >= 0 {
+		e, err := strconv.ParseInt(str[i+1:], 10, 32)
+		if err != nil {
+			return Decimal{}, errors.New(errDecimalSyntax.Error() + ": " + s)
+		}
+		exp, str = e, str[:i]
+	}
+
+	digits := str
+	if i := strings.IndexByte(str, '.'); i >= 0 {
+		digits = str[:i] + str[i+1:]
+		exp -= int64(len(str) - i - 1)
+	}
+	if strings.HasPrefix(digits, "+") {
+		digits = digits[1:]
+	}
+	unsigned := strings.TrimPrefix(digits, "-")
+	if unsigned == "" || strings.Trim(unsigned, "0123456789") != "" {
+		return Decimal{}, errors.New(errDecimalSyntax.Error() + ": " + s)
+	}
+	if exp+int64(len(digits)) > math.MaxInt32 || exp < math.MinInt32 {
+		return Decimal{}, errors.New(errDecimalSyntax.Error() + ": " + s)
+	}
+	return normalizeDecimal(digits, int32(exp)), nil
+}
+
+// NewDecimalFromFloat uses the shortest decimal that reads back as f, which is the number
+// as the exchange wrote it in its json whenever that had no more than 15 significant digits.
+// NaN and the infinities, which json can't hold, are 0.
+func NewDecimalFromFloat(f float64) Decimal {
+	d, _ := NewDecimalFromString(strconv.FormatFloat(f, 'g', -1, 64))
+	return d
+}
+
+// RequireDecimal is NewDecimalFromString for constants, it panics on a malformed s
+func RequireDecimal(s string) Decimal {
+	d, err := NewDecimalFromString(s)
+	if err != nil {
+		panic(err)
+	}
+	return d
+}
+
+// normalizeDecimal is digits * 10^exp with the zeros around digits dropped, digits is an integer
+// with an optional "-"
+func normalizeDecimal(digits string, exp int32) Decimal {
+	sign := ""
+	if strings.HasPrefix(digits, "-") {
+		sign, digits = "-", digits[1:]
+	}
+	digits = strings.TrimLeft(digits, "0")
+	if digits == "" {
+		return Decimal{}
+	}
+	trimmed := strings.TrimRight(digits, "0")
+	return Decimal{mant: sign + trimmed, exp: exp + int32(len(digits)-len(trimmed))}
+}
+
+func decimalFromBig(mant *big.Int, exp int32) Decimal {
+	return normalizeDecimal(mant.String(), exp)
+}
+
+func pow10(n int32) *big.Int {
+	return new(big.Int).Exp(bigTen, big.NewInt(int64(n)), nil)
+}
+
+// roundQuo is x / y rounded half away from zero, y > 0
+func roundQuo(x, y *big.Int) *big.Int {
+	q, r := new(big.Int).QuoRem(x, y, new(big.Int))
+	r.Abs(r).Lsh(r, 1)
+	if r.Cmp(y) >= 0 {
+		if x.Sign() < 0 {
+			q.Sub(q, big.NewInt(1))
+		} else {
+			q.Add(q, big.NewInt(1))
+		}
+	}
+	return q
+}
+
+// bigMant is the mantissa of d as a big.Int
+func (d Decimal) bigMant() *big.Int {
+	if len(d.mant) <= 18 {
+		m, _ := strconv.ParseInt(d.mant, 10, 64)
+		return big.NewInt(m)
+	}
+	m, _ := new(big.Int).SetString(d.mant, 10)
+	return m
+}
+
+// scaled is the mantissa of d at the smaller exponent exp
+func (d Decimal) scaled(exp int32) *big.Int {
+	m := d.bigMant()
+	if d.exp > exp {
+		m.Mul(m, pow10(d.exp-exp))
+	}
+	return m
+}
+
+func minExp(d, d2 Decimal) int32 {
+	if d.mant == "" {
+		return d2.exp
+	}
+	if d2.mant == "" || d.exp < d2.exp {
+		return d.exp
+	}
+	return d2.exp
+}
+
+func (d Decimal) Add(d2 Decimal) Decimal {
+	if d.mant == "" {
+		return d2
+	}
+	if d2.mant == "" {
+		return d
+	}
+	exp := minExp(d, d2)
+	return decimalFromBig(new(big.Int).Add(d.scaled(exp), d2.scaled(exp)), exp)
+}
+
+func (d Decimal) Sub(d2 Decimal) Decimal {
+	return d.Add(d2.Neg())
+}
+
+func (d Decimal) Mul(d2 Decimal) Decimal {
+	if d.mant == "" || d2.mant == "" {
+		return Decimal{}
+	}
+	return decimalFromBig(new(big.Int).Mul(d.bigMant(), d2.bigMant()), d.exp+d2.exp)
+}
+
+// Div is d / d2 rounded to DivisionPrecision decimal places, it panics when d2 is zero
+func (d Decimal) Div(d2 Decimal) Decimal {
+	return d.DivRound(d2, DivisionPrecision)
+}
+
+// DivRound is d / d2 rounded half away from zero to places decimal places
+func (d Decimal) DivRound(d2 Decimal, places int32) Decimal {
+	if d2.mant == "" {
+		panic("decimal division by zero")
+	}
+	//d / d2 * 10^places = d.mant * 10^(d.exp - d2.exp + places) / d2.mant
+	num, den := d.bigMant(), d2.bigMant()
+	if shift := d.exp - d2.exp + places; shift >= 0 {
+		num.Mul(num, pow10(shift))
+	} else {
+		den.Mul(den, pow10(-shift))
+	}
+	if den.Sign() < 0 {
+		num.Neg(num)
+		den.Neg(den)
+	}
+	return decimalFromBig(roundQuo(num, den), -places)
+}
+
+// Cmp returns -1, 0 or +1 as d is less than, equal to or greater than d2
+func (d Decimal) Cmp(d2 Decimal) int {
+	if s, s2 := d.Sign(), d2.Sign(); s != s2 || s == 0 {
+		switch {
+		case s < s2:
+			return -1
+		case s > s2:
+			return 1
+		}
+		return 0
+	}
+	if d == d2 {
+		return 0
+	}
+	exp := minExp(d, d2)
+	return d.scaled(exp).Cmp(d2.scaled(exp))
+}
+
+func (d Decimal) Equal(d2 Decimal) bool {
+	return d == d2
+}
+
+func (d Decimal) LessThan(d2 Decimal) bool {
+	return d.Cmp(d2) < 0
+}
+
+func (d Decimal) GreaterThan(d2 Decimal) bool {
+	return d.Cmp(d2) > 0
+}
+
+func (d Decimal) Sign() int {
+	switch {
+	case d.mant == "":
+		return 0
+	case d.mant[0] == '-':
+		return -1
+	}
+	return 1
+}
+
+func (d Decimal) IsZero() bool {
+	return d.mant == ""
+}
+
+func (d Decimal) Neg() Decimal {
+	switch d.Sign() {
+	case 1:
+		return Decimal{mant: "-" + d.mant, exp: d.exp}
+	case -1:
+		return Decimal{mant: d.mant[1:], exp: d.exp}
+	}
+	return d
+}
+
+func (d Decimal) Abs() Decimal {
+	if d.Sign() < 0 {
+		return d.Neg()
+	}
+	return d
+}
+
+// Round rounds half away from zero to places decimal places, a negative places rounds to tens, hundreds...
+func (d Decimal) Round(places int32) Decimal {
+	if d.exp >= -places {
+		return d
+	}
+	return decimalFromBig(roundQuo(d.bigMant(), pow10(-places-d.exp)), -places)
+}
+
+// Truncate drops the digits after places decimal places, rounding towards zero
+func (d Decimal) Truncate(places int32) Decimal {
+	if d.exp >= -places {
+		return d
+	}
+	return decimalFromBig(new(big.Int).Quo(d.bigMant(), pow10(-places-d.exp)), -places)
+}
+
+// FloorTo is the largest multiple of step not above d, step must be positive
+func (d Decimal) FloorTo(step Decimal) Decimal {
+	return d.roundTo(step, false)
+}
+
+// CeilTo is the smallest multiple of step not below d, step must be positive
+func (d Decimal) CeilTo(step Decimal) Decimal {
+	return d.roundTo(step, true)
+}
+
+func (d Decimal) roundTo(step Decimal, up bool) Decimal {
+	if step.Sign() <= 0 {
+		panic("decimal step must be positive")
+	}
+	if d.mant == "" {
+		return d
+	}
+	exp := minExp(d, step)
+	s := step.scaled(exp)
+	q, m := new(big.Int).DivMod(d.scaled(exp), s, new(big.Int)) //floor division, 0 <= m < s
+	if up && m.Sign() != 0 {
+		q.Add(q, big.NewInt(1))
+	}
+	return decimalFromBig(q.Mul(q, s), exp)
+}
+
+// Digits is the number of significant digits, 0 for zero
+func (d Decimal) Digits() int32 {
+	return int32(len(strings.TrimPrefix(d.mant, "-")))
+}
+
+// Places is the number of digits after the decimal point, 0 for whole numbers
+func (d Decimal) Places() int32 {
+	if d.exp >= 0 {
+		return 0
+	}
+	return -d.exp
+}
+
+func (d Decimal) Float64() float64 {
+	f, _ := strconv.ParseFloat(d.String(), 64)
+	return f
+}
+
+// String is the shortest plain notation of d, without exponent: "0.00000001", "1200", "-3.5"
+func (d Decimal) String() string {
+	if d.mant == "" {
+		return "0"
+	}
+
+	digits, sign := d.mant, ""
+	if d.mant[0] == '-' {
+		sign, digits = "-", digits[1:]
+	}
+	if d.exp >= 0 {
+		return sign + digits + strings.Repeat("0", int(d.exp))
+	}
+	point := len(digits) + int(d.exp)
+	if point <= 0 {
+		return sign + "0." + strings.Repeat("0", -point) + digits
+	}
+	return sign + digits[:point] + "." + digits[point:]
+}
+
+// StringFixed rounds d to places decimal places and always prints that many: 1.5 with 3 places is "1.500"
+func (d Decimal) StringFixed(places int32) string {
+	str := d.Round(places).String()
+	if places <= 0 {
+		return str
+	}
+	i := strings.IndexByte(str, '.')
+	if i < 0 {
+		return str + "." + strings.Repeat("0", int(places))
+	}
+	return str + strings.Repeat("0", int(places)-(len(str)-i-1))
+}
+
+// MarshalJSON writes d as a json string, the way most exchanges send prices
+func (d Decimal) MarshalJSON() ([]byte, error) {
+	return []byte(`"` + d.String() + `"`), nil
+}
+
+// UnmarshalJSON accepts both json strings and json numbers
+func (d *Decimal) UnmarshalJSON(data []byte) error {
+	str := string(data)
+	if str == "null" {
+		return nil
+	}
+	str = strings.Trim(str, `"`)
+	if str == "" {
+		*d = Decimal{}
+		return nil
+	}
+	v, err := NewDecimalFromString(str)
+	if err != nil {
+		return err
+	}
+	*d = v
+	return nil
+}
@@ -0,0 +1,174 @@
+package goex
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestNewDecimalFromString(t *testing.T) {
+	for in, out := range map[string]string{
+		"0":                      "0",
+		"-0.000":                 "0",
+		"12.3400":                "12.34",
+		"0.00000001":             "0.00000001",
+		"-1200":                  "-1200",
+		"+7.5":                   "7.5",
+		"1.5e-8":                 "0.000000015",
+		"2E3":                    "2000",
+		"123456789.123456789":    "123456789.123456789",
+		"100000000000000000000":  "100000000000000000000",
+		"1234567890.1234567891":  "1234567890.1234567891",
+		"0.99999999999999999999": "0.99999999999999999999",
+	} {
+		d, err := NewDecimalFromString(in)
+		assert.Nil(t, err, in)
+		assert.Equal(t, out, d.String(), in)
+	}
+
+	for _, in := range []string{"", "-", "abc", "1.2.3", "1e", "--1", "1e2147483647"} {
+		_, err := NewDecimalFromString(in)
+		assert.NotNil(t, err, in)
+	}
+}
+
+func TestDecimal_Normalized(t *testing.T) {
+	assert.True(t, RequireDecimal("1.10") == RequireDecimal("1.1"))
+	assert.True(t, NewDecimal(1100, -3) == NewDecimalFromFloat(1.1))
+	assert.Equal(t, Decimal{}, RequireDecimal("0.000"))
+
+	m := map[Decimal]int{RequireDecimal("6100.50"): 1}
+	assert.Equal(t, 1, m[RequireDecimal("6100.5")])
+}
+
+func TestDecimal_Arithmetic(t *testing.T) {
+	a, b := RequireDecimal("0.1"), RequireDecimal("0.2")
+	assert.Equal(t, "0.3", a.Add(b).String())
+	assert.Equal(t, "-0.1", a.Sub(b).String())
+	assert.Equal(t, "0.02", a.Mul(b).String())
+	assert.Equal(t, "0.5", a.Div(b).String())
+	assert.Equal(t, "0.333333333333333333", RequireDecimal("1").Div(RequireDecimal("3")).String())
+	assert.Equal(t, "0.67", RequireDecimal("2").DivRound(RequireDecimal("3"), 2).String())
+	assert.Equal(t, "-0.67", RequireDecimal("2").DivRound(RequireDecimal("-3"), 2).String())
+
+	//one satoshi survives against a large balance
+	sum := RequireDecimal("21000000").Add(RequireDecimal("0.00000001"))
+	assert.Equal(t, "21000000.00000001", sum.String())
+	assert.Equal(t, "0.00000001", sum.Sub(RequireDecimal("21000000")).String())
+
+	assert.Panics(t, func() { a.Div(Decimal{}) })
+
+	//past 18 significant digits nothing is rounded away
+	wide := RequireDecimal("123456789012345678.9")
+	assert.Equal(t, "123456789012345679", wide.Add(RequireDecimal("0.1")).String())
+	assert.Equal(t, "0.9", wide.Sub(RequireDecimal("123456789012345678")).String())
+	assert.Equal(t, "15241578753238836762536198888736473.1", wide.Mul(wide.Round(0)).String())
+	assert.Equal(t, "0.000000000000000000001", RequireDecimal("1e20").Add(RequireDecimal("1e-21")).Sub(RequireDecimal("1e20")).String())
+}
+
+func TestDecimal_Cmp(t *testing.T) {
+	assert.Equal(t, -1, RequireDecimal("0.99").Cmp(RequireDecimal("1")))
+	assert.Equal(t, 1, RequireDecimal("-0.99").Cmp(RequireDecimal("-1")))
+	assert.Equal(t, 0, RequireDecimal("1.0").Cmp(RequireDecimal("1")))
+	assert.Equal(t, 1, RequireDecimal("0.001").Cmp(Decimal{}))
+	assert.Equal(t, -1, RequireDecimal("-100").Cmp(RequireDecimal("0.001")))
+	assert.True(t, RequireDecimal("2").GreaterThan(RequireDecimal("1.99999999")))
+	assert.True(t, RequireDecimal("-2").LessThan(Decimal{}))
+	assert.Equal(t, 1, RequireDecimal("0.10000000000000000001").Cmp(RequireDecimal("0.1")))
+	assert.Equal(t, -1, RequireDecimal("-0.10000000000000000001").Cmp(RequireDecimal("-0.1")))
+}
+
+func TestDecimal_RoundTruncate(t *testing.T) {
+	d := RequireDecimal("1.23456")
+	assert.Equal(t, "1.2346", d.Round(4).String())
+	assert.Equal(t, "1.2345", d.Truncate(4).String())
+	assert.Equal(t, "-1.2346", d.Neg().Round(4).String())
+	assert.Equal(t, "-1.2345", d.Neg().Truncate(4).String())
+	assert.Equal(t, "1200", RequireDecimal("1249").Round(-2).String())
+	assert.Equal(t, "1300", RequireDecimal("1250").Round(-2).String())
+	assert.Equal(t, "1.23456", d.Round(8).String())
+	assert.Equal(t, "1.23456000", d.StringFixed(8))
+	assert.Equal(t, "1.23", d.StringFixed(2))
+	assert.Equal(t, "1", d.StringFixed(0))
+	assert.Equal(t, int32(5), d.Places())
+	assert.Equal(t, int32(0), RequireDecimal("1200").Places())
+}
+
+func TestDecimal_FloorCeilTo(t *testing.T) {
+	step := RequireDecimal("0.05")
+	assert.Equal(t, "1.2", RequireDecimal("1.23").FloorTo(step).String())
+	assert.Equal(t, "1.25", RequireDecimal("1.23").CeilTo(step).String())
+	assert.Equal(t, "-1.25", RequireDecimal("-1.23").FloorTo(step).String())
+	assert.Equal(t, "-1.2", RequireDecimal("-1.23").CeilTo(step).String())
+	assert.Equal(t, "1.25", RequireDecimal("1.25").CeilTo(step).String())
+	assert.Panics(t, func() { RequireDecimal("1").FloorTo(Decimal{}) })
+	assert.Equal(t, int32(5), RequireDecimal("6100.1").Digits())
+	assert.Equal(t, int32(2), RequireDecimal("-0.0012").Digits())
+	assert.Equal(t, int32(0), Decimal{}.Digits())
+}
+
+func TestDecimal_JSON(t *testing.T) {
+	var v struct {
+		Price  Decimal `json:"price"`
+		Amount Decimal `json:"amount"`
+		Fee    Decimal `json:"fee"`
+	}
+	assert.Nil(t, json.Unmarshal([]byte(`{"price":"6100.10","amount":0.1,"fee":null}`), &v))
+	assert.Equal(t, "6100.1", v.Price.String())
+	assert.Equal(t, "0.1", v.Amount.String())
+	assert.True(t, v.Fee.IsZero())
+
+	data, err := json.Marshal(v)
+	assert.Nil(t, err)
+	assert.Equal(t, `{"price":"6100.1","amount":"0.1","fee":"0"}`, string(data))
+
+	assert.NotNil(t, json.Unmarshal([]byte(`{"price":"abc"}`), &v))
+}
+
+func TestToDecimal(t *testing.T) {
+	assert.Equal(t, RequireDecimal("0.1"), ToDecimal(0.1))
+	assert.Equal(t, RequireDecimal("6100.5"), ToDecimal("6100.50"))
+	assert.Equal(t, RequireDecimal("5"), ToDecimal(5))
+	assert.Equal(t, Decimal{}, ToDecimal(nil))
+	assert.Equal(t, Decimal{}, ToDecimal(""))
+	assert.Equal(t, 0.1, ToDecimal("0.1").Float64())
+	assert.Equal(t, RequireDecimal("1234567890.1234567891"), ToDecimal("1234567890.1234567891"))
+	assert.Equal(t, RequireDecimal("0.5"), ToDecimal(json.Number("0.50")))
+
+	//odd fields of an exchange's json read as 0 instead of bringing the process down
+	assert.Equal(t, Decimal{}, ToDecimal("--"))
+	assert.Equal(t, Decimal{}, ToDecimal(true))
+}
+
+func TestToDecimalE(t *testing.T) {
+	d, err := ToDecimalE("6100.50")
+	assert.Nil(t, err)
+	assert.Equal(t, RequireDecimal("6100.5"), d)
+	d, err = ToDecimalE(nil)
+	assert.Nil(t, err)
+	assert.True(t, d.IsZero())
+
+	for _, v := range []interface{}{"--", "N/A", true, []interface{}{"1"}, json.Number("1,5")} {
+		_, err := ToDecimalE(v)
+		assert.NotNil(t, err, v)
+	}
+}
+
+func TestDecimalParser(t *testing.T) {
+	var dp DecimalParser
+	assert.Equal(t, RequireDecimal("6100.5"), dp.Price("6100.5"))
+	assert.Equal(t, RequireDecimal("0.1"), dp.Decimal(0.1))
+	assert.Equal(t, Decimal{}, dp.Decimal(nil))
+	assert.Nil(t, dp.Err)
+
+	dp.Decimal("N/A")
+	dp.Decimal(true)
+	assert.EqualError(t, dp.Err, "invalid decimal syntax: N/A")
+
+	for _, v := range []interface{}{"0", nil, "", "-1"} {
+		var dp DecimalParser
+		dp.Price(v)
+		assert.NotNil(t, dp.Err, v)
+	}
+}
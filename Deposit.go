@@ -0,0 +1,53 @@
+package goex
+
+type DepositStatus int
+
+const (
+	DEPOSIT_PENDING   DepositStatus = iota //seen on the network, waiting for confirmations
+	DEPOSIT_COMPLETED                      //credited to the account
+	DEPOSIT_FAILED
+)
+
+var depositStatusSymbol = [...]string{"PENDING", "COMPLETED", "FAILED"}
+
+func (ds DepositStatus) String() string {
+	if ds < 0 || int(ds) >= len(depositStatusSymbol) {
+		return "UNKNOWN"
+	}
+	return depositStatusSymbol[ds]
+}
+
+type Deposit struct {
+	ID            string //the exchange's id of the deposit, the TxID where it has none
+	Currency      Currency
+	Amount        Decimal
+	Address       string
+	Tag           string
+	TxID          string
+	Confirmations int //0 where the exchange does not tell
+	Status        DepositStatus
+	Time          int64 //unix seconds the deposit was seen
+}
+
+//DepositAPI is implemented by adapters that can tell where to send funds and what arrived.
+//GetDeposits pages like GetOrderHistorys, newest first, currentPage counting from 1.
+type DepositAPI interface {
+	GetDepositAddress(currency Currency) (*CryptoAddress, error)
+	GetDeposits(currency Currency, currentPage, pageSize int) ([]Deposit, error)
+}
+
+//DepositsPage is the page GetDeposits returns out of the whole history, for exchanges without paging
+func DepositsPage(deposits []Deposit, currentPage, pageSize int) []Deposit {
+	if currentPage < 1 {
+		currentPage = 1
+	}
+	start := (currentPage - 1) * pageSize
+	if pageSize <= 0 || start >= len(deposits) {
+		return []Deposit{}
+	}
+	end := start + pageSize
+	if end > len(deposits) {
+		end = len(deposits)
+	}
+	return deposits[start:end]
+}
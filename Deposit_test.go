@@ -0,0 +1,16 @@
+package goex
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestDepositsPage(t *testing.T) {
+	deposits := []Deposit{{ID: "5"}, {ID: "4"}, {ID: "3"}, {ID: "2"}, {ID: "1"}}
+	assert.Equal(t, []Deposit{{ID: "5"}, {ID: "4"}}, DepositsPage(deposits, 1, 2))
+	assert.Equal(t, []Deposit{{ID: "1"}}, DepositsPage(deposits, 3, 2))
+	assert.Equal(t, []Deposit{}, DepositsPage(deposits, 4, 2))
+	assert.Equal(t, []Deposit{{ID: "5"}}, DepositsPage(deposits, 0, 1))
+	assert.Equal(t, []Deposit{}, DepositsPage(nil, 1, 10))
+}
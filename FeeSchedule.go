@@ -0,0 +1,243 @@
+package goex
+
+import (
+	"io"
+	"io/ioutil"
+	"os"
+	"strings"
+	"sync"
+	"time"
+
+	"gopkg.in/yaml.v2"
+)
+
+//FeeSchedule tells what an exchange charges for withdrawing a currency
+type FeeSchedule interface {
+	WithdrawFee(exchange string, currency Currency) (Decimal, error)
+}
+
+//WithdrawFees is the schedule the adapters' Withdraw take the fee from when the request has none.
+//Replace it to add overrides or live lookups, as FeeSchedules{overrides, live, DefaultFeeSchedule}.
+var WithdrawFees FeeSchedule = DefaultFeeSchedule
+
+//WithdrawFee is req.Fee, or the fee WithdrawFees knows for the exchange when it is zero
+func (req WithdrawRequest) WithdrawFee(exchange string) (Decimal, error) {
+	if !req.Fee.IsZero() {
+		return req.Fee, nil
+	}
+	return WithdrawFees.WithdrawFee(exchange, req.Currency)
+}
+
+//StaticFeeSchedule is a fixed table of fees by exchange name and currency symbol
+type StaticFeeSchedule map[string]map[string]Decimal
+
+func (s StaticFeeSchedule) WithdrawFee(exchange string, currency Currency) (Decimal, error) {
+	fee, ok := s[strings.ToLower(exchange)][strings.ToUpper(currency.Symbol)]
+	if !ok {
+		errCode := EX_ERR_UNKNOWN_FEE
+		errCode.OriginErrMsg = exchange + " " + currency.Symbol
+		return Decimal{}, errCode
+	}
+	return fee, nil
+}
+
+//Set adds or overrides the fee of the currency on the exchange
+func (s StaticFeeSchedule) Set(exchange string, currency Currency, fee Decimal) {
+	exchange = strings.ToLower(exchange)
+	if s[exchange] == nil {
+		s[exchange] = make(map[string]Decimal)
+	}
+	s[exchange][strings.ToUpper(currency.Symbol)] = fee
+}
+
+//LoadFeeSchedule reads a table of fees by exchange and currency:
+//
+//	bitfinex.com:
+//	  BTC: 0.0008
+//	  ETH: 0.0027
+func LoadFeeSchedule(r io.Reader) (StaticFeeSchedule, error) {
+	data, err := ioutil.ReadAll(r)
+	if err != nil {
+		return nil, err
+	}
+	var table map[string]map[string]string
+	if err := yaml.Unmarshal(data, &table); err != nil {
+		return nil, err
+	}
+	s := make(StaticFeeSchedule)
+	for exchange, fees := range table {
+		for symbol, fee := range fees {
+			d, err := NewDecimalFromString(fee)
+			if err != nil {
+				return nil, err
+			}
+			s.Set(exchange, NewCurrency(symbol, ""), d)
+		}
+	}
+	return s, nil
+}
+
+func LoadFeeScheduleFile(path string) (StaticFeeSchedule, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+	return LoadFeeSchedule(f)
+}
+
+//FeeSchedules asks each schedule in turn, the first knowing the fee answers
+type FeeSchedules []FeeSchedule
+
+func (s FeeSchedules) WithdrawFee(exchange string, currency Currency) (Decimal, error) {
+	err := error(EX_ERR_UNKNOWN_FEE)
+	for _, schedule := range s {
+		var fee Decimal
+		fee, err = schedule.WithdrawFee(exchange, currency)
+		if err == nil {
+			return fee, nil
+		}
+	}
+	return Decimal{}, err
+}
+
+//WithdrawFeeAPI is implemented by adapters reading the withdrawal fees from the exchange
+type WithdrawFeeAPI interface {
+	GetWithdrawFee(currency Currency) (Decimal, error)
+}
+
+//LiveFeeSchedule asks the exchanges added to it and remembers each fee for TTL
+type LiveFeeSchedule struct {
+	TTL time.Duration
+
+	l     sync.Mutex
+	apis  map[string]WithdrawFeeAPI
+	cache map[string]liveFee
+}
+
+type liveFee struct {
+	fee     Decimal
+	expires time.Time
+}
+
+func NewLiveFeeSchedule(ttl time.Duration) *LiveFeeSchedule {
+	return &LiveFeeSchedule{TTL: ttl, apis: make(map[string]WithdrawFeeAPI), cache: make(map[string]liveFee)}
+}
+
+func (s *LiveFeeSchedule) Add(exchange string, api WithdrawFeeAPI) {
+	s.l.Lock()
+	defer s.l.Unlock()
+	s.apis[strings.ToLower(exchange)] = api
+}
+
+func (s *LiveFeeSchedule) WithdrawFee(exchange string, currency Currency) (Decimal, error) {
+	exchange = strings.ToLower(exchange)
+	key := exchange + "|" + strings.ToUpper(currency.Symbol)
+	s.l.Lock()
+	api, ok := s.apis[exchange]
+	cached, fresh := s.cache[key]
+	s.l.Unlock()
+	if !ok {
+		errCode := EX_ERR_UNKNOWN_FEE
+		errCode.OriginErrMsg = exchange + " " + currency.Symbol
+		return Decimal{}, errCode
+	}
+	if fresh && time.Now().Before(cached.expires) {
+		return cached.fee, nil
+	}
+
+	fee, err := api.GetWithdrawFee(currency)
+	if err != nil {
+		return Decimal{}, err
+	}
+	s.l.Lock()
+	s.cache[key] = liveFee{fee, time.Now().Add(s.TTL)}
+	s.l.Unlock()
+	return fee, nil
+}
+
+//DefaultFeeSchedule holds the fees the exchanges published when it was last updated,
+//override the ones that changed or use a LiveFeeSchedule in front of it
+var DefaultFeeSchedule = mustLoadFeeSchedule(defaultWithdrawFees)
+
+func mustLoadFeeSchedule(table string) StaticFeeSchedule {
+	s, err := LoadFeeSchedule(strings.NewReader(table))
+	if err != nil {
+		panic(err)
+	}
+	return s
+}
+
+const defaultWithdrawFees = `
+binance.com:
+  BTC: 0.0005
+  BCH: 0.001
+  LTC: 0.001
+  ETH: 0.01
+  ETC: 0.01
+  XRP: 0.25
+  EOS: 0.1
+  NEO: 0
+  USDT: 5
+bitfinex.com:
+  BTC: 0.0008
+  BCH: 0.0001
+  LTC: 0.001
+  ETH: 0.0027
+  ETC: 0.01
+  ZEC: 0.001
+  XMR: 0.04
+  DASH: 0.01
+  XRP: 0.02
+  EOS: 0.1
+  NEO: 0
+  AVT: 0.5
+  QTUM: 0.01
+  EDO: 0.5
+  REP: 0.01
+chbtc.com:
+  BTC: 0.001
+  LTC: 0.005
+  ETH: 0.01
+  ETC: 0.01
+huobi.pro:
+  BTC: 0.001
+  BCH: 0.0001
+  LTC: 0.001
+  ETH: 0.01
+  ETC: 0.01
+  XRP: 0.1
+  USDT: 20
+kraken.com:
+  BTC: 0.0005
+  BCH: 0.0001
+  LTC: 0.001
+  ETH: 0.005
+  ETC: 0.005
+  ZEC: 0.0001
+  XMR: 0.05
+  DASH: 0.005
+  XRP: 0.02
+  EOS: 0.05
+  REP: 0.01
+okcoin.cn:
+  BTC: 0.0005
+  LTC: 0.001
+  ETH: 0.01
+  ETC: 0.01
+okcoin.com:
+  BTC: 0.0005
+  LTC: 0.001
+  ETH: 0.01
+  ETC: 0.01
+poloniex.com:
+  BTC: 0.0005
+  BCH: 0.0001
+  LTC: 0.001
+  ETH: 0.005
+  ETC: 0.01
+  ZEC: 0.001
+  XMR: 0.015
+  DASH: 0.01
+  XRP: 0.15
+`
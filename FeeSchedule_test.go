@@ -0,0 +1,86 @@
+package goex
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+//feeAPI counts the lookups, err fails them
+type feeAPI struct {
+	fee   Decimal
+	err   error
+	calls int
+}
+
+func (f *feeAPI) GetWithdrawFee(currency Currency) (Decimal, error) {
+	f.calls++
+	return f.fee, f.err
+}
+
+func TestLoadFeeSchedule(t *testing.T) {
+	s, err := LoadFeeSchedule(strings.NewReader("Bitfinex.com:\n  btc: 0.0008\n  ETH: 0.0027\n"))
+	assert.Nil(t, err)
+	fee, err := s.WithdrawFee("bitfinex.com", BTC)
+	assert.Nil(t, err)
+	assert.True(t, fee.Equal(RequireDecimal("0.0008")))
+	_, err = s.WithdrawFee("bitfinex.com", LTC)
+	assert.True(t, EX_ERR_UNKNOWN_FEE.Is(err))
+
+	_, err = LoadFeeSchedule(strings.NewReader("bitfinex.com:\n  BTC: abc\n"))
+	assert.NotNil(t, err)
+}
+
+func TestDefaultFeeSchedule(t *testing.T) {
+	fee, err := DefaultFeeSchedule.WithdrawFee("poloniex.com", BTC)
+	assert.Nil(t, err)
+	assert.True(t, fee.Equal(RequireDecimal("0.0005")))
+
+	req := WithdrawRequest{Currency: BTC, Fee: RequireDecimal("0.001")}
+	fee, err = req.WithdrawFee("poloniex.com")
+	assert.Nil(t, err)
+	assert.True(t, fee.Equal(RequireDecimal("0.001")))
+	req.Fee = Decimal{}
+	fee, err = req.WithdrawFee("poloniex.com")
+	assert.Nil(t, err)
+	assert.True(t, fee.Equal(RequireDecimal("0.0005")))
+}
+
+func TestFeeSchedules(t *testing.T) {
+	overrides := make(StaticFeeSchedule)
+	overrides.Set("Kraken.com", BTC, RequireDecimal("0.001"))
+	schedule := FeeSchedules{overrides, DefaultFeeSchedule}
+
+	fee, err := schedule.WithdrawFee("kraken.com", BTC)
+	assert.Nil(t, err)
+	assert.True(t, fee.Equal(RequireDecimal("0.001")))
+	fee, err = schedule.WithdrawFee("kraken.com", LTC)
+	assert.Nil(t, err)
+	assert.True(t, fee.Equal(RequireDecimal("0.001")))
+	_, err = schedule.WithdrawFee("unknown.com", BTC)
+	assert.True(t, EX_ERR_UNKNOWN_FEE.Is(err))
+}
+
+func TestLiveFeeSchedule(t *testing.T) {
+	api := &feeAPI{fee: RequireDecimal("0.0004")}
+	live := NewLiveFeeSchedule(time.Minute)
+	live.Add("binance.com", api)
+
+	for i := 0; i < 2; i++ {
+		fee, err := live.WithdrawFee("binance.com", BTC)
+		assert.Nil(t, err)
+		assert.True(t, fee.Equal(RequireDecimal("0.0004")))
+	}
+	assert.Equal(t, 1, api.calls)
+
+	_, err := live.WithdrawFee("kraken.com", BTC)
+	assert.True(t, EX_ERR_UNKNOWN_FEE.Is(err))
+
+	//a failed lookup falls back to the next schedule
+	api.err = EX_ERR_API_LIMIT
+	fee, err := FeeSchedules{live, DefaultFeeSchedule}.WithdrawFee("binance.com", ETH)
+	assert.Nil(t, err)
+	assert.True(t, fee.Equal(RequireDecimal("0.01")))
+}
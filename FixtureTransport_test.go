@@ -0,0 +1,76 @@
+package goex
+
+import (
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestFixtureTransport(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		r.ParseForm()
+		switch r.URL.Path {
+		case "/ticker":
+			w.Write([]byte(`{"last":"` + r.Form.Get("symbol") + `"}`))
+		case "/balance":
+			w.Write([]byte(`{"nonce":` + r.Form.Get("nonce") + `}`))
+		default:
+			w.WriteHeader(404)
+			w.Write([]byte("not found"))
+		}
+	}))
+	defer srv.Close()
+
+	dir, err := ioutil.TempDir("", "fixtures")
+	assert.Nil(t, err)
+	defer os.RemoveAll(dir)
+	path := filepath.Join(dir, "testdata", "fixtures.json")
+
+	recorder := NewFixtureTransport(path)
+	recorder.Mode = FIXTURE_RECORD
+	client := recorder.Client()
+	get := func(url string) string {
+		data, err := NewHttpRequest(client, "GET", url, "", nil)
+		if err != nil {
+			return err.Error()
+		}
+		return string(data)
+	}
+	post := func(url, form string) string {
+		data, err := NewHttpRequest(client, "POST", url, form, map[string]string{"Content-Type": "application/x-www-form-urlencoded"})
+		if err != nil {
+			return err.Error()
+		}
+		return string(data)
+	}
+	assert.Equal(t, `{"last":"btc"}`, get(srv.URL+"/ticker?symbol=btc"))
+	assert.Equal(t, `{"last":"eth"}`, get(srv.URL+"/ticker?symbol=eth"))
+	assert.Equal(t, `{"nonce":1}`, post(srv.URL+"/balance", "apikey=k&nonce=1&sign=s"))
+	assert.Equal(t, `{"nonce":2}`, post(srv.URL+"/balance", "apikey=k&nonce=2&sign=s"))
+	assert.Contains(t, get(srv.URL+"/missing?api_key=k"), "not found")
+	assert.Nil(t, recorder.Save())
+
+	data, err := ioutil.ReadFile(path)
+	assert.Nil(t, err)
+	assert.NotContains(t, string(data), "apikey=k")
+	assert.NotContains(t, string(data), "api_key=k")
+	assert.Contains(t, string(data), "apikey=SCRUBBED&nonce=1&sign=SCRUBBED")
+
+	client = NewFixtureTransport(path).Client()
+	//the same parameters whatever their order, requests that differ only by their nonce in the order recorded
+	assert.Equal(t, `{"last":"eth"}`, get(srv.URL+"/ticker?symbol=eth"))
+	assert.Equal(t, `{"nonce":1}`, post(srv.URL+"/balance", "nonce=7&sign=s2&apikey=k2"))
+	assert.Equal(t, `{"nonce":2}`, post(srv.URL+"/balance", "apikey=k2&nonce=8&sign=s2"))
+	assert.True(t, strings.HasPrefix(get(srv.URL+"/ticker?symbol=xrp"), "Get "))
+	assert.Equal(t, `{"last":"btc"}`, get(srv.URL+"/ticker?symbol=btc"))
+	assert.True(t, strings.HasPrefix(get(srv.URL+"/ticker?symbol=btc"), "Get "))
+	assert.True(t, strings.HasPrefix(post(srv.URL+"/balance", "apikey=k2&nonce=9&sign=s2&amount=1"), "Post "))
+	assert.True(t, strings.HasPrefix(get(srv.URL+"/missing"), "Get "))
+	assert.Contains(t, get(srv.URL+"/missing?api_key=k2"), "HttpStatusCode:404")
+}
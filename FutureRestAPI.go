@@ -1,11 +1,18 @@
 package goex
 
+import "context"
+
 type FutureRestAPI interface {
 	/**
 	 *获取交易所名字
 	 */
 	GetExchangeName() string
 
+	/**
+	 *交易所支持的功能
+	 */
+	Capabilities() Capabilities
+
 	/**
 	 *获取交割预估价
 	 */
@@ -101,3 +108,24 @@ type FutureRestAPI interface {
 	 */
 	GetKlineRecords(contract_type string, currency CurrencyPair, period string, size, since int) ([]FutureKline, error)
 }
+
+// FutureRestAPIWithContext mirrors FutureRestAPI, with a context.Context on
+// every call that goes over the network.
+type FutureRestAPIWithContext interface {
+	GetExchangeName() string
+	GetFutureEstimatedPriceCtx(ctx context.Context, currencyPair CurrencyPair) (float64, error)
+	GetFutureTickerCtx(ctx context.Context, currencyPair CurrencyPair, contractType string) (*Ticker, error)
+	GetFutureDepthCtx(ctx context.Context, currencyPair CurrencyPair, contractType string, size int) (*Depth, error)
+	GetFutureIndexCtx(ctx context.Context, currencyPair CurrencyPair) (float64, error)
+	GetFutureUserinfoCtx(ctx context.Context) (*FutureAccount, error)
+	PlaceFutureOrderCtx(ctx context.Context, currencyPair CurrencyPair, contractType, price, amount string, openType, matchPrice, leverRate int) (string, error)
+	FutureCancelOrderCtx(ctx context.Context, currencyPair CurrencyPair, contractType, orderId string) (bool, error)
+	GetFuturePositionCtx(ctx context.Context, currencyPair CurrencyPair, contractType string) ([]FuturePosition, error)
+	GetFutureOrdersCtx(ctx context.Context, orderIds []string, currencyPair CurrencyPair, contractType string) ([]FutureOrder, error)
+	GetUnfinishFutureOrdersCtx(ctx context.Context, currencyPair CurrencyPair, contractType string) ([]FutureOrder, error)
+	GetFeeCtx(ctx context.Context) (float64, error)
+	GetExchangeRateCtx(ctx context.Context) (float64, error)
+	GetContractValueCtx(ctx context.Context, currencyPair CurrencyPair) (float64, error)
+	GetDeliveryTime() (int, int, int, int)
+	GetKlineRecordsCtx(ctx context.Context, contract_type string, currency CurrencyPair, period string, size, since int) ([]FutureKline, error)
+}
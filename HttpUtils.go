@@ -2,8 +2,8 @@ package goex
 
 //http request 工具函数
 import (
+	"context"
 	"encoding/json"
-	"errors"
 	"fmt"
 	"io/ioutil"
 	"log"
@@ -13,7 +13,16 @@ import (
 )
 
 func NewHttpRequest(client *http.Client, reqType string, reqUrl string, postData string, requstHeaders map[string]string) ([]byte, error) {
-	req, _ := http.NewRequest(reqType, reqUrl, strings.NewReader(postData))
+	return NewHttpRequestWithContext(context.Background(), client, reqType, reqUrl, postData, requstHeaders)
+}
+
+// NewHttpRequestWithContext is NewHttpRequest, but the request is abandoned as soon as ctx is done.
+func NewHttpRequestWithContext(ctx context.Context, client *http.Client, reqType string, reqUrl string, postData string, requstHeaders map[string]string) ([]byte, error) {
+	req, err := http.NewRequest(reqType, reqUrl, strings.NewReader(postData))
+	if err != nil {
+		return nil, err
+	}
+	req = req.WithContext(ctx)
 
 	//req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
 	req.Header.Set("User-Agent", "Mozilla/5.0 (Windows NT 5.1) AppleWebKit/537.36 (KHTML, like Gecko) Chrome/31.0.1650.63 Safari/537.36")
@@ -37,7 +46,18 @@ func NewHttpRequest(client *http.Client, reqType string, reqUrl string, postData
 	}
 
 	if resp.StatusCode != 200 {
-		return nil, errors.New(fmt.Sprintf("HttpStatusCode:%d ,Desc:%s", resp.StatusCode, string(bodyData)))
+		var errCode ApiError
+		switch resp.StatusCode {
+		case 418, 429:
+			errCode = EX_ERR_API_LIMIT
+		case 502, 503:
+			errCode = EX_ERR_MAINTENANCE
+		default:
+			errCode = HTTP_ERR_CODE
+		}
+		errCode.ErrMsg = fmt.Sprintf("%s, HttpStatusCode:%d", errCode.ErrMsg, resp.StatusCode)
+		errCode.OriginErrMsg = string(bodyData)
+		return nil, errCode
 	}
 
 	//var bodyDataMap map[string]interface{};
@@ -128,3 +148,30 @@ func HttpDeleteForm(client *http.Client, reqUrl string, postData url.Values, hea
 	headers["Content-Type"] = "application/x-www-form-urlencoded"
 	return NewHttpRequest(client, "DELETE", reqUrl, postData.Encode(), headers)
 }
+
+// HttpClientWithContext returns a shallow copy of client whose requests all carry ctx,
+// so adapters built on the helpers above can be cancelled without changing their call sites.
+func HttpClientWithContext(ctx context.Context, client *http.Client) *http.Client {
+	if client == nil {
+		client = http.DefaultClient
+	}
+	transport := client.Transport
+	if transport == nil {
+		transport = http.DefaultTransport
+	}
+	c := *client
+	c.Transport = &contextTransport{ctx: ctx, transport: transport}
+	return &c
+}
+
+type contextTransport struct {
+	ctx       context.Context
+	transport http.RoundTripper
+}
+
+func (t *contextTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	if err := t.ctx.Err(); err != nil {
+		return nil, err
+	}
+	return t.transport.RoundTrip(req.WithContext(t.ctx))
+}
@@ -0,0 +1,75 @@
+package goex
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestHttpClientWithContext(t *testing.T) {
+	release := make(chan struct{})
+	defer close(release)
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		select {
+		case <-release:
+		case <-r.Context().Done():
+		}
+	}))
+	defer srv.Close()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+
+	start := time.Now()
+	_, err := HttpGet(HttpClientWithContext(ctx, http.DefaultClient), srv.URL)
+	assert.Error(t, err)
+	assert.True(t, time.Since(start) < time.Second)
+}
+
+func TestNewHttpRequestWithContext_Canceled(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(`{}`))
+	}))
+	defer srv.Close()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	_, err := NewHttpRequestWithContext(ctx, http.DefaultClient, "GET", srv.URL, "", nil)
+	assert.Error(t, err)
+
+	body, err := NewHttpRequest(http.DefaultClient, "GET", srv.URL, "", nil)
+	assert.NoError(t, err)
+	assert.Equal(t, "{}", string(body))
+}
+
+func TestNewHttpRequest_StatusCode(t *testing.T) {
+	for _, c := range []struct {
+		status int
+		err    ApiError
+	}{
+		{418, EX_ERR_API_LIMIT},
+		{429, EX_ERR_API_LIMIT},
+		{502, EX_ERR_MAINTENANCE},
+		{503, EX_ERR_MAINTENANCE},
+		{400, HTTP_ERR_CODE},
+		{500, HTTP_ERR_CODE},
+	} {
+		srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			w.WriteHeader(c.status)
+			w.Write([]byte(`{"msg":"failed"}`))
+		}))
+		_, err := NewHttpRequest(http.DefaultClient, "GET", srv.URL, "", nil)
+		srv.Close()
+
+		apiErr, isok := err.(ApiError)
+		assert.True(t, isok, "%d", c.status)
+		assert.True(t, c.err.Is(apiErr), "%d", c.status)
+		assert.Equal(t, `{"msg":"failed"}`, apiErr.OriginErrMsg)
+		assert.Contains(t, apiErr.Error(), "HttpStatusCode:"+strconv.Itoa(c.status))
+	}
+}
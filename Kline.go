@@ -0,0 +1,202 @@
+package goex
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strconv"
+	"time"
+)
+
+//KlinePeriodDuration is the length of a candle of the period, months and years taken as 30 and 365 days
+func KlinePeriodDuration(period int) time.Duration {
+	switch period {
+	case KLINE_PERIOD_1MIN:
+		return time.Minute
+	case KLINE_PERIOD_5MIN:
+		return 5 * time.Minute
+	case KLINE_PERIOD_15MIN:
+		return 15 * time.Minute
+	case KLINE_PERIOD_30MIN:
+		return 30 * time.Minute
+	case KLINE_PERIOD_60MIN:
+		return time.Hour
+	case KLINE_PERIOD_4H:
+		return 4 * time.Hour
+	case KLINE_PERIOD_1DAY:
+		return 24 * time.Hour
+	case KLINE_PERIOD_1WEEK:
+		return 7 * 24 * time.Hour
+	case KLINE_PERIOD_1MONTH:
+		return 30 * 24 * time.Hour
+	case KLINE_PERIOD_1YEAR:
+		return 365 * 24 * time.Hour
+	}
+	return 0
+}
+
+//UnsupportedKlinePeriod is the error of GetKlineRecords for a period the exchange has no candles of
+func UnsupportedKlinePeriod(period int) error {
+	errCode := ErrNotSupported
+	errCode.OriginErrMsg = "kline period " + strconv.Itoa(period)
+	return errCode
+}
+
+//KlinesSince sorts the candles oldest first, drops the duplicates and keeps size of them: the first from since,
+//unix milliseconds, or the latest when since is 0. For exchanges answering a fixed window whatever is asked.
+func KlinesSince(klines []Kline, since int64, size int) []Kline {
+	sort.SliceStable(klines, func(i, j int) bool { return klines[i].Timestamp < klines[j].Timestamp })
+	unique := klines[:0]
+	for i, k := range klines {
+		if i > 0 && k.Timestamp == klines[i-1].Timestamp {
+			continue
+		}
+		if k.Timestamp*1000 < since {
+			continue
+		}
+		unique = append(unique, k)
+	}
+	if size > 0 && len(unique) > size {
+		if since > 0 {
+			return unique[:size]
+		}
+		return unique[len(unique)-size:]
+	}
+	return unique
+}
+
+//KlineChunkSizes is how many candles the exchanges answer at most, KlineIterator asks for as many at once
+var KlineChunkSizes = map[string]int{
+	"binance.com":  1000,
+	"bitfinex.com": 1000,
+	"bitstamp.net": 1000,
+	"kraken.com":   720,
+	"okcoin.cn":    2000,
+	"okcoin.com":   2000,
+	"okex.com":     2000,
+	"huobi.com":    2000,
+}
+
+//KlineLatestOnly are the exchanges answering a window of their latest candles whatever since asks, kraken the
+//last 720 and the others a window of their own. Their history can't be read back by since.
+var KlineLatestOnly = map[string]bool{
+	"bithumb.com": true,
+	"bittrex.com": true,
+	"huobi.com":   true,
+	"kraken.com":  true,
+}
+
+//KlineIterator walks the candles opened in [start, end) one exchange-sized chunk at a time, forward from start
+//or backward from end, each candle once even where the chunks the exchange answers overlap
+type KlineIterator struct {
+	Policy    RetryPolicy //retries every chunk, the rate limiter of the adapter's client paces them
+	ChunkSize int         //candles asked for at once, from KlineChunkSizes or 500
+
+	api        API
+	pair       CurrencyPair
+	period     int
+	start, end int64 //unix milliseconds
+	backward   bool
+	cursor     int64 //forward the start of the next chunk, backward the end of it
+	last       int64 //Timestamp of the last candle returned, 0 before the first
+	done       bool
+}
+
+//NewKlineIterator walks forward from start to before end, unix milliseconds with end 0 for now.
+//GetKlineRecords takes since as an int, which on 32-bit platforms holds no unix milliseconds past 1970-01-25:
+//Next fails there rather than ask for a wrapped around since.
+func NewKlineIterator(api API, pair CurrencyPair, period int, start, end int64) *KlineIterator {
+	it := newKlineIterator(api, pair, period, start, end)
+	it.cursor = start
+	return it
+}
+
+//NewKlineIteratorBackward walks back from end to start, and stops early at the first chunk without
+//candles, where the exchange's history of the pair begins
+func NewKlineIteratorBackward(api API, pair CurrencyPair, period int, start, end int64) *KlineIterator {
+	it := newKlineIterator(api, pair, period, start, end)
+	it.backward = true
+	it.cursor = it.end
+	return it
+}
+
+func newKlineIterator(api API, pair CurrencyPair, period int, start, end int64) *KlineIterator {
+	if end <= 0 {
+		end = time.Now().UnixNano() / int64(time.Millisecond)
+	}
+	chunk, ok := KlineChunkSizes[api.GetExchangeName()]
+	if !ok {
+		chunk = 500
+	}
+	return &KlineIterator{Policy: DefaultRetryPolicy, ChunkSize: chunk, api: api, pair: pair, period: period, start: start, end: end}
+}
+
+//Next returns the next chunk, oldest first either way. It returns nil, nil once Done.
+func (it *KlineIterator) Next(ctx context.Context) ([]Kline, error) {
+	if it.done {
+		return nil, nil
+	}
+	periodMs := int64(KlinePeriodDuration(it.period) / time.Millisecond)
+	if periodMs <= 0 {
+		return nil, UnsupportedKlinePeriod(it.period)
+	}
+	span := periodMs * int64(it.ChunkSize)
+
+	since := it.cursor
+	if it.backward {
+		since = it.cursor - span
+		if since < it.start {
+			since = it.start
+		}
+	}
+	if int64(int(since)) != since {
+		return nil, fmt.Errorf("kline since %d overflows int", since)
+	}
+	var klines []Kline
+	err := it.Policy.Do(ctx, func() (err error) {
+		klines, err = it.api.GetKlineRecords(it.pair, it.period, it.ChunkSize, int(since))
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	klines = KlinesSince(klines, since, 0)
+	chunk := make([]Kline, 0, len(klines))
+	for _, k := range klines {
+		ms := k.Timestamp * 1000
+		if ms < it.start || ms >= it.end {
+			continue
+		}
+		if it.last != 0 && (!it.backward && k.Timestamp <= it.last || it.backward && k.Timestamp >= it.last) {
+			continue
+		}
+		if it.backward && ms >= it.cursor {
+			continue
+		}
+		chunk = append(chunk, k)
+	}
+
+	if it.backward {
+		if len(chunk) > 0 {
+			it.last = chunk[0].Timestamp
+		}
+		it.cursor = since
+		it.done = len(chunk) == 0 || it.cursor <= it.start
+		return chunk, nil
+	}
+
+	if len(chunk) > 0 {
+		it.last = chunk[len(chunk)-1].Timestamp
+		it.cursor = it.last*1000 + periodMs
+	} else {
+		//nothing from the cursor on, a gap or an exchange answering a window of its own: skip ahead
+		it.cursor += span
+	}
+	it.done = it.cursor >= it.end
+	return chunk, nil
+}
+
+func (it *KlineIterator) Done() bool {
+	return it.done
+}
@@ -0,0 +1,76 @@
+package goex
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+//klineAPI answers minute candles from its own, oldest first, the way GetKlineRecords asks
+type klineAPI struct {
+	API
+	klines []Kline
+	calls  int
+}
+
+func (k *klineAPI) GetKlineRecords(currency CurrencyPair, period, size, since int) ([]Kline, error) {
+	k.calls++
+	return KlinesSince(append([]Kline{}, k.klines...), int64(since), size), nil
+}
+
+func (k *klineAPI) GetExchangeName() string {
+	return "kline.test"
+}
+
+func timestamps(klines []Kline) (ts []int64) {
+	for _, k := range klines {
+		ts = append(ts, k.Timestamp)
+	}
+	return ts
+}
+
+func TestKlinesSince(t *testing.T) {
+	klines := []Kline{{Timestamp: 180}, {Timestamp: 60}, {Timestamp: 120}, {Timestamp: 60}, {Timestamp: 240}}
+	assert.Equal(t, []int64{120, 180}, timestamps(KlinesSince(append([]Kline{}, klines...), 120000, 2)))
+	assert.Equal(t, []int64{180, 240}, timestamps(KlinesSince(append([]Kline{}, klines...), 0, 2)))
+	assert.Equal(t, []int64{60, 120, 180, 240}, timestamps(KlinesSince(append([]Kline{}, klines...), 0, 0)))
+}
+
+func TestKlineIterator(t *testing.T) {
+	api := new(klineAPI)
+	for ts := int64(600); ts < 1800; ts += 60 {
+		api.klines = append(api.klines, Kline{Timestamp: ts})
+	}
+
+	drain := func(it *KlineIterator) (ts []int64) {
+		it.ChunkSize = 3
+		for !it.Done() {
+			klines, err := it.Next(context.Background())
+			assert.Nil(t, err)
+			ts = append(ts, timestamps(klines)...)
+		}
+		return ts
+	}
+
+	//forward from before the first candle, the candle opened at end left out
+	ts := drain(NewKlineIterator(api, BTC_USD, KLINE_PERIOD_1MIN, 300000, 1020000))
+	assert.Equal(t, []int64{600, 660, 720, 780, 840, 900, 960}, ts)
+
+	//both directions walk the same [start, end)
+	forward := drain(NewKlineIterator(api, BTC_USD, KLINE_PERIOD_1MIN, 1500000, 1800000))
+	assert.Equal(t, []int64{1500, 1560, 1620, 1680, 1740}, forward)
+
+	//backward, each chunk oldest first
+	ts = drain(NewKlineIteratorBackward(api, BTC_USD, KLINE_PERIOD_1MIN, 1500000, 1800000))
+	assert.Equal(t, []int64{1620, 1680, 1740, 1500, 1560}, ts)
+
+	//backward stops where the history begins
+	api.calls = 0
+	ts = drain(NewKlineIteratorBackward(api, BTC_USD, KLINE_PERIOD_1MIN, 0, 900000))
+	assert.Equal(t, []int64{720, 780, 840, 600, 660}, ts)
+	assert.Equal(t, 3, api.calls)
+
+	_, err := NewKlineIterator(api, BTC_USD, -1, 0, 0).Next(context.Background())
+	assert.True(t, ErrNotSupported.Is(err))
+}
@@ -7,28 +7,29 @@ type Order struct {
 	Amount,
 	AvgPrice,
 	DealAmount,
-	Fee float64
-	OrderID2  string
-	OrderID   int
-	OrderTime int
-	Status    TradeStatus
-	Currency  CurrencyPair
-	Side      TradeSide
+	Fee Decimal
+	OrderID2      string
+	OrderID       int
+	ClientOrderID string //set when placed with PlaceOrderWithClientID
+	OrderTime     int
+	Status        TradeStatus
+	Currency      CurrencyPair
+	Side          TradeSide
 }
 
 type Trade struct {
 	Tid    int64   `json:"tid"`
 	Type   string  `json:"type"`
-	Amount float64 `json:"amount,string"`
-	Price  float64 `json:"price,string"`
-	Date   int64   `json:"date_ms"`
+	Amount Decimal `json:"amount"`
+	Price  Decimal `json:"price"`
+	Date   int64   `json:"date_ms"` //unix milliseconds
 }
 
 type SubAccount struct {
 	Currency Currency
 	Amount,
 	FrozenAmount,
-	LoanAmount float64
+	LoanAmount Decimal
 }
 
 type Account struct {
@@ -39,29 +40,31 @@ type Account struct {
 }
 
 type Ticker struct {
-	Last float64 `json:"last"`
-	Buy  float64 `json:"buy"`
-	Sell float64 `json:"sell"`
-	High float64 `json:"high"`
-	Low  float64 `json:"low"`
-	Vol  float64 `json:"vol"`
+	Last Decimal `json:"last"`
+	Buy  Decimal `json:"buy"`
+	Sell Decimal `json:"sell"`
+	High Decimal `json:"high"`
+	Low  Decimal `json:"low"`
+	Vol  Decimal `json:"vol"`
 	Date uint64  `json:"date"`
 }
 
 type DepthRecord struct {
 	Price,
-	Amount float64
+	Amount Decimal
 }
 
 // Withdraw is a uniform struct for returning Withdraw request results.
 type Withdraw struct {
-  RefID         string  `json:"refid"`
-  WithdrawalID  int     `json:"withdrawal_id"`
-  WithdrawID    int     `json:"withdraw_id"`
-  Result        bool    `json:"result"`
-  Status        string  `json:"status"`
-  Message       string  `json:"message"`
-  ErrorCode     int     `json:"error_code"`
+	ID       string //the exchange's withdrawal id
+	Currency Currency
+	Amount   Decimal
+	Fee      Decimal
+	Address  string
+	Tag      string
+	TxID     string //transaction hash, once sent to the network
+	Status   WithdrawStatus
+	Time     int64 //unix seconds the withdrawal was requested, 0 when unknown
 }
 
 // CryptoAddress stores the address hash and related information.
@@ -83,7 +86,7 @@ func (dr DepthRecords) Swap(i, j int) {
 }
 
 func (dr DepthRecords) Less(i, j int) bool {
-	return dr[i].Price < dr[j].Price
+	return dr[i].Price.LessThan(dr[j].Price)
 }
 
 type Depth struct {
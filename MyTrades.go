@@ -0,0 +1,36 @@
+package goex
+
+import "sort"
+
+//MyTrade is one fill of an order of the account
+type MyTrade struct {
+	TradeID     string
+	OrderID     string
+	Currency    CurrencyPair
+	Side        TradeSide //BUY or SELL
+	Price       Decimal
+	Amount      Decimal
+	Fee         Decimal //what the fill cost, positive
+	FeeCurrency Currency
+	IsMaker     bool
+	Time        int64 //unix milliseconds of the fill
+}
+
+//MyTradesAPI is implemented by adapters that list the account's own fills, unlike GetTrades which lists
+//the whole market's. GetMyTrades returns at most limit fills made at or after since, unix milliseconds,
+//oldest first.
+type MyTradesAPI interface {
+	GetMyTrades(pair CurrencyPair, since int64, limit int) ([]MyTrade, error)
+}
+
+//MyTradesSince sorts the fills oldest first and keeps the first limit made at or after since,
+//for exchanges listing the newest first
+func MyTradesSince(trades []MyTrade, since int64, limit int) []MyTrade {
+	sort.SliceStable(trades, func(i, j int) bool { return trades[i].Time < trades[j].Time })
+	i := sort.Search(len(trades), func(i int) bool { return trades[i].Time >= since })
+	trades = trades[i:]
+	if limit > 0 && len(trades) > limit {
+		trades = trades[:limit]
+	}
+	return trades
+}
@@ -0,0 +1,21 @@
+package goex
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestMyTradesSince(t *testing.T) {
+	trades := []MyTrade{{TradeID: "4", Time: 4000}, {TradeID: "3", Time: 3000}, {TradeID: "2", Time: 2000}, {TradeID: "1", Time: 1000}}
+
+	ids := func(trades []MyTrade) (ids []string) {
+		for _, t := range trades {
+			ids = append(ids, t.TradeID)
+		}
+		return ids
+	}
+	assert.Equal(t, []string{"2", "3"}, ids(MyTradesSince(trades, 2000, 2)))
+	assert.Equal(t, []string{"1", "2", "3", "4"}, ids(MyTradesSince(trades, 0, 0)))
+	assert.Len(t, MyTradesSince(trades, 5000, 10), 0)
+}
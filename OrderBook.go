@@ -0,0 +1,241 @@
+package goex
+
+import (
+	"fmt"
+	"sort"
+	"sync"
+	"time"
+)
+
+//OrderBookUpdate is one diff of an order book feed.
+//Amounts are absolute, an amount of 0 removes the price level.
+type OrderBookUpdate struct {
+	FirstSeq, LastSeq int64 //sequence numbers covered by the update, leave both 0 for an unsequenced feed
+	Bids, Asks        DepthRecords
+
+	Checksum    int32 //compared with OrderBook.ChecksumFunc after applying when HasChecksum is set
+	HasChecksum bool
+}
+
+//OrderBook is a local order book kept up to date from a snapshot plus diff updates.
+//Update is meant to be called from a single feed goroutine, every other method is safe for many readers.
+//When a sequence gap or a checksum mismatch is found the book marks itself out of sync, keeps the
+//updates that arrive meanwhile and rebuilds from SnapshotFunc.
+type OrderBook struct {
+	SnapshotFunc func() (*Depth, int64, error) //a fresh snapshot and its sequence number, nil: the feed resyncs by calling Reset
+	ChecksumFunc func(top *Depth) int32        //checksum of the top 25 levels, as the exchanges compute it
+
+	l       sync.RWMutex
+	bids    DepthRecords //highest price first
+	asks    DepthRecords //lowest price first
+	seq     int64
+	synced  bool
+	pending []OrderBookUpdate
+
+	snapshotAt time.Time
+}
+
+const (
+	maxPendingOrderBookUpdates = 10000       //at most this many updates are kept while waiting for a snapshot
+	orderBookSnapshotInterval  = time.Second //SnapshotFunc is not called more often than this
+)
+
+func NewOrderBook() *OrderBook {
+	return &OrderBook{}
+}
+
+//Reset replaces the whole book with depth, seq is the sequence number the snapshot is valid at
+func (ob *OrderBook) Reset(depth *Depth, seq int64) {
+	ob.l.Lock()
+	defer ob.l.Unlock()
+	ob.resetLocked(depth, seq)
+	ob.pending = nil
+}
+
+//Update applies u. A non nil error means the book is out of sync; it has already tried to
+//resync from SnapshotFunc and will try again on the next Update.
+func (ob *OrderBook) Update(u OrderBookUpdate) error {
+	ob.l.Lock()
+	if ob.synced {
+		err := ob.applyLocked(u)
+		if err == nil {
+			ob.l.Unlock()
+			return nil
+		}
+		ob.synced = false
+		ob.pending = nil
+		if EX_ERR_ORDERBOOK_SEQ_GAP.Is(err) {
+			ob.pending = append(ob.pending, u)
+		}
+		if ob.SnapshotFunc == nil {
+			ob.l.Unlock()
+			return err
+		}
+	} else {
+		ob.pending = append(ob.pending, u)
+		if len(ob.pending) > maxPendingOrderBookUpdates {
+			ob.pending = ob.pending[len(ob.pending)-maxPendingOrderBookUpdates:]
+		}
+		if ob.SnapshotFunc == nil || time.Since(ob.snapshotAt) < orderBookSnapshotInterval {
+			ob.l.Unlock()
+			errCode := EX_ERR_ORDERBOOK_SEQ_GAP
+			errCode.OriginErrMsg = "waiting for a snapshot"
+			return errCode
+		}
+	}
+	ob.snapshotAt = time.Now()
+	ob.l.Unlock()
+
+	//readers keep seeing the old book while the snapshot is fetched
+	depth, seq, err := ob.SnapshotFunc()
+	if err != nil {
+		return err
+	}
+
+	ob.l.Lock()
+	defer ob.l.Unlock()
+	pending := ob.pending
+	ob.resetLocked(depth, seq)
+	ob.pending = nil
+	for i, p := range pending {
+		if err = ob.applyLocked(p); err != nil {
+			ob.synced = false
+			if EX_ERR_ORDERBOOK_SEQ_GAP.Is(err) {
+				//the snapshot is older than the buffered updates, try again with a newer one
+				ob.pending = pending[i:]
+			}
+			return err
+		}
+	}
+	return nil
+}
+
+func (ob *OrderBook) Synced() bool {
+	ob.l.RLock()
+	defer ob.l.RUnlock()
+	return ob.synced
+}
+
+//Seq is the sequence number of the last applied update or snapshot
+func (ob *OrderBook) Seq() int64 {
+	ob.l.RLock()
+	defer ob.l.RUnlock()
+	return ob.seq
+}
+
+func (ob *OrderBook) BestBid() (DepthRecord, bool) {
+	ob.l.RLock()
+	defer ob.l.RUnlock()
+	if len(ob.bids) == 0 {
+		return DepthRecord{}, false
+	}
+	return ob.bids[0], true
+}
+
+func (ob *OrderBook) BestAsk() (DepthRecord, bool) {
+	ob.l.RLock()
+	defer ob.l.RUnlock()
+	if len(ob.asks) == 0 {
+		return DepthRecord{}, false
+	}
+	return ob.asks[0], true
+}
+
+//AmountAt is the amount resting at exactly price, on whichever side holds it
+func (ob *OrderBook) AmountAt(price Decimal) Decimal {
+	ob.l.RLock()
+	defer ob.l.RUnlock()
+	if i, found := searchLevel(ob.bids, price, true); found {
+		return ob.bids[i].Amount
+	}
+	if i, found := searchLevel(ob.asks, price, false); found {
+		return ob.asks[i].Amount
+	}
+	return Decimal{}
+}
+
+//Depth returns the top size levels of both sides, bids from the highest price and asks from the lowest.
+//size <= 0 returns the whole book.
+func (ob *OrderBook) Depth(size int) *Depth {
+	ob.l.RLock()
+	defer ob.l.RUnlock()
+	return ob.depthLocked(size)
+}
+
+//depthLocked copies the top size levels, the sides are already best first
+func (ob *OrderBook) depthLocked(size int) *Depth {
+	top := func(levels DepthRecords) DepthRecords {
+		if size > 0 && len(levels) > size {
+			levels = levels[:size]
+		}
+		return append(DepthRecords(nil), levels...)
+	}
+	return &Depth{BidList: top(ob.bids), AskList: top(ob.asks)}
+}
+
+func (ob *OrderBook) resetLocked(depth *Depth, seq int64) {
+	ob.bids, ob.asks = nil, nil
+	if depth != nil {
+		applyLevels(&ob.bids, depth.BidList, true)
+		applyLevels(&ob.asks, depth.AskList, false)
+	}
+	ob.seq = seq
+	ob.synced = true
+}
+
+func (ob *OrderBook) applyLocked(u OrderBookUpdate) error {
+	if u.LastSeq != 0 {
+		if u.LastSeq <= ob.seq {
+			return nil //already in the snapshot
+		}
+		if u.FirstSeq > ob.seq+1 {
+			errCode := EX_ERR_ORDERBOOK_SEQ_GAP
+			errCode.OriginErrMsg = fmt.Sprintf("expected %d got %d", ob.seq+1, u.FirstSeq)
+			return errCode
+		}
+		ob.seq = u.LastSeq
+	}
+
+	applyLevels(&ob.bids, u.Bids, true)
+	applyLevels(&ob.asks, u.Asks, false)
+
+	if u.HasChecksum && ob.ChecksumFunc != nil {
+		if checksum := ob.ChecksumFunc(ob.depthLocked(25)); checksum != u.Checksum {
+			errCode := EX_ERR_ORDERBOOK_CHECKSUM
+			errCode.OriginErrMsg = fmt.Sprintf("expected %d got %d", u.Checksum, checksum)
+			return errCode
+		}
+	}
+	return nil
+}
+
+//searchLevel is where price is, or would go, in levels kept best first: highest first for bids, lowest for asks
+func searchLevel(levels DepthRecords, price Decimal, bids bool) (int, bool) {
+	i := sort.Search(len(levels), func(i int) bool {
+		c := levels[i].Price.Cmp(price)
+		if bids {
+			c = -c
+		}
+		return c >= 0
+	})
+	return i, i < len(levels) && levels[i].Price.Cmp(price) == 0
+}
+
+//applyLevels sets the amounts of records in side, keeping it best first
+func applyLevels(side *DepthRecords, records DepthRecords, bids bool) {
+	for _, r := range records {
+		i, found := searchLevel(*side, r.Price, bids)
+		switch {
+		case r.Amount.IsZero():
+			if found {
+				*side = append((*side)[:i], (*side)[i+1:]...)
+			}
+		case found:
+			(*side)[i].Amount = r.Amount
+		default:
+			*side = append(*side, DepthRecord{})
+			copy((*side)[i+1:], (*side)[i:])
+			(*side)[i] = r
+		}
+	}
+}
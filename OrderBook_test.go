@@ -0,0 +1,98 @@
+package goex
+
+import (
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestOrderBook_Update(t *testing.T) {
+	ob := NewOrderBook()
+	ob.Reset(&Depth{
+		BidList: DepthRecords{{Price: RequireDecimal("100"), Amount: RequireDecimal("1")}, {Price: RequireDecimal("99"), Amount: RequireDecimal("2")}},
+		AskList: DepthRecords{{Price: RequireDecimal("101"), Amount: RequireDecimal("1")}, {Price: RequireDecimal("102"), Amount: RequireDecimal("3")}}}, 10)
+
+	assert.Nil(t, ob.Update(OrderBookUpdate{FirstSeq: 11, LastSeq: 12,
+		Bids: DepthRecords{{Price: RequireDecimal("100"), Amount: Decimal{}}, {Price: RequireDecimal("99.5"), Amount: RequireDecimal("4")}},
+		Asks: DepthRecords{{Price: RequireDecimal("100.5"), Amount: RequireDecimal("0.5")}}}))
+	//already covered by the previous update
+	assert.Nil(t, ob.Update(OrderBookUpdate{FirstSeq: 12, LastSeq: 12, Bids: DepthRecords{{Price: RequireDecimal("1"), Amount: RequireDecimal("1")}}}))
+
+	bid, _ := ob.BestBid()
+	ask, _ := ob.BestAsk()
+	assert.Equal(t, DepthRecord{Price: RequireDecimal("99.5"), Amount: RequireDecimal("4")}, bid)
+	assert.Equal(t, DepthRecord{Price: RequireDecimal("100.5"), Amount: RequireDecimal("0.5")}, ask)
+	assert.Equal(t, RequireDecimal("3"), ob.AmountAt(RequireDecimal("102")))
+	assert.True(t, ob.AmountAt(RequireDecimal("100")).IsZero())
+	assert.Equal(t, int64(12), ob.Seq())
+	assert.Equal(t, &Depth{
+		BidList: DepthRecords{{Price: RequireDecimal("99.5"), Amount: RequireDecimal("4")}, {Price: RequireDecimal("99"), Amount: RequireDecimal("2")}},
+		AskList: DepthRecords{{Price: RequireDecimal("100.5"), Amount: RequireDecimal("0.5")}, {Price: RequireDecimal("101"), Amount: RequireDecimal("1")}}}, ob.Depth(2))
+}
+
+func TestOrderBook_ResyncOnGap(t *testing.T) {
+	ob := NewOrderBook()
+	ob.Reset(&Depth{BidList: DepthRecords{{Price: RequireDecimal("100"), Amount: RequireDecimal("1")}}}, 10)
+
+	snapshots := 0
+	ob.SnapshotFunc = func() (*Depth, int64, error) {
+		snapshots++
+		return &Depth{BidList: DepthRecords{{Price: RequireDecimal("98"), Amount: RequireDecimal("1")}}}, 20, nil
+	}
+
+	//13..19 were lost, the snapshot at 20 covers them and the buffered 19..21 is replayed on top
+	err := ob.Update(OrderBookUpdate{FirstSeq: 19, LastSeq: 21, Bids: DepthRecords{{Price: RequireDecimal("97"), Amount: RequireDecimal("5")}}})
+	assert.Nil(t, err)
+	assert.Equal(t, 1, snapshots)
+	assert.True(t, ob.Synced())
+	assert.Equal(t, int64(21), ob.Seq())
+	assert.Equal(t, DepthRecords{{Price: RequireDecimal("98"), Amount: RequireDecimal("1")}, {Price: RequireDecimal("97"), Amount: RequireDecimal("5")}}, ob.Depth(0).BidList)
+
+	//a snapshot older than the buffered updates leaves the book waiting
+	err = ob.Update(OrderBookUpdate{FirstSeq: 30, LastSeq: 30})
+	assert.True(t, EX_ERR_ORDERBOOK_SEQ_GAP.Is(err))
+	assert.False(t, ob.Synced())
+	err = ob.Update(OrderBookUpdate{FirstSeq: 31, LastSeq: 31})
+	assert.True(t, EX_ERR_ORDERBOOK_SEQ_GAP.Is(err))
+	assert.Equal(t, 2, snapshots)
+}
+
+func TestOrderBook_Checksum(t *testing.T) {
+	ob := NewOrderBook()
+	ob.ChecksumFunc = func(top *Depth) int32 {
+		return int32(len(top.BidList)*10 + len(top.AskList))
+	}
+	ob.Reset(&Depth{BidList: DepthRecords{{Price: RequireDecimal("100"), Amount: RequireDecimal("1")}}}, 0)
+
+	assert.Nil(t, ob.Update(OrderBookUpdate{Asks: DepthRecords{{Price: RequireDecimal("101"), Amount: RequireDecimal("1")}}, Checksum: 11, HasChecksum: true}))
+	err := ob.Update(OrderBookUpdate{Asks: DepthRecords{{Price: RequireDecimal("102"), Amount: RequireDecimal("1")}}, Checksum: 11, HasChecksum: true})
+	assert.True(t, EX_ERR_ORDERBOOK_CHECKSUM.Is(err))
+	assert.False(t, ob.Synced())
+
+	ob.Reset(&Depth{}, 0)
+	assert.True(t, ob.Synced())
+}
+
+func TestOrderBook_ConcurrentReaders(t *testing.T) {
+	ob := NewOrderBook()
+	ob.Reset(&Depth{}, 0)
+
+	var wg sync.WaitGroup
+	for i := 0; i < 4; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for j := 0; j < 1000; j++ {
+				ob.BestBid()
+				ob.Depth(5)
+			}
+		}()
+	}
+	for i := int64(1); i <= 1000; i++ {
+		ob.Update(OrderBookUpdate{FirstSeq: i, LastSeq: i, Bids: DepthRecords{{Price: NewDecimalFromInt(i % 50), Amount: RequireDecimal("1")}}})
+	}
+	wg.Wait()
+	bid, _ := ob.BestBid()
+	assert.Equal(t, RequireDecimal("49"), bid.Price)
+}
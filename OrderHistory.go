@@ -0,0 +1,112 @@
+package goex
+
+import (
+	"context"
+	"strconv"
+)
+
+//OrderHistoryAPI is implemented by adapters that page the order history by time with the exchange's own
+//pagination. GetOrderHistory returns a page of the orders created from start to end, unix milliseconds with
+//end 0 for now, and the cursor of the next page, "" after the last one. Pass cursor "" for the first page.
+//Cursors are opaque, only the adapter that returned one understands it.
+type OrderHistoryAPI interface {
+	GetOrderHistory(pair CurrencyPair, start, end int64, cursor string) ([]Order, string, error)
+}
+
+//OrderHistoryIterator walks the pages of a range one Next at a time
+type OrderHistoryIterator struct {
+	Policy RetryPolicy //retries every page, the rate limiter of the adapter's client paces them
+
+	api        OrderHistoryAPI
+	pair       CurrencyPair
+	start, end int64
+	cursor     string
+	done       bool
+}
+
+func NewOrderHistoryIterator(api OrderHistoryAPI, pair CurrencyPair, start, end int64) *OrderHistoryIterator {
+	return &OrderHistoryIterator{Policy: DefaultRetryPolicy, api: api, pair: pair, start: start, end: end}
+}
+
+//Next returns the next page, an empty one may come before the last. It returns nil, nil once Done.
+func (it *OrderHistoryIterator) Next(ctx context.Context) ([]Order, error) {
+	if it.done {
+		return nil, nil
+	}
+	var orders []Order
+	var cursor string
+	err := it.Policy.Do(ctx, func() (err error) {
+		orders, cursor, err = it.api.GetOrderHistory(it.pair, it.start, it.end, it.cursor)
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+	it.cursor = cursor
+	it.done = cursor == ""
+	return orders, nil
+}
+
+func (it *OrderHistoryIterator) Done() bool {
+	return it.done
+}
+
+//DrainOrderHistory reads every page of the range, "all orders since Tuesday" is
+//DrainOrderHistory(ctx, api, pair, tuesday.UnixNano()/1e6, 0)
+func DrainOrderHistory(ctx context.Context, api OrderHistoryAPI, pair CurrencyPair, start, end int64) ([]Order, error) {
+	it := NewOrderHistoryIterator(api, pair, start, end)
+	var all []Order
+	for !it.Done() {
+		if err := ctx.Err(); err != nil {
+			return all, err
+		}
+		orders, err := it.Next(ctx)
+		if err != nil {
+			return all, err
+		}
+		all = append(all, orders...)
+	}
+	return all, nil
+}
+
+//PagedOrderHistory gives an OrderHistoryAPI to the adapters that only page GetOrderHistorys by number, newest
+//first with OrderTime in unix milliseconds. The cursor is the page number, the pages are read until one holds
+//an order older than start.
+type PagedOrderHistory struct {
+	API
+	PageSize int
+}
+
+func (p PagedOrderHistory) GetOrderHistory(pair CurrencyPair, start, end int64, cursor string) ([]Order, string, error) {
+	page := 1
+	if cursor != "" {
+		var err error
+		if page, err = strconv.Atoi(cursor); err != nil {
+			errCode := API_ERR
+			errCode.OriginErrMsg = "bad cursor " + cursor
+			return nil, "", errCode
+		}
+	}
+	orders, err := p.API.GetOrderHistorys(pair, page, p.PageSize)
+	if err != nil {
+		return nil, "", err
+	}
+
+	inRange := make([]Order, 0, len(orders))
+	next := strconv.Itoa(page + 1)
+	for _, ord := range orders {
+		t := int64(ord.OrderTime)
+		if t < start {
+			next = ""
+			continue
+		}
+		if end > 0 && t > end {
+			continue
+		}
+		inRange = append(inRange, ord)
+	}
+	if len(orders) < p.PageSize {
+		next = ""
+	}
+	return inRange, next, nil
+}
@@ -0,0 +1,87 @@
+package goex
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+//historyAPI pages orders newest first, err fails the next call
+type historyAPI struct {
+	API
+	orders []Order
+	err    error
+	calls  int
+}
+
+func (h *historyAPI) GetOrderHistorys(currency CurrencyPair, currentPage, pageSize int) ([]Order, error) {
+	h.calls++
+	if err := h.err; err != nil {
+		h.err = nil
+		return nil, err
+	}
+	start := (currentPage - 1) * pageSize
+	if start >= len(h.orders) {
+		return []Order{}, nil
+	}
+	end := start + pageSize
+	if end > len(h.orders) {
+		end = len(h.orders)
+	}
+	return h.orders[start:end], nil
+}
+
+func TestDrainOrderHistory(t *testing.T) {
+	inner := new(historyAPI)
+	for i := 10; i > 0; i-- {
+		inner.orders = append(inner.orders, Order{OrderID: i, OrderTime: i * 1000})
+	}
+	api := PagedOrderHistory{API: inner, PageSize: 3}
+
+	ids := func(orders []Order) (ids []int) {
+		for _, o := range orders {
+			ids = append(ids, o.OrderID)
+		}
+		return ids
+	}
+	orders, err := DrainOrderHistory(context.Background(), api, BTC_USD, 4000, 8000)
+	assert.Nil(t, err)
+	assert.Equal(t, []int{8, 7, 6, 5, 4}, ids(orders))
+	assert.Equal(t, 3, inner.calls)
+
+	//the rate limit is waited out
+	inner.calls = 0
+	inner.err = EX_ERR_API_LIMIT
+	orders, err = DrainOrderHistory(context.Background(), api, BTC_USD, 0, 0)
+	assert.Nil(t, err)
+	assert.Len(t, orders, 10)
+	assert.Equal(t, 5, inner.calls)
+
+	inner.err = EX_ERR_SIGN
+	_, err = DrainOrderHistory(context.Background(), api, BTC_USD, 0, 0)
+	assert.Equal(t, EX_ERR_SIGN, err)
+}
+
+func TestOrderHistoryIterator(t *testing.T) {
+	inner := new(historyAPI)
+	for i := 4; i > 0; i-- {
+		inner.orders = append(inner.orders, Order{OrderID: i, OrderTime: i * 1000})
+	}
+	it := NewOrderHistoryIterator(PagedOrderHistory{API: inner, PageSize: 2}, BTC_USD, 0, 0)
+
+	var pages [][]Order
+	for !it.Done() {
+		orders, err := it.Next(context.Background())
+		assert.Nil(t, err)
+		pages = append(pages, orders)
+	}
+	assert.Len(t, pages, 3)
+	assert.Len(t, pages[2], 0)
+	orders, err := it.Next(context.Background())
+	assert.Nil(t, orders)
+	assert.Nil(t, err)
+
+	_, _, err = PagedOrderHistory{API: inner, PageSize: 2}.GetOrderHistory(BTC_USD, 0, 0, "x")
+	assert.True(t, API_ERR.Is(err))
+}
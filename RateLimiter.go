@@ -0,0 +1,273 @@
+package goex
+
+import (
+	"context"
+	"net/http"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+//EndpointClass groups the requests an exchange counts against the same limit
+type EndpointClass int
+
+const (
+	ENDPOINT_PUBLIC  EndpointClass = iota //market data
+	ENDPOINT_PRIVATE                      //account and order queries
+	ENDPOINT_ORDER                        //placing and cancelling orders
+)
+
+func (c EndpointClass) String() string {
+	switch c {
+	case ENDPOINT_PUBLIC:
+		return "public"
+	case ENDPOINT_PRIVATE:
+		return "private"
+	case ENDPOINT_ORDER:
+		return "order"
+	}
+	return "unknown"
+}
+
+//RateLimit allows Limit units of weight per Interval to the requests of Classes.
+//Binance for example counts all requests against one weight budget and orders against another:
+//
+//	[]RateLimit{
+//		{Classes: []EndpointClass{ENDPOINT_PUBLIC, ENDPOINT_PRIVATE, ENDPOINT_ORDER}, Limit: 1200, Interval: time.Minute},
+//		{Classes: []EndpointClass{ENDPOINT_ORDER}, Limit: 10, Interval: time.Second}}
+type RateLimit struct {
+	Classes  []EndpointClass
+	Limit    int
+	Interval time.Duration
+}
+
+//EndpointClassifier tells the class and weight of a request
+type EndpointClassifier func(req *http.Request) (EndpointClass, int)
+
+//RateLimitConfig is what an adapter knows about the limits of its exchange
+type RateLimitConfig struct {
+	Limits   []RateLimit
+	Classify EndpointClassifier
+}
+
+//DefaultRateLimitConfig is used for the exchanges without a config of their own,
+//it stays well below what any of them allows
+var DefaultRateLimitConfig = RateLimitConfig{
+	Limits: []RateLimit{
+		{Classes: []EndpointClass{ENDPOINT_PUBLIC}, Limit: 10, Interval: time.Second},
+		{Classes: []EndpointClass{ENDPOINT_PRIVATE}, Limit: 5, Interval: time.Second},
+		{Classes: []EndpointClass{ENDPOINT_ORDER}, Limit: 5, Interval: time.Second}},
+	Classify: ClassifyEndpoint}
+
+//ClassifyEndpoint guesses the class of a request from its method, path and credentials, every request weighs 1.
+//Requests that change something are orders when their path speaks of trading, private otherwise.
+//Reads are private when they carry a key or a signature.
+func ClassifyEndpoint(req *http.Request) (EndpointClass, int) {
+	path := strings.ToLower(req.URL.Path)
+	if req.Method != "GET" {
+		for _, word := range []string{"trade", "cancel", "place", "buy", "sell"} {
+			if strings.Contains(path, word) {
+				return ENDPOINT_ORDER, 1
+			}
+		}
+		if strings.HasSuffix(path, "/order") || strings.HasSuffix(path, "/orders") {
+			return ENDPOINT_ORDER, 1
+		}
+		return ENDPOINT_PRIVATE, 1
+	}
+
+	for k := range req.URL.Query() {
+		if isCredentialName(k) {
+			return ENDPOINT_PRIVATE, 1
+		}
+	}
+	for k := range req.Header {
+		if isCredentialName(k) || k == "Authorization" {
+			return ENDPOINT_PRIVATE, 1
+		}
+	}
+	return ENDPOINT_PUBLIC, 1
+}
+
+func isCredentialName(name string) bool {
+	name = strings.ToLower(name)
+	return strings.Contains(name, "sign") || strings.Contains(name, "key")
+}
+
+//RateLimiter is a set of token buckets, one per RateLimit. Share one between all the clients
+//that use the same credentials, it is safe for concurrent use.
+type RateLimiter struct {
+	l       sync.Mutex
+	buckets []*tokenBucket
+}
+
+type tokenBucket struct {
+	classes  []EndpointClass
+	capacity float64
+	rate     float64 //tokens per second
+	tokens   float64 //negative when waiters have reserved tokens not refilled yet
+	last     time.Time
+}
+
+func NewRateLimiter(limits ...RateLimit) *RateLimiter {
+	rl := &RateLimiter{}
+	now := time.Now()
+	for _, limit := range limits {
+		rl.buckets = append(rl.buckets, &tokenBucket{
+			classes:  limit.Classes,
+			capacity: float64(limit.Limit),
+			rate:     float64(limit.Limit) / limit.Interval.Seconds(),
+			tokens:   float64(limit.Limit),
+			last:     now})
+	}
+	return rl
+}
+
+func (b *tokenBucket) covers(class EndpointClass) bool {
+	for _, c := range b.classes {
+		if c == class {
+			return true
+		}
+	}
+	return false
+}
+
+func (b *tokenBucket) refill(now time.Time) {
+	b.tokens += now.Sub(b.last).Seconds() * b.rate
+	if b.tokens > b.capacity {
+		b.tokens = b.capacity
+	}
+	b.last = now
+}
+
+//Wait blocks until a request of class and weight fits in every limit covering class,
+//or returns ctx.Err() when ctx is done first.
+func (rl *RateLimiter) Wait(ctx context.Context, class EndpointClass, weight int) error {
+	rl.l.Lock()
+	now := time.Now()
+	var delay time.Duration
+	var reserved []*tokenBucket
+	for _, b := range rl.buckets {
+		if !b.covers(class) {
+			continue
+		}
+		b.refill(now)
+		b.tokens -= float64(weight)
+		reserved = append(reserved, b)
+		if b.tokens < 0 {
+			if d := time.Duration(-b.tokens / b.rate * float64(time.Second)); d > delay {
+				delay = d
+			}
+		}
+	}
+	rl.l.Unlock()
+
+	if delay <= 0 {
+		return nil
+	}
+	timer := time.NewTimer(delay)
+	defer timer.Stop()
+	select {
+	case <-timer.C:
+		return nil
+	case <-ctx.Done():
+		rl.l.Lock()
+		for _, b := range reserved {
+			b.tokens += float64(weight)
+		}
+		rl.l.Unlock()
+		return ctx.Err()
+	}
+}
+
+//Pause empties the buckets covering class and holds them empty for d more,
+//for when the exchange answers that the limit is reached anyway
+func (rl *RateLimiter) Pause(class EndpointClass, d time.Duration) {
+	rl.l.Lock()
+	defer rl.l.Unlock()
+	now := time.Now()
+	for _, b := range rl.buckets {
+		if !b.covers(class) {
+			continue
+		}
+		b.refill(now)
+		if b.tokens > 0 {
+			b.tokens = 0
+		}
+		b.tokens -= d.Seconds() * b.rate
+	}
+}
+
+//RateLimitedClient returns a shallow copy of client whose requests wait for limiter first,
+//so adapters honor the limits without changing their call sites
+func RateLimitedClient(client *http.Client, limiter *RateLimiter, classify EndpointClassifier) *http.Client {
+	if client == nil {
+		client = http.DefaultClient
+	}
+	transport := client.Transport
+	if transport == nil {
+		transport = http.DefaultTransport
+	}
+	c := *client
+	c.Transport = &rateLimitTransport{limiter: limiter, classify: classify, transport: transport}
+	return &c
+}
+
+//rateLimiters holds one RateLimiter per exchange and api key, shared by every client made for them
+var rateLimiters = struct {
+	sync.Mutex
+	m map[string]*RateLimiter
+}{m: map[string]*RateLimiter{}}
+
+//SharedRateLimiter is the RateLimiter of exchange and apiKey, made from config the first time it is asked for
+func SharedRateLimiter(exchange, apiKey string, config RateLimitConfig) *RateLimiter {
+	rateLimiters.Lock()
+	defer rateLimiters.Unlock()
+	key := exchange + "|" + apiKey
+	limiter := rateLimiters.m[key]
+	if limiter == nil {
+		limiter = NewRateLimiter(config.Limits...)
+		rateLimiters.m[key] = limiter
+	}
+	return limiter
+}
+
+//ExchangeRateLimitedClient is what the adapter constructors send their requests with: client waiting for
+//the SharedRateLimiter of exchange and apiKey. A client already rate limited, or made by UnlimitedClient, is kept as is.
+func ExchangeRateLimitedClient(client *http.Client, exchange, apiKey string, config RateLimitConfig) *http.Client {
+	if client != nil {
+		if _, isok := client.Transport.(*rateLimitTransport); isok {
+			return client
+		}
+	}
+	return RateLimitedClient(client, SharedRateLimiter(exchange, apiKey, config), config.Classify)
+}
+
+//UnlimitedClient returns a shallow copy of client the adapters send their requests with as fast as they are made
+func UnlimitedClient(client *http.Client) *http.Client {
+	return RateLimitedClient(client, nil, nil)
+}
+
+type rateLimitTransport struct {
+	limiter   *RateLimiter //nil for UnlimitedClient
+	classify  EndpointClassifier
+	transport http.RoundTripper
+}
+
+func (t *rateLimitTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	if t.limiter == nil {
+		return t.transport.RoundTrip(req)
+	}
+	class, weight := t.classify(req)
+	if err := t.limiter.Wait(req.Context(), class, weight); err != nil {
+		return nil, err
+	}
+
+	resp, err := t.transport.RoundTrip(req)
+	if err == nil && (resp.StatusCode == 418 || resp.StatusCode == 429) {
+		retryAfter, _ := strconv.Atoi(resp.Header.Get("Retry-After"))
+		t.limiter.Pause(class, time.Duration(retryAfter)*time.Second)
+	}
+	return resp, err
+}
@@ -0,0 +1,150 @@
+package goex
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRateLimiter_Wait(t *testing.T) {
+	rl := NewRateLimiter(
+		RateLimit{Classes: []EndpointClass{ENDPOINT_PUBLIC, ENDPOINT_ORDER}, Limit: 10, Interval: time.Second},
+		RateLimit{Classes: []EndpointClass{ENDPOINT_ORDER}, Limit: 2, Interval: time.Second})
+	ctx := context.Background()
+
+	start := time.Now()
+	assert.Nil(t, rl.Wait(ctx, ENDPOINT_ORDER, 1))
+	assert.Nil(t, rl.Wait(ctx, ENDPOINT_ORDER, 1))
+	assert.Nil(t, rl.Wait(ctx, ENDPOINT_PUBLIC, 7))
+	assert.True(t, time.Since(start) < 50*time.Millisecond)
+
+	//the order bucket is empty, half a second brings back one order
+	assert.Nil(t, rl.Wait(ctx, ENDPOINT_ORDER, 1))
+	assert.InDelta(t, 500, time.Since(start).Seconds()*1000, 100)
+
+	//private requests are not limited at all
+	assert.Nil(t, rl.Wait(ctx, ENDPOINT_PRIVATE, 1000))
+}
+
+func TestRateLimiter_WaitConcurrent(t *testing.T) {
+	rl := NewRateLimiter(RateLimit{Classes: []EndpointClass{ENDPOINT_PUBLIC}, Limit: 20, Interval: time.Second})
+	start := time.Now()
+	var wg sync.WaitGroup
+	for i := 0; i < 30; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			rl.Wait(context.Background(), ENDPOINT_PUBLIC, 1)
+		}()
+	}
+	wg.Wait()
+	//20 at once, the other 10 at 20 a second
+	assert.InDelta(t, 500, time.Since(start).Seconds()*1000, 100)
+}
+
+func TestRateLimiter_WaitCancel(t *testing.T) {
+	rl := NewRateLimiter(RateLimit{Classes: []EndpointClass{ENDPOINT_PRIVATE}, Limit: 1, Interval: time.Second})
+	assert.Nil(t, rl.Wait(context.Background(), ENDPOINT_PRIVATE, 1))
+
+	ctx, cancel := context.WithTimeout(context.Background(), 20*time.Millisecond)
+	defer cancel()
+	assert.Equal(t, context.DeadlineExceeded, rl.Wait(ctx, ENDPOINT_PRIVATE, 1))
+
+	//the cancelled wait gave its reservation back
+	start := time.Now()
+	assert.Nil(t, rl.Wait(context.Background(), ENDPOINT_PRIVATE, 1))
+	assert.True(t, time.Since(start) < 990*time.Millisecond)
+}
+
+func TestClassifyEndpoint(t *testing.T) {
+	for _, c := range []struct {
+		method, url string
+		header      string
+		class       EndpointClass
+	}{
+		{"GET", "https://www.okex.com/api/v1/ticker.do?symbol=btc_usdt", "", ENDPOINT_PUBLIC},
+		{"GET", "https://api.huobi.pro/v1/order/orders?AccessKeyId=k&Signature=s", "", ENDPOINT_PRIVATE},
+		{"GET", "https://api.bitfinex.com/v1/balances", "X-Bfx-Apikey", ENDPOINT_PRIVATE},
+		{"POST", "https://www.okex.com/api/v1/userinfo.do", "", ENDPOINT_PRIVATE},
+		{"POST", "https://www.okex.com/api/v1/trade.do", "", ENDPOINT_ORDER},
+		{"POST", "https://api.huobi.pro/v1/order/orders/123/submitcancel", "", ENDPOINT_ORDER},
+		{"DELETE", "https://api.binance.com/api/v3/order", "", ENDPOINT_ORDER},
+	} {
+		req, _ := http.NewRequest(c.method, c.url, nil)
+		if c.header != "" {
+			req.Header.Set(c.header, "key")
+		}
+		class, weight := ClassifyEndpoint(req)
+		assert.Equal(t, c.class, class, c.url)
+		assert.Equal(t, 1, weight)
+	}
+}
+
+func TestRateLimitedClient(t *testing.T) {
+	requests := 0
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests++
+		if requests == 1 {
+			w.Header().Set("Retry-After", "1")
+			w.WriteHeader(429)
+			return
+		}
+		w.Write([]byte(`{}`))
+	}))
+	defer srv.Close()
+
+	rl := NewRateLimiter(RateLimit{Classes: []EndpointClass{ENDPOINT_PUBLIC}, Limit: 100, Interval: time.Second})
+	client := RateLimitedClient(http.DefaultClient, rl, ClassifyEndpoint)
+
+	_, err := HttpGet(client, srv.URL)
+	assert.True(t, EX_ERR_API_LIMIT.Is(err))
+
+	//the 429 emptied the bucket for the second it asked for
+	start := time.Now()
+	_, err = HttpGet(client, srv.URL)
+	assert.Nil(t, err)
+	assert.True(t, time.Since(start) > 900*time.Millisecond)
+
+	//a cancelled context ends the wait
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	rl.Pause(ENDPOINT_PUBLIC, time.Second)
+	_, err = HttpGet(HttpClientWithContext(ctx, client), srv.URL)
+	assert.NotNil(t, err)
+	assert.Equal(t, 2, requests)
+}
+
+func TestExchangeRateLimitedClient(t *testing.T) {
+	config := RateLimitConfig{
+		Limits:   []RateLimit{{Classes: []EndpointClass{ENDPOINT_PUBLIC}, Limit: 1, Interval: time.Second}},
+		Classify: ClassifyEndpoint}
+	assert.True(t, SharedRateLimiter("test.com", "k1", config) == SharedRateLimiter("test.com", "k1", config))
+	assert.True(t, SharedRateLimiter("test.com", "k1", config) != SharedRateLimiter("test.com", "k2", config))
+
+	client := ExchangeRateLimitedClient(http.DefaultClient, "test.com", "k1", config)
+	assert.True(t, client != http.DefaultClient)
+	assert.True(t, client == ExchangeRateLimitedClient(client, "test.com", "k1", config))
+	unlimited := UnlimitedClient(http.DefaultClient)
+	assert.True(t, unlimited == ExchangeRateLimitedClient(unlimited, "test.com", "k1", config))
+
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(`{}`))
+	}))
+	defer srv.Close()
+
+	//two clients of the same exchange and key wait for each other, an unlimited one does not wait
+	start := time.Now()
+	_, err := HttpGet(client, srv.URL)
+	assert.Nil(t, err)
+	_, err = HttpGet(unlimited, srv.URL)
+	assert.Nil(t, err)
+	assert.True(t, time.Since(start) < 500*time.Millisecond)
+	_, err = HttpGet(ExchangeRateLimitedClient(http.DefaultClient, "test.com", "k1", config), srv.URL)
+	assert.Nil(t, err)
+	assert.True(t, time.Since(start) > 900*time.Millisecond)
+}
@@ -0,0 +1,138 @@
+package goex
+
+import (
+	"context"
+	"errors"
+	"io"
+	"math"
+	"math/rand"
+	"net"
+	"net/url"
+	"strings"
+	"time"
+)
+
+//RetryPolicy retries a failed call with exponential backoff and jitter. Reads are retried on any
+//error IsRetryableError accepts, writes (placing and cancelling orders) only on errors proving the
+//exchange never executed them, so an order is never placed twice. It never panics, the last error is returned.
+type RetryPolicy struct {
+	MaxAttempts     int           //including the first call, 0 leaves the limit to MaxElapsedTime
+	InitialInterval time.Duration //wait before the first retry
+	MaxInterval     time.Duration //the wait stops growing here
+	Multiplier      float64       //growth of the wait per attempt
+	Jitter          float64       //0.5 makes every wait a random one between 50% and 150% of the interval
+	MaxElapsedTime  time.Duration //no retry starts after this, 0 no limit
+
+	Retryable      func(err error) bool //for reads, nil is IsRetryableError
+	RetryableWrite func(err error) bool //for writes, nil is IsNotExecutedError
+
+	OnRetry func(attempt int, err error, wait time.Duration) //called before each wait when not nil
+}
+
+var DefaultRetryPolicy = RetryPolicy{
+	MaxAttempts:     5,
+	InitialInterval: 200 * time.Millisecond,
+	MaxInterval:     5 * time.Second,
+	Multiplier:      2,
+	Jitter:          0.5,
+	MaxElapsedTime:  30 * time.Second}
+
+//Do calls the read op until it succeeds, fails with an error not worth retrying or the policy gives up
+func (p RetryPolicy) Do(ctx context.Context, op func() error) error {
+	retryable := p.Retryable
+	if retryable == nil {
+		retryable = IsRetryableError
+	}
+	return p.do(ctx, retryable, op)
+}
+
+//DoWrite is Do for calls that must not be executed twice
+func (p RetryPolicy) DoWrite(ctx context.Context, op func() error) error {
+	retryable := p.RetryableWrite
+	if retryable == nil {
+		retryable = IsNotExecutedError
+	}
+	return p.do(ctx, retryable, op)
+}
+
+func (p RetryPolicy) do(ctx context.Context, retryable func(error) bool, op func() error) error {
+	start := time.Now()
+	for attempt := 1; ; attempt++ {
+		err := op()
+		if err == nil || !retryable(err) {
+			return err
+		}
+		if p.MaxAttempts > 0 && attempt >= p.MaxAttempts {
+			return err
+		}
+		wait := p.backoff(attempt)
+		if p.MaxElapsedTime > 0 && time.Since(start)+wait > p.MaxElapsedTime {
+			return err
+		}
+
+		if p.OnRetry != nil {
+			p.OnRetry(attempt, err, wait)
+		}
+		timer := time.NewTimer(wait)
+		select {
+		case <-timer.C:
+		case <-ctx.Done():
+			timer.Stop()
+			return err
+		}
+	}
+}
+
+//backoff is the wait after the attempt-th call failed
+func (p RetryPolicy) backoff(attempt int) time.Duration {
+	interval := float64(p.InitialInterval) * math.Pow(math.Max(p.Multiplier, 1), float64(attempt-1))
+	if p.MaxInterval > 0 && interval > float64(p.MaxInterval) {
+		interval = float64(p.MaxInterval)
+	}
+	if p.Jitter > 0 {
+		interval *= 1 + p.Jitter*(2*rand.Float64()-1)
+	}
+	return time.Duration(interval)
+}
+
+//IsRetryableError tells whether a read failed for a passing reason: the rate limit, maintenance,
+//a server error or the network. Errors of the request itself, as a bad signature or pair, are final.
+func IsRetryableError(err error) bool {
+	if IsNotExecutedError(err) {
+		return true
+	}
+
+	var apiErr ApiError
+	if errors.As(err, &apiErr) {
+		switch {
+		case EX_ERR_MAINTENANCE.Is(apiErr):
+			return true
+		case HTTP_ERR_CODE.Is(apiErr):
+			return strings.HasSuffix(apiErr.ErrMsg, "HttpStatusCode:500") || strings.HasSuffix(apiErr.ErrMsg, "HttpStatusCode:504")
+		}
+		return false
+	}
+
+	if errors.Is(err, context.Canceled) || errors.Is(err, context.DeadlineExceeded) {
+		return false
+	}
+	var netErr net.Error
+	return errors.As(err, &netErr) || errors.Is(err, io.EOF) || errors.Is(err, io.ErrUnexpectedEOF)
+}
+
+//IsNotExecutedError tells whether the exchange certainly refused a request without executing it:
+//the rate limit was hit, the nonce was stale or the connection could not even be made.
+//Only these make resending an order safe.
+func IsNotExecutedError(err error) bool {
+	var apiErr ApiError
+	if errors.As(err, &apiErr) {
+		return apiErr.Is(EX_ERR_API_LIMIT) || apiErr.Is(EX_ERR_NONCE)
+	}
+
+	var urlErr *url.Error
+	if errors.As(err, &urlErr) {
+		var opErr *net.OpError
+		return errors.As(urlErr.Err, &opErr) && opErr.Op == "dial"
+	}
+	return false
+}
@@ -0,0 +1,241 @@
+package goex
+
+import "context"
+
+//RetryAPI wraps an API and retries its failed calls with Policy,
+//orders and cancels only when the exchange certainly did not execute them
+type RetryAPI struct {
+	API
+	Policy RetryPolicy
+}
+
+func NewRetryAPI(api API, policy RetryPolicy) *RetryAPI {
+	return &RetryAPI{API: api, Policy: policy}
+}
+
+func (r *RetryAPI) placeOrder(place func(amount, price Decimal, currency CurrencyPair) (*Order, error), amount, price Decimal, currency CurrencyPair) (*Order, error) {
+	var order *Order
+	err := r.Policy.DoWrite(context.Background(), func() (err error) {
+		order, err = place(amount, price, currency)
+		return err
+	})
+	return order, err
+}
+
+func (r *RetryAPI) LimitBuy(amount, price Decimal, currency CurrencyPair) (*Order, error) {
+	return r.placeOrder(r.API.LimitBuy, amount, price, currency)
+}
+
+func (r *RetryAPI) LimitSell(amount, price Decimal, currency CurrencyPair) (*Order, error) {
+	return r.placeOrder(r.API.LimitSell, amount, price, currency)
+}
+
+func (r *RetryAPI) MarketBuy(amount, price Decimal, currency CurrencyPair) (*Order, error) {
+	return r.placeOrder(r.API.MarketBuy, amount, price, currency)
+}
+
+func (r *RetryAPI) MarketSell(amount, price Decimal, currency CurrencyPair) (*Order, error) {
+	return r.placeOrder(r.API.MarketSell, amount, price, currency)
+}
+
+func (r *RetryAPI) CancelOrder(orderId string, currency CurrencyPair) (bool, error) {
+	var ok bool
+	err := r.Policy.DoWrite(context.Background(), func() (err error) {
+		ok, err = r.API.CancelOrder(orderId, currency)
+		return err
+	})
+	return ok, err
+}
+
+func (r *RetryAPI) GetOneOrder(orderId string, currency CurrencyPair) (*Order, error) {
+	var order *Order
+	err := r.Policy.Do(context.Background(), func() (err error) {
+		order, err = r.API.GetOneOrder(orderId, currency)
+		return err
+	})
+	return order, err
+}
+
+func (r *RetryAPI) GetUnfinishOrders(currency CurrencyPair) ([]Order, error) {
+	var orders []Order
+	err := r.Policy.Do(context.Background(), func() (err error) {
+		orders, err = r.API.GetUnfinishOrders(currency)
+		return err
+	})
+	return orders, err
+}
+
+func (r *RetryAPI) GetOrderHistorys(currency CurrencyPair, currentPage, pageSize int) ([]Order, error) {
+	var orders []Order
+	err := r.Policy.Do(context.Background(), func() (err error) {
+		orders, err = r.API.GetOrderHistorys(currency, currentPage, pageSize)
+		return err
+	})
+	return orders, err
+}
+
+func (r *RetryAPI) GetAccount() (*Account, error) {
+	var account *Account
+	err := r.Policy.Do(context.Background(), func() (err error) {
+		account, err = r.API.GetAccount()
+		return err
+	})
+	return account, err
+}
+
+func (r *RetryAPI) GetTicker(currency CurrencyPair) (*Ticker, error) {
+	var ticker *Ticker
+	err := r.Policy.Do(context.Background(), func() (err error) {
+		ticker, err = r.API.GetTicker(currency)
+		return err
+	})
+	return ticker, err
+}
+
+func (r *RetryAPI) GetDepth(size int, currency CurrencyPair) (*Depth, error) {
+	var depth *Depth
+	err := r.Policy.Do(context.Background(), func() (err error) {
+		depth, err = r.API.GetDepth(size, currency)
+		return err
+	})
+	return depth, err
+}
+
+func (r *RetryAPI) GetKlineRecords(currency CurrencyPair, period, size, since int) ([]Kline, error) {
+	var klines []Kline
+	err := r.Policy.Do(context.Background(), func() (err error) {
+		klines, err = r.API.GetKlineRecords(currency, period, size, since)
+		return err
+	})
+	return klines, err
+}
+
+func (r *RetryAPI) GetTrades(currencyPair CurrencyPair, since int64) ([]Trade, error) {
+	var trades []Trade
+	err := r.Policy.Do(context.Background(), func() (err error) {
+		trades, err = r.API.GetTrades(currencyPair, since)
+		return err
+	})
+	return trades, err
+}
+
+//RetryFutureAPI is RetryAPI for a FutureRestAPI
+type RetryFutureAPI struct {
+	FutureRestAPI
+	Policy RetryPolicy
+}
+
+func NewRetryFutureAPI(api FutureRestAPI, policy RetryPolicy) *RetryFutureAPI {
+	return &RetryFutureAPI{FutureRestAPI: api, Policy: policy}
+}
+
+//readFloat retries one of the calls returning a float64
+func (r *RetryFutureAPI) readFloat(read func() (float64, error)) (float64, error) {
+	var v float64
+	err := r.Policy.Do(context.Background(), func() (err error) {
+		v, err = read()
+		return err
+	})
+	return v, err
+}
+
+func (r *RetryFutureAPI) GetFutureEstimatedPrice(currencyPair CurrencyPair) (float64, error) {
+	return r.readFloat(func() (float64, error) { return r.FutureRestAPI.GetFutureEstimatedPrice(currencyPair) })
+}
+
+func (r *RetryFutureAPI) GetFutureTicker(currencyPair CurrencyPair, contractType string) (*Ticker, error) {
+	var ticker *Ticker
+	err := r.Policy.Do(context.Background(), func() (err error) {
+		ticker, err = r.FutureRestAPI.GetFutureTicker(currencyPair, contractType)
+		return err
+	})
+	return ticker, err
+}
+
+func (r *RetryFutureAPI) GetFutureDepth(currencyPair CurrencyPair, contractType string, size int) (*Depth, error) {
+	var depth *Depth
+	err := r.Policy.Do(context.Background(), func() (err error) {
+		depth, err = r.FutureRestAPI.GetFutureDepth(currencyPair, contractType, size)
+		return err
+	})
+	return depth, err
+}
+
+func (r *RetryFutureAPI) GetFutureIndex(currencyPair CurrencyPair) (float64, error) {
+	return r.readFloat(func() (float64, error) { return r.FutureRestAPI.GetFutureIndex(currencyPair) })
+}
+
+func (r *RetryFutureAPI) GetFutureUserinfo() (*FutureAccount, error) {
+	var account *FutureAccount
+	err := r.Policy.Do(context.Background(), func() (err error) {
+		account, err = r.FutureRestAPI.GetFutureUserinfo()
+		return err
+	})
+	return account, err
+}
+
+func (r *RetryFutureAPI) PlaceFutureOrder(currencyPair CurrencyPair, contractType, price, amount string, openType, matchPrice, leverRate int) (string, error) {
+	var orderId string
+	err := r.Policy.DoWrite(context.Background(), func() (err error) {
+		orderId, err = r.FutureRestAPI.PlaceFutureOrder(currencyPair, contractType, price, amount, openType, matchPrice, leverRate)
+		return err
+	})
+	return orderId, err
+}
+
+func (r *RetryFutureAPI) FutureCancelOrder(currencyPair CurrencyPair, contractType, orderId string) (bool, error) {
+	var ok bool
+	err := r.Policy.DoWrite(context.Background(), func() (err error) {
+		ok, err = r.FutureRestAPI.FutureCancelOrder(currencyPair, contractType, orderId)
+		return err
+	})
+	return ok, err
+}
+
+func (r *RetryFutureAPI) GetFuturePosition(currencyPair CurrencyPair, contractType string) ([]FuturePosition, error) {
+	var positions []FuturePosition
+	err := r.Policy.Do(context.Background(), func() (err error) {
+		positions, err = r.FutureRestAPI.GetFuturePosition(currencyPair, contractType)
+		return err
+	})
+	return positions, err
+}
+
+func (r *RetryFutureAPI) GetFutureOrders(orderIds []string, currencyPair CurrencyPair, contractType string) ([]FutureOrder, error) {
+	var orders []FutureOrder
+	err := r.Policy.Do(context.Background(), func() (err error) {
+		orders, err = r.FutureRestAPI.GetFutureOrders(orderIds, currencyPair, contractType)
+		return err
+	})
+	return orders, err
+}
+
+func (r *RetryFutureAPI) GetUnfinishFutureOrders(currencyPair CurrencyPair, contractType string) ([]FutureOrder, error) {
+	var orders []FutureOrder
+	err := r.Policy.Do(context.Background(), func() (err error) {
+		orders, err = r.FutureRestAPI.GetUnfinishFutureOrders(currencyPair, contractType)
+		return err
+	})
+	return orders, err
+}
+
+func (r *RetryFutureAPI) GetFee() (float64, error) {
+	return r.readFloat(r.FutureRestAPI.GetFee)
+}
+
+func (r *RetryFutureAPI) GetExchangeRate() (float64, error) {
+	return r.readFloat(r.FutureRestAPI.GetExchangeRate)
+}
+
+func (r *RetryFutureAPI) GetContractValue(currencyPair CurrencyPair) (float64, error) {
+	return r.readFloat(func() (float64, error) { return r.FutureRestAPI.GetContractValue(currencyPair) })
+}
+
+func (r *RetryFutureAPI) GetKlineRecords(contract_type string, currency CurrencyPair, period string, size, since int) ([]FutureKline, error) {
+	var klines []FutureKline
+	err := r.Policy.Do(context.Background(), func() (err error) {
+		klines, err = r.FutureRestAPI.GetKlineRecords(contract_type, currency, period, size, since)
+		return err
+	})
+	return klines, err
+}
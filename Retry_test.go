@@ -0,0 +1,155 @@
+package goex
+
+import (
+	"context"
+	"errors"
+	"net"
+	"net/http"
+	"net/url"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+var fastRetryPolicy = RetryPolicy{MaxAttempts: 4, InitialInterval: time.Millisecond, Multiplier: 2}
+
+//flakyAPI fails each call with the next of errs, then succeeds
+type flakyAPI struct {
+	API
+	errs  []error
+	calls int
+}
+
+func (f *flakyAPI) next() error {
+	f.calls++
+	if len(f.errs) == 0 {
+		return nil
+	}
+	err := f.errs[0]
+	f.errs = f.errs[1:]
+	return err
+}
+
+func (f *flakyAPI) GetTicker(currency CurrencyPair) (*Ticker, error) {
+	if err := f.next(); err != nil {
+		return nil, err
+	}
+	return &Ticker{Last: RequireDecimal("6100")}, nil
+}
+
+func (f *flakyAPI) LimitBuy(amount, price Decimal, currency CurrencyPair) (*Order, error) {
+	if err := f.next(); err != nil {
+		return nil, err
+	}
+	return &Order{Amount: amount, Price: price, Currency: currency, Side: BUY}, nil
+}
+
+func TestRetryPolicy_backoff(t *testing.T) {
+	p := RetryPolicy{InitialInterval: 100 * time.Millisecond, MaxInterval: time.Second, Multiplier: 2}
+	assert.Equal(t, 100*time.Millisecond, p.backoff(1))
+	assert.Equal(t, 400*time.Millisecond, p.backoff(3))
+	assert.Equal(t, time.Second, p.backoff(10))
+
+	p.Jitter = 0.5
+	for i := 0; i < 100; i++ {
+		wait := p.backoff(2)
+		assert.True(t, wait >= 100*time.Millisecond && wait <= 300*time.Millisecond, wait.String())
+	}
+}
+
+func TestRetryPolicy_Do(t *testing.T) {
+	calls := 0
+	err := fastRetryPolicy.Do(context.Background(), func() error {
+		calls++
+		return EX_ERR_MAINTENANCE
+	})
+	assert.Equal(t, EX_ERR_MAINTENANCE, err)
+	assert.Equal(t, 4, calls)
+
+	calls = 0
+	err = fastRetryPolicy.Do(context.Background(), func() error {
+		calls++
+		return EX_ERR_SIGN
+	})
+	assert.Equal(t, EX_ERR_SIGN, err)
+	assert.Equal(t, 1, calls)
+
+	//MaxElapsedTime stops before MaxAttempts
+	p := RetryPolicy{InitialInterval: 30 * time.Millisecond, MaxElapsedTime: 50 * time.Millisecond}
+	calls = 0
+	p.Do(context.Background(), func() error {
+		calls++
+		return EX_ERR_API_LIMIT
+	})
+	assert.Equal(t, 2, calls)
+
+	//a cancelled context ends the waiting
+	ctx, cancel := context.WithCancel(context.Background())
+	p = RetryPolicy{InitialInterval: time.Hour, OnRetry: func(attempt int, err error, wait time.Duration) { cancel() }}
+	assert.Equal(t, EX_ERR_API_LIMIT, p.Do(ctx, func() error { return EX_ERR_API_LIMIT }))
+}
+
+func TestIsRetryableError(t *testing.T) {
+	dialErr := &url.Error{Op: "Get", URL: "https://api.binance.com", Err: &net.OpError{Op: "dial", Err: errors.New("connection refused")}}
+	readErr := &url.Error{Op: "Post", URL: "https://api.binance.com", Err: &net.OpError{Op: "read", Err: errors.New("connection reset by peer")}}
+	serverErr := HTTP_ERR_CODE
+	serverErr.ErrMsg = serverErr.ErrMsg + ", HttpStatusCode:504"
+	badRequest := HTTP_ERR_CODE
+	badRequest.ErrMsg = badRequest.ErrMsg + ", HttpStatusCode:400"
+
+	for _, c := range []struct {
+		err         error
+		read, write bool
+	}{
+		{EX_ERR_API_LIMIT, true, true},
+		{EX_ERR_NONCE, true, true},
+		{dialErr, true, true},
+		{EX_ERR_MAINTENANCE, true, false},
+		{serverErr, true, false},
+		{readErr, true, false},
+		{badRequest, false, false},
+		{EX_ERR_INSUFFICIENT_BALANCE, false, false},
+		{ErrNotSupported, false, false},
+		{&url.Error{Op: "Get", URL: "https://api.binance.com", Err: context.Canceled}, false, false},
+		{errors.New("invalid character '<' looking for beginning of value"), false, false},
+	} {
+		assert.Equal(t, c.read, IsRetryableError(c.err), c.err.Error())
+		assert.Equal(t, c.write, IsNotExecutedError(c.err), c.err.Error())
+	}
+}
+
+func TestRetryAPI(t *testing.T) {
+	flaky := &flakyAPI{errs: []error{EX_ERR_MAINTENANCE, EX_ERR_API_LIMIT}}
+	api := NewRetryAPI(flaky, fastRetryPolicy)
+	ticker, err := api.GetTicker(BTC_USDT)
+	assert.Nil(t, err)
+	assert.Equal(t, RequireDecimal("6100"), ticker.Last)
+	assert.Equal(t, 3, flaky.calls)
+
+	//the order may have been placed before the 503, it is not sent again
+	flaky = &flakyAPI{errs: []error{EX_ERR_MAINTENANCE}}
+	api = NewRetryAPI(flaky, fastRetryPolicy)
+	_, err = api.LimitBuy(RequireDecimal("1"), RequireDecimal("6100"), BTC_USDT)
+	assert.Equal(t, EX_ERR_MAINTENANCE, err)
+	assert.Equal(t, 1, flaky.calls)
+
+	//but a rejection by the rate limit is safe to resend
+	flaky = &flakyAPI{errs: []error{EX_ERR_API_LIMIT}}
+	api = NewRetryAPI(flaky, fastRetryPolicy)
+	order, err := api.LimitBuy(RequireDecimal("1"), RequireDecimal("6100"), BTC_USDT)
+	assert.Nil(t, err)
+	assert.Equal(t, RequireDecimal("1"), order.Amount)
+	assert.Equal(t, 2, flaky.calls)
+}
+
+func TestRetryPolicy_DoRealDialError(t *testing.T) {
+	calls := 0
+	err := fastRetryPolicy.DoWrite(context.Background(), func() error {
+		calls++
+		_, err := NewHttpRequest(http.DefaultClient, "POST", "http://127.0.0.1:1/order", "", nil)
+		return err
+	})
+	assert.NotNil(t, err)
+	assert.Equal(t, 4, calls)
+}
@@ -0,0 +1,16 @@
+package goex
+
+//StreamAPI pushes market data over a websocket.
+//Each Subscribe call returns a channel that is fed until Close is called;
+//subscribing to the same stream twice returns the same channel.
+//Dropped connections are redialed and all subscriptions restored.
+type StreamAPI interface {
+	SubscribeTicker(pair CurrencyPair) (<-chan Ticker, error)
+	SubscribeDepth(pair CurrencyPair, size int) (<-chan Depth, error)
+	SubscribeTrades(pair CurrencyPair) (<-chan Trade, error)
+	//period: KLINE_PERIOD_1MIN, KLINE_PERIOD_5MIN ...
+	SubscribeKline(pair CurrencyPair, period int) (<-chan Kline, error)
+
+	GetExchangeName() string
+	Close() error
+}
@@ -0,0 +1,144 @@
+package goex
+
+import (
+	"fmt"
+	"sync"
+	"time"
+)
+
+//SymbolInfo holds the trading rules of one pair. A zero field means the exchange sets no such rule.
+type SymbolInfo struct {
+	Pair   CurrencyPair
+	Symbol string //the pair as the exchange spells it
+
+	PricePrecision  int32   //decimal places of a price
+	AmountPrecision int32   //decimal places of an amount
+	TickSize        Decimal //prices are a multiple of TickSize
+	StepSize        Decimal //amounts are a multiple of StepSize
+	PriceDigits     int32   //significant digits of a price, bitfinex limits prices this way
+
+	MinAmount,
+	MaxAmount Decimal
+	MinPrice,
+	MaxPrice Decimal
+	MinNotional Decimal //smallest price * amount
+}
+
+//MarketAPI is implemented by the adapters that know the exchange's trading rules,
+//type assert an API to get it: if m, isok := api.(MarketAPI); isok { ... }
+type MarketAPI interface {
+	GetMarkets() ([]SymbolInfo, error)
+	//EX_ERR_INVALID_CURRENCY_PAIR when the exchange does not list pair
+	GetSymbolInfo(pair CurrencyPair) (*SymbolInfo, error)
+}
+
+//RoundPrice moves price onto the tick grid and to at most PriceDigits significant digits,
+//towards the safe side: buys round down, sells round up
+func (s *SymbolInfo) RoundPrice(price Decimal, side TradeSide) Decimal {
+	up := side == SELL || side == SELL_MARKET
+	if s.PriceDigits > 0 && price.Digits() > s.PriceDigits {
+		//the unit of the last digit kept
+		price = price.roundTo(NewDecimal(1, price.exp+price.Digits()-s.PriceDigits), up)
+	}
+	if s.TickSize.Sign() > 0 {
+		price = price.roundTo(s.TickSize, up)
+	}
+	return price
+}
+
+//RoundAmount cuts amount down to the step grid, never ordering more than asked for
+func (s *SymbolInfo) RoundAmount(amount Decimal) Decimal {
+	if s.StepSize.Sign() > 0 {
+		return amount.FloorTo(s.StepSize)
+	}
+	return amount
+}
+
+//Conform rounds a proposed order with RoundAmount and RoundPrice and checks it against the limits.
+//The error is EX_ERR_INVALID_ORDER_SIZE when the rounded order is still out of range.
+//A market order passes a zero price, which skips the price and notional checks.
+func (s *SymbolInfo) Conform(side TradeSide, amount, price Decimal) (Decimal, Decimal, error) {
+	amount = s.RoundAmount(amount)
+	if !price.IsZero() {
+		price = s.RoundPrice(price, side)
+	}
+	return amount, price, s.checkLimits(amount, price)
+}
+
+//Validate is Conform without the rounding: an amount off the step grid or a price off the tick grid
+//is EX_ERR_INVALID_PRECISION, an order out of range EX_ERR_INVALID_ORDER_SIZE.
+func (s *SymbolInfo) Validate(side TradeSide, amount, price Decimal) error {
+	var violation string
+	switch {
+	case s.RoundAmount(amount) != amount:
+		violation = fmt.Sprintf("amount %s is not a multiple of %s", amount, s.StepSize)
+	case !price.IsZero() && s.RoundPrice(price, side) != price:
+		violation = fmt.Sprintf("price %s is not a multiple of %s or has more than %d digits", price, s.TickSize, s.PriceDigits)
+	}
+	if violation != "" {
+		errCode := EX_ERR_INVALID_PRECISION
+		errCode.OriginErrMsg = s.Pair.String() + " " + violation
+		return errCode
+	}
+	return s.checkLimits(amount, price)
+}
+
+func (s *SymbolInfo) checkLimits(amount, price Decimal) error {
+	var violation string
+	switch {
+	case amount.Sign() <= 0:
+		violation = fmt.Sprintf("amount %s is not positive", amount)
+	case s.MinAmount.Sign() > 0 && amount.LessThan(s.MinAmount):
+		violation = fmt.Sprintf("amount %s below the minimum %s", amount, s.MinAmount)
+	case s.MaxAmount.Sign() > 0 && amount.GreaterThan(s.MaxAmount):
+		violation = fmt.Sprintf("amount %s above the maximum %s", amount, s.MaxAmount)
+	case price.IsZero():
+	case s.MinPrice.Sign() > 0 && price.LessThan(s.MinPrice):
+		violation = fmt.Sprintf("price %s below the minimum %s", price, s.MinPrice)
+	case s.MaxPrice.Sign() > 0 && price.GreaterThan(s.MaxPrice):
+		violation = fmt.Sprintf("price %s above the maximum %s", price, s.MaxPrice)
+	case s.MinNotional.Sign() > 0 && amount.Mul(price).LessThan(s.MinNotional):
+		violation = fmt.Sprintf("value %s below the minimum %s", amount.Mul(price), s.MinNotional)
+	}
+	if violation != "" {
+		errCode := EX_ERR_INVALID_ORDER_SIZE
+		errCode.OriginErrMsg = s.Pair.String() + " " + violation
+		return errCode
+	}
+	return nil
+}
+
+const symbolInfoCacheTTL = time.Hour
+
+//SymbolInfoCache keeps the result of GetMarkets for an hour, so adapters can answer GetSymbolInfo
+//without asking the exchange before every order. The zero value is ready to use.
+type SymbolInfoCache struct {
+	l         sync.Mutex
+	symbols   map[CurrencyPair]SymbolInfo
+	fetchedAt time.Time
+}
+
+func (c *SymbolInfoCache) Get(pair CurrencyPair, fetch func() ([]SymbolInfo, error)) (*SymbolInfo, error) {
+	c.l.Lock()
+	defer c.l.Unlock()
+
+	if c.symbols == nil || time.Since(c.fetchedAt) > symbolInfoCacheTTL {
+		markets, err := fetch()
+		if err != nil {
+			return nil, err
+		}
+		c.symbols = make(map[CurrencyPair]SymbolInfo, len(markets))
+		for _, m := range markets {
+			c.symbols[m.Pair] = m
+		}
+		c.fetchedAt = time.Now()
+	}
+
+	info, isok := c.symbols[pair]
+	if !isok {
+		errCode := EX_ERR_INVALID_CURRENCY_PAIR
+		errCode.OriginErrMsg = pair.String()
+		return nil, errCode
+	}
+	return &info, nil
+}
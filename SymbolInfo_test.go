@@ -0,0 +1,82 @@
+package goex
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+var btcUsdtInfo = SymbolInfo{
+	Pair:        BTC_USDT,
+	TickSize:    RequireDecimal("0.01"),
+	StepSize:    RequireDecimal("0.000001"),
+	MinAmount:   RequireDecimal("0.0001"),
+	MaxAmount:   RequireDecimal("100"),
+	MinPrice:    RequireDecimal("0.01"),
+	MaxPrice:    RequireDecimal("1000000"),
+	MinNotional: RequireDecimal("10")}
+
+func TestSymbolInfo_RoundPrice(t *testing.T) {
+	assert.Equal(t, "6100.12", btcUsdtInfo.RoundPrice(RequireDecimal("6100.129"), BUY).String())
+	assert.Equal(t, "6100.13", btcUsdtInfo.RoundPrice(RequireDecimal("6100.121"), SELL).String())
+	assert.Equal(t, "6100.1", btcUsdtInfo.RoundPrice(RequireDecimal("6100.1"), SELL).String())
+
+	//bitfinex: 5 significant digits
+	digits := SymbolInfo{PriceDigits: 5, TickSize: RequireDecimal("0.00000001")}
+	assert.Equal(t, "6100.1", digits.RoundPrice(RequireDecimal("6100.129"), BUY).String())
+	assert.Equal(t, "6100.2", digits.RoundPrice(RequireDecimal("6100.121"), SELL).String())
+	assert.Equal(t, "0.00012346", digits.RoundPrice(RequireDecimal("0.000123456"), SELL).String())
+	assert.Equal(t, "61001", digits.RoundPrice(RequireDecimal("61001.9"), BUY).String())
+}
+
+func TestSymbolInfo_RoundAmount(t *testing.T) {
+	assert.Equal(t, "0.123456", btcUsdtInfo.RoundAmount(RequireDecimal("0.1234569")).String())
+	assert.Equal(t, "1.5", btcUsdtInfo.RoundAmount(RequireDecimal("1.5")).String())
+	assert.Equal(t, "1.5", (&SymbolInfo{}).RoundAmount(RequireDecimal("1.5")).String())
+
+	lot := SymbolInfo{StepSize: RequireDecimal("5")}
+	assert.Equal(t, "15", lot.RoundAmount(RequireDecimal("19.9")).String())
+}
+
+func TestSymbolInfo_Conform(t *testing.T) {
+	amount, price, err := btcUsdtInfo.Conform(BUY, RequireDecimal("0.0123456789"), RequireDecimal("6100.129"))
+	assert.Nil(t, err)
+	assert.Equal(t, "0.012345", amount.String())
+	assert.Equal(t, "6100.12", price.String())
+
+	amount, price, err = btcUsdtInfo.Conform(SELL_MARKET, RequireDecimal("0.5"), Decimal{})
+	assert.Nil(t, err)
+	assert.True(t, price.IsZero())
+
+	for _, order := range [][2]string{
+		{"0.0000001", "6100"}, //rounds to nothing
+		{"0.00005", "6100"},   //below MinAmount
+		{"101", "6100"},       //above MaxAmount
+		{"1", "2000000"},      //above MaxPrice
+		{"0.001", "6100"},     //6.1 below MinNotional
+	} {
+		_, _, err = btcUsdtInfo.Conform(BUY, RequireDecimal(order[0]), RequireDecimal(order[1]))
+		assert.True(t, EX_ERR_INVALID_ORDER_SIZE.Is(err), order[0]+"@"+order[1])
+	}
+}
+
+func TestSymbolInfoCache_Get(t *testing.T) {
+	var cache SymbolInfoCache
+	fetches := 0
+	fetch := func() ([]SymbolInfo, error) {
+		fetches++
+		return []SymbolInfo{btcUsdtInfo}, nil
+	}
+
+	info, err := cache.Get(BTC_USDT, fetch)
+	assert.Nil(t, err)
+	assert.Equal(t, btcUsdtInfo, *info)
+	_, err = cache.Get(ETH_BTC, fetch)
+	assert.True(t, EX_ERR_INVALID_CURRENCY_PAIR.Is(err))
+	assert.Equal(t, 1, fetches)
+
+	fetchErr := errors.New("down")
+	_, err = (&SymbolInfoCache{}).Get(BTC_USDT, func() ([]SymbolInfo, error) { return nil, fetchErr })
+	assert.Equal(t, fetchErr, err)
+}
@@ -0,0 +1,54 @@
+package goex
+
+//TradeFee is the part of the order value an exchange charges, 0.001 for 0.1%
+type TradeFee struct {
+	Maker Decimal //orders resting on the book
+	Taker Decimal //orders filled on arrival
+}
+
+//Fee is what a fill of amount at price costs, in the quote currency
+func (f TradeFee) Fee(amount, price Decimal, maker bool) Decimal {
+	rate := f.Taker
+	if maker {
+		rate = f.Maker
+	}
+	return amount.Mul(price).Mul(rate)
+}
+
+//TradeFeeAPI is implemented by adapters that read the account's own rates, lowered by its volume tier or discounts
+type TradeFeeAPI interface {
+	GetTradeFee(pair CurrencyPair) (*TradeFee, error)
+}
+
+//PublishedTradeFees holds the base tier rates of the exchanges by name, for the adapters without GetTradeFee
+var PublishedTradeFees = map[string]TradeFee{
+	"binance.com":  {Maker: RequireDecimal("0.001"), Taker: RequireDecimal("0.001")},
+	"bitfinex.com": {Maker: RequireDecimal("0.001"), Taker: RequireDecimal("0.002")},
+	"bitstamp.net": {Maker: RequireDecimal("0.0025"), Taker: RequireDecimal("0.0025")},
+	"chbtc.com":    {Maker: RequireDecimal("0.002"), Taker: RequireDecimal("0.002")},
+	"gdax.com":     {Maker: RequireDecimal("0"), Taker: RequireDecimal("0.003")},
+	"hitbtc.com":   {Maker: RequireDecimal("-0.0001"), Taker: RequireDecimal("0.001")},
+	"huobi.com":    {Maker: RequireDecimal("0.002"), Taker: RequireDecimal("0.002")},
+	"kraken.com":   {Maker: RequireDecimal("0.0016"), Taker: RequireDecimal("0.0026")},
+	"okcoin.cn":    {Maker: RequireDecimal("0.001"), Taker: RequireDecimal("0.002")},
+	"okcoin.com":   {Maker: RequireDecimal("0.001"), Taker: RequireDecimal("0.002")},
+	"poloniex.com": {Maker: RequireDecimal("0.0015"), Taker: RequireDecimal("0.0025")},
+}
+
+//GetTradeFee asks the adapter for the account's rates when it implements TradeFeeAPI, and falls back to
+//PublishedTradeFees when it does not or answers ErrNotSupported. Other errors are returned as they are.
+func GetTradeFee(api API, pair CurrencyPair) (*TradeFee, error) {
+	if feeAPI, ok := api.(TradeFeeAPI); ok {
+		fee, err := feeAPI.GetTradeFee(pair)
+		if !ErrNotSupported.Is(err) {
+			return fee, err
+		}
+	}
+	fee, ok := PublishedTradeFees[api.GetExchangeName()]
+	if !ok {
+		errCode := EX_ERR_UNKNOWN_FEE
+		errCode.OriginErrMsg = api.GetExchangeName() + " " + pair.String()
+		return nil, errCode
+	}
+	return &fee, nil
+}
@@ -0,0 +1,45 @@
+package goex
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+//feesAPI is an API on exchange whose GetTradeFee answers fee, err
+type feesAPI struct {
+	API
+	exchange string
+	fee      *TradeFee
+	err      error
+}
+
+func (f *feesAPI) GetExchangeName() string {
+	return f.exchange
+}
+
+func (f *feesAPI) GetTradeFee(pair CurrencyPair) (*TradeFee, error) {
+	return f.fee, f.err
+}
+
+func TestTradeFee_Fee(t *testing.T) {
+	fee := TradeFee{Maker: RequireDecimal("0.001"), Taker: RequireDecimal("0.002")}
+	assert.True(t, fee.Fee(RequireDecimal("2"), RequireDecimal("6000"), true).Equal(RequireDecimal("12")))
+	assert.True(t, fee.Fee(RequireDecimal("2"), RequireDecimal("6000"), false).Equal(RequireDecimal("24")))
+}
+
+func TestGetTradeFee(t *testing.T) {
+	account := &TradeFee{Maker: RequireDecimal("0.0008"), Taker: RequireDecimal("0.001")}
+	fee, err := GetTradeFee(&feesAPI{exchange: "kraken.com", fee: account}, BTC_USD)
+	assert.Nil(t, err)
+	assert.Equal(t, account, fee)
+
+	fee, err = GetTradeFee(&feesAPI{exchange: "kraken.com", err: ErrNotSupported}, BTC_USD)
+	assert.Nil(t, err)
+	assert.Equal(t, PublishedTradeFees["kraken.com"], *fee)
+
+	_, err = GetTradeFee(&feesAPI{exchange: "kraken.com", err: EX_ERR_API_LIMIT}, BTC_USD)
+	assert.Equal(t, EX_ERR_API_LIMIT, err)
+	_, err = GetTradeFee(&feesAPI{exchange: "unknown.com", err: ErrNotSupported}, BTC_USD)
+	assert.True(t, EX_ERR_UNKNOWN_FEE.Is(err))
+}
@@ -0,0 +1,179 @@
+package goex
+
+import (
+	"context"
+	"sort"
+	"time"
+)
+
+//TradesSince is what the since of an adapter's GetTrades counts
+type TradesSince int
+
+const (
+	TRADES_SINCE_TIME TradesSince = iota //unix milliseconds, the trades at or after it
+	TRADES_SINCE_ID                      //a trade id, the trades after it
+)
+
+//TradesSinceAPI is implemented by the adapters whose GetTrades pages by trade id, the others page by time
+type TradesSinceAPI interface {
+	TradesSince() TradesSince
+}
+
+//TradesSinceTime sorts the trades oldest first and keeps those made at or after since, unix milliseconds,
+//for exchanges listing the newest first or answering the latest whatever is asked
+func TradesSinceTime(trades []Trade, since int64) []Trade {
+	sort.SliceStable(trades, func(i, j int) bool {
+		if trades[i].Date != trades[j].Date {
+			return trades[i].Date < trades[j].Date
+		}
+		return trades[i].Tid < trades[j].Tid
+	})
+	i := sort.Search(len(trades), func(i int) bool { return trades[i].Date >= since })
+	return trades[i:]
+}
+
+//TradeIterator walks the public trades of a range one GetTrades page at a time, by time or by trade id as
+//the adapter pages, each trade once. Exchanges answering only their latest trades reach back as far as those.
+type TradeIterator struct {
+	Policy RetryPolicy //retries every page, the rate limiter of the adapter's client paces them
+
+	api        API
+	pair       CurrencyPair
+	start, end int64 //unix milliseconds
+	byID       bool
+	found      bool           //whether the id paging found where start is
+	cursor     int64          //the since of the next page
+	seen       map[int64]bool //the trades at the cursor's millisecond, paging by time answers them again
+	done       bool
+}
+
+//NewTradeIterator walks from start to end, unix milliseconds with end 0 for now
+func NewTradeIterator(api API, pair CurrencyPair, start, end int64) *TradeIterator {
+	if end <= 0 {
+		end = time.Now().UnixNano() / int64(time.Millisecond)
+	}
+	it := &TradeIterator{Policy: DefaultRetryPolicy, api: api, pair: pair, start: start, end: end, cursor: start}
+	if paging, ok := api.(TradesSinceAPI); ok && paging.TradesSince() == TRADES_SINCE_ID {
+		it.byID = true
+	}
+	return it
+}
+
+func (it *TradeIterator) getTrades(ctx context.Context, since int64) ([]Trade, error) {
+	var trades []Trade
+	err := it.Policy.Do(ctx, func() (err error) {
+		trades, err = it.api.GetTrades(it.pair, since)
+		return err
+	})
+	return trades, err
+}
+
+//findID returns a trade id from before start, bisecting the ids up to the latest trade's
+func (it *TradeIterator) findID(ctx context.Context) (int64, error) {
+	latest, err := it.getTrades(ctx, 0)
+	if err != nil || len(latest) == 0 {
+		return 0, err
+	}
+	if latest[0].Date < it.start {
+		return latest[0].Tid - 1, nil
+	}
+
+	lo, hi := int64(0), latest[0].Tid
+	for hi-lo > 1 {
+		mid := lo + (hi-lo)/2
+		page, err := it.getTrades(ctx, mid)
+		if err != nil {
+			return 0, err
+		}
+		if len(page) == 0 || page[0].Date >= it.start {
+			hi = mid
+			continue
+		}
+		lo = mid
+		if page[len(page)-1].Date >= it.start {
+			break
+		}
+	}
+	if lo == 0 {
+		lo = 1 //0 asks for the latest trades
+	}
+	return lo, nil
+}
+
+//Next returns the next page, oldest first, an empty one may come before the last. It returns nil, nil once Done.
+func (it *TradeIterator) Next(ctx context.Context) ([]Trade, error) {
+	if it.done {
+		return nil, nil
+	}
+	if it.byID && !it.found {
+		id, err := it.findID(ctx)
+		if err != nil {
+			return nil, err
+		}
+		it.found = true
+		if id == 0 {
+			it.done = true
+			return nil, nil
+		}
+		it.cursor = id
+	}
+
+	trades, err := it.getTrades(ctx, it.cursor)
+	if err != nil {
+		return nil, err
+	}
+
+	page := make([]Trade, 0, len(trades))
+	fresh := false
+	for _, t := range trades {
+		if it.byID && t.Tid <= it.cursor || !it.byID && (t.Date < it.cursor || t.Date == it.cursor && it.seen[t.Tid]) {
+			continue
+		}
+		fresh = true
+		if t.Date >= it.start && t.Date <= it.end {
+			page = append(page, t)
+		}
+	}
+	if !fresh {
+		it.done = true
+		return page, nil
+	}
+
+	last := trades[len(trades)-1]
+	if it.byID {
+		it.cursor = last.Tid
+	} else {
+		if last.Date != it.cursor || it.seen == nil {
+			it.seen = map[int64]bool{}
+		}
+		for _, t := range trades {
+			if t.Date == last.Date {
+				it.seen[t.Tid] = true
+			}
+		}
+		it.cursor = last.Date
+	}
+	it.done = last.Date > it.end
+	return page, nil
+}
+
+func (it *TradeIterator) Done() bool {
+	return it.done
+}
+
+//DrainTrades reads every page of the range, the public tape from start to end in unix milliseconds
+func DrainTrades(ctx context.Context, api API, pair CurrencyPair, start, end int64) ([]Trade, error) {
+	it := NewTradeIterator(api, pair, start, end)
+	var all []Trade
+	for !it.Done() {
+		if err := ctx.Err(); err != nil {
+			return all, err
+		}
+		trades, err := it.Next(ctx)
+		if err != nil {
+			return all, err
+		}
+		all = append(all, trades...)
+	}
+	return all, nil
+}
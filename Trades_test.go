@@ -0,0 +1,71 @@
+package goex
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+//tapeAPI answers pages of 3 trades from its tape, oldest first, by time or after an id as paging says
+type tapeAPI struct {
+	API
+	tape   []Trade
+	paging TradesSince
+}
+
+func (t *tapeAPI) GetTrades(currencyPair CurrencyPair, since int64) ([]Trade, error) {
+	if since == 0 {
+		return t.tape[len(t.tape)-3:], nil
+	}
+	var page []Trade
+	for _, trade := range t.tape {
+		if t.paging == TRADES_SINCE_ID && trade.Tid > since || t.paging == TRADES_SINCE_TIME && trade.Date >= since {
+			page = append(page, trade)
+		}
+		if len(page) == 3 {
+			break
+		}
+	}
+	return page, nil
+}
+
+func (t *tapeAPI) TradesSince() TradesSince {
+	return t.paging
+}
+
+func tids(trades []Trade) (ids []int64) {
+	for _, t := range trades {
+		ids = append(ids, t.Tid)
+	}
+	return ids
+}
+
+func TestTradesSinceTime(t *testing.T) {
+	trades := []Trade{{Tid: 3, Date: 2000}, {Tid: 2, Date: 1000}, {Tid: 1, Date: 1000}, {Tid: 4, Date: 3000}}
+	assert.Equal(t, []int64{1, 2, 3, 4}, tids(TradesSinceTime(trades, 0)))
+	assert.Equal(t, []int64{3, 4}, tids(TradesSinceTime(trades, 1500)))
+}
+
+func TestDrainTrades(t *testing.T) {
+	api := new(tapeAPI)
+	for id := int64(1); id <= 20; id++ {
+		//two trades every second millisecond, the pages split them
+		api.tape = append(api.tape, Trade{Tid: id * 10, Date: 1000 + (id-1)/2*2})
+	}
+
+	api.paging = TRADES_SINCE_TIME
+	trades, err := DrainTrades(context.Background(), api, BTC_USD, 1004, 1012)
+	assert.Nil(t, err)
+	assert.Equal(t, []int64{50, 60, 70, 80, 90, 100, 110, 120, 130, 140}, tids(trades))
+
+	api.paging = TRADES_SINCE_ID
+	trades, err = DrainTrades(context.Background(), api, BTC_USD, 1004, 1012)
+	assert.Nil(t, err)
+	assert.Equal(t, []int64{50, 60, 70, 80, 90, 100, 110, 120, 130, 140}, tids(trades))
+
+	//the whole tape, from before the first trade
+	trades, err = DrainTrades(context.Background(), api, BTC_USD, 0, 2000)
+	assert.Nil(t, err)
+	assert.Len(t, trades, 20)
+}
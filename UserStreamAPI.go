@@ -0,0 +1,15 @@
+package goex
+
+//UserStreamAPI pushes changes of the account's own orders and balances over an authenticated websocket,
+//so a fill is seen as it happens instead of on the next GetOneOrder poll.
+//As with StreamAPI the channels are fed until Close is called and a dropped connection is
+//redialed, authenticated again and resubscribed.
+type UserStreamAPI interface {
+	//every state change of any order of the account: placed, partially filled, filled, canceled ...
+	SubscribeOrders() (<-chan Order, error)
+	//the new balance of every currency that changed
+	SubscribeBalances() (<-chan SubAccount, error)
+
+	GetExchangeName() string
+	Close() error
+}
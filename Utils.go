@@ -1,6 +1,9 @@
 package goex
 
 import (
+	"encoding/json"
+	"fmt"
+	"log"
 	"strconv"
 )
 
@@ -58,3 +61,84 @@ func ToUint64(v interface{}) uint64 {
 		panic("to uint64 error.")
 	}
 }
+
+func ToInt64(v interface{}) int64 {
+	if v == nil {
+		return 0
+	}
+
+	switch v.(type) {
+	case int:
+		return int64(v.(int))
+	case int64:
+		return v.(int64)
+	case float64:
+		return int64(v.(float64))
+	case string:
+		iV, _ := strconv.ParseInt(v.(string), 10, 64)
+		return iV
+	default:
+		panic("to int64 error.")
+	}
+}
+
+//ToDecimalE converts a parsed json value. nil and "" are 0, a malformed string or a value of another
+//type is an error.
+func ToDecimalE(v interface{}) (Decimal, error) {
+	if v == nil {
+		return Decimal{}, nil
+	}
+
+	switch v.(type) {
+	case Decimal:
+		return v.(Decimal), nil
+	case float64:
+		return NewDecimalFromFloat(v.(float64)), nil
+	case int:
+		return NewDecimalFromInt(int64(v.(int))), nil
+	case int64:
+		return NewDecimalFromInt(v.(int64)), nil
+	case json.Number:
+		return ToDecimalE(string(v.(json.Number)))
+	case string:
+		if v.(string) == "" {
+			return Decimal{}, nil
+		}
+		return NewDecimalFromString(v.(string))
+	default:
+		return Decimal{}, fmt.Errorf("unexpected %T %v for a decimal", v, v)
+	}
+}
+
+//ToDecimal is ToDecimalE for values known to be well formed: the error is logged and the value read as 0.
+//Responses of an exchange are read with ToDecimalE or a DecimalParser, which return the error instead.
+func ToDecimal(v interface{}) Decimal {
+	d, err := ToDecimalE(v)
+	if err != nil {
+		log.Println("to decimal error:", err)
+	}
+	return d
+}
+
+//DecimalParser reads the fields of one response with ToDecimalE and keeps the first error in Err,
+//so a parse function converts them all and checks Err once before returning what it built.
+type DecimalParser struct {
+	Err error
+}
+
+func (p *DecimalParser) Decimal(v interface{}) Decimal {
+	d, err := ToDecimalE(v)
+	if err != nil && p.Err == nil {
+		p.Err = err
+	}
+	return d
+}
+
+//Price is Decimal for a value that has to be positive, as the price of a depth level
+func (p *DecimalParser) Price(v interface{}) Decimal {
+	d := p.Decimal(v)
+	if d.Sign() <= 0 && p.Err == nil {
+		p.Err = fmt.Errorf("price %v not positive", v)
+	}
+	return d
+}
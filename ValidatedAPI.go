@@ -0,0 +1,147 @@
+package goex
+
+import "fmt"
+
+//ValidatedAPI wraps an API and checks every order before it is sent, so a bad order costs no request
+//and a fat-fingered price never reaches the exchange. The checks, each one only when possible or enabled:
+//
+//	precision and limits  SymbolInfo.Validate, when the wrapped API implements MarketAPI
+//	price band            MaxPriceDeviation against the last price of GetTicker
+//	balance               CheckBalance against the available amounts of GetAccount
+//
+//Market orders are checked with the amount in the base currency and the best opposite ticker price
+//standing in for the order price. The other calls go straight to the wrapped API.
+type ValidatedAPI struct {
+	API
+	MaxPriceDeviation Decimal //0.05 refuses limit prices more than 5% away from the last price, zero disables
+	CheckBalance      bool    //trading fees are not taken into account
+}
+
+func NewValidatedAPI(api API) *ValidatedAPI {
+	return &ValidatedAPI{API: api}
+}
+
+func (v *ValidatedAPI) LimitBuy(amount, price Decimal, currency CurrencyPair) (*Order, error) {
+	if err := v.Validate(BUY, amount, price, currency); err != nil {
+		return nil, err
+	}
+	return v.API.LimitBuy(amount, price, currency)
+}
+
+func (v *ValidatedAPI) LimitSell(amount, price Decimal, currency CurrencyPair) (*Order, error) {
+	if err := v.Validate(SELL, amount, price, currency); err != nil {
+		return nil, err
+	}
+	return v.API.LimitSell(amount, price, currency)
+}
+
+func (v *ValidatedAPI) MarketBuy(amount, price Decimal, currency CurrencyPair) (*Order, error) {
+	if err := v.Validate(BUY_MARKET, amount, price, currency); err != nil {
+		return nil, err
+	}
+	return v.API.MarketBuy(amount, price, currency)
+}
+
+func (v *ValidatedAPI) MarketSell(amount, price Decimal, currency CurrencyPair) (*Order, error) {
+	if err := v.Validate(SELL_MARKET, amount, price, currency); err != nil {
+		return nil, err
+	}
+	return v.API.MarketSell(amount, price, currency)
+}
+
+//Validate runs the checks of an order without placing it. The price of a market order is ignored.
+//The errors are EX_ERR_INVALID_PRECISION, EX_ERR_INVALID_ORDER_SIZE, EX_ERR_PRICE_OUT_OF_BAND and
+//EX_ERR_INSUFFICIENT_BALANCE, or the error of the request the check needed.
+func (v *ValidatedAPI) Validate(side TradeSide, amount, price Decimal, currency CurrencyPair) error {
+	isMarket := side == BUY_MARKET || side == SELL_MARKET
+	isBuy := side == BUY || side == BUY_MARKET
+	if isMarket {
+		price = Decimal{}
+	} else if price.Sign() <= 0 {
+		errCode := EX_ERR_INVALID_ORDER_SIZE
+		errCode.OriginErrMsg = fmt.Sprintf("%s price %s is not positive", currency, price)
+		return errCode
+	}
+
+	var info *SymbolInfo
+	if m, isok := v.API.(MarketAPI); isok {
+		var err error
+		if info, err = m.GetSymbolInfo(currency); err != nil {
+			return err
+		}
+		if err = info.Validate(side, amount, price); err != nil {
+			return err
+		}
+	} else if amount.Sign() <= 0 {
+		errCode := EX_ERR_INVALID_ORDER_SIZE
+		errCode.OriginErrMsg = fmt.Sprintf("%s amount %s is not positive", currency, amount)
+		return errCode
+	}
+
+	if isMarket && (info != nil && info.MinNotional.Sign() > 0 || isBuy && v.CheckBalance) ||
+		!isMarket && v.MaxPriceDeviation.Sign() > 0 {
+		ticker, err := v.API.GetTicker(currency)
+		if err != nil {
+			return err
+		}
+		if isMarket {
+			price = v.marketPrice(ticker, isBuy)
+			if info != nil {
+				if err = info.checkLimits(amount, price); err != nil {
+					return err
+				}
+			}
+		} else if err = v.checkPriceBand(price, ticker, currency); err != nil {
+			return err
+		}
+	}
+
+	if v.CheckBalance {
+		return v.checkBalance(isBuy, amount, price, currency)
+	}
+	return nil
+}
+
+//marketPrice is what a market order is expected to fill at, the best opposite price
+func (v *ValidatedAPI) marketPrice(ticker *Ticker, isBuy bool) Decimal {
+	price := ticker.Buy
+	if isBuy {
+		price = ticker.Sell
+	}
+	if price.IsZero() {
+		price = ticker.Last
+	}
+	return price
+}
+
+func (v *ValidatedAPI) checkPriceBand(price Decimal, ticker *Ticker, currency CurrencyPair) error {
+	if ticker.Last.Sign() <= 0 {
+		return nil
+	}
+	band := ticker.Last.Mul(v.MaxPriceDeviation)
+	if price.Sub(ticker.Last).Abs().GreaterThan(band) {
+		errCode := EX_ERR_PRICE_OUT_OF_BAND
+		errCode.OriginErrMsg = fmt.Sprintf("%s price %s more than %s away from the last price %s", currency, price, band, ticker.Last)
+		return errCode
+	}
+	return nil
+}
+
+func (v *ValidatedAPI) checkBalance(isBuy bool, amount, price Decimal, currency CurrencyPair) error {
+	account, err := v.API.GetAccount()
+	if err != nil {
+		return err
+	}
+
+	need, pay := amount, currency.CurrencyA
+	if isBuy {
+		need, pay = amount.Mul(price), currency.CurrencyB
+	}
+	available := account.SubAccounts[pay].Amount
+	if available.LessThan(need) {
+		errCode := EX_ERR_INSUFFICIENT_BALANCE
+		errCode.OriginErrMsg = fmt.Sprintf("%s needs %s %s, %s available", currency, need, pay, available)
+		return errCode
+	}
+	return nil
+}
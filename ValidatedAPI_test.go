@@ -0,0 +1,124 @@
+package goex
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+//stubAPI places every order it is given and counts the requests
+type stubAPI struct {
+	API
+	requests int
+	placed   []Order
+}
+
+func (s *stubAPI) GetTicker(currency CurrencyPair) (*Ticker, error) {
+	s.requests++
+	return &Ticker{Last: RequireDecimal("6100"), Buy: RequireDecimal("6099.5"), Sell: RequireDecimal("6100.5")}, nil
+}
+
+func (s *stubAPI) GetAccount() (*Account, error) {
+	s.requests++
+	return &Account{SubAccounts: map[Currency]SubAccount{
+		BTC:  {Currency: BTC, Amount: RequireDecimal("0.5")},
+		USDT: {Currency: USDT, Amount: RequireDecimal("1000")}}}, nil
+}
+
+func (s *stubAPI) GetMarkets() ([]SymbolInfo, error) {
+	return []SymbolInfo{btcUsdtInfo}, nil
+}
+
+func (s *stubAPI) GetSymbolInfo(pair CurrencyPair) (*SymbolInfo, error) {
+	return new(SymbolInfoCache).Get(pair, s.GetMarkets)
+}
+
+func (s *stubAPI) place(amount, price Decimal, currency CurrencyPair, side TradeSide) (*Order, error) {
+	s.requests++
+	s.placed = append(s.placed, Order{Amount: amount, Price: price, Currency: currency, Side: side})
+	return &s.placed[len(s.placed)-1], nil
+}
+
+func (s *stubAPI) LimitBuy(amount, price Decimal, currency CurrencyPair) (*Order, error) {
+	return s.place(amount, price, currency, BUY)
+}
+
+func (s *stubAPI) LimitSell(amount, price Decimal, currency CurrencyPair) (*Order, error) {
+	return s.place(amount, price, currency, SELL)
+}
+
+func (s *stubAPI) MarketBuy(amount, price Decimal, currency CurrencyPair) (*Order, error) {
+	return s.place(amount, price, currency, BUY_MARKET)
+}
+
+func (s *stubAPI) MarketSell(amount, price Decimal, currency CurrencyPair) (*Order, error) {
+	return s.place(amount, price, currency, SELL_MARKET)
+}
+
+func TestValidatedAPI_SymbolRules(t *testing.T) {
+	stub := new(stubAPI)
+	api := NewValidatedAPI(stub)
+
+	_, err := api.LimitBuy(RequireDecimal("0.012345"), RequireDecimal("6100"), BTC_USDT)
+	assert.Nil(t, err)
+	assert.Equal(t, 1, stub.requests)
+
+	for _, c := range []struct {
+		amount, price string
+		err           ApiError
+	}{
+		{"0.0123456789", "6100", EX_ERR_INVALID_PRECISION},
+		{"0.01", "6100.001", EX_ERR_INVALID_PRECISION},
+		{"0.001", "6100", EX_ERR_INVALID_ORDER_SIZE},
+		{"0.01", "0", EX_ERR_INVALID_ORDER_SIZE},
+		{"0", "6100", EX_ERR_INVALID_ORDER_SIZE},
+	} {
+		_, err = api.LimitSell(RequireDecimal(c.amount), RequireDecimal(c.price), BTC_USDT)
+		assert.True(t, c.err.Is(err), c.amount+"@"+c.price)
+	}
+	_, err = api.LimitSell(RequireDecimal("1"), RequireDecimal("1"), ETH_BTC)
+	assert.True(t, EX_ERR_INVALID_CURRENCY_PAIR.Is(err))
+	assert.Equal(t, 1, stub.requests)
+
+	//a market buy of 0.001 btc is worth 6.1 usdt, below the minimum of 10
+	_, err = api.MarketBuy(RequireDecimal("0.001"), Decimal{}, BTC_USDT)
+	assert.True(t, EX_ERR_INVALID_ORDER_SIZE.Is(err))
+	_, err = api.MarketSell(RequireDecimal("0.01"), Decimal{}, BTC_USDT)
+	assert.Nil(t, err)
+	assert.Equal(t, 4, stub.requests)
+}
+
+func TestValidatedAPI_PriceBand(t *testing.T) {
+	stub := new(stubAPI)
+	api := NewValidatedAPI(stub)
+	api.MaxPriceDeviation = RequireDecimal("0.05")
+
+	_, err := api.LimitBuy(RequireDecimal("0.01"), RequireDecimal("5795"), BTC_USDT)
+	assert.Nil(t, err)
+	_, err = api.LimitBuy(RequireDecimal("0.01"), RequireDecimal("5794.99"), BTC_USDT)
+	assert.True(t, EX_ERR_PRICE_OUT_OF_BAND.Is(err))
+	_, err = api.LimitSell(RequireDecimal("0.01"), RequireDecimal("61000"), BTC_USDT)
+	assert.True(t, EX_ERR_PRICE_OUT_OF_BAND.Is(err))
+	assert.Equal(t, 1, len(stub.placed))
+}
+
+func TestValidatedAPI_CheckBalance(t *testing.T) {
+	stub := new(stubAPI)
+	api := NewValidatedAPI(stub)
+	api.CheckBalance = true
+
+	_, err := api.LimitBuy(RequireDecimal("0.2"), RequireDecimal("6100"), BTC_USDT)
+	assert.True(t, EX_ERR_INSUFFICIENT_BALANCE.Is(err))
+	_, err = api.LimitBuy(RequireDecimal("0.1"), RequireDecimal("100"), BTC_USDT)
+	assert.Nil(t, err)
+
+	_, err = api.MarketBuy(RequireDecimal("0.2"), Decimal{}, BTC_USDT) //1220.1 usdt at the ask
+	assert.True(t, EX_ERR_INSUFFICIENT_BALANCE.Is(err))
+	_, err = api.MarketSell(RequireDecimal("0.6"), Decimal{}, BTC_USDT)
+	assert.True(t, EX_ERR_INSUFFICIENT_BALANCE.Is(err))
+	_, err = api.MarketSell(RequireDecimal("0.5"), Decimal{}, BTC_USDT)
+	assert.Nil(t, err)
+	assert.Equal(t, []Order{
+		{Amount: RequireDecimal("0.1"), Price: RequireDecimal("100"), Currency: BTC_USDT, Side: BUY},
+		{Amount: RequireDecimal("0.5"), Currency: BTC_USDT, Side: SELL_MARKET}}, stub.placed)
+}
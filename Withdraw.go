@@ -0,0 +1,42 @@
+package goex
+
+type WithdrawStatus int
+
+const (
+	WITHDRAW_PENDING    WithdrawStatus = iota //accepted, waiting for the exchange's approval or processing
+	WITHDRAW_PROCESSING                       //sent to the network, waiting for confirmations
+	WITHDRAW_COMPLETED
+	WITHDRAW_CANCELED
+	WITHDRAW_FAILED
+)
+
+var withdrawStatusSymbol = [...]string{"PENDING", "PROCESSING", "COMPLETED", "CANCELED", "FAILED"}
+
+func (ws WithdrawStatus) String() string {
+	if ws < 0 || int(ws) >= len(withdrawStatusSymbol) {
+		return "UNKNOWN"
+	}
+	return withdrawStatusSymbol[ws]
+}
+
+//WithdrawRequest is what Withdraw sends out, the fields an exchange does not use are ignored
+type WithdrawRequest struct {
+	Currency Currency
+	Amount   Decimal
+	Fee      Decimal //the network fee to offer, zero takes the exchange's default
+	Address  string
+	Tag      string //memo, payment id or destination tag of the address, if it needs one
+	Key      string //name of an address registered on the exchange, kraken withdraws to these only
+	Wallet   string //wallet to withdraw from, bitfinex: "exchange", "trading" or "deposit"
+
+	TradePassword string //the fund password some exchanges ask for, chbtc and okcoin
+}
+
+//WithdrawAPI is implemented by adapters that can move funds off the exchange. Every adapter returns
+//the exchange's withdrawal id in Withdraw.ID, CancelWithdraw and GetWithdraw take it back.
+//Calls an exchange has no api for return ErrNotSupported.
+type WithdrawAPI interface {
+	Withdraw(req WithdrawRequest) (*Withdraw, error)
+	CancelWithdraw(id string, currency Currency, tradePassword string) (bool, error)
+	GetWithdraw(id string, currency Currency) (*Withdraw, error)
+}
@@ -0,0 +1,242 @@
+package goex
+
+import (
+	"bytes"
+	"compress/flate"
+	"compress/gzip"
+	"io/ioutil"
+	"log"
+	"net/http"
+	"sync"
+	"time"
+
+	"github.com/gorilla/websocket"
+)
+
+//WsConn is a websocket client that redials and replays its subscriptions
+//whenever the connection drops. Set the exported fields before Connect.
+type WsConn struct {
+	WsUrl             string
+	ReqHeaders        http.Header
+	ReconnectInterval time.Duration //default 1s
+	ReadTimeout       time.Duration //0: wait forever, otherwise a silent connection is redialed
+	HeartbeatInterval time.Duration
+	HeartbeatData     func() []byte                //sent every HeartbeatInterval when not nil
+	LoginDataFunc     func() interface{}           //sent as JSON before the subscriptions on every (re)connect when not nil
+	UnCompressFunc    func([]byte) ([]byte, error) //applied to each received message when not nil
+	ProtoHandleFunc   func([]byte) error           //called for every received message
+	CloseHandleFunc   func()                       //called once the read loop has stopped after Close
+
+	writeL   sync.Mutex
+	conn     *websocket.Conn
+	subs     []interface{}
+	closeCh  chan struct{}
+	initOnce sync.Once
+	once     sync.Once
+}
+
+func NewWsConn(wsUrl string, handle func([]byte) error) *WsConn {
+	return &WsConn{WsUrl: wsUrl, ProtoHandleFunc: handle, ReconnectInterval: time.Second, closeCh: make(chan struct{})}
+}
+
+func (ws *WsConn) Connect() error {
+	if err := ws.dial(); err != nil {
+		return err
+	}
+	go ws.readLoop()
+	if ws.HeartbeatInterval > 0 && ws.HeartbeatData != nil {
+		go ws.heartbeatLoop()
+	}
+	return nil
+}
+
+//Subscribe sends sub as JSON now and again after every reconnect.
+//A failed send is only logged, the read loop notices the broken connection and replays sub.
+func (ws *WsConn) Subscribe(sub interface{}) {
+	ws.writeL.Lock()
+	defer ws.writeL.Unlock()
+	ws.subs = append(ws.subs, sub)
+	if ws.conn == nil {
+		return
+	}
+	if err := ws.conn.WriteJSON(sub); err != nil {
+		log.Println("[ws] subscribe", ws.WsUrl, "error:", err)
+	}
+}
+
+func (ws *WsConn) SendJsonMessage(v interface{}) error {
+	ws.writeL.Lock()
+	defer ws.writeL.Unlock()
+	if ws.conn == nil {
+		return HTTP_ERR_CODE
+	}
+	return ws.conn.WriteJSON(v)
+}
+
+func (ws *WsConn) SendMessage(msg []byte) error {
+	ws.writeL.Lock()
+	defer ws.writeL.Unlock()
+	if ws.conn == nil {
+		return HTTP_ERR_CODE
+	}
+	return ws.conn.WriteMessage(websocket.TextMessage, msg)
+}
+
+//done is closed by Close, made on first use so that a WsConn not made by NewWsConn can be closed too
+func (ws *WsConn) done() chan struct{} {
+	ws.initOnce.Do(func() {
+		if ws.closeCh == nil {
+			ws.closeCh = make(chan struct{})
+		}
+	})
+	return ws.closeCh
+}
+
+func (ws *WsConn) Close() error {
+	ws.once.Do(func() { close(ws.done()) })
+	ws.writeL.Lock()
+	defer ws.writeL.Unlock()
+	if ws.conn == nil {
+		return nil
+	}
+	ws.conn.WriteControl(websocket.CloseMessage, websocket.FormatCloseMessage(websocket.CloseNormalClosure, ""), time.Now().Add(time.Second))
+	return ws.conn.Close()
+}
+
+func (ws *WsConn) isClosed() bool {
+	select {
+	case <-ws.done():
+		return true
+	default:
+		return false
+	}
+}
+
+func (ws *WsConn) dial() error {
+	conn, _, err := websocket.DefaultDialer.Dial(ws.WsUrl, ws.ReqHeaders)
+	if err != nil {
+		errCode := HTTP_ERR_CODE
+		errCode.OriginErrMsg = err.Error()
+		return errCode
+	}
+
+	ws.writeL.Lock()
+	defer ws.writeL.Unlock()
+	if ws.isClosed() {
+		conn.Close()
+		return nil
+	}
+	ws.conn = conn
+	if ws.LoginDataFunc != nil {
+		if err = conn.WriteJSON(ws.LoginDataFunc()); err != nil {
+			conn.Close()
+			return err
+		}
+	}
+	for _, sub := range ws.subs {
+		if err = conn.WriteJSON(sub); err != nil {
+			conn.Close()
+			return err
+		}
+	}
+	return nil
+}
+
+//reconnect blocks until a new connection is up, returns false if Close was called meanwhile
+func (ws *WsConn) reconnect() bool {
+	ws.writeL.Lock()
+	if ws.conn != nil {
+		ws.conn.Close()
+	}
+	ws.writeL.Unlock()
+
+	interval := ws.ReconnectInterval
+	if interval <= 0 {
+		interval = time.Second
+	}
+	for {
+		select {
+		case <-ws.done():
+			return false
+		case <-time.After(interval):
+		}
+		err := ws.dial()
+		if err == nil {
+			return !ws.isClosed()
+		}
+		log.Println("[ws] reconnect", ws.WsUrl, "fail:", err)
+	}
+}
+
+func (ws *WsConn) readLoop() {
+	defer func() {
+		if ws.CloseHandleFunc != nil {
+			ws.CloseHandleFunc()
+		}
+	}()
+
+	for {
+		ws.writeL.Lock()
+		conn := ws.conn
+		ws.writeL.Unlock()
+		if conn == nil {
+			return
+		}
+
+		if ws.ReadTimeout > 0 {
+			conn.SetReadDeadline(time.Now().Add(ws.ReadTimeout))
+		}
+		_, msg, err := conn.ReadMessage()
+		if err != nil {
+			if ws.isClosed() {
+				return
+			}
+			log.Println("[ws]", ws.WsUrl, "read error:", err, ", reconnecting")
+			if !ws.reconnect() {
+				return
+			}
+			continue
+		}
+
+		if ws.UnCompressFunc != nil {
+			if msg, err = ws.UnCompressFunc(msg); err != nil {
+				log.Println("[ws] uncompress error:", err)
+				continue
+			}
+		}
+
+		if err = ws.ProtoHandleFunc(msg); err != nil {
+			log.Println("[ws] handle message error:", err, string(msg))
+		}
+	}
+}
+
+func (ws *WsConn) heartbeatLoop() {
+	ticker := time.NewTicker(ws.HeartbeatInterval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ws.done():
+			return
+		case <-ticker.C:
+			ws.SendMessage(ws.HeartbeatData())
+		}
+	}
+}
+
+//GzipUnCompress can be used as WsConn.UnCompressFunc
+func GzipUnCompress(data []byte) ([]byte, error) {
+	r, err := gzip.NewReader(bytes.NewReader(data))
+	if err != nil {
+		return nil, err
+	}
+	defer r.Close()
+	return ioutil.ReadAll(r)
+}
+
+//FlateUnCompress can be used as WsConn.UnCompressFunc for raw deflate streams
+func FlateUnCompress(data []byte) ([]byte, error) {
+	r := flate.NewReader(bytes.NewReader(data))
+	defer r.Close()
+	return ioutil.ReadAll(r)
+}
@@ -0,0 +1,21 @@
+package goex
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestWsConn_Close(t *testing.T) {
+	//a WsConn not made by NewWsConn, closed twice and without ever connecting
+	ws := &WsConn{WsUrl: "ws://127.0.0.1:1"}
+	assert.Nil(t, ws.Close())
+	assert.Nil(t, ws.Close())
+	assert.True(t, ws.isClosed())
+
+	//connecting after Close leaves it closed
+	ws = NewWsConn("ws://127.0.0.1:1", func([]byte) error { return nil })
+	assert.Nil(t, ws.Close())
+	assert.NotNil(t, ws.Connect())
+	assert.True(t, ws.isClosed())
+}
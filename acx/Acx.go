@@ -1,6 +1,7 @@
 package acx
 
 import (
+	"encoding/json"
 	"fmt"
 	. "github.com/nntaoli-project/GoEx"
 	"net/http"
@@ -30,6 +31,7 @@ type Acx struct {
 }
 
 func New(client *http.Client, api_key, secret_key string) *Acx {
+	client = ExchangeRateLimitedClient(client, EXCHANGE_NAME, api_key, DefaultRateLimitConfig)
 	return &Acx{api_key, secret_key, client}
 }
 
@@ -37,12 +39,20 @@ func (acx *Acx) GetExchangeName() string {
 	return EXCHANGE_NAME
 }
 
+func (acx *Acx) Capabilities() Capabilities {
+	return Capabilities{}
+}
+
 func (acx *Acx) GetTicker(currency CurrencyPair) (*Ticker, error) {
+	var dp DecimalParser
 	tickerUri := API_V1 + fmt.Sprintf(TICKER_URI, strings.ToLower(currency.ToSymbol("")))
 	bodyDataMap, err := HttpGet(acx.httpClient, tickerUri)
 
 	if err != nil {
-		return nil, err
+		return nil, acx.adaptError(err)
+	}
+	if respErr, isok := bodyDataMap["error"]; isok {
+		return nil, acx.errorWrapper(respErr)
 	}
 
 	//log.Println("acx uri:", tickerUri)
@@ -52,12 +62,15 @@ func (acx *Acx) GetTicker(currency CurrencyPair) (*Ticker, error) {
 	var ticker Ticker
 
 	ticker.Date = uint64(bodyDataMap["at"].(float64))
-	ticker.Last = ToFloat64(tickerMap["last"])
-	ticker.Buy = ToFloat64(tickerMap["buy"])
-	ticker.Sell = ToFloat64(tickerMap["sell"])
-	ticker.Low = ToFloat64(tickerMap["low"])
-	ticker.High = ToFloat64(tickerMap["high"])
-	ticker.Vol = ToFloat64(tickerMap["vol"])
+	ticker.Last = dp.Decimal(tickerMap["last"])
+	ticker.Buy = dp.Decimal(tickerMap["buy"])
+	ticker.Sell = dp.Decimal(tickerMap["sell"])
+	ticker.Low = dp.Decimal(tickerMap["low"])
+	ticker.High = dp.Decimal(tickerMap["high"])
+	ticker.Vol = dp.Decimal(tickerMap["vol"])
+	if dp.Err != nil {
+		return nil, dp.Err
+	}
 	return &ticker, nil
 }
 func (acx *Acx) GetTickers(currency CurrencyPair) (*Ticker, error) {
@@ -67,50 +80,93 @@ func (acx *Acx) GetTickerInBuf(currency CurrencyPair) (*Ticker, error) {
 	return acx.GetTicker(currency)
 }
 
-func (acx *Acx) LimitBuy(amount, price string, currency CurrencyPair) (*Order, error) {
-	panic("not implements")
+func (acx *Acx) LimitBuy(amount, price Decimal, currency CurrencyPair) (*Order, error) {
+	return nil, ErrNotSupported
 }
 
-func (acx *Acx) LimitSell(amount, price string, currency CurrencyPair) (*Order, error) {
-	panic("not implements")
+func (acx *Acx) LimitSell(amount, price Decimal, currency CurrencyPair) (*Order, error) {
+	return nil, ErrNotSupported
 }
 
-func (acx *Acx) MarketBuy(amount, price string, currency CurrencyPair) (*Order, error) {
-	panic("not implements")
+func (acx *Acx) MarketBuy(amount, price Decimal, currency CurrencyPair) (*Order, error) {
+	return nil, ErrNotSupported
 }
 
-func (acx *Acx) MarketSell(amount, price string, currency CurrencyPair) (*Order, error) {
-	panic("not implements")
+func (acx *Acx) MarketSell(amount, price Decimal, currency CurrencyPair) (*Order, error) {
+	return nil, ErrNotSupported
 }
 
 func (acx *Acx) CancelOrder(orderId string, currency CurrencyPair) (bool, error) {
-	panic("not implements")
+	return false, ErrNotSupported
 }
 
 func (acx *Acx) GetOneOrder(orderId string, currency CurrencyPair) (*Order, error) {
-	panic("not implements")
+	return nil, ErrNotSupported
 }
 func (acx *Acx) GetUnfinishOrders(currency CurrencyPair) ([]Order, error) {
-	panic("not implements")
+	return nil, ErrNotSupported
 }
 
 func (acx *Acx) GetOrderHistorys(currency CurrencyPair, currentPage, pageSize int) ([]Order, error) {
-	panic("not implements")
+	return nil, ErrNotSupported
 }
 
 func (acx *Acx) GetAccount() (*Account, error) {
-	panic("not implements")
+	return nil, ErrNotSupported
 }
 
 func (acx *Acx) GetDepth(size int, currency CurrencyPair) (*Depth, error) {
-	return nil, nil
+	return nil, ErrNotSupported
 }
 
 func (acx *Acx) GetKlineRecords(currency CurrencyPair, period, size, since int) ([]Kline, error) {
-	panic("not implements")
+	return nil, ErrNotSupported
 }
 
 //非个人，整个交易所的交易记录
 func (acx *Acx) GetTrades(currencyPair CurrencyPair, since int64) ([]Trade, error) {
-	panic("not implements")
+	return nil, ErrNotSupported
+}
+
+//acx runs peatio, it reports failures as {"error":{"code":1001,"message":"market does not have a valid value"}}
+func (acx *Acx) errorWrapper(respErr interface{}) ApiError {
+	errmap, _ := respErr.(map[string]interface{})
+	errcode := ToInt(errmap["code"])
+	errmsg, _ := errmap["message"].(string)
+
+	var errCode ApiError
+	switch {
+	case errcode == 1001 && strings.HasPrefix(errmsg, "market"):
+		errCode = EX_ERR_INVALID_CURRENCY_PAIR
+	case errcode == 2002:
+		errCode = EX_ERR_INSUFFICIENT_BALANCE
+	case errcode == 2003:
+		errCode = EX_ERR_CANCEL_ORDER_FAIL
+	case errcode == 2004:
+		errCode = EX_ERR_NOT_FIND_ORDER
+	case errcode == 2005:
+		errCode = EX_ERR_SIGN
+	case errcode == 2006, errcode == 2007:
+		errCode = EX_ERR_NONCE
+	case errcode == 2008:
+		errCode = EX_ERR_NOT_FIND_APIKEY
+	default:
+		errCode = API_ERR
+	}
+	errCode.OriginErrMsg = fmt.Sprintf("%d:%s", errcode, errmsg)
+	return errCode
+}
+
+//peatio sends the error body with a non-200 status
+func (acx *Acx) adaptError(err error) error {
+	apiErr, isok := err.(ApiError)
+	if !isok {
+		return err
+	}
+
+	var resp map[string]interface{}
+	if json.Unmarshal([]byte(apiErr.OriginErrMsg), &resp) != nil || resp["error"] == nil {
+		return err
+	}
+	return acx.errorWrapper(resp["error"])
 }
@@ -0,0 +1,68 @@
+package acx
+
+import (
+	"context"
+
+	. "github.com/nntaoli-project/GoEx"
+)
+
+var _ API = (*Acx)(nil)
+var _ APIWithContext = (*Acx)(nil)
+
+func (acx *Acx) withContext(ctx context.Context) *Acx {
+	c := *acx
+	c.httpClient = HttpClientWithContext(ctx, acx.httpClient)
+	return &c
+}
+
+func (acx *Acx) LimitBuyCtx(ctx context.Context, amount, price Decimal, currency CurrencyPair) (*Order, error) {
+	return acx.withContext(ctx).LimitBuy(amount, price, currency)
+}
+
+func (acx *Acx) LimitSellCtx(ctx context.Context, amount, price Decimal, currency CurrencyPair) (*Order, error) {
+	return acx.withContext(ctx).LimitSell(amount, price, currency)
+}
+
+func (acx *Acx) MarketBuyCtx(ctx context.Context, amount, price Decimal, currency CurrencyPair) (*Order, error) {
+	return acx.withContext(ctx).MarketBuy(amount, price, currency)
+}
+
+func (acx *Acx) MarketSellCtx(ctx context.Context, amount, price Decimal, currency CurrencyPair) (*Order, error) {
+	return acx.withContext(ctx).MarketSell(amount, price, currency)
+}
+
+func (acx *Acx) CancelOrderCtx(ctx context.Context, orderId string, currency CurrencyPair) (bool, error) {
+	return acx.withContext(ctx).CancelOrder(orderId, currency)
+}
+
+func (acx *Acx) GetOneOrderCtx(ctx context.Context, orderId string, currency CurrencyPair) (*Order, error) {
+	return acx.withContext(ctx).GetOneOrder(orderId, currency)
+}
+
+func (acx *Acx) GetUnfinishOrdersCtx(ctx context.Context, currency CurrencyPair) ([]Order, error) {
+	return acx.withContext(ctx).GetUnfinishOrders(currency)
+}
+
+func (acx *Acx) GetOrderHistorysCtx(ctx context.Context, currency CurrencyPair, currentPage, pageSize int) ([]Order, error) {
+	return acx.withContext(ctx).GetOrderHistorys(currency, currentPage, pageSize)
+}
+
+func (acx *Acx) GetAccountCtx(ctx context.Context) (*Account, error) {
+	return acx.withContext(ctx).GetAccount()
+}
+
+func (acx *Acx) GetTickerCtx(ctx context.Context, currency CurrencyPair) (*Ticker, error) {
+	return acx.withContext(ctx).GetTicker(currency)
+}
+
+func (acx *Acx) GetDepthCtx(ctx context.Context, size int, currency CurrencyPair) (*Depth, error) {
+	return acx.withContext(ctx).GetDepth(size, currency)
+}
+
+func (acx *Acx) GetKlineRecordsCtx(ctx context.Context, currency CurrencyPair, period, size, since int) ([]Kline, error) {
+	return acx.withContext(ctx).GetKlineRecords(currency, period, size, since)
+}
+
+func (acx *Acx) GetTradesCtx(ctx context.Context, currencyPair CurrencyPair, since int64) ([]Trade, error) {
+	return acx.withContext(ctx).GetTrades(currencyPair, since)
+}
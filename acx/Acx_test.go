@@ -2,16 +2,51 @@ package acx
 
 import (
 	"github.com/nntaoli-project/GoEx"
-	"net/http"
+	"github.com/stretchr/testify/assert"
+	"os"
 	"testing"
 )
 
-var acx = New(http.DefaultClient, "", "")
+var fixtures = goex.NewFixtureTransport("testdata/fixtures.json")
+var acx = New(fixtures.Client(), "", "")
 
 func TestAcx_GetTicker(t *testing.T) {
 	AUD := goex.NewCurrency("AUD", "")
 	BTC_AUD := goex.NewCurrencyPair(goex.BTC, AUD)
 	ticker, err := acx.GetTicker(BTC_AUD)
-	t.Log("err=>", err)
-	t.Log("ticker=>", ticker)
+	assert.Nil(t, err)
+	assert.Equal(t, goex.Ticker{Last: goex.RequireDecimal("8255"), Buy: goex.RequireDecimal("8220"), Sell: goex.RequireDecimal("8269.99"),
+		High: goex.RequireDecimal("8400"), Low: goex.RequireDecimal("8100"), Vol: goex.RequireDecimal("52.1406"), Date: 1529920350}, *ticker)
+}
+
+func TestMain(m *testing.M) {
+	os.Exit(fixtures.Run(m.Run))
+}
+
+func TestAcx_errorWrapper(t *testing.T) {
+	for code, errCode := range map[int]goex.ApiError{
+		1001: goex.EX_ERR_INVALID_CURRENCY_PAIR,
+		2002: goex.EX_ERR_INSUFFICIENT_BALANCE,
+		2003: goex.EX_ERR_CANCEL_ORDER_FAIL,
+		2004: goex.EX_ERR_NOT_FIND_ORDER,
+		2005: goex.EX_ERR_SIGN,
+		2006: goex.EX_ERR_NONCE,
+		2007: goex.EX_ERR_NONCE,
+		2008: goex.EX_ERR_NOT_FIND_APIKEY,
+		1000: goex.API_ERR,
+	} {
+		err := acx.errorWrapper(map[string]interface{}{"code": float64(code), "message": "market does not have a valid value"})
+		assert.True(t, errCode.Is(err), "%d", code)
+	}
+}
+
+func TestAcx_adaptError(t *testing.T) {
+	httpErr := goex.HTTP_ERR_CODE
+	httpErr.OriginErrMsg = `{"error":{"code":2002,"message":"Failed to create order. Reason: cannot lock funds"}}`
+	err := acx.adaptError(httpErr)
+	assert.True(t, goex.EX_ERR_INSUFFICIENT_BALANCE.Is(err))
+	assert.Contains(t, err.Error(), "2002:Failed to create order")
+
+	httpErr.OriginErrMsg = "<html>bad gateway</html>"
+	assert.Equal(t, error(httpErr), acx.adaptError(httpErr))
 }
@@ -1,7 +1,6 @@
 package aex
 
 import (
-	"errors"
 	"fmt"
 	. "github.com/nntaoli-project/GoEx"
 	//"log"
@@ -33,6 +32,7 @@ type Aex struct {
 }
 
 func New(client *http.Client, accessKey, secretKey, accountId string) *Aex {
+	client = ExchangeRateLimitedClient(client, EXCHANGE_NAME, accessKey, DefaultRateLimitConfig)
 	return &Aex{accessKey, secretKey, accountId, client}
 }
 
@@ -40,12 +40,19 @@ func (aex *Aex) GetExchangeName() string {
 	return EXCHANGE_NAME
 }
 
+func (aex *Aex) Capabilities() Capabilities {
+	return Capabilities{}
+}
+
 func (aex *Aex) GetTicker(currency CurrencyPair) (*Ticker, error) {
+	var dp DecimalParser
 	cur := currency.CurrencyA.String()
 	money := currency.CurrencyB.String()
 	if cur == "UNKNOWN" {
 		//log.Println("Unsupport The CurrencyPair")
-		return nil, errors.New("Unsupport The CurrencyPair")
+		errCode := EX_ERR_INVALID_CURRENCY_PAIR
+		errCode.OriginErrMsg = currency.String()
+		return nil, errCode
 	}
 	tickerUri := API_V1 + fmt.Sprintf(TICKER_URI, cur, money)
 	timestamp := time.Now().Unix()
@@ -64,18 +71,23 @@ func (aex *Aex) GetTicker(currency CurrencyPair) (*Ticker, error) {
 	case map[string]interface{}:
 		tickerMap = bodyDataMap["ticker"].(map[string]interface{})
 	default:
-		return nil, errors.New(fmt.Sprintf("Type Convert Error ? \n %s", bodyDataMap))
+		errCode := API_ERR
+		errCode.OriginErrMsg = fmt.Sprintf("Type Convert Error ? \n %s", bodyDataMap)
+		return nil, errCode
 	}
 
 	ticker.Date = uint64(timestamp)
-	ticker.Last = tickerMap["last"].(float64)
-	ticker.Buy = tickerMap["buy"].(float64)
-	ticker.Sell = tickerMap["sell"].(float64)
-	ticker.Low = tickerMap["low"].(float64)
-	ticker.High = tickerMap["high"].(float64)
-	ticker.Vol = tickerMap["vol"].(float64)
+	ticker.Last = dp.Decimal(tickerMap["last"])
+	ticker.Buy = dp.Decimal(tickerMap["buy"])
+	ticker.Sell = dp.Decimal(tickerMap["sell"])
+	ticker.Low = dp.Decimal(tickerMap["low"])
+	ticker.High = dp.Decimal(tickerMap["high"])
+	ticker.Vol = dp.Decimal(tickerMap["vol"])
 	//log.Println("Aex", currency, "ticker:", ticker)
 
+	if dp.Err != nil {
+		return nil, dp.Err
+	}
 	return &ticker, nil
 }
 
@@ -88,49 +100,49 @@ func (aex *Aex) GetTickerInBuf(currency CurrencyPair) (*Ticker, error) {
 }
 
 func (aex *Aex) GetDepth(size int, currency CurrencyPair) (*Depth, error) {
-	panic("not implement")
+	return nil, ErrNotSupported
 }
 
-func (aex *Aex) LimitBuy(amount, price string, currency CurrencyPair) (*Order, error) {
-	panic("not implements")
+func (aex *Aex) LimitBuy(amount, price Decimal, currency CurrencyPair) (*Order, error) {
+	return nil, ErrNotSupported
 }
 
-func (aex *Aex) LimitSell(amount, price string, currency CurrencyPair) (*Order, error) {
-	panic("not implements")
+func (aex *Aex) LimitSell(amount, price Decimal, currency CurrencyPair) (*Order, error) {
+	return nil, ErrNotSupported
 }
 
-func (aex *Aex) MarketBuy(amount, price string, currency CurrencyPair) (*Order, error) {
-	panic("not implements")
+func (aex *Aex) MarketBuy(amount, price Decimal, currency CurrencyPair) (*Order, error) {
+	return nil, ErrNotSupported
 }
 
-func (aex *Aex) MarketSell(amount, price string, currency CurrencyPair) (*Order, error) {
-	panic("not implements")
+func (aex *Aex) MarketSell(amount, price Decimal, currency CurrencyPair) (*Order, error) {
+	return nil, ErrNotSupported
 }
 
 func (aex *Aex) CancelOrder(orderId string, currency CurrencyPair) (bool, error) {
-	panic("not implements")
+	return false, ErrNotSupported
 }
 
 func (aex *Aex) GetOneOrder(orderId string, currency CurrencyPair) (*Order, error) {
-	panic("not implements")
+	return nil, ErrNotSupported
 }
 func (aex *Aex) GetUnfinishOrders(currency CurrencyPair) ([]Order, error) {
-	panic("not implements")
+	return nil, ErrNotSupported
 }
 
 func (aex *Aex) GetOrderHistorys(currency CurrencyPair, currentPage, pageSize int) ([]Order, error) {
-	panic("not implements")
+	return nil, ErrNotSupported
 }
 
 func (aex *Aex) GetAccount() (*Account, error) {
-	panic("not implements")
+	return nil, ErrNotSupported
 }
 
 func (aex *Aex) GetKlineRecords(currency CurrencyPair, period, size, since int) ([]Kline, error) {
-	panic("not implements")
+	return nil, ErrNotSupported
 }
 
 //非个人，整个交易所的交易记录
 func (aex *Aex) GetTrades(currencyPair CurrencyPair, since int64) ([]Trade, error) {
-	panic("not implements")
+	return nil, ErrNotSupported
 }
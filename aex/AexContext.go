@@ -0,0 +1,68 @@
+package aex
+
+import (
+	"context"
+
+	. "github.com/nntaoli-project/GoEx"
+)
+
+var _ API = (*Aex)(nil)
+var _ APIWithContext = (*Aex)(nil)
+
+func (aex *Aex) withContext(ctx context.Context) *Aex {
+	c := *aex
+	c.httpClient = HttpClientWithContext(ctx, aex.httpClient)
+	return &c
+}
+
+func (aex *Aex) LimitBuyCtx(ctx context.Context, amount, price Decimal, currency CurrencyPair) (*Order, error) {
+	return aex.withContext(ctx).LimitBuy(amount, price, currency)
+}
+
+func (aex *Aex) LimitSellCtx(ctx context.Context, amount, price Decimal, currency CurrencyPair) (*Order, error) {
+	return aex.withContext(ctx).LimitSell(amount, price, currency)
+}
+
+func (aex *Aex) MarketBuyCtx(ctx context.Context, amount, price Decimal, currency CurrencyPair) (*Order, error) {
+	return aex.withContext(ctx).MarketBuy(amount, price, currency)
+}
+
+func (aex *Aex) MarketSellCtx(ctx context.Context, amount, price Decimal, currency CurrencyPair) (*Order, error) {
+	return aex.withContext(ctx).MarketSell(amount, price, currency)
+}
+
+func (aex *Aex) CancelOrderCtx(ctx context.Context, orderId string, currency CurrencyPair) (bool, error) {
+	return aex.withContext(ctx).CancelOrder(orderId, currency)
+}
+
+func (aex *Aex) GetOneOrderCtx(ctx context.Context, orderId string, currency CurrencyPair) (*Order, error) {
+	return aex.withContext(ctx).GetOneOrder(orderId, currency)
+}
+
+func (aex *Aex) GetUnfinishOrdersCtx(ctx context.Context, currency CurrencyPair) ([]Order, error) {
+	return aex.withContext(ctx).GetUnfinishOrders(currency)
+}
+
+func (aex *Aex) GetOrderHistorysCtx(ctx context.Context, currency CurrencyPair, currentPage, pageSize int) ([]Order, error) {
+	return aex.withContext(ctx).GetOrderHistorys(currency, currentPage, pageSize)
+}
+
+func (aex *Aex) GetAccountCtx(ctx context.Context) (*Account, error) {
+	return aex.withContext(ctx).GetAccount()
+}
+
+func (aex *Aex) GetTickerCtx(ctx context.Context, currency CurrencyPair) (*Ticker, error) {
+	return aex.withContext(ctx).GetTicker(currency)
+}
+
+func (aex *Aex) GetDepthCtx(ctx context.Context, size int, currency CurrencyPair) (*Depth, error) {
+	return aex.withContext(ctx).GetDepth(size, currency)
+}
+
+func (aex *Aex) GetKlineRecordsCtx(ctx context.Context, currency CurrencyPair, period, size, since int) ([]Kline, error) {
+	return aex.withContext(ctx).GetKlineRecords(currency, period, size, since)
+}
+
+func (aex *Aex) GetTradesCtx(ctx context.Context, currencyPair CurrencyPair, since int64) ([]Trade, error) {
+	return aex.withContext(ctx).GetTrades(currencyPair, since)
+}
@@ -2,14 +2,30 @@ package aex
 
 import (
 	"github.com/nntaoli-project/GoEx"
-	"net/http"
+	"github.com/stretchr/testify/assert"
+	"os"
 	"testing"
 )
 
-var acx = New(http.DefaultClient, "", "", "")
+var fixtures = goex.NewFixtureTransport("testdata/fixtures.json")
+var acx = New(fixtures.Client(), "", "", "")
 
 func TestAex_GetTicker(t *testing.T) {
 	ticker, err := acx.GetTicker(goex.ETH_BTC)
-	t.Log("err=>", err)
-	t.Log("ticker=>", ticker)
+	assert.Nil(t, err)
+	assert.Equal(t, goex.RequireDecimal("0.07278"), ticker.Last)
+	assert.Equal(t, goex.RequireDecimal("0.07262"), ticker.Buy)
+	assert.Equal(t, goex.RequireDecimal("0.07289"), ticker.Sell)
+	assert.Equal(t, goex.RequireDecimal("0.07399"), ticker.High)
+	assert.Equal(t, goex.RequireDecimal("0.0711"), ticker.Low)
+	assert.Equal(t, goex.RequireDecimal("1532.6148"), ticker.Vol)
+}
+
+func TestAex_GetTicker_UnknownPair(t *testing.T) {
+	_, err := acx.GetTicker(goex.UNKNOWN_PAIR)
+	assert.True(t, goex.EX_ERR_INVALID_CURRENCY_PAIR.Is(err))
+}
+
+func TestMain(m *testing.M) {
+	os.Exit(fixtures.Run(m.Run))
 }
@@ -0,0 +1,192 @@
+package backtest
+
+import (
+	. "github.com/nntaoli-project/GoEx"
+	"github.com/nntaoli-project/GoEx/mock"
+	"github.com/nntaoli-project/GoEx/store"
+	"sort"
+	"time"
+)
+
+//Strategy is a step of a strategy, given the API to trade and the time it runs at. It's the same code run
+//live by a loop, here the replay calls it after every moment of the history.
+type Strategy func(api API, now time.Time) error
+
+//Backtest replays the klines, depth snapshots and trades of a pair, recorded with the store package for
+//instance, through a mock.Exchange whose clock is the history's. At every moment something happened it
+//feeds the exchange what did, then runs the strategy against it. GetKlineRecords answers only the candles
+//closed by then, orders fill against the depth and resting ones when the depth or the trades cross them.
+type Backtest struct {
+	Pair     CurrencyPair
+	Period   int //of Klines
+	Klines   []Kline
+	Depths   []store.DepthSnapshot
+	Trades   []Trade
+	Balances map[Currency]Decimal //what the account starts with
+	Fee      TradeFee
+	Latency  time.Duration //how long orders and cancels take to reach the book
+	Slippage Decimal       //a fraction of the price, how much worse taking the depth fills
+}
+
+type EquityPoint struct {
+	Time   int64   //unix milliseconds
+	Equity Decimal //in the quote currency, the base valued at the last price
+}
+
+type Stats struct {
+	Start, End  Decimal //the equity before the first step and after the last
+	Return      float64 //End / Start - 1
+	MaxDrawdown float64 //the largest fall of the equity from a peak, a fraction of the peak
+	Fills       int
+	Volume      Decimal //in the quote currency
+	Fees        Decimal //in the quote currency, those taken in the base valued at the fill's price
+}
+
+type Result struct {
+	Equity []EquityPoint //after every step
+	Trades []MyTrade     //the account's fills
+	Stats  Stats
+}
+
+const (
+	klineEvent = iota //a candle closing, ordered before the depth and trades of the same moment
+	depthEvent
+	tradeEvent
+)
+
+type event struct {
+	at   int64 //unix milliseconds
+	kind int
+	i    int
+}
+
+func (b *Backtest) events() ([]event, error) {
+	var events []event
+	if len(b.Klines) > 0 {
+		periodMs := int64(KlinePeriodDuration(b.Period) / time.Millisecond)
+		if periodMs <= 0 {
+			return nil, UnsupportedKlinePeriod(b.Period)
+		}
+		for i, k := range b.Klines {
+			events = append(events, event{at: k.Timestamp*1000 + periodMs, kind: klineEvent, i: i})
+		}
+	}
+	for i, d := range b.Depths {
+		events = append(events, event{at: d.Time, kind: depthEvent, i: i})
+	}
+	for i, t := range b.Trades {
+		events = append(events, event{at: t.Date, kind: tradeEvent, i: i})
+	}
+	sort.SliceStable(events, func(i, j int) bool {
+		if events[i].at != events[j].at {
+			return events[i].at < events[j].at
+		}
+		return events[i].kind < events[j].kind
+	})
+	return events, nil
+}
+
+//Run replays the history through strategy, stopping at its first error
+func (b *Backtest) Run(strategy Strategy) (*Result, error) {
+	events, err := b.events()
+	if err != nil {
+		return nil, err
+	}
+
+	var clock time.Time
+	ex := mock.New()
+	ex.Fee, ex.Latency, ex.Slippage = b.Fee, b.Latency, b.Slippage
+	ex.Now = func() time.Time { return clock }
+	for currency, amount := range b.Balances {
+		ex.SetBalance(currency, amount)
+	}
+
+	result := new(Result)
+	var price Decimal //the last one, of a trade, a candle's close or the middle of the depth
+	for i := 0; i < len(events); {
+		at := events[i].at
+		clock = time.Unix(0, at*int64(time.Millisecond))
+		for ; i < len(events) && events[i].at == at; i++ {
+			e := events[i]
+			switch e.kind {
+			case klineEvent:
+				k := b.Klines[e.i]
+				ex.AddKlines(b.Pair, b.Period, k)
+				price = NewDecimalFromFloat(k.Close)
+			case depthEvent:
+				dep := &b.Depths[e.i].Depth
+				ex.SetDepth(b.Pair, dep)
+				if len(dep.BidList) > 0 && len(dep.AskList) > 0 {
+					//the sides may be listed either way
+					bid, ask := dep.BidList[0].Price, dep.AskList[0].Price
+					for _, r := range dep.BidList {
+						if r.Price.GreaterThan(bid) {
+							bid = r.Price
+						}
+					}
+					for _, r := range dep.AskList {
+						if r.Price.LessThan(ask) {
+							ask = r.Price
+						}
+					}
+					price = bid.Add(ask).Div(NewDecimalFromInt(2))
+				}
+			case tradeEvent:
+				t := b.Trades[e.i]
+				ex.AddTrades(b.Pair, t)
+				price = t.Price
+			}
+		}
+
+		if len(result.Equity) == 0 {
+			result.Stats.Start = b.equity(ex, price)
+		}
+		if err := strategy(ex, clock); err != nil {
+			return nil, err
+		}
+		result.Equity = append(result.Equity, EquityPoint{Time: at, Equity: b.equity(ex, price)})
+	}
+
+	if result.Trades, err = ex.GetMyTrades(b.Pair, 0, 0); err != nil {
+		return nil, err
+	}
+	result.Stats.summarize(result, b.Pair)
+	return result, nil
+}
+
+//equity is what the account holds of the pair, frozen or not, in the quote currency
+func (b *Backtest) equity(ex *mock.Exchange, price Decimal) Decimal {
+	base, quote := ex.Balance(b.Pair.CurrencyA), ex.Balance(b.Pair.CurrencyB)
+	return quote.Amount.Add(quote.FrozenAmount).Add(base.Amount.Add(base.FrozenAmount).Mul(price))
+}
+
+func (s *Stats) summarize(result *Result, pair CurrencyPair) {
+	s.End = s.Start
+	if n := len(result.Equity); n > 0 {
+		s.End = result.Equity[n-1].Equity
+	}
+	if s.Start.Sign() > 0 {
+		s.Return = s.End.Float64()/s.Start.Float64() - 1
+	}
+
+	peak := s.Start.Float64()
+	for _, p := range result.Equity {
+		equity := p.Equity.Float64()
+		if equity > peak {
+			peak = equity
+		}
+		if peak > 0 && 1-equity/peak > s.MaxDrawdown {
+			s.MaxDrawdown = 1 - equity/peak
+		}
+	}
+
+	s.Fills = len(result.Trades)
+	for _, t := range result.Trades {
+		s.Volume = s.Volume.Add(t.Amount.Mul(t.Price))
+		if t.FeeCurrency == pair.CurrencyB {
+			s.Fees = s.Fees.Add(t.Fee)
+		} else {
+			s.Fees = s.Fees.Add(t.Fee.Mul(t.Price))
+		}
+	}
+}
@@ -0,0 +1,58 @@
+package backtest
+
+import (
+	"testing"
+	"time"
+
+	"github.com/nntaoli-project/GoEx"
+	"github.com/nntaoli-project/GoEx/store"
+	"github.com/stretchr/testify/assert"
+)
+
+var d = goex.RequireDecimal
+
+func TestBacktest_Run(t *testing.T) {
+	bt := &Backtest{Pair: goex.BTC_USDT, Period: goex.KLINE_PERIOD_1MIN,
+		Balances: map[goex.Currency]goex.Decimal{goex.USDT: d("1000")},
+		Fee:      goex.TradeFee{Maker: d("0.001"), Taker: d("0.001")}}
+	for i, close := range []float64{100, 110, 121} {
+		open := int64(1530000000 + 60*i)
+		bt.Klines = append(bt.Klines, goex.Kline{Timestamp: open, Close: close})
+		bt.Depths = append(bt.Depths, store.DepthSnapshot{Time: (open + 60) * 1000, Depth: goex.Depth{
+			AskList: goex.DepthRecords{{Price: goex.NewDecimalFromFloat(close + 1), Amount: d("5")}},
+			BidList: goex.DepthRecords{{Price: goex.NewDecimalFromFloat(close - 1), Amount: d("5")}}}})
+	}
+
+	var steps []int64
+	result, err := bt.Run(func(api goex.API, now time.Time) error {
+		steps = append(steps, now.Unix())
+		//the candles closed by now, never the one still open
+		klines, err := api.GetKlineRecords(goex.BTC_USDT, goex.KLINE_PERIOD_1MIN, 10, 0)
+		assert.Nil(t, err)
+		assert.Equal(t, now.Unix()-60, klines[len(klines)-1].Timestamp)
+
+		switch len(klines) {
+		case 1:
+			_, err = api.MarketBuy(d("1"), goex.Decimal{}, goex.BTC_USDT)
+		case 3:
+			_, err = api.MarketSell(d("0.999"), goex.Decimal{}, goex.BTC_USDT)
+		}
+		return err
+	})
+	assert.Nil(t, err)
+	assert.Equal(t, []int64{1530000060, 1530000120, 1530000180}, steps)
+
+	//bought 1 at 101 paying 0.001 of it, sold the rest at 120
+	assert.Len(t, result.Equity, 3)
+	assert.True(t, result.Equity[0].Equity.Equal(d("998.9")), result.Equity[0].Equity.String())
+	assert.True(t, result.Equity[1].Equity.Equal(d("1008.89")), result.Equity[1].Equity.String())
+	assert.True(t, result.Equity[2].Equity.Equal(d("1018.76012")), result.Equity[2].Equity.String())
+
+	stats := result.Stats
+	assert.True(t, stats.Start.Equal(d("1000")))
+	assert.InDelta(t, 0.01876012, stats.Return, 1e-9)
+	assert.InDelta(t, 0.0011, stats.MaxDrawdown, 1e-9)
+	assert.Equal(t, 2, stats.Fills)
+	assert.True(t, stats.Volume.Equal(d("220.88")), stats.Volume.String())
+	assert.True(t, stats.Fees.Equal(d("0.22088")), stats.Fees.String())
+}
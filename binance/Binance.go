@@ -8,7 +8,9 @@ import (
 	"log"
 	"net/http"
 	"net/url"
+	"sort"
 	"strconv"
+	"strings"
 	"time"
 )
 
@@ -18,6 +20,7 @@ const (
 	API_BASE_URL = "https://www.binance.com/"
 	API_V1       = API_BASE_URL + "api/v1/"
 	API_V3       = API_BASE_URL + "api/v3/"
+	WAPI_V3      = API_BASE_URL + "wapi/v3/"
 
 	TICKER_URI             = "ticker/24hr?symbol=%s"
 	TICKERS_URI            = "ticker/allBookTickers"
@@ -25,12 +28,21 @@ const (
 	ACCOUNT_URI            = "account?"
 	ORDER_URI              = "order?"
 	UNFINISHED_ORDERS_INFO = "openOrders?"
+	USER_DATA_STREAM_URI   = "userDataStream"
+	EXCHANGE_INFO_URI      = "exchangeInfo"
+	KLINE_URI              = "klines?"
+	DEPOSIT_ADDRESS_URI    = "depositAddress.html?"
+	DEPOSIT_HISTORY_URI    = "depositHistory.html?"
+	ASSET_DETAIL_URI       = "assetDetail.html?"
+	MY_TRADES_URI          = "myTrades?"
+	ALL_ORDERS_URI         = "allOrders?"
 )
 
 type Binance struct {
 	accessKey,
 	secretKey string
 	httpClient *http.Client
+	symbols    *SymbolInfoCache
 }
 
 func (bn *Binance) buildParamsSigned(postForm *url.Values) error {
@@ -44,32 +56,41 @@ func (bn *Binance) buildParamsSigned(postForm *url.Values) error {
 }
 
 func New(client *http.Client, api_key, secret_key string) *Binance {
-	return &Binance{api_key, secret_key, client}
+	client = ExchangeRateLimitedClient(client, EXCHANGE_NAME, api_key, RateLimits)
+	return &Binance{accessKey: api_key, secretKey: secret_key, httpClient: client, symbols: new(SymbolInfoCache)}
 }
 
 func (bn *Binance) GetExchangeName() string {
 	return EXCHANGE_NAME
 }
 
+func (bn *Binance) Capabilities() Capabilities {
+	return Capabilities{MarketOrder: true, Kline: true, Deposit: true, ClientOrder: true, TradeFee: true, MyTrades: true, OrderCursor: true}
+}
+
 func (bn *Binance) GetTicker(currency CurrencyPair) (*Ticker, error) {
+	var dp DecimalParser
 	tickerUri := API_V1 + fmt.Sprintf(TICKER_URI, currency.ToSymbol(""))
 	bodyDataMap, err := HttpGet(bn.httpClient, tickerUri)
 
 	if err != nil {
 		log.Println("GetTicker error:", err)
-		return nil, err
+		return nil, bn.adaptError(err)
 	}
 	var tickerMap map[string]interface{} = bodyDataMap
 	var ticker Ticker
 
 	t, _ := tickerMap["closeTime"].(float64)
 	ticker.Date = uint64(t)
-	ticker.Last = ToFloat64(tickerMap["lastPrice"])
-	ticker.Buy = ToFloat64(tickerMap["bidPrice"])
-	ticker.Sell = ToFloat64(tickerMap["askPrice"])
-	ticker.Low = ToFloat64(tickerMap["lowPrice"])
-	ticker.High = ToFloat64(tickerMap["highPrice"])
-	ticker.Vol = ToFloat64(tickerMap["volume"])
+	ticker.Last = dp.Decimal(tickerMap["lastPrice"])
+	ticker.Buy = dp.Decimal(tickerMap["bidPrice"])
+	ticker.Sell = dp.Decimal(tickerMap["askPrice"])
+	ticker.Low = dp.Decimal(tickerMap["lowPrice"])
+	ticker.High = dp.Decimal(tickerMap["highPrice"])
+	ticker.Vol = dp.Decimal(tickerMap["volume"])
+	if dp.Err != nil {
+		return nil, dp.Err
+	}
 	return &ticker, nil
 }
 
@@ -80,15 +101,22 @@ func (bn *Binance) GetDepth(size int, currencyPair CurrencyPair) (*Depth, error)
 		size = 5
 	}
 
+	depth, _, err := bn.getDepth(size, currencyPair)
+	return depth, err
+}
+
+//getDepth also returns lastUpdateId, the sequence number the snapshot is valid at
+func (bn *Binance) getDepth(size int, currencyPair CurrencyPair) (*Depth, int64, error) {
+	var dp DecimalParser
 	apiUrl := fmt.Sprintf(API_V1+DEPTH_URI, currencyPair.ToSymbol(""), size)
 	resp, err := HttpGet(bn.httpClient, apiUrl)
 	if err != nil {
 		log.Println("GetDepth error:", err)
-		return nil, err
+		return nil, 0, bn.adaptError(err)
 	}
 
 	if _, isok := resp["code"]; isok {
-		return nil, errors.New(resp["msg"].(string))
+		return nil, 0, bn.errorWrapper(ToInt(resp["code"]), resp["msg"].(string))
 	}
 
 	bids := resp["bids"].([]interface{})
@@ -101,36 +129,42 @@ func (bn *Binance) GetDepth(size int, currencyPair CurrencyPair) (*Depth, error)
 
 	for _, bid := range bids {
 		_bid := bid.([]interface{})
-		amount := ToFloat64(_bid[1])
-		price := ToFloat64(_bid[0])
+		amount := dp.Decimal(_bid[1])
+		price := dp.Price(_bid[0])
 		dr := DepthRecord{Amount: amount, Price: price}
 		depth.BidList = append(depth.BidList, dr)
 	}
 
 	for _, ask := range asks {
 		_ask := ask.([]interface{})
-		amount := ToFloat64(_ask[1])
-		price := ToFloat64(_ask[0])
+		amount := dp.Decimal(_ask[1])
+		price := dp.Price(_ask[0])
 		dr := DepthRecord{Amount: amount, Price: price}
 		depth.AskList = append(depth.AskList, dr)
 	}
 
-	return depth, nil
+	if dp.Err != nil {
+		return nil, 0, dp.Err
+	}
+	return depth, ToInt64(resp["lastUpdateId"]), nil
 }
 
-func (bn *Binance) placeOrder(amount, price string, pair CurrencyPair, orderType, orderSide string) (*Order, error) {
+func (bn *Binance) placeOrder(amount, price string, pair CurrencyPair, orderType, orderSide, clientOrderId string) (*Order, error) {
+	var dp DecimalParser
 	path := API_V3 + ORDER_URI
 	params := url.Values{}
 	params.Set("symbol", pair.ToSymbol(""))
 	params.Set("side", orderSide)
 	params.Set("type", orderType)
+	if clientOrderId != "" {
+		params.Set("newClientOrderId", clientOrderId)
+	}
 
 	params.Set("quantity", amount)
-	params.Set("type", "LIMIT")
-	params.Set("timeInForce", "GTC")
 
 	switch orderType {
 	case "LIMIT":
+		params.Set("timeInForce", "GTC")
 		params.Set("price", price)
 	}
 
@@ -140,7 +174,7 @@ func (bn *Binance) placeOrder(amount, price string, pair CurrencyPair, orderType
 		map[string]string{"X-MBX-APIKEY": bn.accessKey})
 	//log.Println("resp:", string(resp), "err:", err)
 	if err != nil {
-		return nil, err
+		return nil, bn.adaptError(err)
 	}
 
 	respmap := make(map[string]interface{})
@@ -152,6 +186,9 @@ func (bn *Binance) placeOrder(amount, price string, pair CurrencyPair, orderType
 
 	orderId := ToInt(respmap["orderId"])
 	if orderId <= 0 {
+		if _, isok := respmap["code"]; isok {
+			return nil, bn.errorWrapper(ToInt(respmap["code"]), string(resp))
+		}
 		return nil, errors.New(string(resp))
 	}
 
@@ -160,30 +197,37 @@ func (bn *Binance) placeOrder(amount, price string, pair CurrencyPair, orderType
 		side = SELL
 	}
 
-	return &Order{
-		Currency:   pair,
-		OrderID:    ToInt(orderId),
-		Price:      ToFloat64(price),
-		Amount:     ToFloat64(amount),
-		DealAmount: 0,
-		AvgPrice:   0,
-		Side:       TradeSide(side),
-		Status:     ORDER_UNFINISH,
-		OrderTime:  int(time.Now().Unix())}, nil
+	clientOrderId, _ = respmap["clientOrderId"].(string)
+	ord := &Order{
+		Currency:      pair,
+		OrderID:       ToInt(orderId),
+		ClientOrderID: clientOrderId,
+		Price:         dp.Decimal(price),
+		Amount:        dp.Decimal(amount),
+		DealAmount:    Decimal{},
+		AvgPrice:      Decimal{},
+		Side:          TradeSide(side),
+		Status:        ORDER_UNFINISH,
+		OrderTime:     int(time.Now().Unix())}
+	if dp.Err != nil {
+		return nil, dp.Err
+	}
+	return ord, nil
 }
 
 func (bn *Binance) GetAccount() (*Account, error) {
+	var dp DecimalParser
 	params := url.Values{}
 	bn.buildParamsSigned(&params)
 	path := API_V3 + ACCOUNT_URI + params.Encode()
 	respmap, err := HttpGet2(bn.httpClient, path, map[string]string{"X-MBX-APIKEY": bn.accessKey})
 	if err != nil {
 		log.Println(err)
-		return nil, err
+		return nil, bn.adaptError(err)
 	}
 	//log.Println("respmap:", respmap)
 	if _, isok := respmap["code"]; isok == true {
-		return nil, errors.New(respmap["msg"].(string))
+		return nil, bn.errorWrapper(ToInt(respmap["code"]), respmap["msg"].(string))
 	}
 	acc := Account{}
 	acc.Exchange = bn.GetExchangeName()
@@ -196,35 +240,64 @@ func (bn *Binance) GetAccount() (*Account, error) {
 		currency := NewCurrency(vv["asset"].(string), "")
 		acc.SubAccounts[currency] = SubAccount{
 			Currency:     currency,
-			Amount:       ToFloat64(vv["free"]),
-			ForzenAmount: ToFloat64(vv["locked"]),
+			Amount:       dp.Decimal(vv["free"]),
+			FrozenAmount: dp.Decimal(vv["locked"]),
 		}
 	}
 
+	if dp.Err != nil {
+		return nil, dp.Err
+	}
 	return &acc, nil
 }
 
-func (bn *Binance) LimitBuy(amount, price string, currencyPair CurrencyPair) (*Order, error) {
-	return bn.placeOrder(amount, price, currencyPair, "LIMIT", "BUY")
+func (bn *Binance) LimitBuy(amount, price Decimal, currencyPair CurrencyPair) (*Order, error) {
+	return bn.placeOrder(amount.String(), price.String(), currencyPair, "LIMIT", "BUY", "")
+}
+
+func (bn *Binance) LimitSell(amount, price Decimal, currencyPair CurrencyPair) (*Order, error) {
+	return bn.placeOrder(amount.String(), price.String(), currencyPair, "LIMIT", "SELL", "")
 }
 
-func (bn *Binance) LimitSell(amount, price string, currencyPair CurrencyPair) (*Order, error) {
-	return bn.placeOrder(amount, price, currencyPair, "LIMIT", "SELL")
+func (bn *Binance) MarketBuy(amount, price Decimal, currencyPair CurrencyPair) (*Order, error) {
+	return bn.placeOrder(amount.String(), price.String(), currencyPair, "MARKET", "BUY", "")
 }
 
-func (bn *Binance) MarketBuy(amount, price string, currencyPair CurrencyPair) (*Order, error) {
-	return bn.placeOrder(amount, price, currencyPair, "MARKET", "BUY")
+func (bn *Binance) MarketSell(amount, price Decimal, currencyPair CurrencyPair) (*Order, error) {
+	return bn.placeOrder(amount.String(), price.String(), currencyPair, "MARKET", "SELL", "")
+}
+
+//PlaceOrderWithClientID places the order with newClientOrderId, GetOneOrder and CancelOrder take it as origClientOrderId
+func (bn *Binance) PlaceOrderWithClientID(clientOrderId string, side TradeSide, amount, price Decimal, currencyPair CurrencyPair) (*Order, error) {
+	if !ValidClientOrderID(clientOrderId) {
+		errCode := EX_ERR_INVALID_CLIENT_ORDER_ID
+		errCode.OriginErrMsg = clientOrderId
+		return nil, errCode
+	}
+	orderType, orderSide := "LIMIT", "BUY"
+	if side == BUY_MARKET || side == SELL_MARKET {
+		orderType = "MARKET"
+	}
+	if side == SELL || side == SELL_MARKET {
+		orderSide = "SELL"
+	}
+	return bn.placeOrder(amount.String(), price.String(), currencyPair, orderType, orderSide, clientOrderId)
 }
 
-func (bn *Binance) MarketSell(amount, price string, currencyPair CurrencyPair) (*Order, error) {
-	return bn.placeOrder(amount, price, currencyPair, "MARKET", "SELL")
+//setOrderId sets orderId or, for a client order id, origClientOrderId. Binance's own order ids are integers.
+func (bn *Binance) setOrderId(params *url.Values, orderId string) {
+	if _, err := strconv.ParseInt(orderId, 10, 64); err == nil {
+		params.Set("orderId", orderId)
+	} else {
+		params.Set("origClientOrderId", orderId)
+	}
 }
 
 func (bn *Binance) CancelOrder(orderId string, currencyPair CurrencyPair) (bool, error) {
 	path := API_V3 + ORDER_URI
 	params := url.Values{}
 	params.Set("symbol", currencyPair.ToSymbol(""))
-	params.Set("orderId", orderId)
+	bn.setOrderId(&params, orderId)
 
 	bn.buildParamsSigned(&params)
 
@@ -232,7 +305,7 @@ func (bn *Binance) CancelOrder(orderId string, currencyPair CurrencyPair) (bool,
 
 	//log.Println("resp:", string(resp), "err:", err)
 	if err != nil {
-		return false, err
+		return false, bn.adaptError(err)
 	}
 
 	respmap := make(map[string]interface{})
@@ -244,6 +317,9 @@ func (bn *Binance) CancelOrder(orderId string, currencyPair CurrencyPair) (bool,
 
 	orderIdCanceled := ToInt(respmap["orderId"])
 	if orderIdCanceled <= 0 {
+		if _, isok := respmap["code"]; isok {
+			return false, bn.errorWrapper(ToInt(respmap["code"]), string(resp))
+		}
 		return false, errors.New(string(resp))
 	}
 
@@ -251,12 +327,10 @@ func (bn *Binance) CancelOrder(orderId string, currencyPair CurrencyPair) (bool,
 }
 
 func (bn *Binance) GetOneOrder(orderId string, currencyPair CurrencyPair) (*Order, error) {
+	var dp DecimalParser
 	params := url.Values{}
 	params.Set("symbol", currencyPair.ToSymbol(""))
-	if orderId != "" {
-		params.Set("orderId", orderId)
-	}
-	params.Set("orderId", orderId)
+	bn.setOrderId(&params, orderId)
 
 	bn.buildParamsSigned(&params)
 	path := API_V3 + ORDER_URI + params.Encode()
@@ -264,14 +338,15 @@ func (bn *Binance) GetOneOrder(orderId string, currencyPair CurrencyPair) (*Orde
 	respmap, err := HttpGet2(bn.httpClient, path, map[string]string{"X-MBX-APIKEY": bn.accessKey})
 	//log.Println(respmap)
 	if err != nil {
-		return nil, err
+		return nil, bn.adaptError(err)
 	}
 	status := respmap["status"].(string)
 	side := respmap["side"].(string)
 
 	ord := Order{}
 	ord.Currency = currencyPair
-	ord.OrderID = ToInt(orderId)
+	ord.OrderID = ToInt(respmap["orderId"])
+	ord.ClientOrderID, _ = respmap["clientOrderId"].(string)
 
 	if side == "SELL" {
 		ord.Side = SELL
@@ -279,26 +354,19 @@ func (bn *Binance) GetOneOrder(orderId string, currencyPair CurrencyPair) (*Orde
 		ord.Side = BUY
 	}
 
-	switch status {
-	case "FILLED":
-		ord.Status = ORDER_FINISH
-	case "PARTIALLY_FILLED":
-		ord.Status = ORDER_PART_FINISH
-	case "CANCELED":
-		ord.Status = ORDER_CANCEL
-	case "PENDING_CANCEL":
-		ord.Status = ORDER_CANCEL_ING
-	case "REJECTED":
-		ord.Status = ORDER_REJECT
-	}
+	ord.Status = bn.adaptOrderStatus(status)
 
-	ord.Amount = ToFloat64(respmap["origQty"].(string))
-	ord.Price = ToFloat64(respmap["price"].(string))
+	ord.Amount = dp.Decimal(respmap["origQty"])
+	ord.Price = dp.Decimal(respmap["price"])
 
+	if dp.Err != nil {
+		return nil, dp.Err
+	}
 	return &ord, nil
 }
 
 func (bn *Binance) GetUnfinishOrders(currencyPair CurrencyPair) ([]Order, error) {
+	var dp DecimalParser
 	params := url.Values{}
 	params.Set("symbol", currencyPair.ToSymbol(""))
 
@@ -308,7 +376,7 @@ func (bn *Binance) GetUnfinishOrders(currencyPair CurrencyPair) ([]Order, error)
 	respmap, err := HttpGet3(bn.httpClient, path, map[string]string{"X-MBX-APIKEY": bn.accessKey})
 	//log.Println("respmap", respmap, "err", err)
 	if err != nil {
-		return nil, err
+		return nil, bn.adaptError(err)
 	}
 
 	orders := make([]Order, 0)
@@ -323,24 +391,521 @@ func (bn *Binance) GetUnfinishOrders(currencyPair CurrencyPair) ([]Order, error)
 		orders = append(orders, Order{
 			OrderID:   ToInt(ord["orderId"]),
 			Currency:  currencyPair,
-			Price:     ToFloat64(ord["price"]),
-			Amount:    ToFloat64(ord["origQty"]),
+			Price:     dp.Decimal(ord["price"]),
+			Amount:    dp.Decimal(ord["origQty"]),
 			Side:      TradeSide(orderSide),
 			Status:    ORDER_UNFINISH,
 			OrderTime: ToInt(ord["time"])})
 	}
+	if dp.Err != nil {
+		return nil, dp.Err
+	}
 	return orders, nil
 }
 
+var _INERNAL_KLINE_PERIOD_CONVERTER = map[int]string{
+	KLINE_PERIOD_1MIN:   "1m",
+	KLINE_PERIOD_5MIN:   "5m",
+	KLINE_PERIOD_15MIN:  "15m",
+	KLINE_PERIOD_30MIN:  "30m",
+	KLINE_PERIOD_60MIN:  "1h",
+	KLINE_PERIOD_4H:     "4h",
+	KLINE_PERIOD_1DAY:   "1d",
+	KLINE_PERIOD_1WEEK:  "1w",
+	KLINE_PERIOD_1MONTH: "1M",
+}
+
 func (bn *Binance) GetKlineRecords(currency CurrencyPair, period, size, since int) ([]Kline, error) {
-	panic("not implements")
+	interval, ok := _INERNAL_KLINE_PERIOD_CONVERTER[period]
+	if !ok {
+		return nil, UnsupportedKlinePeriod(period)
+	}
+	params := url.Values{}
+	params.Set("symbol", currency.ToSymbol(""))
+	params.Set("interval", interval)
+	params.Set("limit", strconv.Itoa(size))
+	if since > 0 {
+		params.Set("startTime", strconv.Itoa(since))
+	}
+
+	resp, err := NewHttpRequest(bn.httpClient, "GET", API_V1+KLINE_URI+params.Encode(), "", nil)
+	if err != nil {
+		return nil, bn.adaptError(err)
+	}
+	var records [][]interface{}
+	err = json.Unmarshal(resp, &records)
+	if err != nil {
+		return nil, err
+	}
+
+	//[open time, open, high, low, close, volume, close time, ...]
+	klines := make([]Kline, 0, len(records))
+	for _, r := range records {
+		if len(r) < 6 {
+			continue
+		}
+		klines = append(klines, Kline{
+			Timestamp: ToInt64(r[0]) / 1000,
+			Open:      ToFloat64(r[1]),
+			High:      ToFloat64(r[2]),
+			Low:       ToFloat64(r[3]),
+			Close:     ToFloat64(r[4]),
+			Vol:       ToFloat64(r[5])})
+	}
+	return klines, nil
 }
 
 //非个人，整个交易所的交易记录
 func (bn *Binance) GetTrades(currencyPair CurrencyPair, since int64) ([]Trade, error) {
-	panic("not implements")
+	return nil, ErrNotSupported
 }
 
 func (bn *Binance) GetOrderHistorys(currency CurrencyPair, currentPage, pageSize int) ([]Order, error) {
-	panic("not implements")
+	return nil, ErrNotSupported
+}
+
+//GetOrderHistory pages allOrders by order id, oldest first. The cursor is the id the next page starts at.
+func (bn *Binance) GetOrderHistory(pair CurrencyPair, start, end int64, cursor string) ([]Order, string, error) {
+	var dp DecimalParser
+	const pageSize = 1000
+	params := url.Values{}
+	params.Set("symbol", pair.ToSymbol(""))
+	params.Set("limit", strconv.Itoa(pageSize))
+	if cursor == "" {
+		params.Set("startTime", strconv.FormatInt(start, 10))
+		if end > 0 {
+			params.Set("endTime", strconv.FormatInt(end, 10))
+		}
+	} else {
+		params.Set("orderId", cursor)
+	}
+	bn.buildParamsSigned(&params)
+	path := API_V3 + ALL_ORDERS_URI + params.Encode()
+
+	resp, err := NewHttpRequest(bn.httpClient, "GET", path, "", map[string]string{"X-MBX-APIKEY": bn.accessKey})
+	if err != nil {
+		return nil, "", bn.adaptError(err)
+	}
+	var records []struct {
+		OrderId       int64  `json:"orderId"`
+		ClientOrderId string `json:"clientOrderId"`
+		Price         string `json:"price"`
+		OrigQty       string `json:"origQty"`
+		ExecutedQty   string `json:"executedQty"`
+		QuoteQty      string `json:"cummulativeQuoteQty"`
+		Status        string `json:"status"`
+		Type          string `json:"type"`
+		Side          string `json:"side"`
+		Time          int64  `json:"time"`
+	}
+	err = json.Unmarshal(resp, &records)
+	if err != nil {
+		return nil, "", err
+	}
+
+	orders := make([]Order, 0, len(records))
+	next := ""
+	if len(records) == pageSize {
+		next = strconv.FormatInt(records[len(records)-1].OrderId+1, 10)
+	}
+	for _, r := range records {
+		if r.Time < start {
+			continue
+		}
+		if end > 0 && r.Time > end {
+			next = ""
+			break
+		}
+		ord := Order{
+			OrderID:       int(r.OrderId),
+			ClientOrderID: r.ClientOrderId,
+			Currency:      pair,
+			Price:         dp.Decimal(r.Price),
+			Amount:        dp.Decimal(r.OrigQty),
+			DealAmount:    dp.Decimal(r.ExecutedQty),
+			Status:        bn.adaptOrderStatus(r.Status),
+			OrderTime:     int(r.Time)}
+		if ord.DealAmount.Sign() > 0 {
+			ord.AvgPrice = dp.Decimal(r.QuoteQty).Div(ord.DealAmount)
+		}
+		switch {
+		case r.Side == "BUY" && r.Type == "MARKET":
+			ord.Side = BUY_MARKET
+		case r.Side == "BUY":
+			ord.Side = BUY
+		case r.Type == "MARKET":
+			ord.Side = SELL_MARKET
+		default:
+			ord.Side = SELL
+		}
+		orders = append(orders, ord)
+	}
+	if dp.Err != nil {
+		return nil, "", dp.Err
+	}
+	return orders, next, nil
+}
+
+//GetMyTrades returns the fills from since, binance answers 1000 at most
+func (bn *Binance) GetMyTrades(pair CurrencyPair, since int64, limit int) ([]MyTrade, error) {
+	var dp DecimalParser
+	params := url.Values{}
+	params.Set("symbol", pair.ToSymbol(""))
+	params.Set("startTime", strconv.FormatInt(since, 10))
+	if limit > 0 {
+		params.Set("limit", strconv.Itoa(limit))
+	}
+	bn.buildParamsSigned(&params)
+	path := API_V3 + MY_TRADES_URI + params.Encode()
+
+	resp, err := NewHttpRequest(bn.httpClient, "GET", path, "", map[string]string{"X-MBX-APIKEY": bn.accessKey})
+	if err != nil {
+		return nil, bn.adaptError(err)
+	}
+	var fills []struct {
+		Id              int64  `json:"id"`
+		OrderId         int64  `json:"orderId"`
+		Price           string `json:"price"`
+		Qty             string `json:"qty"`
+		Commission      string `json:"commission"`
+		CommissionAsset string `json:"commissionAsset"`
+		Time            int64  `json:"time"`
+		IsBuyer         bool   `json:"isBuyer"`
+		IsMaker         bool   `json:"isMaker"`
+	}
+	err = json.Unmarshal(resp, &fills)
+	if err != nil {
+		return nil, err
+	}
+
+	trades := make([]MyTrade, 0, len(fills))
+	for _, f := range fills {
+		side := SELL
+		if f.IsBuyer {
+			side = BUY
+		}
+		trades = append(trades, MyTrade{
+			TradeID:     strconv.FormatInt(f.Id, 10),
+			OrderID:     strconv.FormatInt(f.OrderId, 10),
+			Currency:    pair,
+			Side:        TradeSide(side),
+			Price:       dp.Decimal(f.Price),
+			Amount:      dp.Decimal(f.Qty),
+			Fee:         dp.Decimal(f.Commission),
+			FeeCurrency: NewCurrency(f.CommissionAsset, ""),
+			IsMaker:     f.IsMaker,
+			Time:        f.Time})
+	}
+	if dp.Err != nil {
+		return nil, dp.Err
+	}
+	return MyTradesSince(trades, since, limit), nil
+}
+
+//GetMarkets reads the PRICE_FILTER, LOT_SIZE and MIN_NOTIONAL filters of every symbol in trading
+func (bn *Binance) GetMarkets() ([]SymbolInfo, error) {
+	resp, err := NewHttpRequest(bn.httpClient, "GET", API_V1+EXCHANGE_INFO_URI, "", nil)
+	if err != nil {
+		return nil, bn.adaptError(err)
+	}
+
+	var exchangeInfo struct {
+		Symbols []struct {
+			Symbol     string `json:"symbol"`
+			Status     string `json:"status"`
+			BaseAsset  string `json:"baseAsset"`
+			QuoteAsset string `json:"quoteAsset"`
+			Filters    []struct {
+				FilterType  string  `json:"filterType"`
+				MinPrice    Decimal `json:"minPrice"`
+				MaxPrice    Decimal `json:"maxPrice"`
+				TickSize    Decimal `json:"tickSize"`
+				MinQty      Decimal `json:"minQty"`
+				MaxQty      Decimal `json:"maxQty"`
+				StepSize    Decimal `json:"stepSize"`
+				MinNotional Decimal `json:"minNotional"`
+			} `json:"filters"`
+		} `json:"symbols"`
+	}
+	err = json.Unmarshal(resp, &exchangeInfo)
+	if err != nil {
+		log.Println(string(resp))
+		return nil, err
+	}
+
+	markets := make([]SymbolInfo, 0, len(exchangeInfo.Symbols))
+	for _, sym := range exchangeInfo.Symbols {
+		if sym.Status != "TRADING" {
+			continue
+		}
+		info := SymbolInfo{
+			Pair:   NewCurrencyPair(NewCurrency(sym.BaseAsset, ""), NewCurrency(sym.QuoteAsset, "")),
+			Symbol: sym.Symbol}
+		for _, f := range sym.Filters {
+			switch f.FilterType {
+			case "PRICE_FILTER":
+				info.MinPrice, info.MaxPrice, info.TickSize = f.MinPrice, f.MaxPrice, f.TickSize
+			case "LOT_SIZE":
+				info.MinAmount, info.MaxAmount, info.StepSize = f.MinQty, f.MaxQty, f.StepSize
+			case "MIN_NOTIONAL":
+				info.MinNotional = f.MinNotional
+			}
+		}
+		info.PricePrecision = info.TickSize.Places()
+		info.AmountPrecision = info.StepSize.Places()
+		markets = append(markets, info)
+	}
+	return markets, nil
+}
+
+func (bn *Binance) GetSymbolInfo(pair CurrencyPair) (*SymbolInfo, error) {
+	return bn.symbols.Get(pair, bn.GetMarkets)
+}
+
+func (bn *Binance) GetDepositAddress(currency Currency) (*CryptoAddress, error) {
+	params := url.Values{}
+	params.Set("asset", currency.Symbol)
+	bn.buildParamsSigned(&params)
+
+	var resp struct {
+		Success    bool   `json:"success"`
+		Msg        string `json:"msg"`
+		Address    string `json:"address"`
+		AddressTag string `json:"addressTag"`
+	}
+	err := bn.getWapi(DEPOSIT_ADDRESS_URI+params.Encode(), &resp)
+	if err != nil {
+		return nil, err
+	}
+	if !resp.Success || resp.Address == "" {
+		return nil, errors.New(resp.Msg)
+	}
+	return &CryptoAddress{Currency: currency, Address: resp.Address, Tag: resp.AddressTag, ExchangeName: EXCHANGE_NAME}, nil
+}
+
+//GetDeposits pages the deposits of the last 90 days
+func (bn *Binance) GetDeposits(currency Currency, currentPage, pageSize int) ([]Deposit, error) {
+	var dp DecimalParser
+	params := url.Values{}
+	params.Set("asset", currency.Symbol)
+	bn.buildParamsSigned(&params)
+
+	var resp struct {
+		Success     bool   `json:"success"`
+		Msg         string `json:"msg"`
+		DepositList []struct {
+			InsertTime int64   `json:"insertTime"`
+			Amount     float64 `json:"amount"`
+			Address    string  `json:"address"`
+			AddressTag string  `json:"addressTag"`
+			TxId       string  `json:"txId"`
+			Status     int     `json:"status"`
+		} `json:"depositList"`
+	}
+	err := bn.getWapi(DEPOSIT_HISTORY_URI+params.Encode(), &resp)
+	if err != nil {
+		return nil, err
+	}
+	if !resp.Success {
+		return nil, errors.New(resp.Msg)
+	}
+
+	deposits := make([]Deposit, 0, len(resp.DepositList))
+	for _, r := range resp.DepositList {
+		d := Deposit{
+			ID:       r.TxId,
+			Currency: currency,
+			Amount:   dp.Decimal(r.Amount),
+			Address:  r.Address,
+			Tag:      r.AddressTag,
+			TxID:     r.TxId,
+			Time:     r.InsertTime / 1000}
+		//0 pending, 6 credited but not yet withdrawable, 1 success
+		if r.Status == 1 || r.Status == 6 {
+			d.Status = DEPOSIT_COMPLETED
+		}
+		deposits = append(deposits, d)
+	}
+	sort.Slice(deposits, func(i, j int) bool { return deposits[i].Time > deposits[j].Time })
+	if dp.Err != nil {
+		return nil, dp.Err
+	}
+	return DepositsPage(deposits, currentPage, pageSize), nil
+}
+
+//GetTradeFee returns the account's commissions, the same on every pair. Binance gives them in basis points.
+func (bn *Binance) GetTradeFee(pair CurrencyPair) (*TradeFee, error) {
+	var dp DecimalParser
+	params := url.Values{}
+	bn.buildParamsSigned(&params)
+	path := API_V3 + ACCOUNT_URI + params.Encode()
+	respmap, err := HttpGet2(bn.httpClient, path, map[string]string{"X-MBX-APIKEY": bn.accessKey})
+	if err != nil {
+		return nil, bn.adaptError(err)
+	}
+	if _, isok := respmap["code"]; isok {
+		return nil, bn.errorWrapper(ToInt(respmap["code"]), respmap["msg"].(string))
+	}
+	bps := NewDecimalFromInt(10000)
+	fee := &TradeFee{
+		Maker: dp.Decimal(respmap["makerCommission"]).Div(bps),
+		Taker: dp.Decimal(respmap["takerCommission"]).Div(bps)}
+	if dp.Err != nil {
+		return nil, dp.Err
+	}
+	return fee, nil
+}
+
+//GetWithdrawFee returns the withdrawFee of the asset detail
+func (bn *Binance) GetWithdrawFee(currency Currency) (Decimal, error) {
+	params := url.Values{}
+	bn.buildParamsSigned(&params)
+
+	var resp struct {
+		Success     bool   `json:"success"`
+		Msg         string `json:"msg"`
+		AssetDetail map[string]struct {
+			WithdrawFee float64 `json:"withdrawFee"`
+		} `json:"assetDetail"`
+	}
+	err := bn.getWapi(ASSET_DETAIL_URI+params.Encode(), &resp)
+	if err != nil {
+		return Decimal{}, err
+	}
+	if !resp.Success {
+		return Decimal{}, errors.New(resp.Msg)
+	}
+	detail, ok := resp.AssetDetail[currency.Symbol]
+	if !ok {
+		errCode := EX_ERR_UNKNOWN_FEE
+		errCode.OriginErrMsg = EXCHANGE_NAME + " " + currency.Symbol
+		return Decimal{}, errCode
+	}
+	return ToDecimalE(detail.WithdrawFee)
+}
+
+func (bn *Binance) getWapi(uri string, ret interface{}) error {
+	resp, err := NewHttpRequest(bn.httpClient, "GET", WAPI_V3+uri, "", map[string]string{"X-MBX-APIKEY": bn.accessKey})
+	if err != nil {
+		return bn.adaptError(err)
+	}
+	return json.Unmarshal(resp, ret)
+}
+
+//createListenKey opens a user data stream, it expires unless kept alive with keepListenKey
+func (bn *Binance) createListenKey() (string, error) {
+	resp, err := HttpPostForm2(bn.httpClient, API_V1+USER_DATA_STREAM_URI, url.Values{},
+		map[string]string{"X-MBX-APIKEY": bn.accessKey})
+	if err != nil {
+		return "", bn.adaptError(err)
+	}
+
+	respmap := make(map[string]interface{})
+	err = json.Unmarshal(resp, &respmap)
+	if err != nil {
+		log.Println(string(resp))
+		return "", err
+	}
+
+	listenKey, _ := respmap["listenKey"].(string)
+	if listenKey == "" {
+		if _, isok := respmap["code"]; isok {
+			return "", bn.errorWrapper(ToInt(respmap["code"]), string(resp))
+		}
+		return "", errors.New(string(resp))
+	}
+	return listenKey, nil
+}
+
+func (bn *Binance) keepListenKey(listenKey string) error {
+	_, err := NewHttpRequest(bn.httpClient, "PUT", API_V1+USER_DATA_STREAM_URI+"?listenKey="+listenKey, "",
+		map[string]string{"X-MBX-APIKEY": bn.accessKey})
+	return bn.adaptError(err)
+}
+
+func (bn *Binance) closeListenKey(listenKey string) error {
+	_, err := HttpDeleteForm(bn.httpClient, API_V1+USER_DATA_STREAM_URI+"?listenKey="+listenKey, url.Values{},
+		map[string]string{"X-MBX-APIKEY": bn.accessKey})
+	return bn.adaptError(err)
+}
+
+func (bn *Binance) adaptOrderStatus(status string) TradeStatus {
+	switch status {
+	case "FILLED":
+		return ORDER_FINISH
+	case "PARTIALLY_FILLED":
+		return ORDER_PART_FINISH
+	case "CANCELED", "EXPIRED":
+		return ORDER_CANCEL
+	case "PENDING_CANCEL":
+		return ORDER_CANCEL_ING
+	case "REJECTED":
+		return ORDER_REJECT
+	}
+	return ORDER_UNFINISH
+}
+
+//adaptSymbolToCurrencyPair splits a symbol like BTCUSDT at the quote currency
+func (bn *Binance) adaptSymbolToCurrencyPair(symbol string) CurrencyPair {
+	for _, quote := range []string{"USDT", "TUSD", "USDC", "PAX", "BTC", "ETH", "BNB", "XRP"} {
+		if strings.HasSuffix(symbol, quote) && len(symbol) > len(quote) {
+			return NewCurrencyPair(NewCurrency(symbol[:len(symbol)-len(quote)], ""), NewCurrency(quote, ""))
+		}
+	}
+	return UNKNOWN_PAIR
+}
+
+func (bn *Binance) errorWrapper(code int, msg string) ApiError {
+	var errCode ApiError
+	switch code {
+	case -1003, -1015:
+		errCode = EX_ERR_API_LIMIT
+	case -1021:
+		errCode = EX_ERR_NONCE
+	case -1022:
+		errCode = EX_ERR_SIGN
+	case -2014, -2015:
+		errCode = EX_ERR_NOT_FIND_APIKEY
+	case -1013, -1111:
+		errCode = EX_ERR_INVALID_PRECISION
+	case -1121:
+		errCode = EX_ERR_INVALID_CURRENCY_PAIR
+	case -1001, -1016:
+		errCode = EX_ERR_MAINTENANCE
+	case -2013:
+		errCode = EX_ERR_NOT_FIND_ORDER
+	case -2010:
+		if strings.Contains(msg, "insufficient balance") {
+			errCode = EX_ERR_INSUFFICIENT_BALANCE
+		} else {
+			errCode = EX_ERR_PLACE_ORDER_FAIL
+		}
+	case -2011:
+		if strings.Contains(msg, "Unknown order") {
+			errCode = EX_ERR_NOT_FIND_ORDER
+		} else {
+			errCode = EX_ERR_CANCEL_ORDER_FAIL
+		}
+	default:
+		errCode = API_ERR
+	}
+	errCode.OriginErrMsg = msg
+	return errCode
+}
+
+//binance puts {"code":-1121,"msg":"Invalid symbol."} in the body of non-200 responses
+func (bn *Binance) adaptError(err error) error {
+	apiErr, isok := err.(ApiError)
+	if !isok {
+		return err
+	}
+
+	var resp struct {
+		Code int    `json:"code"`
+		Msg  string `json:"msg"`
+	}
+	if json.Unmarshal([]byte(apiErr.OriginErrMsg), &resp) != nil || resp.Code == 0 {
+		return err
+	}
+	return bn.errorWrapper(resp.Code, apiErr.OriginErrMsg)
 }
@@ -0,0 +1,68 @@
+package binance
+
+import (
+	"context"
+
+	. "github.com/nntaoli-project/GoEx"
+)
+
+var _ API = (*Binance)(nil)
+var _ APIWithContext = (*Binance)(nil)
+
+func (bn *Binance) withContext(ctx context.Context) *Binance {
+	c := *bn
+	c.httpClient = HttpClientWithContext(ctx, bn.httpClient)
+	return &c
+}
+
+func (bn *Binance) LimitBuyCtx(ctx context.Context, amount, price Decimal, currency CurrencyPair) (*Order, error) {
+	return bn.withContext(ctx).LimitBuy(amount, price, currency)
+}
+
+func (bn *Binance) LimitSellCtx(ctx context.Context, amount, price Decimal, currency CurrencyPair) (*Order, error) {
+	return bn.withContext(ctx).LimitSell(amount, price, currency)
+}
+
+func (bn *Binance) MarketBuyCtx(ctx context.Context, amount, price Decimal, currency CurrencyPair) (*Order, error) {
+	return bn.withContext(ctx).MarketBuy(amount, price, currency)
+}
+
+func (bn *Binance) MarketSellCtx(ctx context.Context, amount, price Decimal, currency CurrencyPair) (*Order, error) {
+	return bn.withContext(ctx).MarketSell(amount, price, currency)
+}
+
+func (bn *Binance) CancelOrderCtx(ctx context.Context, orderId string, currency CurrencyPair) (bool, error) {
+	return bn.withContext(ctx).CancelOrder(orderId, currency)
+}
+
+func (bn *Binance) GetOneOrderCtx(ctx context.Context, orderId string, currency CurrencyPair) (*Order, error) {
+	return bn.withContext(ctx).GetOneOrder(orderId, currency)
+}
+
+func (bn *Binance) GetUnfinishOrdersCtx(ctx context.Context, currency CurrencyPair) ([]Order, error) {
+	return bn.withContext(ctx).GetUnfinishOrders(currency)
+}
+
+func (bn *Binance) GetOrderHistorysCtx(ctx context.Context, currency CurrencyPair, currentPage, pageSize int) ([]Order, error) {
+	return bn.withContext(ctx).GetOrderHistorys(currency, currentPage, pageSize)
+}
+
+func (bn *Binance) GetAccountCtx(ctx context.Context) (*Account, error) {
+	return bn.withContext(ctx).GetAccount()
+}
+
+func (bn *Binance) GetTickerCtx(ctx context.Context, currency CurrencyPair) (*Ticker, error) {
+	return bn.withContext(ctx).GetTicker(currency)
+}
+
+func (bn *Binance) GetDepthCtx(ctx context.Context, size int, currency CurrencyPair) (*Depth, error) {
+	return bn.withContext(ctx).GetDepth(size, currency)
+}
+
+func (bn *Binance) GetKlineRecordsCtx(ctx context.Context, currency CurrencyPair, period, size, since int) ([]Kline, error) {
+	return bn.withContext(ctx).GetKlineRecords(currency, period, size, since)
+}
+
+func (bn *Binance) GetTradesCtx(ctx context.Context, currencyPair CurrencyPair, since int64) ([]Trade, error) {
+	return bn.withContext(ctx).GetTrades(currencyPair, since)
+}
@@ -0,0 +1,56 @@
+package binance
+
+import (
+	"net/http"
+	"strings"
+	"time"
+
+	. "github.com/nntaoli-project/GoEx"
+)
+
+//RateLimits binance weighs every request against 1200 per minute and allows 10 orders per second
+var RateLimits = RateLimitConfig{
+	Limits: []RateLimit{
+		{Classes: []EndpointClass{ENDPOINT_PUBLIC, ENDPOINT_PRIVATE, ENDPOINT_ORDER}, Limit: 1200, Interval: time.Minute},
+		{Classes: []EndpointClass{ENDPOINT_ORDER}, Limit: 10, Interval: time.Second}},
+	Classify: classifyEndpoint}
+
+//classifyEndpoint weights as in the binance api docs
+func classifyEndpoint(req *http.Request) (EndpointClass, int) {
+	path := req.URL.Path
+	query := req.URL.Query()
+	switch {
+	case strings.HasSuffix(path, "/order"):
+		if req.Method == "GET" {
+			return ENDPOINT_PRIVATE, 1
+		}
+		return ENDPOINT_ORDER, 1
+	case strings.HasSuffix(path, "/openOrders"):
+		if query.Get("symbol") == "" {
+			return ENDPOINT_PRIVATE, 40
+		}
+		return ENDPOINT_PRIVATE, 1
+	case strings.HasSuffix(path, "/account"), strings.HasSuffix(path, "/allOrders"), strings.HasSuffix(path, "/myTrades"):
+		return ENDPOINT_PRIVATE, 5
+	case strings.HasSuffix(path, "/historicalTrades"):
+		return ENDPOINT_PUBLIC, 5
+	case strings.HasSuffix(path, "/"+USER_DATA_STREAM_URI):
+		return ENDPOINT_PRIVATE, 1
+	case strings.HasSuffix(path, "/depth"):
+		switch limit := ToInt(query.Get("limit")); {
+		case limit > 500:
+			return ENDPOINT_PUBLIC, 10
+		case limit > 100:
+			return ENDPOINT_PUBLIC, 5
+		}
+		return ENDPOINT_PUBLIC, 1
+	case strings.HasSuffix(path, "/ticker/24hr"):
+		if query.Get("symbol") == "" {
+			return ENDPOINT_PUBLIC, 40
+		}
+		return ENDPOINT_PUBLIC, 1
+	case strings.HasSuffix(path, "/ticker/allBookTickers"):
+		return ENDPOINT_PUBLIC, 2
+	}
+	return ClassifyEndpoint(req)
+}
@@ -0,0 +1,199 @@
+package binance
+
+import (
+	"encoding/json"
+	. "github.com/nntaoli-project/GoEx"
+	"log"
+	"net/http"
+	"sync"
+	"time"
+)
+
+const (
+	WS_USER_BASE_URL = "wss://stream.binance.com:9443/ws/"
+
+	listenKeyKeepAliveInterval = 30 * time.Minute //binance drops a listen key after 60 minutes
+)
+
+//BinanceUserWs implements UserStreamAPI on the user data stream of a listen key
+type BinanceUserWs struct {
+	wsUrl     string
+	restApi   *Binance
+	wsConn    *WsConn
+	listenKey string
+	l         sync.Mutex
+	orders    chan Order
+	balances  chan SubAccount
+	closeCh   chan struct{}
+	once      sync.Once
+}
+
+func NewBinanceUserWs(client *http.Client, accessKey, secretKey string) *BinanceUserWs {
+	return &BinanceUserWs{
+		wsUrl:   WS_USER_BASE_URL,
+		restApi: New(client, accessKey, secretKey),
+		closeCh: make(chan struct{})}
+}
+
+func (bnUserWs *BinanceUserWs) GetExchangeName() string {
+	return EXCHANGE_NAME
+}
+
+func (bnUserWs *BinanceUserWs) SubscribeOrders() (<-chan Order, error) {
+	bnUserWs.l.Lock()
+	defer bnUserWs.l.Unlock()
+	if err := bnUserWs.connect(); err != nil {
+		return nil, err
+	}
+	if bnUserWs.orders == nil {
+		bnUserWs.orders = make(chan Order, 64)
+	}
+	return bnUserWs.orders, nil
+}
+
+func (bnUserWs *BinanceUserWs) SubscribeBalances() (<-chan SubAccount, error) {
+	bnUserWs.l.Lock()
+	defer bnUserWs.l.Unlock()
+	if err := bnUserWs.connect(); err != nil {
+		return nil, err
+	}
+	if bnUserWs.balances == nil {
+		bnUserWs.balances = make(chan SubAccount, 64)
+	}
+	return bnUserWs.balances, nil
+}
+
+//Close also deletes the listen key
+func (bnUserWs *BinanceUserWs) Close() error {
+	bnUserWs.once.Do(func() { close(bnUserWs.closeCh) })
+	bnUserWs.l.Lock()
+	wsConn, listenKey := bnUserWs.wsConn, bnUserWs.listenKey
+	bnUserWs.l.Unlock()
+	if wsConn == nil {
+		return nil
+	}
+	if err := bnUserWs.restApi.closeListenKey(listenKey); err != nil {
+		log.Println("[binance user ws] close listen key error:", err)
+	}
+	return wsConn.Close()
+}
+
+//connect opens the stream on the first subscription, l must be held
+func (bnUserWs *BinanceUserWs) connect() error {
+	if bnUserWs.wsConn != nil {
+		return nil
+	}
+
+	listenKey, err := bnUserWs.restApi.createListenKey()
+	if err != nil {
+		return err
+	}
+
+	//the stream is silent while nothing changes, so no ReadTimeout; binance pings at the websocket level
+	wsConn := NewWsConn(bnUserWs.wsUrl+listenKey, bnUserWs.handle)
+	wsConn.CloseHandleFunc = bnUserWs.closeChans
+	bnUserWs.wsConn, bnUserWs.listenKey = wsConn, listenKey
+	if err := wsConn.Connect(); err != nil {
+		bnUserWs.wsConn = nil
+		return err
+	}
+	go bnUserWs.keepAlive(listenKey)
+	return nil
+}
+
+func (bnUserWs *BinanceUserWs) keepAlive(listenKey string) {
+	ticker := time.NewTicker(listenKeyKeepAliveInterval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-bnUserWs.closeCh:
+			return
+		case <-ticker.C:
+			if err := bnUserWs.restApi.keepListenKey(listenKey); err != nil {
+				log.Println("[binance user ws] keep listen key alive error:", err)
+			}
+		}
+	}
+}
+
+func (bnUserWs *BinanceUserWs) handle(msg []byte) error {
+	var event map[string]interface{}
+	if err := json.Unmarshal(msg, &event); err != nil {
+		return err
+	}
+
+	bnUserWs.l.Lock()
+	orders, balances := bnUserWs.orders, bnUserWs.balances
+	bnUserWs.l.Unlock()
+
+	switch event["e"] {
+	case "executionReport":
+		if orders == nil {
+			return nil
+		}
+		ord, err := bnUserWs.parseOrder(event)
+		if err != nil {
+			return err
+		}
+		select {
+		case orders <- ord:
+		case <-bnUserWs.closeCh:
+		}
+	case "outboundAccountInfo", "outboundAccountPosition":
+		if balances == nil {
+			return nil
+		}
+		assets, _ := event["B"].([]interface{})
+		for _, v := range assets {
+			asset, isok := v.(map[string]interface{})
+			if !isok {
+				continue
+			}
+			var dp DecimalParser
+			currency, _ := asset["a"].(string)
+			sub := SubAccount{Currency: NewCurrency(currency, ""), Amount: dp.Decimal(asset["f"]), FrozenAmount: dp.Decimal(asset["l"])}
+			if dp.Err != nil {
+				return dp.Err
+			}
+			select {
+			case balances <- sub:
+			case <-bnUserWs.closeCh:
+				return nil
+			}
+		}
+	}
+	return nil
+}
+
+func (bnUserWs *BinanceUserWs) parseOrder(event map[string]interface{}) (Order, error) {
+	var dp DecimalParser
+	symbol, _ := event["s"].(string)
+	status, _ := event["X"].(string)
+	ord := Order{
+		OrderID:    ToInt(event["i"]),
+		Currency:   bnUserWs.restApi.adaptSymbolToCurrencyPair(symbol),
+		Price:      dp.Decimal(event["p"]),
+		Amount:     dp.Decimal(event["q"]),
+		DealAmount: dp.Decimal(event["z"]), //cumulative, "l" is the last fill only
+		Status:     bnUserWs.restApi.adaptOrderStatus(status),
+		Side:       BUY,
+		OrderTime:  ToInt(event["O"])}
+	if event["S"] == "SELL" {
+		ord.Side = SELL
+	}
+	if ord.DealAmount.Sign() > 0 {
+		ord.AvgPrice = dp.Decimal(event["Z"]).Div(ord.DealAmount)
+	}
+	return ord, dp.Err
+}
+
+func (bnUserWs *BinanceUserWs) closeChans() {
+	bnUserWs.l.Lock()
+	defer bnUserWs.l.Unlock()
+	if bnUserWs.orders != nil {
+		close(bnUserWs.orders)
+	}
+	if bnUserWs.balances != nil {
+		close(bnUserWs.balances)
+	}
+}
@@ -0,0 +1,71 @@
+package binance
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/gorilla/websocket"
+	. "github.com/nntaoli-project/GoEx"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestBinanceUserWs(t *testing.T) {
+	upgrader := websocket.Upgrader{}
+	closed := make(chan string, 1)
+	subscribed := make(chan struct{})
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch {
+		case r.URL.Path == "/api/v1/userDataStream" && r.Method == "POST":
+			assert.Equal(t, "key", r.Header.Get("X-MBX-APIKEY"))
+			w.Write([]byte(`{"listenKey":"lk1"}`))
+		case r.URL.Path == "/api/v1/userDataStream" && r.Method == "DELETE":
+			r.ParseForm()
+			closed <- r.Form.Get("listenKey")
+			w.Write([]byte(`{}`))
+		case r.URL.Path == "/ws/lk1":
+			conn, err := upgrader.Upgrade(w, r, nil)
+			if err != nil {
+				t.Error(err)
+				return
+			}
+			defer conn.Close()
+			<-subscribed
+			conn.WriteMessage(websocket.TextMessage, []byte(`{"e":"executionReport","E":1530000000100,"s":"BTCUSDT","S":"SELL","o":"LIMIT",`+
+				`"q":"1.00000000","p":"6100.00000000","X":"PARTIALLY_FILLED","i":4293153,"l":"0.25000000","z":"0.50000000","Z":"3050.50000000","O":1530000000000}`))
+			conn.WriteMessage(websocket.TextMessage, []byte(`{"e":"outboundAccountPosition","E":1530000000101,`+
+				`"B":[{"a":"BTC","f":"9.5","l":"0.5"},{"a":"USDT","f":"3050.5","l":"0"}]}`))
+			conn.ReadMessage()
+		default:
+			t.Error("unexpected request", r.Method, r.URL)
+		}
+	}))
+	defer srv.Close()
+
+	bnUserWs := NewBinanceUserWs(&http.Client{Transport: rewriteTransport{strings.TrimPrefix(srv.URL, "http://")}}, "key", "secret")
+	bnUserWs.wsUrl = "ws" + strings.TrimPrefix(srv.URL, "http") + "/ws/"
+
+	balances, err := bnUserWs.SubscribeBalances()
+	assert.Nil(t, err)
+	orders, err := bnUserWs.SubscribeOrders()
+	assert.Nil(t, err)
+	close(subscribed)
+
+	assert.Equal(t, Order{OrderID: 4293153, Currency: BTC_USDT, Price: RequireDecimal("6100"), Amount: RequireDecimal("1"), DealAmount: RequireDecimal("0.5"), AvgPrice: RequireDecimal("6101"),
+		Status: ORDER_PART_FINISH, Side: SELL, OrderTime: 1530000000000}, <-orders)
+	assert.Equal(t, SubAccount{Currency: BTC, Amount: RequireDecimal("9.5"), FrozenAmount: RequireDecimal("0.5")}, <-balances)
+	assert.Equal(t, SubAccount{Currency: USDT, Amount: RequireDecimal("3050.5")}, <-balances)
+
+	assert.Nil(t, bnUserWs.Close())
+	assert.Equal(t, "lk1", <-closed)
+	for range orders {
+	}
+}
+
+func TestBinance_adaptSymbolToCurrencyPair(t *testing.T) {
+	bn := New(http.DefaultClient, "", "")
+	assert.Equal(t, BTC_USDT, bn.adaptSymbolToCurrencyPair("BTCUSDT"))
+	assert.Equal(t, ETH_BTC, bn.adaptSymbolToCurrencyPair("ETHBTC"))
+	assert.Equal(t, UNKNOWN_PAIR, bn.adaptSymbolToCurrencyPair("BTC"))
+}
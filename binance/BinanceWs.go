@@ -0,0 +1,305 @@
+package binance
+
+import (
+	"encoding/json"
+	"fmt"
+	. "github.com/nntaoli-project/GoEx"
+	"log"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+)
+
+const (
+	WS_BASE_URL = "wss://stream.binance.com:9443/stream"
+)
+
+var wsKlinePeriods = map[int]string{
+	KLINE_PERIOD_1MIN:   "1m",
+	KLINE_PERIOD_5MIN:   "5m",
+	KLINE_PERIOD_15MIN:  "15m",
+	KLINE_PERIOD_30MIN:  "30m",
+	KLINE_PERIOD_60MIN:  "1h",
+	KLINE_PERIOD_4H:     "4h",
+	KLINE_PERIOD_1DAY:   "1d",
+	KLINE_PERIOD_1WEEK:  "1w",
+	KLINE_PERIOD_1MONTH: "1M",
+}
+
+//BinanceWs implements StreamAPI on top of the combined stream endpoint
+type BinanceWs struct {
+	wsUrl   string
+	restApi *Binance //order book snapshots
+	wsConn  *WsConn
+	l       sync.Mutex
+	id      int
+	chans   map[string]interface{}
+	handles map[string]func(data map[string]interface{}) error
+	closers []func()
+	closeCh chan struct{}
+	once    sync.Once
+}
+
+func NewBinanceWs() *BinanceWs {
+	return &BinanceWs{
+		wsUrl:   WS_BASE_URL,
+		restApi: New(http.DefaultClient, "", ""),
+		chans:   make(map[string]interface{}),
+		handles: make(map[string]func(data map[string]interface{}) error),
+		closeCh: make(chan struct{})}
+}
+
+func (bnWs *BinanceWs) GetExchangeName() string {
+	return EXCHANGE_NAME
+}
+
+func (bnWs *BinanceWs) SubscribeTicker(pair CurrencyPair) (<-chan Ticker, error) {
+	ch := make(chan Ticker, 64)
+	c, err := bnWs.subscribe(bnWs.streamName(pair, "ticker"), ch, func() { close(ch) }, func(data map[string]interface{}) error {
+		var dp DecimalParser
+		ticker := Ticker{
+			Last: dp.Decimal(data["c"]),
+			Buy:  dp.Decimal(data["b"]),
+			Sell: dp.Decimal(data["a"]),
+			High: dp.Decimal(data["h"]),
+			Low:  dp.Decimal(data["l"]),
+			Vol:  dp.Decimal(data["v"]),
+			Date: ToUint64(data["E"])}
+		if dp.Err != nil {
+			return dp.Err
+		}
+		select {
+		case ch <- ticker:
+		case <-bnWs.closeCh:
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return c.(chan Ticker), nil
+}
+
+//size is rounded up to one of the partial book levels binance pushes: 5, 10 or 20
+func (bnWs *BinanceWs) SubscribeDepth(pair CurrencyPair, size int) (<-chan Depth, error) {
+	level := 20
+	if size <= 5 {
+		level = 5
+	} else if size <= 10 {
+		level = 10
+	}
+
+	ch := make(chan Depth, 64)
+	c, err := bnWs.subscribe(bnWs.streamName(pair, fmt.Sprintf("depth%d", level)), ch, func() { close(ch) }, func(data map[string]interface{}) error {
+		var dp DecimalParser
+		depth := Depth{
+			AskList: bnWs.parseDepthRecords(&dp, data["asks"], size),
+			BidList: bnWs.parseDepthRecords(&dp, data["bids"], size)}
+		if dp.Err != nil {
+			return dp.Err
+		}
+		select {
+		case ch <- depth:
+		case <-bnWs.closeCh:
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return c.(chan Depth), nil
+}
+
+func (bnWs *BinanceWs) SubscribeTrades(pair CurrencyPair) (<-chan Trade, error) {
+	ch := make(chan Trade, 64)
+	c, err := bnWs.subscribe(bnWs.streamName(pair, "trade"), ch, func() { close(ch) }, func(data map[string]interface{}) error {
+		var dp DecimalParser
+		trade := Trade{
+			Tid:    ToInt64(data["t"]),
+			Type:   "buy",
+			Amount: dp.Decimal(data["q"]),
+			Price:  dp.Decimal(data["p"]),
+			Date:   ToInt64(data["T"])}
+		if isMaker, _ := data["m"].(bool); isMaker {
+			trade.Type = "sell"
+		}
+		if dp.Err != nil {
+			return dp.Err
+		}
+		select {
+		case ch <- trade:
+		case <-bnWs.closeCh:
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return c.(chan Trade), nil
+}
+
+func (bnWs *BinanceWs) SubscribeKline(pair CurrencyPair, period int) (<-chan Kline, error) {
+	interval, isok := wsKlinePeriods[period]
+	if !isok {
+		return nil, ErrNotSupported
+	}
+
+	ch := make(chan Kline, 64)
+	c, err := bnWs.subscribe(bnWs.streamName(pair, "kline_"+interval), ch, func() { close(ch) }, func(data map[string]interface{}) error {
+		k, isok := data["k"].(map[string]interface{})
+		if !isok {
+			return nil
+		}
+		kline := Kline{
+			Timestamp: ToInt64(k["t"]) / 1000,
+			Open:      ToFloat64(k["o"]),
+			Close:     ToFloat64(k["c"]),
+			High:      ToFloat64(k["h"]),
+			Low:       ToFloat64(k["l"]),
+			Vol:       ToFloat64(k["v"])}
+		select {
+		case ch <- kline:
+		case <-bnWs.closeCh:
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return c.(chan Kline), nil
+}
+
+//SubscribeOrderBook keeps a full local book from the diff depth stream, synced from REST snapshots
+func (bnWs *BinanceWs) SubscribeOrderBook(pair CurrencyPair) (*OrderBook, error) {
+	ob := NewOrderBook()
+	ob.SnapshotFunc = func() (*Depth, int64, error) {
+		return bnWs.restApi.getDepth(1000, pair)
+	}
+	c, err := bnWs.subscribe(bnWs.streamName(pair, "depth"), ob, func() {}, func(data map[string]interface{}) error {
+		var dp DecimalParser
+		update := OrderBookUpdate{
+			FirstSeq: ToInt64(data["U"]),
+			LastSeq:  ToInt64(data["u"]),
+			Bids:     bnWs.parseDepthRecords(&dp, data["b"], 0),
+			Asks:     bnWs.parseDepthRecords(&dp, data["a"], 0)}
+		if dp.Err != nil {
+			return dp.Err
+		}
+		if err := ob.Update(update); err != nil {
+			log.Println("[binance ws] order book", pair, err)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return c.(*OrderBook), nil
+}
+
+func (bnWs *BinanceWs) Close() error {
+	bnWs.once.Do(func() { close(bnWs.closeCh) })
+	bnWs.l.Lock()
+	wsConn := bnWs.wsConn
+	bnWs.l.Unlock()
+	if wsConn == nil {
+		return nil
+	}
+	return wsConn.Close()
+}
+
+func (bnWs *BinanceWs) streamName(pair CurrencyPair, stream string) string {
+	return strings.ToLower(pair.ToSymbol("")) + "@" + stream
+}
+
+func (bnWs *BinanceWs) subscribe(stream string, ch interface{}, closer func(), handle func(data map[string]interface{}) error) (interface{}, error) {
+	bnWs.l.Lock()
+	defer bnWs.l.Unlock()
+
+	select {
+	case <-bnWs.closeCh:
+		return nil, EX_ERR_STREAM_CLOSED
+	default:
+	}
+	if c, isok := bnWs.chans[stream]; isok {
+		return c, nil
+	}
+
+	if bnWs.wsConn == nil {
+		wsConn := NewWsConn(bnWs.wsUrl, bnWs.handle)
+		wsConn.ReadTimeout = 5 * time.Minute
+		wsConn.CloseHandleFunc = bnWs.closeChans
+		if err := wsConn.Connect(); err != nil {
+			return nil, err
+		}
+		bnWs.wsConn = wsConn
+	}
+
+	bnWs.chans[stream] = ch
+	bnWs.handles[stream] = handle
+	bnWs.closers = append(bnWs.closers, closer)
+
+	bnWs.id++
+	bnWs.wsConn.Subscribe(map[string]interface{}{
+		"method": "SUBSCRIBE",
+		"params": []string{stream},
+		"id":     bnWs.id})
+	return ch, nil
+}
+
+func (bnWs *BinanceWs) handle(msg []byte) error {
+	var resp struct {
+		Stream string                 `json:"stream"`
+		Data   map[string]interface{} `json:"data"`
+		Error  map[string]interface{} `json:"error"`
+	}
+	if err := json.Unmarshal(msg, &resp); err != nil {
+		return err
+	}
+
+	if resp.Error != nil {
+		return bnWs.errorWrapper(ToInt(resp.Error["code"]), fmt.Sprint(resp.Error["msg"]))
+	}
+
+	bnWs.l.Lock()
+	handle := bnWs.handles[resp.Stream]
+	bnWs.l.Unlock()
+
+	if handle == nil {
+		if resp.Stream != "" {
+			log.Println("[binance ws] unknown stream:", resp.Stream)
+		}
+		return nil
+	}
+	return handle(resp.Data)
+}
+
+func (bnWs *BinanceWs) errorWrapper(code int, msg string) ApiError {
+	return (&Binance{}).errorWrapper(code, msg)
+}
+
+//size <= 0 keeps every record
+func (bnWs *BinanceWs) parseDepthRecords(dp *DecimalParser, records interface{}, size int) DepthRecords {
+	var drs DepthRecords
+	list, _ := records.([]interface{})
+	for _, r := range list {
+		if size > 0 && len(drs) >= size {
+			break
+		}
+		_r, isok := r.([]interface{})
+		if !isok || len(_r) < 2 {
+			continue
+		}
+		drs = append(drs, DepthRecord{Price: dp.Price(_r[0]), Amount: dp.Decimal(_r[1])})
+	}
+	return drs
+}
+
+func (bnWs *BinanceWs) closeChans() {
+	bnWs.l.Lock()
+	defer bnWs.l.Unlock()
+	for _, closer := range bnWs.closers {
+		closer()
+	}
+	bnWs.closers = nil
+}
@@ -0,0 +1,206 @@
+package binance
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/gorilla/websocket"
+	. "github.com/nntaoli-project/GoEx"
+	"github.com/stretchr/testify/assert"
+)
+
+//newWsStandIn answers every SUBSCRIBE with the canned pushes of each stream. The first
+//connection is dropped after its first push so the client has to reconnect and resubscribe.
+func newWsStandIn(t *testing.T, pushes map[string][]string) (*httptest.Server, chan string) {
+	subscribed := make(chan string, 16)
+	upgrader := websocket.Upgrader{}
+	var conns int32
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		conn, err := upgrader.Upgrade(w, r, nil)
+		if err != nil {
+			t.Error(err)
+			return
+		}
+		defer conn.Close()
+		first := atomic.AddInt32(&conns, 1) == 1
+		for {
+			var req struct {
+				Method string   `json:"method"`
+				Params []string `json:"params"`
+				Id     int      `json:"id"`
+			}
+			if err := conn.ReadJSON(&req); err != nil {
+				return
+			}
+			conn.WriteJSON(map[string]interface{}{"result": nil, "id": req.Id})
+			for _, stream := range req.Params {
+				select {
+				case subscribed <- stream:
+				default:
+				}
+				for _, push := range pushes[stream] {
+					conn.WriteMessage(websocket.TextMessage, []byte(`{"stream":"`+stream+`","data":`+push+`}`))
+				}
+			}
+			if first {
+				return
+			}
+		}
+	}))
+	return srv, subscribed
+}
+
+func newTestBinanceWs(srv *httptest.Server) *BinanceWs {
+	bnWs := NewBinanceWs()
+	bnWs.wsUrl = "ws" + strings.TrimPrefix(srv.URL, "http")
+	return bnWs
+}
+
+func TestBinanceWs_SubscribeTicker(t *testing.T) {
+	srv, subscribed := newWsStandIn(t, map[string][]string{
+		"btcusdt@ticker": {`{"e":"24hrTicker","E":1530000000000,"s":"BTCUSDT","c":"6100.5","b":"6100.1","a":"6100.9","h":"6200","l":"6000","v":"1234.5"}`}})
+	defer srv.Close()
+
+	bnWs := newTestBinanceWs(srv)
+	ch, err := bnWs.SubscribeTicker(BTC_USDT)
+	assert.Nil(t, err)
+
+	ticker := <-ch
+	assert.Equal(t, RequireDecimal("6100.5"), ticker.Last)
+	assert.Equal(t, RequireDecimal("6100.1"), ticker.Buy)
+	assert.Equal(t, RequireDecimal("6100.9"), ticker.Sell)
+	assert.Equal(t, uint64(1530000000000), ticker.Date)
+	assert.Equal(t, "btcusdt@ticker", <-subscribed)
+
+	//the stand-in hung up, so the same stream must be subscribed again on the new connection
+	select {
+	case stream := <-subscribed:
+		assert.Equal(t, "btcusdt@ticker", stream)
+	case <-time.After(5 * time.Second):
+		t.Fatal("not resubscribed after reconnect")
+	}
+	<-ch
+
+	again, _ := bnWs.SubscribeTicker(BTC_USDT)
+	assert.True(t, (<-chan Ticker)(ch) == again)
+
+	assert.Nil(t, bnWs.Close())
+	for range ch {
+	}
+
+	_, err = bnWs.SubscribeTicker(BTC_USDT)
+	assert.True(t, EX_ERR_STREAM_CLOSED.Is(err))
+	_, err = bnWs.SubscribeTrades(BTC_USDT)
+	assert.True(t, EX_ERR_STREAM_CLOSED.Is(err))
+}
+
+func TestBinanceWs_SubscribeDepthTradesKline(t *testing.T) {
+	srv, _ := newWsStandIn(t, map[string][]string{
+		"btcusdt@depth5":   {`{"lastUpdateId":1,"bids":[["6100.1","1.5"],["6100.0","2"]],"asks":[["6100.9","0.5"]]}`},
+		"btcusdt@trade":    {`{"e":"trade","E":1530000000001,"s":"BTCUSDT","t":12345,"p":"6100.5","q":"0.01","T":1530000000000,"m":true}`},
+		"btcusdt@kline_1m": {`{"e":"kline","E":1530000000001,"s":"BTCUSDT","k":{"t":1530000000000,"o":"1","c":"2","h":"3","l":"0.5","v":"10"}}`}})
+	defer srv.Close()
+
+	bnWs := newTestBinanceWs(srv)
+	defer bnWs.Close()
+
+	depthCh, err := bnWs.SubscribeDepth(BTC_USDT, 1)
+	assert.Nil(t, err)
+	depth := <-depthCh
+	assert.Equal(t, DepthRecords{{Price: RequireDecimal("6100.1"), Amount: RequireDecimal("1.5")}}, depth.BidList)
+	assert.Equal(t, DepthRecords{{Price: RequireDecimal("6100.9"), Amount: RequireDecimal("0.5")}}, depth.AskList)
+
+	tradeCh, err := bnWs.SubscribeTrades(BTC_USDT)
+	assert.Nil(t, err)
+	trade := <-tradeCh
+	assert.Equal(t, Trade{Tid: 12345, Type: "sell", Amount: RequireDecimal("0.01"), Price: RequireDecimal("6100.5"), Date: 1530000000000}, trade)
+
+	klineCh, err := bnWs.SubscribeKline(BTC_USDT, KLINE_PERIOD_1MIN)
+	assert.Nil(t, err)
+	kline := <-klineCh
+	assert.Equal(t, Kline{Timestamp: 1530000000, Open: 1, Close: 2, High: 3, Low: 0.5, Vol: 10}, kline)
+
+	_, err = bnWs.SubscribeKline(BTC_USDT, KLINE_PERIOD_1YEAR)
+	assert.Equal(t, ErrNotSupported, err)
+}
+
+//rewriteTransport sends every request to the stand-in server
+type rewriteTransport struct {
+	host string
+}
+
+func (rt rewriteTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	req.URL.Scheme = "http"
+	req.URL.Host = rt.host
+	return http.DefaultTransport.RoundTrip(req)
+}
+
+func TestBinanceWs_SubscribeOrderBook(t *testing.T) {
+	//the diff stream starts before the snapshot (lastUpdateId 102): 100 is dropped, 101..103 straddles it,
+	//then 104..105 is applied. After the reconnect 110..111 leaves a gap and forces a new snapshot.
+	srv, _ := newWsStandIn(t, map[string][]string{
+		"btcusdt@depth": {
+			`{"e":"depthUpdate","s":"BTCUSDT","U":100,"u":100,"b":[["6000","9"]],"a":[]}`,
+			`{"e":"depthUpdate","s":"BTCUSDT","U":101,"u":103,"b":[["6100.1","0"]],"a":[["6100.9","0.7"]]}`,
+			`{"e":"depthUpdate","s":"BTCUSDT","U":104,"u":105,"b":[["6100.2","1"]],"a":[]}`}})
+	defer srv.Close()
+
+	var snapshots int32
+	rest := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "/api/v1/depth", r.URL.Path)
+		atomic.AddInt32(&snapshots, 1)
+		w.Write([]byte(`{"lastUpdateId":102,"bids":[["6100.1","1.5"],["6100.0","2"]],"asks":[["6100.9","0.5"],["6101","3"]]}`))
+	}))
+	defer rest.Close()
+
+	bnWs := newTestBinanceWs(srv)
+	defer bnWs.Close()
+	bnWs.restApi = New(&http.Client{Transport: rewriteTransport{strings.TrimPrefix(rest.URL, "http://")}}, "", "")
+
+	ob, err := bnWs.SubscribeOrderBook(BTC_USDT)
+	assert.Nil(t, err)
+
+	deadline := time.Now().Add(5 * time.Second)
+	for ob.Seq() != 105 && time.Now().Before(deadline) {
+		time.Sleep(10 * time.Millisecond)
+	}
+	assert.Equal(t, int64(105), ob.Seq())
+	assert.True(t, ob.Synced())
+	bid, _ := ob.BestBid()
+	ask, _ := ob.BestAsk()
+	assert.Equal(t, DepthRecord{Price: RequireDecimal("6100.2"), Amount: RequireDecimal("1")}, bid)
+	assert.Equal(t, DepthRecord{Price: RequireDecimal("6100.9"), Amount: RequireDecimal("0.7")}, ask)
+	assert.True(t, ob.AmountAt(RequireDecimal("6000")).IsZero())
+	assert.Equal(t, int32(1), atomic.LoadInt32(&snapshots))
+}
+
+func TestBinanceWs_handleError(t *testing.T) {
+	bnWs := NewBinanceWs()
+	msg, _ := json.Marshal(map[string]interface{}{"error": map[string]interface{}{"code": -1121, "msg": "Invalid symbol."}, "id": 1})
+	err := bnWs.handle(msg)
+	assert.True(t, EX_ERR_INVALID_CURRENCY_PAIR.Is(err))
+}
+
+func TestBinanceWs_SubscribeDepth_malformed(t *testing.T) {
+	srv, _ := newWsStandIn(t, map[string][]string{
+		"btcusdt@depth5": {
+			`{"lastUpdateId":1,"bids":[["0","1"]],"asks":[]}`,
+			`{"lastUpdateId":2,"bids":[["6100,1","1"]],"asks":[]}`,
+			`{"lastUpdateId":3,"bids":[["6100.1","1.5"]],"asks":[]}`}})
+	defer srv.Close()
+
+	bnWs := newTestBinanceWs(srv)
+	defer bnWs.Close()
+
+	ch, err := bnWs.SubscribeDepth(BTC_USDT, 5)
+	assert.Nil(t, err)
+
+	//the pushes with a zero or malformed price are dropped, not passed on with a price of 0
+	depth := <-ch
+	assert.Equal(t, DepthRecords{{Price: RequireDecimal("6100.1"), Amount: RequireDecimal("1.5")}}, depth.BidList)
+}
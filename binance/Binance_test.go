@@ -1,37 +1,413 @@
 package binance
 
 import (
+	"fmt"
 	"github.com/nntaoli-project/GoEx"
+	"github.com/stretchr/testify/assert"
+	"io/ioutil"
 	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"os"
+	"strings"
 	"testing"
 )
 
-var ba = New(http.DefaultClient, "", "")
+var fixtures = goex.NewFixtureTransport("testdata/fixtures.json")
+var ba = New(fixtures.Client(), "", "")
 
 func TestBinance_GetTicker(t *testing.T) {
-	ticker, _ := ba.GetTicker(goex.LTC_BTC)
-	t.Log(ticker)
+	ticker, err := ba.GetTicker(goex.LTC_BTC)
+	assert.Nil(t, err)
+	assert.Equal(t, goex.Ticker{Last: goex.RequireDecimal("0.01342"), Buy: goex.RequireDecimal("0.01341"), Sell: goex.RequireDecimal("0.01342"),
+		High: goex.RequireDecimal("0.01359"), Low: goex.RequireDecimal("0.01305"), Vol: goex.RequireDecimal("76712.48"), Date: 1529920350514}, *ticker)
 }
 func TestBinance_LimitSell(t *testing.T) {
-	order, err := ba.LimitSell("1", "1", goex.LTC_BTC)
-	t.Log(order, err)
+	order, err := ba.LimitSell(goex.RequireDecimal("1"), goex.RequireDecimal("1"), goex.LTC_BTC)
+	assert.True(t, goex.EX_ERR_INSUFFICIENT_BALANCE.Is(err))
+	assert.Nil(t, order)
 }
 
 func TestBinance_GetDepth(t *testing.T) {
 	dep, err := ba.GetDepth(5, goex.ETH_BTC)
-	t.Log(err)
-	if err == nil {
-		t.Log(dep.AskList)
-		t.Log(dep.BidList)
+	assert.Nil(t, err)
+	assert.Equal(t, goex.DepthRecords{
+		{Price: goex.RequireDecimal("0.072073"), Amount: goex.RequireDecimal("0.056")},
+		{Price: goex.RequireDecimal("0.072075"), Amount: goex.RequireDecimal("0.321")},
+		{Price: goex.RequireDecimal("0.072085"), Amount: goex.RequireDecimal("11.63")},
+		{Price: goex.RequireDecimal("0.072089"), Amount: goex.RequireDecimal("0.486")},
+		{Price: goex.RequireDecimal("0.072094"), Amount: goex.RequireDecimal("3")}}, dep.AskList)
+	assert.Equal(t, goex.DepthRecords{
+		{Price: goex.RequireDecimal("0.072054"), Amount: goex.RequireDecimal("2.7")},
+		{Price: goex.RequireDecimal("0.072051"), Amount: goex.RequireDecimal("0.165")},
+		{Price: goex.RequireDecimal("0.072046"), Amount: goex.RequireDecimal("0.04")},
+		{Price: goex.RequireDecimal("0.072043"), Amount: goex.RequireDecimal("1")},
+		{Price: goex.RequireDecimal("0.072039"), Amount: goex.RequireDecimal("6.129")}}, dep.BidList)
+}
+
+func TestBinance_GetDepth_malformed(t *testing.T) {
+	for _, body := range []string{
+		`{"lastUpdateId":1,"bids":[["6100.1","1"]],"asks":[["0.00000000","2"]]}`,
+		`{"lastUpdateId":1,"bids":[["6100,1","1"]],"asks":[]}`,
+		`{"lastUpdateId":1,"bids":[["6100.1","abc"]],"asks":[]}`} {
+		srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			w.Write([]byte(body))
+		}))
+		bn := New(&http.Client{Transport: rewriteTransport{strings.TrimPrefix(srv.URL, "http://")}}, "", "")
+
+		dep, err := bn.GetDepth(5, goex.BTC_USDT)
+		assert.NotNil(t, err, body)
+		assert.Nil(t, dep, body)
+		srv.Close()
 	}
 }
 
+func TestBinance_GetOneOrder_malformed(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(`{"symbol":"BTCUSDT","orderId":1,"clientOrderId":"a","price":"61OO.1","origQty":"1",` +
+			`"status":"NEW","side":"BUY"}`))
+	}))
+	defer srv.Close()
+	bn := New(&http.Client{Transport: rewriteTransport{strings.TrimPrefix(srv.URL, "http://")}}, "", "")
+
+	ord, err := bn.GetOneOrder("1", goex.BTC_USDT)
+	assert.NotNil(t, err)
+	assert.Nil(t, ord)
+}
+
 func TestBinance_GetAccount(t *testing.T) {
 	account, err := ba.GetAccount()
-	t.Log(account, err)
+	assert.Nil(t, err)
+	BNB := goex.NewCurrency("BNB", "")
+	assert.Equal(t, map[goex.Currency]goex.SubAccount{
+		BNB:      {Currency: BNB, Amount: goex.RequireDecimal("1.0382")},
+		goex.BTC: {Currency: goex.BTC, Amount: goex.RequireDecimal("0.004231")},
+		goex.ETH: {Currency: goex.ETH, Amount: goex.RequireDecimal("0.1125"), FrozenAmount: goex.RequireDecimal("0.05")},
+		goex.LTC: {Currency: goex.LTC}}, account.SubAccounts)
 }
 
 func TestBinance_GetUnfinishOrders(t *testing.T) {
 	orders, err := ba.GetUnfinishOrders(goex.ETH_BTC)
-	t.Log(orders, err)
+	assert.Nil(t, err)
+	assert.Len(t, orders, 1)
+	assert.Equal(t, 171863415, orders[0].OrderID)
+	assert.Equal(t, goex.RequireDecimal("0.075"), orders[0].Price)
+	assert.Equal(t, goex.RequireDecimal("0.05"), orders[0].Amount)
+	assert.Equal(t, 1529919011430, orders[0].OrderTime)
+	assert.Equal(t, goex.TradeSide(goex.SELL), orders[0].Side)
+	assert.Equal(t, goex.TradeStatus(goex.ORDER_UNFINISH), orders[0].Status)
+}
+
+func TestBinance_GetMarkets(t *testing.T) {
+	requests := 0
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests++
+		assert.Equal(t, "/api/v1/exchangeInfo", r.URL.Path)
+		w.Write([]byte(`{"timezone":"UTC","symbols":[` +
+			`{"symbol":"ETHBTC","status":"TRADING","baseAsset":"ETH","quoteAsset":"BTC","filters":[` +
+			`{"filterType":"PRICE_FILTER","minPrice":"0.00000100","maxPrice":"100000.00000000","tickSize":"0.00000100"},` +
+			`{"filterType":"LOT_SIZE","minQty":"0.00100000","maxQty":"100000.00000000","stepSize":"0.00100000"},` +
+			`{"filterType":"MIN_NOTIONAL","minNotional":"0.00100000"}]},` +
+			`{"symbol":"BCCBTC","status":"BREAK","baseAsset":"BCC","quoteAsset":"BTC","filters":[]}]}`))
+	}))
+	defer srv.Close()
+
+	bn := New(&http.Client{Transport: rewriteTransport{strings.TrimPrefix(srv.URL, "http://")}}, "", "")
+	markets, err := bn.GetMarkets()
+	assert.Nil(t, err)
+	assert.Equal(t, []goex.SymbolInfo{{
+		Pair:            goex.ETH_BTC,
+		Symbol:          "ETHBTC",
+		PricePrecision:  6,
+		AmountPrecision: 3,
+		TickSize:        goex.RequireDecimal("0.000001"),
+		StepSize:        goex.RequireDecimal("0.001"),
+		MinAmount:       goex.RequireDecimal("0.001"),
+		MaxAmount:       goex.RequireDecimal("100000"),
+		MinPrice:        goex.RequireDecimal("0.000001"),
+		MaxPrice:        goex.RequireDecimal("100000"),
+		MinNotional:     goex.RequireDecimal("0.001")}}, markets)
+
+	info, err := bn.GetSymbolInfo(goex.ETH_BTC)
+	assert.Nil(t, err)
+	assert.Equal(t, "ETHBTC", info.Symbol)
+	_, err = bn.GetSymbolInfo(goex.BCC_BTC)
+	assert.True(t, goex.EX_ERR_INVALID_CURRENCY_PAIR.Is(err))
+	assert.Equal(t, 2, requests)
+}
+
+func TestBinance_classifyEndpoint(t *testing.T) {
+	for _, c := range []struct {
+		method, url string
+		class       goex.EndpointClass
+		weight      int
+	}{
+		{"GET", API_V1 + "depth?symbol=BTCUSDT&limit=100", goex.ENDPOINT_PUBLIC, 1},
+		{"GET", API_V1 + "depth?symbol=BTCUSDT&limit=1000", goex.ENDPOINT_PUBLIC, 10},
+		{"GET", API_V1 + "ticker/24hr", goex.ENDPOINT_PUBLIC, 40},
+		{"GET", API_V3 + "account?timestamp=1&signature=s", goex.ENDPOINT_PRIVATE, 5},
+		{"GET", API_V3 + "order?symbol=BTCUSDT&orderId=1", goex.ENDPOINT_PRIVATE, 1},
+		{"POST", API_V3 + "order", goex.ENDPOINT_ORDER, 1},
+		{"GET", API_V3 + "openOrders?timestamp=1", goex.ENDPOINT_PRIVATE, 40},
+		{"GET", API_V3 + "allOrders?symbol=BTCUSDT&timestamp=1&signature=s", goex.ENDPOINT_PRIVATE, 5},
+		{"GET", API_V3 + "myTrades?symbol=BTCUSDT&timestamp=1&signature=s", goex.ENDPOINT_PRIVATE, 5},
+		{"GET", API_V1 + "historicalTrades?symbol=BTCUSDT", goex.ENDPOINT_PUBLIC, 5},
+	} {
+		req, _ := http.NewRequest(c.method, c.url, nil)
+		class, weight := RateLimits.Classify(req)
+		assert.Equal(t, c.class, class, c.url)
+		assert.Equal(t, c.weight, weight, c.url)
+	}
+}
+
+func TestBinance_ClientOrderID(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := ioutil.ReadAll(r.Body)
+		r.Form, _ = url.ParseQuery(r.URL.RawQuery + "&" + string(body))
+		assert.Equal(t, "/api/v3/order", r.URL.Path)
+		assert.Equal(t, "", r.Form.Get("orderId"))
+		switch r.Method {
+		case "POST":
+			assert.Equal(t, "myOrder1", r.Form.Get("newClientOrderId"))
+			assert.Equal(t, "SELL", r.Form.Get("side"))
+			w.Write([]byte(`{"symbol":"BTCUSDT","orderId":28,"clientOrderId":"myOrder1","transactTime":1507725176595}`))
+		case "GET":
+			assert.Equal(t, "myOrder1", r.Form.Get("origClientOrderId"))
+			w.Write([]byte(`{"symbol":"BTCUSDT","orderId":28,"clientOrderId":"myOrder1","price":"6100.00000000",` +
+				`"origQty":"1.00000000","executedQty":"0.00000000","status":"NEW","type":"LIMIT","side":"SELL"}`))
+		case "DELETE":
+			assert.Equal(t, "myOrder1", r.Form.Get("origClientOrderId"))
+			w.Write([]byte(`{"symbol":"BTCUSDT","origClientOrderId":"myOrder1","orderId":28,"clientOrderId":"cancelMyOrder1"}`))
+		}
+	}))
+	defer srv.Close()
+
+	bn := New(&http.Client{Transport: rewriteTransport{strings.TrimPrefix(srv.URL, "http://")}}, "", "")
+	order, err := bn.PlaceOrderWithClientID("myOrder1", goex.SELL, goex.RequireDecimal("1"), goex.RequireDecimal("6100"), goex.BTC_USDT)
+	assert.Nil(t, err)
+	assert.Equal(t, 28, order.OrderID)
+	assert.Equal(t, "myOrder1", order.ClientOrderID)
+
+	order, err = bn.GetOneOrder("myOrder1", goex.BTC_USDT)
+	assert.Nil(t, err)
+	assert.Equal(t, 28, order.OrderID)
+	assert.Equal(t, "myOrder1", order.ClientOrderID)
+	assert.Equal(t, goex.TradeSide(goex.SELL), order.Side)
+
+	ok, err := bn.CancelOrder("myOrder1", goex.BTC_USDT)
+	assert.Nil(t, err)
+	assert.True(t, ok)
+
+	_, err = bn.PlaceOrderWithClientID("12345", goex.SELL, goex.RequireDecimal("1"), goex.RequireDecimal("6100"), goex.BTC_USDT)
+	assert.True(t, goex.EX_ERR_INVALID_CLIENT_ORDER_ID.Is(err))
+}
+
+func TestBinance_setOrderId(t *testing.T) {
+	params := url.Values{}
+	ba.setOrderId(&params, "28")
+	assert.Equal(t, "28", params.Get("orderId"))
+	params = url.Values{}
+	ba.setOrderId(&params, "O6EAJC-YAC3C")
+	assert.Equal(t, "O6EAJC-YAC3C", params.Get("origClientOrderId"))
+	assert.Equal(t, "", params.Get("orderId"))
+}
+
+func TestBinance_OrderType(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := ioutil.ReadAll(r.Body)
+		form, _ := url.ParseQuery(string(body))
+		switch form.Get("type") {
+		case "MARKET":
+			assert.Equal(t, "", form.Get("price"))
+			assert.Equal(t, "", form.Get("timeInForce"))
+		case "LIMIT":
+			assert.Equal(t, "6100", form.Get("price"))
+			assert.Equal(t, "GTC", form.Get("timeInForce"))
+		default:
+			t.Errorf("type %q", form.Get("type"))
+		}
+		w.Write([]byte(`{"symbol":"BTCUSDT","orderId":28,"clientOrderId":"6gCrw2kRUAF9CvJDGP16IP","transactTime":1507725176595}`))
+	}))
+	defer srv.Close()
+	bn := New(&http.Client{Transport: rewriteTransport{strings.TrimPrefix(srv.URL, "http://")}}, "", "")
+
+	_, err := bn.MarketBuy(goex.RequireDecimal("1"), goex.Decimal{}, goex.BTC_USDT)
+	assert.Nil(t, err)
+	_, err = bn.MarketSell(goex.RequireDecimal("1"), goex.Decimal{}, goex.BTC_USDT)
+	assert.Nil(t, err)
+	_, err = bn.LimitBuy(goex.RequireDecimal("1"), goex.RequireDecimal("6100"), goex.BTC_USDT)
+	assert.Nil(t, err)
+}
+
+func TestBinance_Deposits(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "XRP", r.URL.Query().Get("asset"))
+		assert.NotEmpty(t, r.URL.Query().Get("signature"))
+		switch r.URL.Path {
+		case "/wapi/v3/depositAddress.html":
+			w.Write([]byte(`{"address":"rEb8TK3gBgk5auZkwc6sHnwrGVJH8DuaLh","success":true,"addressTag":"104505261","asset":"XRP"}`))
+		case "/wapi/v3/depositHistory.html":
+			w.Write([]byte(`{"depositList":[` +
+				`{"insertTime":1508198532000,"amount":25,"asset":"XRP","address":"rEb8TK3gBgk5auZkwc6sHnwrGVJH8DuaLh","addressTag":"104505261","txId":"9E6A","status":1},` +
+				`{"insertTime":1508298532000,"amount":10.5,"asset":"XRP","address":"rEb8TK3gBgk5auZkwc6sHnwrGVJH8DuaLh","addressTag":"104505261","txId":"A71B","status":0}],` +
+				`"success":true}`))
+		}
+	}))
+	defer srv.Close()
+	bn := New(&http.Client{Transport: rewriteTransport{strings.TrimPrefix(srv.URL, "http://")}}, "", "")
+
+	addr, err := bn.GetDepositAddress(goex.XRP)
+	assert.Nil(t, err)
+	assert.Equal(t, goex.CryptoAddress{Currency: goex.XRP, Address: "rEb8TK3gBgk5auZkwc6sHnwrGVJH8DuaLh", Tag: "104505261", ExchangeName: "binance.com"}, *addr)
+
+	deposits, err := bn.GetDeposits(goex.XRP, 1, 10)
+	assert.Nil(t, err)
+	assert.Len(t, deposits, 2)
+	assert.Equal(t, "A71B", deposits[0].TxID)
+	assert.Equal(t, goex.DEPOSIT_PENDING, deposits[0].Status)
+	assert.Equal(t, goex.Deposit{ID: "9E6A", Currency: goex.XRP, Amount: goex.RequireDecimal("25"), Address: "rEb8TK3gBgk5auZkwc6sHnwrGVJH8DuaLh",
+		Tag: "104505261", TxID: "9E6A", Status: goex.DEPOSIT_COMPLETED, Time: 1508198532}, deposits[1])
+
+	deposits, err = bn.GetDeposits(goex.XRP, 2, 1)
+	assert.Nil(t, err)
+	assert.Equal(t, "9E6A", deposits[0].TxID)
+}
+
+func TestBinance_GetTradeFee(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "/api/v3/account", r.URL.Path)
+		w.Write([]byte(`{"makerCommission":10,"takerCommission":15,"buyerCommission":0,"sellerCommission":0,"balances":[]}`))
+	}))
+	defer srv.Close()
+	bn := New(&http.Client{Transport: rewriteTransport{strings.TrimPrefix(srv.URL, "http://")}}, "", "")
+
+	fee, err := bn.GetTradeFee(goex.BTC_USDT)
+	assert.Nil(t, err)
+	assert.True(t, fee.Maker.Equal(goex.RequireDecimal("0.001")))
+	assert.True(t, fee.Taker.Equal(goex.RequireDecimal("0.0015")))
+}
+
+func TestBinance_GetMyTrades(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "/api/v3/myTrades", r.URL.Path)
+		assert.Equal(t, "BTCUSDT", r.URL.Query().Get("symbol"))
+		assert.Equal(t, "1499865549000", r.URL.Query().Get("startTime"))
+		assert.Equal(t, "10", r.URL.Query().Get("limit"))
+		w.Write([]byte(`[{"id":28457,"orderId":100234,"price":"4.00000100","qty":"12.00000000","commission":"10.10000000",` +
+			`"commissionAsset":"BNB","time":1499865549590,"isBuyer":true,"isMaker":false,"isBestMatch":true}]`))
+	}))
+	defer srv.Close()
+	bn := New(&http.Client{Transport: rewriteTransport{strings.TrimPrefix(srv.URL, "http://")}}, "", "")
+
+	trades, err := bn.GetMyTrades(goex.BTC_USDT, 1499865549000, 10)
+	assert.Nil(t, err)
+	assert.Equal(t, []goex.MyTrade{{TradeID: "28457", OrderID: "100234", Currency: goex.BTC_USDT, Side: goex.TradeSide(goex.BUY),
+		Price: goex.RequireDecimal("4.00000100"), Amount: goex.RequireDecimal("12.00000000"), Fee: goex.RequireDecimal("10.10000000"),
+		FeeCurrency: goex.NewCurrency("BNB", ""), Time: 1499865549590}}, trades)
+}
+
+func TestBinance_GetOrderHistory(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "/api/v3/allOrders", r.URL.Path)
+		q := r.URL.Query()
+		assert.Equal(t, "1000", q.Get("limit"))
+		if q.Get("orderId") == "" {
+			assert.Equal(t, "1499827300000", q.Get("startTime"))
+			assert.Equal(t, "1499827400000", q.Get("endTime"))
+		} else {
+			assert.Equal(t, "1001", q.Get("orderId"))
+		}
+		orders := make([]string, 0, 1000)
+		for i := 1; i <= 1000; i++ {
+			id := i
+			if q.Get("orderId") != "" {
+				id += 1000
+			}
+			orders = append(orders, fmt.Sprintf(`{"symbol":"LTCBTC","orderId":%d,"clientOrderId":"myOrder%d","price":"0.1","origQty":"1.0",`+
+				`"executedQty":"0.5","cummulativeQuoteQty":"0.06","status":"PARTIALLY_FILLED","type":"LIMIT","side":"SELL","time":%d}`, id, id, 1499827319000+int64(id)*60))
+		}
+		w.Write([]byte("[" + strings.Join(orders, ",") + "]"))
+	}))
+	defer srv.Close()
+	bn := New(&http.Client{Transport: rewriteTransport{strings.TrimPrefix(srv.URL, "http://")}}, "", "")
+
+	orders, cursor, err := bn.GetOrderHistory(goex.LTC_BTC, 1499827300000, 1499827400000, "")
+	assert.Nil(t, err)
+	assert.Equal(t, "1001", cursor)
+	assert.Len(t, orders, 1000)
+	assert.Equal(t, goex.Order{OrderID: 1, ClientOrderID: "myOrder1", Currency: goex.LTC_BTC, Price: goex.RequireDecimal("0.1"),
+		Amount: goex.RequireDecimal("1.0"), DealAmount: goex.RequireDecimal("0.5"), AvgPrice: goex.RequireDecimal("0.06").Div(goex.RequireDecimal("0.5")),
+		Status: goex.ORDER_PART_FINISH, Side: goex.TradeSide(goex.SELL), OrderTime: 1499827319060}, orders[0])
+
+	//the second page runs past the end
+	orders, cursor, err = bn.GetOrderHistory(goex.LTC_BTC, 1499827300000, 1499827400000, cursor)
+	assert.Nil(t, err)
+	assert.Equal(t, "", cursor)
+	assert.Len(t, orders, 350)
+}
+
+func TestBinance_GetKlineRecords(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "/api/v1/klines", r.URL.Path)
+		q := r.URL.Query()
+		assert.Equal(t, "BTCUSDT", q.Get("symbol"))
+		assert.Equal(t, "1h", q.Get("interval"))
+		assert.Equal(t, "2", q.Get("limit"))
+		assert.Equal(t, "1499040000000", q.Get("startTime"))
+		w.Write([]byte(`[[1499040000000,"0.01634790","0.80000000","0.01575800","0.01577100","148976.11427815",1499644799999,"2434.19055334",308,"1756.87402397","28.46694368","0"],` +
+			`[1499043600000,"0.01577100","0.01600000","0.01570000","0.01590000","100.5",1499647199999,"1.6",10,"50.2","0.8","0"]]`))
+	}))
+	defer srv.Close()
+	bn := New(&http.Client{Transport: rewriteTransport{strings.TrimPrefix(srv.URL, "http://")}}, "", "")
+
+	klines, err := bn.GetKlineRecords(goex.BTC_USDT, goex.KLINE_PERIOD_60MIN, 2, 1499040000000)
+	assert.Nil(t, err)
+	assert.Equal(t, []goex.Kline{
+		{Timestamp: 1499040000, Open: 0.0163479, High: 0.8, Low: 0.015758, Close: 0.015771, Vol: 148976.11427815},
+		{Timestamp: 1499043600, Open: 0.015771, High: 0.016, Low: 0.0157, Close: 0.0159, Vol: 100.5}}, klines)
+
+	_, err = bn.GetKlineRecords(goex.BTC_USDT, goex.KLINE_PERIOD_1YEAR, 2, 0)
+	assert.True(t, goex.ErrNotSupported.Is(err))
+}
+
+func TestMain(m *testing.M) {
+	os.Exit(fixtures.Run(m.Run))
+}
+
+func TestBinance_errorWrapper(t *testing.T) {
+	for _, c := range []struct {
+		code    int
+		msg     string
+		errCode goex.ApiError
+	}{
+		{-1003, "Too many requests.", goex.EX_ERR_API_LIMIT},
+		{-1021, "Timestamp for this request is outside of the recvWindow.", goex.EX_ERR_NONCE},
+		{-1022, "Signature for this request is not valid.", goex.EX_ERR_SIGN},
+		{-2015, "Invalid API-key, IP, or permissions for action.", goex.EX_ERR_NOT_FIND_APIKEY},
+		{-1013, "Filter failure: LOT_SIZE", goex.EX_ERR_INVALID_PRECISION},
+		{-1121, "Invalid symbol.", goex.EX_ERR_INVALID_CURRENCY_PAIR},
+		{-1016, "This service is no longer available.", goex.EX_ERR_MAINTENANCE},
+		{-2013, "Order does not exist.", goex.EX_ERR_NOT_FIND_ORDER},
+		{-2010, "Account has insufficient balance for requested action.", goex.EX_ERR_INSUFFICIENT_BALANCE},
+		{-2010, "Market is closed.", goex.EX_ERR_PLACE_ORDER_FAIL},
+		{-2011, "Unknown order sent.", goex.EX_ERR_NOT_FIND_ORDER},
+		{-2011, "Order was canceled or expired.", goex.EX_ERR_CANCEL_ORDER_FAIL},
+		{-1100, "Illegal characters found in a parameter.", goex.API_ERR},
+	} {
+		err := ba.errorWrapper(c.code, c.msg)
+		assert.True(t, c.errCode.Is(err), "%d %s", c.code, c.msg)
+		assert.Equal(t, c.msg, err.OriginErrMsg)
+	}
+}
+
+func TestBinance_adaptError(t *testing.T) {
+	httpErr := goex.HTTP_ERR_CODE
+	httpErr.OriginErrMsg = `{"code":-1121,"msg":"Invalid symbol."}`
+	err := ba.adaptError(httpErr)
+	assert.True(t, goex.EX_ERR_INVALID_CURRENCY_PAIR.Is(err))
+	assert.Equal(t, httpErr.OriginErrMsg, err.(goex.ApiError).OriginErrMsg)
+
+	limited := goex.EX_ERR_API_LIMIT
+	limited.OriginErrMsg = "<html>418 I'm a teapot</html>"
+	assert.Equal(t, error(limited), ba.adaptError(limited))
 }
@@ -0,0 +1,68 @@
+package bitfinex
+
+import (
+	"context"
+
+	. "github.com/nntaoli-project/GoEx"
+)
+
+var _ API = (*Bitfinex)(nil)
+var _ APIWithContext = (*Bitfinex)(nil)
+
+func (bfx *Bitfinex) withContext(ctx context.Context) *Bitfinex {
+	c := *bfx
+	c.httpClient = HttpClientWithContext(ctx, bfx.httpClient)
+	return &c
+}
+
+func (bfx *Bitfinex) LimitBuyCtx(ctx context.Context, amount, price Decimal, currency CurrencyPair) (*Order, error) {
+	return bfx.withContext(ctx).LimitBuy(amount, price, currency)
+}
+
+func (bfx *Bitfinex) LimitSellCtx(ctx context.Context, amount, price Decimal, currency CurrencyPair) (*Order, error) {
+	return bfx.withContext(ctx).LimitSell(amount, price, currency)
+}
+
+func (bfx *Bitfinex) MarketBuyCtx(ctx context.Context, amount, price Decimal, currency CurrencyPair) (*Order, error) {
+	return bfx.withContext(ctx).MarketBuy(amount, price, currency)
+}
+
+func (bfx *Bitfinex) MarketSellCtx(ctx context.Context, amount, price Decimal, currency CurrencyPair) (*Order, error) {
+	return bfx.withContext(ctx).MarketSell(amount, price, currency)
+}
+
+func (bfx *Bitfinex) CancelOrderCtx(ctx context.Context, orderId string, currency CurrencyPair) (bool, error) {
+	return bfx.withContext(ctx).CancelOrder(orderId, currency)
+}
+
+func (bfx *Bitfinex) GetOneOrderCtx(ctx context.Context, orderId string, currency CurrencyPair) (*Order, error) {
+	return bfx.withContext(ctx).GetOneOrder(orderId, currency)
+}
+
+func (bfx *Bitfinex) GetUnfinishOrdersCtx(ctx context.Context, currency CurrencyPair) ([]Order, error) {
+	return bfx.withContext(ctx).GetUnfinishOrders(currency)
+}
+
+func (bfx *Bitfinex) GetOrderHistorysCtx(ctx context.Context, currency CurrencyPair, currentPage, pageSize int) ([]Order, error) {
+	return bfx.withContext(ctx).GetOrderHistorys(currency, currentPage, pageSize)
+}
+
+func (bfx *Bitfinex) GetAccountCtx(ctx context.Context) (*Account, error) {
+	return bfx.withContext(ctx).GetAccount()
+}
+
+func (bfx *Bitfinex) GetTickerCtx(ctx context.Context, currency CurrencyPair) (*Ticker, error) {
+	return bfx.withContext(ctx).GetTicker(currency)
+}
+
+func (bfx *Bitfinex) GetDepthCtx(ctx context.Context, size int, currency CurrencyPair) (*Depth, error) {
+	return bfx.withContext(ctx).GetDepth(size, currency)
+}
+
+func (bfx *Bitfinex) GetKlineRecordsCtx(ctx context.Context, currency CurrencyPair, period, size, since int) ([]Kline, error) {
+	return bfx.withContext(ctx).GetKlineRecords(currency, period, size, since)
+}
+
+func (bfx *Bitfinex) GetTradesCtx(ctx context.Context, currencyPair CurrencyPair, since int64) ([]Trade, error) {
+	return bfx.withContext(ctx).GetTrades(currencyPair, since)
+}
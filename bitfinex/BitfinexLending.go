@@ -89,7 +89,7 @@ func (bfx *Bitfinex) Transfer(amount float64, currency Currency, fromWallet, toW
 		return nil
 	}
 
-	return errors.New(resp[0]["message"].(string))
+	return bfx.errorWrapper(resp[0]["message"].(string))
 }
 
 func (bfx *Bitfinex) newOffer(currency Currency, amount, rate string, period int, direction string) (error, *LendOrder) {
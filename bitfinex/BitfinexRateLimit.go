@@ -0,0 +1,24 @@
+package bitfinex
+
+import (
+	"net/http"
+	"strings"
+	"time"
+
+	. "github.com/nntaoli-project/GoEx"
+)
+
+//RateLimits bitfinex limits each v1 endpoint to between 10 and 90 requests a minute
+var RateLimits = RateLimitConfig{
+	Limits: []RateLimit{
+		{Classes: []EndpointClass{ENDPOINT_PUBLIC}, Limit: 30, Interval: time.Minute},
+		{Classes: []EndpointClass{ENDPOINT_PRIVATE}, Limit: 60, Interval: time.Minute},
+		{Classes: []EndpointClass{ENDPOINT_ORDER}, Limit: 90, Interval: time.Minute}},
+	Classify: classifyEndpoint}
+
+func classifyEndpoint(req *http.Request) (EndpointClass, int) {
+	if strings.HasPrefix(req.URL.Path, "/v1/order/new") {
+		return ENDPOINT_ORDER, 1
+	}
+	return ClassifyEndpoint(req)
+}
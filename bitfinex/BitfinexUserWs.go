@@ -0,0 +1,232 @@
+package bitfinex
+
+import (
+	"encoding/json"
+	"fmt"
+	. "github.com/nntaoli-project/GoEx"
+	"strings"
+	"sync"
+	"time"
+)
+
+//BitfinexUserWs implements UserStreamAPI on the v2 authenticated channel 0.
+//Only the exchange wallet is reported, as GetAccount does.
+type BitfinexUserWs struct {
+	wsUrl     string
+	accessKey string
+	secretKey string
+	wsConn    *WsConn
+	l         sync.Mutex
+	orders    chan Order
+	balances  chan SubAccount
+	closeCh   chan struct{}
+	once      sync.Once
+}
+
+func NewBitfinexUserWs(accessKey, secretKey string) *BitfinexUserWs {
+	return &BitfinexUserWs{
+		wsUrl:     WS_BASE_URL,
+		accessKey: accessKey,
+		secretKey: secretKey,
+		closeCh:   make(chan struct{})}
+}
+
+func (bfxUserWs *BitfinexUserWs) GetExchangeName() string {
+	return EXCHANGE_NAME
+}
+
+func (bfxUserWs *BitfinexUserWs) SubscribeOrders() (<-chan Order, error) {
+	bfxUserWs.l.Lock()
+	defer bfxUserWs.l.Unlock()
+	if err := bfxUserWs.connect(); err != nil {
+		return nil, err
+	}
+	if bfxUserWs.orders == nil {
+		bfxUserWs.orders = make(chan Order, 64)
+	}
+	return bfxUserWs.orders, nil
+}
+
+func (bfxUserWs *BitfinexUserWs) SubscribeBalances() (<-chan SubAccount, error) {
+	bfxUserWs.l.Lock()
+	defer bfxUserWs.l.Unlock()
+	if err := bfxUserWs.connect(); err != nil {
+		return nil, err
+	}
+	if bfxUserWs.balances == nil {
+		bfxUserWs.balances = make(chan SubAccount, 64)
+	}
+	return bfxUserWs.balances, nil
+}
+
+func (bfxUserWs *BitfinexUserWs) Close() error {
+	bfxUserWs.once.Do(func() { close(bfxUserWs.closeCh) })
+	bfxUserWs.l.Lock()
+	wsConn := bfxUserWs.wsConn
+	bfxUserWs.l.Unlock()
+	if wsConn == nil {
+		return nil
+	}
+	return wsConn.Close()
+}
+
+//connect authenticates on the first subscription, l must be held
+func (bfxUserWs *BitfinexUserWs) connect() error {
+	if bfxUserWs.wsConn != nil {
+		return nil
+	}
+	wsConn := NewWsConn(bfxUserWs.wsUrl, bfxUserWs.handle)
+	wsConn.ReadTimeout = time.Minute //channel 0 sends a heartbeat each 15s too
+	wsConn.LoginDataFunc = bfxUserWs.authData
+	wsConn.CloseHandleFunc = bfxUserWs.closeChans
+	if err := wsConn.Connect(); err != nil {
+		return err
+	}
+	bfxUserWs.wsConn = wsConn
+	return nil
+}
+
+//authData signs a new nonce for each connection, the filter leaves out funding and positions
+func (bfxUserWs *BitfinexUserWs) authData() interface{} {
+	nonce := fmt.Sprint(time.Now().UnixNano() / int64(time.Millisecond) * 1000)
+	payload := "AUTH" + nonce
+	sign, _ := GetParamHmacSha384Sign(bfxUserWs.secretKey, payload)
+	return map[string]interface{}{
+		"event":       "auth",
+		"apiKey":      bfxUserWs.accessKey,
+		"authSig":     sign,
+		"authPayload": payload,
+		"authNonce":   nonce,
+		"filter":      []string{"trading", "wallet"}}
+}
+
+func (bfxUserWs *BitfinexUserWs) handle(msg []byte) error {
+	if len(msg) > 0 && msg[0] == '{' {
+		var event map[string]interface{}
+		if err := json.Unmarshal(msg, &event); err != nil {
+			return err
+		}
+		if event["event"] == "error" || event["event"] == "auth" && event["status"] != "OK" {
+			return (&Bitfinex{}).errorWrapper(fmt.Sprint(event["msg"]))
+		}
+		return nil
+	}
+
+	//[0, TYPE, DATA], os/ws carry a snapshot: a list of orders or wallets
+	var data []interface{}
+	if err := json.Unmarshal(msg, &data); err != nil {
+		return err
+	}
+	if len(data) < 3 {
+		return nil
+	}
+	entries, isok := data[2].([]interface{})
+	if !isok {
+		return nil
+	}
+	if data[1] == "os" || data[1] == "ws" {
+		for _, entry := range entries {
+			if e, isok := entry.([]interface{}); isok {
+				if err := bfxUserWs.push(data[1], e); err != nil {
+					return err
+				}
+			}
+		}
+		return nil
+	}
+	return bfxUserWs.push(data[1], entries)
+}
+
+func (bfxUserWs *BitfinexUserWs) push(msgType interface{}, entry []interface{}) error {
+	bfxUserWs.l.Lock()
+	orders, balances := bfxUserWs.orders, bfxUserWs.balances
+	bfxUserWs.l.Unlock()
+
+	switch msgType {
+	case "os", "on", "ou", "oc":
+		if orders == nil || len(entry) < 18 {
+			return nil
+		}
+		ord, err := bfxUserWs.parseOrder(entry)
+		if err != nil {
+			return err
+		}
+		select {
+		case orders <- ord:
+		case <-bfxUserWs.closeCh:
+		}
+	case "ws", "wu":
+		//[WALLET_TYPE, CURRENCY, BALANCE, UNSETTLED_INTEREST, BALANCE_AVAILABLE]
+		if balances == nil || len(entry) < 3 || entry[0] != "exchange" {
+			return nil
+		}
+		var dp DecimalParser
+		balance := dp.Decimal(entry[2])
+		account := SubAccount{Currency: NewCurrency(fmt.Sprint(entry[1]), ""), Amount: balance}
+		if len(entry) >= 5 && entry[4] != nil { //null until bitfinex has calculated it
+			account.Amount = dp.Decimal(entry[4])
+			account.FrozenAmount = balance.Sub(account.Amount)
+		}
+		if dp.Err != nil {
+			return dp.Err
+		}
+		select {
+		case balances <- account:
+		case <-bfxUserWs.closeCh:
+		}
+	}
+	return nil
+}
+
+//order: [ID, GID, CID, SYMBOL, MTS_CREATE, MTS_UPDATE, AMOUNT, AMOUNT_ORIG, TYPE, TYPE_PREV,
+//MTS_TIF, _, FLAGS, STATUS, _, _, PRICE, PRICE_AVG, ...], AMOUNT is what is left, negative for a sell
+func (bfxUserWs *BitfinexUserWs) parseOrder(entry []interface{}) (Order, error) {
+	var dp DecimalParser
+	amount, amountOrig := dp.Decimal(entry[6]), dp.Decimal(entry[7])
+	ord := Order{
+		OrderID:    ToInt(entry[0]),
+		Currency:   (&Bitfinex{}).symbolToCurrencyPair(strings.TrimPrefix(fmt.Sprint(entry[3]), "t")),
+		Amount:     amountOrig.Abs(),
+		DealAmount: amountOrig.Abs().Sub(amount.Abs()),
+		Price:      dp.Decimal(entry[16]),
+		AvgPrice:   dp.Decimal(entry[17]),
+		OrderTime:  ToInt(entry[4])}
+
+	isMarket := strings.Contains(fmt.Sprint(entry[8]), "MARKET")
+	switch {
+	case amountOrig.Sign() > 0 && isMarket:
+		ord.Side = BUY_MARKET
+	case amountOrig.Sign() > 0:
+		ord.Side = BUY
+	case isMarket:
+		ord.Side = SELL_MARKET
+	default:
+		ord.Side = SELL
+	}
+
+	//e.g. "ACTIVE", "EXECUTED @ 6100.0(-0.5)", "PARTIALLY FILLED @ 6100.0(-0.2)", "CANCELED was: PARTIALLY FILLED @ ..."
+	switch status := fmt.Sprint(entry[13]); {
+	case strings.HasPrefix(status, "EXECUTED"):
+		ord.Status = ORDER_FINISH
+	case strings.Contains(status, "CANCELED"):
+		ord.Status = ORDER_CANCEL
+	case strings.HasPrefix(status, "PARTIALLY FILLED"):
+		ord.Status = ORDER_PART_FINISH
+	case strings.HasPrefix(status, "ACTIVE"):
+		ord.Status = ORDER_UNFINISH
+	default:
+		ord.Status = ORDER_REJECT //INSUFFICIENT MARGIN, RSN_...
+	}
+	return ord, dp.Err
+}
+
+func (bfxUserWs *BitfinexUserWs) closeChans() {
+	bfxUserWs.l.Lock()
+	defer bfxUserWs.l.Unlock()
+	if bfxUserWs.orders != nil {
+		close(bfxUserWs.orders)
+	}
+	if bfxUserWs.balances != nil {
+		close(bfxUserWs.balances)
+	}
+}
@@ -0,0 +1,77 @@
+package bitfinex
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/gorilla/websocket"
+	. "github.com/nntaoli-project/GoEx"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestBitfinexUserWs(t *testing.T) {
+	upgrader := websocket.Upgrader{}
+	subscribed := make(chan struct{})
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		conn, err := upgrader.Upgrade(w, r, nil)
+		if err != nil {
+			t.Error(err)
+			return
+		}
+		defer conn.Close()
+
+		var auth map[string]interface{}
+		if err := conn.ReadJSON(&auth); err != nil {
+			t.Error(err)
+			return
+		}
+		sign, _ := GetParamHmacSha384Sign("secret", auth["authPayload"].(string))
+		assert.Equal(t, "key", auth["apiKey"])
+		assert.Equal(t, sign, auth["authSig"])
+		assert.Equal(t, "AUTH"+auth["authNonce"].(string), auth["authPayload"])
+		<-subscribed
+
+		for _, msg := range []string{
+			`{"event":"auth","status":"OK","chanId":0,"userId":1}`,
+			`[0,"os",[[1001,null,7,"tBTCUSD",1530000000000,1530000000000,-1,-1,"EXCHANGE LIMIT",null,null,null,0,"ACTIVE",null,null,6100,0]]]`,
+			`[0,"ws",[["exchange","BTC",10,0,null],["margin","BTC",5,0,5]]]`,
+			`[0,"hb"]`,
+			`[0,"ou",[1001,null,7,"tBTCUSD",1530000000000,1530000001000,-0.5,-1,"EXCHANGE LIMIT",null,null,null,0,"PARTIALLY FILLED @ 6100.0(-0.5)",null,null,6100,6100]]`,
+			`[0,"wu",["exchange","BTC",9.5,0,9]]`,
+			`[0,"oc",[1002,null,8,"tETHBTC",1530000000000,1530000002000,0,0.2,"EXCHANGE MARKET",null,null,null,0,"EXECUTED @ 0.07(0.2)",null,null,0.07,0.07]]`} {
+			conn.WriteMessage(websocket.TextMessage, []byte(msg))
+		}
+		conn.ReadMessage()
+	}))
+	defer srv.Close()
+
+	bfxUserWs := NewBitfinexUserWs("key", "secret")
+	bfxUserWs.wsUrl = "ws" + strings.TrimPrefix(srv.URL, "http")
+
+	orders, err := bfxUserWs.SubscribeOrders()
+	assert.Nil(t, err)
+	balances, err := bfxUserWs.SubscribeBalances()
+	assert.Nil(t, err)
+	close(subscribed)
+
+	assert.Equal(t, Order{OrderID: 1001, Currency: BTC_USD, Amount: RequireDecimal("1"), Price: RequireDecimal("6100"), Side: SELL, Status: ORDER_UNFINISH,
+		OrderTime: 1530000000000}, <-orders)
+	assert.Equal(t, Order{OrderID: 1001, Currency: BTC_USD, Amount: RequireDecimal("1"), DealAmount: RequireDecimal("0.5"), Price: RequireDecimal("6100"), AvgPrice: RequireDecimal("6100"),
+		Side: SELL, Status: ORDER_PART_FINISH, OrderTime: 1530000000000}, <-orders)
+	assert.Equal(t, Order{OrderID: 1002, Currency: ETH_BTC, Amount: RequireDecimal("0.2"), DealAmount: RequireDecimal("0.2"), Price: RequireDecimal("0.07"), AvgPrice: RequireDecimal("0.07"),
+		Side: BUY_MARKET, Status: ORDER_FINISH, OrderTime: 1530000000000}, <-orders)
+
+	assert.Equal(t, SubAccount{Currency: BTC, Amount: RequireDecimal("10")}, <-balances)
+	assert.Equal(t, SubAccount{Currency: BTC, Amount: RequireDecimal("9"), FrozenAmount: RequireDecimal("0.5")}, <-balances)
+
+	assert.Nil(t, bfxUserWs.Close())
+	for range orders {
+	}
+}
+
+func TestBitfinexUserWs_handleError(t *testing.T) {
+	err := NewBitfinexUserWs("", "").handle([]byte(`{"event":"auth","status":"FAILED","chanId":0,"msg":"apikey: invalid","code":10100}`))
+	assert.NotNil(t, err)
+}
@@ -0,0 +1,426 @@
+package bitfinex
+
+import (
+	"encoding/json"
+	"fmt"
+	. "github.com/nntaoli-project/GoEx"
+	"hash/crc32"
+	"log"
+	"math"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+const (
+	WS_BASE_URL = "wss://api.bitfinex.com/ws/2"
+
+	bookChecksumFlag = 131072
+)
+
+var wsKlinePeriods = map[int]string{
+	KLINE_PERIOD_1MIN:   "1m",
+	KLINE_PERIOD_5MIN:   "5m",
+	KLINE_PERIOD_15MIN:  "15m",
+	KLINE_PERIOD_30MIN:  "30m",
+	KLINE_PERIOD_60MIN:  "1h",
+	KLINE_PERIOD_1DAY:   "1D",
+	KLINE_PERIOD_1WEEK:  "7D",
+	KLINE_PERIOD_1MONTH: "1M",
+}
+
+//BitfinexWs implements StreamAPI on the v2 public channels.
+//Channel ids are reassigned on every (re)subscribe, so they are only used to look up the subscription key.
+type BitfinexWs struct {
+	wsUrl   string
+	wsConn  *WsConn
+	l       sync.Mutex
+	chans   map[string]interface{}
+	handles map[string]func(data []interface{}) error
+	chanIds map[int64]string
+	closers []func()
+	closeCh chan struct{}
+	once    sync.Once
+}
+
+func NewBitfinexWs() *BitfinexWs {
+	return &BitfinexWs{
+		wsUrl:   WS_BASE_URL,
+		chans:   make(map[string]interface{}),
+		handles: make(map[string]func(data []interface{}) error),
+		chanIds: make(map[int64]string),
+		closeCh: make(chan struct{})}
+}
+
+func (bfxWs *BitfinexWs) GetExchangeName() string {
+	return EXCHANGE_NAME
+}
+
+func (bfxWs *BitfinexWs) SubscribeTicker(pair CurrencyPair) (<-chan Ticker, error) {
+	symbol := bfxWs.symbol(pair)
+	ch := make(chan Ticker, 64)
+	c, err := bfxWs.subscribe("ticker:"+symbol, map[string]interface{}{"event": "subscribe", "channel": "ticker", "symbol": symbol},
+		ch, func() { close(ch) }, func(data []interface{}) error {
+			//[BID, BID_SIZE, ASK, ASK_SIZE, DAILY_CHANGE, DAILY_CHANGE_PERC, LAST_PRICE, VOLUME, HIGH, LOW]
+			t, isok := data[0].([]interface{})
+			if !isok || len(t) < 10 {
+				return nil
+			}
+			var dp DecimalParser
+			ticker := Ticker{
+				Buy:  dp.Decimal(t[0]),
+				Sell: dp.Decimal(t[2]),
+				Last: dp.Decimal(t[6]),
+				Vol:  dp.Decimal(t[7]),
+				High: dp.Decimal(t[8]),
+				Low:  dp.Decimal(t[9]),
+				Date: uint64(time.Now().Unix())}
+			if dp.Err != nil {
+				return dp.Err
+			}
+			select {
+			case ch <- ticker:
+			case <-bfxWs.closeCh:
+			}
+			return nil
+		})
+	if err != nil {
+		return nil, err
+	}
+	return c.(chan Ticker), nil
+}
+
+//the book channel only pushes updates, so a local book is kept and the top size levels are sent after each update
+func (bfxWs *BitfinexWs) SubscribeDepth(pair CurrencyPair, size int) (<-chan Depth, error) {
+	symbol := bfxWs.symbol(pair)
+	length := "25"
+	if size > 25 {
+		length = "100"
+	}
+
+	ob := NewOrderBook()
+	ch := make(chan Depth, 64)
+	c, err := bfxWs.subscribe("book:"+symbol+":"+length, map[string]interface{}{"event": "subscribe", "channel": "book", "symbol": symbol, "prec": "P0", "len": length},
+		ch, func() { close(ch) }, func(data []interface{}) error {
+			isBook, err := bfxWs.updateBook(ob, data)
+			if err != nil || !isBook {
+				return err
+			}
+			select {
+			case ch <- *ob.Depth(size):
+			case <-bfxWs.closeCh:
+			}
+			return nil
+		})
+	if err != nil {
+		return nil, err
+	}
+	return c.(chan Depth), nil
+}
+
+//SubscribeOrderBook keeps a local book of the top 250 levels, checked against the checksum bitfinex
+//sends after every update. On a mismatch the channel is resubscribed to get a new snapshot.
+func (bfxWs *BitfinexWs) SubscribeOrderBook(pair CurrencyPair) (*OrderBook, error) {
+	symbol := bfxWs.symbol(pair)
+	key := "book:" + symbol + ":250"
+	sub := map[string]interface{}{"event": "subscribe", "channel": "book", "symbol": symbol, "prec": "P0", "len": "250"}
+
+	ob := NewOrderBook()
+	ob.ChecksumFunc = bfxWs.checksum
+	c, err := bfxWs.subscribe(key, sub, ob, func() {}, func(data []interface{}) error {
+		if data[0] != "cs" {
+			_, err := bfxWs.updateBook(ob, data)
+			return err
+		}
+		if len(data) < 2 || !ob.Synced() {
+			return nil
+		}
+		err := ob.Update(OrderBookUpdate{Checksum: int32(ToInt64(data[1])), HasChecksum: true})
+		if err != nil {
+			log.Println("[bitfinex ws] order book", pair, err, ", resubscribing")
+			bfxWs.resubscribe(key, sub)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return c.(*OrderBook), nil
+}
+
+func (bfxWs *BitfinexWs) SubscribeTrades(pair CurrencyPair) (<-chan Trade, error) {
+	symbol := bfxWs.symbol(pair)
+	ch := make(chan Trade, 64)
+	c, err := bfxWs.subscribe("trades:"+symbol, map[string]interface{}{"event": "subscribe", "channel": "trades", "symbol": symbol},
+		ch, func() { close(ch) }, func(data []interface{}) error {
+			//only "te" (trade executed) is forwarded, the snapshot and the "tu" repeats are dropped
+			if len(data) < 2 || data[0] != "te" {
+				return nil
+			}
+			//[ID, MTS, AMOUNT, PRICE]
+			t, isok := data[1].([]interface{})
+			if !isok || len(t) < 4 {
+				return nil
+			}
+			var dp DecimalParser
+			trade := Trade{
+				Tid:    ToInt64(t[0]),
+				Type:   "buy",
+				Amount: dp.Decimal(t[2]),
+				Price:  dp.Decimal(t[3]),
+				Date:   ToInt64(t[1])}
+			if trade.Amount.Sign() < 0 {
+				trade.Type = "sell"
+				trade.Amount = trade.Amount.Neg()
+			}
+			if dp.Err != nil {
+				return dp.Err
+			}
+			select {
+			case ch <- trade:
+			case <-bfxWs.closeCh:
+			}
+			return nil
+		})
+	if err != nil {
+		return nil, err
+	}
+	return c.(chan Trade), nil
+}
+
+func (bfxWs *BitfinexWs) SubscribeKline(pair CurrencyPair, period int) (<-chan Kline, error) {
+	timeFrame, isok := wsKlinePeriods[period]
+	if !isok {
+		return nil, ErrNotSupported
+	}
+
+	candleKey := fmt.Sprintf("trade:%s:%s", timeFrame, bfxWs.symbol(pair))
+	ch := make(chan Kline, 64)
+	c, err := bfxWs.subscribe("candles:"+candleKey, map[string]interface{}{"event": "subscribe", "channel": "candles", "key": candleKey},
+		ch, func() { close(ch) }, func(data []interface{}) error {
+			//snapshot: [[MTS, OPEN, CLOSE, HIGH, LOW, VOLUME], ...] newest first, update: [MTS, OPEN, CLOSE, HIGH, LOW, VOLUME]
+			k, isok := data[0].([]interface{})
+			if !isok || len(k) == 0 {
+				return nil
+			}
+			if latest, isSnapshot := k[0].([]interface{}); isSnapshot {
+				k = latest
+			}
+			if len(k) < 6 {
+				return nil
+			}
+			kline := Kline{
+				Timestamp: ToInt64(k[0]) / 1000,
+				Open:      ToFloat64(k[1]),
+				Close:     ToFloat64(k[2]),
+				High:      ToFloat64(k[3]),
+				Low:       ToFloat64(k[4]),
+				Vol:       ToFloat64(k[5])}
+			select {
+			case ch <- kline:
+			case <-bfxWs.closeCh:
+			}
+			return nil
+		})
+	if err != nil {
+		return nil, err
+	}
+	return c.(chan Kline), nil
+}
+
+func (bfxWs *BitfinexWs) Close() error {
+	bfxWs.once.Do(func() { close(bfxWs.closeCh) })
+	bfxWs.l.Lock()
+	wsConn := bfxWs.wsConn
+	bfxWs.l.Unlock()
+	if wsConn == nil {
+		return nil
+	}
+	return wsConn.Close()
+}
+
+func (bfxWs *BitfinexWs) symbol(pair CurrencyPair) string {
+	bfx := &Bitfinex{}
+	return "t" + bfx.currencyPairToSymbol(bfx.adaptCurrencyPair(pair))
+}
+
+func (bfxWs *BitfinexWs) subscribe(key string, sub map[string]interface{}, ch interface{}, closer func(), handle func(data []interface{}) error) (interface{}, error) {
+	bfxWs.l.Lock()
+	defer bfxWs.l.Unlock()
+
+	select {
+	case <-bfxWs.closeCh:
+		return nil, EX_ERR_STREAM_CLOSED
+	default:
+	}
+	if c, isok := bfxWs.chans[key]; isok {
+		return c, nil
+	}
+
+	if bfxWs.wsConn == nil {
+		wsConn := NewWsConn(bfxWs.wsUrl, bfxWs.handle)
+		wsConn.ReadTimeout = time.Minute //bitfinex sends a heartbeat on every channel each 15s
+		wsConn.CloseHandleFunc = bfxWs.closeChans
+		//sent before any subscription, book channels then end every update with [chanId, "cs", checksum]
+		wsConn.Subscribe(map[string]interface{}{"event": "conf", "flags": bookChecksumFlag})
+		if err := wsConn.Connect(); err != nil {
+			return nil, err
+		}
+		bfxWs.wsConn = wsConn
+	}
+
+	bfxWs.chans[key] = ch
+	bfxWs.handles[key] = handle
+	bfxWs.closers = append(bfxWs.closers, closer)
+	bfxWs.wsConn.Subscribe(sub)
+	return ch, nil
+}
+
+func (bfxWs *BitfinexWs) handle(msg []byte) error {
+	if len(msg) > 0 && msg[0] == '{' {
+		return bfxWs.handleEvent(msg)
+	}
+
+	var data []interface{}
+	if err := json.Unmarshal(msg, &data); err != nil {
+		return err
+	}
+	if len(data) < 2 || data[1] == "hb" {
+		return nil
+	}
+
+	bfxWs.l.Lock()
+	handle := bfxWs.handles[bfxWs.chanIds[ToInt64(data[0])]]
+	bfxWs.l.Unlock()
+
+	if handle != nil {
+		return handle(data[1:])
+	}
+	return nil
+}
+
+func (bfxWs *BitfinexWs) handleEvent(msg []byte) error {
+	var event map[string]interface{}
+	if err := json.Unmarshal(msg, &event); err != nil {
+		return err
+	}
+
+	switch event["event"] {
+	case "subscribed":
+		var key string
+		switch channel := fmt.Sprint(event["channel"]); channel {
+		case "candles":
+			key = channel + ":" + fmt.Sprint(event["key"])
+		case "book":
+			key = channel + ":" + fmt.Sprint(event["symbol"]) + ":" + fmt.Sprint(event["len"])
+		default:
+			key = channel + ":" + fmt.Sprint(event["symbol"])
+		}
+		bfxWs.l.Lock()
+		for chanId, k := range bfxWs.chanIds {
+			if k == key {
+				delete(bfxWs.chanIds, chanId) //left from the connection before a reconnect
+			}
+		}
+		bfxWs.chanIds[ToInt64(event["chanId"])] = key
+		bfxWs.l.Unlock()
+	case "error":
+		return (&Bitfinex{}).errorWrapper(fmt.Sprint(event["msg"]))
+	}
+	return nil
+}
+
+func (bfxWs *BitfinexWs) closeChans() {
+	bfxWs.l.Lock()
+	defer bfxWs.l.Unlock()
+	for _, closer := range bfxWs.closers {
+		closer()
+	}
+	bfxWs.closers = nil
+}
+
+//resubscribe drops the channel of key and subscribes it again, bitfinex answers with a new snapshot
+func (bfxWs *BitfinexWs) resubscribe(key string, sub map[string]interface{}) {
+	bfxWs.l.Lock()
+	wsConn := bfxWs.wsConn
+	for chanId, k := range bfxWs.chanIds {
+		if k == key {
+			delete(bfxWs.chanIds, chanId)
+			wsConn.SendJsonMessage(map[string]interface{}{"event": "unsubscribe", "chanId": chanId})
+		}
+	}
+	bfxWs.l.Unlock()
+	wsConn.SendJsonMessage(sub)
+}
+
+//updateBook applies a book channel message to ob, returns false if it was not a book message.
+//snapshot: [[PRICE, COUNT, AMOUNT], ...], update: [PRICE, COUNT, AMOUNT], AMOUNT > 0 is a bid, COUNT = 0 removes the level
+func (bfxWs *BitfinexWs) updateBook(ob *OrderBook, data []interface{}) (bool, error) {
+	entries, isok := data[0].([]interface{})
+	if !isok || len(entries) == 0 {
+		return false, nil
+	}
+	_, isSnapshot := entries[0].([]interface{})
+	if !isSnapshot {
+		entries = []interface{}{entries}
+	}
+
+	var dp DecimalParser
+	var depth Depth
+	for _, entry := range entries {
+		e, isok := entry.([]interface{})
+		if !isok || len(e) < 3 {
+			continue
+		}
+		record := DepthRecord{Price: dp.Price(e[0]), Amount: dp.Decimal(e[2])}
+		isBid := record.Amount.Sign() > 0
+		if ToFloat64(e[1]) == 0 {
+			record.Amount = Decimal{}
+		} else if !isBid {
+			record.Amount = record.Amount.Neg()
+		}
+		if isBid {
+			depth.BidList = append(depth.BidList, record)
+		} else {
+			depth.AskList = append(depth.AskList, record)
+		}
+	}
+	if dp.Err != nil {
+		return true, dp.Err
+	}
+
+	if isSnapshot {
+		ob.Reset(&depth, 0)
+	} else {
+		//only fails while waiting for a new snapshot
+		ob.Update(OrderBookUpdate{Bids: depth.BidList, Asks: depth.AskList})
+	}
+	return true, nil
+}
+
+//checksum is the crc32 bitfinex computes over the top 25 levels as
+//"bidPrice:bidAmount:askPrice:-askAmount:..." with the numbers printed the javascript way
+func (bfxWs *BitfinexWs) checksum(top *Depth) int32 {
+	var fields []string
+	for i := 0; i < 25; i++ {
+		if i < len(top.BidList) {
+			fields = append(fields, jsNumber(top.BidList[i].Price.Float64()), jsNumber(top.BidList[i].Amount.Float64()))
+		}
+		if i < len(top.AskList) {
+			fields = append(fields, jsNumber(top.AskList[i].Price.Float64()), jsNumber(top.AskList[i].Amount.Neg().Float64()))
+		}
+	}
+	return int32(crc32.ChecksumIEEE([]byte(strings.Join(fields, ":"))))
+}
+
+//jsNumber prints f like javascript's Number.toString: exponents below 1e-6 and from 1e21 on, e.g. 1e-7
+func jsNumber(f float64) string {
+	if abs := math.Abs(f); abs != 0 && (abs < 1e-6 || abs >= 1e21) {
+		s := strconv.FormatFloat(f, 'e', -1, 64)
+		i := strings.IndexByte(s, 'e')
+		exp, _ := strconv.Atoi(s[i+1:])
+		return fmt.Sprintf("%se%+d", s[:i], exp)
+	}
+	return strconv.FormatFloat(f, 'f', -1, 64)
+}
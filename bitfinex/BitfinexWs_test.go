@@ -0,0 +1,219 @@
+package bitfinex
+
+import (
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/gorilla/websocket"
+	. "github.com/nntaoli-project/GoEx"
+	"github.com/stretchr/testify/assert"
+)
+
+//newWsStandIn confirms each subscribe with a fresh chanId and replays the canned pushes for it,
+//"%d" in a push is replaced by the chanId. Pushes under "key#n" are used for the n-th subscription
+//of key only. The first connection is dropped after its first subscription so the client has to
+//reconnect and resubscribe.
+func newWsStandIn(t *testing.T, pushes map[string][]string) (*httptest.Server, chan string) {
+	subscribed := make(chan string, 16)
+	upgrader := websocket.Upgrader{}
+	var conns, chanId int32
+	var l sync.Mutex
+	subCount := make(map[string]int)
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		conn, err := upgrader.Upgrade(w, r, nil)
+		if err != nil {
+			t.Error(err)
+			return
+		}
+		defer conn.Close()
+		first := atomic.AddInt32(&conns, 1) == 1
+		conn.WriteMessage(websocket.TextMessage, []byte(`{"event":"info","version":2}`))
+		for {
+			var sub map[string]interface{}
+			if err := conn.ReadJSON(&sub); err != nil {
+				return
+			}
+			switch sub["event"] {
+			case "conf":
+				conn.WriteMessage(websocket.TextMessage, []byte(`{"event":"conf","status":"OK"}`))
+				continue
+			case "unsubscribe":
+				conn.WriteJSON(map[string]interface{}{"event": "unsubscribed", "status": "OK", "chanId": sub["chanId"]})
+				select {
+				case subscribed <- fmt.Sprint("unsubscribe:", sub["chanId"]):
+				default:
+				}
+				continue
+			}
+			key := fmt.Sprint(sub["channel"], ":", sub["symbol"])
+			switch sub["channel"] {
+			case "candles":
+				key = fmt.Sprint(sub["channel"], ":", sub["key"])
+			case "book":
+				key = fmt.Sprint(sub["channel"], ":", sub["symbol"], ":", sub["len"])
+			}
+			id := atomic.AddInt32(&chanId, 1)
+			sub["event"] = "subscribed"
+			sub["chanId"] = id
+			conn.WriteJSON(sub)
+			select {
+			case subscribed <- key:
+			default:
+			}
+			conn.WriteMessage(websocket.TextMessage, []byte(fmt.Sprintf(`[%d,"hb"]`, id)))
+			l.Lock()
+			subCount[key]++
+			keyPushes, isok := pushes[fmt.Sprint(key, "#", subCount[key])]
+			l.Unlock()
+			if !isok {
+				keyPushes = pushes[key]
+			}
+			for _, push := range keyPushes {
+				conn.WriteMessage(websocket.TextMessage, []byte(fmt.Sprintf(push, id)))
+			}
+			if first {
+				return
+			}
+		}
+	}))
+	return srv, subscribed
+}
+
+func newTestBitfinexWs(srv *httptest.Server) *BitfinexWs {
+	bfxWs := NewBitfinexWs()
+	bfxWs.wsUrl = "ws" + strings.TrimPrefix(srv.URL, "http")
+	return bfxWs
+}
+
+func TestBitfinexWs_SubscribeTicker(t *testing.T) {
+	srv, subscribed := newWsStandIn(t, map[string][]string{
+		"ticker:tBTCUSD": {`[%d,[6100.1,10,6100.9,12,-50,-0.01,6100.5,1234.5,6200,6000]]`}})
+	defer srv.Close()
+
+	bfxWs := newTestBitfinexWs(srv)
+	ch, err := bfxWs.SubscribeTicker(BTC_USDT)
+	assert.Nil(t, err)
+
+	ticker := <-ch
+	assert.Equal(t, RequireDecimal("6100.5"), ticker.Last)
+	assert.Equal(t, RequireDecimal("6100.1"), ticker.Buy)
+	assert.Equal(t, RequireDecimal("6100.9"), ticker.Sell)
+	assert.Equal(t, RequireDecimal("6200"), ticker.High)
+	assert.Equal(t, "ticker:tBTCUSD", <-subscribed)
+
+	select {
+	case key := <-subscribed:
+		assert.Equal(t, "ticker:tBTCUSD", key)
+	case <-time.After(5 * time.Second):
+		t.Fatal("not resubscribed after reconnect")
+	}
+	//the new chanId must be routed to the same channel
+	assert.Equal(t, RequireDecimal("6100.5"), (<-ch).Last)
+
+	assert.Nil(t, bfxWs.Close())
+	for range ch {
+	}
+
+	_, err = bfxWs.SubscribeTicker(BTC_USDT)
+	assert.True(t, EX_ERR_STREAM_CLOSED.Is(err))
+	_, err = bfxWs.SubscribeTrades(BTC_USDT)
+	assert.True(t, EX_ERR_STREAM_CLOSED.Is(err))
+}
+
+func TestBitfinexWs_SubscribeDepthTradesKline(t *testing.T) {
+	srv, _ := newWsStandIn(t, map[string][]string{
+		"book:tBTCUSD:25": {
+			`[%d,[[6100.1,1,1.5],[6100.0,2,2],[6100.9,1,-0.5],[6101,1,-3]]]`,
+			`[%d,[6100.1,0,1]]`},
+		"trades:tBTCUSD": {
+			`[%d,[[1,1530000000000,0.5,6100]]]`,
+			`[%d,"te",[12345,1530000000000,-0.01,6100.5]]`,
+			`[%d,"tu",[12345,1530000000000,-0.01,6100.5]]`},
+		"candles:trade:1m:tBTCUSD": {
+			`[%d,[[1530000060000,1,2,3,0.5,10],[1530000000000,1,1,1,1,1]]]`}})
+	defer srv.Close()
+
+	bfxWs := newTestBitfinexWs(srv)
+	defer bfxWs.Close()
+
+	depthCh, err := bfxWs.SubscribeDepth(BTC_USD, 1)
+	assert.Nil(t, err)
+	depth := <-depthCh
+	assert.Equal(t, DepthRecords{{Price: RequireDecimal("6100.1"), Amount: RequireDecimal("1.5")}}, depth.BidList)
+	assert.Equal(t, DepthRecords{{Price: RequireDecimal("6100.9"), Amount: RequireDecimal("0.5")}}, depth.AskList)
+	depth = <-depthCh
+	assert.Equal(t, DepthRecords{{Price: RequireDecimal("6100.0"), Amount: RequireDecimal("2")}}, depth.BidList)
+
+	tradeCh, err := bfxWs.SubscribeTrades(BTC_USD)
+	assert.Nil(t, err)
+	assert.Equal(t, Trade{Tid: 12345, Type: "sell", Amount: RequireDecimal("0.01"), Price: RequireDecimal("6100.5"), Date: 1530000000000}, <-tradeCh)
+
+	klineCh, err := bfxWs.SubscribeKline(BTC_USD, KLINE_PERIOD_1MIN)
+	assert.Nil(t, err)
+	assert.Equal(t, Kline{Timestamp: 1530000060, Open: 1, Close: 2, High: 3, Low: 0.5, Vol: 10}, <-klineCh)
+
+	_, err = bfxWs.SubscribeKline(BTC_USD, KLINE_PERIOD_4H)
+	assert.Equal(t, ErrNotSupported, err)
+}
+
+func TestBitfinexWs_SubscribeOrderBook(t *testing.T) {
+	snapshot := `[%d,[[6100.1,1,1.5],[6100.0,2,2],[6100.9,1,-0.5],[6101,1,-3]]]`
+	srv, subscribed := newWsStandIn(t, map[string][]string{
+		//the second subscription, after the reconnect, is sent a wrong checksum
+		"book:tBTCUSD:250#2": {snapshot, `[%d,[6100.9,1,-0.7]]`, `[%d,"cs",1689675615]`},
+		"book:tBTCUSD:250":   {snapshot, `[%d,"cs",1689675615]`, `[%d,[6100.9,1,-0.7]]`, `[%d,"cs",-485080516]`}})
+	defer srv.Close()
+
+	bfxWs := newTestBitfinexWs(srv)
+	defer bfxWs.Close()
+
+	ob, err := bfxWs.SubscribeOrderBook(BTC_USD)
+	assert.Nil(t, err)
+	assert.Equal(t, "book:tBTCUSD:250", <-subscribed)
+	assert.Equal(t, "book:tBTCUSD:250", <-subscribed)
+	select {
+	case unsubscribe := <-subscribed:
+		assert.Equal(t, "unsubscribe:2", unsubscribe)
+	case <-time.After(5 * time.Second):
+		t.Fatal("not resubscribed after a checksum mismatch")
+	}
+	assert.Equal(t, "book:tBTCUSD:250", <-subscribed)
+
+	deadline := time.Now().Add(5 * time.Second)
+	for !(ob.Synced() && ob.AmountAt(RequireDecimal("6100.9")) == RequireDecimal("0.7")) && time.Now().Before(deadline) {
+		time.Sleep(10 * time.Millisecond)
+	}
+	assert.True(t, ob.Synced())
+	ask, _ := ob.BestAsk()
+	assert.Equal(t, DepthRecord{Price: RequireDecimal("6100.9"), Amount: RequireDecimal("0.7")}, ask)
+
+	same, err := bfxWs.SubscribeOrderBook(BTC_USD)
+	assert.Nil(t, err)
+	assert.True(t, ob == same)
+}
+
+func TestBitfinexWs_checksum(t *testing.T) {
+	bfxWs := NewBitfinexWs()
+	assert.Equal(t, int32(1689675615), bfxWs.checksum(&Depth{
+		BidList: DepthRecords{{Price: RequireDecimal("6100.1"), Amount: RequireDecimal("1.5")}, {Price: RequireDecimal("6100"), Amount: RequireDecimal("2")}},
+		AskList: DepthRecords{{Price: RequireDecimal("6100.9"), Amount: RequireDecimal("0.5")}, {Price: RequireDecimal("6101"), Amount: RequireDecimal("3")}}}))
+	assert.Equal(t, int32(740858964), bfxWs.checksum(&Depth{
+		BidList: DepthRecords{{Price: RequireDecimal("6100"), Amount: RequireDecimal("2")}},
+		AskList: DepthRecords{{Price: RequireDecimal("6100.9"), Amount: RequireDecimal("0.5")}, {Price: RequireDecimal("6101"), Amount: RequireDecimal("3")}}}))
+	assert.Equal(t, int32(481798910), bfxWs.checksum(&Depth{
+		BidList: DepthRecords{{Price: RequireDecimal("0.0000001"), Amount: RequireDecimal("1")}},
+		AskList: DepthRecords{{Price: RequireDecimal("2"), Amount: RequireDecimal("0.0000001")}}}))
+	assert.Equal(t, "1e+21", jsNumber(1e21))
+	assert.Equal(t, "0.000001", jsNumber(0.000001))
+}
+
+func TestBitfinexWs_handleError(t *testing.T) {
+	err := NewBitfinexWs().handle([]byte(`{"event":"error","msg":"symbol: invalid","code":10300}`))
+	assert.NotNil(t, err)
+}
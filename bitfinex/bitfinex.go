@@ -5,12 +5,13 @@ import (
 	"encoding/json"
 	"errors"
 	"fmt"
+	"hash/fnv"
 	"net/http"
+	"net/url"
 	"strconv"
 	"strings"
 	"time"
 
-	"bitbucket.org/i0n/compounda/exchanges"
 	. "github.com/nntaoli-project/GoEx"
 )
 
@@ -18,73 +19,123 @@ type Bitfinex struct {
 	httpClient *http.Client
 	accessKey,
 	secretKey string
+	symbols *SymbolInfoCache
 }
 
 const (
 	EXCHANGE_NAME = "bitfinex.com"
 
-	BASE_URL = "https://api.bitfinex.com/v1"
+	BASE_URL    = "https://api.bitfinex.com/v1"
+	BASE_URL_V2 = "https://api.bitfinex.com/v2"
 )
 
 func New(client *http.Client, accessKey, secretKey string) *Bitfinex {
-	return &Bitfinex{client, accessKey, secretKey}
+	client = ExchangeRateLimitedClient(client, EXCHANGE_NAME, accessKey, RateLimits)
+	return &Bitfinex{httpClient: client, accessKey: accessKey, secretKey: secretKey, symbols: new(SymbolInfoCache)}
 }
 
 func (bfx *Bitfinex) GetExchangeName() string {
 	return EXCHANGE_NAME
 }
 
-// GetSymbols Get all trade symbol pairs
-func (bfx *Bitfinex) GetSymbols() (map[string]interface{}, error) {
+func (bfx *Bitfinex) Capabilities() Capabilities {
+	return Capabilities{MarketOrder: true, Kline: true, Withdraw: true, Deposit: true, Trades: true, Margin: true, ClientOrder: true, TradeFee: true, MyTrades: true, OrderCursor: true}
+}
+
+// GetSymbols Get all trade symbol pairs, as "btcusd"
+func (bfx *Bitfinex) GetSymbols() ([]string, error) {
 	apiUrl := fmt.Sprintf("%s/symbols", BASE_URL)
-	resp, err := HttpGet(bfx.httpClient, apiUrl)
+	resp, err := NewHttpRequest(bfx.httpClient, "GET", apiUrl, "", nil)
 	if err != nil {
-		return nil, err
+		return nil, bfx.adaptError(err)
 	}
 
-	if resp["error"] != nil {
-		return nil, errors.New(resp["error"].(string))
+	var symbols []string
+	err = json.Unmarshal(resp, &symbols)
+	if err != nil {
+		return nil, errors.New(string(resp))
+	}
+	return symbols, nil
+}
+
+// GetMarkets prices on bitfinex are limited to price_precision significant digits and 8 decimal places,
+// amounts to 8 decimal places
+func (bfx *Bitfinex) GetMarkets() ([]SymbolInfo, error) {
+	apiUrl := fmt.Sprintf("%s/symbols_details", BASE_URL)
+	resp, err := NewHttpRequest(bfx.httpClient, "GET", apiUrl, "", nil)
+	if err != nil {
+		return nil, bfx.adaptError(err)
+	}
+
+	var details []struct {
+		Pair             string  `json:"pair"`
+		PricePrecision   int32   `json:"price_precision"`
+		MinimumOrderSize Decimal `json:"minimum_order_size"`
+		MaximumOrderSize Decimal `json:"maximum_order_size"`
+	}
+	err = json.Unmarshal(resp, &details)
+	if err != nil {
+		return nil, errors.New(string(resp))
 	}
-	for k, v := range resp {
-		fmt.Println(k)
-		fmt.Println(v)
-		fmt.Println("")
+
+	markets := make([]SymbolInfo, 0, len(details))
+	for _, d := range details {
+		markets = append(markets, SymbolInfo{
+			Pair:            bfx.symbolToCurrencyPair(d.Pair),
+			Symbol:          d.Pair,
+			PricePrecision:  8,
+			AmountPrecision: 8,
+			TickSize:        NewDecimal(1, -8),
+			StepSize:        NewDecimal(1, -8),
+			PriceDigits:     d.PricePrecision,
+			MinAmount:       d.MinimumOrderSize,
+			MaxAmount:       d.MaximumOrderSize})
 	}
-	return resp, nil
+	return markets, nil
+}
+
+// GetSymbolInfo the pair is looked up the way bitfinex names it, so DASH_USDT finds DSH_USD
+func (bfx *Bitfinex) GetSymbolInfo(pair CurrencyPair) (*SymbolInfo, error) {
+	return bfx.symbols.Get(bfx.adaptCurrencyPair(pair), bfx.GetMarkets)
 }
 
 func (bfx *Bitfinex) GetTicker(currencyPair CurrencyPair) (*Ticker, error) {
+	var dp DecimalParser
 	//pubticker
 	currencyPair = bfx.adaptCurrencyPair(currencyPair)
 
 	apiUrl := fmt.Sprintf("%s/pubticker/%s", BASE_URL, strings.ToLower(currencyPair.ToSymbol("")))
 	resp, err := HttpGet(bfx.httpClient, apiUrl)
 	if err != nil {
-		return nil, err
+		return nil, bfx.adaptError(err)
 	}
 
 	if resp["error"] != nil {
-		return nil, errors.New(resp["error"].(string))
+		return nil, bfx.errorWrapper(resp["error"].(string))
 	}
 
 	//fmt.Println(resp)
 	ticker := new(Ticker)
-	ticker.Last = ToFloat64(resp["last_price"])
-	ticker.Vol = ToFloat64(resp["volume"])
-	ticker.High = ToFloat64(resp["high"])
-	ticker.Low = ToFloat64(resp["low"])
-	ticker.Sell = ToFloat64(resp["ask"])
-	ticker.Buy = ToFloat64(resp["bid"])
+	ticker.Last = dp.Decimal(resp["last_price"])
+	ticker.Vol = dp.Decimal(resp["volume"])
+	ticker.High = dp.Decimal(resp["high"])
+	ticker.Low = dp.Decimal(resp["low"])
+	ticker.Sell = dp.Decimal(resp["ask"])
+	ticker.Buy = dp.Decimal(resp["bid"])
 	ticker.Date = uint64(bfx.adaptTimestamp(resp["timestamp"].(string)))
+	if dp.Err != nil {
+		return nil, dp.Err
+	}
 	return ticker, nil
 }
 
 func (bfx *Bitfinex) GetDepth(size int, currencyPair CurrencyPair) (*Depth, error) {
+	var dp DecimalParser
 	currencyPair = bfx.adaptCurrencyPair(currencyPair)
 	apiUrl := fmt.Sprintf("%s/book/%s?limit_bids=%d&limit_asks=%d", BASE_URL, bfx.currencyPairToSymbol(currencyPair), size, size)
 	resp, err := HttpGet(bfx.httpClient, apiUrl)
 	if err != nil {
-		return nil, err
+		return nil, bfx.adaptError(err)
 	}
 	//println("resp:", resp)
 	bids := resp["bids"].([]interface{})
@@ -94,34 +145,173 @@ func (bfx *Bitfinex) GetDepth(size int, currencyPair CurrencyPair) (*Depth, erro
 
 	for _, bid := range bids {
 		_bid := bid.(map[string]interface{})
-		amount := ToFloat64(_bid["amount"])
-		price := ToFloat64(_bid["price"])
+		amount := dp.Decimal(_bid["amount"])
+		price := dp.Price(_bid["price"])
 		dr := DepthRecord{Amount: amount, Price: price}
 		depth.BidList = append(depth.BidList, dr)
 	}
 
 	for _, ask := range asks {
 		_ask := ask.(map[string]interface{})
-		amount := ToFloat64(_ask["amount"])
-		price := ToFloat64(_ask["price"])
+		amount := dp.Decimal(_ask["amount"])
+		price := dp.Price(_ask["price"])
 		dr := DepthRecord{Amount: amount, Price: price}
 		depth.AskList = append(depth.AskList, dr)
 	}
 
+	if dp.Err != nil {
+		return nil, dp.Err
+	}
 	return depth, nil
 }
 
+var klinePeriods = map[int]string{
+	KLINE_PERIOD_1MIN:   "1m",
+	KLINE_PERIOD_5MIN:   "5m",
+	KLINE_PERIOD_15MIN:  "15m",
+	KLINE_PERIOD_30MIN:  "30m",
+	KLINE_PERIOD_60MIN:  "1h",
+	KLINE_PERIOD_1DAY:   "1D",
+	KLINE_PERIOD_1WEEK:  "7D",
+	KLINE_PERIOD_1MONTH: "1M",
+}
+
+//GetKlineRecords reads the v2 candles, which have no 4 hour period
 func (bfx *Bitfinex) GetKlineRecords(currencyPair CurrencyPair, period, size, since int) ([]Kline, error) {
-	panic("not implement")
+	timeframe, ok := klinePeriods[period]
+	if !ok {
+		return nil, UnsupportedKlinePeriod(period)
+	}
+	params := url.Values{}
+	params.Set("limit", strconv.Itoa(size))
+	if since > 0 {
+		params.Set("start", strconv.Itoa(since))
+		params.Set("sort", "1")
+	}
+	path := fmt.Sprintf("%s/candles/trade:%s:t%s/hist?%s", BASE_URL_V2, timeframe, bfx.currencyPairToSymbol(currencyPair), params.Encode())
+	resp, err := NewHttpRequest(bfx.httpClient, "GET", path, "", nil)
+	if err != nil {
+		return nil, bfx.adaptError(err)
+	}
+	var records [][]interface{}
+	err = json.Unmarshal(resp, &records)
+	if err != nil {
+		return nil, err
+	}
+
+	//[MTS, OPEN, CLOSE, HIGH, LOW, VOLUME], newest first unless sorted
+	klines := make([]Kline, 0, len(records))
+	for _, r := range records {
+		if len(r) < 6 {
+			continue
+		}
+		klines = append(klines, Kline{
+			Timestamp: ToInt64(r[0]) / 1000,
+			Open:      ToFloat64(r[1]),
+			Close:     ToFloat64(r[2]),
+			High:      ToFloat64(r[3]),
+			Low:       ToFloat64(r[4]),
+			Vol:       ToFloat64(r[5])})
+	}
+	return KlinesSince(klines, int64(since), size), nil
 }
 
 //非个人，整个交易所的交易记录
-
+//GetTrades answers up to 1000 trades from since on, or the latest when since is 0
 func (bfx *Bitfinex) GetTrades(currencyPair CurrencyPair, since int64) ([]Trade, error) {
-	panic("not implement")
+	var dp DecimalParser
+	params := url.Values{}
+	params.Set("limit", "1000")
+	if since > 0 {
+		params.Set("start", strconv.FormatInt(since, 10))
+		params.Set("sort", "1")
+	}
+	path := fmt.Sprintf("%s/trades/t%s/hist?%s", BASE_URL_V2, bfx.currencyPairToSymbol(currencyPair), params.Encode())
+	resp, err := NewHttpRequest(bfx.httpClient, "GET", path, "", nil)
+	if err != nil {
+		return nil, bfx.adaptError(err)
+	}
+	var records [][]interface{}
+	err = json.Unmarshal(resp, &records)
+	if err != nil {
+		return nil, err
+	}
+
+	//[ID, MTS, AMOUNT, PRICE], the amount negative for sells
+	trades := make([]Trade, 0, len(records))
+	for _, r := range records {
+		if len(r) < 4 {
+			continue
+		}
+		trade := Trade{
+			Tid:    ToInt64(r[0]),
+			Type:   "buy",
+			Amount: dp.Decimal(r[2]),
+			Price:  dp.Decimal(r[3]),
+			Date:   ToInt64(r[1])}
+		if trade.Amount.Sign() < 0 {
+			trade.Type = "sell"
+			trade.Amount = trade.Amount.Neg()
+		}
+		trades = append(trades, trade)
+	}
+	if dp.Err != nil {
+		return nil, dp.Err
+	}
+	return TradesSinceTime(trades, since), nil
+}
+
+//GetMyTrades returns the fills from since, oldest first with reverse. The v1 api does not tell
+//maker from taker, IsMaker stays false.
+func (bfx *Bitfinex) GetMyTrades(pair CurrencyPair, since int64, limit int) ([]MyTrade, error) {
+	var dp DecimalParser
+	payload := map[string]interface{}{
+		"symbol":    bfx.currencyPairToSymbol(pair),
+		"timestamp": fmt.Sprintf("%.3f", float64(since)/1000),
+		"reverse":   1}
+	if limit > 0 {
+		payload["limit_trades"] = limit
+	}
+	var fills []struct {
+		Price       string `json:"price"`
+		Amount      string `json:"amount"`
+		Timestamp   string `json:"timestamp"`
+		Type        string `json:"type"`
+		FeeCurrency string `json:"fee_currency"`
+		FeeAmount   string `json:"fee_amount"`
+		Tid         int64  `json:"tid"`
+		OrderId     int64  `json:"order_id"`
+	}
+	err := bfx.doAuthenticatedRequest("POST", "mytrades", payload, &fills)
+	if err != nil {
+		return nil, err
+	}
+
+	trades := make([]MyTrade, 0, len(fills))
+	for _, f := range fills {
+		side := BUY
+		if strings.EqualFold(f.Type, "sell") {
+			side = SELL
+		}
+		trades = append(trades, MyTrade{
+			TradeID:     strconv.FormatInt(f.Tid, 10),
+			OrderID:     strconv.FormatInt(f.OrderId, 10),
+			Currency:    pair,
+			Side:        TradeSide(side),
+			Price:       dp.Decimal(f.Price),
+			Amount:      dp.Decimal(f.Amount),
+			Fee:         dp.Decimal(f.FeeAmount).Abs(),
+			FeeCurrency: NewCurrency(f.FeeCurrency, ""),
+			Time:        int64(ToFloat64(f.Timestamp) * 1000)})
+	}
+	if dp.Err != nil {
+		return nil, dp.Err
+	}
+	return MyTradesSince(trades, since, limit), nil
 }
 
 func (bfx *Bitfinex) GetWalletBalances() (map[string]*Account, error) {
+	var dp DecimalParser
 	var respmap []interface{}
 	err := bfx.doAuthenticatedRequest("GET", "balances", map[string]interface{}{}, &respmap)
 	if err != nil {
@@ -142,8 +332,8 @@ func (bfx *Bitfinex) GetWalletBalances() (map[string]*Account, error) {
 		}
 
 		//typeS := subacc["type"].(string)
-		amount := ToFloat64(subacc["amount"])
-		available := ToFloat64(subacc["available"])
+		amount := dp.Decimal(subacc["amount"])
+		available := dp.Decimal(subacc["available"])
 
 		account := walletmap[typeStr]
 		if account == nil {
@@ -152,17 +342,20 @@ func (bfx *Bitfinex) GetWalletBalances() (map[string]*Account, error) {
 			account.SubAccounts = make(map[Currency]SubAccount)
 		}
 
-		account.NetAsset = amount
-		account.Asset = amount
+		account.NetAsset = amount.Float64()
+		account.Asset = amount.Float64()
 		account.SubAccounts[currency] = SubAccount{
 			Currency:     currency,
 			Amount:       available,
-			FrozenAmount: amount - available,
-			LoanAmount:   0}
+			FrozenAmount: amount.Sub(available),
+			LoanAmount:   Decimal{}}
 
 		walletmap[typeStr] = account
 	}
 
+	if dp.Err != nil {
+		return nil, dp.Err
+	}
 	return walletmap, nil
 }
 
@@ -175,79 +368,276 @@ func (bfx *Bitfinex) GetAccount() (*Account, error) {
 	return wallets["exchange"], nil
 }
 
-func (bfx *Bitfinex) Withdraw(currencyPair CurrencyPair, address CryptoAddressReader, amount float64, wallet string, adminPassword string) (*Withdraw, error) {
-	var amountMinusTransactionFee float64
+//withdrawTypes are the withdraw_type of each currency
+var withdrawTypes = map[Currency]string{
+	BTC:  "bitcoin",
+	BCH:  "bcash",
+	LTC:  "litecoin",
+	ETH:  "ethereum",
+	ETC:  "ethereumc",
+	ZEC:  "zcash",
+	XMR:  "monero",
+	DASH: "dash",
+	XRP:  "ripple",
+	EOS:  "eos",
+	NEO:  "neo",
+	AVT:  "aventus",
+	QTUM: "qtum",
+	EDO:  "eidoo",
+	REP:  "augur", // REP is undocumented...
+}
+
+//Withdraw takes req.Amount from the wallet, bitfinex's fee included: req.Fee, or the one in WithdrawFees when zero
+func (bfx *Bitfinex) Withdraw(req WithdrawRequest) (*Withdraw, error) {
 	path := "withdraw"
-	var c string
-	switch x := currencyPair.CurrencyA; x {
-	case BTC:
-		c = "bitcoin"
-		amountMinusTransactionFee = amount - exchanges.All["bitfinex.com"].WithdrawFees[x]
-	case BCH:
-		c = "bcash"
-		amountMinusTransactionFee = amount - exchanges.All["bitfinex.com"].WithdrawFees[x]
-	case LTC:
-		c = "litecoin"
-		amountMinusTransactionFee = amount - exchanges.All["bitfinex.com"].WithdrawFees[x]
-	case ETH:
-		c = "ethereum"
-		amountMinusTransactionFee = amount - exchanges.All["bitfinex.com"].WithdrawFees[x]
-	case ETC:
-		c = "ethereumc"
-		amountMinusTransactionFee = amount - exchanges.All["bitfinex.com"].WithdrawFees[x]
-	case ZEC:
-		c = "zcash"
-		amountMinusTransactionFee = amount - exchanges.All["bitfinex.com"].WithdrawFees[x]
-	case XMR:
-		c = "monero"
-		amountMinusTransactionFee = amount - exchanges.All["bitfinex.com"].WithdrawFees[x]
-	case DASH:
-		c = "dash"
-		amountMinusTransactionFee = amount - exchanges.All["bitfinex.com"].WithdrawFees[x]
-	case XRP:
-		c = "ripple"
-		amountMinusTransactionFee = amount - exchanges.All["bitfinex.com"].WithdrawFees[x]
-	case EOS:
-		c = "eos"
-		amountMinusTransactionFee = amount - exchanges.All["bitfinex.com"].WithdrawFees[x]
-	case NEO:
-		c = "neo"
-		amountMinusTransactionFee = amount - exchanges.All["bitfinex.com"].WithdrawFees[x]
-	case AVT:
-		c = "aventus"
-		amountMinusTransactionFee = amount - exchanges.All["bitfinex.com"].WithdrawFees[x]
-	case QTUM:
-		c = "qtum"
-		amountMinusTransactionFee = amount - exchanges.All["bitfinex.com"].WithdrawFees[x]
-	case EDO:
-		c = "eidoo"
-		amountMinusTransactionFee = amount - exchanges.All["bitfinex.com"].WithdrawFees[x]
-	// REP is undocumented...
-	case REP:
-		c = "augur"
-		amountMinusTransactionFee = amount - exchanges.All["bitfinex.com"].WithdrawFees[x]
-	default:
+	c, ok := withdrawTypes[req.Currency]
+	if !ok {
 		return nil, errors.New("Unsupported currency type")
 	}
-	a := strconv.FormatFloat(amountMinusTransactionFee, 'f', -1, 64)
+	fee, err := req.WithdrawFee(EXCHANGE_NAME)
+	if err != nil {
+		return nil, err
+	}
+	//the fee comes out of req.Amount, reject what would leave nothing to send before signing
+	if !req.Amount.GreaterThan(fee) {
+		errCode := EX_ERR_INVALID_WITHDRAW_AMOUNT
+		errCode.OriginErrMsg = fmt.Sprintf("amount %s, fee %s", req.Amount, fee)
+		return nil, errCode
+	}
 	params := map[string]interface{}{
 		"withdraw_type":  c,
-		"walletselected": wallet,
-		"address":        address.Address(),
-		"amount":         a,
+		"walletselected": req.Wallet,
+		"address":        req.Address,
+		"amount":         req.Amount.Sub(fee).String(),
 	}
-	var res []Withdraw
-	err := bfx.doAuthenticatedRequest("POST", path, params, &res)
+	if req.Tag != "" {
+		params["payment_id"] = req.Tag
+	}
+	var res []struct {
+		Status       string `json:"status"`
+		Message      string `json:"message"`
+		WithdrawalID int64  `json:"withdrawal_id"`
+	}
+	err = bfx.doAuthenticatedRequest("POST", path, params, &res)
 	if err != nil {
 		return nil, err
 	}
+	if len(res) == 0 {
+		return nil, errors.New("empty withdraw response")
+	}
 	if res[0].Status != "success" {
-		return &res[0], errors.New(res[0].Message)
+		return nil, bfx.errorWrapper(res[0].Message)
+	}
+	return &Withdraw{
+		ID:       strconv.FormatInt(res[0].WithdrawalID, 10),
+		Currency: req.Currency,
+		Amount:   req.Amount,
+		Fee:      fee,
+		Address:  req.Address,
+		Tag:      req.Tag,
+		Status:   WITHDRAW_PENDING,
+		Time:     time.Now().Unix()}, nil
+}
+
+func (bfx *Bitfinex) CancelWithdraw(id string, currency Currency, tradePassword string) (bool, error) {
+	return false, ErrNotSupported
+}
+
+//movement is a deposit or withdrawal in history/movements
+type movement struct {
+	Id        int64       `json:"id"`
+	TxId      interface{} `json:"txid"`
+	Type      string      `json:"type"`
+	Amount    interface{} `json:"amount"`
+	Fee       interface{} `json:"fee"`
+	Address   string      `json:"address"`
+	Status    string      `json:"status"`
+	Timestamp string      `json:"timestamp"`
+}
+
+func (m movement) txId() string {
+	if m.TxId == nil {
+		return ""
+	}
+	return fmt.Sprint(m.TxId)
+}
+
+//movements are the last 500 deposits and withdrawals of the currency, newest first
+func (bfx *Bitfinex) movements(currency Currency) ([]movement, error) {
+	var movements []movement
+	err := bfx.doAuthenticatedRequest("POST", "history/movements", map[string]interface{}{"currency": currency.String()}, &movements)
+	return movements, err
+}
+
+//GetWithdraw finds the withdrawal in the last 500 deposits and withdrawals of the currency
+func (bfx *Bitfinex) GetWithdraw(id string, currency Currency) (*Withdraw, error) {
+	var dp DecimalParser
+	movements, err := bfx.movements(currency)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, m := range movements {
+		if m.Type != "WITHDRAWAL" || strconv.FormatInt(m.Id, 10) != id {
+			continue
+		}
+		w := &Withdraw{
+			ID:       id,
+			Currency: currency,
+			Amount:   dp.Decimal(m.Amount).Abs(),
+			Fee:      dp.Decimal(m.Fee).Abs(),
+			Address:  m.Address,
+			TxID:     m.txId(),
+			Time:     int64(ToFloat64(m.Timestamp))}
+		switch status := strings.ToUpper(m.Status); {
+		case status == "COMPLETED":
+			w.Status = WITHDRAW_COMPLETED
+		case strings.Contains(status, "CANCEL"):
+			w.Status = WITHDRAW_CANCELED
+		case strings.Contains(status, "FAIL"):
+			w.Status = WITHDRAW_FAILED
+		case strings.Contains(status, "PROCESS"), strings.Contains(status, "SENDING"):
+			w.Status = WITHDRAW_PROCESSING
+		default:
+			w.Status = WITHDRAW_PENDING
+		}
+		if dp.Err != nil {
+			return nil, dp.Err
+		}
+		return w, nil
+	}
+
+	errCode := EX_ERR_NOT_FIND_WITHDRAW
+	errCode.OriginErrMsg = id
+	return nil, errCode
+}
+
+//GetDepositAddress returns the address of the exchange wallet
+func (bfx *Bitfinex) GetDepositAddress(currency Currency) (*CryptoAddress, error) {
+	method, ok := withdrawTypes[currency]
+	if !ok {
+		return nil, errors.New("Unsupported currency type")
+	}
+	var res struct {
+		Result      string `json:"result"`
+		Message     string `json:"message"`
+		Address     string `json:"address"`
+		AddressPool string `json:"address_pool"`
+	}
+	err := bfx.doAuthenticatedRequest("POST", "deposit/new", map[string]interface{}{
+		"method":      method,
+		"wallet_name": "exchange",
+		"renew":       0}, &res)
+	if err != nil {
+		return nil, err
+	}
+	if res.Result != "success" {
+		//the address field holds the reason when no message does
+		if res.Message != "" {
+			return nil, bfx.errorWrapper(res.Message)
+		}
+		return nil, bfx.errorWrapper(res.Address)
+	}
+	addr := &CryptoAddress{Currency: currency, Address: res.Address, ExchangeName: EXCHANGE_NAME}
+	//currencies sharing one address, as ripple, tell deposits apart by tag
+	if res.AddressPool != "" {
+		addr.Address, addr.Tag = res.AddressPool, res.Address
+	}
+	return addr, nil
+}
+
+//GetTradeFee returns the rates of account_infos for the pair's base currency, bitfinex gives them in percent
+func (bfx *Bitfinex) GetTradeFee(pair CurrencyPair) (*TradeFee, error) {
+	var dp DecimalParser
+	type fees struct {
+		Pairs     string `json:"pairs"`
+		MakerFees string `json:"maker_fees"`
+		TakerFees string `json:"taker_fees"`
+	}
+	var res []struct {
+		fees
+		Fees []fees `json:"fees"`
+	}
+	err := bfx.doAuthenticatedRequest("POST", "account_infos", map[string]interface{}{}, &res)
+	if err != nil {
+		return nil, err
+	}
+	if len(res) == 0 {
+		return nil, errors.New("no account infos")
 	}
-	return &res[0], nil
+	rates := res[0].fees
+	for _, f := range res[0].Fees {
+		if strings.EqualFold(f.Pairs, pair.CurrencyA.Symbol) {
+			rates = f
+			break
+		}
+	}
+	percent := NewDecimalFromInt(100)
+	fee := &TradeFee{
+		Maker: dp.Decimal(rates.MakerFees).Div(percent),
+		Taker: dp.Decimal(rates.TakerFees).Div(percent)}
+	if dp.Err != nil {
+		return nil, dp.Err
+	}
+	return fee, nil
+}
+
+//GetWithdrawFee returns the fee of account_fees, bitfinex takes it out of the amount withdrawn
+func (bfx *Bitfinex) GetWithdrawFee(currency Currency) (Decimal, error) {
+	var res struct {
+		Withdraw map[string]interface{} `json:"withdraw"`
+	}
+	err := bfx.doAuthenticatedRequest("POST", "account_fees", map[string]interface{}{}, &res)
+	if err != nil {
+		return Decimal{}, err
+	}
+	fee, ok := res.Withdraw[strings.ToUpper(currency.Symbol)]
+	if !ok {
+		errCode := EX_ERR_UNKNOWN_FEE
+		errCode.OriginErrMsg = EXCHANGE_NAME + " " + currency.Symbol
+		return Decimal{}, errCode
+	}
+	return ToDecimalE(fee)
+}
+
+//GetDeposits pages the deposits among the last 500 movements
+func (bfx *Bitfinex) GetDeposits(currency Currency, currentPage, pageSize int) ([]Deposit, error) {
+	var dp DecimalParser
+	movements, err := bfx.movements(currency)
+	if err != nil {
+		return nil, err
+	}
+
+	var deposits []Deposit
+	for _, m := range movements {
+		if m.Type != "DEPOSIT" {
+			continue
+		}
+		d := Deposit{
+			ID:       strconv.FormatInt(m.Id, 10),
+			Currency: currency,
+			Amount:   dp.Decimal(m.Amount),
+			Address:  m.Address,
+			TxID:     m.txId(),
+			Time:     int64(ToFloat64(m.Timestamp))}
+		switch status := strings.ToUpper(m.Status); {
+		case status == "COMPLETED":
+			d.Status = DEPOSIT_COMPLETED
+		case strings.Contains(status, "CANCEL"), strings.Contains(status, "FAIL"):
+			d.Status = DEPOSIT_FAILED
+		default:
+			d.Status = DEPOSIT_PENDING
+		}
+		deposits = append(deposits, d)
+	}
+	if dp.Err != nil {
+		return nil, dp.Err
+	}
+	return DepositsPage(deposits, currentPage, pageSize), nil
 }
 
 func (bfx *Bitfinex) placeOrder(orderType, side, amount, price string, pair CurrencyPair) (*Order, error) {
+	var dp DecimalParser
 	path := "order/new"
 	params := map[string]interface{}{
 		"symbol":   bfx.currencyPairToSymbol(pair),
@@ -266,10 +656,10 @@ func (bfx *Bitfinex) placeOrder(orderType, side, amount, price string, pair Curr
 	order := new(Order)
 	order.Currency = pair
 	order.OrderID = ToInt(respmap["id"])
-	order.Amount = ToFloat64(amount)
-	order.Price = ToFloat64(price)
-	order.AvgPrice = ToFloat64(respmap["avg_execution_price"])
-	order.DealAmount = ToFloat64(respmap["executed_amount"])
+	order.Amount = dp.Decimal(amount)
+	order.Price = dp.Decimal(price)
+	order.AvgPrice = dp.Decimal(respmap["avg_execution_price"])
+	order.DealAmount = dp.Decimal(respmap["executed_amount"])
 	order.Status = ORDER_UNFINISH
 
 	switch side {
@@ -287,26 +677,167 @@ func (bfx *Bitfinex) placeOrder(orderType, side, amount, price string, pair Curr
 		}
 
 	}
+	if dp.Err != nil {
+		return nil, dp.Err
+	}
 	return order, nil
 }
 
-func (bfx *Bitfinex) LimitBuy(amount, price string, currencyPair CurrencyPair) (*Order, error) {
-	return bfx.placeOrder("exchange limit", "buy", amount, price, currencyPair)
+func (bfx *Bitfinex) LimitBuy(amount, price Decimal, currencyPair CurrencyPair) (*Order, error) {
+	return bfx.placeOrder("exchange limit", "buy", amount.String(), price.String(), currencyPair)
 }
 
-func (bfx *Bitfinex) LimitSell(amount, price string, currencyPair CurrencyPair) (*Order, error) {
-	return bfx.placeOrder("exchange limit", "sell", amount, price, currencyPair)
+func (bfx *Bitfinex) LimitSell(amount, price Decimal, currencyPair CurrencyPair) (*Order, error) {
+	return bfx.placeOrder("exchange limit", "sell", amount.String(), price.String(), currencyPair)
 }
 
-func (bfx *Bitfinex) MarketBuy(amount, price string, currencyPair CurrencyPair) (*Order, error) {
-	return bfx.placeOrder("exchange market", "buy", amount, price, currencyPair)
+func (bfx *Bitfinex) MarketBuy(amount, price Decimal, currencyPair CurrencyPair) (*Order, error) {
+	return bfx.placeOrder("exchange market", "buy", amount.String(), price.String(), currencyPair)
 }
 
-func (bfx *Bitfinex) MarketSell(amount, price string, currencyPair CurrencyPair) (*Order, error) {
-	return bfx.placeOrder("exchange market", "sell", amount, price, currencyPair)
+func (bfx *Bitfinex) MarketSell(amount, price Decimal, currencyPair CurrencyPair) (*Order, error) {
+	return bfx.placeOrder("exchange market", "sell", amount.String(), price.String(), currencyPair)
+}
+
+//PlaceOrderWithClientID places the order with the v2 api, whose cid is an integer that bitfinex tells apart by
+//the utc date it was placed on (cid_date). The client order id is hashed to the cid, and GetOneOrder and
+//CancelOrder take the client order id in place of the order id for an order placed today or yesterday.
+func (bfx *Bitfinex) PlaceOrderWithClientID(clientOrderId string, side TradeSide, amount, price Decimal, currencyPair CurrencyPair) (*Order, error) {
+	if !ValidClientOrderID(clientOrderId) {
+		errCode := EX_ERR_INVALID_CLIENT_ORDER_ID
+		errCode.OriginErrMsg = clientOrderId
+		return nil, errCode
+	}
+	body := map[string]interface{}{
+		"type":   "EXCHANGE LIMIT",
+		"symbol": "t" + bfx.currencyPairToSymbol(currencyPair),
+		"amount": amount.String(),
+		"price":  price.String(),
+		"cid":    bfx.cid(clientOrderId)}
+	switch side {
+	case BUY:
+	case SELL:
+		body["amount"] = amount.Neg().String()
+	case BUY_MARKET:
+		body["type"] = "EXCHANGE MARKET"
+	case SELL_MARKET:
+		body["type"] = "EXCHANGE MARKET"
+		body["amount"] = amount.Neg().String()
+	default:
+		errCode := EX_ERR_PLACE_ORDER_FAIL
+		errCode.OriginErrMsg = "unknown side " + side.String()
+		return nil, errCode
+	}
+
+	//[MTS, TYPE, MESSAGE_ID, null, [ORDER, ...], CODE, STATUS, TEXT]
+	var resp []interface{}
+	if err := bfx.doAuthenticatedRequestV2("auth/w/order/submit", body, &resp); err != nil {
+		return nil, err
+	}
+	if len(resp) < 8 || resp[6] != "SUCCESS" {
+		return nil, bfx.notificationError(resp)
+	}
+	rows, _ := resp[4].([]interface{})
+	if len(rows) == 0 {
+		return nil, bfx.notificationError(resp)
+	}
+	row, isok := rows[0].([]interface{})
+	if !isok || len(row) < 18 {
+		return nil, bfx.notificationError(resp)
+	}
+	ord, err := bfx.toOrderV2(row, currencyPair)
+	if err != nil {
+		return nil, err
+	}
+	ord.ClientOrderID = clientOrderId
+	return &ord, nil
+}
+
+//cid hashes a client order id to a bitfinex cid, a positive integer of at most 45 bits
+func (bfx *Bitfinex) cid(clientOrderId string) int64 {
+	h := fnv.New64a()
+	h.Write([]byte(clientOrderId))
+	return int64(h.Sum64()&(1<<45-1)) | 1
+}
+
+//cidDates are the utc dates an order looked up by client order id may have been placed on, today first
+func (bfx *Bitfinex) cidDates(now time.Time) []string {
+	now = now.UTC()
+	return []string{now.Format("2006-01-02"), now.AddDate(0, 0, -1).Format("2006-01-02")}
+}
+
+//isClientOrderId tells a client order id from Bitfinex's own order ids, which are integers
+func isClientOrderId(orderId string) bool {
+	_, err := strconv.ParseInt(orderId, 10, 64)
+	return err != nil
+}
+
+//notificationError reads the STATUS and TEXT of a v2 notification that did not succeed
+func (bfx *Bitfinex) notificationError(resp []interface{}) error {
+	if len(resp) < 8 {
+		errCode := API_ERR
+		errCode.OriginErrMsg = fmt.Sprint(resp)
+		return errCode
+	}
+	return bfx.errorWrapper(fmt.Sprint(resp[6], ": ", resp[7]))
+}
+
+//getOrderByClientId looks the cid up among the open orders, then in the order history of the cid dates
+func (bfx *Bitfinex) getOrderByClientId(clientOrderId string, pair CurrencyPair) (*Order, error) {
+	cid := bfx.cid(clientOrderId)
+	symbol := "t" + bfx.currencyPairToSymbol(pair)
+	dates := bfx.cidDates(time.Now())
+	start, _ := time.Parse("2006-01-02", dates[len(dates)-1])
+	for _, path := range []string{"auth/r/orders/" + symbol, "auth/r/orders/" + symbol + "/hist"} {
+		body := map[string]interface{}{}
+		if strings.HasSuffix(path, "/hist") {
+			body["start"] = start.UnixNano() / int64(time.Millisecond)
+		}
+		var rows [][]interface{}
+		if err := bfx.doAuthenticatedRequestV2(path, body, &rows); err != nil {
+			return nil, err
+		}
+		for _, row := range rows {
+			if len(row) < 18 || ToInt64(row[2]) != cid {
+				continue
+			}
+			ord, err := bfx.toOrderV2(row, pair)
+			if err != nil {
+				return nil, err
+			}
+			ord.ClientOrderID = clientOrderId
+			return &ord, nil
+		}
+	}
+	errCode := EX_ERR_NOT_FIND_ORDER
+	errCode.OriginErrMsg = "unknown client order id " + clientOrderId
+	return nil, errCode
+}
+
+//cancelOrderByClientId cancels with cid and cid_date, trying each date an order may have been placed on
+func (bfx *Bitfinex) cancelOrderByClientId(clientOrderId string) (bool, error) {
+	var err error
+	for _, date := range bfx.cidDates(time.Now()) {
+		var resp []interface{}
+		err = bfx.doAuthenticatedRequestV2("auth/w/order/cancel",
+			map[string]interface{}{"cid": bfx.cid(clientOrderId), "cid_date": date}, &resp)
+		if err == nil && (len(resp) < 8 || resp[6] != "SUCCESS") {
+			err = bfx.notificationError(resp)
+		}
+		if !EX_ERR_NOT_FIND_ORDER.Is(err) {
+			break
+		}
+	}
+	if err != nil {
+		return false, err
+	}
+	return true, nil
 }
 
 func (bfx *Bitfinex) CancelOrder(orderId string, currencyPair CurrencyPair) (bool, error) {
+	if isClientOrderId(orderId) {
+		return bfx.cancelOrderByClientId(orderId)
+	}
 	var respmap map[string]interface{}
 	path := "order/cancel"
 	err := bfx.doAuthenticatedRequest("POST", path, map[string]interface{}{"order_id": ToInt(orderId)}, &respmap)
@@ -316,19 +847,20 @@ func (bfx *Bitfinex) CancelOrder(orderId string, currencyPair CurrencyPair) (boo
 	return respmap["is_live"].(bool), nil
 }
 
-func (bfx *Bitfinex) toOrder(respmap map[string]interface{}) *Order {
+func (bfx *Bitfinex) toOrder(respmap map[string]interface{}) (*Order, error) {
+	var dp DecimalParser
 	order := new(Order)
 	order.Currency = bfx.symbolToCurrencyPair(respmap["symbol"].(string))
 	order.OrderID = ToInt(respmap["id"])
-	order.Amount = ToFloat64(respmap["original_amount"])
-	order.Price = ToFloat64(respmap["price"])
-	order.DealAmount = ToFloat64(respmap["executed_amount"])
-	order.AvgPrice = ToFloat64(respmap["avg_execution_price"])
+	order.Amount = dp.Decimal(respmap["original_amount"])
+	order.Price = dp.Decimal(respmap["price"])
+	order.DealAmount = dp.Decimal(respmap["executed_amount"])
+	order.AvgPrice = dp.Decimal(respmap["avg_execution_price"])
 	order.OrderTime = bfx.adaptTimestamp(respmap["timestamp"].(string))
 
 	if order.DealAmount == order.Amount {
 		order.Status = ORDER_FINISH
-	} else if order.DealAmount > 0 {
+	} else if order.DealAmount.Sign() > 0 {
 		order.Status = ORDER_PART_FINISH
 	}
 
@@ -342,17 +874,23 @@ func (bfx *Bitfinex) toOrder(respmap map[string]interface{}) *Order {
 	if respmap["is_cancelled"].(bool) {
 		order.Status = ORDER_CANCEL
 	}
-	return order
+	if dp.Err != nil {
+		return nil, dp.Err
+	}
+	return order, nil
 }
 
 func (bfx *Bitfinex) GetOneOrder(orderId string, currencyPair CurrencyPair) (*Order, error) {
+	if isClientOrderId(orderId) {
+		return bfx.getOrderByClientId(orderId, currencyPair)
+	}
 	var respmap map[string]interface{}
 	path := "order/status"
 	err := bfx.doAuthenticatedRequest("POST", path, map[string]interface{}{"order_id": ToInt(orderId)}, &respmap)
 	if err != nil {
 		return nil, err
 	}
-	return bfx.toOrder(respmap), nil
+	return bfx.toOrder(respmap)
 }
 
 func (bfx *Bitfinex) GetUnfinishOrders(currencyPair CurrencyPair) ([]Order, error) {
@@ -365,13 +903,121 @@ func (bfx *Bitfinex) GetUnfinishOrders(currencyPair CurrencyPair) ([]Order, erro
 	var orders []Order
 	for _, v := range ordersmap {
 		ordermap := v.(map[string]interface{})
-		orders = append(orders, *bfx.toOrder(ordermap))
+		ord, err := bfx.toOrder(ordermap)
+		if err != nil {
+			return nil, err
+		}
+		orders = append(orders, *ord)
 	}
 	return orders, nil
 }
 
 func (bfx *Bitfinex) GetOrderHistorys(currencyPair CurrencyPair, currentPage, pageSize int) ([]Order, error) {
-	panic("not implement")
+	return nil, ErrNotSupported
+}
+
+//GetOrderHistory pages the v2 order history back from end, newest first 500 to a page.
+//The cursor is the end of the next page.
+func (bfx *Bitfinex) GetOrderHistory(pair CurrencyPair, start, end int64, cursor string) ([]Order, string, error) {
+	const pageSize = 500
+	body := map[string]interface{}{"start": start, "limit": pageSize}
+	if cursor != "" {
+		body["end"] = ToInt64(cursor)
+	} else if end > 0 {
+		body["end"] = end
+	}
+	var rows [][]interface{}
+	err := bfx.doAuthenticatedRequestV2("auth/r/orders/t"+bfx.currencyPairToSymbol(pair)+"/hist", body, &rows)
+	if err != nil {
+		return nil, "", err
+	}
+
+	orders := make([]Order, 0, len(rows))
+	oldest := int64(0)
+	for _, row := range rows {
+		if len(row) < 18 {
+			continue
+		}
+		created := ToInt64(row[4])
+		if oldest == 0 || created < oldest {
+			oldest = created
+		}
+		ord, err := bfx.toOrderV2(row, pair)
+		if err != nil {
+			return nil, "", err
+		}
+		orders = append(orders, ord)
+	}
+	next := ""
+	if len(rows) == pageSize && oldest > start {
+		next = strconv.FormatInt(oldest-1, 10)
+	}
+	return orders, next, nil
+}
+
+//toOrderV2 reads an order array of the v2 api: [ID, GID, CID, SYMBOL, MTS_CREATE, MTS_UPDATE, AMOUNT, AMOUNT_ORIG,
+//TYPE, TYPE_PREV, MTS_TIF, _, FLAGS, STATUS, _, _, PRICE, PRICE_AVG, ...], sells have negative amounts
+func (bfx *Bitfinex) toOrderV2(row []interface{}, pair CurrencyPair) (Order, error) {
+	var dp DecimalParser
+	amount := dp.Decimal(row[7])
+	remaining := dp.Decimal(row[6])
+	ord := Order{
+		OrderID:    ToInt(row[0]),
+		Currency:   pair,
+		Amount:     amount.Abs(),
+		DealAmount: amount.Abs().Sub(remaining.Abs()),
+		Price:      dp.Decimal(row[16]),
+		AvgPrice:   dp.Decimal(row[17]),
+		OrderTime:  int(ToInt64(row[4]) / 1000)}
+
+	market := strings.Contains(fmt.Sprint(row[8]), "MARKET")
+	switch {
+	case amount.Sign() >= 0 && market:
+		ord.Side = BUY_MARKET
+	case amount.Sign() >= 0:
+		ord.Side = BUY
+	case market:
+		ord.Side = SELL_MARKET
+	default:
+		ord.Side = SELL
+	}
+
+	switch status := fmt.Sprint(row[13]); {
+	case strings.HasPrefix(status, "CANCELED"):
+		ord.Status = ORDER_CANCEL
+	case strings.HasPrefix(status, "EXECUTED"):
+		ord.Status = ORDER_FINISH
+	case strings.HasPrefix(status, "PARTIALLY FILLED"):
+		ord.Status = ORDER_PART_FINISH
+	default:
+		ord.Status = ORDER_UNFINISH
+	}
+	return ord, dp.Err
+}
+
+//doAuthenticatedRequestV2 posts body to the v2 api, whose errors come as ["error", code, message]
+func (bfx *Bitfinex) doAuthenticatedRequestV2(path string, body map[string]interface{}, ret interface{}) error {
+	p, err := json.Marshal(body)
+	if err != nil {
+		return err
+	}
+	nonce := strconv.FormatInt(time.Now().UnixNano()/1000, 10)
+	sign, _ := GetParamHmacSha384Sign(bfx.secretKey, "/api/v2/"+path+nonce+string(p))
+
+	resp, err := NewHttpRequest(bfx.httpClient, "POST", BASE_URL_V2+"/"+path, string(p), map[string]string{
+		"Content-Type":  "application/json",
+		"bfx-nonce":     nonce,
+		"bfx-apikey":    bfx.accessKey,
+		"bfx-signature": sign})
+	if err != nil {
+		return bfx.adaptError(err)
+	}
+
+	var errResp []interface{}
+	if json.Unmarshal(resp, &errResp) == nil && len(errResp) == 3 && errResp[0] == "error" {
+		return bfx.errorWrapper(fmt.Sprint(errResp[2]))
+	}
+	return json.Unmarshal(resp, ret)
 }
 
 func (bfx *Bitfinex) doAuthenticatedRequest(method, path string, payload map[string]interface{}, ret interface{}) error {
@@ -400,7 +1046,7 @@ func (bfx *Bitfinex) doAuthenticatedRequest(method, path string, payload map[str
 		"X-BFX-SIGNATURE": sign})
 
 	if err != nil {
-		return err
+		return bfx.adaptError(err)
 	}
 	//print(string(resp))
 	err = json.Unmarshal(resp, ret)
@@ -451,3 +1097,60 @@ func (bfx *Bitfinex) adaptCurrencyPair(pair CurrencyPair) CurrencyPair {
 
 	return NewCurrencyPair(currencyA, currencyB)
 }
+
+func (bfx *Bitfinex) errorWrapper(message string) ApiError {
+	var errCode ApiError
+	msg := strings.ToLower(message)
+	switch {
+	case strings.Contains(msg, "nonce"):
+		errCode = EX_ERR_NONCE
+	case strings.Contains(msg, "ratelimit"), strings.Contains(msg, "rate_limit"), strings.Contains(msg, "rate limit"):
+		errCode = EX_ERR_API_LIMIT
+	case strings.Contains(msg, "maintenance"):
+		errCode = EX_ERR_MAINTENANCE
+	case strings.Contains(msg, "not enough"), strings.Contains(msg, "insufficient"):
+		errCode = EX_ERR_INSUFFICIENT_BALANCE
+	case strings.Contains(msg, "no such order"), strings.Contains(msg, "order not found"):
+		errCode = EX_ERR_NOT_FIND_ORDER
+	case strings.Contains(msg, "could not be cancelled"):
+		errCode = EX_ERR_CANCEL_ORDER_FAIL
+	case strings.Contains(msg, "minimum size"), strings.Contains(msg, "precision"):
+		errCode = EX_ERR_INVALID_PRECISION
+	case strings.Contains(msg, "unknown symbol"):
+		errCode = EX_ERR_INVALID_CURRENCY_PAIR
+	case strings.Contains(msg, "x-bfx-signature"):
+		errCode = EX_ERR_SIGN
+	case strings.Contains(msg, "x-bfx-apikey"):
+		errCode = EX_ERR_NOT_FIND_APIKEY
+	default:
+		errCode = API_ERR
+	}
+	errCode.OriginErrMsg = message
+	return errCode
+}
+
+//bitfinex answers non-200 requests with {"message":"..."} or {"error":"..."}, the v2 api with ["error", code, "..."]
+func (bfx *Bitfinex) adaptError(err error) error {
+	apiErr, isok := err.(ApiError)
+	if !isok {
+		return err
+	}
+
+	var errV2 []interface{}
+	if json.Unmarshal([]byte(apiErr.OriginErrMsg), &errV2) == nil && len(errV2) == 3 && errV2[0] == "error" {
+		return bfx.errorWrapper(fmt.Sprint(errV2[2]))
+	}
+
+	var resp struct {
+		Message string `json:"message"`
+		Error   string `json:"error"`
+	}
+	json.Unmarshal([]byte(apiErr.OriginErrMsg), &resp)
+	switch {
+	case resp.Message != "":
+		return bfx.errorWrapper(resp.Message)
+	case resp.Error != "":
+		return bfx.errorWrapper(resp.Error)
+	}
+	return err
+}
@@ -1,10 +1,16 @@
 package bitfinex
 
 import (
-	"errors"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
 	"io/ioutil"
 	"net/http"
+	"net/http/httptest"
+	"os"
+	"strings"
 	"testing"
+	"time"
 
 	addresses "github.com/i0n/crypto-addresses"
 	goex "github.com/nntaoli-project/GoEx"
@@ -29,6 +35,10 @@ type ExchangeKeys struct {
 func GetManifest(configFilePath *string) *Manifest {
 	manifest := &Manifest{}
 	configFile, err := ioutil.ReadFile(*configFilePath)
+	if os.IsNotExist(err) {
+		//the fixtures replay without keys
+		return manifest
+	}
 	if err != nil {
 		panic(err)
 	}
@@ -37,28 +47,333 @@ func GetManifest(configFilePath *string) *Manifest {
 }
 
 var manifest = GetManifest(&configFilePath)
-var bfx = New(http.DefaultClient, manifest.Exchanges["bitfinex.com"].AccessKey, manifest.Exchanges["bitfinex.com"].SecretKey)
+var fixtures = goex.NewFixtureTransport("testdata/fixtures.json")
+var bfx = New(fixtures.Client(), manifest.Exchanges["bitfinex.com"].AccessKey, manifest.Exchanges["bitfinex.com"].SecretKey)
 
 func TestBitfinex_GetTicker(t *testing.T) {
-	ticker, _ := bfx.GetTicker(goex.ETH_BTC)
-	t.Log(ticker)
+	ticker, err := bfx.GetTicker(goex.ETH_BTC)
+	assert.Nil(t, err)
+	assert.Equal(t, goex.Ticker{Last: goex.RequireDecimal("0.07208"), Buy: goex.RequireDecimal("0.07206"), Sell: goex.RequireDecimal("0.07209"),
+		High: goex.RequireDecimal("0.07301"), Low: goex.RequireDecimal("0.070969"), Vol: goex.RequireDecimal("15483.69254417"), Date: 1529920350}, *ticker)
 }
 
 func TestBitfinex_GetDepth_Bid(t *testing.T) {
-	dep, _ := bfx.GetDepth(2, goex.ETH_BTC)
-	assert.True(t, dep.BidList[0].Price > dep.BidList[1].Price)
-	t.Log(dep.BidList)
+	dep, err := bfx.GetDepth(2, goex.ETH_BTC)
+	assert.Nil(t, err)
+	assert.True(t, dep.BidList[0].Price.GreaterThan(dep.BidList[1].Price))
+	assert.Equal(t, goex.DepthRecords{
+		{Price: goex.RequireDecimal("0.07206"), Amount: goex.RequireDecimal("6.44")},
+		{Price: goex.RequireDecimal("0.072058"), Amount: goex.RequireDecimal("1.2")}}, dep.BidList)
 }
 
 func TestBitfinex_GetDepth_Ask(t *testing.T) {
-	dep, _ := bfx.GetDepth(2, goex.ETH_BTC)
-	assert.True(t, dep.AskList[0].Price < dep.AskList[1].Price)
-	t.Log(dep.AskList)
+	dep, err := bfx.GetDepth(2, goex.ETH_BTC)
+	assert.Nil(t, err)
+	assert.True(t, dep.AskList[0].Price.LessThan(dep.AskList[1].Price))
+	assert.Equal(t, goex.DepthRecords{
+		{Price: goex.RequireDecimal("0.07209"), Amount: goex.RequireDecimal("0.5")},
+		{Price: goex.RequireDecimal("0.072095"), Amount: goex.RequireDecimal("17.3")}}, dep.AskList)
 }
 
 func TestBitfinex_Withdraw(t *testing.T) {
-	_, err := bfx.Withdraw(goex.ETC_USD, addresses.All["okcoin.com"][goex.ETC], 0.1, "exchange", "")
-	assert.Equal(t, errors.New("Min 250 USD Equivalent"), err)
+	_, err := bfx.Withdraw(goex.WithdrawRequest{Currency: goex.ETC, Amount: goex.RequireDecimal("0.1"), Address: addresses.All["okcoin.com"][goex.ETC].Address(), Wallet: "exchange"})
+	assert.True(t, goex.API_ERR.Is(err))
+	assert.Contains(t, err.Error(), "Min 250 USD Equivalent")
+}
+
+func TestBitfinex_Withdraw_amountNotAboveFee(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		t.Errorf("unexpected request %s", r.URL.Path)
+	}))
+	defer srv.Close()
+	bfx := New(&http.Client{Transport: rewriteTransport{strings.TrimPrefix(srv.URL, "http://")}}, "", "")
+
+	for _, amount := range []string{"0.01", "0.005"} {
+		_, err := bfx.Withdraw(goex.WithdrawRequest{Currency: goex.ETC, Amount: goex.RequireDecimal(amount), Fee: goex.RequireDecimal("0.01"),
+			Address: "0x5e4f", Wallet: "exchange"})
+		assert.True(t, goex.EX_ERR_INVALID_WITHDRAW_AMOUNT.Is(err), amount)
+	}
+}
+
+//rewriteTransport sends every request to the stand-in server
+type rewriteTransport struct {
+	host string
+}
+
+func (rt rewriteTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	req.URL.Scheme = "http"
+	req.URL.Host = rt.host
+	return http.DefaultTransport.RoundTrip(req)
+}
+
+func TestBitfinex_GetMarkets(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/v1/symbols":
+			w.Write([]byte(`["btcusd","dshusd"]`))
+		case "/v1/symbols_details":
+			w.Write([]byte(`[{"pair":"btcusd","price_precision":5,"initial_margin":"30.0","minimum_margin":"15.0","maximum_order_size":"2000.0","minimum_order_size":"0.002","expiration":"NA"},` +
+				`{"pair":"dshusd","price_precision":5,"initial_margin":"30.0","minimum_margin":"15.0","maximum_order_size":"5000.0","minimum_order_size":"0.06","expiration":"NA"}]`))
+		}
+	}))
+	defer srv.Close()
+	bfx := New(&http.Client{Transport: rewriteTransport{strings.TrimPrefix(srv.URL, "http://")}}, "", "")
+
+	symbols, err := bfx.GetSymbols()
+	assert.Nil(t, err)
+	assert.Equal(t, []string{"btcusd", "dshusd"}, symbols)
+
+	markets, err := bfx.GetMarkets()
+	assert.Nil(t, err)
+	assert.Equal(t, 2, len(markets))
+	assert.Equal(t, goex.SymbolInfo{
+		Pair:            goex.BTC_USD,
+		Symbol:          "btcusd",
+		PricePrecision:  8,
+		AmountPrecision: 8,
+		TickSize:        goex.RequireDecimal("0.00000001"),
+		StepSize:        goex.RequireDecimal("0.00000001"),
+		PriceDigits:     5,
+		MinAmount:       goex.RequireDecimal("0.002"),
+		MaxAmount:       goex.RequireDecimal("2000")}, markets[0])
+
+	info, err := bfx.GetSymbolInfo(goex.NewCurrencyPair(goex.DASH, goex.USDT))
+	assert.Nil(t, err)
+	assert.Equal(t, "dshusd", info.Symbol)
+	assert.Equal(t, "6100.1", info.RoundPrice(goex.RequireDecimal("6100.19"), goex.BUY).String())
 }
 
 // TODO Write more tests
+
+func TestBitfinex_GetMyTrades(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "/v1/mytrades", r.URL.Path)
+		payload, _ := base64.StdEncoding.DecodeString(r.Header.Get("X-BFX-PAYLOAD"))
+		var p map[string]interface{}
+		assert.Nil(t, json.Unmarshal(payload, &p))
+		assert.Equal(t, "BTCUSD", p["symbol"])
+		assert.Equal(t, "1444266681.000", p["timestamp"])
+		assert.Equal(t, float64(1), p["reverse"])
+		assert.Equal(t, float64(10), p["limit_trades"])
+		w.Write([]byte(`[{"price":"246.94","amount":"1.0","timestamp":"1444266681.0","exchange":"bitfinex","type":"Buy",` +
+			`"fee_currency":"USD","fee_amount":"-0.49388","tid":11970839,"order_id":446913929},` +
+			`{"price":"247.1","amount":"0.5","timestamp":"1444266690.0","exchange":"bitfinex","type":"Sell",` +
+			`"fee_currency":"USD","fee_amount":"-0.247","tid":11970840,"order_id":446913930}]`))
+	}))
+	defer srv.Close()
+	bfx := New(&http.Client{Transport: rewriteTransport{strings.TrimPrefix(srv.URL, "http://")}}, "", "")
+
+	trades, err := bfx.GetMyTrades(goex.BTC_USD, 1444266681000, 10)
+	assert.Nil(t, err)
+	assert.Equal(t, []goex.MyTrade{
+		{TradeID: "11970839", OrderID: "446913929", Currency: goex.BTC_USD, Side: goex.TradeSide(goex.BUY), Price: goex.RequireDecimal("246.94"),
+			Amount: goex.RequireDecimal("1.0"), Fee: goex.RequireDecimal("0.49388"), FeeCurrency: goex.USD, Time: 1444266681000},
+		{TradeID: "11970840", OrderID: "446913930", Currency: goex.BTC_USD, Side: goex.TradeSide(goex.SELL), Price: goex.RequireDecimal("247.1"),
+			Amount: goex.RequireDecimal("0.5"), Fee: goex.RequireDecimal("0.247"), FeeCurrency: goex.USD, Time: 1444266690000},
+	}, trades)
+}
+
+func TestBitfinex_GetWithdraw(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "/v1/history/movements", r.URL.Path)
+		w.Write([]byte(`[{"id":581183,"txid":"0x9a2f","currency":"ETC","method":"ETHEREUMC","type":"WITHDRAWAL","amount":"-1.5",` +
+			`"description":"0x5e4f","address":"0x5e4f","status":"COMPLETED","timestamp":"1443833327.0","fee":-0.01},` +
+			`{"id":581180,"txid":null,"currency":"ETC","method":"ETHEREUMC","type":"DEPOSIT","amount":"2.0",` +
+			`"address":"0x77aa","status":"COMPLETED","timestamp":"1443833000.0","fee":0}]`))
+	}))
+	defer srv.Close()
+	bfx := New(&http.Client{Transport: rewriteTransport{strings.TrimPrefix(srv.URL, "http://")}}, "", "")
+
+	withdraw, err := bfx.GetWithdraw("581183", goex.ETC)
+	assert.Nil(t, err)
+	assert.Equal(t, goex.Withdraw{ID: "581183", Currency: goex.ETC, Amount: goex.RequireDecimal("1.5"), Fee: goex.RequireDecimal("0.01"),
+		Address: "0x5e4f", TxID: "0x9a2f", Status: goex.WITHDRAW_COMPLETED, Time: 1443833327}, *withdraw)
+
+	_, err = bfx.GetWithdraw("581180", goex.ETC)
+	assert.True(t, goex.EX_ERR_NOT_FIND_WITHDRAW.Is(err))
+	_, err = bfx.CancelWithdraw("581183", goex.ETC, "")
+	assert.Equal(t, goex.ErrNotSupported, err)
+}
+
+func TestBitfinex_GetWithdrawFee(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "/v1/account_fees", r.URL.Path)
+		w.Write([]byte(`{"withdraw":{"BTC":"0.0005","ETH":0.01}}`))
+	}))
+	defer srv.Close()
+	bfx := New(&http.Client{Transport: rewriteTransport{strings.TrimPrefix(srv.URL, "http://")}}, "", "")
+
+	fee, err := bfx.GetWithdrawFee(goex.BTC)
+	assert.Nil(t, err)
+	assert.True(t, fee.Equal(goex.RequireDecimal("0.0005")))
+	fee, err = bfx.GetWithdrawFee(goex.ETH)
+	assert.Nil(t, err)
+	assert.True(t, fee.Equal(goex.RequireDecimal("0.01")))
+	_, err = bfx.GetWithdrawFee(goex.LTC)
+	assert.True(t, goex.EX_ERR_UNKNOWN_FEE.Is(err))
+}
+
+func TestBitfinex_GetOrderHistory(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "/v2/auth/r/orders/tBTCUSD/hist", r.URL.Path)
+		assert.NotEmpty(t, r.Header.Get("bfx-signature"))
+		body, _ := ioutil.ReadAll(r.Body)
+		assert.JSONEq(t, `{"start":1524000000000,"end":1524200000000,"limit":500}`, string(body))
+		w.Write([]byte(`[[1151079507,null,1524160000000,"tBTCUSD",1524160000000,1524160010000,0,-0.5,"EXCHANGE LIMIT",null,null,null,0,` +
+			`"EXECUTED @ 8100.0(-0.5)",null,null,8100,8100,0,0,null,null,null,0,0,null,null,null,"API>BFX",null,null,null],` +
+			`[1151079480,null,1524150000000,"tBTCUSD",1524150000000,1524150010000,0.2,1,"EXCHANGE LIMIT",null,null,null,0,` +
+			`"CANCELED was: PARTIALLY FILLED @ 8000.0(0.8)",null,null,8000,8000,0,0,null,null,null,0,0,null,null,null,"API>BFX",null,null,null]]`))
+	}))
+	defer srv.Close()
+	bfx := New(&http.Client{Transport: rewriteTransport{strings.TrimPrefix(srv.URL, "http://")}}, "", "")
+
+	orders, cursor, err := bfx.GetOrderHistory(goex.BTC_USD, 1524000000000, 1524200000000, "")
+	assert.Nil(t, err)
+	assert.Equal(t, "", cursor)
+	assert.Equal(t, []goex.Order{
+		{OrderID: 1151079507, Currency: goex.BTC_USD, Amount: goex.RequireDecimal("0.5"), DealAmount: goex.RequireDecimal("0.5"),
+			Price: goex.RequireDecimal("8100"), AvgPrice: goex.RequireDecimal("8100"), OrderTime: 1524160000,
+			Side: goex.TradeSide(goex.SELL), Status: goex.ORDER_FINISH},
+		{OrderID: 1151079480, Currency: goex.BTC_USD, Amount: goex.RequireDecimal("1"), DealAmount: goex.RequireDecimal("0.8"),
+			Price: goex.RequireDecimal("8000"), AvgPrice: goex.RequireDecimal("8000"), OrderTime: 1524150000,
+			Side: goex.TradeSide(goex.BUY), Status: goex.ORDER_CANCEL},
+	}, orders)
+}
+
+func TestBitfinex_ClientOrderID(t *testing.T) {
+	cid := New(nil, "", "").cid("myOrder1")
+	today := time.Now().UTC().Format("2006-01-02")
+	yesterday := time.Now().UTC().AddDate(0, 0, -1).Format("2006-01-02")
+	order := func(status string) string {
+		return fmt.Sprintf(`[1151079507,null,%d,"tBTCUSD",1524160000000,1524160010000,-1,-1,"EXCHANGE LIMIT",null,null,null,0,`+
+			`"%s",null,null,6100,0,0,0,null,null,null,0,0,null,null,null,"API>BFX",null,null,null]`, cid, status)
+	}
+	var cancels []string
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var body map[string]interface{}
+		raw, _ := ioutil.ReadAll(r.Body)
+		json.Unmarshal(raw, &body)
+		switch r.URL.Path {
+		case "/v2/auth/w/order/submit":
+			assert.JSONEq(t, fmt.Sprintf(`{"type":"EXCHANGE LIMIT","symbol":"tBTCUSD","amount":"-1","price":"6100","cid":%d}`, cid), string(raw))
+			w.Write([]byte(`[1524160000000,"on-req",null,null,[` + order("ACTIVE") + `],null,"SUCCESS","Submitting 1 orders."]`))
+		case "/v2/auth/r/orders/tBTCUSD":
+			w.Write([]byte(`[]`))
+		case "/v2/auth/r/orders/tBTCUSD/hist":
+			start, _ := time.Parse("2006-01-02", yesterday)
+			assert.Equal(t, float64(start.UnixNano()/int64(time.Millisecond)), body["start"])
+			w.Write([]byte(`[` + order("CANCELED") + `]`))
+		case "/v2/auth/w/order/cancel":
+			assert.Equal(t, float64(cid), body["cid"])
+			cancels = append(cancels, body["cid_date"].(string))
+			if body["cid_date"] == today {
+				w.WriteHeader(http.StatusInternalServerError)
+				w.Write([]byte(`["error",10001,"Order not found."]`))
+				return
+			}
+			w.Write([]byte(`[1524160000000,"oc-req",null,null,` + order("ACTIVE") + `,null,"SUCCESS","Submitted for cancellation."]`))
+		default:
+			t.Error("unexpected", r.URL.Path)
+		}
+	}))
+	defer srv.Close()
+	bfx := New(&http.Client{Transport: rewriteTransport{strings.TrimPrefix(srv.URL, "http://")}}, "", "")
+
+	placed, err := bfx.PlaceOrderWithClientID("myOrder1", goex.SELL, goex.RequireDecimal("1"), goex.RequireDecimal("6100"), goex.BTC_USD)
+	assert.Nil(t, err)
+	assert.Equal(t, 1151079507, placed.OrderID)
+	assert.Equal(t, "myOrder1", placed.ClientOrderID)
+	assert.Equal(t, goex.TradeSide(goex.SELL), placed.Side)
+	assert.Equal(t, goex.RequireDecimal("1"), placed.Amount)
+
+	//not open any more, found in the history
+	found, err := bfx.GetOneOrder("myOrder1", goex.BTC_USD)
+	assert.Nil(t, err)
+	assert.Equal(t, 1151079507, found.OrderID)
+	assert.Equal(t, "myOrder1", found.ClientOrderID)
+	assert.Equal(t, goex.TradeStatus(goex.ORDER_CANCEL), found.Status)
+
+	_, err = bfx.GetOneOrder("myOrder2", goex.BTC_USD)
+	assert.True(t, goex.EX_ERR_NOT_FIND_ORDER.Is(err))
+
+	//not placed today, so cancelled with yesterday's cid_date
+	ok, err := bfx.CancelOrder("myOrder1", goex.BTC_USD)
+	assert.Nil(t, err)
+	assert.True(t, ok)
+	assert.Equal(t, []string{today, yesterday}, cancels)
+
+	_, err = bfx.PlaceOrderWithClientID("12345", goex.SELL, goex.RequireDecimal("1"), goex.RequireDecimal("6100"), goex.BTC_USD)
+	assert.True(t, goex.EX_ERR_INVALID_CLIENT_ORDER_ID.Is(err))
+}
+
+func TestBitfinex_GetTrades(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "/v2/trades/tBTCUSD/hist", r.URL.Path)
+		assert.Equal(t, "1524160000000", r.URL.Query().Get("start"))
+		assert.Equal(t, "1", r.URL.Query().Get("sort"))
+		w.Write([]byte(`[[243000001,1524160000000,0.5,8100],[243000002,1524160000500,-0.25,8099.5]]`))
+	}))
+	defer srv.Close()
+	bfx := New(&http.Client{Transport: rewriteTransport{strings.TrimPrefix(srv.URL, "http://")}}, "", "")
+
+	trades, err := bfx.GetTrades(goex.BTC_USD, 1524160000000)
+	assert.Nil(t, err)
+	assert.Equal(t, []goex.Trade{
+		{Tid: 243000001, Type: "buy", Amount: goex.RequireDecimal("0.5"), Price: goex.RequireDecimal("8100"), Date: 1524160000000},
+		{Tid: 243000002, Type: "sell", Amount: goex.RequireDecimal("0.25"), Price: goex.RequireDecimal("8099.5"), Date: 1524160000500}}, trades)
+}
+
+func TestMain(m *testing.M) {
+	os.Exit(fixtures.Run(m.Run))
+}
+
+func TestBitfinex_errorWrapper(t *testing.T) {
+	for message, errCode := range map[string]goex.ApiError{
+		"Nonce is too small.":           goex.EX_ERR_NONCE,
+		"ERR_RATE_LIMIT":                goex.EX_ERR_API_LIMIT,
+		"Bitfinex is under maintenance": goex.EX_ERR_MAINTENANCE,
+		"Invalid order: not enough exchange balance for 1.0 ETHBTC at 0.07": goex.EX_ERR_INSUFFICIENT_BALANCE,
+		"No such order found.":                                  goex.EX_ERR_NOT_FIND_ORDER,
+		"Order could not be cancelled.":                         goex.EX_ERR_CANCEL_ORDER_FAIL,
+		"Invalid order: minimum size for ETHBTC is 0.04":        goex.EX_ERR_INVALID_PRECISION,
+		"Unknown symbol":                                        goex.EX_ERR_INVALID_CURRENCY_PAIR,
+		"Invalid X-BFX-SIGNATURE.":                              goex.EX_ERR_SIGN,
+		"Could not find a key matching the given X-BFX-APIKEY.": goex.EX_ERR_NOT_FIND_APIKEY,
+		"Min 250 USD Equivalent":                                goex.API_ERR,
+	} {
+		err := bfx.errorWrapper(message)
+		assert.True(t, errCode.Is(err), message)
+		assert.Equal(t, message, err.OriginErrMsg)
+	}
+}
+
+func TestBitfinex_adaptError(t *testing.T) {
+	httpErr := goex.HTTP_ERR_CODE
+	httpErr.OriginErrMsg = `{"message":"Unknown symbol"}`
+	assert.True(t, goex.EX_ERR_INVALID_CURRENCY_PAIR.Is(bfx.adaptError(httpErr)))
+
+	httpErr.OriginErrMsg = `{"error":"ERR_RATE_LIMIT"}`
+	assert.True(t, goex.EX_ERR_API_LIMIT.Is(bfx.adaptError(httpErr)))
+
+	httpErr.OriginErrMsg = "<html>502 Bad Gateway</html>"
+	assert.Equal(t, error(httpErr), bfx.adaptError(httpErr))
+}
+
+func TestBitfinex_GetKlineRecords(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		q := r.URL.Query()
+		assert.Equal(t, "/v2/candles/trade:1h:tBTCUSD/hist", r.URL.Path)
+		assert.Equal(t, "2", q.Get("limit"))
+		assert.Equal(t, "1525132800000", q.Get("start"))
+		assert.Equal(t, "1", q.Get("sort"))
+		w.Write([]byte(`[[1525132800000,9250,9280,9300,9200,12.5],[1525136400000,9280,9260,9290,9240,8.25]]`))
+	}))
+	defer srv.Close()
+	bfx := New(&http.Client{Transport: rewriteTransport{strings.TrimPrefix(srv.URL, "http://")}}, "", "")
+
+	klines, err := bfx.GetKlineRecords(goex.BTC_USD, goex.KLINE_PERIOD_60MIN, 2, 1525132800000)
+	assert.Nil(t, err)
+	assert.Equal(t, []goex.Kline{
+		{Timestamp: 1525132800, Open: 9250, Close: 9280, High: 9300, Low: 9200, Vol: 12.5},
+		{Timestamp: 1525136400, Open: 9280, Close: 9260, High: 9290, Low: 9240, Vol: 8.25}}, klines)
+}
@@ -0,0 +1,68 @@
+package bithumb
+
+import (
+	"context"
+
+	. "github.com/nntaoli-project/GoEx"
+)
+
+var _ API = (*Bithumb)(nil)
+var _ APIWithContext = (*Bithumb)(nil)
+
+func (bit *Bithumb) withContext(ctx context.Context) *Bithumb {
+	c := *bit
+	c.client = HttpClientWithContext(ctx, bit.client)
+	return &c
+}
+
+func (bit *Bithumb) LimitBuyCtx(ctx context.Context, amount, price Decimal, currency CurrencyPair) (*Order, error) {
+	return bit.withContext(ctx).LimitBuy(amount, price, currency)
+}
+
+func (bit *Bithumb) LimitSellCtx(ctx context.Context, amount, price Decimal, currency CurrencyPair) (*Order, error) {
+	return bit.withContext(ctx).LimitSell(amount, price, currency)
+}
+
+func (bit *Bithumb) MarketBuyCtx(ctx context.Context, amount, price Decimal, currency CurrencyPair) (*Order, error) {
+	return bit.withContext(ctx).MarketBuy(amount, price, currency)
+}
+
+func (bit *Bithumb) MarketSellCtx(ctx context.Context, amount, price Decimal, currency CurrencyPair) (*Order, error) {
+	return bit.withContext(ctx).MarketSell(amount, price, currency)
+}
+
+func (bit *Bithumb) CancelOrderCtx(ctx context.Context, orderId string, currency CurrencyPair) (bool, error) {
+	return bit.withContext(ctx).CancelOrder(orderId, currency)
+}
+
+func (bit *Bithumb) GetOneOrderCtx(ctx context.Context, orderId string, currency CurrencyPair) (*Order, error) {
+	return bit.withContext(ctx).GetOneOrder(orderId, currency)
+}
+
+func (bit *Bithumb) GetUnfinishOrdersCtx(ctx context.Context, currency CurrencyPair) ([]Order, error) {
+	return bit.withContext(ctx).GetUnfinishOrders(currency)
+}
+
+func (bit *Bithumb) GetOrderHistorysCtx(ctx context.Context, currency CurrencyPair, currentPage, pageSize int) ([]Order, error) {
+	return bit.withContext(ctx).GetOrderHistorys(currency, currentPage, pageSize)
+}
+
+func (bit *Bithumb) GetAccountCtx(ctx context.Context) (*Account, error) {
+	return bit.withContext(ctx).GetAccount()
+}
+
+func (bit *Bithumb) GetTickerCtx(ctx context.Context, currency CurrencyPair) (*Ticker, error) {
+	return bit.withContext(ctx).GetTicker(currency)
+}
+
+func (bit *Bithumb) GetDepthCtx(ctx context.Context, size int, currency CurrencyPair) (*Depth, error) {
+	return bit.withContext(ctx).GetDepth(size, currency)
+}
+
+func (bit *Bithumb) GetKlineRecordsCtx(ctx context.Context, currency CurrencyPair, period, size, since int) ([]Kline, error) {
+	return bit.withContext(ctx).GetKlineRecords(currency, period, size, since)
+}
+
+func (bit *Bithumb) GetTradesCtx(ctx context.Context, currencyPair CurrencyPair, since int64) ([]Trade, error) {
+	return bit.withContext(ctx).GetTrades(currencyPair, since)
+}
@@ -4,13 +4,13 @@ import (
 	"bytes"
 	"encoding/json"
 	"fmt"
-	"github.com/btcsuite/goleveldb/leveldb/errors"
 	. "github.com/nntaoli-project/GoEx"
 	"log"
 	"net/http"
 	"net/url"
 	"sort"
 	"strconv"
+	"strings"
 	"time"
 )
 
@@ -25,10 +25,12 @@ var (
 )
 
 func New(client *http.Client, accesskey, secretkey string) *Bithumb {
+	client = ExchangeRateLimitedClient(client, "bithumb.com", accesskey, DefaultRateLimitConfig)
 	return &Bithumb{client: client, accesskey: accesskey, secretkey: secretkey}
 }
 
 func (bit *Bithumb) placeOrder(side, amount, price string, pair CurrencyPair) (*Order, error) {
+	var dp DecimalParser
 	var retmap map[string]interface{}
 	params := fmt.Sprintf("order_currency=%s&units=%s&price=%s&type=%s", pair.CurrencyA.Symbol, amount, price, side)
 	log.Println(params)
@@ -38,7 +40,7 @@ func (bit *Bithumb) placeOrder(side, amount, price string, pair CurrencyPair) (*
 	}
 	if retmap["status"].(string) != "0000" {
 		log.Println(retmap)
-		return nil, errors.New(retmap["status"].(string))
+		return nil, bit.errorWrapper(retmap)
 	}
 
 	var tradeSide TradeSide
@@ -50,33 +52,39 @@ func (bit *Bithumb) placeOrder(side, amount, price string, pair CurrencyPair) (*
 	}
 
 	log.Println(retmap)
-	return &Order{
+	ord := &Order{
 		OrderID:  ToInt(retmap["order_id"]),
-		Amount:   ToFloat64(amount),
-		Price:    ToFloat64(price),
+		Amount:   dp.Decimal(amount),
+		Price:    dp.Decimal(price),
 		Currency: pair,
 		Side:     tradeSide,
-		Status:   ORDER_UNFINISH}, nil
+		Status:   ORDER_UNFINISH}
+	if dp.Err != nil {
+		return nil, dp.Err
+	}
+	return ord, nil
 }
 
-func (bit *Bithumb) LimitBuy(amount, price string, currency CurrencyPair) (*Order, error) {
-	return bit.placeOrder("bid", amount, price, currency)
+func (bit *Bithumb) LimitBuy(amount, price Decimal, currency CurrencyPair) (*Order, error) {
+	return bit.placeOrder("bid", amount.String(), price.String(), currency)
 }
 
-func (bit *Bithumb) LimitSell(amount, price string, currency CurrencyPair) (*Order, error) {
-	return bit.placeOrder("ask", amount, price, currency)
+func (bit *Bithumb) LimitSell(amount, price Decimal, currency CurrencyPair) (*Order, error) {
+	return bit.placeOrder("ask", amount.String(), price.String(), currency)
 }
 
-func (bit *Bithumb) MarketBuy(amount, price string, currency CurrencyPair) (*Order, error) {
-	panic("not implement")
+func (bit *Bithumb) MarketBuy(amount, price Decimal, currency CurrencyPair) (*Order, error) {
+	return nil, ErrNotSupported
 }
 
-func (bit *Bithumb) MarketSell(amount, price string, currency CurrencyPair) (*Order, error) {
-	panic("not implement")
+func (bit *Bithumb) MarketSell(amount, price Decimal, currency CurrencyPair) (*Order, error) {
+	return nil, ErrNotSupported
 }
 
 func (bit *Bithumb) CancelOrder(orderId string, currency CurrencyPair) (bool, error) {
-	panic("please invoke the CancelOrder2 method.")
+	errCode := ErrNotSupported
+	errCode.OriginErrMsg = "please invoke the CancelOrder2 method."
+	return false, errCode
 }
 
 /*补丁*/
@@ -90,15 +98,18 @@ func (bit *Bithumb) CancelOrder2(side, orderId string, currency CurrencyPair) (b
 	if retmap["status"].(string) == "0000" {
 		return true, nil
 	}
-	return false, errors.New(retmap["status"].(string))
+	return false, bit.errorWrapper(retmap)
 }
 
 func (bit *Bithumb) GetOneOrder(orderId string, currency CurrencyPair) (*Order, error) {
-	panic("please invoke the GetOneOrder2 method.")
+	errCode := ErrNotSupported
+	errCode.OriginErrMsg = "please invoke the GetOneOrder2 method."
+	return nil, errCode
 }
 
 /*补丁*/
 func (bit *Bithumb) GetOneOrder2(side, orderId string, currency CurrencyPair) (*Order, error) {
+	var dp DecimalParser
 	var retmap map[string]interface{}
 	params := fmt.Sprintf("type=%s&order_id=%s&currency=%s", side, orderId, currency.CurrencyA.Symbol)
 	err := bit.doAuthenticatedRequest("/info/order_detail", params, &retmap)
@@ -112,11 +123,11 @@ func (bit *Bithumb) GetOneOrder2(side, orderId string, currency CurrencyPair) (*
 			return nil, EX_ERR_NOT_FIND_ORDER
 		}
 		log.Println(retmap)
-		return nil, errors.New(retmap["status"].(string))
+		return nil, bit.errorWrapper(retmap)
 	}
 
 	order := new(Order)
-	total := 0.0
+	total := Decimal{}
 	data := retmap["data"].([]interface{})
 	for _, v := range data {
 		ord := v.(map[string]interface{})
@@ -126,24 +137,29 @@ func (bit *Bithumb) GetOneOrder2(side, orderId string, currency CurrencyPair) (*
 		case "bid":
 			order.Side = BUY
 		}
-		order.Amount += ToFloat64(ord["units_traded"])
-		order.Fee += ToFloat64(ord["fee"])
-		total += ToFloat64(ord["total"])
+		order.Amount = order.Amount.Add(dp.Decimal(ord["units_traded"]))
+		order.Fee = order.Fee.Add(dp.Decimal(ord["fee"]))
+		total = total.Add(dp.Decimal(ord["total"]))
 	}
 
 	order.DealAmount = order.Amount
-	avg := total / order.DealAmount
-	order.AvgPrice = ToFloat64(fmt.Sprintf("%.2f", avg))
+	if order.DealAmount.Sign() > 0 {
+		order.AvgPrice = total.DivRound(order.DealAmount, 2)
+	}
 	order.Price = order.AvgPrice
 	order.Currency = currency
 	order.OrderID = ToInt(orderId)
 	order.Status = ORDER_FINISH
 
 	log.Println(retmap)
+	if dp.Err != nil {
+		return nil, dp.Err
+	}
 	return order, nil
 }
 
 func (bit *Bithumb) GetUnfinishOrders(currency CurrencyPair) ([]Order, error) {
+	var dp DecimalParser
 	var retmap map[string]interface{}
 	params := fmt.Sprintf("currency=%s", currency.CurrencyA.Symbol)
 	err := bit.doAuthenticatedRequest("/info/orders", params, &retmap)
@@ -156,7 +172,7 @@ func (bit *Bithumb) GetUnfinishOrders(currency CurrencyPair) ([]Order, error) {
 		if "거래 진행중인 내역이 존재하지 않습니다." == message {
 			return []Order{}, nil
 		}
-		return nil, errors.New(fmt.Sprintf("[%s]%s", retmap["status"].(string), message))
+		return nil, bit.errorWrapper(retmap)
 	}
 
 	var orders []Order
@@ -165,19 +181,18 @@ func (bit *Bithumb) GetUnfinishOrders(currency CurrencyPair) ([]Order, error) {
 		orderinfo := v.(map[string]interface{})
 		ord := Order{
 			OrderID:  ToInt(orderinfo["order_id"]),
-			Amount:   ToFloat64(orderinfo["units"]),
-			Price:    ToFloat64(orderinfo["price"]),
+			Amount:   dp.Decimal(orderinfo["units"]),
+			Price:    dp.Decimal(orderinfo["price"]),
 			Currency: currency,
-			Fee:      ToFloat64(orderinfo["fee"])}
+			Fee:      dp.Decimal(orderinfo["fee"])}
 
-		remaining := ToFloat64(orderinfo["units_remaining"])
-		total := ToFloat64(orderinfo["total"])
-		dealamount := ord.Amount - remaining
+		remaining := dp.Decimal(orderinfo["units_remaining"])
+		total := dp.Decimal(orderinfo["total"])
+		dealamount := ord.Amount.Sub(remaining)
 		ord.DealAmount = dealamount
 
-		if dealamount > 0 {
-			avg := fmt.Sprintf("%.4f", total/dealamount)
-			ord.AvgPrice = ToFloat64(avg)
+		if dealamount.Sign() > 0 {
+			ord.AvgPrice = total.DivRound(dealamount, 4)
 		}
 
 		switch orderinfo["type"].(string) {
@@ -196,14 +211,18 @@ func (bit *Bithumb) GetUnfinishOrders(currency CurrencyPair) ([]Order, error) {
 	}
 
 	log.Println(retmap)
+	if dp.Err != nil {
+		return nil, dp.Err
+	}
 	return orders, nil
 }
 
 func (bit *Bithumb) GetOrderHistorys(currency CurrencyPair, currentPage, pageSize int) ([]Order, error) {
-	panic("not implement")
+	return nil, ErrNotSupported
 }
 
 func (bit *Bithumb) GetAccount() (*Account, error) {
+	var dp DecimalParser
 	var retmap map[string]interface{}
 	err := bit.doAuthenticatedRequest("/info/balance", "currency=ALL", &retmap)
 	if err != nil {
@@ -214,36 +233,39 @@ func (bit *Bithumb) GetAccount() (*Account, error) {
 	acc.SubAccounts = make(map[Currency]SubAccount)
 	acc.SubAccounts[LTC] = SubAccount{
 		Currency:     LTC,
-		Amount:       ToFloat64(datamap["available_ltc"]),
-		ForzenAmount: ToFloat64(datamap["in_use_ltc"]),
-		LoanAmount:   0}
+		Amount:       dp.Decimal(datamap["available_ltc"]),
+		FrozenAmount: dp.Decimal(datamap["in_use_ltc"]),
+		LoanAmount:   Decimal{}}
 	acc.SubAccounts[BTC] = SubAccount{
 		Currency:     BTC,
-		Amount:       ToFloat64(datamap["available_btc"]),
-		ForzenAmount: ToFloat64(datamap["in_use_etc"]),
-		LoanAmount:   0}
+		Amount:       dp.Decimal(datamap["available_btc"]),
+		FrozenAmount: dp.Decimal(datamap["in_use_etc"]),
+		LoanAmount:   Decimal{}}
 	acc.SubAccounts[ETH] = SubAccount{
 		Currency:     ETH,
-		Amount:       ToFloat64(datamap["available_eth"]),
-		ForzenAmount: ToFloat64(datamap["in_use_eth"]),
-		LoanAmount:   0}
+		Amount:       dp.Decimal(datamap["available_eth"]),
+		FrozenAmount: dp.Decimal(datamap["in_use_eth"]),
+		LoanAmount:   Decimal{}}
 	acc.SubAccounts[ETC] = SubAccount{
 		Currency:     ETC,
-		Amount:       ToFloat64(datamap["available_etc"]),
-		ForzenAmount: ToFloat64(datamap["in_use_etc"]),
-		LoanAmount:   0}
+		Amount:       dp.Decimal(datamap["available_etc"]),
+		FrozenAmount: dp.Decimal(datamap["in_use_etc"]),
+		LoanAmount:   Decimal{}}
 	acc.SubAccounts[BCH] = SubAccount{
 		Currency:     BCH,
-		Amount:       ToFloat64(datamap["available_bch"]),
-		ForzenAmount: ToFloat64(datamap["in_use_bch"]),
-		LoanAmount:   0}
+		Amount:       dp.Decimal(datamap["available_bch"]),
+		FrozenAmount: dp.Decimal(datamap["in_use_bch"]),
+		LoanAmount:   Decimal{}}
 	acc.SubAccounts[KRW] = SubAccount{
 		Currency:     KRW,
-		Amount:       ToFloat64(datamap["available_krw"]),
-		ForzenAmount: ToFloat64(datamap["in_use_krw"]),
-		LoanAmount:   0}
+		Amount:       dp.Decimal(datamap["available_krw"]),
+		FrozenAmount: dp.Decimal(datamap["in_use_krw"]),
+		LoanAmount:   Decimal{}}
 	//log.Println(datamap)
 	acc.Exchange = bit.GetExchangeName()
+	if dp.Err != nil {
+		return nil, dp.Err
+	}
 	return acc, nil
 }
 
@@ -254,7 +276,7 @@ func (bit *Bithumb) doAuthenticatedRequest(uri, params string, ret interface{})
 	params += "&endpoint=" + e_endpoint
 
 	// Api-Sign information generation.
-	hmac_data := uri + string(0) + params + string(0) + api_nonce
+	hmac_data := uri + "\x00" + params + "\x00" + api_nonce
 	hash_hmac_str := GetParamHmacSHA512Base64Sign(bit.secretkey, hmac_data)
 	api_sign := hash_hmac_str
 	content_length_str := strconv.Itoa(len(params))
@@ -279,35 +301,41 @@ func (bit *Bithumb) doAuthenticatedRequest(uri, params string, ret interface{})
 }
 
 func (bit *Bithumb) GetTicker(currency CurrencyPair) (*Ticker, error) {
+	var dp DecimalParser
 	respmap, err := HttpGet(bit.client, fmt.Sprintf("%s/public/ticker/%s", baseUrl, currency.CurrencyA))
 	if err != nil {
 		return nil, err
 	}
 
 	if respmap["status"].(string) != "0000" {
-		return nil, errors.New(respmap["status"].(string))
+		return nil, bit.errorWrapper(respmap)
 	}
 
 	datamap := respmap["data"].(map[string]interface{})
 
-	return &Ticker{
-		Low:  ToFloat64(datamap["min_price"]),
-		High: ToFloat64(datamap["max_price"]),
-		Last: ToFloat64(datamap["closing_price"]),
-		Vol:  ToFloat64(datamap["units_traded"]),
-		Buy:  ToFloat64(datamap["buy_price"]),
-		Sell: ToFloat64(datamap["sell_price"]),
-	}, nil
+	ticker := &Ticker{
+		Low:  dp.Decimal(datamap["min_price"]),
+		High: dp.Decimal(datamap["max_price"]),
+		Last: dp.Decimal(datamap["closing_price"]),
+		Vol:  dp.Decimal(datamap["units_traded"]),
+		Buy:  dp.Decimal(datamap["buy_price"]),
+		Sell: dp.Decimal(datamap["sell_price"]),
+	}
+	if dp.Err != nil {
+		return nil, dp.Err
+	}
+	return ticker, nil
 }
 
 func (bit *Bithumb) GetDepth(size int, currency CurrencyPair) (*Depth, error) {
+	var dp DecimalParser
 	resp, err := HttpGet(bit.client, fmt.Sprintf("%s/public/orderbook/%s", baseUrl, currency.CurrencyA))
 	if err != nil {
 		return nil, err
 	}
 
 	if resp["status"].(string) != "0000" {
-		return nil, errors.New(resp["status"].(string))
+		return nil, bit.errorWrapper(resp)
 	}
 
 	datamap := resp["data"].(map[string]interface{})
@@ -318,28 +346,128 @@ func (bit *Bithumb) GetDepth(size int, currency CurrencyPair) (*Depth, error) {
 
 	for _, v := range bids {
 		bid := v.(map[string]interface{})
-		dep.BidList = append(dep.BidList, DepthRecord{ToFloat64(bid["price"]), ToFloat64(bid["quantity"])})
+		dep.BidList = append(dep.BidList, DepthRecord{dp.Price(bid["price"]), dp.Decimal(bid["quantity"])})
 	}
 
 	for _, v := range asks {
 		ask := v.(map[string]interface{})
-		dep.AskList = append(dep.AskList, DepthRecord{ToFloat64(ask["price"]), ToFloat64(ask["quantity"])})
+		dep.AskList = append(dep.AskList, DepthRecord{dp.Price(ask["price"]), dp.Decimal(ask["quantity"])})
 	}
 
 	sort.Sort(sort.Reverse(dep.AskList))
 
+	if dp.Err != nil {
+		return nil, dp.Err
+	}
 	return dep, nil
 }
 
+var klineIntervals = map[int]string{
+	KLINE_PERIOD_1MIN:  "1m",
+	KLINE_PERIOD_5MIN:  "5m",
+	KLINE_PERIOD_30MIN: "30m",
+	KLINE_PERIOD_60MIN: "1h",
+	KLINE_PERIOD_1DAY:  "24h",
+}
+
+//GetKlineRecords reads the latest candles bithumb keeps, it has no paging by time
 func (bit *Bithumb) GetKlineRecords(currency CurrencyPair, period, size, since int) ([]Kline, error) {
-	panic("not implement")
+	interval, ok := klineIntervals[period]
+	if !ok {
+		return nil, UnsupportedKlinePeriod(period)
+	}
+	respmap, err := HttpGet(bit.client, fmt.Sprintf("%s/public/candlestick/%s_%s/%s", baseUrl, currency.CurrencyA, currency.CurrencyB, interval))
+	if err != nil {
+		return nil, err
+	}
+	if respmap["status"].(string) != "0000" {
+		return nil, bit.errorWrapper(respmap)
+	}
+
+	data, _ := respmap["data"].([]interface{})
+	klines := make([]Kline, 0, len(data))
+	for _, v := range data {
+		//[time ms, open, close, high, low, volume]
+		r, ok := v.([]interface{})
+		if !ok || len(r) < 6 {
+			continue
+		}
+		klines = append(klines, Kline{
+			Timestamp: ToInt64(r[0]) / 1000,
+			Open:      ToFloat64(r[1]),
+			Close:     ToFloat64(r[2]),
+			High:      ToFloat64(r[3]),
+			Low:       ToFloat64(r[4]),
+			Vol:       ToFloat64(r[5])})
+	}
+	return KlinesSince(klines, int64(since), size), nil
 }
 
+//transactionLocation is korean time, bithumb's transaction_date is in it
+var transactionLocation = time.FixedZone("KST", 9*3600)
+
 //非个人，整个交易所的交易记录
+//GetTrades answers the trades from since on among the latest 100, bithumb has no paging for older ones
 func (bit *Bithumb) GetTrades(currencyPair CurrencyPair, since int64) ([]Trade, error) {
-	panic("not implement")
+	var dp DecimalParser
+	respmap, err := HttpGet(bit.client, fmt.Sprintf("%s/public/recent_transactions/%s?count=100", baseUrl, currencyPair.CurrencyA))
+	if err != nil {
+		return nil, err
+	}
+	if respmap["status"].(string) != "0000" {
+		return nil, bit.errorWrapper(respmap)
+	}
+
+	data, _ := respmap["data"].([]interface{})
+	trades := make([]Trade, 0, len(data))
+	for _, v := range data {
+		t := v.(map[string]interface{})
+		date, _ := time.ParseInLocation("2006-01-02 15:04:05", fmt.Sprint(t["transaction_date"]), transactionLocation)
+		trade := Trade{
+			Tid:    ToInt64(t["cont_no"]),
+			Type:   "buy",
+			Amount: dp.Decimal(t["units_traded"]),
+			Price:  dp.Decimal(t["price"]),
+			Date:   date.UnixNano() / int64(time.Millisecond)}
+		if t["type"] == "ask" {
+			trade.Type = "sell"
+		}
+		trades = append(trades, trade)
+	}
+	if dp.Err != nil {
+		return nil, dp.Err
+	}
+	return TradesSinceTime(trades, since), nil
 }
 
 func (bit *Bithumb) GetExchangeName() string {
 	return "bithumb.com"
 }
+
+func (bit *Bithumb) Capabilities() Capabilities {
+	return Capabilities{Kline: true, Trades: true}
+}
+
+func (bit *Bithumb) errorWrapper(retmap map[string]interface{}) ApiError {
+	status, _ := retmap["status"].(string)
+	message, _ := retmap["message"].(string)
+	msg := strings.ToLower(message)
+
+	var errCode ApiError
+	switch {
+	case status == "5300":
+		errCode = EX_ERR_NOT_FIND_APIKEY
+	case strings.Contains(msg, "nonce"), strings.Contains(msg, "time expired"):
+		errCode = EX_ERR_NONCE
+	case strings.Contains(message, "부족"):
+		errCode = EX_ERR_INSUFFICIENT_BALANCE
+	case strings.Contains(message, "존재하지 않습니다"):
+		errCode = EX_ERR_NOT_FIND_ORDER
+	case strings.Contains(message, "점검"):
+		errCode = EX_ERR_MAINTENANCE
+	default:
+		errCode = API_ERR
+	}
+	errCode.OriginErrMsg = fmt.Sprintf("[%s]%s", status, message)
+	return errCode
+}
@@ -2,21 +2,98 @@ package bithumb
 
 import (
 	"github.com/nntaoli-project/GoEx"
+	"github.com/stretchr/testify/assert"
 	"net/http"
+	"net/http/httptest"
+	"os"
+	"strings"
 	"testing"
 )
 
-var bh = New(http.DefaultClient, "", "")
+var fixtures = goex.NewFixtureTransport("testdata/fixtures.json")
+var bh = New(fixtures.Client(), "", "")
 
 func TestBithumb_GetTicker(t *testing.T) {
-	ticker, err := bh.GetTicker(goex.NewCurrencyPair2("ALL_KAW"))
-	t.Log("err=>", err)
-	t.Log("ticker=>", ticker)
+	ticker, err := bh.GetTicker(goex.BTC_KRW)
+	assert.Nil(t, err)
+	assert.Equal(t, goex.Ticker{Last: goex.RequireDecimal("7034000"), Buy: goex.RequireDecimal("7033000"), Sell: goex.RequireDecimal("7034000"),
+		High: goex.RequireDecimal("7100000"), Low: goex.RequireDecimal("6850000"), Vol: goex.RequireDecimal("6893.62")}, *ticker)
 }
 
 func TestBithumb_GetDepth(t *testing.T) {
 	dep, err := bh.GetDepth(1, goex.BTC_KRW)
-	t.Log("err=>", err)
-	t.Log("asks=>", dep.AskList)
-	t.Log("bids=>", dep.BidList)
+	assert.Nil(t, err)
+	//the best ask last
+	assert.Equal(t, goex.DepthRecords{
+		{Price: goex.RequireDecimal("7035000"), Amount: goex.RequireDecimal("2.3")},
+		{Price: goex.RequireDecimal("7034000"), Amount: goex.RequireDecimal("0.0722")}}, dep.AskList)
+	assert.Equal(t, goex.DepthRecords{
+		{Price: goex.RequireDecimal("7033000"), Amount: goex.RequireDecimal("0.4021")},
+		{Price: goex.RequireDecimal("7032000"), Amount: goex.RequireDecimal("1")}}, dep.BidList)
+}
+
+func TestMain(m *testing.M) {
+	os.Exit(fixtures.Run(m.Run))
+}
+
+func TestBithumb_errorWrapper(t *testing.T) {
+	for _, c := range []struct {
+		status, message string
+		errCode         goex.ApiError
+	}{
+		{"5300", "Invalid Apikey", goex.EX_ERR_NOT_FIND_APIKEY},
+		{"5100", "Invalid Nonce", goex.EX_ERR_NONCE},
+		{"5600", "매수금액이 사용가능 KRW 를 초과하였습니다. 잔액이 부족합니다.", goex.EX_ERR_INSUFFICIENT_BALANCE},
+		{"5600", "거래 진행중인 내역이 존재하지 않습니다.", goex.EX_ERR_NOT_FIND_ORDER},
+		{"5600", "서비스 점검중입니다.", goex.EX_ERR_MAINTENANCE},
+		{"5500", "Invalid Parameter", goex.API_ERR},
+	} {
+		err := bh.errorWrapper(map[string]interface{}{"status": c.status, "message": c.message})
+		assert.True(t, c.errCode.Is(err), c.message)
+		assert.Equal(t, "["+c.status+"]"+c.message, err.OriginErrMsg)
+	}
+}
+
+//rewriteTransport sends every request to the stand-in server
+type rewriteTransport struct {
+	host string
+}
+
+func (rt rewriteTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	req.URL.Scheme = "http"
+	req.URL.Host = rt.host
+	return http.DefaultTransport.RoundTrip(req)
+}
+
+func TestBithumb_GetTrades(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "/public/recent_transactions/BTC", r.URL.Path)
+		assert.Equal(t, "100", r.URL.Query().Get("count"))
+		//transaction_date is korean time, 05:30:12 UTC
+		w.Write([]byte(`{"status":"0000","data":[{"cont_no":"23549421","transaction_date":"2018-05-01 14:30:12","type":"ask",` +
+			`"units_traded":"0.01","price":"10000000","total":"100000"}]}`))
+	}))
+	defer srv.Close()
+	bit := New(&http.Client{Transport: rewriteTransport{strings.TrimPrefix(srv.URL, "http://")}}, "", "")
+
+	trades, err := bit.GetTrades(goex.NewCurrencyPair2("BTC_KRW"), 0)
+	assert.Nil(t, err)
+	assert.Equal(t, []goex.Trade{{Tid: 23549421, Type: "sell", Amount: goex.RequireDecimal("0.01"), Price: goex.RequireDecimal("10000000"),
+		Date: 1525152612000}}, trades)
+}
+
+func TestBithumb_GetKlineRecords(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "/public/candlestick/BTC_KRW/1h", r.URL.Path)
+		w.Write([]byte(`{"status":"0000","data":[[1525132800000,"10000000","10050000","10100000","9950000","12.5"],` +
+			`[1525136400000,"10050000","10020000","10080000","10000000","8.25"]]}`))
+	}))
+	defer srv.Close()
+	bit := New(&http.Client{Transport: rewriteTransport{strings.TrimPrefix(srv.URL, "http://")}}, "", "")
+
+	klines, err := bit.GetKlineRecords(goex.NewCurrencyPair2("BTC_KRW"), goex.KLINE_PERIOD_60MIN, 2, 0)
+	assert.Nil(t, err)
+	assert.Equal(t, []goex.Kline{
+		{Timestamp: 1525132800, Open: 10000000, Close: 10050000, High: 10100000, Low: 9950000, Vol: 12.5},
+		{Timestamp: 1525136400, Open: 10050000, Close: 10020000, High: 10080000, Low: 10000000, Vol: 8.25}}, klines)
 }
@@ -26,6 +26,7 @@ type Bitstamp struct {
 }
 
 func NewBitstamp(client *http.Client, accessKey, secertkey, clientId string) *Bitstamp {
+	client = ExchangeRateLimitedClient(client, "bitstamp.net", accessKey, DefaultRateLimitConfig)
 	return &Bitstamp{client: client, accessKey: accessKey, secretkey: secertkey, clientId: clientId}
 }
 
@@ -41,6 +42,7 @@ func (bitstamp *Bitstamp) buildPostForm(params *url.Values) {
 }
 
 func (bitstamp *Bitstamp) GetAccount() (*Account, error) {
+	var dp DecimalParser
 	urlStr := fmt.Sprintf("%s%s", BASE_URL, "v2/balance/")
 	params := url.Values{}
 	bitstamp.buildPostForm(&params)
@@ -63,49 +65,53 @@ func (bitstamp *Bitstamp) GetAccount() (*Account, error) {
 	acc.SubAccounts = make(map[Currency]SubAccount)
 	acc.SubAccounts[BTC] = SubAccount{
 		Currency:     BTC,
-		Amount:       ToFloat64(respmap["btc_available"]),
-		ForzenAmount: ToFloat64(respmap["btc_reserved"]),
-		LoanAmount:   0,
+		Amount:       dp.Decimal(respmap["btc_available"]),
+		FrozenAmount: dp.Decimal(respmap["btc_reserved"]),
+		LoanAmount:   Decimal{},
 	}
 	acc.SubAccounts[LTC] = SubAccount{
 		Currency:     LTC,
-		Amount:       ToFloat64(respmap["ltc_available"]),
-		ForzenAmount: ToFloat64(respmap["ltc_reserved"]),
-		LoanAmount:   0,
+		Amount:       dp.Decimal(respmap["ltc_available"]),
+		FrozenAmount: dp.Decimal(respmap["ltc_reserved"]),
+		LoanAmount:   Decimal{},
 	}
 	acc.SubAccounts[ETH] = SubAccount{
 		Currency:     ETH,
-		Amount:       ToFloat64(respmap["eth_available"]),
-		ForzenAmount: ToFloat64(respmap["eth_reserved"]),
-		LoanAmount:   0,
+		Amount:       dp.Decimal(respmap["eth_available"]),
+		FrozenAmount: dp.Decimal(respmap["eth_reserved"]),
+		LoanAmount:   Decimal{},
 	}
 	acc.SubAccounts[XRP] = SubAccount{
 		Currency:     XRP,
-		Amount:       ToFloat64(respmap["xrp_available"]),
-		ForzenAmount: ToFloat64(respmap["xrp_reserved"]),
-		LoanAmount:   0,
+		Amount:       dp.Decimal(respmap["xrp_available"]),
+		FrozenAmount: dp.Decimal(respmap["xrp_reserved"]),
+		LoanAmount:   Decimal{},
 	}
 	acc.SubAccounts[USD] = SubAccount{
 		Currency:     USD,
-		Amount:       ToFloat64(respmap["usd_available"]),
-		ForzenAmount: ToFloat64(respmap["usd_reserved"]),
-		LoanAmount:   0,
+		Amount:       dp.Decimal(respmap["usd_available"]),
+		FrozenAmount: dp.Decimal(respmap["usd_reserved"]),
+		LoanAmount:   Decimal{},
 	}
 	acc.SubAccounts[EUR] = SubAccount{
 		Currency:     EUR,
-		Amount:       ToFloat64(respmap["eur_available"]),
-		ForzenAmount: ToFloat64(respmap["eur_reserved"]),
-		LoanAmount:   0,
+		Amount:       dp.Decimal(respmap["eur_available"]),
+		FrozenAmount: dp.Decimal(respmap["eur_reserved"]),
+		LoanAmount:   Decimal{},
 	}
 	acc.SubAccounts[BCH] = SubAccount{
 		Currency:BCH,
-		Amount: ToFloat64(respmap["bch_available"]),
-		ForzenAmount:ToFloat64(respmap["bch_reserved"]),
-		LoanAmount:0}
+		Amount: dp.Decimal(respmap["bch_available"]),
+		FrozenAmount:dp.Decimal(respmap["bch_reserved"]),
+		LoanAmount:Decimal{}}
+	if dp.Err != nil {
+		return nil, dp.Err
+	}
 	return &acc, nil
 }
 
 func (bitstamp *Bitstamp) placeOrder(side string, pair CurrencyPair, amount, price string) (*Order, error) {
+	var dp DecimalParser
 	params := url.Values{}
 	params.Set("amount", amount)
 	params.Set("price", price)
@@ -127,7 +133,7 @@ func (bitstamp *Bitstamp) placeOrder(side string, pair CurrencyPair, amount, pri
 
 	orderId, isok := respmap["id"].(string)
 	if !isok {
-		return nil, errors.New(string(resp))
+		return nil, bitstamp.errorWrapper(string(resp))
 	}
 
 	orderSide := BUY
@@ -135,32 +141,36 @@ func (bitstamp *Bitstamp) placeOrder(side string, pair CurrencyPair, amount, pri
 		orderSide = SELL
 	}
 
-	return &Order{
+	ord := &Order{
 		Currency:   pair,
 		OrderID:    ToInt(orderId),
-		Price:      ToFloat64(price),
-		Amount:     ToFloat64(amount),
-		DealAmount: 0,
-		AvgPrice:   0,
+		Price:      dp.Decimal(price),
+		Amount:     dp.Decimal(amount),
+		DealAmount: Decimal{},
+		AvgPrice:   Decimal{},
 		Side:       TradeSide(orderSide),
 		Status:     ORDER_UNFINISH,
-		OrderTime:  1}, nil
+		OrderTime:  1}
+	if dp.Err != nil {
+		return nil, dp.Err
+	}
+	return ord, nil
 }
 
-func (bitstamp *Bitstamp) LimitBuy(amount, price string, currency CurrencyPair) (*Order, error) {
-	return bitstamp.placeOrder("buy", currency, amount, price)
+func (bitstamp *Bitstamp) LimitBuy(amount, price Decimal, currency CurrencyPair) (*Order, error) {
+	return bitstamp.placeOrder("buy", currency, amount.String(), price.String())
 }
 
-func (bitstamp *Bitstamp) LimitSell(amount, price string, currency CurrencyPair) (*Order, error) {
-	return bitstamp.placeOrder("sell", currency, amount, price)
+func (bitstamp *Bitstamp) LimitSell(amount, price Decimal, currency CurrencyPair) (*Order, error) {
+	return bitstamp.placeOrder("sell", currency, amount.String(), price.String())
 }
 
-func (bitstamp *Bitstamp) MarketBuy(amount, price string, currency CurrencyPair) (*Order, error) {
-	panic("not implement")
+func (bitstamp *Bitstamp) MarketBuy(amount, price Decimal, currency CurrencyPair) (*Order, error) {
+	return nil, ErrNotSupported
 }
 
-func (bitstamp *Bitstamp) MarketSell(amount, price string, currency CurrencyPair) (*Order, error) {
-	panic("not implement")
+func (bitstamp *Bitstamp) MarketSell(amount, price Decimal, currency CurrencyPair) (*Order, error) {
+	return nil, ErrNotSupported
 }
 
 func (bitstamp *Bitstamp) CancelOrder(orderId string, currency CurrencyPair) (bool, error) {
@@ -181,7 +191,7 @@ func (bitstamp *Bitstamp) CancelOrder(orderId string, currency CurrencyPair) (bo
 	}
 
 	if respmap["error"] != nil {
-		return false, errors.New(string(resp))
+		return false, bitstamp.errorWrapper(string(resp))
 	}
 
 	println(string(resp))
@@ -189,6 +199,7 @@ func (bitstamp *Bitstamp) CancelOrder(orderId string, currency CurrencyPair) (bo
 }
 
 func (bitstamp *Bitstamp) GetOneOrder(orderId string, currency CurrencyPair) (*Order, error) {
+	var dp DecimalParser
 	params := url.Values{}
 	params.Set("id", orderId)
 	bitstamp.buildPostForm(&params)
@@ -208,7 +219,7 @@ func (bitstamp *Bitstamp) GetOneOrder(orderId string, currency CurrencyPair) (*O
 
 	transactions, isok := respmap["transactions"].([]interface{})
 	if !isok {
-		return nil, errors.New(string(resp))
+		return nil, bitstamp.errorWrapper(string(resp))
 	}
 
 	status := respmap["status"].(string)
@@ -227,31 +238,35 @@ func (bitstamp *Bitstamp) GetOneOrder(orderId string, currency CurrencyPair) (*O
 		if ord.Status != ORDER_FINISH {
 			ord.Status = ORDER_PART_FINISH
 		}
-		var dealAmount float64
-		var tradeAmount float64
+		var dealAmount Decimal
+		var tradeAmount Decimal
 		currencyStr := strings.ToLower(currency.CurrencyA.Symbol)
 		for _, v := range transactions {
 			transaction := v.(map[string]interface{})
-			price := ToFloat64(transaction["price"])
-			amount := ToFloat64(transaction[currencyStr])
-			dealAmount += amount
-			tradeAmount += amount * price
+			price := dp.Decimal(transaction["price"])
+			amount := dp.Decimal(transaction[currencyStr])
+			dealAmount = dealAmount.Add(amount)
+			tradeAmount = tradeAmount.Add(amount.Mul(price))
 
 			tpy := ToInt(transaction["type"])
 			if tpy == 2 {
 				ord.Side = SELL
 			}
 		}
-		avgPrice := tradeAmount / dealAmount
+		avgPrice := tradeAmount.Div(dealAmount)
 		ord.DealAmount = dealAmount
 		ord.AvgPrice = avgPrice
 	}
 
 	//	println(string(resp))
+	if dp.Err != nil {
+		return nil, dp.Err
+	}
 	return &ord, nil
 }
 
 func (bitstamp *Bitstamp) GetUnfinishOrders(currency CurrencyPair) ([]Order, error) {
+	var dp DecimalParser
 	params := url.Values{}
 	bitstamp.buildPostForm(&params)
 
@@ -280,24 +295,28 @@ func (bitstamp *Bitstamp) GetUnfinishOrders(currency CurrencyPair) ([]Order, err
 		orders = append(orders, Order{
 			OrderID:   ToInt(ord["id"]),
 			Currency:  currency,
-			Price:     ToFloat64(ord["price"]),
-			Amount:    ToFloat64(ord["amount"]),
+			Price:     dp.Decimal(ord["price"]),
+			Amount:    dp.Decimal(ord["amount"]),
 			Side:      TradeSide(orderSide),
 			Status:    ORDER_UNFINISH,
 			OrderTime: int(orderTime.Unix())})
 	}
 	//println(string(resp))
 
+	if dp.Err != nil {
+		return nil, dp.Err
+	}
 	return orders, nil
 }
 
 func (bitstamp *Bitstamp) GetOrderHistorys(currency CurrencyPair, currentPage, pageSize int) ([]Order, error) {
-	panic("not implement")
+	return nil, ErrNotSupported
 }
 
 //
 
 func (bitstamp *Bitstamp) GetTicker(currency CurrencyPair) (*Ticker, error) {
+	var dp DecimalParser
 	urlStr := BASE_URL + "v2/ticker/" + strings.ToLower(currency.ToSymbol(""))
 	respmap, err := HttpGet(bitstamp.client, urlStr)
 	if err != nil {
@@ -305,17 +324,22 @@ func (bitstamp *Bitstamp) GetTicker(currency CurrencyPair) (*Ticker, error) {
 	}
 	//log.Println(respmap)
 	timestamp, _ := strconv.ParseUint(respmap["timestamp"].(string), 10, 64)
-	return &Ticker{
-		Last: ToFloat64(respmap["last"]),
-		High: ToFloat64(respmap["high"]),
-		Low:  ToFloat64(respmap["low"]),
-		Vol:  ToFloat64(respmap["volume"]),
-		Sell: ToFloat64(respmap["ask"]),
-		Buy:  ToFloat64(respmap["bid"]),
-		Date: timestamp}, nil
+	ticker := &Ticker{
+		Last: dp.Decimal(respmap["last"]),
+		High: dp.Decimal(respmap["high"]),
+		Low:  dp.Decimal(respmap["low"]),
+		Vol:  dp.Decimal(respmap["volume"]),
+		Sell: dp.Decimal(respmap["ask"]),
+		Buy:  dp.Decimal(respmap["bid"]),
+		Date: timestamp}
+	if dp.Err != nil {
+		return nil, dp.Err
+	}
+	return ticker, nil
 }
 
 func (bitstamp *Bitstamp) GetDepth(size int, currency CurrencyPair) (*Depth, error) {
+	var dp DecimalParser
 	urlStr := BASE_URL + "v2/order_book/" + strings.ToLower(currency.ToSymbol(""))
 	//println(urlStr)
 	respmap, err := HttpGet(bitstamp.client, urlStr)
@@ -336,7 +360,7 @@ func (bitstamp *Bitstamp) GetDepth(size int, currency CurrencyPair) (*Depth, err
 	dep := new(Depth)
 	for _, v := range bids {
 		bid := v.([]interface{})
-		dep.BidList = append(dep.BidList, DepthRecord{ToFloat64(bid[0]), ToFloat64(bid[1])})
+		dep.BidList = append(dep.BidList, DepthRecord{dp.Price(bid[0]), dp.Decimal(bid[1])})
 		i++
 		if i == size {
 			break
@@ -346,7 +370,7 @@ func (bitstamp *Bitstamp) GetDepth(size int, currency CurrencyPair) (*Depth, err
 	i = 0
 	for _, v := range asks {
 		ask := v.([]interface{})
-		dep.AskList = append(dep.AskList, DepthRecord{ToFloat64(ask[0]), ToFloat64(ask[1])})
+		dep.AskList = append(dep.AskList, DepthRecord{dp.Price(ask[0]), dp.Decimal(ask[1])})
 		i++
 		if i == size {
 			break
@@ -354,18 +378,97 @@ func (bitstamp *Bitstamp) GetDepth(size int, currency CurrencyPair) (*Depth, err
 	}
 
 	sort.Sort(sort.Reverse(dep.AskList)) //reverse
+	if dp.Err != nil {
+		return nil, dp.Err
+	}
 	return dep, nil
 }
 
+var klineSteps = map[int]int{
+	KLINE_PERIOD_1MIN:  60,
+	KLINE_PERIOD_5MIN:  300,
+	KLINE_PERIOD_15MIN: 900,
+	KLINE_PERIOD_30MIN: 1800,
+	KLINE_PERIOD_60MIN: 3600,
+	KLINE_PERIOD_4H:    14400,
+	KLINE_PERIOD_1DAY:  86400,
+}
+
 func (bitstamp *Bitstamp) GetKlineRecords(currency CurrencyPair, period, size, since int) ([]Kline, error) {
-	panic("not implement")
+	step, ok := klineSteps[period]
+	if !ok {
+		return nil, UnsupportedKlinePeriod(period)
+	}
+	if size <= 0 || size > 1000 {
+		size = 1000
+	}
+	urlStr := fmt.Sprintf("%sv2/ohlc/%s/?step=%d&limit=%d", BASE_URL, strings.ToLower(currency.ToSymbol("")), step, size)
+	if since > 0 {
+		urlStr += fmt.Sprintf("&start=%d", since/1000)
+	}
+	respmap, err := HttpGet(bitstamp.client, urlStr)
+	if err != nil {
+		return nil, err
+	}
+
+	data, isok := respmap["data"].(map[string]interface{})
+	if !isok {
+		log.Println(respmap)
+		return nil, errors.New("Get Kline Error.")
+	}
+	ohlc, _ := data["ohlc"].([]interface{})
+	klines := make([]Kline, 0, len(ohlc))
+	for _, v := range ohlc {
+		k := v.(map[string]interface{})
+		klines = append(klines, Kline{
+			Timestamp: ToInt64(k["timestamp"]),
+			Open:      ToFloat64(k["open"]),
+			High:      ToFloat64(k["high"]),
+			Low:       ToFloat64(k["low"]),
+			Close:     ToFloat64(k["close"]),
+			Vol:       ToFloat64(k["volume"])})
+	}
+	return KlinesSince(klines, int64(since), size), nil
 }
 
 ////非个人，整个交易所的交易记录
 func (bitstamp *Bitstamp) GetTrades(currencyPair CurrencyPair, since int64) ([]Trade, error) {
-	panic("not implement")
+	return nil, ErrNotSupported
 }
 
 func (bitstamp *Bitstamp) GetExchangeName() string {
 	return "bitstamp.net"
 }
+
+func (bitstamp *Bitstamp) Capabilities() Capabilities {
+	return Capabilities{Kline: true}
+}
+
+//bitstamp reports failures as {"error":"..."} or {"status":"error","reason":...,"code":"API0004"}
+func (bitstamp *Bitstamp) errorWrapper(resp string) ApiError {
+	msg := strings.ToLower(resp)
+
+	var errCode ApiError
+	switch {
+	case strings.Contains(msg, "api0004"), strings.Contains(msg, "invalid nonce"):
+		errCode = EX_ERR_NONCE
+	case strings.Contains(msg, "api0005"), strings.Contains(msg, "invalid signature"):
+		errCode = EX_ERR_SIGN
+	case strings.Contains(msg, "api0001"), strings.Contains(msg, "api key not found"):
+		errCode = EX_ERR_NOT_FIND_APIKEY
+	case strings.Contains(msg, "order not found"):
+		errCode = EX_ERR_NOT_FIND_ORDER
+	case strings.Contains(msg, "check your account balance"):
+		errCode = EX_ERR_INSUFFICIENT_BALANCE
+	case strings.Contains(msg, "minimum order size"), strings.Contains(msg, "decimal places"):
+		errCode = EX_ERR_INVALID_PRECISION
+	case strings.Contains(msg, "rate limit"), strings.Contains(msg, "too many requests"):
+		errCode = EX_ERR_API_LIMIT
+	case strings.Contains(msg, "maintenance"):
+		errCode = EX_ERR_MAINTENANCE
+	default:
+		errCode = API_ERR
+	}
+	errCode.OriginErrMsg = resp
+	return errCode
+}
@@ -0,0 +1,68 @@
+package bitstamp
+
+import (
+	"context"
+
+	. "github.com/nntaoli-project/GoEx"
+)
+
+var _ API = (*Bitstamp)(nil)
+var _ APIWithContext = (*Bitstamp)(nil)
+
+func (bitstamp *Bitstamp) withContext(ctx context.Context) *Bitstamp {
+	c := *bitstamp
+	c.client = HttpClientWithContext(ctx, bitstamp.client)
+	return &c
+}
+
+func (bitstamp *Bitstamp) LimitBuyCtx(ctx context.Context, amount, price Decimal, currency CurrencyPair) (*Order, error) {
+	return bitstamp.withContext(ctx).LimitBuy(amount, price, currency)
+}
+
+func (bitstamp *Bitstamp) LimitSellCtx(ctx context.Context, amount, price Decimal, currency CurrencyPair) (*Order, error) {
+	return bitstamp.withContext(ctx).LimitSell(amount, price, currency)
+}
+
+func (bitstamp *Bitstamp) MarketBuyCtx(ctx context.Context, amount, price Decimal, currency CurrencyPair) (*Order, error) {
+	return bitstamp.withContext(ctx).MarketBuy(amount, price, currency)
+}
+
+func (bitstamp *Bitstamp) MarketSellCtx(ctx context.Context, amount, price Decimal, currency CurrencyPair) (*Order, error) {
+	return bitstamp.withContext(ctx).MarketSell(amount, price, currency)
+}
+
+func (bitstamp *Bitstamp) CancelOrderCtx(ctx context.Context, orderId string, currency CurrencyPair) (bool, error) {
+	return bitstamp.withContext(ctx).CancelOrder(orderId, currency)
+}
+
+func (bitstamp *Bitstamp) GetOneOrderCtx(ctx context.Context, orderId string, currency CurrencyPair) (*Order, error) {
+	return bitstamp.withContext(ctx).GetOneOrder(orderId, currency)
+}
+
+func (bitstamp *Bitstamp) GetUnfinishOrdersCtx(ctx context.Context, currency CurrencyPair) ([]Order, error) {
+	return bitstamp.withContext(ctx).GetUnfinishOrders(currency)
+}
+
+func (bitstamp *Bitstamp) GetOrderHistorysCtx(ctx context.Context, currency CurrencyPair, currentPage, pageSize int) ([]Order, error) {
+	return bitstamp.withContext(ctx).GetOrderHistorys(currency, currentPage, pageSize)
+}
+
+func (bitstamp *Bitstamp) GetAccountCtx(ctx context.Context) (*Account, error) {
+	return bitstamp.withContext(ctx).GetAccount()
+}
+
+func (bitstamp *Bitstamp) GetTickerCtx(ctx context.Context, currency CurrencyPair) (*Ticker, error) {
+	return bitstamp.withContext(ctx).GetTicker(currency)
+}
+
+func (bitstamp *Bitstamp) GetDepthCtx(ctx context.Context, size int, currency CurrencyPair) (*Depth, error) {
+	return bitstamp.withContext(ctx).GetDepth(size, currency)
+}
+
+func (bitstamp *Bitstamp) GetKlineRecordsCtx(ctx context.Context, currency CurrencyPair, period, size, since int) ([]Kline, error) {
+	return bitstamp.withContext(ctx).GetKlineRecords(currency, period, size, since)
+}
+
+func (bitstamp *Bitstamp) GetTradesCtx(ctx context.Context, currencyPair CurrencyPair, since int64) ([]Trade, error) {
+	return bitstamp.withContext(ctx).GetTrades(currencyPair, since)
+}
@@ -5,11 +5,15 @@ import (
 	"github.com/stretchr/testify/assert"
 	"log"
 	"net/http"
+	"net/http/httptest"
 	"os"
+	"strings"
 	"testing"
 )
 
+var fixtures = goex.NewFixtureTransport("testdata/fixtures.json")
 var client = http.Client{
+	Transport: fixtures,
 	CheckRedirect: func(req *http.Request, via []*http.Request) error {
 		log.Println("======")
 		return nil
@@ -20,48 +24,124 @@ var btmp = NewBitstamp(&client, "", "", "")
 func TestBitstamp_GetAccount(t *testing.T) {
 	acc, err := btmp.GetAccount()
 	assert.Nil(t, err)
-	t.Log(acc)
+	assert.Equal(t, goex.SubAccount{Currency: goex.BTC, Amount: goex.RequireDecimal("0.012")}, acc.SubAccounts[goex.BTC])
+	assert.Equal(t, goex.SubAccount{Currency: goex.USD, Amount: goex.RequireDecimal("10.41"), FrozenAmount: goex.RequireDecimal("6.6")}, acc.SubAccounts[goex.USD])
+	assert.Equal(t, goex.SubAccount{Currency: goex.XRP, Amount: goex.RequireDecimal("45"), FrozenAmount: goex.RequireDecimal("55")}, acc.SubAccounts[goex.XRP])
 }
 
 func TestBitstamp_GetTicker(t *testing.T) {
 	ticker, err := btmp.GetTicker(goex.BTC_USD)
 	assert.Nil(t, err)
-	t.Log(ticker)
+	assert.Equal(t, goex.Ticker{Last: goex.RequireDecimal("6268.31"), Buy: goex.RequireDecimal("6266.05"), Sell: goex.RequireDecimal("6268.31"),
+		High: goex.RequireDecimal("6340"), Low: goex.RequireDecimal("6090"), Vol: goex.RequireDecimal("6922.48331412"), Date: 1529920350}, *ticker)
 }
 
 func TestBitstamp_GetDepth(t *testing.T) {
 	dep, err := btmp.GetDepth(5, goex.BTC_USD)
 	assert.Nil(t, err)
-	t.Log(dep.BidList)
-	t.Log(dep.AskList)
+	assert.Len(t, dep.BidList, 5)
+	assert.Equal(t, goex.DepthRecord{Price: goex.RequireDecimal("6266.05"), Amount: goex.RequireDecimal("0.03")}, dep.BidList[0])
+	assert.Equal(t, goex.DepthRecord{Price: goex.RequireDecimal("6260.82"), Amount: goex.RequireDecimal("0.5796")}, dep.BidList[4])
+	//the best ask last
+	assert.Len(t, dep.AskList, 5)
+	assert.Equal(t, goex.DepthRecord{Price: goex.RequireDecimal("6272.93"), Amount: goex.RequireDecimal("1.3")}, dep.AskList[0])
+	assert.Equal(t, goex.DepthRecord{Price: goex.RequireDecimal("6268.31"), Amount: goex.RequireDecimal("0.2")}, dep.AskList[4])
 }
 
 func TestBitstamp_LimitBuy(t *testing.T) {
-	ord, err := btmp.LimitBuy("55", "0.12", goex.XRP_USD)
+	ord, err := btmp.LimitBuy(goex.RequireDecimal("55"), goex.RequireDecimal("0.12"), goex.XRP_USD)
 	assert.Nil(t, err)
-	t.Log(ord)
+	assert.Equal(t, 311242779, ord.OrderID)
+	assert.Equal(t, goex.TradeSide(goex.BUY), ord.Side)
+	assert.Equal(t, goex.RequireDecimal("55"), ord.Amount)
+	assert.Equal(t, goex.RequireDecimal("0.12"), ord.Price)
 }
 
 func TestBitstamp_LimitSell(t *testing.T) {
-	ord, err := btmp.LimitSell("40", "0.22", goex.XRP_USD)
+	ord, err := btmp.LimitSell(goex.RequireDecimal("40"), goex.RequireDecimal("0.22"), goex.XRP_USD)
 	assert.Nil(t, err)
-	t.Log(ord)
+	assert.Equal(t, 311242781, ord.OrderID)
+	assert.Equal(t, goex.TradeSide(goex.SELL), ord.Side)
+	assert.Equal(t, goex.RequireDecimal("40"), ord.Amount)
+	assert.Equal(t, goex.RequireDecimal("0.22"), ord.Price)
 }
 
 func TestBitstamp_CancelOrder(t *testing.T) {
 	r, err := btmp.CancelOrder("311242779", goex.XRP_USD)
 	assert.Nil(t, err)
-	t.Log(r)
+	assert.True(t, r)
 }
 
 func TestBitstamp_GetUnfinishOrders(t *testing.T) {
 	ords, err := btmp.GetUnfinishOrders(goex.XRP_USD)
 	assert.Nil(t, err)
-	t.Log(ords)
+	assert.Len(t, ords, 1)
+	assert.Equal(t, 311752078, ords[0].OrderID)
+	assert.Equal(t, goex.TradeSide(goex.BUY), ords[0].Side)
+	assert.Equal(t, goex.RequireDecimal("12"), ords[0].Amount)
+	assert.Equal(t, goex.RequireDecimal("0.48"), ords[0].Price)
+	assert.Equal(t, 1529920502, ords[0].OrderTime)
 }
 
 func TestBitstamp_GetOneOrder(t *testing.T) {
 	ord, err := btmp.GetOneOrder("311752078", goex.XRP_USD)
 	assert.Nil(t, err)
-	t.Log(ord)
+	assert.Equal(t, 311752078, ord.OrderID)
+	assert.Equal(t, goex.TradeStatus(goex.ORDER_PART_FINISH), ord.Status)
+	assert.Equal(t, goex.RequireDecimal("5"), ord.DealAmount)
+	assert.Equal(t, goex.RequireDecimal("0.48"), ord.AvgPrice)
+}
+
+func TestMain(m *testing.M) {
+	os.Exit(fixtures.Run(m.Run))
+}
+
+func TestBitstamp_errorWrapper(t *testing.T) {
+	for resp, errCode := range map[string]goex.ApiError{
+		`{"status":"error","reason":"Invalid nonce","code":"API0004"}`:     goex.EX_ERR_NONCE,
+		`{"status":"error","reason":"Invalid signature","code":"API0005"}`: goex.EX_ERR_SIGN,
+		`{"status":"error","reason":"API key not found","code":"API0001"}`: goex.EX_ERR_NOT_FIND_APIKEY,
+		`{"error":"Order not found"}`:                                      goex.EX_ERR_NOT_FIND_ORDER,
+		`{"status":"error","reason":{"__all__":["You need 10.00 USD to open that order. You have only 0.00 USD available. Check your account balance for details."]}}`: goex.EX_ERR_INSUFFICIENT_BALANCE,
+		`{"status":"error","reason":{"__all__":["Minimum order size is 5.0 USD."]}}`:                                                                                   goex.EX_ERR_INVALID_PRECISION,
+		`{"status":"error","reason":"Too many requests"}`:                                                                                                              goex.EX_ERR_API_LIMIT,
+		`{"status":"error","reason":"Bitstamp is under maintenance"}`:                                                                                                  goex.EX_ERR_MAINTENANCE,
+		`{"status":"error","reason":"Invalid address"}`:                                                                                                                goex.API_ERR,
+	} {
+		err := btmp.errorWrapper(resp)
+		assert.True(t, errCode.Is(err), resp)
+		assert.Equal(t, resp, err.OriginErrMsg)
+	}
+}
+
+//rewriteTransport sends every request to the stand-in server
+type rewriteTransport struct {
+	host string
+}
+
+func (rt rewriteTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	req.URL.Scheme = "http"
+	req.URL.Host = rt.host
+	return http.DefaultTransport.RoundTrip(req)
+}
+
+func TestBitstamp_GetKlineRecords(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		q := r.URL.Query()
+		assert.Equal(t, "/api/v2/ohlc/btcusd/", r.URL.Path)
+		assert.Equal(t, "3600", q.Get("step"))
+		assert.Equal(t, "2", q.Get("limit"))
+		assert.Equal(t, "1525132800", q.Get("start"))
+		w.Write([]byte(`{"data":{"pair":"BTC/USD","ohlc":[` +
+			`{"high":"9300","timestamp":"1525132800","volume":"12.5","low":"9200","close":"9280","open":"9250"},` +
+			`{"high":"9290","timestamp":"1525136400","volume":"8.25","low":"9240","close":"9260","open":"9280"}]}}`))
+	}))
+	defer srv.Close()
+	bs := NewBitstamp(&http.Client{Transport: rewriteTransport{strings.TrimPrefix(srv.URL, "http://")}}, "", "", "")
+
+	klines, err := bs.GetKlineRecords(goex.BTC_USD, goex.KLINE_PERIOD_60MIN, 2, 1525132800000)
+	assert.Nil(t, err)
+	assert.Equal(t, []goex.Kline{
+		{Timestamp: 1525132800, Open: 9250, Close: 9280, High: 9300, Low: 9200, Vol: 12.5},
+		{Timestamp: 1525136400, Open: 9280, Close: 9260, High: 9290, Low: 9240, Vol: 8.25}}, klines)
 }
@@ -0,0 +1,68 @@
+package bittrex
+
+import (
+	"context"
+
+	. "github.com/nntaoli-project/GoEx"
+)
+
+var _ API = (*Bittrex)(nil)
+var _ APIWithContext = (*Bittrex)(nil)
+
+func (bx *Bittrex) withContext(ctx context.Context) *Bittrex {
+	c := *bx
+	c.client = HttpClientWithContext(ctx, bx.client)
+	return &c
+}
+
+func (bx *Bittrex) LimitBuyCtx(ctx context.Context, amount, price Decimal, currency CurrencyPair) (*Order, error) {
+	return bx.withContext(ctx).LimitBuy(amount, price, currency)
+}
+
+func (bx *Bittrex) LimitSellCtx(ctx context.Context, amount, price Decimal, currency CurrencyPair) (*Order, error) {
+	return bx.withContext(ctx).LimitSell(amount, price, currency)
+}
+
+func (bx *Bittrex) MarketBuyCtx(ctx context.Context, amount, price Decimal, currency CurrencyPair) (*Order, error) {
+	return bx.withContext(ctx).MarketBuy(amount, price, currency)
+}
+
+func (bx *Bittrex) MarketSellCtx(ctx context.Context, amount, price Decimal, currency CurrencyPair) (*Order, error) {
+	return bx.withContext(ctx).MarketSell(amount, price, currency)
+}
+
+func (bx *Bittrex) CancelOrderCtx(ctx context.Context, orderId string, currency CurrencyPair) (bool, error) {
+	return bx.withContext(ctx).CancelOrder(orderId, currency)
+}
+
+func (bx *Bittrex) GetOneOrderCtx(ctx context.Context, orderId string, currency CurrencyPair) (*Order, error) {
+	return bx.withContext(ctx).GetOneOrder(orderId, currency)
+}
+
+func (bx *Bittrex) GetUnfinishOrdersCtx(ctx context.Context, currency CurrencyPair) ([]Order, error) {
+	return bx.withContext(ctx).GetUnfinishOrders(currency)
+}
+
+func (bx *Bittrex) GetOrderHistorysCtx(ctx context.Context, currency CurrencyPair, currentPage, pageSize int) ([]Order, error) {
+	return bx.withContext(ctx).GetOrderHistorys(currency, currentPage, pageSize)
+}
+
+func (bx *Bittrex) GetAccountCtx(ctx context.Context) (*Account, error) {
+	return bx.withContext(ctx).GetAccount()
+}
+
+func (bx *Bittrex) GetTickerCtx(ctx context.Context, currency CurrencyPair) (*Ticker, error) {
+	return bx.withContext(ctx).GetTicker(currency)
+}
+
+func (bx *Bittrex) GetDepthCtx(ctx context.Context, size int, currency CurrencyPair) (*Depth, error) {
+	return bx.withContext(ctx).GetDepth(size, currency)
+}
+
+func (bx *Bittrex) GetKlineRecordsCtx(ctx context.Context, currency CurrencyPair, period, size, since int) ([]Kline, error) {
+	return bx.withContext(ctx).GetKlineRecords(currency, period, size, since)
+}
+
+func (bx *Bittrex) GetTradesCtx(ctx context.Context, currencyPair CurrencyPair, since int64) ([]Trade, error) {
+	return bx.withContext(ctx).GetTrades(currencyPair, since)
+}
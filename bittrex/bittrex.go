@@ -5,6 +5,8 @@ import (
 	. "github.com/nntaoli-project/GoEx"
 	"net/http"
 	"sort"
+	"strings"
+	"time"
 )
 
 type Bittrex struct {
@@ -15,43 +17,46 @@ type Bittrex struct {
 }
 
 func New(client *http.Client, accesskey, secretkey string) *Bittrex {
+	client = ExchangeRateLimitedClient(client, "bittrex.com", accesskey, DefaultRateLimitConfig)
 	return &Bittrex{client: client, accesskey: accesskey, secretkey: secretkey, baseUrl: "https://bittrex.com/api/v1.1"}
 }
 
-func (bx *Bittrex) LimitBuy(amount, price string, currency CurrencyPair) (*Order, error) {
-	panic("not implement")
+func (bx *Bittrex) LimitBuy(amount, price Decimal, currency CurrencyPair) (*Order, error) {
+	return nil, ErrNotSupported
 }
-func (bx *Bittrex) LimitSell(amount, price string, currency CurrencyPair) (*Order, error) {
-	panic("not implement")
+func (bx *Bittrex) LimitSell(amount, price Decimal, currency CurrencyPair) (*Order, error) {
+	return nil, ErrNotSupported
 }
-func (bx *Bittrex) MarketBuy(amount, price string, currency CurrencyPair) (*Order, error) {
-	panic("not implement")
+func (bx *Bittrex) MarketBuy(amount, price Decimal, currency CurrencyPair) (*Order, error) {
+	return nil, ErrNotSupported
 }
-func (bx *Bittrex) MarketSell(amount, price string, currency CurrencyPair) (*Order, error) {
-	panic("not implement")
+func (bx *Bittrex) MarketSell(amount, price Decimal, currency CurrencyPair) (*Order, error) {
+	return nil, ErrNotSupported
 }
 func (bx *Bittrex) CancelOrder(orderId string, currency CurrencyPair) (bool, error) {
-	panic("not implement")
+	return false, ErrNotSupported
 }
 func (bx *Bittrex) GetOneOrder(orderId string, currency CurrencyPair) (*Order, error) {
-	panic("not implement")
+	return nil, ErrNotSupported
 }
 func (bx *Bittrex) GetUnfinishOrders(currency CurrencyPair) ([]Order, error) {
-	panic("not implement")
+	return nil, ErrNotSupported
 }
 func (bx *Bittrex) GetOrderHistorys(currency CurrencyPair, currentPage, pageSize int) ([]Order, error) {
-	panic("not implement")
+	return nil, ErrNotSupported
 }
 func (bx *Bittrex) GetAccount() (*Account, error) {
-	panic("not implement")
+	return nil, ErrNotSupported
 }
 
 func (bx *Bittrex) GetTicker(currency CurrencyPair) (*Ticker, error) {
+	var dp DecimalParser
 	resp, err := HttpGet(bx.client, fmt.Sprintf("%s/public/getmarketsummary?market=%s", bx.baseUrl, currency.ToSymbol2("-")))
 	if err != nil {
-		errCode := HTTP_ERR_CODE
-		errCode.OriginErrMsg = err.Error()
-		return nil, errCode
+		return nil, bx.adaptError(err)
+	}
+	if success, _ := resp["success"].(bool); !success {
+		return nil, bx.errorWrapper(fmt.Sprint(resp["message"]))
 	}
 
 	result, _ := resp["result"].([]interface{})
@@ -61,26 +66,32 @@ func (bx *Bittrex) GetTicker(currency CurrencyPair) (*Ticker, error) {
 
 	tickermap := result[0].(map[string]interface{})
 
-	return &Ticker{
-		Last: ToFloat64(tickermap["Last"]),
-		Sell: ToFloat64(tickermap["Ask"]),
-		Buy:  ToFloat64(tickermap["Bid"]),
-		Low:  ToFloat64(tickermap["Low"]),
-		High: ToFloat64(tickermap["High"]),
-		Vol:  ToFloat64(tickermap["Volume"]),
-	}, nil
+	ticker := &Ticker{
+		Last: dp.Decimal(tickermap["Last"]),
+		Sell: dp.Decimal(tickermap["Ask"]),
+		Buy:  dp.Decimal(tickermap["Bid"]),
+		Low:  dp.Decimal(tickermap["Low"]),
+		High: dp.Decimal(tickermap["High"]),
+		Vol:  dp.Decimal(tickermap["Volume"]),
+	}
+	if dp.Err != nil {
+		return nil, dp.Err
+	}
+	return ticker, nil
 }
 
 func (bx *Bittrex) GetDepth(size int, currency CurrencyPair) (*Depth, error) {
+	var dp DecimalParser
 
 	resp, err := HttpGet(bx.client, fmt.Sprintf("%s/public/getorderbook?market=%s&type=both", bx.baseUrl, currency.ToSymbol2("-")))
 	if err != nil {
-		errCode := HTTP_ERR_CODE
-		errCode.OriginErrMsg = err.Error()
-		return nil, errCode
+		return nil, bx.adaptError(err)
+	}
+	if success, _ := resp["success"].(bool); !success {
+		return nil, bx.errorWrapper(fmt.Sprint(resp["message"]))
 	}
 
-	result := resp["result"].(map[string]interface{})
+	result, _ := resp["result"].(map[string]interface{})
 
 	bids, _ := result["buy"].([]interface{})
 	asks, _ := result["sell"].([]interface{})
@@ -89,28 +100,131 @@ func (bx *Bittrex) GetDepth(size int, currency CurrencyPair) (*Depth, error) {
 
 	for _, v := range bids {
 		r := v.(map[string]interface{})
-		dep.BidList = append(dep.BidList, DepthRecord{ToFloat64(r["Rate"]), ToFloat64(r["Quantity"])})
+		dep.BidList = append(dep.BidList, DepthRecord{dp.Price(r["Rate"]), dp.Decimal(r["Quantity"])})
 	}
 
 	for _, v := range asks {
 		r := v.(map[string]interface{})
-		dep.AskList = append(dep.AskList, DepthRecord{ToFloat64(r["Rate"]), ToFloat64(r["Quantity"])})
+		dep.AskList = append(dep.AskList, DepthRecord{dp.Price(r["Rate"]), dp.Decimal(r["Quantity"])})
 	}
 
 	sort.Sort(sort.Reverse(dep.AskList))
 
+	if dp.Err != nil {
+		return nil, dp.Err
+	}
 	return dep, nil
 }
 
+var klineIntervals = map[int]string{
+	KLINE_PERIOD_1MIN:  "oneMin",
+	KLINE_PERIOD_5MIN:  "fiveMin",
+	KLINE_PERIOD_30MIN: "thirtyMin",
+	KLINE_PERIOD_60MIN: "hour",
+	KLINE_PERIOD_1DAY:  "day",
+}
+
+//GetKlineRecords reads the ticks of the v2 api, which answers a fixed window of the latest ones, some days of
+//minutes up to years of days. Candles before the window are not to be had.
 func (bx *Bittrex) GetKlineRecords(currency CurrencyPair, period, size, since int) ([]Kline, error) {
-	panic("not implement")
+	interval, ok := klineIntervals[period]
+	if !ok {
+		return nil, UnsupportedKlinePeriod(period)
+	}
+	v2Url := strings.TrimSuffix(bx.baseUrl, "/api/v1.1") + "/Api/v2.0"
+	resp, err := HttpGet(bx.client, fmt.Sprintf("%s/pub/market/GetTicks?marketName=%s&tickInterval=%s", v2Url, currency.ToSymbol2("-"), interval))
+	if err != nil {
+		return nil, bx.adaptError(err)
+	}
+	if success, _ := resp["success"].(bool); !success {
+		return nil, bx.errorWrapper(fmt.Sprint(resp["message"]))
+	}
+
+	result, _ := resp["result"].([]interface{})
+	klines := make([]Kline, 0, len(result))
+	for _, v := range result {
+		r := v.(map[string]interface{})
+		t, _ := time.Parse("2006-01-02T15:04:05", fmt.Sprint(r["T"]))
+		klines = append(klines, Kline{
+			Timestamp: t.Unix(),
+			Open:      ToFloat64(r["O"]),
+			High:      ToFloat64(r["H"]),
+			Low:       ToFloat64(r["L"]),
+			Close:     ToFloat64(r["C"]),
+			Vol:       ToFloat64(r["V"])})
+	}
+	return KlinesSince(klines, int64(since), size), nil
 }
 
 //非个人，整个交易所的交易记录
+//GetTrades answers the trades from since on among the latest 100, bittrex has no paging for older ones
 func (bx *Bittrex) GetTrades(currencyPair CurrencyPair, since int64) ([]Trade, error) {
-	panic("not implement")
+	var dp DecimalParser
+	resp, err := HttpGet(bx.client, fmt.Sprintf("%s/public/getmarkethistory?market=%s", bx.baseUrl, currencyPair.ToSymbol2("-")))
+	if err != nil {
+		return nil, bx.adaptError(err)
+	}
+	if success, _ := resp["success"].(bool); !success {
+		return nil, bx.errorWrapper(fmt.Sprint(resp["message"]))
+	}
+
+	result, _ := resp["result"].([]interface{})
+	trades := make([]Trade, 0, len(result))
+	for _, v := range result {
+		t := v.(map[string]interface{})
+		date, _ := time.Parse("2006-01-02T15:04:05", fmt.Sprint(t["TimeStamp"]))
+		trades = append(trades, Trade{
+			Tid:    ToInt64(t["Id"]),
+			Type:   strings.ToLower(fmt.Sprint(t["OrderType"])),
+			Amount: dp.Decimal(t["Quantity"]),
+			Price:  dp.Decimal(t["Price"]),
+			Date:   date.UnixNano() / int64(time.Millisecond)})
+	}
+	if dp.Err != nil {
+		return nil, dp.Err
+	}
+	return TradesSinceTime(trades, since), nil
 }
 
 func (bx *Bittrex) GetExchangeName() string {
 	return "bittrex.com"
 }
+
+func (bx *Bittrex) Capabilities() Capabilities {
+	return Capabilities{Kline: true, Trades: true}
+}
+
+//bittrex answers {"success":false,"message":"INVALID_MARKET","result":null}
+func (bx *Bittrex) errorWrapper(message string) ApiError {
+	var errCode ApiError
+	switch message {
+	case "INVALID_MARKET", "MARKET_NOT_PROVIDED":
+		errCode = EX_ERR_INVALID_CURRENCY_PAIR
+	case "APIKEY_INVALID", "APIKEY_NOT_PROVIDED":
+		errCode = EX_ERR_NOT_FIND_APIKEY
+	case "INVALID_SIGNATURE":
+		errCode = EX_ERR_SIGN
+	case "NONCE_NOT_PROVIDED":
+		errCode = EX_ERR_NONCE
+	case "INSUFFICIENT_FUNDS":
+		errCode = EX_ERR_INSUFFICIENT_BALANCE
+	case "INVALID_ORDER", "ORDER_NOT_OPEN":
+		errCode = EX_ERR_NOT_FIND_ORDER
+	case "MIN_TRADE_REQUIREMENT_NOT_MET", "DUST_TRADE_DISALLOWED_MIN_VALUE_50K_SAT":
+		errCode = EX_ERR_INVALID_PRECISION
+	default:
+		errCode = API_ERR
+	}
+	errCode.OriginErrMsg = message
+	return errCode
+}
+
+//adaptError keeps the ApiError of a failed status, other errors happened before a response and are HTTP_ERR_CODE
+func (bx *Bittrex) adaptError(err error) error {
+	if _, isok := err.(ApiError); isok {
+		return err
+	}
+	errCode := HTTP_ERR_CODE
+	errCode.OriginErrMsg = err.Error()
+	return errCode
+}
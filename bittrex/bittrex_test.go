@@ -1,22 +1,106 @@
 package bittrex
 
 import (
+	"errors"
 	"github.com/nntaoli-project/GoEx"
+	"github.com/stretchr/testify/assert"
 	"net/http"
+	"net/http/httptest"
+	"os"
+	"strings"
 	"testing"
 )
 
-var b = New(http.DefaultClient, "", "")
+var fixtures = goex.NewFixtureTransport("testdata/fixtures.json")
+var b = New(fixtures.Client(), "", "")
 
 func TestBittrex_GetTicker(t *testing.T) {
 	ticker, err := b.GetTicker(goex.BTC_USDT)
-	t.Log("err=>", err)
-	t.Log("ticker=>", ticker)
+	assert.Nil(t, err)
+	assert.Equal(t, goex.Ticker{Last: goex.RequireDecimal("6263.00000001"), Buy: goex.RequireDecimal("6262.00000001"), Sell: goex.RequireDecimal("6263.00000001"),
+		High: goex.RequireDecimal("6340"), Low: goex.RequireDecimal("6085.00000001"), Vol: goex.RequireDecimal("2816.20542087")}, *ticker)
 }
 
 func TestBittrex_GetDepth(t *testing.T) {
 	dep, err := b.GetDepth(1, goex.BTC_USDT)
-	t.Log("err=>", err)
-	t.Log("ask=>", dep.AskList)
-	t.Log("bid=>", dep.BidList)
+	assert.Nil(t, err)
+	//the best ask last
+	assert.Equal(t, goex.DepthRecords{
+		{Price: goex.RequireDecimal("6265"), Amount: goex.RequireDecimal("0.04")},
+		{Price: goex.RequireDecimal("6263.00000001"), Amount: goex.RequireDecimal("0.3")}}, dep.AskList)
+	assert.Equal(t, goex.DepthRecords{
+		{Price: goex.RequireDecimal("6262.00000001"), Amount: goex.RequireDecimal("0.12")},
+		{Price: goex.RequireDecimal("6261"), Amount: goex.RequireDecimal("1.5")}}, dep.BidList)
+}
+
+func TestMain(m *testing.M) {
+	os.Exit(fixtures.Run(m.Run))
+}
+
+func TestBittrex_errorWrapper(t *testing.T) {
+	for message, errCode := range map[string]goex.ApiError{
+		"INVALID_MARKET":                goex.EX_ERR_INVALID_CURRENCY_PAIR,
+		"APIKEY_INVALID":                goex.EX_ERR_NOT_FIND_APIKEY,
+		"INVALID_SIGNATURE":             goex.EX_ERR_SIGN,
+		"NONCE_NOT_PROVIDED":            goex.EX_ERR_NONCE,
+		"INSUFFICIENT_FUNDS":            goex.EX_ERR_INSUFFICIENT_BALANCE,
+		"ORDER_NOT_OPEN":                goex.EX_ERR_NOT_FIND_ORDER,
+		"MIN_TRADE_REQUIREMENT_NOT_MET": goex.EX_ERR_INVALID_PRECISION,
+		"QUANTITY_NOT_PROVIDED":         goex.API_ERR,
+	} {
+		err := b.errorWrapper(message)
+		assert.True(t, errCode.Is(err), message)
+		assert.Equal(t, message, err.OriginErrMsg)
+	}
+}
+
+func TestBittrex_adaptError(t *testing.T) {
+	limited := goex.EX_ERR_API_LIMIT
+	assert.Equal(t, error(limited), b.adaptError(limited))
+	assert.True(t, goex.HTTP_ERR_CODE.Is(b.adaptError(errors.New("connection refused"))))
+}
+
+//rewriteTransport sends every request to the stand-in server
+type rewriteTransport struct {
+	host string
+}
+
+func (rt rewriteTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	req.URL.Scheme = "http"
+	req.URL.Host = rt.host
+	return http.DefaultTransport.RoundTrip(req)
+}
+
+func TestBittrex_GetTrades(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "/api/v1.1/public/getmarkethistory", r.URL.Path)
+		assert.Equal(t, "USDT-BTC", r.URL.Query().Get("market"))
+		w.Write([]byte(`{"success":true,"message":"","result":[{"Id":23549421,"TimeStamp":"2018-05-01T05:30:12.37","Quantity":0.01,` +
+			`"Price":9250,"Total":92.5,"FillType":"FILL","OrderType":"SELL"}]}`))
+	}))
+	defer srv.Close()
+	bx := New(&http.Client{Transport: rewriteTransport{strings.TrimPrefix(srv.URL, "http://")}}, "", "")
+
+	trades, err := bx.GetTrades(goex.BTC_USDT, 0)
+	assert.Nil(t, err)
+	assert.Equal(t, []goex.Trade{{Tid: 23549421, Type: "sell", Amount: goex.RequireDecimal("0.01"), Price: goex.RequireDecimal("9250"),
+		Date: 1525152612370}}, trades)
+}
+
+func TestBittrex_GetKlineRecords(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "/Api/v2.0/pub/market/GetTicks", r.URL.Path)
+		assert.Equal(t, "USDT-BTC", r.URL.Query().Get("marketName"))
+		assert.Equal(t, "hour", r.URL.Query().Get("tickInterval"))
+		w.Write([]byte(`{"success":true,"message":"","result":[{"O":9250,"H":9300,"L":9200,"C":9280,"V":12.5,"T":"2018-05-01T00:00:00","BV":115625},` +
+			`{"O":9280,"H":9290,"L":9240,"C":9260,"V":8.25,"T":"2018-05-01T01:00:00","BV":76395}]}`))
+	}))
+	defer srv.Close()
+	bx := New(&http.Client{Transport: rewriteTransport{strings.TrimPrefix(srv.URL, "http://")}}, "", "")
+
+	klines, err := bx.GetKlineRecords(goex.BTC_USDT, goex.KLINE_PERIOD_60MIN, 2, 0)
+	assert.Nil(t, err)
+	assert.Equal(t, []goex.Kline{
+		{Timestamp: 1525132800, Open: 9250, Close: 9280, High: 9300, Low: 9200, Vol: 12.5},
+		{Timestamp: 1525136400, Open: 9280, Close: 9260, High: 9290, Low: 9240, Vol: 8.25}}, klines)
 }
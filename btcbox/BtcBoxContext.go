@@ -0,0 +1,68 @@
+package btcbox
+
+import (
+	"context"
+
+	. "github.com/nntaoli-project/GoEx"
+)
+
+var _ API = (*BtcBox)(nil)
+var _ APIWithContext = (*BtcBox)(nil)
+
+func (btcbox *BtcBox) withContext(ctx context.Context) *BtcBox {
+	c := *btcbox
+	c.client = HttpClientWithContext(ctx, btcbox.client)
+	return &c
+}
+
+func (btcbox *BtcBox) LimitBuyCtx(ctx context.Context, amount, price Decimal, currency CurrencyPair) (*Order, error) {
+	return btcbox.withContext(ctx).LimitBuy(amount, price, currency)
+}
+
+func (btcbox *BtcBox) LimitSellCtx(ctx context.Context, amount, price Decimal, currency CurrencyPair) (*Order, error) {
+	return btcbox.withContext(ctx).LimitSell(amount, price, currency)
+}
+
+func (btcbox *BtcBox) MarketBuyCtx(ctx context.Context, amount, price Decimal, currency CurrencyPair) (*Order, error) {
+	return btcbox.withContext(ctx).MarketBuy(amount, price, currency)
+}
+
+func (btcbox *BtcBox) MarketSellCtx(ctx context.Context, amount, price Decimal, currency CurrencyPair) (*Order, error) {
+	return btcbox.withContext(ctx).MarketSell(amount, price, currency)
+}
+
+func (btcbox *BtcBox) CancelOrderCtx(ctx context.Context, orderId string, currency CurrencyPair) (bool, error) {
+	return btcbox.withContext(ctx).CancelOrder(orderId, currency)
+}
+
+func (btcbox *BtcBox) GetOneOrderCtx(ctx context.Context, orderId string, currency CurrencyPair) (*Order, error) {
+	return btcbox.withContext(ctx).GetOneOrder(orderId, currency)
+}
+
+func (btcbox *BtcBox) GetUnfinishOrdersCtx(ctx context.Context, currency CurrencyPair) ([]Order, error) {
+	return btcbox.withContext(ctx).GetUnfinishOrders(currency)
+}
+
+func (btcbox *BtcBox) GetOrderHistorysCtx(ctx context.Context, currency CurrencyPair, currentPage, pageSize int) ([]Order, error) {
+	return btcbox.withContext(ctx).GetOrderHistorys(currency, currentPage, pageSize)
+}
+
+func (btcbox *BtcBox) GetAccountCtx(ctx context.Context) (*Account, error) {
+	return btcbox.withContext(ctx).GetAccount()
+}
+
+func (btcbox *BtcBox) GetTickerCtx(ctx context.Context, currency CurrencyPair) (*Ticker, error) {
+	return btcbox.withContext(ctx).GetTicker(currency)
+}
+
+func (btcbox *BtcBox) GetDepthCtx(ctx context.Context, size int, currency CurrencyPair) (*Depth, error) {
+	return btcbox.withContext(ctx).GetDepth(size, currency)
+}
+
+func (btcbox *BtcBox) GetKlineRecordsCtx(ctx context.Context, currency CurrencyPair, period, size, since int) ([]Kline, error) {
+	return btcbox.withContext(ctx).GetKlineRecords(currency, period, size, since)
+}
+
+func (btcbox *BtcBox) GetTradesCtx(ctx context.Context, currencyPair CurrencyPair, since int64) ([]Trade, error) {
+	return btcbox.withContext(ctx).GetTrades(currencyPair, since)
+}
@@ -1,6 +1,7 @@
 package btcbox
 
 import (
+	"fmt"
 	. "github.com/nntaoli-project/GoEx"
 	"net/http"
 	"strings"
@@ -17,68 +18,81 @@ type BtcBox struct {
 }
 
 func New(client *http.Client, apikey, secretkey string) *BtcBox {
+	client = ExchangeRateLimitedClient(client, "btcbox.co.jp", apikey, DefaultRateLimitConfig)
 	return &BtcBox{client: client, accessKey: apikey, secretkey: secretkey}
 }
 
-func (btcbox *BtcBox) LimitBuy(amount, price string, currency CurrencyPair) (*Order, error) {
-	panic("not implements")
+func (btcbox *BtcBox) LimitBuy(amount, price Decimal, currency CurrencyPair) (*Order, error) {
+	return nil, ErrNotSupported
 }
 
-func (btcbox *BtcBox) LimitSell(amount, price string, currency CurrencyPair) (*Order, error) {
-	panic("not implements")
+func (btcbox *BtcBox) LimitSell(amount, price Decimal, currency CurrencyPair) (*Order, error) {
+	return nil, ErrNotSupported
 }
 
-func (btcbox *BtcBox) MarketBuy(amount, price string, currency CurrencyPair) (*Order, error) {
-	panic("not implements")
+func (btcbox *BtcBox) MarketBuy(amount, price Decimal, currency CurrencyPair) (*Order, error) {
+	return nil, ErrNotSupported
 }
 
-func (btcbox *BtcBox) MarketSell(amount, price string, currency CurrencyPair) (*Order, error) {
-	panic("not implements")
+func (btcbox *BtcBox) MarketSell(amount, price Decimal, currency CurrencyPair) (*Order, error) {
+	return nil, ErrNotSupported
 }
 
 func (btcbox *BtcBox) CancelOrder(orderId string, currency CurrencyPair) (bool, error) {
-	panic("not implements")
+	return false, ErrNotSupported
 }
 
 func (btcbox *BtcBox) GetOneOrder(orderId string, currency CurrencyPair) (*Order, error) {
-	panic("not implements")
+	return nil, ErrNotSupported
 }
 func (btcbox *BtcBox) GetUnfinishOrders(currency CurrencyPair) ([]Order, error) {
-	panic("not implements")
+	return nil, ErrNotSupported
 }
 
 func (btcbox *BtcBox) GetOrderHistorys(currency CurrencyPair, currentPage, pageSize int) ([]Order, error) {
-	panic("not implements")
+	return nil, ErrNotSupported
 }
 
 func (btcbox *BtcBox) GetAccount() (*Account, error) {
-	panic("not implements")
+	return nil, ErrNotSupported
 }
 
 func (btcbox *BtcBox) GetTicker(currency CurrencyPair) (*Ticker, error) {
+	var dp DecimalParser
 	respmap, err := HttpGet(btcbox.client, baseurl+"ticker?coin="+strings.ToLower(currency.CurrencyA.Symbol))
 	if err != nil {
 		return nil, err
 	}
+	if result, isok := respmap["result"].(bool); isok && !result {
+		return nil, btcbox.errorWrapper(respmap)
+	}
 
-	return &Ticker{
-		Low:  ToFloat64(respmap["low"]),
-		Buy:  ToFloat64(respmap["buy"]),
-		Sell: ToFloat64(respmap["sell"]),
-		Last: ToFloat64(respmap["last"]),
-		Vol:  ToFloat64(respmap["vol"]),
-		High: ToFloat64(respmap["high"])}, nil
+	ticker := &Ticker{
+		Low:  dp.Decimal(respmap["low"]),
+		Buy:  dp.Decimal(respmap["buy"]),
+		Sell: dp.Decimal(respmap["sell"]),
+		Last: dp.Decimal(respmap["last"]),
+		Vol:  dp.Decimal(respmap["vol"]),
+		High: dp.Decimal(respmap["high"])}
+	if dp.Err != nil {
+		return nil, dp.Err
+	}
+	return ticker, nil
 }
 
 func (btcbox *BtcBox) GetDepth(size int, currency CurrencyPair) (*Depth, error) {
+	var dp DecimalParser
 	respmap, err := HttpGet(btcbox.client, baseurl+"depth?coin="+strings.ToLower(currency.CurrencyA.Symbol))
 	if err != nil {
 		return nil, err
 	}
 	//log.Println(respmap)
+	if result, isok := respmap["result"].(bool); isok && !result {
+		return nil, btcbox.errorWrapper(respmap)
+	}
 	dep := new(Depth)
-	asksmap := respmap["asks"].([]interface{})
-	bidsmap := respmap["bids"].([]interface{})
+	asksmap, _ := respmap["asks"].([]interface{})
+	bidsmap, _ := respmap["bids"].([]interface{})
 
 	var (
 		l     = len(asksmap)
@@ -92,9 +106,9 @@ func (btcbox *BtcBox) GetDepth(size int, currency CurrencyPair) (*Depth, error)
 		for i, vv := range ask.([]interface{}) {
 			switch i {
 			case 0:
-				dr.Price = vv.(float64)
+				dr.Price = dp.Price(vv)
 			case 1:
-				dr.Amount = vv.(float64)
+				dr.Amount = dp.Decimal(vv)
 			}
 		}
 		dep.AskList = append(dep.AskList, dr)
@@ -106,9 +120,9 @@ func (btcbox *BtcBox) GetDepth(size int, currency CurrencyPair) (*Depth, error)
 		for i, vv := range v.([]interface{}) {
 			switch i {
 			case 0:
-				dr.Price = vv.(float64)
+				dr.Price = dp.Price(vv)
 			case 1:
-				dr.Amount = vv.(float64)
+				dr.Amount = dp.Decimal(vv)
 			}
 		}
 		dep.BidList = append(dep.BidList, dr)
@@ -118,18 +132,32 @@ func (btcbox *BtcBox) GetDepth(size int, currency CurrencyPair) (*Depth, error)
 		}
 	}
 
+	if dp.Err != nil {
+		return nil, dp.Err
+	}
 	return dep, nil
 }
 
 func (btcbox *BtcBox) GetKlineRecords(currency CurrencyPair, period, size, since int) ([]Kline, error) {
-	panic("not implements")
+	return nil, ErrNotSupported
 }
 
 //非个人，整个交易所的交易记录
 func (btcbox *BtcBox) GetTrades(currencyPair CurrencyPair, since int64) ([]Trade, error) {
-	panic("not implements")
+	return nil, ErrNotSupported
 }
 
 func (btcbox *BtcBox) GetExchangeName() string {
 	return "btcbox.co.jp"
 }
+
+func (btcbox *BtcBox) Capabilities() Capabilities {
+	return Capabilities{}
+}
+
+//btcbox reports failures as {"result":false,"code":"..."}, its codes are not mapped
+func (btcbox *BtcBox) errorWrapper(respmap map[string]interface{}) ApiError {
+	errCode := API_ERR
+	errCode.OriginErrMsg = fmt.Sprintf("[%v]", respmap["code"])
+	return errCode
+}
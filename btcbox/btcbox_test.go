@@ -3,24 +3,41 @@ package btcbox
 import (
 	"github.com/nntaoli-project/GoEx"
 	"github.com/stretchr/testify/assert"
-	"net/http"
+	"os"
 	"testing"
 )
 
-var btcbox = New(http.DefaultClient, "", "")
+var fixtures = goex.NewFixtureTransport("testdata/fixtures.json")
+var btcbox = New(fixtures.Client(), "", "")
 
 func TestBtcBox_GetTicker(t *testing.T) {
 	ticker, err := btcbox.GetTicker(goex.BTC_JPY)
 	assert.Nil(t, err)
-	t.Log(ticker)
+	assert.Equal(t, goex.Ticker{Last: goex.RequireDecimal("685980"), Buy: goex.RequireDecimal("685060"), Sell: goex.RequireDecimal("685980"),
+		High: goex.RequireDecimal("692000"), Low: goex.RequireDecimal("668100"), Vol: goex.RequireDecimal("1462.5402")}, *ticker)
 }
 
 func TestBtcBox_GetDepth(t *testing.T) {
 	dep, err := btcbox.GetDepth(5, goex.ETH_JPY)
 	assert.Nil(t, err)
-	t.Log(dep.BidList)
+	assert.Equal(t, goex.DepthRecords{
+		{Price: goex.RequireDecimal("51700"), Amount: goex.RequireDecimal("1.5")},
+		{Price: goex.RequireDecimal("51680"), Amount: goex.RequireDecimal("0.3")},
+		{Price: goex.RequireDecimal("51660"), Amount: goex.RequireDecimal("2.74")},
+		{Price: goex.RequireDecimal("51600"), Amount: goex.RequireDecimal("10")},
+		{Price: goex.RequireDecimal("51550"), Amount: goex.RequireDecimal("0.12")}}, dep.BidList)
 }
 
 func TestBtcBox_CancelOrder(t *testing.T) {
 
 }
+
+func TestMain(m *testing.M) {
+	os.Exit(fixtures.Run(m.Run))
+}
+
+func TestBtcBox_errorWrapper(t *testing.T) {
+	err := btcbox.errorWrapper(map[string]interface{}{"result": false, "code": "104"})
+	assert.True(t, goex.API_ERR.Is(err))
+	assert.Equal(t, "[104]", err.OriginErrMsg)
+}
@@ -32,10 +32,12 @@ var (
 )
 
 func NewBTCChina(client *http.Client, accessKey, secretKey string) *BTCChina {
+	client = ExchangeRateLimitedClient(client, "btcchina.com", accessKey, DefaultRateLimitConfig)
 	return &BTCChina{client, accessKey, secretKey}
 }
 
 func (btch *BTCChina) GetTicker(currency CurrencyPair) (*Ticker, error) {
+	var dp DecimalParser
 	tickerResp, err := HttpGet(btch.httpClient, fmt.Sprintf("%s/ticker?market=%s",
 		_MARKET_API_URL, strings.ToLower(currency.ToSymbol(""))))
 
@@ -51,19 +53,23 @@ func (btch *BTCChina) GetTicker(currency CurrencyPair) (*Ticker, error) {
 	}
 
 	ticker := Ticker{}
-	ticker.Buy = ToFloat64(tickermap["buy"])
-	ticker.Sell = ToFloat64(tickermap["sell"])
-	ticker.High = ToFloat64(tickermap["high"])
-	ticker.Low = ToFloat64(tickermap["low"])
-	ticker.Last = ToFloat64(tickermap["last"])
-	ticker.Vol = ToFloat64(tickermap["vol"])
+	ticker.Buy = dp.Decimal(tickermap["buy"])
+	ticker.Sell = dp.Decimal(tickermap["sell"])
+	ticker.High = dp.Decimal(tickermap["high"])
+	ticker.Low = dp.Decimal(tickermap["low"])
+	ticker.Last = dp.Decimal(tickermap["last"])
+	ticker.Vol = dp.Decimal(tickermap["vol"])
 	date := tickermap["date"].(float64)
 	ticker.Date = uint64(date)
 
+	if dp.Err != nil {
+		return nil, dp.Err
+	}
 	return &ticker, nil
 }
 
 func (btch *BTCChina) GetDepth(size int, currency CurrencyPair) (*Depth, error) {
+	var dp DecimalParser
 	depthresp, err := HttpGet(btch.httpClient, fmt.Sprintf("%s/orderbook?market=%s&limit=%d",
 		_MARKET_API_URL, strings.ToLower(currency.ToSymbol("")), size))
 
@@ -83,9 +89,9 @@ func (btch *BTCChina) GetDepth(size int, currency CurrencyPair) (*Depth, error)
 		for i, vv := range v.([]interface{}) {
 			switch i {
 			case 0:
-				dr.Price = ToFloat64(vv)
+				dr.Price = dp.Price(vv)
 			case 1:
-				dr.Amount = ToFloat64(vv)
+				dr.Amount = dp.Decimal(vv)
 			}
 		}
 		depth.AskList = append(depth.AskList, dr)
@@ -96,22 +102,26 @@ func (btch *BTCChina) GetDepth(size int, currency CurrencyPair) (*Depth, error)
 		for i, vv := range v.([]interface{}) {
 			switch i {
 			case 0:
-				dr.Price = ToFloat64(vv)
+				dr.Price = dp.Price(vv)
 			case 1:
-				dr.Amount = ToFloat64(vv)
+				dr.Amount = dp.Decimal(vv)
 			}
 		}
 		depth.BidList = append(depth.BidList, dr)
 	}
 
+	if dp.Err != nil {
+		return nil, dp.Err
+	}
 	return &depth, nil
 }
 
 func (btch *BTCChina) GetKlineRecords(currency CurrencyPair, period , size, since int) ([]Kline, error) {
-	panic("unimplement")
+	return nil, ErrNotSupported
 }
 
 func (btch *BTCChina) GetAccount() (*Account, error) {
+	var dp DecimalParser
 	respmap, err := btch.sendAuthorizationRequst("getAccountInfo", []interface{}{})
 	if err != nil {
 		return nil, err
@@ -131,8 +141,8 @@ func (btch *BTCChina) GetAccount() (*Account, error) {
 		_frozen := frozen[c].(map[string]interface{})
 
 		sub := SubAccount{
-			Amount:       ToFloat64(vv["amount"]),
-			ForzenAmount: ToFloat64(_frozen["amount"])}
+			Amount:       dp.Decimal(vv["amount"]),
+			FrozenAmount: dp.Decimal(_frozen["amount"])}
 		var currency Currency
 
 		switch c {
@@ -146,13 +156,18 @@ func (btch *BTCChina) GetAccount() (*Account, error) {
 			currency = UNKNOWN
 		}
 
+		sub.Currency = currency
 		acc.SubAccounts[currency] = sub
 	}
 
+	if dp.Err != nil {
+		return nil, dp.Err
+	}
 	return acc, nil
 }
 
 func (btch *BTCChina) placeorder(method, amount, price string, currencyPair CurrencyPair) (*Order, error) {
+	var dp DecimalParser
 	respmap, err := btch.sendAuthorizationRequst(method, []interface{}{
 		price, amount, strings.ToUpper(currencyPair.ToSymbol(""))})
 
@@ -163,30 +178,33 @@ func (btch *BTCChina) placeorder(method, amount, price string, currencyPair Curr
 	ord := new(Order)
 	ord.OrderID = ToInt(respmap["result"])
 	ord.Currency = currencyPair
-	ord.Amount = ToFloat64(amount)
-	ord.Price = ToFloat64(price)
+	ord.Amount = dp.Decimal(amount)
+	ord.Price = dp.Decimal(price)
 
+	if dp.Err != nil {
+		return nil, dp.Err
+	}
 	return ord, nil
 }
 
-func (btch *BTCChina) LimitBuy(amount, price string, currency CurrencyPair) (*Order, error) {
-	ord, err := btch.placeorder("buyOrder2", amount, price, currency)
+func (btch *BTCChina) LimitBuy(amount, price Decimal, currency CurrencyPair) (*Order, error) {
+	ord, err := btch.placeorder("buyOrder2", amount.String(), price.String(), currency)
 	ord.Side = BUY
 	return ord, err
 }
 
-func (btch *BTCChina) LimitSell(amount, price string, currency CurrencyPair) (*Order, error) {
-	ord, err := btch.placeorder("sellOrder2", amount, price, currency)
+func (btch *BTCChina) LimitSell(amount, price Decimal, currency CurrencyPair) (*Order, error) {
+	ord, err := btch.placeorder("sellOrder2", amount.String(), price.String(), currency)
 	ord.Side = SELL
 	return ord, err
 }
 
-func (btch *BTCChina) MarketBuy(amount, price string, currency CurrencyPair) (*Order, error) {
-	panic("unimplement")
+func (btch *BTCChina) MarketBuy(amount, price Decimal, currency CurrencyPair) (*Order, error) {
+	return nil, ErrNotSupported
 }
 
-func (btch *BTCChina) MarketSell(amount, price string, currency CurrencyPair) (*Order, error) {
-	panic("unimplement")
+func (btch *BTCChina) MarketSell(amount, price Decimal, currency CurrencyPair) (*Order, error) {
+	return nil, ErrNotSupported
 }
 
 func (btch *BTCChina) CancelOrder(orderId string, currency CurrencyPair) (bool, error) {
@@ -198,13 +216,14 @@ func (btch *BTCChina) CancelOrder(orderId string, currency CurrencyPair) (bool,
 	return respmap["result"].(bool), nil
 }
 
-func (btch *BTCChina) toOrder(ordermap map[string]interface{}) Order {
+func (btch *BTCChina) toOrder(ordermap map[string]interface{}) (Order, error) {
+	var dp DecimalParser
 	ord := Order{}
 	ord.OrderID = ToInt(ordermap["id"])
-	ord.Price = ToFloat64(ordermap["price"])
-	ord.Amount = ToFloat64(ordermap["amount_original"])
-	ord.DealAmount = ToFloat64(ordermap["amount"])
-	ord.AvgPrice = ToFloat64(ordermap["price"])
+	ord.Price = dp.Decimal(ordermap["price"])
+	ord.Amount = dp.Decimal(ordermap["amount_original"])
+	ord.DealAmount = dp.Decimal(ordermap["amount"])
+	ord.AvgPrice = dp.Decimal(ordermap["price"])
 
 	switch ordermap["status"].(string) {
 	case "closed":
@@ -226,7 +245,7 @@ func (btch *BTCChina) toOrder(ordermap map[string]interface{}) Order {
 		ord.Side = BUY
 	}
 
-	return ord
+	return ord, dp.Err
 }
 
 func (btch *BTCChina) GetOneOrder(orderId string, currency CurrencyPair) (*Order, error) {
@@ -239,7 +258,10 @@ func (btch *BTCChina) GetOneOrder(orderId string, currency CurrencyPair) (*Order
 	result := respmap["result"].(map[string]interface{})
 	ordermap := result["order"].(map[string]interface{})
 
-	ord := btch.toOrder(ordermap)
+	ord, err := btch.toOrder(ordermap)
+	if err != nil {
+		return nil, err
+	}
 	return &ord, nil
 }
 
@@ -253,26 +275,34 @@ func (btch *BTCChina) GetUnfinishOrders(currency CurrencyPair) ([]Order, error)
 	orders := make([]Order, 0)
 	result := respmap["result"].(map[string]interface{})
 	ordersmap := result["order"].([]interface{})
-	for _, ord := range ordersmap {
-		orders = append(orders, btch.toOrder(ord.(map[string]interface{})))
+	for _, v := range ordersmap {
+		ord, err := btch.toOrder(v.(map[string]interface{}))
+		if err != nil {
+			return nil, err
+		}
+		orders = append(orders, ord)
 	}
 
 	return orders, nil
 }
 
 func (btch *BTCChina) GetOrderHistorys(currency CurrencyPair, currentPage, pageSize int) ([]Order, error) {
-	panic("unimplement")
+	return nil, ErrNotSupported
 }
 
 //非个人，整个交易所的交易记录
 func (btch *BTCChina) GetTrades(currencyPair CurrencyPair, since int64) ([]Trade, error) {
-	panic("unimplement")
+	return nil, ErrNotSupported
 }
 
 func (btch *BTCChina) GetExchangeName() string {
 	return "btcchina.com"
 }
 
+func (btch *BTCChina) Capabilities() Capabilities {
+	return Capabilities{}
+}
+
 func (btch *BTCChina) GetBasicAuth(sign string) string {
 	authStr := btch.accessKey + ":" + sign
 	basicAuth := "Basic " + base64.StdEncoding.EncodeToString([]byte(authStr))
@@ -301,7 +331,7 @@ func (btch *BTCChina) sendAuthorizationRequst(method string, params []interface{
 	}
 
 	if respmap["error"] != nil {
-		return nil, errors.New(string(resp))
+		return nil, btch.errorWrapper(respmap["error"], string(resp))
 	}
 
 	return respmap, nil
@@ -350,3 +380,28 @@ func (btch *BTCChina) buildPostForm(method string, paramAr []interface{}) ReqBod
 
 	return reqbody
 }
+
+func (btch *BTCChina) errorWrapper(rpcErr interface{}, originErrMsg string) ApiError {
+	var code int
+	if errmap, isok := rpcErr.(map[string]interface{}); isok {
+		code = ToInt(errmap["code"])
+	}
+
+	var errCode ApiError
+	switch code {
+	case -32003, -32004:
+		errCode = EX_ERR_INSUFFICIENT_BALANCE
+	case -32025:
+		errCode = EX_ERR_NOT_FIND_ORDER
+	case -32026:
+		errCode = EX_ERR_CANCEL_ORDER_FAIL
+	case -32017, -32018:
+		errCode = EX_ERR_INVALID_PRECISION
+	case -32065:
+		errCode = EX_ERR_INVALID_CURRENCY_PAIR
+	default:
+		errCode = API_ERR
+	}
+	errCode.OriginErrMsg = originErrMsg
+	return errCode
+}
@@ -0,0 +1,68 @@
+package btcc
+
+import (
+	"context"
+
+	. "github.com/nntaoli-project/GoEx"
+)
+
+var _ API = (*BTCChina)(nil)
+var _ APIWithContext = (*BTCChina)(nil)
+
+func (btch *BTCChina) withContext(ctx context.Context) *BTCChina {
+	c := *btch
+	c.httpClient = HttpClientWithContext(ctx, btch.httpClient)
+	return &c
+}
+
+func (btch *BTCChina) LimitBuyCtx(ctx context.Context, amount, price Decimal, currency CurrencyPair) (*Order, error) {
+	return btch.withContext(ctx).LimitBuy(amount, price, currency)
+}
+
+func (btch *BTCChina) LimitSellCtx(ctx context.Context, amount, price Decimal, currency CurrencyPair) (*Order, error) {
+	return btch.withContext(ctx).LimitSell(amount, price, currency)
+}
+
+func (btch *BTCChina) MarketBuyCtx(ctx context.Context, amount, price Decimal, currency CurrencyPair) (*Order, error) {
+	return btch.withContext(ctx).MarketBuy(amount, price, currency)
+}
+
+func (btch *BTCChina) MarketSellCtx(ctx context.Context, amount, price Decimal, currency CurrencyPair) (*Order, error) {
+	return btch.withContext(ctx).MarketSell(amount, price, currency)
+}
+
+func (btch *BTCChina) CancelOrderCtx(ctx context.Context, orderId string, currency CurrencyPair) (bool, error) {
+	return btch.withContext(ctx).CancelOrder(orderId, currency)
+}
+
+func (btch *BTCChina) GetOneOrderCtx(ctx context.Context, orderId string, currency CurrencyPair) (*Order, error) {
+	return btch.withContext(ctx).GetOneOrder(orderId, currency)
+}
+
+func (btch *BTCChina) GetUnfinishOrdersCtx(ctx context.Context, currency CurrencyPair) ([]Order, error) {
+	return btch.withContext(ctx).GetUnfinishOrders(currency)
+}
+
+func (btch *BTCChina) GetOrderHistorysCtx(ctx context.Context, currency CurrencyPair, currentPage, pageSize int) ([]Order, error) {
+	return btch.withContext(ctx).GetOrderHistorys(currency, currentPage, pageSize)
+}
+
+func (btch *BTCChina) GetAccountCtx(ctx context.Context) (*Account, error) {
+	return btch.withContext(ctx).GetAccount()
+}
+
+func (btch *BTCChina) GetTickerCtx(ctx context.Context, currency CurrencyPair) (*Ticker, error) {
+	return btch.withContext(ctx).GetTicker(currency)
+}
+
+func (btch *BTCChina) GetDepthCtx(ctx context.Context, size int, currency CurrencyPair) (*Depth, error) {
+	return btch.withContext(ctx).GetDepth(size, currency)
+}
+
+func (btch *BTCChina) GetKlineRecordsCtx(ctx context.Context, currency CurrencyPair, period, size, since int) ([]Kline, error) {
+	return btch.withContext(ctx).GetKlineRecords(currency, period, size, since)
+}
+
+func (btch *BTCChina) GetTradesCtx(ctx context.Context, currencyPair CurrencyPair, since int64) ([]Trade, error) {
+	return btch.withContext(ctx).GetTrades(currencyPair, since)
+}
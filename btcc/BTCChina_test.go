@@ -3,50 +3,93 @@ package btcc
 import (
 	"github.com/nntaoli-project/GoEx"
 	"github.com/stretchr/testify/assert"
-	"net/http"
+	"os"
 	"testing"
 )
 
-var btch = NewBTCChina(http.DefaultClient, "", "")
+var fixtures = goex.NewFixtureTransport("testdata/fixtures.json")
+var btch = NewBTCChina(fixtures.Client(), "", "")
 
 func TestBTCChina_GetTicker(t *testing.T) {
 	ticker, err := btch.GetTicker(goex.BTC_CNY)
 	assert.Nil(t, err)
-	t.Log(ticker)
+	assert.Equal(t, goex.Ticker{Last: goex.RequireDecimal("18412.03"), Buy: goex.RequireDecimal("18412.03"), Sell: goex.RequireDecimal("18420"),
+		High: goex.RequireDecimal("18700"), Low: goex.RequireDecimal("18138"), Vol: goex.RequireDecimal("21703.851"), Date: 1504686312}, *ticker)
 }
 
 func TestBTCChina_GetDepth(t *testing.T) {
 	dep, err := btch.GetDepth(2, goex.BTC_CNY)
 	assert.Nil(t, err)
-	t.Log(dep)
+	//the best ask last
+	assert.Equal(t, goex.DepthRecords{
+		{Price: goex.RequireDecimal("18421.76"), Amount: goex.RequireDecimal("0.4")},
+		{Price: goex.RequireDecimal("18420"), Amount: goex.RequireDecimal("1.2354")}}, dep.AskList)
+	assert.Equal(t, goex.DepthRecords{
+		{Price: goex.RequireDecimal("18412.03"), Amount: goex.RequireDecimal("0.5")},
+		{Price: goex.RequireDecimal("18410"), Amount: goex.RequireDecimal("2.0971")}}, dep.BidList)
 }
 
 func TestBTCChina_GetAccount(t *testing.T) {
 	acc, err := btch.GetAccount()
 	assert.Nil(t, err)
-	t.Log(acc)
+	assert.Equal(t, map[goex.Currency]goex.SubAccount{
+		goex.BTC: {Currency: goex.BTC, Amount: goex.RequireDecimal("0.0183")},
+		goex.CNY: {Currency: goex.CNY, Amount: goex.RequireDecimal("418.32"), FrozenAmount: goex.RequireDecimal("0.2")},
+		goex.LTC: {Currency: goex.LTC, Amount: goex.RequireDecimal("2")}}, acc.SubAccounts)
 }
 
 func TestBTCChina_LimitBuy(t *testing.T) {
-	ord, err := btch.LimitBuy("0.001", "200", goex.LTC_CNY)
+	ord, err := btch.LimitBuy(goex.RequireDecimal("0.001"), goex.RequireDecimal("200"), goex.LTC_CNY)
 	assert.Nil(t, err)
-	t.Log(ord)
+	assert.Equal(t, 24956079, ord.OrderID)
+	assert.Equal(t, goex.RequireDecimal("200"), ord.Price)
+	assert.Equal(t, goex.RequireDecimal("0.001"), ord.Amount)
+	assert.Equal(t, goex.TradeSide(goex.BUY), ord.Side)
+	assert.Equal(t, goex.TradeStatus(goex.ORDER_UNFINISH), ord.Status)
 }
 
 func TestBTCChina_CancelOrder(t *testing.T) {
 	r, err := btch.CancelOrder("24956079", goex.LTC_CNY)
 	assert.Nil(t, err)
-	t.Log(r)
+	assert.True(t, r)
 }
 
 func TestBTCChina_GetOneOrder(t *testing.T) {
 	order, err := btch.GetOneOrder("24956079", goex.LTC_CNY)
 	assert.Nil(t, err)
-	t.Log(order)
+	assert.Equal(t, 24956079, order.OrderID)
+	assert.Equal(t, goex.RequireDecimal("200"), order.AvgPrice)
+	assert.Equal(t, goex.TradeSide(goex.BUY), order.Side)
+	assert.Equal(t, goex.TradeStatus(goex.ORDER_CANCEL), order.Status)
 }
 
 func TestBTCChina_GetUnfinishOrders(t *testing.T) {
 	ords, err := btch.GetUnfinishOrders(goex.LTC_CNY)
 	assert.Nil(t, err)
-	t.Log(ords)
+	assert.Len(t, ords, 1)
+	assert.Equal(t, 24956112, ords[0].OrderID)
+	assert.Equal(t, goex.RequireDecimal("420"), ords[0].Price)
+	assert.Equal(t, goex.RequireDecimal("0.001"), ords[0].DealAmount)
+	assert.Equal(t, goex.TradeSide(goex.SELL), ords[0].Side)
+}
+
+func TestMain(m *testing.M) {
+	os.Exit(fixtures.Run(m.Run))
+}
+
+func TestBTCChina_errorWrapper(t *testing.T) {
+	for code, errCode := range map[int]goex.ApiError{
+		-32003: goex.EX_ERR_INSUFFICIENT_BALANCE,
+		-32004: goex.EX_ERR_INSUFFICIENT_BALANCE,
+		-32025: goex.EX_ERR_NOT_FIND_ORDER,
+		-32026: goex.EX_ERR_CANCEL_ORDER_FAIL,
+		-32017: goex.EX_ERR_INVALID_PRECISION,
+		-32065: goex.EX_ERR_INVALID_CURRENCY_PAIR,
+		-32000: goex.API_ERR,
+	} {
+		err := btch.errorWrapper(map[string]interface{}{"code": float64(code), "message": "error"}, "origin")
+		assert.True(t, errCode.Is(err), "%d", code)
+		assert.Equal(t, "origin", err.OriginErrMsg)
+	}
+	assert.True(t, goex.API_ERR.Is(btch.errorWrapper(nil, "origin")))
 }
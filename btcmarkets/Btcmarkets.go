@@ -1,10 +1,10 @@
 package btcmarkets
 
 import (
-	"errors"
 	"fmt"
 	. "github.com/nntaoli-project/GoEx"
 	"net/http"
+	"strings"
 	"time"
 )
 
@@ -23,6 +23,7 @@ type Btcmarkets struct {
 }
 
 func New(client *http.Client, accessKey, secretKey string) *Btcmarkets {
+	client = ExchangeRateLimitedClient(client, EXCHANGE_NAME, accessKey, DefaultRateLimitConfig)
 	return &Btcmarkets{accessKey, secretKey, client}
 }
 
@@ -30,7 +31,12 @@ func (btcm *Btcmarkets) GetExchangeName() string {
 	return EXCHANGE_NAME
 }
 
+func (btcm *Btcmarkets) Capabilities() Capabilities {
+	return Capabilities{}
+}
+
 func (btcm *Btcmarkets) GetTicker(currency CurrencyPair) (*Ticker, error) {
+	var dp DecimalParser
 	tickerUri := fmt.Sprintf(API_BASE_URL+TICKER_URI, currency.CurrencyA.String(), currency.CurrencyB.String())
 	//log.Println("tickerUrl:", tickerUri)
 	bodyDataMap, err := HttpGet(btcm.httpClient, tickerUri)
@@ -44,7 +50,7 @@ func (btcm *Btcmarkets) GetTicker(currency CurrencyPair) (*Ticker, error) {
 
 	if result, isok := bodyDataMap["success"].(bool); isok == true && result != true {
 		//log.Println("bodyDataMap[\"success\"]", isok, result)
-		return nil, errors.New("err")
+		return nil, btcm.errorWrapper(bodyDataMap)
 	}
 
 	var tickerMap map[string]interface{} = bodyDataMap
@@ -52,12 +58,15 @@ func (btcm *Btcmarkets) GetTicker(currency CurrencyPair) (*Ticker, error) {
 
 	//fmt.Println(bodyDataMap)
 	ticker.Date = uint64(timestamp)
-	ticker.Last, _ = tickerMap["lastPrice"].(float64)
+	ticker.Last = dp.Decimal(tickerMap["lastPrice"])
 
-	ticker.Buy, _ = tickerMap["bestBid"].(float64)
-	ticker.Sell, _ = tickerMap["bestAsk"].(float64)
-	ticker.Vol, _ = tickerMap["volume24h"].(float64)
+	ticker.Buy = dp.Decimal(tickerMap["bestBid"])
+	ticker.Sell = dp.Decimal(tickerMap["bestAsk"])
+	ticker.Vol = dp.Decimal(tickerMap["volume24h"])
 	//log.Println("Btcmarkets", currency, "ticker:", ticker)
+	if dp.Err != nil {
+		return nil, dp.Err
+	}
 	return &ticker, nil
 }
 func (btcm *Btcmarkets) GetTickers(currency CurrencyPair) (*Ticker, error) {
@@ -69,49 +78,69 @@ func (btcm *Btcmarkets) GetTickerInBuf(currency CurrencyPair) (*Ticker, error) {
 }
 
 func (btcm *Btcmarkets) GetDepth(size int, currency CurrencyPair) (*Depth, error) {
-	panic("not implement")
+	return nil, ErrNotSupported
 }
 
-func (btcm *Btcmarkets) LimitBuy(amount, price string, currency CurrencyPair) (*Order, error) {
-	panic("not implements")
+func (btcm *Btcmarkets) LimitBuy(amount, price Decimal, currency CurrencyPair) (*Order, error) {
+	return nil, ErrNotSupported
 }
 
-func (btcm *Btcmarkets) LimitSell(amount, price string, currency CurrencyPair) (*Order, error) {
-	panic("not implements")
+func (btcm *Btcmarkets) LimitSell(amount, price Decimal, currency CurrencyPair) (*Order, error) {
+	return nil, ErrNotSupported
 }
 
-func (btcm *Btcmarkets) MarketBuy(amount, price string, currency CurrencyPair) (*Order, error) {
-	panic("not implements")
+func (btcm *Btcmarkets) MarketBuy(amount, price Decimal, currency CurrencyPair) (*Order, error) {
+	return nil, ErrNotSupported
 }
 
-func (btcm *Btcmarkets) MarketSell(amount, price string, currency CurrencyPair) (*Order, error) {
-	panic("not implements")
+func (btcm *Btcmarkets) MarketSell(amount, price Decimal, currency CurrencyPair) (*Order, error) {
+	return nil, ErrNotSupported
 }
 
 func (btcm *Btcmarkets) CancelOrder(orderId string, currency CurrencyPair) (bool, error) {
-	panic("not implements")
+	return false, ErrNotSupported
 }
 
 func (btcm *Btcmarkets) GetOneOrder(orderId string, currency CurrencyPair) (*Order, error) {
-	panic("not implements")
+	return nil, ErrNotSupported
 }
 func (btcm *Btcmarkets) GetUnfinishOrders(currency CurrencyPair) ([]Order, error) {
-	panic("not implements")
+	return nil, ErrNotSupported
 }
 
 func (btcm *Btcmarkets) GetOrderHistorys(currency CurrencyPair, currentPage, pageSize int) ([]Order, error) {
-	panic("not implements")
+	return nil, ErrNotSupported
 }
 
 func (btcm *Btcmarkets) GetAccount() (*Account, error) {
-	panic("not implements")
+	return nil, ErrNotSupported
 }
 
 func (btcm *Btcmarkets) GetKlineRecords(currency CurrencyPair, period, size, since int) ([]Kline, error) {
-	panic("not implements")
+	return nil, ErrNotSupported
 }
 
 //非个人，整个交易所的交易记录
 func (btcm *Btcmarkets) GetTrades(currencyPair CurrencyPair, since int64) ([]Trade, error) {
-	panic("not implements")
+	return nil, ErrNotSupported
+}
+
+//btcmarkets reports failures as {"success":false,"errorCode":3,"errorMessage":"Invalid argument."}
+func (btcm *Btcmarkets) errorWrapper(resp map[string]interface{}) ApiError {
+	message := fmt.Sprint(resp["errorMessage"])
+	msg := strings.ToLower(message)
+
+	var errCode ApiError
+	switch {
+	case strings.Contains(msg, "instrument"), strings.Contains(msg, "market"):
+		errCode = EX_ERR_INVALID_CURRENCY_PAIR
+	case strings.Contains(msg, "too many requests"), strings.Contains(msg, "rate limit"):
+		errCode = EX_ERR_API_LIMIT
+	case strings.Contains(msg, "maintenance"):
+		errCode = EX_ERR_MAINTENANCE
+	default:
+		errCode = API_ERR
+	}
+	errCode.OriginErrMsg = fmt.Sprintf("[%v]%s", resp["errorCode"], message)
+	return errCode
 }
@@ -0,0 +1,68 @@
+package btcmarkets
+
+import (
+	"context"
+
+	. "github.com/nntaoli-project/GoEx"
+)
+
+var _ API = (*Btcmarkets)(nil)
+var _ APIWithContext = (*Btcmarkets)(nil)
+
+func (btcm *Btcmarkets) withContext(ctx context.Context) *Btcmarkets {
+	c := *btcm
+	c.httpClient = HttpClientWithContext(ctx, btcm.httpClient)
+	return &c
+}
+
+func (btcm *Btcmarkets) LimitBuyCtx(ctx context.Context, amount, price Decimal, currency CurrencyPair) (*Order, error) {
+	return btcm.withContext(ctx).LimitBuy(amount, price, currency)
+}
+
+func (btcm *Btcmarkets) LimitSellCtx(ctx context.Context, amount, price Decimal, currency CurrencyPair) (*Order, error) {
+	return btcm.withContext(ctx).LimitSell(amount, price, currency)
+}
+
+func (btcm *Btcmarkets) MarketBuyCtx(ctx context.Context, amount, price Decimal, currency CurrencyPair) (*Order, error) {
+	return btcm.withContext(ctx).MarketBuy(amount, price, currency)
+}
+
+func (btcm *Btcmarkets) MarketSellCtx(ctx context.Context, amount, price Decimal, currency CurrencyPair) (*Order, error) {
+	return btcm.withContext(ctx).MarketSell(amount, price, currency)
+}
+
+func (btcm *Btcmarkets) CancelOrderCtx(ctx context.Context, orderId string, currency CurrencyPair) (bool, error) {
+	return btcm.withContext(ctx).CancelOrder(orderId, currency)
+}
+
+func (btcm *Btcmarkets) GetOneOrderCtx(ctx context.Context, orderId string, currency CurrencyPair) (*Order, error) {
+	return btcm.withContext(ctx).GetOneOrder(orderId, currency)
+}
+
+func (btcm *Btcmarkets) GetUnfinishOrdersCtx(ctx context.Context, currency CurrencyPair) ([]Order, error) {
+	return btcm.withContext(ctx).GetUnfinishOrders(currency)
+}
+
+func (btcm *Btcmarkets) GetOrderHistorysCtx(ctx context.Context, currency CurrencyPair, currentPage, pageSize int) ([]Order, error) {
+	return btcm.withContext(ctx).GetOrderHistorys(currency, currentPage, pageSize)
+}
+
+func (btcm *Btcmarkets) GetAccountCtx(ctx context.Context) (*Account, error) {
+	return btcm.withContext(ctx).GetAccount()
+}
+
+func (btcm *Btcmarkets) GetTickerCtx(ctx context.Context, currency CurrencyPair) (*Ticker, error) {
+	return btcm.withContext(ctx).GetTicker(currency)
+}
+
+func (btcm *Btcmarkets) GetDepthCtx(ctx context.Context, size int, currency CurrencyPair) (*Depth, error) {
+	return btcm.withContext(ctx).GetDepth(size, currency)
+}
+
+func (btcm *Btcmarkets) GetKlineRecordsCtx(ctx context.Context, currency CurrencyPair, period, size, since int) ([]Kline, error) {
+	return btcm.withContext(ctx).GetKlineRecords(currency, period, size, since)
+}
+
+func (btcm *Btcmarkets) GetTradesCtx(ctx context.Context, currencyPair CurrencyPair, since int64) ([]Trade, error) {
+	return btcm.withContext(ctx).GetTrades(currencyPair, since)
+}
@@ -2,16 +2,38 @@ package btcmarkets
 
 import (
 	"github.com/nntaoli-project/GoEx"
-	"net/http"
+	"github.com/stretchr/testify/assert"
+	"os"
 	"testing"
 )
 
-var btcm = New(http.DefaultClient, "", "")
+var fixtures = goex.NewFixtureTransport("testdata/fixtures.json")
+var btcm = New(fixtures.Client(), "", "")
 
 func TestBtcm_GetTicker(t *testing.T) {
 	AUD := goex.NewCurrency("AUD", "")
 	BTC_AUD := goex.NewCurrencyPair(goex.BTC, AUD)
 	ticker, err := btcm.GetTicker(BTC_AUD)
-	t.Log("err=>", err)
-	t.Log("ticker=>", ticker)
+	assert.Nil(t, err)
+	assert.Equal(t, goex.RequireDecimal("8255"), ticker.Last)
+	assert.Equal(t, goex.RequireDecimal("8230.01"), ticker.Buy)
+	assert.Equal(t, goex.RequireDecimal("8259.95"), ticker.Sell)
+	assert.Equal(t, goex.RequireDecimal("203.2105"), ticker.Vol)
+}
+
+func TestMain(m *testing.M) {
+	os.Exit(fixtures.Run(m.Run))
+}
+
+func TestBtcmarkets_errorWrapper(t *testing.T) {
+	for message, errCode := range map[string]goex.ApiError{
+		"Instrument not found":  goex.EX_ERR_INVALID_CURRENCY_PAIR,
+		"Too many requests":     goex.EX_ERR_API_LIMIT,
+		"Scheduled maintenance": goex.EX_ERR_MAINTENANCE,
+		"Invalid argument.":     goex.API_ERR,
+	} {
+		err := btcm.errorWrapper(map[string]interface{}{"success": false, "errorCode": float64(3), "errorMessage": message})
+		assert.True(t, errCode.Is(err), message)
+		assert.Equal(t, "[3]"+message, err.OriginErrMsg)
+	}
 }
@@ -26,8 +26,10 @@ type APIBuilder struct {
 	apiKey      string
 	secretkey   string
 	clientId    string
+	noRateLimit bool
 }
 
+
 func NewAPIBuilder() (builder *APIBuilder) {
 	_client := http.DefaultClient
 	transport := &http.Transport{
@@ -71,42 +73,100 @@ func (builder *APIBuilder) HttpTimeout(timeout time.Duration) (_builder *APIBuil
 	return builder
 }
 
+//RateLimit(false) builds apis that send their requests as fast as they are made,
+//otherwise they wait for the limits of their exchange shared by every api of the same api key
+func (builder *APIBuilder) RateLimit(enable bool) (_builder *APIBuilder) {
+	builder.noRateLimit = !enable
+	return builder
+}
+
+//httpClient is the client the apis are built with, the constructors add the rate limits unless it is unlimited
+func (builder *APIBuilder) httpClient() *http.Client {
+	if builder.noRateLimit {
+		return UnlimitedClient(builder.client)
+	}
+	return builder.client
+}
+
+//Build returns the rest api of exName. ClientID is the customer id for bitstamp.net, the spot account id for
+//huobi.pro and the passphrase of the api key for okex.com, whose client order ids and fills need it.
 func (builder *APIBuilder) Build(exName string) (api API) {
 	var _api API
+	client := builder.httpClient()
 	switch exName {
 	case "okcoin.cn":
-		_api = okcoin.New(builder.client, builder.apiKey, builder.secretkey)
+		_api = okcoin.New(client, builder.apiKey, builder.secretkey)
 	case "huobi.com":
-		_api = huobi.New(builder.client, builder.apiKey, builder.secretkey)
+		_api = huobi.New(client, builder.apiKey, builder.secretkey)
 	case "chbtc.com":
-		_api = chbtc.New(builder.client, builder.apiKey, builder.secretkey)
+		_api = chbtc.New(client, builder.apiKey, builder.secretkey)
 	case "yunbi.com":
-		_api = yunbi.New(builder.client, builder.apiKey, builder.secretkey)
+		_api = yunbi.New(client, builder.apiKey, builder.secretkey)
 	case "poloniex.com":
-		_api = poloniex.New(builder.client, builder.apiKey, builder.secretkey)
+		_api = poloniex.New(client, builder.apiKey, builder.secretkey)
 	case "okcoin.com":
-		_api = okcoin.NewCOM(builder.client, builder.apiKey, builder.secretkey)
+		_api = okcoin.NewCOM(client, builder.apiKey, builder.secretkey)
 	case "coincheck.com":
-		_api = coincheck.New(builder.client, builder.apiKey, builder.secretkey)
+		_api = coincheck.New(client, builder.apiKey, builder.secretkey)
 	case "zaif.jp":
-		_api = zaif.New(builder.client, builder.apiKey, builder.secretkey)
+		_api = zaif.New(client, builder.apiKey, builder.secretkey)
 	case "bitstamp.net":
-		_api = bitstamp.NewBitstamp(builder.client, builder.apiKey, builder.secretkey, builder.clientId)
+		_api = bitstamp.NewBitstamp(client, builder.apiKey, builder.secretkey, builder.clientId)
 	case "huobi.pro":
-		_api = huobi.NewHuobiPro(builder.client, builder.apiKey, builder.secretkey, builder.clientId)
+		_api = huobi.NewHuobiPro(client, builder.apiKey, builder.secretkey, builder.clientId)
 	case "okex.com":
-		_api = okcoin.NewOKExSpot(builder.client, builder.apiKey, builder.secretkey)
+		_api = okcoin.NewOKExSpotV3(client, builder.apiKey, builder.secretkey, builder.clientId)
 	case "bitfinex.com":
-		_api = bitfinex.New(builder.client, builder.apiKey, builder.secretkey)
+		_api = bitfinex.New(client, builder.apiKey, builder.secretkey)
 	case "kraken.com":
-		_api = kraken.New(builder.client, builder.apiKey, builder.secretkey)
+		_api = kraken.New(client, builder.apiKey, builder.secretkey)
 	case "binance.com":
-		_api = binance.New(builder.client, builder.apiKey, builder.secretkey)
+		_api = binance.New(client, builder.apiKey, builder.secretkey)
 	case "btcbox.co.jp":
-		_api = btcbox.New(builder.client, builder.apiKey, builder.secretkey)
+		_api = btcbox.New(client, builder.apiKey, builder.secretkey)
+	default:
+		panic("exchange name error.")
+
+	}
+	return _api
+}
+
+//BuildStream returns the websocket market data api of exName
+func (builder *APIBuilder) BuildStream(exName string) (api StreamAPI) {
+	var _api StreamAPI
+	switch exName {
+	case "binance.com":
+		_api = binance.NewBinanceWs()
+	case "bitfinex.com":
+		_api = bitfinex.NewBitfinexWs()
+	case "huobi.pro":
+		_api = huobi.NewHuobiProWs()
+	case "okex.com":
+		_api = okcoin.NewOKExSpotWs()
 	default:
 		panic("exchange name error.")
+	}
+	return _api
+}
 
+//BuildUserStream returns the websocket of exName pushing the account's own orders and balances,
+//authenticated with the api key and secret key; for huobi.pro ClientID is the spot account id
+func (builder *APIBuilder) BuildUserStream(exName string) (api UserStreamAPI) {
+	var _api UserStreamAPI
+	switch exName {
+	case "binance.com":
+		_api = binance.NewBinanceUserWs(builder.httpClient(), builder.apiKey, builder.secretkey)
+	case "bitfinex.com":
+		_api = bitfinex.NewBitfinexUserWs(builder.apiKey, builder.secretkey)
+	case "huobi.pro":
+		_api = huobi.NewHuobiProUserWs(builder.apiKey, builder.secretkey, builder.clientId)
+	default:
+		panic("exchange name error.")
 	}
 	return _api
 }
+
+//Capabilities reports what the named exchange adapter supports, without sending any request.
+func (builder *APIBuilder) Capabilities(exName string) Capabilities {
+	return builder.Build(exName).Capabilities()
+}
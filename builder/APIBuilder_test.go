@@ -1,6 +1,7 @@
 package builder
 
 import (
+	. "github.com/nntaoli-project/GoEx"
 	"github.com/stretchr/testify/assert"
 	"testing"
 )
@@ -18,3 +19,35 @@ func TestAPIBuilder_Build(t *testing.T) {
 	assert.Equal(t, builder.APIKey("").APISecretkey("").Build("zaif.jp").GetExchangeName(), "zaif.jp")
 	assert.Equal(t, builder.APIKey("").APISecretkey("").Build("huobi.pro").GetExchangeName(), "huobi.pro")
 }
+
+func TestAPIBuilder_Capabilities(t *testing.T) {
+	assert.True(t, builder.Capabilities("okcoin.cn").Kline)
+	assert.True(t, builder.Capabilities("poloniex.com").Margin)
+	assert.False(t, builder.Capabilities("bitstamp.net").MarketOrder)
+	assert.False(t, builder.Capabilities("zaif.jp").Kline)
+
+	_, err := builder.Build("zaif.jp").GetKlineRecords(BTC_JPY, KLINE_PERIOD_1DAY, 10, 0)
+	assert.True(t, err == ErrNotSupported)
+}
+
+func TestAPIBuilder_BuildStream(t *testing.T) {
+	assert.Equal(t, "binance.com", builder.BuildStream("binance.com").GetExchangeName())
+	assert.Equal(t, "bitfinex.com", builder.BuildStream("bitfinex.com").GetExchangeName())
+	assert.Equal(t, "huobi.pro", builder.BuildStream("huobi.pro").GetExchangeName())
+	assert.Equal(t, "okex.com", builder.BuildStream("okex.com").GetExchangeName())
+}
+
+func TestAPIBuilder_BuildUserStream(t *testing.T) {
+	assert.Equal(t, "binance.com", builder.BuildUserStream("binance.com").GetExchangeName())
+	assert.Equal(t, "bitfinex.com", builder.BuildUserStream("bitfinex.com").GetExchangeName())
+	assert.Equal(t, "huobi.pro", builder.BuildUserStream("huobi.pro").GetExchangeName())
+}
+
+func TestAPIBuilder_RateLimit(t *testing.T) {
+	limited := NewAPIBuilder()
+	assert.True(t, limited.client == limited.httpClient())
+
+	//the constructors keep an unlimited client as is
+	unlimited := NewAPIBuilder().RateLimit(false).httpClient()
+	assert.True(t, unlimited == ExchangeRateLimitedClient(unlimited, "binance.com", "key1", DefaultRateLimitConfig))
+}
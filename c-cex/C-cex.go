@@ -1,6 +1,7 @@
 package c_cex
 
 import (
+	"fmt"
 	. "github.com/nntaoli-project/GoEx"
 	//"log"
 	"net/http"
@@ -23,6 +24,7 @@ type C_cex struct {
 }
 
 func New(client *http.Client, accessKey, secretKey string) *C_cex {
+	client = ExchangeRateLimitedClient(client, EXCHANGE_NAME, accessKey, DefaultRateLimitConfig)
 	return &C_cex{accessKey, secretKey, client}
 }
 
@@ -30,7 +32,12 @@ func (ccex *C_cex) GetExchangeName() string {
 	return EXCHANGE_NAME
 }
 
+func (ccex *C_cex) Capabilities() Capabilities {
+	return Capabilities{}
+}
+
 func (ccex *C_cex) GetTicker(currency CurrencyPair) (*Ticker, error) {
+	var dp DecimalParser
 	currency = ccex.adaptCurrencyPair(currency)
 
 	tickerUri := API_BASE_URL + TICKER_URI + strings.ToLower(currency.ToSymbol("-")) + ".json"
@@ -44,16 +51,22 @@ func (ccex *C_cex) GetTicker(currency CurrencyPair) (*Ticker, error) {
 		return nil, err
 	}
 
-	tickerMap := bodyDataMap["ticker"].(map[string]interface{})
+	tickerMap, isok := bodyDataMap["ticker"].(map[string]interface{})
+	if !isok {
+		return nil, ccex.errorWrapper(bodyDataMap)
+	}
 	var ticker Ticker
 
 	//fmt.Println(bodyDataMap)
 	ticker.Date = uint64(timestamp)
-	ticker.Last, _ = tickerMap["lastprice"].(float64)
-	ticker.Buy, _ = tickerMap["buy"].(float64)
-	ticker.Sell, _ = tickerMap["sell"].(float64)
+	ticker.Last = dp.Decimal(tickerMap["lastprice"])
+	ticker.Buy = dp.Decimal(tickerMap["buy"])
+	ticker.Sell = dp.Decimal(tickerMap["sell"])
 	//ticker.Vol, _ = tickerMap["Volume"].(float64)
 	//log.Println("C_cex", currency, "ticker:", ticker)
+	if dp.Err != nil {
+		return nil, dp.Err
+	}
 	return &ticker, nil
 }
 func (ccex *C_cex) GetTickers(currency CurrencyPair) (*Ticker, error) {
@@ -65,7 +78,7 @@ func (ccex *C_cex) GetTickerInBuf(currency CurrencyPair) (*Ticker, error) {
 }
 
 func (ccex *C_cex) GetDepth(size int, currency CurrencyPair) (*Depth, error) {
-	panic("not implement")
+	return nil, ErrNotSupported
 }
 func (ccex *C_cex) adaptCurrencyPair(pair CurrencyPair) CurrencyPair {
 	var currencyA Currency
@@ -86,46 +99,66 @@ func (ccex *C_cex) adaptCurrencyPair(pair CurrencyPair) CurrencyPair {
 	return NewCurrencyPair(currencyA, currencyB)
 }
 
-func (ccex *C_cex) LimitBuy(amount, price string, currency CurrencyPair) (*Order, error) {
-	panic("not implements")
+func (ccex *C_cex) LimitBuy(amount, price Decimal, currency CurrencyPair) (*Order, error) {
+	return nil, ErrNotSupported
 }
 
-func (ccex *C_cex) LimitSell(amount, price string, currency CurrencyPair) (*Order, error) {
-	panic("not implements")
+func (ccex *C_cex) LimitSell(amount, price Decimal, currency CurrencyPair) (*Order, error) {
+	return nil, ErrNotSupported
 }
 
-func (ccex *C_cex) MarketBuy(amount, price string, currency CurrencyPair) (*Order, error) {
-	panic("not implements")
+func (ccex *C_cex) MarketBuy(amount, price Decimal, currency CurrencyPair) (*Order, error) {
+	return nil, ErrNotSupported
 }
 
-func (ccex *C_cex) MarketSell(amount, price string, currency CurrencyPair) (*Order, error) {
-	panic("not implements")
+func (ccex *C_cex) MarketSell(amount, price Decimal, currency CurrencyPair) (*Order, error) {
+	return nil, ErrNotSupported
 }
 
 func (ccex *C_cex) CancelOrder(orderId string, currency CurrencyPair) (bool, error) {
-	panic("not implements")
+	return false, ErrNotSupported
 }
 
 func (ccex *C_cex) GetOneOrder(orderId string, currency CurrencyPair) (*Order, error) {
-	panic("not implements")
+	return nil, ErrNotSupported
 }
 func (ccex *C_cex) GetUnfinishOrders(currency CurrencyPair) ([]Order, error) {
-	panic("not implements")
+	return nil, ErrNotSupported
 }
 
 func (ccex *C_cex) GetOrderHistorys(currency CurrencyPair, currentPage, pageSize int) ([]Order, error) {
-	panic("not implements")
+	return nil, ErrNotSupported
 }
 
 func (ccex *C_cex) GetAccount() (*Account, error) {
-	panic("not implements")
+	return nil, ErrNotSupported
 }
 
 func (ccex *C_cex) GetKlineRecords(currency CurrencyPair, period, size, since int) ([]Kline, error) {
-	panic("not implements")
+	return nil, ErrNotSupported
 }
 
 //非个人，整个交易所的交易记录
 func (ccex *C_cex) GetTrades(currencyPair CurrencyPair, since int64) ([]Trade, error) {
-	panic("not implements")
+	return nil, ErrNotSupported
+}
+
+//c-cex answers an unknown pair with {"error":"..."} and no ticker
+func (ccex *C_cex) errorWrapper(resp map[string]interface{}) ApiError {
+	var errCode ApiError
+	errmsg, isok := resp["error"].(string)
+	msg := strings.ToLower(errmsg)
+	switch {
+	case !isok:
+		errCode = API_ERR
+		errmsg = fmt.Sprintf("no ticker in %v", resp)
+	case strings.Contains(msg, "pair"), strings.Contains(msg, "market"):
+		errCode = EX_ERR_INVALID_CURRENCY_PAIR
+	case strings.Contains(msg, "maintenance"):
+		errCode = EX_ERR_MAINTENANCE
+	default:
+		errCode = API_ERR
+	}
+	errCode.OriginErrMsg = errmsg
+	return errCode
 }
@@ -0,0 +1,68 @@
+package c_cex
+
+import (
+	"context"
+
+	. "github.com/nntaoli-project/GoEx"
+)
+
+var _ API = (*C_cex)(nil)
+var _ APIWithContext = (*C_cex)(nil)
+
+func (ccex *C_cex) withContext(ctx context.Context) *C_cex {
+	c := *ccex
+	c.httpClient = HttpClientWithContext(ctx, ccex.httpClient)
+	return &c
+}
+
+func (ccex *C_cex) LimitBuyCtx(ctx context.Context, amount, price Decimal, currency CurrencyPair) (*Order, error) {
+	return ccex.withContext(ctx).LimitBuy(amount, price, currency)
+}
+
+func (ccex *C_cex) LimitSellCtx(ctx context.Context, amount, price Decimal, currency CurrencyPair) (*Order, error) {
+	return ccex.withContext(ctx).LimitSell(amount, price, currency)
+}
+
+func (ccex *C_cex) MarketBuyCtx(ctx context.Context, amount, price Decimal, currency CurrencyPair) (*Order, error) {
+	return ccex.withContext(ctx).MarketBuy(amount, price, currency)
+}
+
+func (ccex *C_cex) MarketSellCtx(ctx context.Context, amount, price Decimal, currency CurrencyPair) (*Order, error) {
+	return ccex.withContext(ctx).MarketSell(amount, price, currency)
+}
+
+func (ccex *C_cex) CancelOrderCtx(ctx context.Context, orderId string, currency CurrencyPair) (bool, error) {
+	return ccex.withContext(ctx).CancelOrder(orderId, currency)
+}
+
+func (ccex *C_cex) GetOneOrderCtx(ctx context.Context, orderId string, currency CurrencyPair) (*Order, error) {
+	return ccex.withContext(ctx).GetOneOrder(orderId, currency)
+}
+
+func (ccex *C_cex) GetUnfinishOrdersCtx(ctx context.Context, currency CurrencyPair) ([]Order, error) {
+	return ccex.withContext(ctx).GetUnfinishOrders(currency)
+}
+
+func (ccex *C_cex) GetOrderHistorysCtx(ctx context.Context, currency CurrencyPair, currentPage, pageSize int) ([]Order, error) {
+	return ccex.withContext(ctx).GetOrderHistorys(currency, currentPage, pageSize)
+}
+
+func (ccex *C_cex) GetAccountCtx(ctx context.Context) (*Account, error) {
+	return ccex.withContext(ctx).GetAccount()
+}
+
+func (ccex *C_cex) GetTickerCtx(ctx context.Context, currency CurrencyPair) (*Ticker, error) {
+	return ccex.withContext(ctx).GetTicker(currency)
+}
+
+func (ccex *C_cex) GetDepthCtx(ctx context.Context, size int, currency CurrencyPair) (*Depth, error) {
+	return ccex.withContext(ctx).GetDepth(size, currency)
+}
+
+func (ccex *C_cex) GetKlineRecordsCtx(ctx context.Context, currency CurrencyPair, period, size, since int) ([]Kline, error) {
+	return ccex.withContext(ctx).GetKlineRecords(currency, period, size, since)
+}
+
+func (ccex *C_cex) GetTradesCtx(ctx context.Context, currencyPair CurrencyPair, since int64) ([]Trade, error) {
+	return ccex.withContext(ctx).GetTrades(currencyPair, since)
+}
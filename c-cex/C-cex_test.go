@@ -2,14 +2,35 @@ package c_cex
 
 import (
 	"github.com/nntaoli-project/GoEx"
-	"net/http"
+	"github.com/stretchr/testify/assert"
+	"os"
 	"testing"
 )
 
-var ccex = New(http.DefaultClient, "", "")
+var fixtures = goex.NewFixtureTransport("testdata/fixtures.json")
+var ccex = New(fixtures.Client(), "", "")
 
 func TestCcex_GetTicker(t *testing.T) {
 	ticker, err := ccex.GetTicker(goex.BTC_USD)
-	t.Log("err=>", err)
-	t.Log("ticker=>", ticker)
+	assert.Nil(t, err)
+	assert.Equal(t, goex.RequireDecimal("1"), ticker.Last)
+	assert.Equal(t, goex.RequireDecimal("1"), ticker.Buy)
+	assert.Equal(t, goex.RequireDecimal("1"), ticker.Sell)
+}
+
+func TestMain(m *testing.M) {
+	os.Exit(fixtures.Run(m.Run))
+}
+
+func TestC_cex_errorWrapper(t *testing.T) {
+	for message, errCode := range map[string]goex.ApiError{
+		"Invalid pair":           goex.EX_ERR_INVALID_CURRENCY_PAIR,
+		"Site under maintenance": goex.EX_ERR_MAINTENANCE,
+		"Unknown error":          goex.API_ERR,
+	} {
+		err := ccex.errorWrapper(map[string]interface{}{"error": message})
+		assert.True(t, errCode.Is(err), message)
+		assert.Equal(t, message, err.OriginErrMsg)
+	}
+	assert.True(t, goex.API_ERR.Is(ccex.errorWrapper(map[string]interface{}{})))
 }
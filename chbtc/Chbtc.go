@@ -2,7 +2,6 @@ package chbtc
 
 import (
 	"encoding/json"
-	"errors"
 	"fmt"
 	. "github.com/nntaoli-project/GoEx"
 	"log"
@@ -17,6 +16,8 @@ const (
 	MARKET_URL = "http://api.chbtc.com/data/v1/"
 	TICKER_API = "ticker?currency=%s"
 	DEPTH_API  = "depth?currency=%s&size=%d"
+	KLINE_API  = "kline?currency=%s&type=%s&size=%d"
+	TRADES_API = "trades?currency=%s"
 
 	TRADE_URL                 = "https://trade.chbtc.com/api/"
 	GET_ACCOUNT_API           = "getAccountInfo"
@@ -26,6 +27,7 @@ const (
 	PLACE_ORDER_API           = "order"
 	WITHDRAW_API              = "withdraw"
 	CANCELWITHDRAW_API        = "cancelWithdraw"
+	GET_WITHDRAW_RECORD_API   = "getWithdrawRecord"
 )
 
 type Chbtc struct {
@@ -35,6 +37,7 @@ type Chbtc struct {
 }
 
 func New(httpClient *http.Client, accessKey, secretKey string) *Chbtc {
+	httpClient = ExchangeRateLimitedClient(httpClient, "chbtc.com", accessKey, DefaultRateLimitConfig)
 	return &Chbtc{httpClient, accessKey, secretKey}
 }
 
@@ -42,7 +45,12 @@ func (chbtc *Chbtc) GetExchangeName() string {
 	return "chbtc.com"
 }
 
+func (chbtc *Chbtc) Capabilities() Capabilities {
+	return Capabilities{Kline: true, Trades: true, Withdraw: true}
+}
+
 func (chbtc *Chbtc) GetTicker(currency CurrencyPair) (*Ticker, error) {
+	var dp DecimalParser
 	resp, err := HttpGet(chbtc.httpClient, MARKET_URL+fmt.Sprintf(TICKER_API, strings.ToLower(currency.ToSymbol("_"))))
 	if err != nil {
 		return nil, err
@@ -52,17 +60,21 @@ func (chbtc *Chbtc) GetTicker(currency CurrencyPair) (*Ticker, error) {
 
 	ticker := new(Ticker)
 	ticker.Date, _ = strconv.ParseUint(resp["date"].(string), 10, 64)
-	ticker.Buy, _ = strconv.ParseFloat(tickermap["buy"].(string), 64)
-	ticker.Sell, _ = strconv.ParseFloat(tickermap["sell"].(string), 64)
-	ticker.Last, _ = strconv.ParseFloat(tickermap["last"].(string), 64)
-	ticker.High, _ = strconv.ParseFloat(tickermap["high"].(string), 64)
-	ticker.Low, _ = strconv.ParseFloat(tickermap["low"].(string), 64)
-	ticker.Vol, _ = strconv.ParseFloat(tickermap["vol"].(string), 64)
-
+	ticker.Buy = dp.Decimal(tickermap["buy"])
+	ticker.Sell = dp.Decimal(tickermap["sell"])
+	ticker.Last = dp.Decimal(tickermap["last"])
+	ticker.High = dp.Decimal(tickermap["high"])
+	ticker.Low = dp.Decimal(tickermap["low"])
+	ticker.Vol = dp.Decimal(tickermap["vol"])
+
+	if dp.Err != nil {
+		return nil, dp.Err
+	}
 	return ticker, nil
 }
 
 func (chbtc *Chbtc) GetDepth(size int, currency CurrencyPair) (*Depth, error) {
+	var dp DecimalParser
 	resp, err := HttpGet(chbtc.httpClient, MARKET_URL+fmt.Sprintf(DEPTH_API, currency.ToSymbol("_"), size))
 	if err != nil {
 		return nil, err
@@ -81,8 +93,8 @@ func (chbtc *Chbtc) GetDepth(size int, currency CurrencyPair) (*Depth, error) {
 	for _, e := range bids {
 		var r DepthRecord
 		ee := e.([]interface{})
-		r.Amount = ee[1].(float64)
-		r.Price = ee[0].(float64)
+		r.Amount = dp.Decimal(ee[1])
+		r.Price = dp.Price(ee[0])
 
 		depth.BidList = append(depth.BidList, r)
 	}
@@ -90,12 +102,15 @@ func (chbtc *Chbtc) GetDepth(size int, currency CurrencyPair) (*Depth, error) {
 	for _, e := range asks {
 		var r DepthRecord
 		ee := e.([]interface{})
-		r.Amount = ee[1].(float64)
-		r.Price = ee[0].(float64)
+		r.Amount = dp.Decimal(ee[1])
+		r.Price = dp.Price(ee[0])
 
 		depth.AskList = append(depth.AskList, r)
 	}
 
+	if dp.Err != nil {
+		return nil, dp.Err
+	}
 	return depth, nil
 }
 
@@ -117,6 +132,7 @@ func (chbtc *Chbtc) buildPostForm(postForm *url.Values) error {
 }
 
 func (chbtc *Chbtc) GetAccount() (*Account, error) {
+	var dp DecimalParser
 	params := url.Values{}
 	params.Set("method", "getAccountInfo")
 	chbtc.buildPostForm(&params)
@@ -135,7 +151,7 @@ func (chbtc *Chbtc) GetAccount() (*Account, error) {
 
 	//log.Println(respmap)
 	if respmap["code"] != nil && respmap["code"].(float64) != 1000 {
-		return nil, errors.New(string(resp))
+		return nil, chbtc.errorWrapper(ToInt(respmap["code"]), string(resp))
 	}
 
 	acc := new(Account)
@@ -152,11 +168,11 @@ func (chbtc *Chbtc) GetAccount() (*Account, error) {
 
 	for t, v := range balancemap {
 		vv := v.(map[string]interface{})
-		frozen := frozenmap["CNY"].(map[string]interface{})
+		frozen := frozenmap[t].(map[string]interface{})
 		subAcc := SubAccount{}
-		subAcc.Amount = ToFloat64(vv["amount"])
-		subAcc.ForzenAmount = ToFloat64(frozen["amount"])
-		subAcc.LoanAmount = ToFloat64(p2pmap[fmt.Sprintf("in%s", t)])
+		subAcc.Amount = dp.Decimal(vv["amount"])
+		subAcc.FrozenAmount = dp.Decimal(frozen["amount"])
+		subAcc.LoanAmount = dp.Decimal(p2pmap[fmt.Sprintf("in%s", t)])
 
 		switch t {
 		case "CNY":
@@ -187,10 +203,14 @@ func (chbtc *Chbtc) GetAccount() (*Account, error) {
 	//log.Println(string(resp))
 	//log.Println(acc)
 
+	if dp.Err != nil {
+		return nil, dp.Err
+	}
 	return acc, nil
 }
 
 func (chbtc *Chbtc) placeOrder(amount, price string, currency CurrencyPair, tradeType int) (*Order, error) {
+	var dp DecimalParser
 	params := url.Values{}
 	params.Set("method", "order")
 	params.Set("price", price)
@@ -217,14 +237,14 @@ func (chbtc *Chbtc) placeOrder(amount, price string, currency CurrencyPair, trad
 	code := respmap["code"].(float64)
 	if code != 1000 {
 		log.Println(string(resp))
-		return nil, errors.New(fmt.Sprintf("%.0f", code))
+		return nil, chbtc.errorWrapper(int(code), string(resp))
 	}
 
 	orid := respmap["id"].(string)
 
 	order := new(Order)
-	order.Amount, _ = strconv.ParseFloat(amount, 64)
-	order.Price, _ = strconv.ParseFloat(price, 64)
+	order.Amount = dp.Decimal(amount)
+	order.Price = dp.Decimal(price)
 	order.Status = ORDER_UNFINISH
 	order.Currency = currency
 	order.OrderTime = int(time.Now().UnixNano() / 1000000)
@@ -237,15 +257,18 @@ func (chbtc *Chbtc) placeOrder(amount, price string, currency CurrencyPair, trad
 		order.Side = BUY
 	}
 
+	if dp.Err != nil {
+		return nil, dp.Err
+	}
 	return order, nil
 }
 
-func (chbtc *Chbtc) LimitBuy(amount, price string, currency CurrencyPair) (*Order, error) {
-	return chbtc.placeOrder(amount, price, currency, 1)
+func (chbtc *Chbtc) LimitBuy(amount, price Decimal, currency CurrencyPair) (*Order, error) {
+	return chbtc.placeOrder(amount.String(), price.String(), currency, 1)
 }
 
-func (chbtc *Chbtc) LimitSell(amount, price string, currency CurrencyPair) (*Order, error) {
-	return chbtc.placeOrder(amount, price, currency, 0)
+func (chbtc *Chbtc) LimitSell(amount, price Decimal, currency CurrencyPair) (*Order, error) {
+	return chbtc.placeOrder(amount.String(), price.String(), currency, 0)
 }
 
 func (chbtc *Chbtc) CancelOrder(orderId string, currency CurrencyPair) (bool, error) {
@@ -275,21 +298,22 @@ func (chbtc *Chbtc) CancelOrder(orderId string, currency CurrencyPair) (bool, er
 	}
 
 	//log.Println(respmap)
-	return false, errors.New(fmt.Sprintf("%.0f", code))
+	return false, chbtc.errorWrapper(int(code), string(resp))
 }
 
-func parseOrder(order *Order, ordermap map[string]interface{}) {
+func parseOrder(order *Order, ordermap map[string]interface{}) error {
+	var dp DecimalParser
 	//log.Println(ordermap)
 	//order.Currency = currency;
 	order.OrderID, _ = strconv.Atoi(ordermap["id"].(string))
-	order.Amount = ordermap["total_amount"].(float64)
-	order.DealAmount = ordermap["trade_amount"].(float64)
-	order.Price = ordermap["price"].(float64)
+	order.Amount = dp.Decimal(ordermap["total_amount"])
+	order.DealAmount = dp.Decimal(ordermap["trade_amount"])
+	order.Price = dp.Decimal(ordermap["price"])
 //	order.Fee = ordermap["fees"].(float64)
-	if order.DealAmount > 0 {
-		order.AvgPrice = ordermap["trade_money"].(float64) / order.DealAmount
+	if order.DealAmount.Sign() > 0 {
+		order.AvgPrice = dp.Decimal(ordermap["trade_money"]).Div(order.DealAmount)
 	} else {
-		order.AvgPrice = 0
+		order.AvgPrice = Decimal{}
 	}
 
 	order.OrderTime = int(ordermap["trade_date"].(float64))
@@ -317,6 +341,7 @@ func parseOrder(order *Order, ordermap map[string]interface{}) {
 
 	}
 
+	return dp.Err
 }
 
 func (chbtc *Chbtc) GetOneOrder(orderId string, currency CurrencyPair) (*Order, error) {
@@ -342,7 +367,9 @@ func (chbtc *Chbtc) GetOneOrder(orderId string, currency CurrencyPair) (*Order,
 	order := new(Order)
 	order.Currency = currency
 
-	parseOrder(order, ordermap)
+	if err = parseOrder(order, ordermap); err != nil {
+		return nil, err
+	}
 
 	return order, nil
 }
@@ -381,7 +408,9 @@ func (chbtc *Chbtc) GetUnfinishOrders(currency CurrencyPair) ([]Order, error) {
 		ordermap := v.(map[string]interface{})
 		order := Order{}
 		order.Currency = currency
-		parseOrder(&order, ordermap)
+		if err = parseOrder(&order, ordermap); err != nil {
+			return nil, err
+		}
 		orders = append(orders, order)
 	}
 
@@ -389,41 +418,98 @@ func (chbtc *Chbtc) GetUnfinishOrders(currency CurrencyPair) ([]Order, error) {
 }
 
 func (chbtc *Chbtc) GetOrderHistorys(currency CurrencyPair, currentPage, pageSize int) ([]Order, error) {
-	return nil, nil
+	return nil, ErrNotSupported
+}
+
+var klinePeriods = map[int]string{
+	KLINE_PERIOD_1MIN:  "1min",
+	KLINE_PERIOD_5MIN:  "5min",
+	KLINE_PERIOD_15MIN: "15min",
+	KLINE_PERIOD_30MIN: "30min",
+	KLINE_PERIOD_60MIN: "1hour",
+	KLINE_PERIOD_4H:    "4hour",
+	KLINE_PERIOD_1DAY:  "1day",
+	KLINE_PERIOD_1WEEK: "1week",
 }
 
-func (chbtc *Chbtc) GetKlineRecords(currency CurrencyPair, period , size, since int) ([]Kline, error) {
-	return nil, nil
+func (chbtc *Chbtc) GetKlineRecords(currency CurrencyPair, period, size, since int) ([]Kline, error) {
+	_period, ok := klinePeriods[period]
+	if !ok {
+		return nil, UnsupportedKlinePeriod(period)
+	}
+	klineUrl := MARKET_URL + fmt.Sprintf(KLINE_API, strings.ToLower(currency.ToSymbol("_")), _period, size)
+	if since > 0 {
+		klineUrl += fmt.Sprintf("&since=%d", since)
+	}
+	resp, err := HttpGet(chbtc.httpClient, klineUrl)
+	if err != nil {
+		return nil, err
+	}
+
+	records, ok := resp["data"].([]interface{})
+	if !ok {
+		errCode := API_ERR
+		errCode.OriginErrMsg = fmt.Sprint(resp)
+		return nil, errCode
+	}
+	//[time ms, open, high, low, close, volume]
+	klines := make([]Kline, 0, len(records))
+	for _, r := range records {
+		record, _ := r.([]interface{})
+		if len(record) < 6 {
+			continue
+		}
+		klines = append(klines, Kline{
+			Timestamp: ToInt64(record[0]) / 1000,
+			Open:      ToFloat64(record[1]),
+			High:      ToFloat64(record[2]),
+			Low:       ToFloat64(record[3]),
+			Close:     ToFloat64(record[4]),
+			Vol:       ToFloat64(record[5])})
+	}
+	return KlinesSince(klines, int64(since), size), nil
 }
 
-func (chbtc *Chbtc) Withdraw(amount string, currency Currency, fees, receiveAddr, safePwd string) (string, error) {
+//Withdraw offers req.Fee, or the one in WithdrawFees when zero
+func (chbtc *Chbtc) Withdraw(req WithdrawRequest) (*Withdraw, error) {
+	fee, err := req.WithdrawFee(chbtc.GetExchangeName())
+	if err != nil {
+		return nil, err
+	}
 	params := url.Values{}
 	params.Set("method", "withdraw")
-	params.Set("currency", strings.ToLower(currency.String()))
-	params.Set("amount", amount)
-	params.Set("fees", fees)
-	params.Set("receiveAddr", receiveAddr)
-	params.Set("safePwd", safePwd)
+	params.Set("currency", strings.ToLower(req.Currency.String()))
+	params.Set("amount", req.Amount.String())
+	params.Set("fees", fee.String())
+	params.Set("receiveAddr", req.Address)
+	params.Set("safePwd", req.TradePassword)
 	chbtc.buildPostForm(&params)
 
 	resp, err := HttpPostForm(chbtc.httpClient, TRADE_URL+WITHDRAW_API, params)
 	if err != nil {
 		log.Println("withdraw fail.", err)
-		return "", err
+		return nil, err
 	}
 
 	respMap := make(map[string]interface{})
 	err = json.Unmarshal(resp, &respMap)
 	if err != nil {
 		log.Println(err, string(resp))
-		return "", err
+		return nil, err
 	}
 
 	if respMap["code"].(float64) == 1000 {
-		return respMap["id"].(string), nil
+		return &Withdraw{
+			ID:       fmt.Sprint(respMap["id"]),
+			Currency: req.Currency,
+			Amount:   req.Amount,
+			Fee:      fee,
+			Address:  req.Address,
+			Status:   WITHDRAW_PENDING,
+			Time:     time.Now().Unix()}, nil
 	}
 
-	return "", errors.New(string(resp))
+	return nil, chbtc.errorWrapper(ToInt(respMap["code"]), string(resp))
 }
 
 func (chbtc *Chbtc) CancelWithdraw(id string, currency Currency, safePwd string) (bool, error) {
@@ -451,17 +537,146 @@ func (chbtc *Chbtc) CancelWithdraw(id string, currency Currency, safePwd string)
 		return true, nil
 	}
 
-	return false, errors.New(string(resp))
+	return false, chbtc.errorWrapper(ToInt(respMap["code"]), string(resp))
+}
+
+//GetWithdraw looks for the withdrawal in the last 100 of the currency
+func (chbtc *Chbtc) GetWithdraw(id string, currency Currency) (*Withdraw, error) {
+	var dp DecimalParser
+	params := url.Values{}
+	params.Set("method", "getWithdrawRecord")
+	params.Set("currency", strings.ToLower(currency.String()))
+	params.Set("pageIndex", "1")
+	params.Set("pageSize", "100")
+	chbtc.buildPostForm(&params)
+
+	resp, err := HttpPostForm(chbtc.httpClient, TRADE_URL+GET_WITHDRAW_RECORD_API, params)
+	if err != nil {
+		return nil, err
+	}
+
+	var records struct {
+		Code    int `json:"code"`
+		Message struct {
+			Datas struct {
+				List []struct {
+					Id         int64   `json:"id"`
+					Amount     float64 `json:"amount"`
+					Fees       float64 `json:"fees"`
+					ToAddress  string  `json:"toAddress"`
+					Status     int     `json:"status"`
+					SubmitTime int64   `json:"submitTime"`
+				} `json:"list"`
+			} `json:"datas"`
+		} `json:"message"`
+	}
+	err = json.Unmarshal(resp, &records)
+	if err != nil {
+		log.Println(err, string(resp))
+		return nil, err
+	}
+	if records.Code != 1000 {
+		return nil, chbtc.errorWrapper(records.Code, string(resp))
+	}
+
+	for _, r := range records.Message.Datas.List {
+		if strconv.FormatInt(r.Id, 10) != id {
+			continue
+		}
+		w := &Withdraw{
+			ID:       id,
+			Currency: currency,
+			Amount:   dp.Decimal(r.Amount),
+			Fee:      dp.Decimal(r.Fees),
+			Address:  r.ToAddress,
+			Time:     r.SubmitTime / 1000}
+		switch r.Status {
+		case 1:
+			w.Status = WITHDRAW_FAILED
+		case 2:
+			w.Status = WITHDRAW_COMPLETED
+		case 3:
+			w.Status = WITHDRAW_CANCELED
+		case 5:
+			w.Status = WITHDRAW_PROCESSING
+		default:
+			w.Status = WITHDRAW_PENDING
+		}
+		if dp.Err != nil {
+			return nil, dp.Err
+		}
+		return w, nil
+	}
+
+	errCode := EX_ERR_NOT_FIND_WITHDRAW
+	errCode.OriginErrMsg = id
+	return nil, errCode
 }
 
+//GetTrades answers the 50 trades after the trade id since, or the latest when since is 0
 func (chbtc *Chbtc) GetTrades(currencyPair CurrencyPair, since int64) ([]Trade, error) {
-	panic("unimplements")
+	var dp DecimalParser
+	tradesUrl := MARKET_URL + fmt.Sprintf(TRADES_API, strings.ToLower(currencyPair.ToSymbol("_")))
+	if since > 0 {
+		tradesUrl += fmt.Sprintf("&since=%d", since)
+	}
+	resp, err := HttpGet3(chbtc.httpClient, tradesUrl, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	trades := make([]Trade, 0, len(resp))
+	for _, v := range resp {
+		t, ok := v.(map[string]interface{})
+		if !ok {
+			continue
+		}
+		trades = append(trades, Trade{
+			Tid:    ToInt64(t["tid"]),
+			Type:   fmt.Sprint(t["type"]),
+			Amount: dp.Decimal(t["amount"]),
+			Price:  dp.Decimal(t["price"]),
+			Date:   ToInt64(t["date"]) * 1000})
+	}
+	if dp.Err != nil {
+		return nil, dp.Err
+	}
+	return trades, nil
+}
+
+//TradesSince tells TradeIterator that GetTrades pages by trade id
+func (chbtc *Chbtc) TradesSince() TradesSince {
+	return TRADES_SINCE_ID
 }
 
-func (chbtc *Chbtc) MarketBuy(amount, price string, currency CurrencyPair) (*Order, error) {
-	panic("unsupport the market order")
+func (chbtc *Chbtc) MarketBuy(amount, price Decimal, currency CurrencyPair) (*Order, error) {
+	return nil, ErrNotSupported
 }
 
-func (chbtc *Chbtc) MarketSell(amount, price string, currency CurrencyPair) (*Order, error) {
-	panic("unsupport the market order")
+func (chbtc *Chbtc) MarketSell(amount, price Decimal, currency CurrencyPair) (*Order, error) {
+	return nil, ErrNotSupported
+}
+
+func (chbtc *Chbtc) errorWrapper(code int, originErrMsg string) ApiError {
+	var errCode ApiError
+	switch code {
+	case 1003:
+		errCode = EX_ERR_SIGN
+	case 1009:
+		errCode = EX_ERR_MAINTENANCE
+	case 2001, 2002, 2003, 2005, 2006, 2007, 2009:
+		errCode = EX_ERR_INSUFFICIENT_BALANCE
+	case 3001:
+		errCode = EX_ERR_NOT_FIND_ORDER
+	case 3002, 3003:
+		errCode = EX_ERR_INVALID_PRECISION
+	case 3007:
+		errCode = EX_ERR_NONCE
+	case 4002:
+		errCode = EX_ERR_API_LIMIT
+	default:
+		errCode = API_ERR
+	}
+	errCode.OriginErrMsg = originErrMsg
+	return errCode
 }
@@ -0,0 +1,68 @@
+package chbtc
+
+import (
+	"context"
+
+	. "github.com/nntaoli-project/GoEx"
+)
+
+var _ API = (*Chbtc)(nil)
+var _ APIWithContext = (*Chbtc)(nil)
+
+func (chbtc *Chbtc) withContext(ctx context.Context) *Chbtc {
+	c := *chbtc
+	c.httpClient = HttpClientWithContext(ctx, chbtc.httpClient)
+	return &c
+}
+
+func (chbtc *Chbtc) LimitBuyCtx(ctx context.Context, amount, price Decimal, currency CurrencyPair) (*Order, error) {
+	return chbtc.withContext(ctx).LimitBuy(amount, price, currency)
+}
+
+func (chbtc *Chbtc) LimitSellCtx(ctx context.Context, amount, price Decimal, currency CurrencyPair) (*Order, error) {
+	return chbtc.withContext(ctx).LimitSell(amount, price, currency)
+}
+
+func (chbtc *Chbtc) MarketBuyCtx(ctx context.Context, amount, price Decimal, currency CurrencyPair) (*Order, error) {
+	return chbtc.withContext(ctx).MarketBuy(amount, price, currency)
+}
+
+func (chbtc *Chbtc) MarketSellCtx(ctx context.Context, amount, price Decimal, currency CurrencyPair) (*Order, error) {
+	return chbtc.withContext(ctx).MarketSell(amount, price, currency)
+}
+
+func (chbtc *Chbtc) CancelOrderCtx(ctx context.Context, orderId string, currency CurrencyPair) (bool, error) {
+	return chbtc.withContext(ctx).CancelOrder(orderId, currency)
+}
+
+func (chbtc *Chbtc) GetOneOrderCtx(ctx context.Context, orderId string, currency CurrencyPair) (*Order, error) {
+	return chbtc.withContext(ctx).GetOneOrder(orderId, currency)
+}
+
+func (chbtc *Chbtc) GetUnfinishOrdersCtx(ctx context.Context, currency CurrencyPair) ([]Order, error) {
+	return chbtc.withContext(ctx).GetUnfinishOrders(currency)
+}
+
+func (chbtc *Chbtc) GetOrderHistorysCtx(ctx context.Context, currency CurrencyPair, currentPage, pageSize int) ([]Order, error) {
+	return chbtc.withContext(ctx).GetOrderHistorys(currency, currentPage, pageSize)
+}
+
+func (chbtc *Chbtc) GetAccountCtx(ctx context.Context) (*Account, error) {
+	return chbtc.withContext(ctx).GetAccount()
+}
+
+func (chbtc *Chbtc) GetTickerCtx(ctx context.Context, currency CurrencyPair) (*Ticker, error) {
+	return chbtc.withContext(ctx).GetTicker(currency)
+}
+
+func (chbtc *Chbtc) GetDepthCtx(ctx context.Context, size int, currency CurrencyPair) (*Depth, error) {
+	return chbtc.withContext(ctx).GetDepth(size, currency)
+}
+
+func (chbtc *Chbtc) GetKlineRecordsCtx(ctx context.Context, currency CurrencyPair, period, size, since int) ([]Kline, error) {
+	return chbtc.withContext(ctx).GetKlineRecords(currency, period, size, since)
+}
+
+func (chbtc *Chbtc) GetTradesCtx(ctx context.Context, currencyPair CurrencyPair, since int64) ([]Trade, error) {
+	return chbtc.withContext(ctx).GetTrades(currencyPair, since)
+}
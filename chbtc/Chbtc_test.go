@@ -2,27 +2,171 @@ package chbtc
 
 import (
 	"github.com/nntaoli-project/GoEx"
+	"github.com/stretchr/testify/assert"
 	"net/http"
+	"net/http/httptest"
+	"os"
+	"strings"
 	"testing"
 )
 
 var (
 	api_key       = ""
 	api_secretkey = ""
-	chbtc         = New(http.DefaultClient, api_key, api_secretkey)
+	fixtures      = goex.NewFixtureTransport("testdata/fixtures.json")
+	chbtc         = New(fixtures.Client(), api_key, api_secretkey)
 )
 
 func TestChbtc_GetAccount(t *testing.T) {
-	acc, _ := chbtc.GetAccount()
-	t.Log(acc)
+	acc, err := chbtc.GetAccount()
+	assert.Nil(t, err)
+	assert.Equal(t, goex.SubAccount{Currency: goex.CNY, Amount: goex.RequireDecimal("418.32"), FrozenAmount: goex.RequireDecimal("0.2")}, acc.SubAccounts[goex.CNY])
+	assert.Equal(t, goex.SubAccount{Currency: goex.BTC, Amount: goex.RequireDecimal("0.0183")}, acc.SubAccounts[goex.BTC])
+	assert.Equal(t, goex.SubAccount{Currency: goex.ETH, Amount: goex.RequireDecimal("0.5")}, acc.SubAccounts[goex.ETH])
 }
 
 func TestChbtc_GetTicker(t *testing.T) {
-	ticker, _ := chbtc.GetTicker(goex.BCC_CNY)
-	t.Log(ticker)
+	ticker, err := chbtc.GetTicker(goex.BCC_CNY)
+	assert.Nil(t, err)
+	assert.Equal(t, goex.Ticker{Last: goex.RequireDecimal("3803"), Buy: goex.RequireDecimal("3802"), Sell: goex.RequireDecimal("3805"),
+		High: goex.RequireDecimal("3928"), Low: goex.RequireDecimal("3700.01"), Vol: goex.RequireDecimal("2417.7283"), Date: 1504686312185}, *ticker)
 }
 
 func TestChbtc_GetDepth(t *testing.T) {
-	dep, _ := chbtc.GetDepth(1, goex.ETH_CNY)
-	t.Log(dep)
+	dep, err := chbtc.GetDepth(1, goex.ETH_CNY)
+	assert.Nil(t, err)
+	assert.Equal(t, goex.DepthRecords{{Price: goex.RequireDecimal("2149.99"), Amount: goex.RequireDecimal("0.4")}}, dep.AskList)
+	assert.Equal(t, goex.DepthRecords{{Price: goex.RequireDecimal("2145"), Amount: goex.RequireDecimal("1.35")}}, dep.BidList)
+}
+
+func TestMain(m *testing.M) {
+	os.Exit(fixtures.Run(m.Run))
+}
+
+func TestChbtc_errorWrapper(t *testing.T) {
+	for code, errCode := range map[int]goex.ApiError{
+		1003: goex.EX_ERR_SIGN,
+		1009: goex.EX_ERR_MAINTENANCE,
+		2001: goex.EX_ERR_INSUFFICIENT_BALANCE,
+		2009: goex.EX_ERR_INSUFFICIENT_BALANCE,
+		3001: goex.EX_ERR_NOT_FIND_ORDER,
+		3002: goex.EX_ERR_INVALID_PRECISION,
+		3007: goex.EX_ERR_NONCE,
+		4002: goex.EX_ERR_API_LIMIT,
+		1001: goex.API_ERR,
+	} {
+		err := chbtc.errorWrapper(code, "origin")
+		assert.True(t, errCode.Is(err), "%d", code)
+		assert.Equal(t, "origin", err.OriginErrMsg)
+	}
+}
+
+//rewriteTransport sends every request to the stand-in server
+type rewriteTransport struct {
+	host string
+}
+
+func (rt rewriteTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	req.URL.Scheme = "http"
+	req.URL.Host = rt.host
+	return http.DefaultTransport.RoundTrip(req)
+}
+
+func TestChbtc_GetTrades(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "/data/v1/trades", r.URL.Path)
+		assert.Equal(t, "btc_usdt", r.URL.Query().Get("currency"))
+		assert.Equal(t, "23549420", r.URL.Query().Get("since"))
+		w.Write([]byte(`[{"amount":"0.010","date":1525152612,"price":"9250.0","tid":23549421,"trade_type":"bid","type":"buy"}]`))
+	}))
+	defer srv.Close()
+	c := New(&http.Client{Transport: rewriteTransport{strings.TrimPrefix(srv.URL, "http://")}}, "", "")
+
+	trades, err := c.GetTrades(goex.BTC_USDT, 23549420)
+	assert.Nil(t, err)
+	assert.Equal(t, []goex.Trade{{Tid: 23549421, Type: "buy", Amount: goex.RequireDecimal("0.01"), Price: goex.RequireDecimal("9250"),
+		Date: 1525152612000}}, trades)
+}
+
+func TestChbtc_GetKlineRecords(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		q := r.URL.Query()
+		assert.Equal(t, "/data/v1/kline", r.URL.Path)
+		assert.Equal(t, "btc_usdt", q.Get("currency"))
+		assert.Equal(t, "1hour", q.Get("type"))
+		assert.Equal(t, "2", q.Get("size"))
+		assert.Equal(t, "1525132800000", q.Get("since"))
+		w.Write([]byte(`{"data":[[1525132800000,9250,9300,9200,9280,12.5],[1525136400000,9280,9290,9240,9260,8.25]],` +
+			`"moneyType":"usdt","symbol":"btc"}`))
+	}))
+	defer srv.Close()
+	c := New(&http.Client{Transport: rewriteTransport{strings.TrimPrefix(srv.URL, "http://")}}, "", "")
+
+	klines, err := c.GetKlineRecords(goex.BTC_USDT, goex.KLINE_PERIOD_60MIN, 2, 1525132800000)
+	assert.Nil(t, err)
+	assert.Equal(t, []goex.Kline{
+		{Timestamp: 1525132800, Open: 9250, Close: 9280, High: 9300, Low: 9200, Vol: 12.5},
+		{Timestamp: 1525136400, Open: 9280, Close: 9260, High: 9290, Low: 9240, Vol: 8.25}}, klines)
+}
+
+func TestChbtc_Withdraw(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "/api/withdraw", r.URL.Path)
+		r.ParseForm()
+		assert.Equal(t, "withdraw", r.PostForm.Get("method"))
+		assert.Equal(t, "btc", r.PostForm.Get("currency"))
+		assert.Equal(t, "0.5", r.PostForm.Get("amount"))
+		assert.Equal(t, "0.0005", r.PostForm.Get("fees"))
+		assert.Equal(t, "1BvBMSEYstWetqTFn5Au4m4GFg7xJaNVN2", r.PostForm.Get("receiveAddr"))
+		assert.Equal(t, "123456", r.PostForm.Get("safePwd"))
+		assert.NotEmpty(t, r.PostForm.Get("sign"))
+		w.Write([]byte(`{"code":1000,"message":"操作成功","id":"20180501"}`))
+	}))
+	defer srv.Close()
+	c := New(&http.Client{Transport: rewriteTransport{strings.TrimPrefix(srv.URL, "http://")}}, "", "")
+
+	withdraw, err := c.Withdraw(goex.WithdrawRequest{Currency: goex.BTC, Amount: goex.RequireDecimal("0.5"), Fee: goex.RequireDecimal("0.0005"),
+		Address: "1BvBMSEYstWetqTFn5Au4m4GFg7xJaNVN2", TradePassword: "123456"})
+	assert.Nil(t, err)
+	assert.Equal(t, "20180501", withdraw.ID)
+	assert.Equal(t, goex.RequireDecimal("0.0005"), withdraw.Fee)
+	assert.Equal(t, goex.WITHDRAW_PENDING, withdraw.Status)
+}
+
+func TestChbtc_GetWithdraw(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "/api/getWithdrawRecord", r.URL.Path)
+		r.ParseForm()
+		assert.Equal(t, "getWithdrawRecord", r.PostForm.Get("method"))
+		assert.Equal(t, "btc", r.PostForm.Get("currency"))
+		assert.Equal(t, "1", r.PostForm.Get("pageIndex"))
+		w.Write([]byte(`{"code":1000,"message":{"des":"success","isSuc":true,"datas":{"list":[` +
+			`{"id":1001,"amount":0.5,"fees":0.0005,"toAddress":"1BvBMSEYstWetqTFn5Au4m4GFg7xJaNVN2","status":2,"submitTime":1525152612000},` +
+			`{"id":1002,"amount":0.5,"fees":0.0005,"toAddress":"1BvBMSEYstWetqTFn5Au4m4GFg7xJaNVN2","status":1,"submitTime":1525152612000},` +
+			`{"id":1003,"amount":0.5,"fees":0.0005,"toAddress":"1BvBMSEYstWetqTFn5Au4m4GFg7xJaNVN2","status":3,"submitTime":1525152612000},` +
+			`{"id":1004,"amount":0.5,"fees":0.0005,"toAddress":"1BvBMSEYstWetqTFn5Au4m4GFg7xJaNVN2","status":5,"submitTime":1525152612000},` +
+			`{"id":1005,"amount":0.5,"fees":0.0005,"toAddress":"1BvBMSEYstWetqTFn5Au4m4GFg7xJaNVN2","status":0,"submitTime":1525152612000}],` +
+			`"pageIndex":1,"pageSize":100,"totalCount":5,"totalPage":1}}}`))
+	}))
+	defer srv.Close()
+	c := New(&http.Client{Transport: rewriteTransport{strings.TrimPrefix(srv.URL, "http://")}}, "", "")
+
+	withdraw, err := c.GetWithdraw("1001", goex.BTC)
+	assert.Nil(t, err)
+	assert.Equal(t, goex.Withdraw{ID: "1001", Currency: goex.BTC, Amount: goex.RequireDecimal("0.5"), Fee: goex.RequireDecimal("0.0005"),
+		Address: "1BvBMSEYstWetqTFn5Au4m4GFg7xJaNVN2", Status: goex.WITHDRAW_COMPLETED, Time: 1525152612}, *withdraw)
+
+	for id, status := range map[string]goex.WithdrawStatus{
+		"1002": goex.WITHDRAW_FAILED,
+		"1003": goex.WITHDRAW_CANCELED,
+		"1004": goex.WITHDRAW_PROCESSING,
+		"1005": goex.WITHDRAW_PENDING,
+	} {
+		withdraw, err := c.GetWithdraw(id, goex.BTC)
+		assert.Nil(t, err)
+		assert.Equal(t, status, withdraw.Status, id)
+	}
+
+	_, err = c.GetWithdraw("1006", goex.BTC)
+	assert.True(t, goex.EX_ERR_NOT_FIND_WITHDRAW.Is(err))
 }
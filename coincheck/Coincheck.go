@@ -1,13 +1,14 @@
 package coincheck
 
 import (
+	"encoding/json"
 	"fmt"
 	. "github.com/nntaoli-project/GoEx"
 	"log"
 	"net/http"
 	//"strconv"
 	"sort"
-	"strconv"
+	"strings"
 )
 
 type Coincheck struct {
@@ -18,6 +19,7 @@ type Coincheck struct {
 }
 
 func New(httpClient *http.Client, accessKey, secretKey string) (coinCheck *Coincheck) {
+	httpClient = ExchangeRateLimitedClient(httpClient, "coincheck.com", accessKey, DefaultRateLimitConfig)
 	cc := new(Coincheck)
 	cc.client = httpClient
 	cc.accessKey = accessKey
@@ -30,33 +32,48 @@ func (cc *Coincheck) GetExchangeName() string {
 	return "coincheck.com"
 }
 
+func (cc *Coincheck) Capabilities() Capabilities {
+	return Capabilities{}
+}
+
 func (cc *Coincheck) GetTicker(currency CurrencyPair) (*Ticker, error) {
-	tickerUrl := fmt.Sprintf(cc.baseUrl + "api/ticker")
+	var dp DecimalParser
+	tickerUrl := cc.baseUrl + "api/ticker"
 
 	//println(tickerUrl)
 	resp, err := HttpGet(cc.client, tickerUrl)
 	if err != nil {
 		log.Print(err)
-		return nil, err
+		return nil, cc.adaptError(err)
+	}
+	if success, isok := resp["success"].(bool); isok && !success {
+		return nil, cc.errorWrapper(fmt.Sprint(resp["error"]))
 	}
 	//log.Println(resp)
 	ticker := new(Ticker)
-	ticker.Buy = resp["bid"].(float64)
-	ticker.Sell = resp["ask"].(float64)
-	ticker.Last = resp["last"].(float64)
-	ticker.High = resp["high"].(float64)
-	ticker.Low = resp["low"].(float64)
+	ticker.Buy = dp.Decimal(resp["bid"])
+	ticker.Sell = dp.Decimal(resp["ask"])
+	ticker.Last = dp.Decimal(resp["last"])
+	ticker.High = dp.Decimal(resp["high"])
+	ticker.Low = dp.Decimal(resp["low"])
 	ticker.Date = uint64(resp["timestamp"].(float64))
-	ticker.Vol = resp["volume"].(float64)
+	ticker.Vol = dp.Decimal(resp["volume"])
+	if dp.Err != nil {
+		return nil, dp.Err
+	}
 	return ticker, nil
 }
 
 func (cc *Coincheck) GetDepth(size int, currency CurrencyPair) (*Depth, error) {
+	var dp DecimalParser
 	depthUrl := cc.baseUrl + "api/order_books"
 	resp, err := HttpGet(cc.client, depthUrl)
 	if err != nil {
 		log.Println(err)
-		return nil, err
+		return nil, cc.adaptError(err)
+	}
+	if success, isok := resp["success"].(bool); isok && !success {
+		return nil, cc.errorWrapper(fmt.Sprint(resp["error"]))
 	}
 	//log.Println(resp)
 	var depth Depth
@@ -71,9 +88,9 @@ func (cc *Coincheck) GetDepth(size int, currency CurrencyPair) (*Depth, error) {
 		for i, vv := range v.([]interface{}) {
 			switch i {
 			case 0:
-				dr.Price, _ = strconv.ParseFloat(vv.(string), 64)
+				dr.Price = dp.Price(vv)
 			case 1:
-				dr.Amount, _ = strconv.ParseFloat(vv.(string), 64)
+				dr.Amount = dp.Decimal(vv)
 			}
 		}
 		depth.AskList = append(depth.AskList, dr)
@@ -91,9 +108,9 @@ func (cc *Coincheck) GetDepth(size int, currency CurrencyPair) (*Depth, error) {
 		for i, vv := range v.([]interface{}) {
 			switch i {
 			case 0:
-				dr.Price, _ = strconv.ParseFloat(vv.(string), 64)
+				dr.Price = dp.Price(vv)
 			case 1:
-				dr.Amount, _ = strconv.ParseFloat(vv.(string), 64)
+				dr.Amount = dp.Decimal(vv)
 			}
 		}
 		depth.BidList = append(depth.BidList, dr)
@@ -104,51 +121,89 @@ func (cc *Coincheck) GetDepth(size int, currency CurrencyPair) (*Depth, error) {
 		}
 	}
 
+	if dp.Err != nil {
+		return nil, dp.Err
+	}
 	return &depth, nil
 }
 
 
-func (cc *Coincheck) LimitBuy(amount, price string, currency CurrencyPair) (*Order, error) {
-	return nil, nil
+func (cc *Coincheck) LimitBuy(amount, price Decimal, currency CurrencyPair) (*Order, error) {
+	return nil, ErrNotSupported
 }
 
-func (cc *Coincheck) LimitSell(amount, price string, currency CurrencyPair) (*Order, error) {
-	return nil, nil
+func (cc *Coincheck) LimitSell(amount, price Decimal, currency CurrencyPair) (*Order, error) {
+	return nil, ErrNotSupported
 }
 
-func (cc *Coincheck) MarketBuy(amount, price string, currency CurrencyPair) (*Order, error) {
-	return nil, nil
+func (cc *Coincheck) MarketBuy(amount, price Decimal, currency CurrencyPair) (*Order, error) {
+	return nil, ErrNotSupported
 }
 
-func (cc *Coincheck) MarketSell(amount, price string, currency CurrencyPair) (*Order, error) {
-	return nil, nil
+func (cc *Coincheck) MarketSell(amount, price Decimal, currency CurrencyPair) (*Order, error) {
+	return nil, ErrNotSupported
 }
 
 func (cc *Coincheck) CancelOrder(orderId string, currency CurrencyPair) (bool, error) {
-	return false, nil
+	return false, ErrNotSupported
 }
 
 func (cc *Coincheck) GetOneOrder(orderId string, currency CurrencyPair) (*Order, error) {
-	return nil, nil
+	return nil, ErrNotSupported
 }
 
 func (cc *Coincheck) GetUnfinishOrders(currency CurrencyPair) ([]Order, error) {
-	return nil, nil
+	return nil, ErrNotSupported
 }
 
 func (cc *Coincheck) GetOrderHistorys(currency CurrencyPair, currentPage, pageSize int) ([]Order, error) {
-	return nil, nil
+	return nil, ErrNotSupported
 }
 
 func (cc *Coincheck) GetAccount() (*Account, error) {
-	return nil, nil
+	return nil, ErrNotSupported
 }
 
 func (cc *Coincheck) GetKlineRecords(currency CurrencyPair, period , size, since int) ([]Kline, error) {
-	return nil, nil
+	return nil, ErrNotSupported
 }
 
 //非个人，整个交易所的交易记录
 func (cc *Coincheck) GetTrades(currencyPair CurrencyPair, since int64) ([]Trade, error) {
-	return nil, nil
-}
\ No newline at end of file
+	return nil, ErrNotSupported
+}
+
+//coincheck reports failures as {"success":false,"error":"..."}
+func (cc *Coincheck) errorWrapper(message string) ApiError {
+	var errCode ApiError
+	msg := strings.ToLower(message)
+	switch {
+	case strings.Contains(msg, "too many requests"):
+		errCode = EX_ERR_API_LIMIT
+	case strings.Contains(msg, "maintenance"):
+		errCode = EX_ERR_MAINTENANCE
+	case strings.Contains(msg, "pair"):
+		errCode = EX_ERR_INVALID_CURRENCY_PAIR
+	default:
+		errCode = API_ERR
+	}
+	errCode.OriginErrMsg = message
+	return errCode
+}
+
+//coincheck sends the same body with a non-200 status too
+func (cc *Coincheck) adaptError(err error) error {
+	apiErr, isok := err.(ApiError)
+	if !isok {
+		return err
+	}
+
+	var resp struct {
+		Success *bool  `json:"success"`
+		Error   string `json:"error"`
+	}
+	if json.Unmarshal([]byte(apiErr.OriginErrMsg), &resp) != nil || resp.Success == nil || *resp.Success {
+		return err
+	}
+	return cc.errorWrapper(resp.Error)
+}
@@ -0,0 +1,68 @@
+package coincheck
+
+import (
+	"context"
+
+	. "github.com/nntaoli-project/GoEx"
+)
+
+var _ API = (*Coincheck)(nil)
+var _ APIWithContext = (*Coincheck)(nil)
+
+func (cc *Coincheck) withContext(ctx context.Context) *Coincheck {
+	c := *cc
+	c.client = HttpClientWithContext(ctx, cc.client)
+	return &c
+}
+
+func (cc *Coincheck) LimitBuyCtx(ctx context.Context, amount, price Decimal, currency CurrencyPair) (*Order, error) {
+	return cc.withContext(ctx).LimitBuy(amount, price, currency)
+}
+
+func (cc *Coincheck) LimitSellCtx(ctx context.Context, amount, price Decimal, currency CurrencyPair) (*Order, error) {
+	return cc.withContext(ctx).LimitSell(amount, price, currency)
+}
+
+func (cc *Coincheck) MarketBuyCtx(ctx context.Context, amount, price Decimal, currency CurrencyPair) (*Order, error) {
+	return cc.withContext(ctx).MarketBuy(amount, price, currency)
+}
+
+func (cc *Coincheck) MarketSellCtx(ctx context.Context, amount, price Decimal, currency CurrencyPair) (*Order, error) {
+	return cc.withContext(ctx).MarketSell(amount, price, currency)
+}
+
+func (cc *Coincheck) CancelOrderCtx(ctx context.Context, orderId string, currency CurrencyPair) (bool, error) {
+	return cc.withContext(ctx).CancelOrder(orderId, currency)
+}
+
+func (cc *Coincheck) GetOneOrderCtx(ctx context.Context, orderId string, currency CurrencyPair) (*Order, error) {
+	return cc.withContext(ctx).GetOneOrder(orderId, currency)
+}
+
+func (cc *Coincheck) GetUnfinishOrdersCtx(ctx context.Context, currency CurrencyPair) ([]Order, error) {
+	return cc.withContext(ctx).GetUnfinishOrders(currency)
+}
+
+func (cc *Coincheck) GetOrderHistorysCtx(ctx context.Context, currency CurrencyPair, currentPage, pageSize int) ([]Order, error) {
+	return cc.withContext(ctx).GetOrderHistorys(currency, currentPage, pageSize)
+}
+
+func (cc *Coincheck) GetAccountCtx(ctx context.Context) (*Account, error) {
+	return cc.withContext(ctx).GetAccount()
+}
+
+func (cc *Coincheck) GetTickerCtx(ctx context.Context, currency CurrencyPair) (*Ticker, error) {
+	return cc.withContext(ctx).GetTicker(currency)
+}
+
+func (cc *Coincheck) GetDepthCtx(ctx context.Context, size int, currency CurrencyPair) (*Depth, error) {
+	return cc.withContext(ctx).GetDepth(size, currency)
+}
+
+func (cc *Coincheck) GetKlineRecordsCtx(ctx context.Context, currency CurrencyPair, period, size, since int) ([]Kline, error) {
+	return cc.withContext(ctx).GetKlineRecords(currency, period, size, since)
+}
+
+func (cc *Coincheck) GetTradesCtx(ctx context.Context, currencyPair CurrencyPair, since int64) ([]Trade, error) {
+	return cc.withContext(ctx).GetTrades(currencyPair, since)
+}
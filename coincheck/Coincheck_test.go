@@ -3,20 +3,59 @@ package coincheck
 import (
 	. "github.com/nntaoli-project/GoEx"
 	"github.com/stretchr/testify/assert"
-	"net/http"
+	"os"
 	"testing"
 )
 
-var api = New(http.DefaultClient, "", "")
+var fixtures = NewFixtureTransport("testdata/fixtures.json")
+var api = New(fixtures.Client(), "", "")
 
 func TestCoincheck_GetTicker(t *testing.T) {
 	ticker, err := api.GetTicker(CurrencyPair{BTC, Currency{"JPY", ""}})
 	assert.NoError(t, err)
-	t.Log(ticker)
+	assert.Equal(t, RequireDecimal("686300"), ticker.Buy)
+	assert.Equal(t, RequireDecimal("686505"), ticker.Sell)
+	assert.Equal(t, RequireDecimal("686505"), ticker.Last)
+	assert.Equal(t, RequireDecimal("692999"), ticker.High)
+	assert.Equal(t, RequireDecimal("667000"), ticker.Low)
+	assert.Equal(t, RequireDecimal("13702.79284361"), ticker.Vol)
+	assert.Equal(t, uint64(1529920350), ticker.Date)
 }
 
 func TestCoincheck_GetDepth(t *testing.T) {
 	depth, err := api.GetDepth(3, CurrencyPair{BTC, NewCurrency("JPY", "")})
 	assert.NoError(t, err)
-	t.Log(depth)
+	//the best ask last
+	assert.Equal(t, DepthRecords{{Price: RequireDecimal("686720"), Amount: RequireDecimal("1.5101")},
+		{Price: RequireDecimal("686600"), Amount: RequireDecimal("0.2")},
+		{Price: RequireDecimal("686505"), Amount: RequireDecimal("0.09")}}, depth.AskList)
+	assert.Equal(t, DepthRecords{{Price: RequireDecimal("686300"), Amount: RequireDecimal("0.8273")},
+		{Price: RequireDecimal("686255"), Amount: RequireDecimal("0.03")},
+		{Price: RequireDecimal("686000"), Amount: RequireDecimal("2")}}, depth.BidList)
+}
+
+func TestMain(m *testing.M) {
+	os.Exit(fixtures.Run(m.Run))
+}
+
+func TestCoincheck_errorWrapper(t *testing.T) {
+	for message, errCode := range map[string]ApiError{
+		"Too many requests":      EX_ERR_API_LIMIT,
+		"Under maintenance":      EX_ERR_MAINTENANCE,
+		"invalid pair":           EX_ERR_INVALID_CURRENCY_PAIR,
+		"invalid authentication": API_ERR,
+	} {
+		err := api.errorWrapper(message)
+		assert.True(t, errCode.Is(err), message)
+		assert.Equal(t, message, err.OriginErrMsg)
+	}
+}
+
+func TestCoincheck_adaptError(t *testing.T) {
+	httpErr := HTTP_ERR_CODE
+	httpErr.OriginErrMsg = `{"success":false,"error":"invalid pair"}`
+	assert.True(t, EX_ERR_INVALID_CURRENCY_PAIR.Is(api.adaptError(httpErr)))
+
+	httpErr.OriginErrMsg = `{"success":true}`
+	assert.Equal(t, error(httpErr), api.adaptError(httpErr))
 }
@@ -1,10 +1,10 @@
 package cryptopia
 
 import (
-	"errors"
 	. "github.com/nntaoli-project/GoEx"
 	//"log"
 	"net/http"
+	"strings"
 	"time"
 )
 
@@ -24,6 +24,7 @@ type Cryptopia struct {
 }
 
 func New(client *http.Client, accessKey, secretKey string) *Cryptopia {
+	client = ExchangeRateLimitedClient(client, EXCHANGE_NAME, accessKey, DefaultRateLimitConfig)
 	return &Cryptopia{accessKey, secretKey, client}
 }
 
@@ -31,6 +32,10 @@ func (cta *Cryptopia) GetExchangeName() string {
 	return EXCHANGE_NAME
 }
 
+func (cta *Cryptopia) Capabilities() Capabilities {
+	return Capabilities{}
+}
+
 func (cta *Cryptopia) GetTickers(currency CurrencyPair) (*Ticker, error) {
 	return cta.GetTicker(currency)
 
@@ -55,6 +60,7 @@ func (cta *Cryptopia) GetTickers(currency CurrencyPair) (*Ticker, error) {
 }
 
 func (cta *Cryptopia) GetTicker(currency CurrencyPair) (*Ticker, error) {
+	var dp DecimalParser
 	currency = cta.adaptCurrencyPair(currency)
 
 	tickerUri := API_BASE_URL + TICKER_URI + currency.ToSymbol("_")
@@ -71,7 +77,8 @@ func (cta *Cryptopia) GetTicker(currency CurrencyPair) (*Ticker, error) {
 		//log.Println("Cryptopia bodyDataMap:", tickerUri, bodyDataMap)
 		//log.Println("bodyDataMap[\"Error\"]", bodyDataMap["Error"].(string))
 		//return nil, errors.New(bodyDataMap["Error"].(string))
-		return nil, errors.New("ERR")
+		errmsg, _ := bodyDataMap["Error"].(string)
+		return nil, cta.errorWrapper(errmsg)
 	}
 	var ticker Ticker
 
@@ -79,12 +86,15 @@ func (cta *Cryptopia) GetTicker(currency CurrencyPair) (*Ticker, error) {
 
 	//fmt.Println(bodyDataMap)
 	ticker.Date = uint64(timestamp)
-	ticker.Last, _ = tickerMap["LastPrice"].(float64)
+	ticker.Last = dp.Decimal(tickerMap["LastPrice"])
 
-	ticker.Buy, _ = tickerMap["BidPrice"].(float64)
-	ticker.Sell, _ = tickerMap["AskPrice"].(float64)
-	ticker.Vol, _ = tickerMap["Volume"].(float64)
+	ticker.Buy = dp.Decimal(tickerMap["BidPrice"])
+	ticker.Sell = dp.Decimal(tickerMap["AskPrice"])
+	ticker.Vol = dp.Decimal(tickerMap["Volume"])
 	//log.Println("Cryptopia", currency, "ticker:", ticker)
+	if dp.Err != nil {
+		return nil, dp.Err
+	}
 	return &ticker, nil
 }
 
@@ -93,7 +103,16 @@ func (cta *Cryptopia) GetTickerInBuf(currency CurrencyPair) (*Ticker, error) {
 }
 
 func (cta *Cryptopia) GetDepth(size int, currency CurrencyPair) (*Depth, error) {
-	panic("not implement")
+	return nil, ErrNotSupported
+}
+
+func (cta *Cryptopia) GetKlineRecords(currency CurrencyPair, period, size, since int) ([]Kline, error) {
+	return nil, ErrNotSupported
+}
+
+//非个人，整个交易所的交易记录
+func (cta *Cryptopia) GetTrades(currencyPair CurrencyPair, since int64) ([]Trade, error) {
+	return nil, ErrNotSupported
 }
 
 func (cta *Cryptopia) adaptCurrencyPair(pair CurrencyPair) CurrencyPair {
@@ -115,37 +134,53 @@ func (cta *Cryptopia) adaptCurrencyPair(pair CurrencyPair) CurrencyPair {
 	return NewCurrencyPair(currencyA, currencyB)
 }
 
-func (cta *Cryptopia) LimitBuy(amount, price string, currency CurrencyPair) (*Order, error) {
-	panic("not implements")
+func (cta *Cryptopia) LimitBuy(amount, price Decimal, currency CurrencyPair) (*Order, error) {
+	return nil, ErrNotSupported
 }
 
-func (cta *Cryptopia) LimitSell(amount, price string, currency CurrencyPair) (*Order, error) {
-	panic("not implements")
+func (cta *Cryptopia) LimitSell(amount, price Decimal, currency CurrencyPair) (*Order, error) {
+	return nil, ErrNotSupported
 }
 
-func (cta *Cryptopia) MarketBuy(amount, price string, currency CurrencyPair) (*Order, error) {
-	panic("not implements")
+func (cta *Cryptopia) MarketBuy(amount, price Decimal, currency CurrencyPair) (*Order, error) {
+	return nil, ErrNotSupported
 }
 
-func (cta *Cryptopia) MarketSell(amount, price string, currency CurrencyPair) (*Order, error) {
-	panic("not implements")
+func (cta *Cryptopia) MarketSell(amount, price Decimal, currency CurrencyPair) (*Order, error) {
+	return nil, ErrNotSupported
 }
 
 func (cta *Cryptopia) CancelOrder(orderId string, currency CurrencyPair) (bool, error) {
-	panic("not implements")
+	return false, ErrNotSupported
 }
 
 func (cta *Cryptopia) GetOneOrder(orderId string, currency CurrencyPair) (*Order, error) {
-	panic("not implements")
+	return nil, ErrNotSupported
 }
 func (cta *Cryptopia) GetUnfinishOrders(currency CurrencyPair) ([]Order, error) {
-	panic("not implements")
+	return nil, ErrNotSupported
 }
 
 func (cta *Cryptopia) GetOrderHistorys(currency CurrencyPair, currentPage, pageSize int) ([]Order, error) {
-	panic("not implements")
+	return nil, ErrNotSupported
 }
 
 func (cta *Cryptopia) GetAccount() (*Account, error) {
-	panic("not implements")
+	return nil, ErrNotSupported
+}
+
+//cryptopia answers {"Success":false,"Error":"Market BTC_XYZ not found","Data":null}
+func (cta *Cryptopia) errorWrapper(message string) ApiError {
+	var errCode ApiError
+	msg := strings.ToLower(message)
+	switch {
+	case strings.HasPrefix(msg, "market") && strings.HasSuffix(msg, "not found"):
+		errCode = EX_ERR_INVALID_CURRENCY_PAIR
+	case strings.Contains(msg, "maintenance"):
+		errCode = EX_ERR_MAINTENANCE
+	default:
+		errCode = API_ERR
+	}
+	errCode.OriginErrMsg = message
+	return errCode
 }
@@ -0,0 +1,68 @@
+package cryptopia
+
+import (
+	"context"
+
+	. "github.com/nntaoli-project/GoEx"
+)
+
+var _ API = (*Cryptopia)(nil)
+var _ APIWithContext = (*Cryptopia)(nil)
+
+func (cta *Cryptopia) withContext(ctx context.Context) *Cryptopia {
+	c := *cta
+	c.httpClient = HttpClientWithContext(ctx, cta.httpClient)
+	return &c
+}
+
+func (cta *Cryptopia) LimitBuyCtx(ctx context.Context, amount, price Decimal, currency CurrencyPair) (*Order, error) {
+	return cta.withContext(ctx).LimitBuy(amount, price, currency)
+}
+
+func (cta *Cryptopia) LimitSellCtx(ctx context.Context, amount, price Decimal, currency CurrencyPair) (*Order, error) {
+	return cta.withContext(ctx).LimitSell(amount, price, currency)
+}
+
+func (cta *Cryptopia) MarketBuyCtx(ctx context.Context, amount, price Decimal, currency CurrencyPair) (*Order, error) {
+	return cta.withContext(ctx).MarketBuy(amount, price, currency)
+}
+
+func (cta *Cryptopia) MarketSellCtx(ctx context.Context, amount, price Decimal, currency CurrencyPair) (*Order, error) {
+	return cta.withContext(ctx).MarketSell(amount, price, currency)
+}
+
+func (cta *Cryptopia) CancelOrderCtx(ctx context.Context, orderId string, currency CurrencyPair) (bool, error) {
+	return cta.withContext(ctx).CancelOrder(orderId, currency)
+}
+
+func (cta *Cryptopia) GetOneOrderCtx(ctx context.Context, orderId string, currency CurrencyPair) (*Order, error) {
+	return cta.withContext(ctx).GetOneOrder(orderId, currency)
+}
+
+func (cta *Cryptopia) GetUnfinishOrdersCtx(ctx context.Context, currency CurrencyPair) ([]Order, error) {
+	return cta.withContext(ctx).GetUnfinishOrders(currency)
+}
+
+func (cta *Cryptopia) GetOrderHistorysCtx(ctx context.Context, currency CurrencyPair, currentPage, pageSize int) ([]Order, error) {
+	return cta.withContext(ctx).GetOrderHistorys(currency, currentPage, pageSize)
+}
+
+func (cta *Cryptopia) GetAccountCtx(ctx context.Context) (*Account, error) {
+	return cta.withContext(ctx).GetAccount()
+}
+
+func (cta *Cryptopia) GetTickerCtx(ctx context.Context, currency CurrencyPair) (*Ticker, error) {
+	return cta.withContext(ctx).GetTicker(currency)
+}
+
+func (cta *Cryptopia) GetDepthCtx(ctx context.Context, size int, currency CurrencyPair) (*Depth, error) {
+	return cta.withContext(ctx).GetDepth(size, currency)
+}
+
+func (cta *Cryptopia) GetKlineRecordsCtx(ctx context.Context, currency CurrencyPair, period, size, since int) ([]Kline, error) {
+	return cta.withContext(ctx).GetKlineRecords(currency, period, size, since)
+}
+
+func (cta *Cryptopia) GetTradesCtx(ctx context.Context, currencyPair CurrencyPair, since int64) ([]Trade, error) {
+	return cta.withContext(ctx).GetTrades(currencyPair, since)
+}
@@ -2,14 +2,35 @@ package cryptopia
 
 import (
 	"github.com/nntaoli-project/GoEx"
-	"net/http"
+	"github.com/stretchr/testify/assert"
+	"os"
 	"testing"
 )
 
-var ctp = New(http.DefaultClient, "", "")
+var fixtures = goex.NewFixtureTransport("testdata/fixtures.json")
+var ctp = New(fixtures.Client(), "", "")
 
 func TestCryptopia_GetTicker(t *testing.T) {
 	ticker, err := ctp.GetTicker(goex.BTC_USDT)
-	t.Log("err=>", err)
-	t.Log("ticker=>", ticker)
+	assert.Nil(t, err)
+	assert.Equal(t, goex.RequireDecimal("6270"), ticker.Last)
+	assert.Equal(t, goex.RequireDecimal("6251.00000001"), ticker.Buy)
+	assert.Equal(t, goex.RequireDecimal("6283"), ticker.Sell)
+	assert.Equal(t, goex.RequireDecimal("36.81302655"), ticker.Vol)
+}
+
+func TestMain(m *testing.M) {
+	os.Exit(fixtures.Run(m.Run))
+}
+
+func TestCryptopia_errorWrapper(t *testing.T) {
+	for message, errCode := range map[string]goex.ApiError{
+		"Market BTC_XYZ not found":  goex.EX_ERR_INVALID_CURRENCY_PAIR,
+		"Site is under maintenance": goex.EX_ERR_MAINTENANCE,
+		"Invalid request":           goex.API_ERR,
+	} {
+		err := ctp.errorWrapper(message)
+		assert.True(t, errCode.Is(err), message)
+		assert.Equal(t, message, err.OriginErrMsg)
+	}
 }
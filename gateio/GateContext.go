@@ -0,0 +1,68 @@
+package gateio
+
+import (
+	"context"
+
+	. "github.com/nntaoli-project/GoEx"
+)
+
+var _ API = (*Gate)(nil)
+var _ APIWithContext = (*Gate)(nil)
+
+func (g *Gate) withContext(ctx context.Context) *Gate {
+	c := *g
+	c.client = HttpClientWithContext(ctx, g.client)
+	return &c
+}
+
+func (g *Gate) LimitBuyCtx(ctx context.Context, amount, price Decimal, currency CurrencyPair) (*Order, error) {
+	return g.withContext(ctx).LimitBuy(amount, price, currency)
+}
+
+func (g *Gate) LimitSellCtx(ctx context.Context, amount, price Decimal, currency CurrencyPair) (*Order, error) {
+	return g.withContext(ctx).LimitSell(amount, price, currency)
+}
+
+func (g *Gate) MarketBuyCtx(ctx context.Context, amount, price Decimal, currency CurrencyPair) (*Order, error) {
+	return g.withContext(ctx).MarketBuy(amount, price, currency)
+}
+
+func (g *Gate) MarketSellCtx(ctx context.Context, amount, price Decimal, currency CurrencyPair) (*Order, error) {
+	return g.withContext(ctx).MarketSell(amount, price, currency)
+}
+
+func (g *Gate) CancelOrderCtx(ctx context.Context, orderId string, currency CurrencyPair) (bool, error) {
+	return g.withContext(ctx).CancelOrder(orderId, currency)
+}
+
+func (g *Gate) GetOneOrderCtx(ctx context.Context, orderId string, currency CurrencyPair) (*Order, error) {
+	return g.withContext(ctx).GetOneOrder(orderId, currency)
+}
+
+func (g *Gate) GetUnfinishOrdersCtx(ctx context.Context, currency CurrencyPair) ([]Order, error) {
+	return g.withContext(ctx).GetUnfinishOrders(currency)
+}
+
+func (g *Gate) GetOrderHistorysCtx(ctx context.Context, currency CurrencyPair, currentPage, pageSize int) ([]Order, error) {
+	return g.withContext(ctx).GetOrderHistorys(currency, currentPage, pageSize)
+}
+
+func (g *Gate) GetAccountCtx(ctx context.Context) (*Account, error) {
+	return g.withContext(ctx).GetAccount()
+}
+
+func (g *Gate) GetTickerCtx(ctx context.Context, currency CurrencyPair) (*Ticker, error) {
+	return g.withContext(ctx).GetTicker(currency)
+}
+
+func (g *Gate) GetDepthCtx(ctx context.Context, size int, currency CurrencyPair) (*Depth, error) {
+	return g.withContext(ctx).GetDepth(size, currency)
+}
+
+func (g *Gate) GetKlineRecordsCtx(ctx context.Context, currency CurrencyPair, period, size, since int) ([]Kline, error) {
+	return g.withContext(ctx).GetKlineRecords(currency, period, size, since)
+}
+
+func (g *Gate) GetTradesCtx(ctx context.Context, currencyPair CurrencyPair, since int64) ([]Trade, error) {
+	return g.withContext(ctx).GetTrades(currencyPair, since)
+}
@@ -1,11 +1,13 @@
 package gateio
 
 import (
+	"encoding/json"
 	"fmt"
 	. "github.com/nntaoli-project/GoEx"
 	"net/http"
 	"sort"
 	"strings"
+	"time"
 )
 
 var (
@@ -19,63 +21,72 @@ type Gate struct {
 }
 
 func New(client *http.Client, accesskey, secretkey string) *Gate {
+	client = ExchangeRateLimitedClient(client, "gate.io", accesskey, DefaultRateLimitConfig)
 	return &Gate{client: client, accesskey: accesskey, secretkey: secretkey}
 }
 
-func (g *Gate) LimitBuy(amount, price string, currency CurrencyPair) (*Order, error) {
-	panic("not implement")
+func (g *Gate) LimitBuy(amount, price Decimal, currency CurrencyPair) (*Order, error) {
+	return nil, ErrNotSupported
 }
-func (g *Gate) LimitSell(amount, price string, currency CurrencyPair) (*Order, error) {
-	panic("not implement")
+func (g *Gate) LimitSell(amount, price Decimal, currency CurrencyPair) (*Order, error) {
+	return nil, ErrNotSupported
 }
-func (g *Gate) MarketBuy(amount, price string, currency CurrencyPair) (*Order, error) {
-	panic("not implement")
+func (g *Gate) MarketBuy(amount, price Decimal, currency CurrencyPair) (*Order, error) {
+	return nil, ErrNotSupported
 }
-func (g *Gate) MarketSell(amount, price string, currency CurrencyPair) (*Order, error) {
-	panic("not implement")
+func (g *Gate) MarketSell(amount, price Decimal, currency CurrencyPair) (*Order, error) {
+	return nil, ErrNotSupported
 }
 func (g *Gate) CancelOrder(orderId string, currency CurrencyPair) (bool, error) {
-	panic("not implement")
+	return false, ErrNotSupported
 }
 func (g *Gate) GetOneOrder(orderId string, currency CurrencyPair) (*Order, error) {
-	panic("not implement")
+	return nil, ErrNotSupported
 }
 func (g *Gate) GetUnfinishOrders(currency CurrencyPair) ([]Order, error) {
-	panic("not implement")
+	return nil, ErrNotSupported
 }
 func (g *Gate) GetOrderHistorys(currency CurrencyPair, currentPage, pageSize int) ([]Order, error) {
-	panic("not implement")
+	return nil, ErrNotSupported
 }
 func (g *Gate) GetAccount() (*Account, error) {
-	panic("not implement")
+	return nil, ErrNotSupported
 }
 
 func (g *Gate) GetTicker(currency CurrencyPair) (*Ticker, error) {
+	var dp DecimalParser
 	uri := fmt.Sprintf("%s/ticker/%s", marketBaseUrl, strings.ToLower(currency.ToSymbol("_")))
 
 	resp, err := HttpGet(g.client, uri)
 	if err != nil {
-		errCode := HTTP_ERR_CODE
-		errCode.OriginErrMsg = err.Error()
-		return nil, errCode
+		return nil, g.adaptError(err)
+	}
+	if fmt.Sprint(resp["result"]) == "false" {
+		return nil, g.errorWrapper(resp)
 	}
 
-	return &Ticker{
-		Last: ToFloat64(resp["last"]),
-		Sell: ToFloat64(resp["lowestAsk"]),
-		Buy:  ToFloat64(resp["highestBid"]),
-		High: ToFloat64(resp["high24hr"]),
-		Low:  ToFloat64(resp["low24hr"]),
-		Vol:  ToFloat64(resp["quoteVolume"]),
-	}, nil
+	ticker := &Ticker{
+		Last: dp.Decimal(resp["last"]),
+		Sell: dp.Decimal(resp["lowestAsk"]),
+		Buy:  dp.Decimal(resp["highestBid"]),
+		High: dp.Decimal(resp["high24hr"]),
+		Low:  dp.Decimal(resp["low24hr"]),
+		Vol:  dp.Decimal(resp["quoteVolume"]),
+	}
+	if dp.Err != nil {
+		return nil, dp.Err
+	}
+	return ticker, nil
 }
 
 func (g *Gate) GetDepth(size int, currency CurrencyPair) (*Depth, error) {
+	var dp DecimalParser
 	resp, err := HttpGet(g.client, fmt.Sprintf("%s/orderBook/%s", marketBaseUrl, currency.ToSymbol("_")))
 	if err != nil {
-		errCode := HTTP_ERR_CODE
-		errCode.OriginErrMsg = err.Error()
-		return nil, errCode
+		return nil, g.adaptError(err)
+	}
+	if fmt.Sprint(resp["result"]) == "false" {
+		return nil, g.errorWrapper(resp)
 	}
 
 	bids, _ := resp["bids"].([]interface{})
@@ -85,28 +96,153 @@ func (g *Gate) GetDepth(size int, currency CurrencyPair) (*Depth, error) {
 
 	for _, v := range bids {
 		r := v.([]interface{})
-		dep.BidList = append(dep.BidList, DepthRecord{ToFloat64(r[0]), ToFloat64(r[1])})
+		dep.BidList = append(dep.BidList, DepthRecord{dp.Price(r[0]), dp.Decimal(r[1])})
 	}
 
 	for _, v := range asks {
 		r := v.([]interface{})
-		dep.AskList = append(dep.AskList, DepthRecord{ToFloat64(r[0]), ToFloat64(r[1])})
+		dep.AskList = append(dep.AskList, DepthRecord{dp.Price(r[0]), dp.Decimal(r[1])})
 	}
 
 	sort.Sort(sort.Reverse(dep.AskList))
 
+	if dp.Err != nil {
+		return nil, dp.Err
+	}
 	return dep, nil
 }
 
+//GetKlineRecords reads the candles of the last hours back to since, or as many as size needs when since is 0
 func (g *Gate) GetKlineRecords(currency CurrencyPair, period, size, since int) ([]Kline, error) {
-	panic("not implement")
+	groupSec := int64(KlinePeriodDuration(period) / time.Second)
+	if groupSec <= 0 || period == KLINE_PERIOD_1MONTH || period == KLINE_PERIOD_1YEAR {
+		return nil, UnsupportedKlinePeriod(period)
+	}
+	rangeHour := (groupSec*int64(size) + 3599) / 3600
+	if since > 0 {
+		rangeHour = (time.Now().Unix() - int64(since)/1000 + 3599) / 3600
+	}
+	if rangeHour < 1 {
+		rangeHour = 1
+	}
+
+	uri := fmt.Sprintf("%s/candlestick2/%s?group_sec=%d&range_hour=%d", marketBaseUrl, strings.ToLower(currency.ToSymbol("_")), groupSec, rangeHour)
+	resp, err := HttpGet(g.client, uri)
+	if err != nil {
+		return nil, g.adaptError(err)
+	}
+	if fmt.Sprint(resp["result"]) != "true" {
+		return nil, g.errorWrapper(resp)
+	}
+
+	data, _ := resp["data"].([]interface{})
+	klines := make([]Kline, 0, len(data))
+	for _, v := range data {
+		//[time ms, volume, close, high, low, open]
+		r, ok := v.([]interface{})
+		if !ok || len(r) < 6 {
+			continue
+		}
+		klines = append(klines, Kline{
+			Timestamp: ToInt64(r[0]) / 1000,
+			Vol:       ToFloat64(r[1]),
+			Close:     ToFloat64(r[2]),
+			High:      ToFloat64(r[3]),
+			Low:       ToFloat64(r[4]),
+			Open:      ToFloat64(r[5])})
+	}
+	return KlinesSince(klines, int64(since), size), nil
 }
 
 //非个人，整个交易所的交易记录
+//GetTrades answers the trades after the trade id since, or the latest 80 when since is 0
 func (g *Gate) GetTrades(currencyPair CurrencyPair, since int64) ([]Trade, error) {
-	panic("not implement")
+	var dp DecimalParser
+	uri := fmt.Sprintf("%s/tradeHistory/%s", marketBaseUrl, strings.ToLower(currencyPair.ToSymbol("_")))
+	if since > 0 {
+		uri += fmt.Sprintf("/%d", since)
+	}
+	resp, err := HttpGet(g.client, uri)
+	if err != nil {
+		return nil, g.adaptError(err)
+	}
+	if fmt.Sprint(resp["result"]) != "true" {
+		return nil, g.errorWrapper(resp)
+	}
+
+	data, _ := resp["data"].([]interface{})
+	trades := make([]Trade, 0, len(data))
+	for _, v := range data {
+		t, ok := v.(map[string]interface{})
+		if !ok {
+			continue
+		}
+		trades = append(trades, Trade{
+			Tid:    ToInt64(t["tradeID"]),
+			Type:   fmt.Sprint(t["type"]),
+			Amount: dp.Decimal(t["amount"]),
+			Price:  dp.Decimal(t["rate"]),
+			Date:   ToInt64(t["timestamp"]) * 1000})
+	}
+	if dp.Err != nil {
+		return nil, dp.Err
+	}
+	return TradesSinceTime(trades, 0), nil
+}
+
+//TradesSince tells TradeIterator that GetTrades pages by trade id
+func (g *Gate) TradesSince() TradesSince {
+	return TRADES_SINCE_ID
 }
 
 func (g *Gate) GetExchangeName() string {
 	return "gdax.com"
 }
+
+func (g *Gate) Capabilities() Capabilities {
+	return Capabilities{Kline: true, Trades: true}
+}
+
+//errorWrapper maps {"result":"false","code":7,"message":"Error: invalid currency pair"}
+func (g *Gate) errorWrapper(resp map[string]interface{}) ApiError {
+	message := fmt.Sprint(resp["message"])
+	var errCode ApiError
+	switch ToInt(resp["code"]) {
+	case 4, 15:
+		errCode = EX_ERR_API_LIMIT
+	case 5, 6:
+		errCode = EX_ERR_SIGN
+	case 7, 8, 9:
+		errCode = EX_ERR_INVALID_CURRENCY_PAIR
+	case 16, 17:
+		errCode = EX_ERR_NOT_FIND_ORDER
+	case 18, 20:
+		errCode = EX_ERR_INVALID_PRECISION
+	case 21:
+		errCode = EX_ERR_INSUFFICIENT_BALANCE
+	default:
+		if strings.Contains(strings.ToLower(message), "currency pair") {
+			errCode = EX_ERR_INVALID_CURRENCY_PAIR
+		} else {
+			errCode = API_ERR
+		}
+	}
+	errCode.OriginErrMsg = fmt.Sprintf("[%v]%s", resp["code"], message)
+	return errCode
+}
+
+//gate answers some failures with a non-200 status and the same body, errors before a response are HTTP_ERR_CODE
+func (g *Gate) adaptError(err error) error {
+	apiErr, isok := err.(ApiError)
+	if !isok {
+		errCode := HTTP_ERR_CODE
+		errCode.OriginErrMsg = err.Error()
+		return errCode
+	}
+
+	var resp map[string]interface{}
+	if json.Unmarshal([]byte(apiErr.OriginErrMsg), &resp) != nil || fmt.Sprint(resp["result"]) != "false" {
+		return err
+	}
+	return g.errorWrapper(resp)
+}
@@ -1,23 +1,112 @@
 package gateio
 
 import (
+	"errors"
 	"github.com/nntaoli-project/GoEx"
+	"github.com/stretchr/testify/assert"
 	"net/http"
+	"net/http/httptest"
+	"os"
+	"strings"
 	"testing"
 )
 
-var gate = New(http.DefaultClient, "", "")
+var fixtures = goex.NewFixtureTransport("testdata/fixtures.json")
+var gate = New(fixtures.Client(), "", "")
 
 func TestGate_GetTicker(t *testing.T) {
 	ticker, err := gate.GetTicker(goex.BTC_USDT)
-	t.Log("err=>", err)
-	t.Log("ticker=>", ticker)
+	assert.Nil(t, err)
+	assert.Equal(t, goex.Ticker{Last: goex.RequireDecimal("6270.13"), Buy: goex.RequireDecimal("6270.13"), Sell: goex.RequireDecimal("6275.46"),
+		High: goex.RequireDecimal("6352"), Low: goex.RequireDecimal("6111.16"), Vol: goex.RequireDecimal("665.4306")}, *ticker)
 }
 
 func TestGate_GetDepth(t *testing.T) {
 	dep, err := gate.GetDepth(1, goex.BTC_USDT)
+	assert.Nil(t, err)
+	//the best ask last
+	assert.Equal(t, goex.DepthRecords{
+		{Price: goex.RequireDecimal("6282"), Amount: goex.RequireDecimal("0.05")},
+		{Price: goex.RequireDecimal("6278.5"), Amount: goex.RequireDecimal("0.3312")},
+		{Price: goex.RequireDecimal("6275.46"), Amount: goex.RequireDecimal("0.12")}}, dep.AskList)
+	assert.Equal(t, goex.DepthRecords{
+		{Price: goex.RequireDecimal("6270.13"), Amount: goex.RequireDecimal("0.2")},
+		{Price: goex.RequireDecimal("6268"), Amount: goex.RequireDecimal("1.0007")},
+		{Price: goex.RequireDecimal("6265.2"), Amount: goex.RequireDecimal("0.5")}}, dep.BidList)
+}
+
+func TestMain(m *testing.M) {
+	os.Exit(fixtures.Run(m.Run))
+}
+
+func TestGate_errorWrapper(t *testing.T) {
+	for code, errCode := range map[int]goex.ApiError{
+		4:  goex.EX_ERR_API_LIMIT,
+		5:  goex.EX_ERR_SIGN,
+		7:  goex.EX_ERR_INVALID_CURRENCY_PAIR,
+		16: goex.EX_ERR_NOT_FIND_ORDER,
+		20: goex.EX_ERR_INVALID_PRECISION,
+		21: goex.EX_ERR_INSUFFICIENT_BALANCE,
+		1:  goex.API_ERR,
+	} {
+		err := gate.errorWrapper(map[string]interface{}{"result": "false", "code": float64(code), "message": "Error"})
+		assert.True(t, errCode.Is(err), "%d", code)
+	}
+
+	err := gate.errorWrapper(map[string]interface{}{"result": "false", "message": "Error: invalid currency pair"})
+	assert.True(t, goex.EX_ERR_INVALID_CURRENCY_PAIR.Is(err))
+}
+
+func TestGate_adaptError(t *testing.T) {
+	httpErr := goex.HTTP_ERR_CODE
+	httpErr.OriginErrMsg = `{"result":"false","code":21,"message":"Error: insufficient balance"}`
+	err := gate.adaptError(httpErr)
+	assert.True(t, goex.EX_ERR_INSUFFICIENT_BALANCE.Is(err))
+	assert.Equal(t, "[21]Error: insufficient balance", err.(goex.ApiError).OriginErrMsg)
+
+	assert.True(t, goex.HTTP_ERR_CODE.Is(gate.adaptError(errors.New("connection refused"))))
+}
+
+//rewriteTransport sends every request to the stand-in server
+type rewriteTransport struct {
+	host string
+}
+
+func (rt rewriteTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	req.URL.Scheme = "http"
+	req.URL.Host = rt.host
+	return http.DefaultTransport.RoundTrip(req)
+}
+
+func TestGate_GetTrades(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "/api2/1/tradeHistory/btc_usdt/23549420", r.URL.Path)
+		w.Write([]byte(`{"result":"true","data":[{"tradeID":"23549421","date":"2018-05-01 13:30:12","timestamp":"1525152612",` +
+			`"type":"sell","rate":9250,"amount":0.01,"total":92.5}],"elapsed":"0.012ms"}`))
+	}))
+	defer srv.Close()
+	g := New(&http.Client{Transport: rewriteTransport{strings.TrimPrefix(srv.URL, "http://")}}, "", "")
+
+	trades, err := g.GetTrades(goex.BTC_USDT, 23549420)
+	assert.Nil(t, err)
+	assert.Equal(t, []goex.Trade{{Tid: 23549421, Type: "sell", Amount: goex.RequireDecimal("0.01"), Price: goex.RequireDecimal("9250"),
+		Date: 1525152612000}}, trades)
+}
+
+func TestGate_GetKlineRecords(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "/api2/1/candlestick2/btc_usdt", r.URL.Path)
+		assert.Equal(t, "3600", r.URL.Query().Get("group_sec"))
+		assert.Equal(t, "2", r.URL.Query().Get("range_hour"))
+		w.Write([]byte(`{"result":"true","data":[["1525132800000","12.5","9280","9300","9200","9250"],` +
+			`["1525136400000","8.25","9260","9290","9240","9280"]],"elapsed":"0.012ms"}`))
+	}))
+	defer srv.Close()
+	g := New(&http.Client{Transport: rewriteTransport{strings.TrimPrefix(srv.URL, "http://")}}, "", "")
 
-	t.Log("err=>", err)
-	t.Log("asks=>", dep.AskList)
-	t.Log("bids=>", dep.BidList)
+	klines, err := g.GetKlineRecords(goex.BTC_USDT, goex.KLINE_PERIOD_60MIN, 2, 0)
+	assert.Nil(t, err)
+	assert.Equal(t, []goex.Kline{
+		{Timestamp: 1525132800, Open: 9250, Close: 9280, High: 9300, Low: 9200, Vol: 12.5},
+		{Timestamp: 1525136400, Open: 9280, Close: 9260, High: 9290, Low: 9240, Vol: 8.25}}, klines)
 }
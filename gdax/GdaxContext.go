@@ -0,0 +1,68 @@
+package gdax
+
+import (
+	"context"
+
+	. "github.com/nntaoli-project/GoEx"
+)
+
+var _ API = (*Gdax)(nil)
+var _ APIWithContext = (*Gdax)(nil)
+
+func (g *Gdax) withContext(ctx context.Context) *Gdax {
+	c := *g
+	c.httpClient = HttpClientWithContext(ctx, g.httpClient)
+	return &c
+}
+
+func (g *Gdax) LimitBuyCtx(ctx context.Context, amount, price Decimal, currency CurrencyPair) (*Order, error) {
+	return g.withContext(ctx).LimitBuy(amount, price, currency)
+}
+
+func (g *Gdax) LimitSellCtx(ctx context.Context, amount, price Decimal, currency CurrencyPair) (*Order, error) {
+	return g.withContext(ctx).LimitSell(amount, price, currency)
+}
+
+func (g *Gdax) MarketBuyCtx(ctx context.Context, amount, price Decimal, currency CurrencyPair) (*Order, error) {
+	return g.withContext(ctx).MarketBuy(amount, price, currency)
+}
+
+func (g *Gdax) MarketSellCtx(ctx context.Context, amount, price Decimal, currency CurrencyPair) (*Order, error) {
+	return g.withContext(ctx).MarketSell(amount, price, currency)
+}
+
+func (g *Gdax) CancelOrderCtx(ctx context.Context, orderId string, currency CurrencyPair) (bool, error) {
+	return g.withContext(ctx).CancelOrder(orderId, currency)
+}
+
+func (g *Gdax) GetOneOrderCtx(ctx context.Context, orderId string, currency CurrencyPair) (*Order, error) {
+	return g.withContext(ctx).GetOneOrder(orderId, currency)
+}
+
+func (g *Gdax) GetUnfinishOrdersCtx(ctx context.Context, currency CurrencyPair) ([]Order, error) {
+	return g.withContext(ctx).GetUnfinishOrders(currency)
+}
+
+func (g *Gdax) GetOrderHistorysCtx(ctx context.Context, currency CurrencyPair, currentPage, pageSize int) ([]Order, error) {
+	return g.withContext(ctx).GetOrderHistorys(currency, currentPage, pageSize)
+}
+
+func (g *Gdax) GetAccountCtx(ctx context.Context) (*Account, error) {
+	return g.withContext(ctx).GetAccount()
+}
+
+func (g *Gdax) GetTickerCtx(ctx context.Context, currency CurrencyPair) (*Ticker, error) {
+	return g.withContext(ctx).GetTicker(currency)
+}
+
+func (g *Gdax) GetDepthCtx(ctx context.Context, size int, currency CurrencyPair) (*Depth, error) {
+	return g.withContext(ctx).GetDepth(size, currency)
+}
+
+func (g *Gdax) GetKlineRecordsCtx(ctx context.Context, currency CurrencyPair, period, size, since int) ([]Kline, error) {
+	return g.withContext(ctx).GetKlineRecords(currency, period, size, since)
+}
+
+func (g *Gdax) GetTradesCtx(ctx context.Context, currencyPair CurrencyPair, since int64) ([]Trade, error) {
+	return g.withContext(ctx).GetTrades(currencyPair, since)
+}
@@ -1,10 +1,12 @@
 package gdax
 
 import (
+	"encoding/json"
 	"fmt"
 	. "github.com/nntaoli-project/GoEx"
 	"net/http"
 	"sort"
+	"strings"
 )
 
 //www.coinbase.com or www.gdax.com
@@ -17,69 +19,77 @@ type Gdax struct {
 }
 
 func New(client *http.Client, accesskey, secretkey string) *Gdax {
+	client = ExchangeRateLimitedClient(client, "gdax.com", accesskey, DefaultRateLimitConfig)
 	return &Gdax{client, "https://api.gdax.com", accesskey, secretkey}
 }
 
-func (g *Gdax) LimitBuy(amount, price string, currency CurrencyPair) (*Order, error) {
-	panic("not implement")
+func (g *Gdax) LimitBuy(amount, price Decimal, currency CurrencyPair) (*Order, error) {
+	return nil, ErrNotSupported
 }
-func (g *Gdax) LimitSell(amount, price string, currency CurrencyPair) (*Order, error) {
-	panic("not implement")
+func (g *Gdax) LimitSell(amount, price Decimal, currency CurrencyPair) (*Order, error) {
+	return nil, ErrNotSupported
 }
-func (g *Gdax) MarketBuy(amount, price string, currency CurrencyPair) (*Order, error) {
-	panic("not implement")
+func (g *Gdax) MarketBuy(amount, price Decimal, currency CurrencyPair) (*Order, error) {
+	return nil, ErrNotSupported
 }
-func (g *Gdax) MarketSell(amount, price string, currency CurrencyPair) (*Order, error) {
-	panic("not implement")
+func (g *Gdax) MarketSell(amount, price Decimal, currency CurrencyPair) (*Order, error) {
+	return nil, ErrNotSupported
 }
 func (g *Gdax) CancelOrder(orderId string, currency CurrencyPair) (bool, error) {
-	panic("not implement")
+	return false, ErrNotSupported
 }
 func (g *Gdax) GetOneOrder(orderId string, currency CurrencyPair) (*Order, error) {
-	panic("not implement")
+	return nil, ErrNotSupported
 }
 func (g *Gdax) GetUnfinishOrders(currency CurrencyPair) ([]Order, error) {
-	panic("not implement")
+	return nil, ErrNotSupported
 }
 func (g *Gdax) GetOrderHistorys(currency CurrencyPair, currentPage, pageSize int) ([]Order, error) {
-	panic("not implement")
+	return nil, ErrNotSupported
 }
 func (g *Gdax) GetAccount() (*Account, error) {
-	panic("not implement")
+	return nil, ErrNotSupported
 }
 
 func (g *Gdax) GetTicker(currency CurrencyPair) (*Ticker, error) {
+	var dp DecimalParser
 	resp, err := HttpGet(g.httpClient, fmt.Sprintf("%s/products/%s/ticker", g.baseUrl, currency.ToSymbol("-")))
 	if err != nil {
-		errCode := HTTP_ERR_CODE
-		errCode.OriginErrMsg = err.Error()
-		return nil, errCode
+		return nil, g.adaptError(err)
 	}
 
-	return &Ticker{
-		Last: ToFloat64(resp["price"]),
-		Sell: ToFloat64(resp["ask"]),
-		Buy:  ToFloat64(resp["bid"]),
-		Vol:  ToFloat64(resp["volume"]),
-	}, nil
+	ticker := &Ticker{
+		Last: dp.Decimal(resp["price"]),
+		Sell: dp.Decimal(resp["ask"]),
+		Buy:  dp.Decimal(resp["bid"]),
+		Vol:  dp.Decimal(resp["volume"]),
+	}
+	if dp.Err != nil {
+		return nil, dp.Err
+	}
+	return ticker, nil
 }
 
 func (g *Gdax) Get24HStats(pair CurrencyPair) (*Ticker, error) {
+	var dp DecimalParser
 	resp, err := HttpGet(g.httpClient, fmt.Sprintf("%s/products/%s/stats", g.baseUrl, pair.ToSymbol("-")))
 	if err != nil {
-		errCode := HTTP_ERR_CODE
-		errCode.OriginErrMsg = err.Error()
-		return nil, errCode
+		return nil, g.adaptError(err)
 	}
-	return &Ticker{
-		High: ToFloat64(resp["high"]),
-		Low:  ToFloat64(resp["low"]),
-		Vol:  ToFloat64(resp["volmue"]),
-		Last: ToFloat64(resp["last"]),
-	}, nil
+	ticker := &Ticker{
+		High: dp.Decimal(resp["high"]),
+		Low:  dp.Decimal(resp["low"]),
+		Vol:  dp.Decimal(resp["volume"]),
+		Last: dp.Decimal(resp["last"]),
+	}
+	if dp.Err != nil {
+		return nil, dp.Err
+	}
+	return ticker, nil
 }
 
 func (g *Gdax) GetDepth(size int, currency CurrencyPair) (*Depth, error) {
+	var dp DecimalParser
 	var level int = 2
 	if size == 1 {
 		level = 1
@@ -87,9 +97,7 @@ func (g *Gdax) GetDepth(size int, currency CurrencyPair) (*Depth, error) {
 
 	resp, err := HttpGet(g.httpClient, fmt.Sprintf("%s/products/%s/book?level=%d", g.baseUrl, currency.ToSymbol("-"), level))
 	if err != nil {
-		errCode := HTTP_ERR_CODE
-		errCode.OriginErrMsg = err.Error()
-		return nil, errCode
+		return nil, g.adaptError(err)
 	}
 
 	bids, _ := resp["bids"].([]interface{})
@@ -99,28 +107,70 @@ func (g *Gdax) GetDepth(size int, currency CurrencyPair) (*Depth, error) {
 
 	for _, v := range bids {
 		r := v.([]interface{})
-		dep.BidList = append(dep.BidList, DepthRecord{ToFloat64(r[0]), ToFloat64(r[1])})
+		dep.BidList = append(dep.BidList, DepthRecord{dp.Price(r[0]), dp.Decimal(r[1])})
 	}
 
 	for _, v := range asks {
 		r := v.([]interface{})
-		dep.AskList = append(dep.AskList, DepthRecord{ToFloat64(r[0]), ToFloat64(r[1])})
+		dep.AskList = append(dep.AskList, DepthRecord{dp.Price(r[0]), dp.Decimal(r[1])})
 	}
 
 	sort.Sort(sort.Reverse(dep.AskList))
 
+	if dp.Err != nil {
+		return nil, dp.Err
+	}
 	return dep, nil
 }
 
 func (g *Gdax) GetKlineRecords(currency CurrencyPair, period, size, since int) ([]Kline, error) {
-	panic("not implement")
+	return nil, ErrNotSupported
 }
 
 //非个人，整个交易所的交易记录
 func (g *Gdax) GetTrades(currencyPair CurrencyPair, since int64) ([]Trade, error) {
-	panic("not implement")
+	return nil, ErrNotSupported
 }
 
 func (g *Gdax) GetExchangeName() string {
 	return "gdax.com"
 }
+
+func (g *Gdax) Capabilities() Capabilities {
+	return Capabilities{}
+}
+
+func (g *Gdax) errorWrapper(message string) ApiError {
+	var errCode ApiError
+	msg := strings.ToLower(message)
+	switch {
+	case msg == "notfound", strings.Contains(msg, "not found"):
+		errCode = EX_ERR_INVALID_CURRENCY_PAIR
+	case strings.Contains(msg, "rate limit"):
+		errCode = EX_ERR_API_LIMIT
+	case strings.Contains(msg, "maintenance"), strings.Contains(msg, "unavailable"):
+		errCode = EX_ERR_MAINTENANCE
+	default:
+		errCode = API_ERR
+	}
+	errCode.OriginErrMsg = message
+	return errCode
+}
+
+//gdax answers failures with a non-200 status and {"message":"..."}, errors before a response are HTTP_ERR_CODE
+func (g *Gdax) adaptError(err error) error {
+	apiErr, isok := err.(ApiError)
+	if !isok {
+		errCode := HTTP_ERR_CODE
+		errCode.OriginErrMsg = err.Error()
+		return errCode
+	}
+
+	var resp struct {
+		Message string `json:"message"`
+	}
+	if json.Unmarshal([]byte(apiErr.OriginErrMsg), &resp) != nil || resp.Message == "" {
+		return err
+	}
+	return g.errorWrapper(resp.Message)
+}
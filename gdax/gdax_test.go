@@ -1,28 +1,67 @@
 package gdax
 
 import (
+	"errors"
 	"github.com/nntaoli-project/GoEx"
-	"net/http"
+	"github.com/stretchr/testify/assert"
+	"os"
 	"testing"
 )
 
-var gdax = New(http.DefaultClient, "", "")
+var fixtures = goex.NewFixtureTransport("testdata/fixtures.json")
+var gdax = New(fixtures.Client(), "", "")
 
 func TestGdax_GetTicker(t *testing.T) {
 	ticker, err := gdax.GetTicker(goex.BTC_USD)
-	t.Log("err=>", err)
-	t.Log("ticker=>", ticker)
+	assert.Nil(t, err)
+	assert.Equal(t, goex.Ticker{Last: goex.RequireDecimal("6265.01"), Buy: goex.RequireDecimal("6265"), Sell: goex.RequireDecimal("6265.01"),
+		Vol: goex.RequireDecimal("9113.29412315")}, *ticker)
 }
 
 func TestGdax_Get24HStats(t *testing.T) {
 	stats, err := gdax.Get24HStats(goex.BTC_USD)
-	t.Log("err=>", err)
-	t.Log("stats=>", stats)
+	assert.Nil(t, err)
+	assert.Equal(t, goex.Ticker{Last: goex.RequireDecimal("6265.01"), High: goex.RequireDecimal("6339.99"), Low: goex.RequireDecimal("6085"),
+		Vol: goex.RequireDecimal("9113.29412315")}, *stats)
 }
 
 func TestGdax_GetDepth(t *testing.T) {
 	dep, err := gdax.GetDepth(2, goex.BTC_USD)
-	t.Log("err=>", err)
-	t.Log("bids=>", dep.BidList)
-	t.Log("asks=>", dep.AskList)
+	assert.Nil(t, err)
+	assert.Equal(t, goex.DepthRecords{
+		{Price: goex.RequireDecimal("6265"), Amount: goex.RequireDecimal("4.30157466")},
+		{Price: goex.RequireDecimal("6264.99"), Amount: goex.RequireDecimal("0.001")}}, dep.BidList)
+	assert.Equal(t, goex.DepthRecords{
+		{Price: goex.RequireDecimal("6265.5"), Amount: goex.RequireDecimal("0.2")},
+		{Price: goex.RequireDecimal("6265.01"), Amount: goex.RequireDecimal("1.94416458")}}, dep.AskList)
+}
+
+func TestMain(m *testing.M) {
+	os.Exit(fixtures.Run(m.Run))
+}
+
+func TestGdax_errorWrapper(t *testing.T) {
+	for message, errCode := range map[string]goex.ApiError{
+		"NotFound":                   goex.EX_ERR_INVALID_CURRENCY_PAIR,
+		"Product not found":          goex.EX_ERR_INVALID_CURRENCY_PAIR,
+		"Public rate limit exceeded": goex.EX_ERR_API_LIMIT,
+		"Service unavailable":        goex.EX_ERR_MAINTENANCE,
+		"invalid signature":          goex.API_ERR,
+	} {
+		err := gdax.errorWrapper(message)
+		assert.True(t, errCode.Is(err), message)
+		assert.Equal(t, message, err.OriginErrMsg)
+	}
+}
+
+func TestGdax_adaptError(t *testing.T) {
+	httpErr := goex.HTTP_ERR_CODE
+	httpErr.OriginErrMsg = `{"message":"NotFound"}`
+	assert.True(t, goex.EX_ERR_INVALID_CURRENCY_PAIR.Is(gdax.adaptError(httpErr)))
+
+	limited := goex.EX_ERR_API_LIMIT
+	limited.OriginErrMsg = "too many requests"
+	assert.Equal(t, error(limited), gdax.adaptError(limited))
+
+	assert.True(t, goex.HTTP_ERR_CODE.Is(gdax.adaptError(errors.New("connection refused"))))
 }
@@ -1,12 +1,24 @@
 module github.com/nntaoli-project/GoEx
 
+go 1.27.1
+
 require (
-	bitbucket.org/i0n/compounda v0.0.0-20180217193440-5a99dffdaaff
 	github.com/btcsuite/goleveldb v1.0.0
-	github.com/cenkalti/backoff v2.0.0+incompatible // indirect
+	github.com/gorilla/websocket v1.4.0
 	github.com/i0n/crypto-addresses v0.0.0-20180921005546-a7ef5211c35b
-	github.com/stretchr/objx v0.1.1 // indirect
 	github.com/stretchr/testify v1.2.2
-	golang.org/x/net v0.0.0-20180911220305-26e67e76b6c3 // indirect
 	gopkg.in/yaml.v2 v2.2.1
 )
+
+require (
+	github.com/btcsuite/snappy-go v1.0.0 // indirect
+	github.com/cenkalti/backoff v2.0.0+incompatible // indirect
+	github.com/davecgh/go-spew v1.1.1 // indirect
+	github.com/onsi/ginkgo v1.6.0 // indirect
+	github.com/onsi/gomega v1.4.1 // indirect
+	github.com/pmezard/go-difflib v1.0.0 // indirect
+	github.com/stretchr/objx v0.1.1 // indirect
+	golang.org/x/net v0.0.0-20180911220305-26e67e76b6c3 // indirect
+	golang.org/x/text v0.3.0 // indirect
+	gopkg.in/check.v1 v0.0.0-20161208181325-20d25e280405 // indirect
+)
@@ -9,7 +9,6 @@ import (
 	"net/url"
 	"encoding/json"
 	"strings"
-	"strconv"
 )
 
 const
@@ -33,6 +32,7 @@ type HaoBtc struct {
 }
 
 func New(httpClient *http.Client, accessKey, secretKey string) *HaoBtc {
+	httpClient = ExchangeRateLimitedClient(httpClient, EXCHANGE_NAME, accessKey, DefaultRateLimitConfig);
 	return &HaoBtc{httpClient, accessKey, secretKey};
 }
 
@@ -54,6 +54,7 @@ func (ctx *HaoBtc) buildPostForm(postForm *url.Values) error {
 }
 
 func (ctx *HaoBtc) GetTicker(currency CurrencyPair) (*Ticker, error) {
+	var dp DecimalParser
 	if currency != BTC_CNY {
 		return nil, errors.New("The HaoBtc Unsupport " + currency.String());
 	}
@@ -70,17 +71,21 @@ func (ctx *HaoBtc) GetTicker(currency CurrencyPair) (*Ticker, error) {
 
 	tickerMap = bodyDataMap["ticker"].(map[string]interface{});
 	ticker.Date = uint64(bodyDataMap["date"].(float64));
-	ticker.Last = tickerMap["last"].(float64);
-	ticker.Buy = tickerMap["buy"].(float64);
-	ticker.Sell = tickerMap["sell"].(float64);
-	ticker.Low = tickerMap["low"].(float64);
-	ticker.High = tickerMap["high"].(float64);
-	ticker.Vol = tickerMap["vol"].(float64);
-
+	ticker.Last = dp.Decimal(tickerMap["last"]);
+	ticker.Buy = dp.Decimal(tickerMap["buy"]);
+	ticker.Sell = dp.Decimal(tickerMap["sell"]);
+	ticker.Low = dp.Decimal(tickerMap["low"]);
+	ticker.High = dp.Decimal(tickerMap["high"]);
+	ticker.Vol = dp.Decimal(tickerMap["vol"]);
+
+	if dp.Err != nil {
+		return nil, dp.Err
+	}
 	return &ticker, nil;
 }
 
 func (ctx *HaoBtc) GetDepth(size int, currency CurrencyPair) (*Depth, error) {
+	var dp DecimalParser
 	var depthUri string;
 
 	switch currency {
@@ -103,9 +108,9 @@ func (ctx *HaoBtc) GetDepth(size int, currency CurrencyPair) (*Depth, error) {
 		for i, vv := range v.([]interface{}) {
 			switch i {
 			case 0:
-				dr.Price = vv.(float64);
+				dr.Price = dp.Price(vv);
 			case 1:
-				dr.Amount = vv.(float64);
+				dr.Amount = dp.Decimal(vv);
 			}
 		}
 		depth.AskList = append(depth.AskList, dr);
@@ -116,22 +121,31 @@ func (ctx *HaoBtc) GetDepth(size int, currency CurrencyPair) (*Depth, error) {
 		for i, vv := range v.([]interface{}) {
 			switch i {
 			case 0:
-				dr.Price = vv.(float64);
+				dr.Price = dp.Price(vv);
 			case 1:
-				dr.Amount = vv.(float64);
+				dr.Amount = dp.Decimal(vv);
 			}
 		}
 		depth.BidList = append(depth.BidList, dr);
 	}
 
+	if dp.Err != nil {
+		return nil, dp.Err
+	}
 	return &depth, nil;
 }
 
-func (ctx *HaoBtc) GetKlineRecords(currency CurrencyPair ,period string, size , since int) ([]Kline , error){
-	return nil , errors.New("unimplement the method.");
+func (ctx *HaoBtc) GetKlineRecords(currency CurrencyPair, period, size, since int) ([]Kline, error) {
+	return nil, ErrNotSupported
+}
+
+//非个人，整个交易所的交易记录
+func (ctx *HaoBtc) GetTrades(currencyPair CurrencyPair, since int64) ([]Trade, error) {
+	return nil, ErrNotSupported
 }
 
 func (ctx *HaoBtc) GetAccount() (*Account, error) {
+	var dp DecimalParser
 	postData := url.Values{};
 	ctx.buildPostForm(&postData);
 
@@ -160,21 +174,25 @@ func (ctx *HaoBtc) GetAccount() (*Account, error) {
 	var cnySubAccount SubAccount;
 
 	btcSubAccount.Currency = BTC;
-	btcSubAccount.Amount = bodyDataMap["exchange_btc"].(float64);
-	btcSubAccount.ForzenAmount = bodyDataMap["exchange_frozen_btc"].(float64);
+	btcSubAccount.Amount = dp.Decimal(bodyDataMap["exchange_btc"]);
+	btcSubAccount.FrozenAmount = dp.Decimal(bodyDataMap["exchange_frozen_btc"]);
 
 	cnySubAccount.Currency = CNY;
-	cnySubAccount.Amount = bodyDataMap["exchange_cny"].(float64);
-	cnySubAccount.ForzenAmount = bodyDataMap["exchange_frozen_cny"].(float64);
+	cnySubAccount.Amount = dp.Decimal(bodyDataMap["exchange_cny"]);
+	cnySubAccount.FrozenAmount = dp.Decimal(bodyDataMap["exchange_frozen_cny"]);
 
 	account.SubAccounts = make(map[Currency]SubAccount, 2);
 	account.SubAccounts[BTC] = btcSubAccount;
 	account.SubAccounts[CNY] = cnySubAccount;
 
+	if dp.Err != nil {
+		return nil, dp.Err
+	}
 	return account, nil;
 }
 
 func (ctx *HaoBtc) placeOrder(_type , amount , price string , currency CurrencyPair)(*Order , error){
+	var dp DecimalParser
 	postData := url.Values{};
 	postData.Set("type" , _type);
 	postData.Set("amount" , amount);
@@ -208,8 +226,8 @@ func (ctx *HaoBtc) placeOrder(_type , amount , price string , currency CurrencyP
 
 	order := new(Order);
 	order.OrderID = int(bodyDataMap["order_id"].(float64));
-	order.Price, _ = strconv.ParseFloat(price, 64);
-	order.Amount, _ = strconv.ParseFloat(amount, 64);
+	order.Price = dp.Decimal(price);
+	order.Amount = dp.Decimal(amount);
 	order.Currency = currency;
 	order.Status = ORDER_UNFINISH;
 
@@ -220,16 +238,27 @@ func (ctx *HaoBtc) placeOrder(_type , amount , price string , currency CurrencyP
 		order.Side = BUY;
 	}
 
+	if dp.Err != nil {
+		return nil, dp.Err
+	}
 	return order , nil;
 }
 
 
-func (ctx *HaoBtc) LimitBuy(amount, price string, currency CurrencyPair) (*Order, error){
-	return ctx.placeOrder("buy" , amount , price ,currency);
+func (ctx *HaoBtc) LimitBuy(amount, price Decimal, currency CurrencyPair) (*Order, error){
+	return ctx.placeOrder("buy" , amount.String() , price.String() ,currency);
 }
 
-func (ctx *HaoBtc) LimitSell(amount, price string, currency CurrencyPair) (*Order, error){
-	return ctx.placeOrder("sell" , amount , price , currency);
+func (ctx *HaoBtc) LimitSell(amount, price Decimal, currency CurrencyPair) (*Order, error){
+	return ctx.placeOrder("sell" , amount.String() , price.String() , currency);
+}
+
+func (ctx *HaoBtc) MarketBuy(amount, price Decimal, currency CurrencyPair) (*Order, error) {
+	return nil, ErrNotSupported
+}
+
+func (ctx *HaoBtc) MarketSell(amount, price Decimal, currency CurrencyPair) (*Order, error) {
+	return nil, ErrNotSupported
 }
 
 func (ctx *HaoBtc) CancelOrder(orderId string, currency CurrencyPair) (bool, error)  {
@@ -266,6 +295,7 @@ func (ctx *HaoBtc) CancelOrder(orderId string, currency CurrencyPair) (bool, err
 }
 
 func (ctx *HaoBtc) GetOneOrder(orderId string, currency CurrencyPair) (*Order, error){
+	var dp DecimalParser
 	postData := url.Values{};
 	postData.Set("order_id" , orderId);
 
@@ -303,10 +333,10 @@ func (ctx *HaoBtc) GetOneOrder(orderId string, currency CurrencyPair) (*Order, e
 
 	order := new(Order);
 	order.OrderID = int(bodyDataMap["order_id"].(float64));
-	order.Amount = bodyDataMap["amount"].(float64);
-	order.DealAmount = bodyDataMap["deal_size"].(float64);
-	order.Price =  bodyDataMap["price"].(float64);
-	order.AvgPrice = bodyDataMap["avg_price"].(float64);
+	order.Amount = dp.Decimal(bodyDataMap["amount"]);
+	order.DealAmount = dp.Decimal(bodyDataMap["deal_size"]);
+	order.Price =  dp.Decimal(bodyDataMap["price"]);
+	order.AvgPrice = dp.Decimal(bodyDataMap["avg_price"]);
 
 	status := bodyDataMap["status"].(string);
 	switch status {
@@ -326,10 +356,14 @@ func (ctx *HaoBtc) GetOneOrder(orderId string, currency CurrencyPair) (*Order, e
 		order.Side = SELL;
 	}
 
+	if dp.Err != nil {
+		return nil, dp.Err
+	}
 	return order , nil;
 }
 
 func (ctx *HaoBtc) GetUnfinishOrders(currency CurrencyPair) ([]Order, error) {
+	var dp DecimalParser
 	postData := url.Values{};
 
 	ctx.buildPostForm(&postData);
@@ -359,12 +393,12 @@ func (ctx *HaoBtc) GetUnfinishOrders(currency CurrencyPair) ([]Order, error) {
 		_map := v.(map[string]interface{});
 		order := Order{};
 		order.OrderID = int(_map["order_id"].(float64));
-		order.Amount = _map["amount"].(float64);
-		order.Price = _map["price"].(float64);
-		order.AvgPrice = _map["avg_price"].(float64);
+		order.Amount = dp.Decimal(_map["amount"]);
+		order.Price = dp.Decimal(_map["price"]);
+		order.AvgPrice = dp.Decimal(_map["avg_price"]);
 
-		remainsize := _map["remainsize"].(float64);
-		order.DealAmount = order.Amount - remainsize;
+		remainsize := dp.Decimal(_map["remainsize"]);
+		order.DealAmount = order.Amount.Sub(remainsize);
 
 		status := _map["status"].(string);
 		switch status {
@@ -387,6 +421,9 @@ func (ctx *HaoBtc) GetUnfinishOrders(currency CurrencyPair) ([]Order, error) {
 		orders = append(orders, order);
 	}
 
+	if dp.Err != nil {
+		return nil, dp.Err
+	}
 	return orders, nil;
 }
 
@@ -396,4 +433,8 @@ func (ctx *HaoBtc) GetOrderHistorys(currency CurrencyPair , currentPage , pageSi
 
 func (ctx *HaoBtc) GetExchangeName() string {
 	return EXCHANGE_NAME;
+}
+
+func (ctx *HaoBtc) Capabilities() Capabilities {
+	return Capabilities{}
 }
\ No newline at end of file
@@ -0,0 +1,68 @@
+package haobtc
+
+import (
+	"context"
+
+	. "github.com/nntaoli-project/GoEx"
+)
+
+var _ API = (*HaoBtc)(nil)
+var _ APIWithContext = (*HaoBtc)(nil)
+
+func (hb *HaoBtc) withContext(ctx context.Context) *HaoBtc {
+	c := *hb
+	c.httpClient = HttpClientWithContext(ctx, hb.httpClient)
+	return &c
+}
+
+func (hb *HaoBtc) LimitBuyCtx(ctx context.Context, amount, price Decimal, currency CurrencyPair) (*Order, error) {
+	return hb.withContext(ctx).LimitBuy(amount, price, currency)
+}
+
+func (hb *HaoBtc) LimitSellCtx(ctx context.Context, amount, price Decimal, currency CurrencyPair) (*Order, error) {
+	return hb.withContext(ctx).LimitSell(amount, price, currency)
+}
+
+func (hb *HaoBtc) CancelOrderCtx(ctx context.Context, orderId string, currency CurrencyPair) (bool, error) {
+	return hb.withContext(ctx).CancelOrder(orderId, currency)
+}
+
+func (hb *HaoBtc) GetOneOrderCtx(ctx context.Context, orderId string, currency CurrencyPair) (*Order, error) {
+	return hb.withContext(ctx).GetOneOrder(orderId, currency)
+}
+
+func (hb *HaoBtc) GetUnfinishOrdersCtx(ctx context.Context, currency CurrencyPair) ([]Order, error) {
+	return hb.withContext(ctx).GetUnfinishOrders(currency)
+}
+
+func (hb *HaoBtc) GetOrderHistorysCtx(ctx context.Context, currency CurrencyPair, currentPage, pageSize int) ([]Order, error) {
+	return hb.withContext(ctx).GetOrderHistorys(currency, currentPage, pageSize)
+}
+
+func (hb *HaoBtc) GetAccountCtx(ctx context.Context) (*Account, error) {
+	return hb.withContext(ctx).GetAccount()
+}
+
+func (hb *HaoBtc) GetTickerCtx(ctx context.Context, currency CurrencyPair) (*Ticker, error) {
+	return hb.withContext(ctx).GetTicker(currency)
+}
+
+func (hb *HaoBtc) GetDepthCtx(ctx context.Context, size int, currency CurrencyPair) (*Depth, error) {
+	return hb.withContext(ctx).GetDepth(size, currency)
+}
+
+func (hb *HaoBtc) MarketBuyCtx(ctx context.Context, amount, price Decimal, currency CurrencyPair) (*Order, error) {
+	return hb.withContext(ctx).MarketBuy(amount, price, currency)
+}
+
+func (hb *HaoBtc) MarketSellCtx(ctx context.Context, amount, price Decimal, currency CurrencyPair) (*Order, error) {
+	return hb.withContext(ctx).MarketSell(amount, price, currency)
+}
+
+func (hb *HaoBtc) GetKlineRecordsCtx(ctx context.Context, currency CurrencyPair, period, size, since int) ([]Kline, error) {
+	return hb.withContext(ctx).GetKlineRecords(currency, period, size, since)
+}
+
+func (hb *HaoBtc) GetTradesCtx(ctx context.Context, currencyPair CurrencyPair, since int64) ([]Trade, error) {
+	return hb.withContext(ctx).GetTrades(currencyPair, since)
+}
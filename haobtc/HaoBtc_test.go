@@ -0,0 +1,34 @@
+package haobtc
+
+import (
+	"github.com/nntaoli-project/GoEx"
+	"github.com/stretchr/testify/assert"
+	"os"
+	"testing"
+)
+
+var fixtures = goex.NewFixtureTransport("testdata/fixtures.json")
+var haobtc = New(fixtures.Client(), "", "")
+
+func TestHaoBtc_GetTicker(t *testing.T) {
+	ticker, err := haobtc.GetTicker(goex.BTC_CNY)
+	assert.Nil(t, err)
+	assert.Equal(t, uint64(1529920350), ticker.Date)
+	assert.True(t, ticker.Last.Equal(goex.RequireDecimal("41545.37")))
+	assert.True(t, ticker.Sell.Equal(goex.RequireDecimal("41560")))
+}
+
+func TestHaoBtc_GetDepth(t *testing.T) {
+	dep, err := haobtc.GetDepth(2, goex.BTC_CNY)
+	assert.Nil(t, err)
+	assert.Len(t, dep.AskList, 2)
+	assert.True(t, dep.AskList[1].Price.Equal(goex.RequireDecimal("41575.5")))
+	assert.True(t, dep.BidList[0].Amount.Equal(goex.RequireDecimal("0.08")))
+
+	_, err = haobtc.GetDepth(2, goex.LTC_CNY)
+	assert.NotNil(t, err)
+}
+
+func TestMain(m *testing.M) {
+	os.Exit(fixtures.Run(m.Run))
+}
@@ -1,7 +1,8 @@
 package hitbtc
 
 import (
-	"errors"
+	"encoding/json"
+	"fmt"
 	. "github.com/nntaoli-project/GoEx"
 	//"log"
 	"net/http"
@@ -23,6 +24,7 @@ type Hitbtc struct {
 }
 
 func New(client *http.Client, accessKey, secretKey string) *Hitbtc {
+	client = ExchangeRateLimitedClient(client, EXCHANGE_NAME, accessKey, DefaultRateLimitConfig)
 	return &Hitbtc{accessKey, secretKey, client}
 }
 
@@ -30,7 +32,12 @@ func (hitbtc *Hitbtc) GetExchangeName() string {
 	return EXCHANGE_NAME
 }
 
+func (hitbtc *Hitbtc) Capabilities() Capabilities {
+	return Capabilities{}
+}
+
 func (hitbtc *Hitbtc) GetTicker(currency CurrencyPair) (*Ticker, error) {
+	var dp DecimalParser
 	currency = hitbtc.adaptCurrencyPair(currency)
 	curr := currency.ToSymbol("")
 	tickerUri := API_BASE_URL + API_V2 + TICKER_URI + curr
@@ -39,11 +46,11 @@ func (hitbtc *Hitbtc) GetTicker(currency CurrencyPair) (*Ticker, error) {
 
 	if err != nil {
 		//log.Println(err)
-		return nil, err
+		return nil, hitbtc.adaptError(err)
 	}
 	if result, isok := bodyDataMap["error"].(map[string]interface{}); isok == true {
 		//log.Println("bodyDataMap[\"error\"]", result)
-		return nil, errors.New(result["message"].(string) + ", " + result["description"].(string))
+		return nil, hitbtc.errorWrapper(result)
 	}
 
 	tickerMap := bodyDataMap
@@ -51,15 +58,18 @@ func (hitbtc *Hitbtc) GetTicker(currency CurrencyPair) (*Ticker, error) {
 
 	timestamp := time.Now().Unix()
 	ticker.Date = uint64(timestamp)
-	ticker.Last = ToFloat64(tickerMap["last"])
-	ticker.Buy = ToFloat64(tickerMap["bid"])
-	ticker.Sell = ToFloat64(tickerMap["ask"])
-	ticker.Low = ToFloat64(tickerMap["low"])
-	ticker.High = ToFloat64(tickerMap["high"])
-	ticker.Vol = ToFloat64(tickerMap["volume"])
+	ticker.Last = dp.Decimal(tickerMap["last"])
+	ticker.Buy = dp.Decimal(tickerMap["bid"])
+	ticker.Sell = dp.Decimal(tickerMap["ask"])
+	ticker.Low = dp.Decimal(tickerMap["low"])
+	ticker.High = dp.Decimal(tickerMap["high"])
+	ticker.Vol = dp.Decimal(tickerMap["volume"])
 
 	//log.Println("Hitbtc", currency, "ticker:", ticker)
 
+	if dp.Err != nil {
+		return nil, dp.Err
+	}
 	return &ticker, nil
 }
 
@@ -71,43 +81,43 @@ func (hitbtc *Hitbtc) GetTickerInBuf(currency CurrencyPair) (*Ticker, error) {
 	return hitbtc.GetTicker(currency)
 }
 
-func (hitbtc *Hitbtc) LimitBuy(amount, price string, currency CurrencyPair) (*Order, error) {
-	panic("not implements")
+func (hitbtc *Hitbtc) LimitBuy(amount, price Decimal, currency CurrencyPair) (*Order, error) {
+	return nil, ErrNotSupported
 }
 
-func (hitbtc *Hitbtc) LimitSell(amount, price string, currency CurrencyPair) (*Order, error) {
-	panic("not implements")
+func (hitbtc *Hitbtc) LimitSell(amount, price Decimal, currency CurrencyPair) (*Order, error) {
+	return nil, ErrNotSupported
 }
 
-func (hitbtc *Hitbtc) MarketBuy(amount, price string, currency CurrencyPair) (*Order, error) {
-	panic("not implements")
+func (hitbtc *Hitbtc) MarketBuy(amount, price Decimal, currency CurrencyPair) (*Order, error) {
+	return nil, ErrNotSupported
 }
 
-func (hitbtc *Hitbtc) MarketSell(amount, price string, currency CurrencyPair) (*Order, error) {
-	panic("not implements")
+func (hitbtc *Hitbtc) MarketSell(amount, price Decimal, currency CurrencyPair) (*Order, error) {
+	return nil, ErrNotSupported
 }
 
 func (hitbtc *Hitbtc) CancelOrder(orderId string, currency CurrencyPair) (bool, error) {
-	panic("not implements")
+	return false, ErrNotSupported
 }
 
 func (hitbtc *Hitbtc) GetOneOrder(orderId string, currency CurrencyPair) (*Order, error) {
-	panic("not implements")
+	return nil, ErrNotSupported
 }
 func (hitbtc *Hitbtc) GetUnfinishOrders(currency CurrencyPair) ([]Order, error) {
-	panic("not implements")
+	return nil, ErrNotSupported
 }
 
 func (hitbtc *Hitbtc) GetOrderHistorys(currency CurrencyPair, currentPage, pageSize int) ([]Order, error) {
-	panic("not implements")
+	return nil, ErrNotSupported
 }
 
 func (hitbtc *Hitbtc) GetAccount() (*Account, error) {
-	panic("not implements")
+	return nil, ErrNotSupported
 }
 
 func (hitbtc *Hitbtc) GetDepth(size int, currency CurrencyPair) (*Depth, error) {
-	panic("not implement")
+	return nil, ErrNotSupported
 }
 func (hitbtc *Hitbtc) adaptCurrencyPair(pair CurrencyPair) CurrencyPair {
 	var currencyA Currency
@@ -129,10 +139,53 @@ func (hitbtc *Hitbtc) adaptCurrencyPair(pair CurrencyPair) CurrencyPair {
 }
 
 func (hitbtc *Hitbtc) GetKlineRecords(currency CurrencyPair, period, size, since int) ([]Kline, error) {
-	panic("not implements")
+	return nil, ErrNotSupported
 }
 
 //非个人，整个交易所的交易记录
 func (hitbtc *Hitbtc) GetTrades(currencyPair CurrencyPair, since int64) ([]Trade, error) {
-	panic("not implements")
+	return nil, ErrNotSupported
+}
+
+//errorWrapper maps {"error":{"code":2001,"message":"Symbol not found","description":"..."}}
+func (hitbtc *Hitbtc) errorWrapper(result map[string]interface{}) ApiError {
+	var errCode ApiError
+	switch ToInt(result["code"]) {
+	case 429, 20003:
+		errCode = EX_ERR_API_LIMIT
+	case 503, 504:
+		errCode = EX_ERR_MAINTENANCE
+	case 1001, 1003:
+		errCode = EX_ERR_NOT_FIND_APIKEY
+	case 1002:
+		errCode = EX_ERR_SIGN
+	case 2001, 2002:
+		errCode = EX_ERR_INVALID_CURRENCY_PAIR
+	case 20001:
+		errCode = EX_ERR_INSUFFICIENT_BALANCE
+	case 20002:
+		errCode = EX_ERR_NOT_FIND_ORDER
+	case 20008:
+		errCode = EX_ERR_INVALID_CLIENT_ORDER_ID
+	default:
+		errCode = API_ERR
+	}
+	errCode.OriginErrMsg = fmt.Sprint(result["message"], ", ", result["description"])
+	return errCode
+}
+
+//hitbtc sends the error body with a 4xx or 5xx status
+func (hitbtc *Hitbtc) adaptError(err error) error {
+	apiErr, isok := err.(ApiError)
+	if !isok {
+		return err
+	}
+
+	var resp struct {
+		Error map[string]interface{} `json:"error"`
+	}
+	if json.Unmarshal([]byte(apiErr.OriginErrMsg), &resp) != nil || resp.Error == nil {
+		return err
+	}
+	return hitbtc.errorWrapper(resp.Error)
 }
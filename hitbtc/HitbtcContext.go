@@ -0,0 +1,68 @@
+package hitbtc
+
+import (
+	"context"
+
+	. "github.com/nntaoli-project/GoEx"
+)
+
+var _ API = (*Hitbtc)(nil)
+var _ APIWithContext = (*Hitbtc)(nil)
+
+func (hitbtc *Hitbtc) withContext(ctx context.Context) *Hitbtc {
+	c := *hitbtc
+	c.httpClient = HttpClientWithContext(ctx, hitbtc.httpClient)
+	return &c
+}
+
+func (hitbtc *Hitbtc) LimitBuyCtx(ctx context.Context, amount, price Decimal, currency CurrencyPair) (*Order, error) {
+	return hitbtc.withContext(ctx).LimitBuy(amount, price, currency)
+}
+
+func (hitbtc *Hitbtc) LimitSellCtx(ctx context.Context, amount, price Decimal, currency CurrencyPair) (*Order, error) {
+	return hitbtc.withContext(ctx).LimitSell(amount, price, currency)
+}
+
+func (hitbtc *Hitbtc) MarketBuyCtx(ctx context.Context, amount, price Decimal, currency CurrencyPair) (*Order, error) {
+	return hitbtc.withContext(ctx).MarketBuy(amount, price, currency)
+}
+
+func (hitbtc *Hitbtc) MarketSellCtx(ctx context.Context, amount, price Decimal, currency CurrencyPair) (*Order, error) {
+	return hitbtc.withContext(ctx).MarketSell(amount, price, currency)
+}
+
+func (hitbtc *Hitbtc) CancelOrderCtx(ctx context.Context, orderId string, currency CurrencyPair) (bool, error) {
+	return hitbtc.withContext(ctx).CancelOrder(orderId, currency)
+}
+
+func (hitbtc *Hitbtc) GetOneOrderCtx(ctx context.Context, orderId string, currency CurrencyPair) (*Order, error) {
+	return hitbtc.withContext(ctx).GetOneOrder(orderId, currency)
+}
+
+func (hitbtc *Hitbtc) GetUnfinishOrdersCtx(ctx context.Context, currency CurrencyPair) ([]Order, error) {
+	return hitbtc.withContext(ctx).GetUnfinishOrders(currency)
+}
+
+func (hitbtc *Hitbtc) GetOrderHistorysCtx(ctx context.Context, currency CurrencyPair, currentPage, pageSize int) ([]Order, error) {
+	return hitbtc.withContext(ctx).GetOrderHistorys(currency, currentPage, pageSize)
+}
+
+func (hitbtc *Hitbtc) GetAccountCtx(ctx context.Context) (*Account, error) {
+	return hitbtc.withContext(ctx).GetAccount()
+}
+
+func (hitbtc *Hitbtc) GetTickerCtx(ctx context.Context, currency CurrencyPair) (*Ticker, error) {
+	return hitbtc.withContext(ctx).GetTicker(currency)
+}
+
+func (hitbtc *Hitbtc) GetDepthCtx(ctx context.Context, size int, currency CurrencyPair) (*Depth, error) {
+	return hitbtc.withContext(ctx).GetDepth(size, currency)
+}
+
+func (hitbtc *Hitbtc) GetKlineRecordsCtx(ctx context.Context, currency CurrencyPair, period, size, since int) ([]Kline, error) {
+	return hitbtc.withContext(ctx).GetKlineRecords(currency, period, size, since)
+}
+
+func (hitbtc *Hitbtc) GetTradesCtx(ctx context.Context, currencyPair CurrencyPair, since int64) ([]Trade, error) {
+	return hitbtc.withContext(ctx).GetTrades(currencyPair, since)
+}
@@ -2,14 +2,53 @@ package hitbtc
 
 import (
 	"github.com/nntaoli-project/GoEx"
-	"net/http"
+	"github.com/stretchr/testify/assert"
+	"os"
 	"testing"
 )
 
-var htb = New(http.DefaultClient, "", "")
+var fixtures = goex.NewFixtureTransport("testdata/fixtures.json")
+var htb = New(fixtures.Client(), "", "")
 
 func TestHitbtc_GetTicker(t *testing.T) {
 	ticker, err := htb.GetTicker(goex.BTC_USD)
-	t.Log("err=>", err)
-	t.Log("ticker=>", ticker)
+	assert.Nil(t, err)
+	assert.Equal(t, goex.RequireDecimal("6288.5"), ticker.Last)
+	assert.Equal(t, goex.RequireDecimal("6287.97"), ticker.Buy)
+	assert.Equal(t, goex.RequireDecimal("6288.5"), ticker.Sell)
+	assert.Equal(t, goex.RequireDecimal("6110"), ticker.Low)
+	assert.Equal(t, goex.RequireDecimal("6347.57"), ticker.High)
+	assert.Equal(t, goex.RequireDecimal("5210.08"), ticker.Vol)
+}
+
+func TestMain(m *testing.M) {
+	os.Exit(fixtures.Run(m.Run))
+}
+
+func TestHitbtc_errorWrapper(t *testing.T) {
+	for code, errCode := range map[int]goex.ApiError{
+		429:   goex.EX_ERR_API_LIMIT,
+		503:   goex.EX_ERR_MAINTENANCE,
+		1002:  goex.EX_ERR_SIGN,
+		1003:  goex.EX_ERR_NOT_FIND_APIKEY,
+		2001:  goex.EX_ERR_INVALID_CURRENCY_PAIR,
+		20001: goex.EX_ERR_INSUFFICIENT_BALANCE,
+		20002: goex.EX_ERR_NOT_FIND_ORDER,
+		20008: goex.EX_ERR_INVALID_CLIENT_ORDER_ID,
+		10001: goex.API_ERR,
+	} {
+		err := htb.errorWrapper(map[string]interface{}{"code": float64(code), "message": "Error", "description": ""})
+		assert.True(t, errCode.Is(err), "%d", code)
+	}
+}
+
+func TestHitbtc_adaptError(t *testing.T) {
+	httpErr := goex.HTTP_ERR_CODE
+	httpErr.OriginErrMsg = `{"error":{"code":2001,"message":"Symbol not found","description":"Try get /api/2/public/symbol, to get list of all available symbols."}}`
+	err := htb.adaptError(httpErr)
+	assert.True(t, goex.EX_ERR_INVALID_CURRENCY_PAIR.Is(err))
+	assert.Contains(t, err.Error(), "Symbol not found")
+
+	httpErr.OriginErrMsg = "{}"
+	assert.Equal(t, error(httpErr), htb.adaptError(httpErr))
 }
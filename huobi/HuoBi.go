@@ -41,6 +41,7 @@ var _INERNAL_KLINE_PERIOD_CONVERTER = map[int]string{
 }
 
 func New(httpClient *http.Client, accessKey, secretKey string) *HuoBi {
+	httpClient = ExchangeRateLimitedClient(httpClient, EXCHANGE_NAME, accessKey, DefaultRateLimitConfig)
 	return &HuoBi{httpClient, accessKey, secretKey}
 }
 
@@ -61,7 +62,12 @@ func (hb *HuoBi) GetExchangeName() string {
 	return EXCHANGE_NAME
 }
 
+func (hb *HuoBi) Capabilities() Capabilities {
+	return Capabilities{MarketOrder: true, Kline: true, Trades: true}
+}
+
 func (hb *HuoBi) GetTicker(currency CurrencyPair) (*Ticker, error) {
+	var dp DecimalParser
 	var tickerUri string
 
 	switch currency {
@@ -90,17 +96,21 @@ func (hb *HuoBi) GetTicker(currency CurrencyPair) (*Ticker, error) {
 	}
 
 	ticker.Date, _ = strconv.ParseUint(bodyDataMap["time"].(string), 10, 64)
-	ticker.Last = tickerMap["last"].(float64)
-	ticker.Buy = tickerMap["buy"].(float64)
-	ticker.Sell = tickerMap["sell"].(float64)
-	ticker.Low = tickerMap["low"].(float64)
-	ticker.High = tickerMap["high"].(float64)
-	ticker.Vol = tickerMap["vol"].(float64)
+	ticker.Last = dp.Decimal(tickerMap["last"])
+	ticker.Buy = dp.Decimal(tickerMap["buy"])
+	ticker.Sell = dp.Decimal(tickerMap["sell"])
+	ticker.Low = dp.Decimal(tickerMap["low"])
+	ticker.High = dp.Decimal(tickerMap["high"])
+	ticker.Vol = dp.Decimal(tickerMap["vol"])
 
+	if dp.Err != nil {
+		return nil, dp.Err
+	}
 	return &ticker, nil
 }
 
 func (hb *HuoBi) GetDepth(size int, currency CurrencyPair) (*Depth, error) {
+	var dp DecimalParser
 	var depthUri string
 
 	switch currency {
@@ -138,9 +148,9 @@ func (hb *HuoBi) GetDepth(size int, currency CurrencyPair) (*Depth, error) {
 		for i, vv := range ask.([]interface{}) {
 			switch i {
 			case 0:
-				dr.Price = vv.(float64)
+				dr.Price = dp.Price(vv)
 			case 1:
-				dr.Amount = vv.(float64)
+				dr.Amount = dp.Decimal(vv)
 			}
 		}
 		depth.AskList = append(depth.AskList, dr)
@@ -151,18 +161,22 @@ func (hb *HuoBi) GetDepth(size int, currency CurrencyPair) (*Depth, error) {
 		for i, vv := range v.([]interface{}) {
 			switch i {
 			case 0:
-				dr.Price = vv.(float64)
+				dr.Price = dp.Price(vv)
 			case 1:
-				dr.Amount = vv.(float64)
+				dr.Amount = dp.Decimal(vv)
 			}
 		}
 		depth.BidList = append(depth.BidList, dr)
 	}
 
+	if dp.Err != nil {
+		return nil, dp.Err
+	}
 	return &depth, nil
 }
 
 func (hb *HuoBi) GetAccount() (*Account, error) {
+	var dp DecimalParser
 	postData := url.Values{}
 	postData.Set("method", "get_account_info")
 	postData.Set("created", fmt.Sprintf("%d", time.Now().Unix()))
@@ -199,29 +213,33 @@ func (hb *HuoBi) GetAccount() (*Account, error) {
 	var cnySubAccount SubAccount
 
 	btcSubAccount.Currency = BTC
-	btcSubAccount.Amount, _ = strconv.ParseFloat(bodyDataMap["available_btc_display"].(string), 64)
-	btcSubAccount.LoanAmount, _ = strconv.ParseFloat(bodyDataMap["loan_btc_display"].(string), 64)
-	btcSubAccount.ForzenAmount, _ = strconv.ParseFloat(bodyDataMap["frozen_btc_display"].(string), 64)
+	btcSubAccount.Amount = dp.Decimal(bodyDataMap["available_btc_display"])
+	btcSubAccount.LoanAmount = dp.Decimal(bodyDataMap["loan_btc_display"])
+	btcSubAccount.FrozenAmount = dp.Decimal(bodyDataMap["frozen_btc_display"])
 
 	ltcSubAccount.Currency = LTC
-	ltcSubAccount.Amount, _ = strconv.ParseFloat(bodyDataMap["available_ltc_display"].(string), 64)
-	ltcSubAccount.LoanAmount, _ = strconv.ParseFloat(bodyDataMap["loan_ltc_display"].(string), 64)
-	ltcSubAccount.ForzenAmount, _ = strconv.ParseFloat(bodyDataMap["frozen_ltc_display"].(string), 64)
+	ltcSubAccount.Amount = dp.Decimal(bodyDataMap["available_ltc_display"])
+	ltcSubAccount.LoanAmount = dp.Decimal(bodyDataMap["loan_ltc_display"])
+	ltcSubAccount.FrozenAmount = dp.Decimal(bodyDataMap["frozen_ltc_display"])
 
 	cnySubAccount.Currency = CNY
-	cnySubAccount.Amount, _ = strconv.ParseFloat(bodyDataMap["available_cny_display"].(string), 64)
-	cnySubAccount.LoanAmount, _ = strconv.ParseFloat(bodyDataMap["loan_cny_display"].(string), 64)
-	cnySubAccount.ForzenAmount, _ = strconv.ParseFloat(bodyDataMap["frozen_cny_display"].(string), 64)
+	cnySubAccount.Amount = dp.Decimal(bodyDataMap["available_cny_display"])
+	cnySubAccount.LoanAmount = dp.Decimal(bodyDataMap["loan_cny_display"])
+	cnySubAccount.FrozenAmount = dp.Decimal(bodyDataMap["frozen_cny_display"])
 
 	account.SubAccounts = make(map[Currency]SubAccount, 3)
 	account.SubAccounts[BTC] = btcSubAccount
 	account.SubAccounts[LTC] = ltcSubAccount
 	account.SubAccounts[CNY] = cnySubAccount
 
+	if dp.Err != nil {
+		return nil, dp.Err
+	}
 	return account, nil
 }
 
 func (hb *HuoBi) GetOneOrder(orderId string, currency CurrencyPair) (*Order, error) {
+	var dp DecimalParser
 	postData := url.Values{}
 	postData.Set("method", "order_info")
 	postData.Set("id", orderId)
@@ -249,7 +267,7 @@ func (hb *HuoBi) GetOneOrder(orderId string, currency CurrencyPair) (*Order, err
 	}
 
 	if bodyDataMap["code"] != nil {
-		return nil, errors.New(string(bodyData))
+		return nil, hb.errorWrapper(bodyDataMap, string(bodyData))
 	}
 
 	//fmt.Println(bodyDataMap);
@@ -257,11 +275,11 @@ func (hb *HuoBi) GetOneOrder(orderId string, currency CurrencyPair) (*Order, err
 	order.Currency = currency
 	order.OrderID, _ = strconv.Atoi(orderId)
 	order.Side = TradeSide(bodyDataMap["type"].(float64))
-	order.Amount, _ = strconv.ParseFloat(bodyDataMap["order_amount"].(string), 64)
-	order.DealAmount, _ = strconv.ParseFloat(bodyDataMap["processed_amount"].(string), 64)
-	order.Price, _ = strconv.ParseFloat(bodyDataMap["order_price"].(string), 64)
-	order.AvgPrice, _ = strconv.ParseFloat(bodyDataMap["processed_price"].(string), 64)
-	order.Fee, _ = strconv.ParseFloat(bodyDataMap["fee"].(string), 64)
+	order.Amount = dp.Decimal(bodyDataMap["order_amount"])
+	order.DealAmount = dp.Decimal(bodyDataMap["processed_amount"])
+	order.Price = dp.Decimal(bodyDataMap["order_price"])
+	order.AvgPrice = dp.Decimal(bodyDataMap["processed_price"])
+	order.Fee = dp.Decimal(bodyDataMap["fee"])
 
 	tradeStatus := TradeStatus(bodyDataMap["status"].(float64))
 	switch tradeStatus {
@@ -275,10 +293,14 @@ func (hb *HuoBi) GetOneOrder(orderId string, currency CurrencyPair) (*Order, err
 		order.Status = ORDER_CANCEL
 	}
 	//fmt.Println(order)
+	if dp.Err != nil {
+		return nil, dp.Err
+	}
 	return order, nil
 }
 
 func (hb *HuoBi) GetUnfinishOrders(currency CurrencyPair) ([]Order, error) {
+	var dp DecimalParser
 	postData := url.Values{}
 	postData.Set("method", "get_orders")
 
@@ -312,19 +334,23 @@ func (hb *HuoBi) GetUnfinishOrders(currency CurrencyPair) ([]Order, error) {
 	for _, v := range bodyDataMap {
 		order := Order{}
 		order.Currency = currency
-		order.Amount, _ = strconv.ParseFloat(v["order_amount"].(string), 64)
-		order.Price, _ = strconv.ParseFloat(v["order_price"].(string), 64)
-		order.DealAmount, _ = strconv.ParseFloat(v["processed_amount"].(string), 64)
+		order.Amount = dp.Decimal(v["order_amount"])
+		order.Price = dp.Decimal(v["order_price"])
+		order.DealAmount = dp.Decimal(v["processed_amount"])
 		order.OrderTime = int(v["order_time"].(float64))
 		order.OrderID = int(v["id"].(float64))
 		order.Side = TradeSide(v["type"].(float64))
 		orders = append(orders, order)
 	}
 
+	if dp.Err != nil {
+		return nil, dp.Err
+	}
 	return orders, nil
 }
 
 func (hb *HuoBi) placeOrder(method, amount, price string, currency CurrencyPair) (*Order, error) {
+	var dp DecimalParser
 	postData := url.Values{}
 	postData.Set("method", method)
 
@@ -363,7 +389,7 @@ func (hb *HuoBi) placeOrder(method, amount, price string, currency CurrencyPair)
 	}
 
 	if bodyDataMap["code"] != nil {
-		return nil, errors.New(string(bodyData))
+		return nil, hb.errorWrapper(bodyDataMap, string(bodyData))
 	}
 
 	ret := bodyDataMap["result"].(string)
@@ -371,18 +397,21 @@ func (hb *HuoBi) placeOrder(method, amount, price string, currency CurrencyPair)
 	if strings.Compare(ret, "success") == 0 {
 		order := new(Order)
 		order.OrderID = int(bodyDataMap["id"].(float64))
-		order.Price, _ = strconv.ParseFloat(price, 64)
-		order.Amount, _ = strconv.ParseFloat(amount, 64)
+		order.Price = dp.Decimal(price)
+		order.Amount = dp.Decimal(amount)
 		order.Currency = currency
 		order.Status = ORDER_UNFINISH
+		if dp.Err != nil {
+			return nil, dp.Err
+		}
 		return order, nil
 	}
 
 	return nil, errors.New(fmt.Sprintf("Place Limit %s Order Fail.", method))
 }
 
-func (hb *HuoBi) LimitBuy(amount, price string, currency CurrencyPair) (*Order, error) {
-	order, err := hb.placeOrder("buy", amount, price, currency)
+func (hb *HuoBi) LimitBuy(amount, price Decimal, currency CurrencyPair) (*Order, error) {
+	order, err := hb.placeOrder("buy", amount.String(), price.String(), currency)
 
 	if err != nil {
 		return nil, err
@@ -393,8 +422,8 @@ func (hb *HuoBi) LimitBuy(amount, price string, currency CurrencyPair) (*Order,
 	return order, nil
 }
 
-func (hb *HuoBi) LimitSell(amount, price string, currency CurrencyPair) (*Order, error) {
-	order, err := hb.placeOrder("sell", amount, price, currency)
+func (hb *HuoBi) LimitSell(amount, price Decimal, currency CurrencyPair) (*Order, error) {
+	order, err := hb.placeOrder("sell", amount.String(), price.String(), currency)
 
 	if err != nil {
 		return nil, err
@@ -405,8 +434,8 @@ func (hb *HuoBi) LimitSell(amount, price string, currency CurrencyPair) (*Order,
 	return order, nil
 }
 
-func (hb *HuoBi) MarketBuy(amount, price string, currency CurrencyPair) (*Order, error) {
-	order, err := hb.placeOrder("buy_market", amount, price, currency)
+func (hb *HuoBi) MarketBuy(amount, price Decimal, currency CurrencyPair) (*Order, error) {
+	order, err := hb.placeOrder("buy_market", amount.String(), price.String(), currency)
 	if err != nil {
 		log.Println(err)
 		return nil, err
@@ -415,8 +444,8 @@ func (hb *HuoBi) MarketBuy(amount, price string, currency CurrencyPair) (*Order,
 	return order, nil
 }
 
-func (hb *HuoBi) MarketSell(amount, price string, currency CurrencyPair) (*Order, error) {
-	order, err := hb.placeOrder("sell_market", amount, price, currency)
+func (hb *HuoBi) MarketSell(amount, price Decimal, currency CurrencyPair) (*Order, error) {
+	order, err := hb.placeOrder("sell_market", amount.String(), price.String(), currency)
 	if err != nil {
 		log.Println(err)
 		return nil, err
@@ -456,7 +485,7 @@ func (hb *HuoBi) CancelOrder(orderId string, currency CurrencyPair) (bool, error
 	}
 
 	if bodyDataMap["code"] != nil {
-		return false, errors.New(string(bodyData))
+		return false, hb.errorWrapper(bodyDataMap, string(bodyData))
 	}
 
 	ret := bodyDataMap["result"].(string)
@@ -483,7 +512,7 @@ func (hb *HuoBi) GetKlineRecords(currency CurrencyPair, period , size, since int
 		return nil, errors.New("Unsupport " + currency.String())
 	}
 	//println(klineUri)
-	resp, err := http.Get(klineUri)
+	resp, err := hb.httpClient.Get(klineUri)
 
 	if err != nil {
 		return nil, err
@@ -538,13 +567,14 @@ func (hb *HuoBi) GetKlineRecords(currency CurrencyPair, period , size, since int
 }
 
 func (hb *HuoBi) GetOrderHistorys(currency CurrencyPair, currentPage, pageSize int) ([]Order, error) {
-	return nil, nil
+	return nil, ErrNotSupported
 }
 
 /**
  * 获取全站最近的交易记录
  */
 func (hb *HuoBi) GetTrades(currencyPair CurrencyPair, since int64) ([]Trade, error) {
+	var dp DecimalParser
 	tradeUrl := API_BASE_URL + trade_url
 	switch currencyPair {
 	case BTC_CNY:
@@ -557,7 +587,7 @@ func (hb *HuoBi) GetTrades(currencyPair CurrencyPair, since int64) ([]Trade, err
 
 	var respmap map[string]interface{}
 
-	resp, err := http.Get(tradeUrl)
+	resp, err := hb.httpClient.Get(tradeUrl)
 	if err != nil {
 		return nil, err
 	}
@@ -579,8 +609,8 @@ func (hb *HuoBi) GetTrades(currencyPair CurrencyPair, since int64) ([]Trade, err
 	for _, t := range tradesmap {
 		tr := t.(map[string]interface{})
 		trade := Trade{}
-		trade.Amount = tr["amount"].(float64)
-		trade.Price = tr["price"].(float64)
+		trade.Amount = dp.Decimal(tr["amount"])
+		trade.Price = dp.Decimal(tr["price"])
 		trade.Type = tr["type"].(string)
 		timeStr := tr["time"].(string)
 		timeMeta := strings.Split(timeStr, ":")
@@ -604,5 +634,30 @@ func (hb *HuoBi) GetTrades(currencyPair CurrencyPair, since int64) ([]Trade, err
 
 	//fmt.Println(tradesmap)
 
+	if dp.Err != nil {
+		return nil, dp.Err
+	}
 	return trades, nil
 }
+
+func (hb *HuoBi) errorWrapper(bodyDataMap map[string]interface{}, originErrMsg string) ApiError {
+	msg, _ := bodyDataMap["msg"].(string)
+
+	var errCode ApiError
+	switch {
+	case strings.Contains(msg, "频繁"):
+		errCode = EX_ERR_API_LIMIT
+	case strings.Contains(msg, "不足"), strings.Contains(msg, "没有足够"):
+		errCode = EX_ERR_INSUFFICIENT_BALANCE
+	case strings.Contains(msg, "不存在"):
+		errCode = EX_ERR_NOT_FIND_ORDER
+	case strings.Contains(msg, "不能取消"):
+		errCode = EX_ERR_CANCEL_ORDER_FAIL
+	case strings.Contains(msg, "签名"):
+		errCode = EX_ERR_SIGN
+	default:
+		errCode = API_ERR
+	}
+	errCode.OriginErrMsg = originErrMsg
+	return errCode
+}
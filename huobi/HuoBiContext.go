@@ -0,0 +1,132 @@
+package huobi
+
+import (
+	"context"
+
+	. "github.com/nntaoli-project/GoEx"
+)
+
+var (
+	_ API            = (*HuoBi)(nil)
+	_ APIWithContext = (*HuoBi)(nil)
+	_ API            = (*HuoBi_V2)(nil)
+	_ APIWithContext = (*HuoBi_V2)(nil)
+	_ API            = (*HuobiPro)(nil)
+	_ APIWithContext = (*HuobiPro)(nil)
+)
+
+func (hb *HuoBi) withContext(ctx context.Context) *HuoBi {
+	c := *hb
+	c.httpClient = HttpClientWithContext(ctx, hb.httpClient)
+	return &c
+}
+
+func (hb *HuoBi) LimitBuyCtx(ctx context.Context, amount, price Decimal, currency CurrencyPair) (*Order, error) {
+	return hb.withContext(ctx).LimitBuy(amount, price, currency)
+}
+
+func (hb *HuoBi) LimitSellCtx(ctx context.Context, amount, price Decimal, currency CurrencyPair) (*Order, error) {
+	return hb.withContext(ctx).LimitSell(amount, price, currency)
+}
+
+func (hb *HuoBi) MarketBuyCtx(ctx context.Context, amount, price Decimal, currency CurrencyPair) (*Order, error) {
+	return hb.withContext(ctx).MarketBuy(amount, price, currency)
+}
+
+func (hb *HuoBi) MarketSellCtx(ctx context.Context, amount, price Decimal, currency CurrencyPair) (*Order, error) {
+	return hb.withContext(ctx).MarketSell(amount, price, currency)
+}
+
+func (hb *HuoBi) CancelOrderCtx(ctx context.Context, orderId string, currency CurrencyPair) (bool, error) {
+	return hb.withContext(ctx).CancelOrder(orderId, currency)
+}
+
+func (hb *HuoBi) GetOneOrderCtx(ctx context.Context, orderId string, currency CurrencyPair) (*Order, error) {
+	return hb.withContext(ctx).GetOneOrder(orderId, currency)
+}
+
+func (hb *HuoBi) GetUnfinishOrdersCtx(ctx context.Context, currency CurrencyPair) ([]Order, error) {
+	return hb.withContext(ctx).GetUnfinishOrders(currency)
+}
+
+func (hb *HuoBi) GetOrderHistorysCtx(ctx context.Context, currency CurrencyPair, currentPage, pageSize int) ([]Order, error) {
+	return hb.withContext(ctx).GetOrderHistorys(currency, currentPage, pageSize)
+}
+
+func (hb *HuoBi) GetAccountCtx(ctx context.Context) (*Account, error) {
+	return hb.withContext(ctx).GetAccount()
+}
+
+func (hb *HuoBi) GetTickerCtx(ctx context.Context, currency CurrencyPair) (*Ticker, error) {
+	return hb.withContext(ctx).GetTicker(currency)
+}
+
+func (hb *HuoBi) GetDepthCtx(ctx context.Context, size int, currency CurrencyPair) (*Depth, error) {
+	return hb.withContext(ctx).GetDepth(size, currency)
+}
+
+func (hb *HuoBi) GetKlineRecordsCtx(ctx context.Context, currency CurrencyPair, period, size, since int) ([]Kline, error) {
+	return hb.withContext(ctx).GetKlineRecords(currency, period, size, since)
+}
+
+func (hb *HuoBi) GetTradesCtx(ctx context.Context, currencyPair CurrencyPair, since int64) ([]Trade, error) {
+	return hb.withContext(ctx).GetTrades(currencyPair, since)
+}
+
+func (hbV2 *HuoBi_V2) withContext(ctx context.Context) *HuoBi_V2 {
+	c := *hbV2
+	c.httpClient = HttpClientWithContext(ctx, hbV2.httpClient)
+	return &c
+}
+
+func (hbV2 *HuoBi_V2) LimitBuyCtx(ctx context.Context, amount, price Decimal, currency CurrencyPair) (*Order, error) {
+	return hbV2.withContext(ctx).LimitBuy(amount, price, currency)
+}
+
+func (hbV2 *HuoBi_V2) LimitSellCtx(ctx context.Context, amount, price Decimal, currency CurrencyPair) (*Order, error) {
+	return hbV2.withContext(ctx).LimitSell(amount, price, currency)
+}
+
+func (hbV2 *HuoBi_V2) MarketBuyCtx(ctx context.Context, amount, price Decimal, currency CurrencyPair) (*Order, error) {
+	return hbV2.withContext(ctx).MarketBuy(amount, price, currency)
+}
+
+func (hbV2 *HuoBi_V2) MarketSellCtx(ctx context.Context, amount, price Decimal, currency CurrencyPair) (*Order, error) {
+	return hbV2.withContext(ctx).MarketSell(amount, price, currency)
+}
+
+func (hbV2 *HuoBi_V2) CancelOrderCtx(ctx context.Context, orderId string, currency CurrencyPair) (bool, error) {
+	return hbV2.withContext(ctx).CancelOrder(orderId, currency)
+}
+
+func (hbV2 *HuoBi_V2) GetOneOrderCtx(ctx context.Context, orderId string, currency CurrencyPair) (*Order, error) {
+	return hbV2.withContext(ctx).GetOneOrder(orderId, currency)
+}
+
+func (hbV2 *HuoBi_V2) GetUnfinishOrdersCtx(ctx context.Context, currency CurrencyPair) ([]Order, error) {
+	return hbV2.withContext(ctx).GetUnfinishOrders(currency)
+}
+
+func (hbV2 *HuoBi_V2) GetOrderHistorysCtx(ctx context.Context, currency CurrencyPair, currentPage, pageSize int) ([]Order, error) {
+	return hbV2.withContext(ctx).GetOrderHistorys(currency, currentPage, pageSize)
+}
+
+func (hbV2 *HuoBi_V2) GetAccountCtx(ctx context.Context) (*Account, error) {
+	return hbV2.withContext(ctx).GetAccount()
+}
+
+func (hbV2 *HuoBi_V2) GetTickerCtx(ctx context.Context, currency CurrencyPair) (*Ticker, error) {
+	return hbV2.withContext(ctx).GetTicker(currency)
+}
+
+func (hbV2 *HuoBi_V2) GetDepthCtx(ctx context.Context, size int, currency CurrencyPair) (*Depth, error) {
+	return hbV2.withContext(ctx).GetDepth(size, currency)
+}
+
+func (hbV2 *HuoBi_V2) GetKlineRecordsCtx(ctx context.Context, currency CurrencyPair, period, size, since int) ([]Kline, error) {
+	return hbV2.withContext(ctx).GetKlineRecords(currency, period, size, since)
+}
+
+func (hbV2 *HuoBi_V2) GetTradesCtx(ctx context.Context, currencyPair CurrencyPair, since int64) ([]Trade, error) {
+	return hbV2.withContext(ctx).GetTrades(currencyPair, since)
+}
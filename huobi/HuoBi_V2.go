@@ -7,6 +7,8 @@ import (
 	. "github.com/nntaoli-project/GoEx"
 	"net/http"
 	"net/url"
+	"sort"
+	"strconv"
 	"strings"
 	"time"
 )
@@ -17,6 +19,7 @@ type HuoBi_V2 struct {
 	baseUrl,
 	accessKey,
 	secretKey string
+	symbols *SymbolInfoCache
 }
 
 type response struct {
@@ -27,7 +30,8 @@ type response struct {
 }
 
 func NewV2(httpClient *http.Client, accessKey, secretKey, clientId string) *HuoBi_V2 {
-	return &HuoBi_V2{httpClient, clientId, "https://be.huobi.com", accessKey, secretKey}
+	httpClient = ExchangeRateLimitedClient(httpClient, "huobi.com", accessKey, DefaultRateLimitConfig)
+	return &HuoBi_V2{httpClient: httpClient, accountId: clientId, baseUrl: "https://be.huobi.com", accessKey: accessKey, secretKey: secretKey, symbols: new(SymbolInfoCache)}
 }
 
 func (hbV2 *HuoBi_V2) GetAccountId() (string, error) {
@@ -43,7 +47,7 @@ func (hbV2 *HuoBi_V2) GetAccountId() (string, error) {
 	}
 	//log.Println(respmap)
 	if respmap["status"].(string) != "ok" {
-		return "", errors.New(respmap["err-code"].(string))
+		return "", hbV2.errorWrapper(respmap)
 	}
 
 	data := respmap["data"].([]interface{})
@@ -55,6 +59,7 @@ func (hbV2 *HuoBi_V2) GetAccountId() (string, error) {
 }
 
 func (hbV2 *HuoBi_V2) GetAccount() (*Account, error) {
+	var dp DecimalParser
 	path := fmt.Sprintf("/v1/account/accounts/%s/balance", hbV2.accountId)
 	params := &url.Values{}
 	params.Set("accountId-id", hbV2.accountId)
@@ -71,7 +76,7 @@ func (hbV2 *HuoBi_V2) GetAccount() (*Account, error) {
 	//log.Println(respmap)
 
 	if respmap["status"].(string) != "ok" {
-		return nil, errors.New(respmap["err-code"].(string))
+		return nil, hbV2.errorWrapper(respmap)
 	}
 
 	datamap := respmap["data"].(map[string]interface{})
@@ -91,7 +96,7 @@ func (hbV2 *HuoBi_V2) GetAccount() (*Account, error) {
 		currencySymbol := balancemap["currency"].(string)
 		currency := NewCurrency(currencySymbol, "")
 		typeStr := balancemap["type"].(string)
-		balance := ToFloat64(balancemap["balance"])
+		balance := dp.Decimal(balancemap["balance"])
 		if subAccMap[currency] == nil {
 			subAccMap[currency] = new(SubAccount)
 		}
@@ -100,7 +105,7 @@ func (hbV2 *HuoBi_V2) GetAccount() (*Account, error) {
 		case "trade":
 			subAccMap[currency].Amount = balance
 		case "frozen":
-			subAccMap[currency].ForzenAmount = balance
+			subAccMap[currency].FrozenAmount = balance
 		}
 	}
 
@@ -108,16 +113,22 @@ func (hbV2 *HuoBi_V2) GetAccount() (*Account, error) {
 		acc.SubAccounts[k] = *v
 	}
 
+	if dp.Err != nil {
+		return nil, dp.Err
+	}
 	return acc, nil
 }
 
-func (hbV2 *HuoBi_V2) placeOrder(amount, price string, pair CurrencyPair, orderType string) (string, error) {
+func (hbV2 *HuoBi_V2) placeOrder(amount, price string, pair CurrencyPair, orderType, clientOrderId string) (string, error) {
 	path := "/v1/order/orders/place"
 	params := url.Values{}
 	params.Set("account-id", hbV2.accountId)
 	params.Set("amount", amount)
 	params.Set("symbol", strings.ToLower(pair.ToSymbol("")))
 	params.Set("type", orderType)
+	if clientOrderId != "" {
+		params.Set("client-order-id", clientOrderId)
+	}
 
 	switch orderType {
 	case "buy-limit", "sell-limit":
@@ -139,73 +150,112 @@ func (hbV2 *HuoBi_V2) placeOrder(amount, price string, pair CurrencyPair, orderT
 	}
 
 	if respmap["status"].(string) != "ok" {
-		return "", errors.New(respmap["err-code"].(string))
+		return "", hbV2.errorWrapper(respmap)
 	}
 
 	return respmap["data"].(string), nil
 }
 
-func (hbV2 *HuoBi_V2) LimitBuy(amount, price string, currency CurrencyPair) (*Order, error) {
-	orderId, err := hbV2.placeOrder(amount, price, currency, "buy-limit")
+func (hbV2 *HuoBi_V2) LimitBuy(amount, price Decimal, currency CurrencyPair) (*Order, error) {
+	orderId, err := hbV2.placeOrder(amount.String(), price.String(), currency, "buy-limit", "")
 	if err != nil {
 		return nil, err
 	}
 	return &Order{
 		Currency: currency,
 		OrderID:  ToInt(orderId),
-		Amount:   ToFloat64(amount),
-		Price:    ToFloat64(price),
+		Amount:   amount,
+		Price:    price,
 		Side:     BUY}, nil
 }
 
-func (hbV2 *HuoBi_V2) LimitSell(amount, price string, currency CurrencyPair) (*Order, error) {
-	orderId, err := hbV2.placeOrder(amount, price, currency, "sell-limit")
+func (hbV2 *HuoBi_V2) LimitSell(amount, price Decimal, currency CurrencyPair) (*Order, error) {
+	orderId, err := hbV2.placeOrder(amount.String(), price.String(), currency, "sell-limit", "")
 	if err != nil {
 		return nil, err
 	}
 	return &Order{
 		Currency: currency,
 		OrderID:  ToInt(orderId),
-		Amount:   ToFloat64(amount),
-		Price:    ToFloat64(price),
+		Amount:   amount,
+		Price:    price,
 		Side:     SELL}, nil
 }
 
-func (hbV2 *HuoBi_V2) MarketBuy(amount, price string, currency CurrencyPair) (*Order, error) {
-	orderId, err := hbV2.placeOrder(amount, price, currency, "buy-market")
+func (hbV2 *HuoBi_V2) MarketBuy(amount, price Decimal, currency CurrencyPair) (*Order, error) {
+	orderId, err := hbV2.placeOrder(amount.String(), price.String(), currency, "buy-market", "")
 	if err != nil {
 		return nil, err
 	}
 	return &Order{
 		Currency: currency,
 		OrderID:  ToInt(orderId),
-		Amount:   ToFloat64(amount),
-		Price:    ToFloat64(price),
+		Amount:   amount,
+		Price:    price,
 		Side:     BUY_MARKET}, nil
 }
 
-func (hbV2 *HuoBi_V2) MarketSell(amount, price string, currency CurrencyPair) (*Order, error) {
-	orderId, err := hbV2.placeOrder(amount, price, currency, "sell-market")
+func (hbV2 *HuoBi_V2) MarketSell(amount, price Decimal, currency CurrencyPair) (*Order, error) {
+	orderId, err := hbV2.placeOrder(amount.String(), price.String(), currency, "sell-market", "")
 	if err != nil {
 		return nil, err
 	}
 	return &Order{
 		Currency: currency,
 		OrderID:  ToInt(orderId),
-		Amount:   ToFloat64(amount),
-		Price:    ToFloat64(price),
+		Amount:   amount,
+		Price:    price,
 		Side:     SELL_MARKET}, nil
 }
 
-func (hbV2 *HuoBi_V2) parseOrder(ordmap map[string]interface{}) Order {
+//isClientOrderId tells a client-order-id from Huobi's own order ids, which are integers
+func isClientOrderId(orderId string) bool {
+	_, err := strconv.ParseInt(orderId, 10, 64)
+	return err != nil
+}
+
+//PlaceOrderWithClientID places the order with client-order-id, GetOneOrder and CancelOrder take it in place of the order id
+func (hbV2 *HuoBi_V2) PlaceOrderWithClientID(clientOrderId string, side TradeSide, amount, price Decimal, currency CurrencyPair) (*Order, error) {
+	if !ValidClientOrderID(clientOrderId) {
+		errCode := EX_ERR_INVALID_CLIENT_ORDER_ID
+		errCode.OriginErrMsg = clientOrderId
+		return nil, errCode
+	}
+	var orderType string
+	switch side {
+	case BUY:
+		orderType = "buy-limit"
+	case SELL:
+		orderType = "sell-limit"
+	case BUY_MARKET:
+		orderType = "buy-market"
+	case SELL_MARKET:
+		orderType = "sell-market"
+	}
+	orderId, err := hbV2.placeOrder(amount.String(), price.String(), currency, orderType, clientOrderId)
+	if err != nil {
+		return nil, err
+	}
+	return &Order{
+		Currency:      currency,
+		OrderID:       ToInt(orderId),
+		ClientOrderID: clientOrderId,
+		Amount:        amount,
+		Price:         price,
+		Side:          side}, nil
+}
+
+func (hbV2 *HuoBi_V2) parseOrder(ordmap map[string]interface{}) (Order, error) {
+	var dp DecimalParser
 	ord := Order{
 		OrderID:    ToInt(ordmap["id"]),
-		Amount:     ToFloat64(ordmap["amount"]),
-		Price:      ToFloat64(ordmap["price"]),
-		DealAmount: ToFloat64(ordmap["field-amount"]),
-		Fee:        ToFloat64(ordmap["field-fees"]),
+		Amount:     dp.Decimal(ordmap["amount"]),
+		Price:      dp.Decimal(ordmap["price"]),
+		DealAmount: dp.Decimal(ordmap["field-amount"]),
+		Fee:        dp.Decimal(ordmap["field-fees"]),
 		OrderTime:  ToInt(ordmap["created-at"]),
 	}
+	ord.ClientOrderID, _ = ordmap["client-order-id"].(string)
 
 	state := ordmap["state"].(string)
 	switch state {
@@ -221,8 +271,8 @@ func (hbV2 *HuoBi_V2) parseOrder(ordmap map[string]interface{}) Order {
 		ord.Status = ORDER_UNFINISH
 	}
 
-	if ord.DealAmount > 0.0 {
-		ord.AvgPrice = ToFloat64(ordmap["field-cash-amount"]) / ord.DealAmount
+	if ord.DealAmount.Sign() > 0 {
+		ord.AvgPrice = dp.Decimal(ordmap["field-cash-amount"]).Div(ord.DealAmount)
 	}
 
 	typeS := ordmap["type"].(string)
@@ -236,12 +286,16 @@ func (hbV2 *HuoBi_V2) parseOrder(ordmap map[string]interface{}) Order {
 	case "sell-market":
 		ord.Side = SELL_MARKET
 	}
-	return ord
+	return ord, dp.Err
 }
 
 func (hbV2 *HuoBi_V2) GetOneOrder(orderId string, currency CurrencyPair) (*Order, error) {
 	path := "/v1/order/orders/" + orderId
 	params := url.Values{}
+	if isClientOrderId(orderId) {
+		path = "/v1/order/orders/getClientOrder"
+		params.Set("clientOrderId", orderId)
+	}
 	hbV2.buildPostForm("GET", path, &params)
 	respmap, err := HttpGet(hbV2.httpClient, hbV2.baseUrl+path+"?"+params.Encode())
 	if err != nil {
@@ -249,11 +303,14 @@ func (hbV2 *HuoBi_V2) GetOneOrder(orderId string, currency CurrencyPair) (*Order
 	}
 
 	if respmap["status"].(string) != "ok" {
-		return nil, errors.New(respmap["err-code"].(string))
+		return nil, hbV2.errorWrapper(respmap)
 	}
 
 	datamap := respmap["data"].(map[string]interface{})
-	order := hbV2.parseOrder(datamap)
+	order, err := hbV2.parseOrder(datamap)
+	if err != nil {
+		return nil, err
+	}
 	order.Currency = currency
 	//log.Println(respmap)
 	return &order, nil
@@ -271,14 +328,17 @@ func (hbV2 *HuoBi_V2) GetUnfinishOrders(currency CurrencyPair) ([]Order, error)
 	}
 
 	if respmap["status"].(string) != "ok" {
-		return nil, errors.New(respmap["err-code"].(string))
+		return nil, hbV2.errorWrapper(respmap)
 	}
 
 	datamap := respmap["data"].([]interface{})
 	var orders []Order
 	for _, v := range datamap {
 		ordmap := v.(map[string]interface{})
-		ord := hbV2.parseOrder(ordmap)
+		ord, err := hbV2.parseOrder(ordmap)
+		if err != nil {
+			return nil, err
+		}
 		ord.Currency = currency
 		orders = append(orders, ord)
 	}
@@ -289,9 +349,79 @@ func (hbV2 *HuoBi_V2) GetUnfinishOrders(currency CurrencyPair) ([]Order, error)
 	return orders, nil
 }
 
+//GetMyTrades pages matchresults from the newest back to since, 100 fills a request
+func (hbV2 *HuoBi_V2) GetMyTrades(pair CurrencyPair, since int64, limit int) ([]MyTrade, error) {
+	var dp DecimalParser
+	const pageSize = 100
+	path := "/v1/order/matchresults"
+	var trades []MyTrade
+	from := ""
+	for {
+		params := url.Values{}
+		params.Set("symbol", strings.ToLower(pair.ToSymbol("")))
+		params.Set("start-date", time.Unix(since/1000, 0).UTC().Format("2006-01-02"))
+		params.Set("size", strconv.Itoa(pageSize))
+		if from != "" {
+			params.Set("from", from)
+			params.Set("direct", "next")
+		}
+		hbV2.buildPostForm("GET", path, &params)
+		respmap, err := HttpGet(hbV2.httpClient, hbV2.baseUrl+path+"?"+params.Encode())
+		if err != nil {
+			return nil, err
+		}
+
+		if respmap["status"].(string) != "ok" {
+			return nil, hbV2.errorWrapper(respmap)
+		}
+
+		datamap, _ := respmap["data"].([]interface{})
+		oldest := int64(-1)
+		for _, v := range datamap {
+			fillmap := v.(map[string]interface{})
+			t := MyTrade{
+				TradeID:  fmt.Sprint(ToInt64(fillmap["id"])),
+				OrderID:  fmt.Sprint(ToInt64(fillmap["order-id"])),
+				Currency: pair,
+				Side:     SELL,
+				Price:    dp.Decimal(fillmap["price"]),
+				Amount:   dp.Decimal(fillmap["filled-amount"]),
+				Fee:      dp.Decimal(fillmap["filled-fees"]),
+				IsMaker:  fillmap["role"] == "maker",
+				Time:     ToInt64(fillmap["created-at"])}
+			//a buy pays in the currency bought, a sell in the one received for it
+			if strings.HasPrefix(fmt.Sprint(fillmap["type"]), "buy") {
+				t.Side = BUY
+				t.FeeCurrency = pair.CurrencyA
+			} else {
+				t.FeeCurrency = pair.CurrencyB
+			}
+			if feeCurrency, _ := fillmap["fee-currency"].(string); feeCurrency != "" {
+				t.FeeCurrency = NewCurrency(feeCurrency, "")
+			}
+			trades = append(trades, t)
+			from = t.TradeID
+			if oldest < 0 || t.Time < oldest {
+				oldest = t.Time
+			}
+		}
+		if len(datamap) < pageSize || oldest < since {
+			break
+		}
+	}
+	if dp.Err != nil {
+		return nil, dp.Err
+	}
+	return MyTradesSince(trades, since, limit), nil
+}
+
 func (hbV2 *HuoBi_V2) CancelOrder(orderId string, currency CurrencyPair) (bool, error) {
 	path := fmt.Sprintf("/v1/order/orders/%s/submitcancel", orderId)
 	params := url.Values{}
+	if isClientOrderId(orderId) {
+		path = "/v1/order/orders/submitCancelClientOrder"
+		params.Set("client-order-id", orderId)
+	}
 	hbV2.buildPostForm("POST", path, &params)
 	resp, err := HttpPostForm3(hbV2.httpClient, hbV2.baseUrl+path+"?"+params.Encode(), hbV2.toJson(params),
 		map[string]string{"Content-Type": "application/json", "Accept-Language": "zh-cn"})
@@ -306,21 +436,26 @@ func (hbV2 *HuoBi_V2) CancelOrder(orderId string, currency CurrencyPair) (bool,
 	}
 
 	if respmap["status"].(string) != "ok" {
-		return false, errors.New(string(resp))
+		return false, hbV2.errorWrapper(respmap)
 	}
 
 	return true, nil
 }
 
 func (hbV2 *HuoBi_V2) GetOrderHistorys(currency CurrencyPair, currentPage, pageSize int) ([]Order, error) {
-	panic("not implement")
+	return nil, ErrNotSupported
 }
 
 func (hbV2 *HuoBi_V2) GetExchangeName() string {
 	return "huobi.com"
 }
 
+func (hbV2 *HuoBi_V2) Capabilities() Capabilities {
+	return Capabilities{MarketOrder: true, Deposit: true, ClientOrder: true, TradeFee: true, MyTrades: true}
+}
+
 func (hbV2 *HuoBi_V2) GetTicker(currencyPair CurrencyPair) (*Ticker, error) {
+	var dp DecimalParser
 	url := hbV2.baseUrl + "/market/detail/merged?symbol=" + strings.ToLower(currencyPair.ToSymbol(""))
 	respmap, err := HttpGet(hbV2.httpClient, url)
 	if err != nil {
@@ -328,7 +463,7 @@ func (hbV2 *HuoBi_V2) GetTicker(currencyPair CurrencyPair) (*Ticker, error) {
 	}
 
 	if respmap["status"].(string) == "error" {
-		return nil, errors.New(respmap["err-msg"].(string))
+		return nil, hbV2.errorWrapper(respmap)
 	}
 
 	tickmap, ok := respmap["tick"].(map[string]interface{})
@@ -337,9 +472,9 @@ func (hbV2 *HuoBi_V2) GetTicker(currencyPair CurrencyPair) (*Ticker, error) {
 	}
 
 	ticker := new(Ticker)
-	ticker.Vol = ToFloat64(tickmap["amount"])
-	ticker.Low = ToFloat64(tickmap["low"])
-	ticker.High = ToFloat64(tickmap["high"])
+	ticker.Vol = dp.Decimal(tickmap["amount"])
+	ticker.Low = dp.Decimal(tickmap["low"])
+	ticker.High = dp.Decimal(tickmap["high"])
 	bid, isOk := tickmap["bid"].([]interface{})
 	if isOk != true {
 		return nil, errors.New("no bid")
@@ -348,15 +483,19 @@ func (hbV2 *HuoBi_V2) GetTicker(currencyPair CurrencyPair) (*Ticker, error) {
 	if isOk != true {
 		return nil, errors.New("no ask")
 	}
-	ticker.Buy = ToFloat64(bid[0])
-	ticker.Sell = ToFloat64(ask[0])
-	ticker.Last = ToFloat64(tickmap["close"])
+	ticker.Buy = dp.Decimal(bid[0])
+	ticker.Sell = dp.Decimal(ask[0])
+	ticker.Last = dp.Decimal(tickmap["close"])
 	ticker.Date = ToUint64(respmap["ts"])
 
+	if dp.Err != nil {
+		return nil, dp.Err
+	}
 	return ticker, nil
 }
 
 func (hbV2 *HuoBi_V2) GetDepth(size int, currency CurrencyPair) (*Depth, error) {
+	var dp DecimalParser
 	url := hbV2.baseUrl + "/market/depth?symbol=%s&type=step0"
 	respmap, err := HttpGet(hbV2.httpClient, fmt.Sprintf(url, strings.ToLower(currency.ToSymbol(""))))
 	if err != nil {
@@ -364,7 +503,7 @@ func (hbV2 *HuoBi_V2) GetDepth(size int, currency CurrencyPair) (*Depth, error)
 	}
 
 	if "ok" != respmap["status"].(string) {
-		return nil, errors.New(respmap["err-msg"].(string))
+		return nil, hbV2.errorWrapper(respmap)
 	}
 
 	tick, _ := respmap["tick"].(map[string]interface{})
@@ -376,8 +515,8 @@ func (hbV2 *HuoBi_V2) GetDepth(size int, currency CurrencyPair) (*Depth, error)
 	for _, r := range asks {
 		var dr DepthRecord
 		rr := r.([]interface{})
-		dr.Price = ToFloat64(rr[0])
-		dr.Amount = ToFloat64(rr[1])
+		dr.Price = dp.Price(rr[0])
+		dr.Amount = dp.Decimal(rr[1])
 		depth.AskList = append(depth.AskList, dr)
 
 		_size--
@@ -390,8 +529,8 @@ func (hbV2 *HuoBi_V2) GetDepth(size int, currency CurrencyPair) (*Depth, error)
 	for _, r := range bids {
 		var dr DepthRecord
 		rr := r.([]interface{})
-		dr.Price = ToFloat64(rr[0])
-		dr.Amount = ToFloat64(rr[1])
+		dr.Price = dp.Price(rr[0])
+		dr.Amount = dp.Decimal(rr[1])
 		depth.BidList = append(depth.BidList, dr)
 
 		_size--
@@ -400,16 +539,186 @@ func (hbV2 *HuoBi_V2) GetDepth(size int, currency CurrencyPair) (*Depth, error)
 		}
 	}
 
+	if dp.Err != nil {
+		return nil, dp.Err
+	}
 	return depth, nil
 }
 
 func (hbV2 *HuoBi_V2) GetKlineRecords(currency CurrencyPair, period, size, since int) ([]Kline, error) {
-	panic("not implement")
+	return nil, ErrNotSupported
 }
 
 //非个人，整个交易所的交易记录
 func (hbV2 *HuoBi_V2) GetTrades(currencyPair CurrencyPair, since int64) ([]Trade, error) {
-	panic("not implement")
+	return nil, ErrNotSupported
+}
+
+//GetMarkets huobi gives the precisions as numbers of decimal places, the steps follow from them
+func (hbV2 *HuoBi_V2) GetMarkets() ([]SymbolInfo, error) {
+	respData, err := NewHttpRequest(hbV2.httpClient, "GET", hbV2.baseUrl+"/v1/common/symbols", "", nil)
+	if err != nil {
+		return nil, err
+	}
+
+	var resp response
+	err = json.Unmarshal(respData, &resp)
+	if err != nil {
+		return nil, errors.New(string(respData))
+	}
+	if resp.Status != "ok" {
+		return nil, hbV2.errorWrapper(map[string]interface{}{"err-code": resp.Errcode, "err-msg": resp.Errmsg})
+	}
+
+	var symbols []struct {
+		BaseCurrency    string  `json:"base-currency"`
+		QuoteCurrency   string  `json:"quote-currency"`
+		PricePrecision  int32   `json:"price-precision"`
+		AmountPrecision int32   `json:"amount-precision"`
+		Symbol          string  `json:"symbol"`
+		State           string  `json:"state"`
+		MinOrderAmt     Decimal `json:"min-order-amt"`
+		MaxOrderAmt     Decimal `json:"max-order-amt"`
+		MinOrderValue   Decimal `json:"min-order-value"`
+	}
+	err = json.Unmarshal(resp.Data, &symbols)
+	if err != nil {
+		return nil, err
+	}
+
+	markets := make([]SymbolInfo, 0, len(symbols))
+	for _, sym := range symbols {
+		if sym.State == "offline" {
+			continue
+		}
+		pair := NewCurrencyPair(NewCurrency(sym.BaseCurrency, ""), NewCurrency(sym.QuoteCurrency, ""))
+		symbol := sym.Symbol
+		if symbol == "" {
+			symbol = strings.ToLower(pair.ToSymbol(""))
+		}
+		markets = append(markets, SymbolInfo{
+			Pair:            pair,
+			Symbol:          symbol,
+			PricePrecision:  sym.PricePrecision,
+			AmountPrecision: sym.AmountPrecision,
+			TickSize:        NewDecimal(1, -sym.PricePrecision),
+			StepSize:        NewDecimal(1, -sym.AmountPrecision),
+			MinAmount:       sym.MinOrderAmt,
+			MaxAmount:       sym.MaxOrderAmt,
+			MinNotional:     sym.MinOrderValue})
+	}
+	return markets, nil
+}
+
+func (hbV2 *HuoBi_V2) GetSymbolInfo(pair CurrencyPair) (*SymbolInfo, error) {
+	return hbV2.symbols.Get(pair, hbV2.GetMarkets)
+}
+
+func (hbV2 *HuoBi_V2) GetDepositAddress(currency Currency) (*CryptoAddress, error) {
+	path := "/v2/account/deposit/address"
+	params := url.Values{}
+	params.Set("currency", strings.ToLower(currency.Symbol))
+	hbV2.buildPostForm("GET", path, &params)
+	respmap, err := HttpGet(hbV2.httpClient, hbV2.baseUrl+path+"?"+params.Encode())
+	if err != nil {
+		return nil, err
+	}
+
+	//the v2 api answers {"code":200,"data":..} or {"code":..,"message":..}
+	if ToInt(respmap["code"]) != 200 {
+		errCode := API_ERR
+		errCode.OriginErrMsg, _ = respmap["message"].(string)
+		return nil, errCode
+	}
+	addresses, _ := respmap["data"].([]interface{})
+	if len(addresses) == 0 {
+		return nil, errors.New("no deposit address")
+	}
+	addrmap := addresses[0].(map[string]interface{})
+	addr := &CryptoAddress{Currency: currency, ExchangeName: hbV2.GetExchangeName()}
+	addr.Address, _ = addrmap["address"].(string)
+	addr.Tag, _ = addrmap["addressTag"].(string)
+	return addr, nil
+}
+
+//GetTradeFee returns the account's rates on the pair
+func (hbV2 *HuoBi_V2) GetTradeFee(pair CurrencyPair) (*TradeFee, error) {
+	var dp DecimalParser
+	path := "/v1/fee/fee-rate/get"
+	params := url.Values{}
+	params.Set("symbols", strings.ToLower(pair.ToSymbol("")))
+	hbV2.buildPostForm("GET", path, &params)
+	respmap, err := HttpGet(hbV2.httpClient, hbV2.baseUrl+path+"?"+params.Encode())
+	if err != nil {
+		return nil, err
+	}
+
+	if respmap["status"].(string) != "ok" {
+		return nil, hbV2.errorWrapper(respmap)
+	}
+	rates, _ := respmap["data"].([]interface{})
+	if len(rates) == 0 {
+		errCode := EX_ERR_UNKNOWN_FEE
+		errCode.OriginErrMsg = hbV2.GetExchangeName() + " " + pair.String()
+		return nil, errCode
+	}
+	ratemap := rates[0].(map[string]interface{})
+	fee := &TradeFee{Maker: dp.Decimal(ratemap["maker-fee"]), Taker: dp.Decimal(ratemap["taker-fee"])}
+	if dp.Err != nil {
+		return nil, dp.Err
+	}
+	return fee, nil
+}
+
+//GetDeposits pages the last 500 deposits at most
+func (hbV2 *HuoBi_V2) GetDeposits(currency Currency, currentPage, pageSize int) ([]Deposit, error) {
+	var dp DecimalParser
+	size := currentPage * pageSize
+	if size > 500 {
+		size = 500
+	}
+	path := "/v1/query/deposit-withdraw"
+	params := url.Values{}
+	params.Set("currency", strings.ToLower(currency.Symbol))
+	params.Set("type", "deposit")
+	params.Set("size", strconv.Itoa(size))
+	hbV2.buildPostForm("GET", path, &params)
+	respmap, err := HttpGet(hbV2.httpClient, hbV2.baseUrl+path+"?"+params.Encode())
+	if err != nil {
+		return nil, err
+	}
+
+	if respmap["status"].(string) != "ok" {
+		return nil, hbV2.errorWrapper(respmap)
+	}
+
+	datamap, _ := respmap["data"].([]interface{})
+	deposits := make([]Deposit, 0, len(datamap))
+	for _, v := range datamap {
+		depmap := v.(map[string]interface{})
+		d := Deposit{
+			ID:       fmt.Sprint(ToInt64(depmap["id"])),
+			Currency: currency,
+			Amount:   dp.Decimal(depmap["amount"]),
+			Time:     ToInt64(depmap["created-at"]) / 1000}
+		d.Address, _ = depmap["address"].(string)
+		d.Tag, _ = depmap["address-tag"].(string)
+		d.TxID, _ = depmap["tx-hash"].(string)
+		switch depmap["state"] {
+		case "confirmed", "safe":
+			d.Status = DEPOSIT_COMPLETED
+		case "orphan":
+			d.Status = DEPOSIT_FAILED
+		default:
+			d.Status = DEPOSIT_PENDING
+		}
+		deposits = append(deposits, d)
+	}
+	sort.Slice(deposits, func(i, j int) bool { return deposits[i].Time > deposits[j].Time })
+	if dp.Err != nil {
+		return nil, dp.Err
+	}
+	return DepositsPage(deposits, currentPage, pageSize), nil
 }
 
 func (hbV2 *HuoBi_V2) buildPostForm(reqMethod, path string, postForm *url.Values) error {
@@ -432,3 +741,38 @@ func (hbV2 *HuoBi_V2) toJson(params url.Values) string {
 	jsonData, _ := json.Marshal(parammap)
 	return string(jsonData)
 }
+
+func (hbV2 *HuoBi_V2) errorWrapper(respmap map[string]interface{}) ApiError {
+	errcode, _ := respmap["err-code"].(string)
+	errmsg, _ := respmap["err-msg"].(string)
+
+	var errCode ApiError
+	switch {
+	case errcode == "api-signature-not-valid", errcode == "api-signature-check-failed":
+		if strings.Contains(strings.ToLower(errmsg), "timestamp") {
+			errCode = EX_ERR_NONCE
+		} else {
+			errCode = EX_ERR_SIGN
+		}
+	case errcode == "invalid-access-key", errcode == "api-key-invalid":
+		errCode = EX_ERR_NOT_FIND_APIKEY
+	case strings.Contains(errcode, "too-many-request"), strings.Contains(errcode, "frequency"):
+		errCode = EX_ERR_API_LIMIT
+	case strings.Contains(errcode, "balance-insufficient"), errcode == "insufficient-balance":
+		errCode = EX_ERR_INSUFFICIENT_BALANCE
+	case errcode == "base-record-invalid", errcode == "order-not-found":
+		errCode = EX_ERR_NOT_FIND_ORDER
+	case errcode == "order-orderstate-error":
+		errCode = EX_ERR_CANCEL_ORDER_FAIL
+	case strings.Contains(errcode, "precision-error"), strings.Contains(errcode, "-min-error"), strings.Contains(errcode, "-max-error"):
+		errCode = EX_ERR_INVALID_PRECISION
+	case errcode == "base-symbol-error", errcode == "invalid-symbol":
+		errCode = EX_ERR_INVALID_CURRENCY_PAIR
+	case strings.Contains(errcode, "maintain"), errcode == "base-system-error":
+		errCode = EX_ERR_MAINTENANCE
+	default:
+		errCode = API_ERR
+	}
+	errCode.OriginErrMsg = fmt.Sprintf("%s %s", errcode, errmsg)
+	return errCode
+}
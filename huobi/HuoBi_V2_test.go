@@ -1,23 +1,208 @@
 package huobi
 
 import (
+	"encoding/json"
 	"github.com/nntaoli-project/GoEx"
 	"github.com/stretchr/testify/assert"
 	"net/http"
+	"net/http/httptest"
 	"testing"
 )
 
-var hb2 = NewV2(http.DefaultClient, "", "")
+var hb2 = NewV2(fixtures.Client(), "", "", "")
 
 func TestHuoBi_V2_GetTicker(t *testing.T) {
 	ticker, err := hb2.GetTicker(goex.BTS_CNY)
 	assert.Nil(t, err)
-	t.Log(ticker)
+	assert.Equal(t, goex.Ticker{Last: goex.RequireDecimal("0.2311"), Buy: goex.RequireDecimal("0.2306"), Sell: goex.RequireDecimal("0.2316"),
+		High: goex.RequireDecimal("0.2433"), Low: goex.RequireDecimal("0.2201"), Vol: goex.RequireDecimal("31452208.12"), Date: 1504686312185}, *ticker)
 }
 
 func TestHuoBi_V2_GetDepth(t *testing.T) {
 	depth, err := hb2.GetDepth(2, goex.BCC_CNY)
 	assert.Nil(t, err)
-	t.Log("asks: ", depth.AskList)
-	t.Log("bids: ", depth.BidList)
+	assert.Equal(t, goex.DepthRecords{
+		{Price: goex.RequireDecimal("3805"), Amount: goex.RequireDecimal("0.8")},
+		{Price: goex.RequireDecimal("3806.98"), Amount: goex.RequireDecimal("2.3")}}, depth.AskList)
+	assert.Equal(t, goex.DepthRecords{
+		{Price: goex.RequireDecimal("3802"), Amount: goex.RequireDecimal("1.2")},
+		{Price: goex.RequireDecimal("3801.5"), Amount: goex.RequireDecimal("0.51")}}, depth.BidList)
+}
+
+func TestHuoBi_V2_GetMarkets(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "/v1/common/symbols", r.URL.Path)
+		w.Write([]byte(`{"status":"ok","data":[` +
+			`{"base-currency":"btc","quote-currency":"usdt","price-precision":2,"amount-precision":4,"symbol-partition":"main",` +
+			`"symbol":"btcusdt","state":"online","min-order-amt":0.0001,"max-order-amt":1000,"min-order-value":1},` +
+			`{"base-currency":"ven","quote-currency":"btc","price-precision":8,"amount-precision":2,"symbol-partition":"main",` +
+			`"symbol":"venbtc","state":"offline","min-order-amt":1,"max-order-amt":100000,"min-order-value":0.0001}]}`))
+	}))
+	defer srv.Close()
+	hbpro := NewHuobiPro(http.DefaultClient, "", "", "")
+	hbpro.baseUrl = srv.URL
+
+	markets, err := hbpro.GetMarkets()
+	assert.Nil(t, err)
+	assert.Equal(t, []goex.SymbolInfo{{
+		Pair:            goex.BTC_USDT,
+		Symbol:          "btcusdt",
+		PricePrecision:  2,
+		AmountPrecision: 4,
+		TickSize:        goex.RequireDecimal("0.01"),
+		StepSize:        goex.RequireDecimal("0.0001"),
+		MinAmount:       goex.RequireDecimal("0.0001"),
+		MaxAmount:       goex.RequireDecimal("1000"),
+		MinNotional:     goex.RequireDecimal("1")}}, markets)
+
+	info, err := hbpro.GetSymbolInfo(goex.BTC_USDT)
+	assert.Nil(t, err)
+	amount, price, err := info.Conform(goex.SELL, goex.RequireDecimal("0.123456"), goex.RequireDecimal("6100.123"))
+	assert.Nil(t, err)
+	assert.Equal(t, "0.1234", amount.String())
+	assert.Equal(t, "6100.13", price.String())
+}
+
+func TestHuoBi_V2_ClientOrderID(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/v1/order/orders/place":
+			var params map[string]string
+			json.NewDecoder(r.Body).Decode(&params)
+			assert.Equal(t, "myOrder1", params["client-order-id"])
+			assert.Equal(t, "buy-limit", params["type"])
+			w.Write([]byte(`{"status":"ok","data":"59378"}`))
+		case "/v1/order/orders/getClientOrder":
+			assert.Equal(t, "myOrder1", r.URL.Query().Get("clientOrderId"))
+			w.Write([]byte(`{"status":"ok","data":{"id":59378,"client-order-id":"myOrder1","symbol":"btcusdt","amount":"1.0000",` +
+				`"price":"6100.00","created-at":1494901162595,"type":"buy-limit","field-amount":"0","field-cash-amount":"0",` +
+				`"field-fees":"0","state":"submitted"}}`))
+		case "/v1/order/orders/submitCancelClientOrder":
+			var params map[string]string
+			json.NewDecoder(r.Body).Decode(&params)
+			assert.Equal(t, "myOrder1", params["client-order-id"])
+			w.Write([]byte(`{"status":"ok","data":0}`))
+		default:
+			t.Error("unexpected request", r.URL.Path)
+		}
+	}))
+	defer srv.Close()
+	hbpro := NewHuobiPro(http.DefaultClient, "", "", "1")
+	hbpro.baseUrl = srv.URL
+
+	order, err := hbpro.PlaceOrderWithClientID("myOrder1", goex.BUY, goex.RequireDecimal("1"), goex.RequireDecimal("6100"), goex.BTC_USDT)
+	assert.Nil(t, err)
+	assert.Equal(t, 59378, order.OrderID)
+	assert.Equal(t, "myOrder1", order.ClientOrderID)
+
+	order, err = hbpro.GetOneOrder("myOrder1", goex.BTC_USDT)
+	assert.Nil(t, err)
+	assert.Equal(t, 59378, order.OrderID)
+	assert.Equal(t, "myOrder1", order.ClientOrderID)
+	assert.Equal(t, goex.TradeStatus(goex.ORDER_UNFINISH), order.Status)
+
+	ok, err := hbpro.CancelOrder("myOrder1", goex.BTC_USDT)
+	assert.Nil(t, err)
+	assert.True(t, ok)
+}
+
+func TestHuoBi_V2_Deposits(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "xrp", r.URL.Query().Get("currency"))
+		switch r.URL.Path {
+		case "/v2/account/deposit/address":
+			w.Write([]byte(`{"code":200,"data":[{"currency":"xrp","address":"rae93V8d2mdoUQHwBDBdM4NHCMehRJAsbm","addressTag":"100040","chain":"xrp"}]}`))
+		case "/v1/query/deposit-withdraw":
+			assert.Equal(t, "deposit", r.URL.Query().Get("type"))
+			assert.Equal(t, "20", r.URL.Query().Get("size"))
+			w.Write([]byte(`{"status":"ok","data":[` +
+				`{"id":1171,"type":"deposit","currency":"xrp","tx-hash":"ed03","amount":7.5,"address":"rae93V8d2mdoUQHwBDBdM4NHCMehRJAsbm",` +
+				`"address-tag":"100040","fee":0,"state":"safe","created-at":1510912472199,"updated-at":1511145876575},` +
+				`{"id":1172,"type":"deposit","currency":"xrp","tx-hash":"ff12","amount":2,"address":"rae93V8d2mdoUQHwBDBdM4NHCMehRJAsbm",` +
+				`"address-tag":"100040","fee":0,"state":"confirming","created-at":1510999472199,"updated-at":1510999472199}]}`))
+		}
+	}))
+	defer srv.Close()
+	hbpro := NewHuobiPro(http.DefaultClient, "", "", "1")
+	hbpro.baseUrl = srv.URL
+
+	addr, err := hbpro.GetDepositAddress(goex.XRP)
+	assert.Nil(t, err)
+	assert.Equal(t, "rae93V8d2mdoUQHwBDBdM4NHCMehRJAsbm", addr.Address)
+	assert.Equal(t, "100040", addr.Tag)
+
+	deposits, err := hbpro.GetDeposits(goex.XRP, 2, 10)
+	assert.Nil(t, err)
+	assert.Len(t, deposits, 0)
+	deposits, err = hbpro.GetDeposits(goex.XRP, 1, 20)
+	assert.Nil(t, err)
+	assert.Equal(t, []goex.Deposit{
+		{ID: "1172", Currency: goex.XRP, Amount: goex.RequireDecimal("2"), Address: "rae93V8d2mdoUQHwBDBdM4NHCMehRJAsbm", Tag: "100040",
+			TxID: "ff12", Status: goex.DEPOSIT_PENDING, Time: 1510999472},
+		{ID: "1171", Currency: goex.XRP, Amount: goex.RequireDecimal("7.5"), Address: "rae93V8d2mdoUQHwBDBdM4NHCMehRJAsbm", Tag: "100040",
+			TxID: "ed03", Status: goex.DEPOSIT_COMPLETED, Time: 1510912472}}, deposits)
+}
+
+func TestHuoBi_V2_GetTradeFee(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "/v1/fee/fee-rate/get", r.URL.Path)
+		assert.Equal(t, "btcusdt", r.URL.Query().Get("symbols"))
+		w.Write([]byte(`{"status":"ok","data":[{"symbol":"btcusdt","maker-fee":"0.0018","taker-fee":"0.002"}]}`))
+	}))
+	defer srv.Close()
+	hbpro := NewHuobiPro(http.DefaultClient, "", "", "1")
+	hbpro.baseUrl = srv.URL
+
+	fee, err := hbpro.GetTradeFee(goex.BTC_USDT)
+	assert.Nil(t, err)
+	assert.Equal(t, goex.TradeFee{Maker: goex.RequireDecimal("0.0018"), Taker: goex.RequireDecimal("0.002")}, *fee)
+}
+
+func TestHuoBi_V2_GetMyTrades(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "/v1/order/matchresults", r.URL.Path)
+		assert.Equal(t, "btcusdt", r.URL.Query().Get("symbol"))
+		assert.Equal(t, "2017-11-17", r.URL.Query().Get("start-date"))
+		w.Write([]byte(`{"status":"ok","data":[` +
+			`{"id":29555,"order-id":59378,"match-id":59335,"symbol":"btcusdt","type":"sell-limit","price":"7800.5","filled-amount":"0.2",` +
+			`"filled-fees":"3.1202","created-at":1510999472199,"role":"maker","fee-currency":"usdt"},` +
+			`{"id":29553,"order-id":59370,"match-id":59331,"symbol":"btcusdt","type":"buy-market","price":"7790","filled-amount":"0.5",` +
+			`"filled-fees":"0.001","created-at":1510912472199,"role":"taker"}]}`))
+	}))
+	defer srv.Close()
+	hbpro := NewHuobiPro(http.DefaultClient, "", "", "1")
+	hbpro.baseUrl = srv.URL
+
+	trades, err := hbpro.GetMyTrades(goex.BTC_USDT, 1510912472199, 10)
+	assert.Nil(t, err)
+	assert.Equal(t, []goex.MyTrade{
+		{TradeID: "29553", OrderID: "59370", Currency: goex.BTC_USDT, Side: goex.TradeSide(goex.BUY), Price: goex.RequireDecimal("7790"),
+			Amount: goex.RequireDecimal("0.5"), Fee: goex.RequireDecimal("0.001"), FeeCurrency: goex.BTC, Time: 1510912472199},
+		{TradeID: "29555", OrderID: "59378", Currency: goex.BTC_USDT, Side: goex.TradeSide(goex.SELL), Price: goex.RequireDecimal("7800.5"),
+			Amount: goex.RequireDecimal("0.2"), Fee: goex.RequireDecimal("3.1202"), FeeCurrency: goex.USDT, IsMaker: true, Time: 1510999472199},
+	}, trades)
+}
+
+func TestHuoBi_V2_errorWrapper(t *testing.T) {
+	for _, c := range []struct {
+		code, msg string
+		errCode   goex.ApiError
+	}{
+		{"api-signature-not-valid", "Signature not valid: Verification failure", goex.EX_ERR_SIGN},
+		{"api-signature-not-valid", "Signature not valid: Timestamp expired", goex.EX_ERR_NONCE},
+		{"invalid-access-key", "", goex.EX_ERR_NOT_FIND_APIKEY},
+		{"too-many-request", "", goex.EX_ERR_API_LIMIT},
+		{"account-frozen-balance-insufficient-error", "", goex.EX_ERR_INSUFFICIENT_BALANCE},
+		{"base-record-invalid", "record invalid", goex.EX_ERR_NOT_FIND_ORDER},
+		{"order-orderstate-error", "", goex.EX_ERR_CANCEL_ORDER_FAIL},
+		{"order-orderamount-precision-error", "", goex.EX_ERR_INVALID_PRECISION},
+		{"order-limitorder-amount-min-error", "", goex.EX_ERR_INVALID_PRECISION},
+		{"base-symbol-error", "", goex.EX_ERR_INVALID_CURRENCY_PAIR},
+		{"base-system-error", "", goex.EX_ERR_MAINTENANCE},
+		{"base-operation-forbidden", "", goex.API_ERR},
+	} {
+		err := hb2.errorWrapper(map[string]interface{}{"status": "error", "err-code": c.code, "err-msg": c.msg})
+		assert.True(t, c.errCode.Is(err), "%s %s", c.code, c.msg)
+		assert.Equal(t, c.code+" "+c.msg, err.OriginErrMsg)
+	}
 }
@@ -3,21 +3,46 @@ package huobi
 import (
 	"github.com/nntaoli-project/GoEx"
 	"github.com/stretchr/testify/assert"
-	"net/http"
+	"os"
 	"testing"
 )
 
-var hb = New(http.DefaultClient, "", "")
+var fixtures = goex.NewFixtureTransport("testdata/fixtures.json")
+var hb = New(fixtures.Client(), "", "")
 
 func TestHuoBi_GetDepth(t *testing.T) {
 	dep, err := hb.GetDepth(2, goex.BTC_CNY)
 	assert.Nil(t, err)
-	t.Log(dep.AskList)
-	t.Log(dep.BidList)
+	//the best ask last
+	assert.Equal(t, goex.DepthRecords{
+		{Price: goex.RequireDecimal("18421.76"), Amount: goex.RequireDecimal("0.4")},
+		{Price: goex.RequireDecimal("18420"), Amount: goex.RequireDecimal("1.2354")}}, dep.AskList)
+	assert.Equal(t, goex.DepthRecords{
+		{Price: goex.RequireDecimal("18412.03"), Amount: goex.RequireDecimal("0.5")},
+		{Price: goex.RequireDecimal("18410"), Amount: goex.RequireDecimal("2.0971")}}, dep.BidList)
 }
 
 func TestHuoBi_GetKlineRecords(t *testing.T) {
-	klines, err := hb.GetKlineRecords(goex.BTC_CNY, goex.KLINE_PERIOD_4H, 1, -1)
+	klines, err := hb.GetKlineRecords(goex.BTC_CNY, goex.KLINE_PERIOD_60MIN, 1, -1)
 	assert.Nil(t, err)
-	t.Log(klines)
+	assert.Equal(t, []goex.Kline{{Timestamp: 1504713600, Open: 18360, Close: 18412.03, High: 18425, Low: 18340, Vol: 312.5531}}, klines)
+}
+
+func TestMain(m *testing.M) {
+	os.Exit(fixtures.Run(m.Run))
+}
+
+func TestHuoBi_errorWrapper(t *testing.T) {
+	for msg, errCode := range map[string]goex.ApiError{
+		"访问过于频繁":  goex.EX_ERR_API_LIMIT,
+		"账户余额不足":  goex.EX_ERR_INSUFFICIENT_BALANCE,
+		"该委托不存在":  goex.EX_ERR_NOT_FIND_ORDER,
+		"该委托不能取消": goex.EX_ERR_CANCEL_ORDER_FAIL,
+		"签名错误":    goex.EX_ERR_SIGN,
+		"系统错误":    goex.API_ERR,
+	} {
+		err := hb.errorWrapper(map[string]interface{}{"code": float64(1), "msg": msg}, "origin")
+		assert.True(t, errCode.Is(err), msg)
+		assert.Equal(t, "origin", err.OriginErrMsg)
+	}
 }
@@ -1,18 +1,24 @@
 package huobi
 
-import "net/http"
+import (
+	"net/http"
+
+	. "github.com/nntaoli-project/GoEx"
+)
 
 type HuobiPro struct {
 	*HuoBi_V2
 }
 
 func NewHuobiPro(client *http.Client, apikey, secretkey, accountId string) *HuobiPro {
+	client = ExchangeRateLimitedClient(client, "huobi.pro", apikey, RateLimits)
 	hbv2 := new(HuoBi_V2)
 	hbv2.accountId = accountId
 	hbv2.accessKey = apikey
 	hbv2.secretKey = secretkey
 	hbv2.httpClient = client
 	hbv2.baseUrl = "https://api.huobi.pro"
+	hbv2.symbols = new(SymbolInfoCache)
 	return &HuobiPro{hbv2}
 }
 
@@ -0,0 +1,14 @@
+package huobi
+
+import (
+	"time"
+
+	. "github.com/nntaoli-project/GoEx"
+)
+
+//RateLimits huobi pro allows 10 market data requests a second and 100 signed requests per 10 seconds
+var RateLimits = RateLimitConfig{
+	Limits: []RateLimit{
+		{Classes: []EndpointClass{ENDPOINT_PUBLIC}, Limit: 10, Interval: time.Second},
+		{Classes: []EndpointClass{ENDPOINT_PRIVATE, ENDPOINT_ORDER}, Limit: 100, Interval: 10 * time.Second}},
+	Classify: ClassifyEndpoint}
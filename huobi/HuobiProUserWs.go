@@ -0,0 +1,239 @@
+package huobi
+
+import (
+	"encoding/json"
+	"fmt"
+	. "github.com/nntaoli-project/GoEx"
+	"net/url"
+	"strings"
+	"sync"
+	"time"
+)
+
+const (
+	HUOBIPRO_USER_WS_URL = "wss://api.huobi.pro/ws/v1"
+)
+
+//HuobiProUserWs implements UserStreamAPI on the authenticated v1 websocket, topics orders.* and accounts.
+//Messages are gzipped like on the market websocket, but pings come as {"op":"ping","ts":ts}.
+type HuobiProUserWs struct {
+	wsUrl     string
+	hbV2      *HuoBi_V2 //signs the auth request
+	accountId string
+	wsConn    *WsConn
+	l         sync.Mutex
+	orders    chan Order
+	balances  chan SubAccount
+	accounts  map[string]SubAccount
+	closeCh   chan struct{}
+	once      sync.Once
+}
+
+//accountId leaves out the balances of other accounts (margin ...), empty: report all of them
+func NewHuobiProUserWs(accessKey, secretKey, accountId string) *HuobiProUserWs {
+	return &HuobiProUserWs{
+		wsUrl:     HUOBIPRO_USER_WS_URL,
+		hbV2:      &HuoBi_V2{accessKey: accessKey, secretKey: secretKey, baseUrl: "https://api.huobi.pro"},
+		accountId: accountId,
+		accounts:  make(map[string]SubAccount),
+		closeCh:   make(chan struct{})}
+}
+
+func (hbproUserWs *HuobiProUserWs) GetExchangeName() string {
+	return "huobi.pro"
+}
+
+func (hbproUserWs *HuobiProUserWs) SubscribeOrders() (<-chan Order, error) {
+	hbproUserWs.l.Lock()
+	defer hbproUserWs.l.Unlock()
+	if hbproUserWs.orders != nil {
+		return hbproUserWs.orders, nil
+	}
+	if err := hbproUserWs.connect(); err != nil {
+		return nil, err
+	}
+	hbproUserWs.orders = make(chan Order, 64)
+	hbproUserWs.wsConn.Subscribe(map[string]interface{}{"op": "sub", "cid": "orders", "topic": "orders.*"})
+	return hbproUserWs.orders, nil
+}
+
+func (hbproUserWs *HuobiProUserWs) SubscribeBalances() (<-chan SubAccount, error) {
+	hbproUserWs.l.Lock()
+	defer hbproUserWs.l.Unlock()
+	if hbproUserWs.balances != nil {
+		return hbproUserWs.balances, nil
+	}
+	if err := hbproUserWs.connect(); err != nil {
+		return nil, err
+	}
+	hbproUserWs.balances = make(chan SubAccount, 64)
+	hbproUserWs.wsConn.Subscribe(map[string]interface{}{"op": "sub", "cid": "accounts", "topic": "accounts"})
+	return hbproUserWs.balances, nil
+}
+
+func (hbproUserWs *HuobiProUserWs) Close() error {
+	hbproUserWs.once.Do(func() { close(hbproUserWs.closeCh) })
+	hbproUserWs.l.Lock()
+	wsConn := hbproUserWs.wsConn
+	hbproUserWs.l.Unlock()
+	if wsConn == nil {
+		return nil
+	}
+	return wsConn.Close()
+}
+
+//connect authenticates on the first subscription, l must be held
+func (hbproUserWs *HuobiProUserWs) connect() error {
+	if hbproUserWs.wsConn != nil {
+		return nil
+	}
+	wsConn := NewWsConn(hbproUserWs.wsUrl, hbproUserWs.handle)
+	wsConn.UnCompressFunc = GzipUnCompress
+	wsConn.ReadTimeout = time.Minute //the server pings every 30s
+	wsConn.LoginDataFunc = hbproUserWs.authData
+	wsConn.CloseHandleFunc = hbproUserWs.closeChans
+	hbproUserWs.wsConn = wsConn //set before Connect, the first ping may arrive right away
+	if err := wsConn.Connect(); err != nil {
+		hbproUserWs.wsConn = nil
+		return err
+	}
+	return nil
+}
+
+//authData is signed like a REST GET of /ws/v1, with a new timestamp for each connection
+func (hbproUserWs *HuobiProUserWs) authData() interface{} {
+	params := url.Values{}
+	hbproUserWs.hbV2.buildPostForm("GET", "/ws/v1", &params)
+	auth := map[string]interface{}{"op": "auth"}
+	for k, v := range params {
+		auth[k] = v[0]
+	}
+	return auth
+}
+
+func (hbproUserWs *HuobiProUserWs) handle(msg []byte) error {
+	var resp map[string]interface{}
+	if err := json.Unmarshal(msg, &resp); err != nil {
+		return err
+	}
+
+	switch resp["op"] {
+	case "ping":
+		hbproUserWs.l.Lock()
+		wsConn := hbproUserWs.wsConn
+		hbproUserWs.l.Unlock()
+		return wsConn.SendJsonMessage(map[string]interface{}{"op": "pong", "ts": resp["ts"]})
+	case "auth", "sub":
+		if ToInt(resp["err-code"]) != 0 {
+			resp["err-code"] = fmt.Sprint(resp["err-code"])
+			return hbproUserWs.hbV2.errorWrapper(resp)
+		}
+	case "notify":
+		data, _ := resp["data"].(map[string]interface{})
+		if data == nil {
+			return nil
+		}
+		if resp["topic"] == "accounts" {
+			return hbproUserWs.pushBalances(data)
+		}
+		return hbproUserWs.pushOrder(data)
+	}
+	return nil
+}
+
+//data: {"order-id", "symbol", "order-amount", "order-price", "created-at", "order-type", "order-state", "unfilled-amount", ...}
+func (hbproUserWs *HuobiProUserWs) pushOrder(data map[string]interface{}) error {
+	hbproUserWs.l.Lock()
+	orders := hbproUserWs.orders
+	hbproUserWs.l.Unlock()
+	if orders == nil {
+		return nil
+	}
+
+	var dp DecimalParser
+	filled := dp.Decimal(data["order-amount"]).Sub(dp.Decimal(data["unfilled-amount"]))
+	if dp.Err != nil {
+		return dp.Err
+	}
+	//rename the fields to those of the rest api, the fills of a single match are not in the order's totals
+	ord, err := hbproUserWs.hbV2.parseOrder(map[string]interface{}{
+		"id":           data["order-id"],
+		"amount":       data["order-amount"],
+		"price":        data["order-price"],
+		"field-amount": filled,
+		"created-at":   data["created-at"],
+		"state":        fmt.Sprint(data["order-state"]),
+		"type":         fmt.Sprint(data["order-type"])})
+	if err != nil {
+		return err
+	}
+	ord.Currency = hbproUserWs.symbolToCurrencyPair(fmt.Sprint(data["symbol"]))
+
+	select {
+	case orders <- ord:
+	case <-hbproUserWs.closeCh:
+	}
+	return nil
+}
+
+//data: {"event", "list": [{"account-id", "currency", "type": "trade"|"frozen", "balance"}, ...]},
+//available and frozen balances come as separate entries so the last known values are kept
+func (hbproUserWs *HuobiProUserWs) pushBalances(data map[string]interface{}) error {
+	list, _ := data["list"].([]interface{})
+	for _, v := range list {
+		entry, isok := v.(map[string]interface{})
+		if !isok || hbproUserWs.accountId != "" && fmt.Sprint(ToInt64(entry["account-id"])) != hbproUserWs.accountId {
+			continue
+		}
+		balance, err := ToDecimalE(entry["balance"])
+		if err != nil {
+			return err
+		}
+
+		currency := strings.ToUpper(fmt.Sprint(entry["currency"]))
+		hbproUserWs.l.Lock()
+		balances := hbproUserWs.balances
+		account := hbproUserWs.accounts[currency]
+		account.Currency = NewCurrency(currency, "")
+		switch entry["type"] {
+		case "trade":
+			account.Amount = balance
+		case "frozen":
+			account.FrozenAmount = balance
+		}
+		hbproUserWs.accounts[currency] = account
+		hbproUserWs.l.Unlock()
+
+		if balances == nil {
+			return nil
+		}
+		select {
+		case balances <- account:
+		case <-hbproUserWs.closeCh:
+			return nil
+		}
+	}
+	return nil
+}
+
+//symbolToCurrencyPair splits a symbol like btcusdt at the quote currency
+func (hbproUserWs *HuobiProUserWs) symbolToCurrencyPair(symbol string) CurrencyPair {
+	for _, quote := range []string{"usdt", "husd", "btc", "eth", "ht"} {
+		if strings.HasSuffix(symbol, quote) && len(symbol) > len(quote) {
+			return NewCurrencyPair(NewCurrency(strings.ToUpper(symbol[:len(symbol)-len(quote)]), ""),
+				NewCurrency(strings.ToUpper(quote), ""))
+		}
+	}
+	return UNKNOWN_PAIR
+}
+
+func (hbproUserWs *HuobiProUserWs) closeChans() {
+	hbproUserWs.l.Lock()
+	defer hbproUserWs.l.Unlock()
+	if hbproUserWs.orders != nil {
+		close(hbproUserWs.orders)
+	}
+	if hbproUserWs.balances != nil {
+		close(hbproUserWs.balances)
+	}
+}
@@ -0,0 +1,85 @@
+package huobi
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/gorilla/websocket"
+	. "github.com/nntaoli-project/GoEx"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestHuobiProUserWs(t *testing.T) {
+	upgrader := websocket.Upgrader{}
+	ponged := make(chan interface{}, 1)
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		conn, err := upgrader.Upgrade(w, r, nil)
+		if err != nil {
+			t.Error(err)
+			return
+		}
+		defer conn.Close()
+
+		var auth map[string]interface{}
+		conn.ReadJSON(&auth)
+		assert.Equal(t, "auth", auth["op"])
+		assert.Equal(t, "key", auth["AccessKeyId"])
+		assert.Equal(t, "HmacSHA256", auth["SignatureMethod"])
+		assert.NotEmpty(t, auth["Signature"])
+		conn.WriteMessage(websocket.BinaryMessage, gzipMessage(`{"op":"auth","ts":1,"err-code":0,"data":{"user-id":1}}`))
+		conn.WriteMessage(websocket.BinaryMessage, gzipMessage(`{"op":"ping","ts":1530000000000}`))
+
+		//pushes go out once both topics are subscribed, pongs are reported whenever they come
+		for topics := 0; ; {
+			var req map[string]interface{}
+			if err := conn.ReadJSON(&req); err != nil {
+				return
+			}
+			if req["op"] == "pong" {
+				select {
+				case ponged <- req["ts"]:
+				default:
+				}
+				continue
+			}
+			if topics++; topics < 2 {
+				continue
+			}
+			for _, msg := range []string{
+				`{"op":"notify","topic":"orders.btcusdt","data":{"seq-id":1,"order-id":2039,"symbol":"btcusdt","account-id":1,` +
+					`"order-amount":"1.0","order-price":"6100.0","created-at":1530000000000,"order-type":"sell-limit","order-state":"partial-filled",` +
+					`"price":"6100.0","filled-amount":"0.25","unfilled-amount":"0.4"}}`,
+				`{"op":"notify","topic":"accounts","data":{"event":"order.place","list":[` +
+					`{"account-id":1,"currency":"btc","type":"frozen","balance":"0.4"},{"account-id":2,"currency":"btc","type":"trade","balance":"8"}]}}`,
+				`{"op":"notify","topic":"accounts","data":{"event":"order.match","list":[{"account-id":1,"currency":"btc","type":"trade","balance":"9"}]}}`} {
+				conn.WriteMessage(websocket.BinaryMessage, gzipMessage(msg))
+			}
+		}
+	}))
+	defer srv.Close()
+
+	hbproUserWs := NewHuobiProUserWs("key", "secret", "1")
+	hbproUserWs.wsUrl = "ws" + strings.TrimPrefix(srv.URL, "http")
+
+	orders, err := hbproUserWs.SubscribeOrders()
+	assert.Nil(t, err)
+	balances, err := hbproUserWs.SubscribeBalances()
+	assert.Nil(t, err)
+
+	assert.Equal(t, float64(1530000000000), <-ponged)
+	assert.Equal(t, Order{OrderID: 2039, Currency: BTC_USDT, Amount: RequireDecimal("1"), DealAmount: RequireDecimal("0.6"), Price: RequireDecimal("6100"), Side: SELL,
+		Status: ORDER_PART_FINISH, OrderTime: 1530000000000}, <-orders)
+	assert.Equal(t, SubAccount{Currency: BTC, FrozenAmount: RequireDecimal("0.4")}, <-balances)
+	assert.Equal(t, SubAccount{Currency: BTC, Amount: RequireDecimal("9"), FrozenAmount: RequireDecimal("0.4")}, <-balances)
+
+	assert.Nil(t, hbproUserWs.Close())
+	for range orders {
+	}
+}
+
+func TestHuobiProUserWs_handleError(t *testing.T) {
+	err := NewHuobiProUserWs("", "", "").handle([]byte(`{"op":"auth","ts":1,"err-code":2002,"err-msg":"invalid.auth.state"}`))
+	assert.NotNil(t, err)
+}
@@ -0,0 +1,287 @@
+package huobi
+
+import (
+	"encoding/json"
+	"fmt"
+	. "github.com/nntaoli-project/GoEx"
+	"strings"
+	"sync"
+	"time"
+)
+
+const (
+	HUOBIPRO_WS_URL = "wss://api.huobi.pro/ws"
+)
+
+var wsKlinePeriods = map[int]string{
+	KLINE_PERIOD_1MIN:   "1min",
+	KLINE_PERIOD_5MIN:   "5min",
+	KLINE_PERIOD_15MIN:  "15min",
+	KLINE_PERIOD_30MIN:  "30min",
+	KLINE_PERIOD_60MIN:  "60min",
+	KLINE_PERIOD_4H:     "4hour",
+	KLINE_PERIOD_1DAY:   "1day",
+	KLINE_PERIOD_1WEEK:  "1week",
+	KLINE_PERIOD_1MONTH: "1mon",
+	KLINE_PERIOD_1YEAR:  "1year",
+}
+
+//HuobiProWs implements StreamAPI on the huobi.pro market websocket.
+//Every message is gzipped, and the server pings with {"ping":ts} expecting {"pong":ts} back.
+type HuobiProWs struct {
+	wsUrl   string
+	wsConn  *WsConn
+	l       sync.Mutex
+	id      int
+	chans   map[string]interface{}
+	handles map[string]func(resp map[string]interface{}) error
+	closers []func()
+	closeCh chan struct{}
+	once    sync.Once
+}
+
+func NewHuobiProWs() *HuobiProWs {
+	return &HuobiProWs{
+		wsUrl:   HUOBIPRO_WS_URL,
+		chans:   make(map[string]interface{}),
+		handles: make(map[string]func(resp map[string]interface{}) error),
+		closeCh: make(chan struct{})}
+}
+
+func (hbproWs *HuobiProWs) GetExchangeName() string {
+	return "huobi.pro"
+}
+
+func (hbproWs *HuobiProWs) SubscribeTicker(pair CurrencyPair) (<-chan Ticker, error) {
+	ch := make(chan Ticker, 64)
+	c, err := hbproWs.subscribe(hbproWs.topic(pair, "ticker"), ch, func() { close(ch) }, func(resp map[string]interface{}) error {
+		tick, isok := resp["tick"].(map[string]interface{})
+		if !isok {
+			return nil
+		}
+		var dp DecimalParser
+		ticker := Ticker{
+			Last: dp.Decimal(tick["close"]),
+			Buy:  dp.Decimal(tick["bid"]),
+			Sell: dp.Decimal(tick["ask"]),
+			High: dp.Decimal(tick["high"]),
+			Low:  dp.Decimal(tick["low"]),
+			Vol:  dp.Decimal(tick["amount"]),
+			Date: ToUint64(resp["ts"])}
+		if dp.Err != nil {
+			return dp.Err
+		}
+		select {
+		case ch <- ticker:
+		case <-hbproWs.closeCh:
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return c.(chan Ticker), nil
+}
+
+func (hbproWs *HuobiProWs) SubscribeDepth(pair CurrencyPair, size int) (<-chan Depth, error) {
+	ch := make(chan Depth, 64)
+	c, err := hbproWs.subscribe(hbproWs.topic(pair, "depth.step0"), ch, func() { close(ch) }, func(resp map[string]interface{}) error {
+		tick, isok := resp["tick"].(map[string]interface{})
+		if !isok {
+			return nil
+		}
+		var dp DecimalParser
+		depth := Depth{
+			AskList: hbproWs.parseDepthRecords(&dp, tick["asks"], size),
+			BidList: hbproWs.parseDepthRecords(&dp, tick["bids"], size)}
+		if dp.Err != nil {
+			return dp.Err
+		}
+		select {
+		case ch <- depth:
+		case <-hbproWs.closeCh:
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return c.(chan Depth), nil
+}
+
+func (hbproWs *HuobiProWs) SubscribeTrades(pair CurrencyPair) (<-chan Trade, error) {
+	ch := make(chan Trade, 64)
+	c, err := hbproWs.subscribe(hbproWs.topic(pair, "trade.detail"), ch, func() { close(ch) }, func(resp map[string]interface{}) error {
+		tick, isok := resp["tick"].(map[string]interface{})
+		if !isok {
+			return nil
+		}
+		var dp DecimalParser
+		var trades []Trade
+		data, _ := tick["data"].([]interface{})
+		for _, d := range data {
+			t, isok := d.(map[string]interface{})
+			if !isok {
+				continue
+			}
+			trades = append(trades, Trade{
+				Tid:    ToInt64(t["id"]),
+				Type:   fmt.Sprint(t["direction"]),
+				Amount: dp.Decimal(t["amount"]),
+				Price:  dp.Decimal(t["price"]),
+				Date:   ToInt64(t["ts"])})
+		}
+		if dp.Err != nil {
+			return dp.Err
+		}
+		for _, trade := range trades {
+			select {
+			case ch <- trade:
+			case <-hbproWs.closeCh:
+				return nil
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return c.(chan Trade), nil
+}
+
+func (hbproWs *HuobiProWs) SubscribeKline(pair CurrencyPair, period int) (<-chan Kline, error) {
+	periodStr, isok := wsKlinePeriods[period]
+	if !isok {
+		return nil, ErrNotSupported
+	}
+
+	ch := make(chan Kline, 64)
+	c, err := hbproWs.subscribe(hbproWs.topic(pair, "kline."+periodStr), ch, func() { close(ch) }, func(resp map[string]interface{}) error {
+		tick, isok := resp["tick"].(map[string]interface{})
+		if !isok {
+			return nil
+		}
+		kline := Kline{
+			Timestamp: ToInt64(tick["id"]),
+			Open:      ToFloat64(tick["open"]),
+			Close:     ToFloat64(tick["close"]),
+			High:      ToFloat64(tick["high"]),
+			Low:       ToFloat64(tick["low"]),
+			Vol:       ToFloat64(tick["amount"])}
+		select {
+		case ch <- kline:
+		case <-hbproWs.closeCh:
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return c.(chan Kline), nil
+}
+
+func (hbproWs *HuobiProWs) Close() error {
+	hbproWs.once.Do(func() { close(hbproWs.closeCh) })
+	hbproWs.l.Lock()
+	wsConn := hbproWs.wsConn
+	hbproWs.l.Unlock()
+	if wsConn == nil {
+		return nil
+	}
+	return wsConn.Close()
+}
+
+func (hbproWs *HuobiProWs) topic(pair CurrencyPair, channel string) string {
+	return fmt.Sprintf("market.%s.%s", strings.ToLower(pair.ToSymbol("")), channel)
+}
+
+func (hbproWs *HuobiProWs) subscribe(topic string, ch interface{}, closer func(), handle func(resp map[string]interface{}) error) (interface{}, error) {
+	hbproWs.l.Lock()
+	defer hbproWs.l.Unlock()
+
+	select {
+	case <-hbproWs.closeCh:
+		return nil, EX_ERR_STREAM_CLOSED
+	default:
+	}
+	if c, isok := hbproWs.chans[topic]; isok {
+		return c, nil
+	}
+
+	if hbproWs.wsConn == nil {
+		wsConn := NewWsConn(hbproWs.wsUrl, hbproWs.handle)
+		wsConn.UnCompressFunc = GzipUnCompress
+		wsConn.ReadTimeout = time.Minute //the server pings every 5s
+		wsConn.CloseHandleFunc = hbproWs.closeChans
+		hbproWs.wsConn = wsConn //set before Connect, the first ping may arrive right away
+		if err := wsConn.Connect(); err != nil {
+			hbproWs.wsConn = nil
+			return nil, err
+		}
+	}
+
+	hbproWs.chans[topic] = ch
+	hbproWs.handles[topic] = handle
+	hbproWs.closers = append(hbproWs.closers, closer)
+
+	hbproWs.id++
+	hbproWs.wsConn.Subscribe(map[string]interface{}{"sub": topic, "id": fmt.Sprint(hbproWs.id)})
+	return ch, nil
+}
+
+func (hbproWs *HuobiProWs) handle(msg []byte) error {
+	var resp map[string]interface{}
+	if err := json.Unmarshal(msg, &resp); err != nil {
+		return err
+	}
+
+	if ping, isok := resp["ping"]; isok {
+		hbproWs.l.Lock()
+		wsConn := hbproWs.wsConn
+		hbproWs.l.Unlock()
+		return wsConn.SendJsonMessage(map[string]interface{}{"pong": ping})
+	}
+
+	if resp["status"] == "error" {
+		return (&HuoBi_V2{}).errorWrapper(resp)
+	}
+
+	ch, isok := resp["ch"].(string)
+	if !isok {
+		return nil
+	}
+
+	hbproWs.l.Lock()
+	handle := hbproWs.handles[ch]
+	hbproWs.l.Unlock()
+
+	if handle != nil {
+		return handle(resp)
+	}
+	return nil
+}
+
+func (hbproWs *HuobiProWs) parseDepthRecords(dp *DecimalParser, records interface{}, size int) DepthRecords {
+	var drs DepthRecords
+	list, _ := records.([]interface{})
+	for _, r := range list {
+		if len(drs) >= size {
+			break
+		}
+		rr, isok := r.([]interface{})
+		if !isok || len(rr) < 2 {
+			continue
+		}
+		drs = append(drs, DepthRecord{Price: dp.Price(rr[0]), Amount: dp.Decimal(rr[1])})
+	}
+	return drs
+}
+
+func (hbproWs *HuobiProWs) closeChans() {
+	hbproWs.l.Lock()
+	defer hbproWs.l.Unlock()
+	for _, closer := range hbproWs.closers {
+		closer()
+	}
+	hbproWs.closers = nil
+}
@@ -0,0 +1,132 @@
+package huobi
+
+import (
+	"bytes"
+	"compress/gzip"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/gorilla/websocket"
+	. "github.com/nntaoli-project/GoEx"
+	"github.com/stretchr/testify/assert"
+)
+
+func gzipMessage(msg string) []byte {
+	var buf bytes.Buffer
+	w := gzip.NewWriter(&buf)
+	w.Write([]byte(msg))
+	w.Close()
+	return buf.Bytes()
+}
+
+//newWsStandIn pings first, then answers every sub with one gzipped push per topic. The first
+//connection is dropped after its first push so the client has to reconnect and resubscribe.
+func newWsStandIn(t *testing.T, pushes map[string]string) (*httptest.Server, chan string, chan string) {
+	subscribed := make(chan string, 16)
+	ponged := make(chan string, 16)
+	upgrader := websocket.Upgrader{}
+	var conns int32
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		conn, err := upgrader.Upgrade(w, r, nil)
+		if err != nil {
+			t.Error(err)
+			return
+		}
+		defer conn.Close()
+		first := atomic.AddInt32(&conns, 1) == 1
+		conn.WriteMessage(websocket.BinaryMessage, gzipMessage(`{"ping":1492420473027}`))
+		for {
+			var req map[string]interface{}
+			if err := conn.ReadJSON(&req); err != nil {
+				return
+			}
+			if pong, isok := req["pong"]; isok {
+				select {
+				case ponged <- fmt.Sprint(int64(pong.(float64))):
+				default:
+				}
+				continue
+			}
+			topic := fmt.Sprint(req["sub"])
+			conn.WriteMessage(websocket.BinaryMessage, gzipMessage(fmt.Sprintf(`{"id":"%s","status":"ok","subbed":"%s","ts":1}`, req["id"], topic)))
+			select {
+			case subscribed <- topic:
+			default:
+			}
+			conn.WriteMessage(websocket.BinaryMessage, gzipMessage(`{"ch":"`+topic+`","ts":1530000000000,"tick":`+pushes[topic]+`}`))
+			if first {
+				return
+			}
+		}
+	}))
+	return srv, subscribed, ponged
+}
+
+func newTestHuobiProWs(srv *httptest.Server) *HuobiProWs {
+	hbproWs := NewHuobiProWs()
+	hbproWs.wsUrl = "ws" + strings.TrimPrefix(srv.URL, "http")
+	return hbproWs
+}
+
+func TestHuobiProWs_SubscribeTicker(t *testing.T) {
+	srv, subscribed, ponged := newWsStandIn(t, map[string]string{
+		"market.btcusdt.ticker": `{"open":6000,"high":6200,"low":6000,"close":6100.5,"amount":1234.5,"vol":7500000,"bid":6100.1,"bidSize":1,"ask":6100.9,"askSize":2}`})
+	defer srv.Close()
+
+	hbproWs := newTestHuobiProWs(srv)
+	ch, err := hbproWs.SubscribeTicker(BTC_USDT)
+	assert.Nil(t, err)
+
+	ticker := <-ch
+	assert.Equal(t, Ticker{Last: RequireDecimal("6100.5"), Buy: RequireDecimal("6100.1"), Sell: RequireDecimal("6100.9"), High: RequireDecimal("6200"), Low: RequireDecimal("6000"), Vol: RequireDecimal("1234.5"), Date: 1530000000000}, ticker)
+	assert.Equal(t, "1492420473027", <-ponged)
+	assert.Equal(t, "market.btcusdt.ticker", <-subscribed)
+
+	select {
+	case topic := <-subscribed:
+		assert.Equal(t, "market.btcusdt.ticker", topic)
+	case <-time.After(5 * time.Second):
+		t.Fatal("not resubscribed after reconnect")
+	}
+	<-ch
+
+	assert.Nil(t, hbproWs.Close())
+	for range ch {
+	}
+}
+
+func TestHuobiProWs_SubscribeDepthTradesKline(t *testing.T) {
+	srv, _, _ := newWsStandIn(t, map[string]string{
+		"market.btcusdt.depth.step0":  `{"bids":[[6100.1,1.5],[6100.0,2]],"asks":[[6100.9,0.5],[6101,3]],"ts":1530000000000}`,
+		"market.btcusdt.trade.detail": `{"id":1,"ts":1530000000000,"data":[{"id":12345,"price":6100.5,"amount":0.01,"direction":"sell","ts":1530000000000},{"id":12346,"price":6101,"amount":0.02,"direction":"buy","ts":1530000000001}]}`,
+		"market.btcusdt.kline.1min":   `{"id":1530000000,"open":1,"close":2,"high":3,"low":0.5,"amount":10,"vol":20,"count":5}`})
+	defer srv.Close()
+
+	hbproWs := newTestHuobiProWs(srv)
+	defer hbproWs.Close()
+
+	depthCh, err := hbproWs.SubscribeDepth(BTC_USDT, 1)
+	assert.Nil(t, err)
+	depth := <-depthCh
+	assert.Equal(t, DepthRecords{{Price: RequireDecimal("6100.1"), Amount: RequireDecimal("1.5")}}, depth.BidList)
+	assert.Equal(t, DepthRecords{{Price: RequireDecimal("6100.9"), Amount: RequireDecimal("0.5")}}, depth.AskList)
+
+	tradeCh, err := hbproWs.SubscribeTrades(BTC_USDT)
+	assert.Nil(t, err)
+	assert.Equal(t, Trade{Tid: 12345, Type: "sell", Amount: RequireDecimal("0.01"), Price: RequireDecimal("6100.5"), Date: 1530000000000}, <-tradeCh)
+	assert.Equal(t, Trade{Tid: 12346, Type: "buy", Amount: RequireDecimal("0.02"), Price: RequireDecimal("6101"), Date: 1530000000001}, <-tradeCh)
+
+	klineCh, err := hbproWs.SubscribeKline(BTC_USDT, KLINE_PERIOD_1MIN)
+	assert.Nil(t, err)
+	assert.Equal(t, Kline{Timestamp: 1530000000, Open: 1, Close: 2, High: 3, Low: 0.5, Vol: 10}, <-klineCh)
+}
+
+func TestHuobiProWs_handleError(t *testing.T) {
+	err := NewHuobiProWs().handle([]byte(`{"status":"error","err-code":"bad-request","err-msg":"invalid topic market.btcxxx.ticker","id":"1"}`))
+	assert.NotNil(t, err)
+}
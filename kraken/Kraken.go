@@ -10,6 +10,7 @@ import (
 	"fmt"
 	"net/http"
 	"net/url"
+	"sort"
 	"strconv"
 	"strings"
 	"time"
@@ -34,13 +35,17 @@ type Kraken struct {
 }
 
 type WithdrawStatus struct {
-	Method string `json:"method"`
-	AClass string `json:"aclass"`
-	Asset  string `json:"asset"`
-	RefID  string `json:"refid"`
-	TXID   string `json:"txid"`
-	Info   string `json:"vol"`
-	Status string `json:"status"`
+	Method     string  `json:"method"`
+	AClass     string  `json:"aclass"`
+	Asset      string  `json:"asset"`
+	RefID      string  `json:"refid"`
+	TXID       string  `json:"txid"`
+	Info       string  `json:"info"`
+	Amount     string  `json:"amount"`
+	Fee        string  `json:"fee"`
+	Time       float64 `json:"time"`
+	Status     string  `json:"status"`
+	StatusProp string  `json:"status-prop"`
 }
 
 var (
@@ -52,10 +57,12 @@ var (
 )
 
 func New(client *http.Client, accesskey, secretkey string) *Kraken {
+	client = goex.ExchangeRateLimitedClient(client, "kraken.com", accesskey, goex.DefaultRateLimitConfig)
 	return &Kraken{client, accesskey, secretkey}
 }
 
 func (k *Kraken) placeOrder(orderType, side, amount, price string, pair goex.CurrencyPair) (*goex.Order, error) {
+	var dp goex.DecimalParser
 	apiuri := "private/AddOrder"
 
 	params := url.Values{}
@@ -77,29 +84,33 @@ func (k *Kraken) placeOrder(orderType, side, amount, price string, pair goex.Cur
 		tradeSide = goex.BUY
 	}
 
-	return &goex.Order{
+	ord := &goex.Order{
 		Currency: pair,
 		OrderID2: resp.TxIds[0],
-		Amount:   goex.ToFloat64(amount),
-		Price:    goex.ToFloat64(price),
+		Amount:   dp.Decimal(amount),
+		Price:    dp.Decimal(price),
 		Side:     tradeSide,
-		Status:   goex.ORDER_UNFINISH}, nil
+		Status:   goex.ORDER_UNFINISH}
+	if dp.Err != nil {
+		return nil, dp.Err
+	}
+	return ord, nil
 }
 
-func (k *Kraken) LimitBuy(amount, price string, currency goex.CurrencyPair) (*goex.Order, error) {
-	return k.placeOrder("limit", "buy", amount, price, currency)
+func (k *Kraken) LimitBuy(amount, price goex.Decimal, currency goex.CurrencyPair) (*goex.Order, error) {
+	return k.placeOrder("limit", "buy", amount.String(), price.String(), currency)
 }
 
-func (k *Kraken) LimitSell(amount, price string, currency goex.CurrencyPair) (*goex.Order, error) {
-	return k.placeOrder("limit", "sell", amount, price, currency)
+func (k *Kraken) LimitSell(amount, price goex.Decimal, currency goex.CurrencyPair) (*goex.Order, error) {
+	return k.placeOrder("limit", "sell", amount.String(), price.String(), currency)
 }
 
-func (k *Kraken) MarketBuy(amount, price string, currency goex.CurrencyPair) (*goex.Order, error) {
-	return k.placeOrder("market", "buy", amount, price, currency)
+func (k *Kraken) MarketBuy(amount, price goex.Decimal, currency goex.CurrencyPair) (*goex.Order, error) {
+	return k.placeOrder("market", "buy", amount.String(), price.String(), currency)
 }
 
-func (k *Kraken) MarketSell(amount, price string, currency goex.CurrencyPair) (*goex.Order, error) {
-	return k.placeOrder("market", "sell", amount, price, currency)
+func (k *Kraken) MarketSell(amount, price goex.Decimal, currency goex.CurrencyPair) (*goex.Order, error) {
+	return k.placeOrder("market", "sell", amount.String(), price.String(), currency)
 }
 
 func (k *Kraken) CancelOrder(orderId string, currency goex.CurrencyPair) (bool, error) {
@@ -116,18 +127,20 @@ func (k *Kraken) CancelOrder(orderId string, currency goex.CurrencyPair) (bool,
 	return true, nil
 }
 
-func (k *Kraken) toOrder(orderinfo interface{}) goex.Order {
+func (k *Kraken) toOrder(orderinfo interface{}) (goex.Order, error) {
+	var dp goex.DecimalParser
 	omap := orderinfo.(map[string]interface{})
 	descmap := omap["descr"].(map[string]interface{})
-	return goex.Order{
-		Amount:     goex.ToFloat64(omap["vol"]),
-		Price:      goex.ToFloat64(descmap["price"]),
-		DealAmount: goex.ToFloat64(omap["vol_exec"]),
-		AvgPrice:   goex.ToFloat64(omap["price"]),
+	ord := goex.Order{
+		Amount:     dp.Decimal(omap["vol"]),
+		Price:      dp.Decimal(descmap["price"]),
+		DealAmount: dp.Decimal(omap["vol_exec"]),
+		AvgPrice:   dp.Decimal(omap["price"]),
 		Side:       k.convertSide(descmap["type"].(string)),
 		Status:     k.convertOrderStatus(omap["status"].(string)),
 		OrderTime:  goex.ToInt(omap["opentm"]),
 	}
+	return ord, dp.Err
 }
 
 func (k *Kraken) GetOrderInfos(txids ...string) ([]goex.Order, error) {
@@ -142,7 +155,10 @@ func (k *Kraken) GetOrderInfos(txids ...string) ([]goex.Order, error) {
 
 	var ords []goex.Order
 	for txid, v := range resultmap {
-		ord := k.toOrder(v)
+		ord, err := k.toOrder(v)
+		if err != nil {
+			return nil, err
+		}
 		ord.OrderID2 = txid
 		ords = append(ords, ord)
 	}
@@ -158,7 +174,9 @@ func (k *Kraken) GetOneOrder(orderId string, currency goex.CurrencyPair) (*goex.
 	}
 
 	if len(orders) == 0 {
-		return nil, errors.New("Could not find the order " + orderId)
+		errCode := goex.EX_ERR_NOT_FIND_ORDER
+		errCode.OriginErrMsg = "Could not find the order " + orderId
+		return nil, errCode
 	}
 
 	ord := &orders[0]
@@ -179,7 +197,10 @@ func (k *Kraken) GetUnfinishOrders(currency goex.CurrencyPair) ([]goex.Order, er
 	var orders []goex.Order
 
 	for txid, v := range result.Open {
-		ord := k.toOrder(v)
+		ord, err := k.toOrder(v)
+		if err != nil {
+			return nil, err
+		}
 		ord.OrderID2 = txid
 		ord.Currency = currency
 		orders = append(orders, ord)
@@ -189,10 +210,62 @@ func (k *Kraken) GetUnfinishOrders(currency goex.CurrencyPair) ([]goex.Order, er
 }
 
 func (k *Kraken) GetOrderHistorys(currency goex.CurrencyPair, currentPage, pageSize int) ([]goex.Order, error) {
-	panic("")
+	return nil, goex.ErrNotSupported
+}
+
+//GetOrderHistory pages ClosedOrders, newest first 50 to a page. Kraken lists the orders of all pairs,
+//a page can come back empty. The cursor is the offset of the next page.
+func (k *Kraken) GetOrderHistory(pair goex.CurrencyPair, start, end int64, cursor string) ([]goex.Order, string, error) {
+	ofs := 0
+	if cursor != "" {
+		var err error
+		if ofs, err = strconv.Atoi(cursor); err != nil {
+			errCode := goex.API_ERR
+			errCode.OriginErrMsg = "bad cursor " + cursor
+			return nil, "", errCode
+		}
+	}
+	params := url.Values{}
+	params.Set("start", strconv.FormatInt(start/1000-1, 10))
+	if end > 0 {
+		params.Set("end", strconv.FormatInt(end/1000+1, 10))
+	}
+	params.Set("ofs", strconv.Itoa(ofs))
+	var result struct {
+		Closed map[string]interface{} `json:"closed"`
+		Count  int                    `json:"count"`
+	}
+	err := k.doAuthenticatedRequest("POST", "private/ClosedOrders", params, &result)
+	if err != nil {
+		return nil, "", err
+	}
+
+	symbol := k.convertPair(pair).ToSymbol("")
+	orders := make([]goex.Order, 0, len(result.Closed))
+	for txid, v := range result.Closed {
+		descmap := v.(map[string]interface{})["descr"].(map[string]interface{})
+		if name, _ := descmap["pair"].(string); !k.isPair(name, symbol) {
+			continue
+		}
+		ord, err := k.toOrder(v)
+		if err != nil {
+			return nil, "", err
+		}
+		ord.OrderID2 = txid
+		ord.Currency = pair
+		orders = append(orders, ord)
+	}
+	sort.Slice(orders, func(i, j int) bool { return orders[i].OrderTime > orders[j].OrderTime })
+
+	next := ""
+	if ofs += len(result.Closed); len(result.Closed) > 0 && ofs < result.Count {
+		next = strconv.Itoa(ofs)
+	}
+	return orders, next, nil
 }
 
 func (k *Kraken) GetAccount() (*goex.Account, error) {
+	var dp goex.DecimalParser
 	params := url.Values{}
 	apiuri := "private/Balance"
 
@@ -208,32 +281,56 @@ func (k *Kraken) GetAccount() (*goex.Account, error) {
 
 	for key, v := range resustmap {
 		currency := k.convertCurrency(key)
-		amount := goex.ToFloat64(v)
+		amount := dp.Decimal(v)
 		//log.Println(symbol, amount)
-		acc.SubAccounts[currency] = goex.SubAccount{Currency: currency, Amount: amount, FrozenAmount: 0, LoanAmount: 0}
+		acc.SubAccounts[currency] = goex.SubAccount{Currency: currency, Amount: amount, FrozenAmount: goex.Decimal{}, LoanAmount: goex.Decimal{}}
 
 		if currency.Symbol == "XBT" {
-			acc.SubAccounts[goex.BTC] = goex.SubAccount{Currency: goex.BTC, Amount: amount, FrozenAmount: 0, LoanAmount: 0}
+			acc.SubAccounts[goex.BTC] = goex.SubAccount{Currency: goex.BTC, Amount: amount, FrozenAmount: goex.Decimal{}, LoanAmount: goex.Decimal{}}
 		}
 	}
 
+	if dp.Err != nil {
+		return nil, dp.Err
+	}
 	return acc, nil
 
 }
 
-func (k *Kraken) Withdraw(currencyPair goex.CurrencyPair, address goex.CryptoAddressReader, amount float64, wallet string, adminPassword string) (*goex.Withdraw, error) {
+//Withdraw sends the amount to req.Key, the name of a withdrawal address set up on kraken
+func (k *Kraken) Withdraw(req goex.WithdrawRequest) (*goex.Withdraw, error) {
 	apiuri := "private/Withdraw"
 	params := url.Values{}
-	a := strconv.FormatFloat(amount, 'f', -1, 64)
-	params.Set("amount", a)
-	params.Set("asset", currencyPair.CurrencyA.String())
-	params.Set("key", address.Tag())
-	var result goex.Withdraw
+	params.Set("amount", req.Amount.String())
+	params.Set("asset", req.Currency.String())
+	params.Set("key", req.Key)
+	var result struct {
+		RefID string `json:"refid"`
+	}
 	err := k.doAuthenticatedRequest("POST", apiuri, params, &result)
 	if err != nil {
 		return nil, err
 	}
-	return &result, nil
+	return &goex.Withdraw{
+		ID:       result.RefID,
+		Currency: req.Currency,
+		Amount:   req.Amount,
+		Address:  req.Address,
+		Status:   goex.WITHDRAW_PENDING,
+		Time:     time.Now().Unix()}, nil
+}
+
+func (k *Kraken) CancelWithdraw(id string, currency goex.Currency, tradePassword string) (bool, error) {
+	apiuri := "private/WithdrawCancel"
+	params := url.Values{}
+	params.Set("asset", currency.String())
+	params.Set("refid", id)
+	var result bool
+	err := k.doAuthenticatedRequest("POST", apiuri, params, &result)
+	if err != nil {
+		return false, err
+	}
+	return result, nil
 }
 
 func (k *Kraken) WithdrawStatus(currency goex.Currency) (*[]WithdrawStatus, error) {
@@ -249,7 +346,258 @@ func (k *Kraken) WithdrawStatus(currency goex.Currency) (*[]WithdrawStatus, erro
 	return &result, nil
 }
 
+//GetWithdraw finds the withdrawal in the recent ones WithdrawStatus returns
+func (k *Kraken) GetWithdraw(id string, currency goex.Currency) (*goex.Withdraw, error) {
+	var dp goex.DecimalParser
+	statuses, err := k.WithdrawStatus(currency)
+	if err != nil {
+		return nil, err
+	}
+	for _, ws := range *statuses {
+		if ws.RefID != id {
+			continue
+		}
+		w := &goex.Withdraw{
+			ID:       ws.RefID,
+			Currency: currency,
+			Amount:   dp.Decimal(ws.Amount),
+			Fee:      dp.Decimal(ws.Fee),
+			Address:  ws.Info,
+			TxID:     ws.TXID,
+			Time:     int64(ws.Time)}
+		switch {
+		case ws.StatusProp == "canceled":
+			w.Status = goex.WITHDRAW_CANCELED
+		case ws.Status == "Success":
+			w.Status = goex.WITHDRAW_COMPLETED
+		case ws.Status == "Failure":
+			w.Status = goex.WITHDRAW_FAILED
+		case ws.Status == "Settled":
+			w.Status = goex.WITHDRAW_PROCESSING
+		default:
+			w.Status = goex.WITHDRAW_PENDING
+		}
+		if dp.Err != nil {
+			return nil, dp.Err
+		}
+		return w, nil
+	}
+	errCode := goex.EX_ERR_NOT_FIND_WITHDRAW
+	errCode.OriginErrMsg = id
+	return nil, errCode
+}
+
+//depositMethod is the first of the ways kraken takes deposits of the asset
+func (k *Kraken) depositMethod(asset string) (string, error) {
+	params := url.Values{}
+	params.Set("asset", asset)
+	var methods []struct {
+		Method string `json:"method"`
+	}
+	err := k.doAuthenticatedRequest("POST", "private/DepositMethods", params, &methods)
+	if err != nil {
+		return "", err
+	}
+	if len(methods) == 0 {
+		return "", goex.ErrNotSupported
+	}
+	return methods[0].Method, nil
+}
+
+func (k *Kraken) GetDepositAddress(currency goex.Currency) (*goex.CryptoAddress, error) {
+	asset := k.convertAsset(currency)
+	method, err := k.depositMethod(asset)
+	if err != nil {
+		return nil, err
+	}
+	params := url.Values{}
+	params.Set("asset", asset)
+	params.Set("method", method)
+	var addresses []struct {
+		Address string `json:"address"`
+		Tag     string `json:"tag"`
+	}
+	err = k.doAuthenticatedRequest("POST", "private/DepositAddresses", params, &addresses)
+	if err != nil {
+		return nil, err
+	}
+	if len(addresses) == 0 {
+		//none generated yet
+		params.Set("new", "true")
+		err = k.doAuthenticatedRequest("POST", "private/DepositAddresses", params, &addresses)
+		if err != nil {
+			return nil, err
+		}
+		if len(addresses) == 0 {
+			return nil, errors.New("no deposit address")
+		}
+	}
+	return &goex.CryptoAddress{
+		Currency:     currency,
+		Address:      addresses[0].Address,
+		Tag:          addresses[0].Tag,
+		ExchangeName: k.GetExchangeName()}, nil
+}
+
+//GetDeposits pages the recent deposits DepositStatus returns
+func (k *Kraken) GetDeposits(currency goex.Currency, currentPage, pageSize int) ([]goex.Deposit, error) {
+	var dp goex.DecimalParser
+	asset := k.convertAsset(currency)
+	method, err := k.depositMethod(asset)
+	if err != nil {
+		return nil, err
+	}
+	params := url.Values{}
+	params.Set("asset", asset)
+	params.Set("method", method)
+	var statuses []WithdrawStatus //deposits come in the same shape
+	err = k.doAuthenticatedRequest("POST", "private/DepositStatus", params, &statuses)
+	if err != nil {
+		return nil, err
+	}
+
+	deposits := make([]goex.Deposit, 0, len(statuses))
+	for _, ds := range statuses {
+		d := goex.Deposit{
+			ID:       ds.RefID,
+			Currency: currency,
+			Amount:   dp.Decimal(ds.Amount),
+			Address:  ds.Info,
+			TxID:     ds.TXID,
+			Time:     int64(ds.Time)}
+		switch ds.Status {
+		case "Success":
+			d.Status = goex.DEPOSIT_COMPLETED
+		case "Failure":
+			d.Status = goex.DEPOSIT_FAILED
+		default:
+			d.Status = goex.DEPOSIT_PENDING
+		}
+		deposits = append(deposits, d)
+	}
+	sort.Slice(deposits, func(i, j int) bool { return deposits[i].Time > deposits[j].Time })
+	if dp.Err != nil {
+		return nil, dp.Err
+	}
+	return goex.DepositsPage(deposits, currentPage, pageSize), nil
+}
+
+//GetTradeFee returns the rates of TradeVolume, which kraken gives in percent
+func (k *Kraken) GetTradeFee(pair goex.CurrencyPair) (*goex.TradeFee, error) {
+	var dp goex.DecimalParser
+	params := url.Values{}
+	params.Set("pair", k.convertPair(pair).ToSymbol(""))
+	params.Set("fee-info", "true")
+	type fees map[string]struct {
+		Fee string `json:"fee"`
+	}
+	var result struct {
+		Fees      fees `json:"fees"`
+		FeesMaker fees `json:"fees_maker"`
+	}
+	err := k.doAuthenticatedRequest("POST", "private/TradeVolume", params, &result)
+	if err != nil {
+		return nil, err
+	}
+
+	//the rates are keyed by kraken's own name of the pair, XXBTZUSD for XBTUSD. A pair without a maker
+	//rate pays the taker one.
+	symbol := k.convertPair(pair).ToSymbol("")
+	percent := goex.NewDecimalFromInt(100)
+	fee := new(goex.TradeFee)
+	found := false
+	for name, f := range result.Fees {
+		if k.isPair(name, symbol) {
+			fee.Taker = dp.Decimal(f.Fee).Div(percent)
+			found = true
+		}
+	}
+	if !found {
+		errCode := goex.EX_ERR_UNKNOWN_FEE
+		errCode.OriginErrMsg = k.GetExchangeName() + " " + symbol
+		return nil, errCode
+	}
+	fee.Maker = fee.Taker
+	for name, f := range result.FeesMaker {
+		if k.isPair(name, symbol) {
+			fee.Maker = dp.Decimal(f.Fee).Div(percent)
+		}
+	}
+	if dp.Err != nil {
+		return nil, dp.Err
+	}
+	return fee, nil
+}
+
+//GetMyTrades reads every page of TradesHistory from since, kraken lists the fills of all pairs newest first
+//50 to a page. The fee is charged in the quote currency.
+func (k *Kraken) GetMyTrades(pair goex.CurrencyPair, since int64, limit int) ([]goex.MyTrade, error) {
+	var dp goex.DecimalParser
+	symbol := k.convertPair(pair).ToSymbol("")
+	var trades []goex.MyTrade
+	for ofs := 0; ; {
+		params := url.Values{}
+		params.Set("start", strconv.FormatInt(since/1000-1, 10))
+		params.Set("ofs", strconv.Itoa(ofs))
+		var result struct {
+			Trades map[string]struct {
+				OrderTxId string  `json:"ordertxid"`
+				Pair      string  `json:"pair"`
+				Time      float64 `json:"time"`
+				Type      string  `json:"type"`
+				Price     string  `json:"price"`
+				Fee       string  `json:"fee"`
+				Vol       string  `json:"vol"`
+				Maker     bool    `json:"maker"`
+			} `json:"trades"`
+			Count int `json:"count"`
+		}
+		err := k.doAuthenticatedRequest("POST", "private/TradesHistory", params, &result)
+		if err != nil {
+			return nil, err
+		}
+
+		for txid, t := range result.Trades {
+			if !k.isPair(t.Pair, symbol) {
+				continue
+			}
+			side := goex.SELL
+			if t.Type == "buy" {
+				side = goex.BUY
+			}
+			trades = append(trades, goex.MyTrade{
+				TradeID:     txid,
+				OrderID:     t.OrderTxId,
+				Currency:    pair,
+				Side:        goex.TradeSide(side),
+				Price:       dp.Decimal(t.Price),
+				Amount:      dp.Decimal(t.Vol),
+				Fee:         dp.Decimal(t.Fee),
+				FeeCurrency: pair.CurrencyB,
+				IsMaker:     t.Maker,
+				Time:        int64(t.Time * 1000)})
+		}
+		ofs += len(result.Trades)
+		if len(result.Trades) == 0 || ofs >= result.Count {
+			break
+		}
+	}
+	if dp.Err != nil {
+		return nil, dp.Err
+	}
+	return goex.MyTradesSince(trades, since, limit), nil
+}
+
+//isPair tells whether kraken's name of a pair, XXBTZUSD, is the symbol, XBTUSD
+func (k *Kraken) isPair(name, symbol string) bool {
+	if name == symbol {
+		return true
+	}
+	return len(name) == 8 && name[1:4]+name[5:] == symbol
+}
+
 func (k *Kraken) GetTicker(currency goex.CurrencyPair) (*goex.Ticker, error) {
+	var dp goex.DecimalParser
 	var resultmap map[string]interface{}
 	err := k.doAuthenticatedRequest("GET", "public/Ticker?pair="+k.convertPair(currency).ToSymbol(""), url.Values{}, &resultmap)
 	if err != nil {
@@ -259,18 +607,22 @@ func (k *Kraken) GetTicker(currency goex.CurrencyPair) (*goex.Ticker, error) {
 	ticker := new(goex.Ticker)
 	for _, t := range resultmap {
 		tickermap := t.(map[string]interface{})
-		ticker.Last = goex.ToFloat64(tickermap["c"].([]interface{})[0])
-		ticker.Buy = goex.ToFloat64(tickermap["b"].([]interface{})[0])
-		ticker.Sell = goex.ToFloat64(tickermap["a"].([]interface{})[0])
-		ticker.Low = goex.ToFloat64(tickermap["l"].([]interface{})[0])
-		ticker.High = goex.ToFloat64(tickermap["h"].([]interface{})[0])
-		ticker.Vol = goex.ToFloat64(tickermap["v"].([]interface{})[0])
+		ticker.Last = dp.Decimal(tickermap["c"].([]interface{})[0])
+		ticker.Buy = dp.Decimal(tickermap["b"].([]interface{})[0])
+		ticker.Sell = dp.Decimal(tickermap["a"].([]interface{})[0])
+		ticker.Low = dp.Decimal(tickermap["l"].([]interface{})[0])
+		ticker.High = dp.Decimal(tickermap["h"].([]interface{})[0])
+		ticker.Vol = dp.Decimal(tickermap["v"].([]interface{})[0])
 	}
 
+	if dp.Err != nil {
+		return nil, dp.Err
+	}
 	return ticker, nil
 }
 
 func (k *Kraken) GetDepth(size int, currency goex.CurrencyPair) (*goex.Depth, error) {
+	var dp goex.DecimalParser
 	apiuri := fmt.Sprintf("public/Depth?pair=%s&count=%d", k.convertPair(currency).ToSymbol(""), size)
 	var resultmap map[string]interface{}
 	err := k.doAuthenticatedRequest("GET", apiuri, url.Values{}, &resultmap)
@@ -287,35 +639,136 @@ func (k *Kraken) GetDepth(size int, currency goex.CurrencyPair) (*goex.Depth, er
 		for _, v := range asksmap {
 			ask := v.([]interface{})
 			dep.AskList = append(dep.AskList, goex.DepthRecord{
-				Price:  goex.ToFloat64(ask[0]),
-				Amount: goex.ToFloat64(ask[1])},
+				Price:  dp.Price(ask[0]),
+				Amount: dp.Decimal(ask[1])},
 			)
 		}
 		for _, v := range bidsmap {
 			bid := v.([]interface{})
 			dep.BidList = append(dep.BidList, goex.DepthRecord{
-				Price:  goex.ToFloat64(bid[0]),
-				Amount: goex.ToFloat64(bid[1])},
+				Price:  dp.Price(bid[0]),
+				Amount: dp.Decimal(bid[1])},
 			)
 		}
 		break
 	}
+	if dp.Err != nil {
+		return nil, dp.Err
+	}
 	return &dep, nil
 }
 
+//klineIntervals are kraken's OHLC intervals in minutes
+var klineIntervals = map[int]int{
+	goex.KLINE_PERIOD_1MIN:  1,
+	goex.KLINE_PERIOD_5MIN:  5,
+	goex.KLINE_PERIOD_15MIN: 15,
+	goex.KLINE_PERIOD_30MIN: 30,
+	goex.KLINE_PERIOD_60MIN: 60,
+	goex.KLINE_PERIOD_4H:    240,
+	goex.KLINE_PERIOD_1DAY:  1440,
+	goex.KLINE_PERIOD_1WEEK: 10080,
+}
+
+//GetKlineRecords reads OHLC, kraken answers the last 720 candles at most whatever since is
 func (k *Kraken) GetKlineRecords(currency goex.CurrencyPair, period, size, since int) ([]goex.Kline, error) {
-	panic("")
+	interval, ok := klineIntervals[period]
+	if !ok {
+		return nil, goex.UnsupportedKlinePeriod(period)
+	}
+	apiuri := fmt.Sprintf("public/OHLC?pair=%s&interval=%d", k.convertPair(currency).ToSymbol(""), interval)
+	if since > 0 {
+		apiuri += fmt.Sprintf("&since=%d", since/1000-1)
+	}
+	var resultmap map[string]interface{}
+	err := k.doAuthenticatedRequest("GET", apiuri, url.Values{}, &resultmap)
+	if err != nil {
+		return nil, err
+	}
+
+	var klines []goex.Kline
+	for key, v := range resultmap {
+		records, ok := v.([]interface{})
+		if key == "last" || !ok {
+			continue
+		}
+		//[time, open, high, low, close, vwap, volume, count]
+		for _, r := range records {
+			record, _ := r.([]interface{})
+			if len(record) < 7 {
+				continue
+			}
+			klines = append(klines, goex.Kline{
+				Timestamp: goex.ToInt64(record[0]),
+				Open:      goex.ToFloat64(record[1]),
+				High:      goex.ToFloat64(record[2]),
+				Low:       goex.ToFloat64(record[3]),
+				Close:     goex.ToFloat64(record[4]),
+				Vol:       goex.ToFloat64(record[6])})
+		}
+	}
+	return goex.KlinesSince(klines, int64(since), size), nil
 }
 
 //非个人，整个交易所的交易记录
+//GetTrades answers up to 1000 trades from since on, kraken's own since counts nanoseconds
 func (k *Kraken) GetTrades(currencyPair goex.CurrencyPair, since int64) ([]goex.Trade, error) {
-	panic("")
+	var dp goex.DecimalParser
+	apiuri := fmt.Sprintf("public/Trades?pair=%s", k.convertPair(currencyPair).ToSymbol(""))
+	if since > 0 {
+		apiuri += fmt.Sprintf("&since=%d", since*int64(time.Millisecond))
+	}
+	var resultmap map[string]interface{}
+	err := k.doAuthenticatedRequest("GET", apiuri, url.Values{}, &resultmap)
+	if err != nil {
+		return nil, err
+	}
+
+	var trades []goex.Trade
+	for key, v := range resultmap {
+		records, ok := v.([]interface{})
+		if key == "last" || !ok {
+			continue
+		}
+		//[price, volume, time, buy/sell, market/limit, miscellaneous, trade id], without the id the trade counts
+		//the rows before it at its time instead, for TradeIterator telling apart the trades of a moment
+		atTime := map[int64]int64{}
+		for _, r := range records {
+			record, _ := r.([]interface{})
+			if len(record) < 4 {
+				continue
+			}
+			trade := goex.Trade{
+				Type:   "buy",
+				Price:  dp.Decimal(record[0]),
+				Amount: dp.Decimal(record[1]),
+				Date:   int64(goex.ToFloat64(record[2]) * 1000)}
+			if record[3] == "s" {
+				trade.Type = "sell"
+			}
+			if len(record) > 6 {
+				trade.Tid = goex.ToInt64(record[6])
+			} else {
+				trade.Tid = atTime[trade.Date]
+				atTime[trade.Date]++
+			}
+			trades = append(trades, trade)
+		}
+	}
+	if dp.Err != nil {
+		return nil, dp.Err
+	}
+	return goex.TradesSinceTime(trades, since), nil
 }
 
 func (k *Kraken) GetExchangeName() string {
 	return "kraken.com"
 }
 
+func (k *Kraken) Capabilities() goex.Capabilities {
+	return goex.Capabilities{MarketOrder: true, Kline: true, Withdraw: true, Deposit: true, Trades: true, TradeFee: true, MyTrades: true, OrderCursor: true}
+}
+
 func (k *Kraken) buildParamsSigned(apiuri string, postForm *url.Values) string {
 	postForm.Set("nonce", fmt.Sprintf("%d", time.Now().UnixNano()))
 	urlPath := API_V0 + apiuri
@@ -365,7 +818,7 @@ func (k *Kraken) doAuthenticatedRequest(method, apiuri string, params url.Values
 	//println(string(resp))
 
 	if len(base.Error) > 0 {
-		return errors.New(base.Error[0])
+		return k.errorWrapper(base.Error[0])
 	}
 
 	return nil
@@ -379,6 +832,13 @@ func (k *Kraken) convertCurrency(currencySymbol string) goex.Currency {
 	return goex.NewCurrency(currencySymbol, "")
 }
 
+func (k *Kraken) convertAsset(currency goex.Currency) string {
+	if "BTC" == currency.Symbol {
+		return goex.XBT.Symbol
+	}
+	return currency.Symbol
+}
+
 func (k *Kraken) convertPair(pair goex.CurrencyPair) goex.CurrencyPair {
 	if "BTC" == pair.CurrencyA.Symbol {
 		return goex.NewCurrencyPair(goex.XBT, pair.CurrencyB)
@@ -414,3 +874,34 @@ func (k *Kraken) convertOrderStatus(status string) goex.TradeStatus {
 	}
 	return goex.ORDER_UNFINISH
 }
+
+func (k *Kraken) errorWrapper(krakenErr string) goex.ApiError {
+	var errCode goex.ApiError
+	switch {
+	case krakenErr == "EAPI:Invalid nonce":
+		errCode = goex.EX_ERR_NONCE
+	case krakenErr == "EAPI:Invalid signature":
+		errCode = goex.EX_ERR_SIGN
+	case krakenErr == "EAPI:Invalid key":
+		errCode = goex.EX_ERR_NOT_FIND_APIKEY
+	case krakenErr == "EAPI:Rate limit exceeded", krakenErr == "EOrder:Rate limit exceeded",
+		krakenErr == "EGeneral:Temporary lockout", strings.HasPrefix(krakenErr, "EOrder:Orders limit exceeded"):
+		errCode = goex.EX_ERR_API_LIMIT
+	case krakenErr == "EOrder:Insufficient funds", krakenErr == "EFunding:Insufficient funds":
+		errCode = goex.EX_ERR_INSUFFICIENT_BALANCE
+	case krakenErr == "EOrder:Unknown order", krakenErr == "EOrder:Invalid order":
+		errCode = goex.EX_ERR_NOT_FIND_ORDER
+	case strings.HasPrefix(krakenErr, "EOrder:Order minimum not met"), strings.HasPrefix(krakenErr, "EGeneral:Invalid arguments:volume"),
+		strings.HasPrefix(krakenErr, "EGeneral:Invalid arguments:price"):
+		errCode = goex.EX_ERR_INVALID_PRECISION
+	case krakenErr == "EQuery:Unknown asset pair":
+		errCode = goex.EX_ERR_INVALID_CURRENCY_PAIR
+	case strings.HasPrefix(krakenErr, "EService:Unavailable"), strings.HasPrefix(krakenErr, "EService:Busy"),
+		krakenErr == "EGeneral:Internal error":
+		errCode = goex.EX_ERR_MAINTENANCE
+	default:
+		errCode = goex.API_ERR
+	}
+	errCode.OriginErrMsg = krakenErr
+	return errCode
+}
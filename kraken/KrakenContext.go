@@ -0,0 +1,68 @@
+package kraken
+
+import (
+	"context"
+
+	"github.com/nntaoli-project/GoEx"
+)
+
+var _ goex.API = (*Kraken)(nil)
+var _ goex.APIWithContext = (*Kraken)(nil)
+
+func (k *Kraken) withContext(ctx context.Context) *Kraken {
+	c := *k
+	c.httpClient = goex.HttpClientWithContext(ctx, k.httpClient)
+	return &c
+}
+
+func (k *Kraken) LimitBuyCtx(ctx context.Context, amount, price goex.Decimal, currency goex.CurrencyPair) (*goex.Order, error) {
+	return k.withContext(ctx).LimitBuy(amount, price, currency)
+}
+
+func (k *Kraken) LimitSellCtx(ctx context.Context, amount, price goex.Decimal, currency goex.CurrencyPair) (*goex.Order, error) {
+	return k.withContext(ctx).LimitSell(amount, price, currency)
+}
+
+func (k *Kraken) MarketBuyCtx(ctx context.Context, amount, price goex.Decimal, currency goex.CurrencyPair) (*goex.Order, error) {
+	return k.withContext(ctx).MarketBuy(amount, price, currency)
+}
+
+func (k *Kraken) MarketSellCtx(ctx context.Context, amount, price goex.Decimal, currency goex.CurrencyPair) (*goex.Order, error) {
+	return k.withContext(ctx).MarketSell(amount, price, currency)
+}
+
+func (k *Kraken) CancelOrderCtx(ctx context.Context, orderId string, currency goex.CurrencyPair) (bool, error) {
+	return k.withContext(ctx).CancelOrder(orderId, currency)
+}
+
+func (k *Kraken) GetOneOrderCtx(ctx context.Context, orderId string, currency goex.CurrencyPair) (*goex.Order, error) {
+	return k.withContext(ctx).GetOneOrder(orderId, currency)
+}
+
+func (k *Kraken) GetUnfinishOrdersCtx(ctx context.Context, currency goex.CurrencyPair) ([]goex.Order, error) {
+	return k.withContext(ctx).GetUnfinishOrders(currency)
+}
+
+func (k *Kraken) GetOrderHistorysCtx(ctx context.Context, currency goex.CurrencyPair, currentPage, pageSize int) ([]goex.Order, error) {
+	return k.withContext(ctx).GetOrderHistorys(currency, currentPage, pageSize)
+}
+
+func (k *Kraken) GetAccountCtx(ctx context.Context) (*goex.Account, error) {
+	return k.withContext(ctx).GetAccount()
+}
+
+func (k *Kraken) GetTickerCtx(ctx context.Context, currency goex.CurrencyPair) (*goex.Ticker, error) {
+	return k.withContext(ctx).GetTicker(currency)
+}
+
+func (k *Kraken) GetDepthCtx(ctx context.Context, size int, currency goex.CurrencyPair) (*goex.Depth, error) {
+	return k.withContext(ctx).GetDepth(size, currency)
+}
+
+func (k *Kraken) GetKlineRecordsCtx(ctx context.Context, currency goex.CurrencyPair, period, size, since int) ([]goex.Kline, error) {
+	return k.withContext(ctx).GetKlineRecords(currency, period, size, since)
+}
+
+func (k *Kraken) GetTradesCtx(ctx context.Context, currencyPair goex.CurrencyPair, since int64) ([]goex.Trade, error) {
+	return k.withContext(ctx).GetTrades(currencyPair, since)
+}
@@ -1,9 +1,12 @@
 package kraken_test
 
 import (
-	"errors"
 	"io/ioutil"
 	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"os"
+	"strings"
 	"testing"
 
 	addresses "github.com/i0n/crypto-addresses"
@@ -30,6 +33,10 @@ type ExchangeKeys struct {
 func GetManifest(configFilePath *string) *Manifest {
 	manifest := &Manifest{}
 	configFile, err := ioutil.ReadFile(*configFilePath)
+	if os.IsNotExist(err) {
+		//the fixtures replay without keys
+		return manifest
+	}
 	if err != nil {
 		panic(err)
 	}
@@ -38,73 +45,295 @@ func GetManifest(configFilePath *string) *Manifest {
 }
 
 var manifest = GetManifest(&configFilePath)
-var k = kraken.New(http.DefaultClient, manifest.Exchanges["kraken.com"].AccessKey, manifest.Exchanges["kraken.com"].SecretKey)
+var fixtures = goex.NewFixtureTransport("testdata/fixtures.json")
+var k = kraken.New(fixtures.Client(), manifest.Exchanges["kraken.com"].AccessKey, manifest.Exchanges["kraken.com"].SecretKey)
 
 var BCH_XBT = goex.NewCurrencyPair(goex.BCH, goex.XBT)
 
 func TestKraken_GetDepth_Bid(t *testing.T) {
 	dep, err := k.GetDepth(2, goex.BTC_USD)
 	assert.Nil(t, err)
-	assert.True(t, dep.BidList[0].Price > dep.BidList[1].Price)
-	t.Log(dep)
+	assert.True(t, dep.BidList[0].Price.GreaterThan(dep.BidList[1].Price))
+	assert.Equal(t, goex.DepthRecords{
+		{Price: goex.RequireDecimal("6268.8"), Amount: goex.RequireDecimal("0.405")},
+		{Price: goex.RequireDecimal("6266.2"), Amount: goex.RequireDecimal("2")}}, dep.BidList)
 }
 
 func TestKraken_GetDepth_Ask(t *testing.T) {
 	dep, err := k.GetDepth(2, goex.BTC_USD)
 	assert.Nil(t, err)
-	assert.True(t, dep.AskList[0].Price < dep.AskList[1].Price)
-	t.Log(dep)
+	assert.True(t, dep.AskList[0].Price.LessThan(dep.AskList[1].Price))
+	assert.Equal(t, goex.DepthRecords{
+		{Price: goex.RequireDecimal("6268.9"), Amount: goex.RequireDecimal("0.752")},
+		{Price: goex.RequireDecimal("6269"), Amount: goex.RequireDecimal("1.5")}}, dep.AskList)
 }
 
 func TestKraken_GetTicker(t *testing.T) {
 	ticker, err := k.GetTicker(goex.ETC_BTC)
 	assert.Nil(t, err)
-	t.Log(ticker)
+	assert.Equal(t, goex.Ticker{Last: goex.RequireDecimal("0.001757"), Buy: goex.RequireDecimal("0.001756"), Sell: goex.RequireDecimal("0.001758"),
+		High: goex.RequireDecimal("0.001774"), Low: goex.RequireDecimal("0.001729"), Vol: goex.RequireDecimal("4183.16731268")}, *ticker)
 }
 
 func TestKraken_GetAccount(t *testing.T) {
 	acc, err := k.GetAccount()
 	assert.Nil(t, err)
-	t.Log(acc)
+	assert.Equal(t, goex.SubAccount{Currency: goex.XBT, Amount: goex.RequireDecimal("0.003")}, acc.SubAccounts[goex.XBT])
+	assert.Equal(t, goex.SubAccount{Currency: goex.ETC, Amount: goex.RequireDecimal("2")}, acc.SubAccounts[goex.ETC])
+	assert.Equal(t, goex.SubAccount{Currency: goex.USD, Amount: goex.RequireDecimal("51.27")}, acc.SubAccounts[goex.USD])
 }
 
 // Test for error...
 func TestKraken_LimitSell(t *testing.T) {
-	ord, err := k.LimitSell("1000000", "690000", goex.BTC_USD)
-	assert.Equal(t, errors.New("EOrder:Insufficient funds"), err)
-	t.Log(ord)
+	ord, err := k.LimitSell(goex.RequireDecimal("1000000"), goex.RequireDecimal("690000"), goex.BTC_USD)
+	assert.True(t, goex.EX_ERR_INSUFFICIENT_BALANCE.Is(err))
+	assert.Contains(t, err.Error(), "EOrder:Insufficient funds")
+	assert.Nil(t, ord)
 }
 
 // Test for error...
 func TestKraken_LimitBuy(t *testing.T) {
-	ord, err := k.LimitBuy("1000000", "61", goex.NewCurrencyPair(goex.XBT, goex.USD))
-	assert.Equal(t, errors.New("EOrder:Insufficient funds"), err)
-	t.Log(ord)
+	ord, err := k.LimitBuy(goex.RequireDecimal("1000000"), goex.RequireDecimal("61"), goex.NewCurrencyPair(goex.XBT, goex.USD))
+	assert.True(t, goex.EX_ERR_INSUFFICIENT_BALANCE.Is(err))
+	assert.Contains(t, err.Error(), "EOrder:Insufficient funds")
+	assert.Nil(t, ord)
 }
 
 func TestKraken_GetUnfinishOrders(t *testing.T) {
 	ords, err := k.GetUnfinishOrders(goex.NewCurrencyPair(goex.XBT, goex.USD))
 	assert.Nil(t, err)
-	t.Log(ords)
+	assert.Len(t, ords, 1)
+	assert.Equal(t, "OQCLML-BW3P3-BUCMWZ", ords[0].OrderID2)
+	assert.Equal(t, goex.RequireDecimal("4500"), ords[0].Price)
+	assert.Equal(t, goex.RequireDecimal("0.002"), ords[0].Amount)
+	assert.Equal(t, 1529919011, ords[0].OrderTime)
+	assert.Equal(t, goex.TradeSide(goex.BUY), ords[0].Side)
+	assert.Equal(t, goex.TradeStatus(goex.ORDER_UNFINISH), ords[0].Status)
 }
 
 // Test for error...
 func TestKraken_CancelOrder(t *testing.T) {
 	r, err := k.CancelOrder("O6EAJC-YAC3C-XDEEXQ", goex.NewCurrencyPair(goex.XBT, goex.USD))
-	assert.Equal(t, errors.New("EOrder:Unknown order"), err)
-	t.Log(r)
+	assert.True(t, goex.EX_ERR_NOT_FIND_ORDER.Is(err))
+	assert.Contains(t, err.Error(), "EOrder:Unknown order")
+	assert.False(t, r)
 }
 
 // Test for error...
 func TestKraken_GetOneOrder(t *testing.T) {
 	ord, err := k.GetOneOrder("ODCRMQ-RDEID-CY334C", goex.BTC_USD)
-	assert.Equal(t, errors.New("Could not find the order ODCRMQ-RDEID-CY334C"), err)
-	t.Log(ord)
+	assert.True(t, goex.EX_ERR_NOT_FIND_ORDER.Is(err))
+	assert.Contains(t, err.Error(), "Could not find the order ODCRMQ-RDEID-CY334C")
+	assert.Nil(t, ord)
 }
 
 func TestKraken_Withdraw(t *testing.T) {
-	_, err := k.Withdraw(goex.ETC_USD, addresses.All["okcoin.com"][goex.ETC], 0.1, "", "")
-	assert.Equal(t, errors.New("EFunding:Invalid amount"), err)
+	_, err := k.Withdraw(goex.WithdrawRequest{Currency: goex.ETC, Amount: goex.RequireDecimal("0.1"), Key: addresses.All["okcoin.com"][goex.ETC].Tag()})
+	assert.True(t, goex.API_ERR.Is(err))
+	assert.Contains(t, err.Error(), "EFunding:Invalid amount")
+}
+
+//rewriteTransport sends every request to the stand-in server
+type rewriteTransport struct {
+	host string
+}
+
+func (rt rewriteTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	req.URL.Scheme = "http"
+	req.URL.Host = rt.host
+	return http.DefaultTransport.RoundTrip(req)
+}
+
+//newTestKraken is a Kraken talking to srv
+func newTestKraken(srv *httptest.Server) *kraken.Kraken {
+	return kraken.New(&http.Client{Transport: rewriteTransport{strings.TrimPrefix(srv.URL, "http://")}}, "", "")
+}
+
+func TestKraken_GetMyTrades(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "/0/private/TradesHistory", r.URL.Path)
+		body, _ := ioutil.ReadAll(r.Body)
+		form, _ := url.ParseQuery(string(body))
+		assert.Equal(t, "1499865548", form.Get("start"))
+		switch form.Get("ofs") {
+		case "0":
+			w.Write([]byte(`{"error":[],"result":{"count":3,"trades":{` +
+				`"TCWJEG-FL4SZ-3FKGH6":{"ordertxid":"OQCLML-BW3P3-BUCMWZ","pair":"XXBTZUSD","time":1499865549.5901,"type":"buy",` +
+				`"ordertype":"limit","price":"2500.0","cost":"250.0","fee":"0.4","vol":"0.1","maker":true},` +
+				`"TKH2SE-M7IF5-CFI7LT":{"ordertxid":"OQCLML-BW3P3-BUCMWY","pair":"XETHZUSD","time":1499865550.0,"type":"sell",` +
+				`"ordertype":"limit","price":"200.0","cost":"200.0","fee":"0.32","vol":"1.0"}}}}`))
+		case "2":
+			w.Write([]byte(`{"error":[],"result":{"count":3,"trades":{` +
+				`"TDLH43-DVQXD-2KHVYY":{"ordertxid":"OQCLML-BW3P3-BUCMWX","pair":"XXBTZUSD","time":1499865600.0,"type":"sell",` +
+				`"ordertype":"market","price":"2510.0","cost":"502.0","fee":"1.3052","vol":"0.2"}}}}`))
+		default:
+			t.Errorf("ofs %s", form.Get("ofs"))
+		}
+	}))
+	defer srv.Close()
+
+	trades, err := newTestKraken(srv).GetMyTrades(goex.BTC_USD, 1499865549000, 0)
+	assert.Nil(t, err)
+	assert.Equal(t, []goex.MyTrade{
+		{TradeID: "TCWJEG-FL4SZ-3FKGH6", OrderID: "OQCLML-BW3P3-BUCMWZ", Currency: goex.BTC_USD, Side: goex.TradeSide(goex.BUY),
+			Price: goex.RequireDecimal("2500.0"), Amount: goex.RequireDecimal("0.1"), Fee: goex.RequireDecimal("0.4"), FeeCurrency: goex.USD,
+			IsMaker: true, Time: 1499865549590},
+		{TradeID: "TDLH43-DVQXD-2KHVYY", OrderID: "OQCLML-BW3P3-BUCMWX", Currency: goex.BTC_USD, Side: goex.TradeSide(goex.SELL),
+			Price: goex.RequireDecimal("2510.0"), Amount: goex.RequireDecimal("0.2"), Fee: goex.RequireDecimal("1.3052"), FeeCurrency: goex.USD,
+			Time: 1499865600000},
+	}, trades)
+}
+
+func TestKraken_GetTradeFee(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "/0/private/TradeVolume", r.URL.Path)
+		body, _ := ioutil.ReadAll(r.Body)
+		form, _ := url.ParseQuery(string(body))
+		assert.Contains(t, []string{"XBTUSD", "LTCUSD"}, form.Get("pair"))
+		assert.Equal(t, "true", form.Get("fee-info"))
+		//kraken may answer the rates of other pairs too, the wanted one is not last here
+		w.Write([]byte(`{"error":[],"result":{"currency":"ZUSD","volume":"0.0000",` +
+			`"fees":{"XXBTZUSD":{"fee":"0.2600"},"XETHZUSD":{"fee":"0.3000"}},` +
+			`"fees_maker":{"XXBTZUSD":{"fee":"0.1600"},"XETHZUSD":{"fee":"0.2000"}}}}`))
+	}))
+	defer srv.Close()
+	api := newTestKraken(srv)
+
+	fee, err := api.GetTradeFee(goex.BTC_USD)
+	assert.Nil(t, err)
+	assert.True(t, fee.Taker.Equal(goex.RequireDecimal("0.0026")))
+	assert.True(t, fee.Maker.Equal(goex.RequireDecimal("0.0016")))
+
+	_, err = api.GetTradeFee(goex.LTC_USD)
+	assert.True(t, goex.EX_ERR_UNKNOWN_FEE.Is(err))
+}
+
+func TestKraken_GetTrades(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "/0/public/Trades", r.URL.Path)
+		assert.Equal(t, "XBTUSD", r.URL.Query().Get("pair"))
+		assert.Equal(t, "1534614057000000000", r.URL.Query().Get("since"))
+		//the rows of the same moment have no id
+		w.Write([]byte(`{"error":[],"result":{"XXBTZUSD":[` +
+			`["6452.20000","0.01000000",1534614057.3215,"b","l",""],` +
+			`["6452.30000","0.20000000",1534614057.3215,"s","m",""],` +
+			`["6452.40000","0.05000000",1534614058.1000,"b","l",""]],"last":"1534614058100000000"}}`))
+	}))
+	defer srv.Close()
+
+	trades, err := newTestKraken(srv).GetTrades(goex.BTC_USD, 1534614057000)
+	assert.Nil(t, err)
+	assert.Equal(t, []goex.Trade{
+		{Tid: 0, Type: "buy", Amount: goex.RequireDecimal("0.01"), Price: goex.RequireDecimal("6452.2"), Date: 1534614057321},
+		{Tid: 1, Type: "sell", Amount: goex.RequireDecimal("0.2"), Price: goex.RequireDecimal("6452.3"), Date: 1534614057321},
+		{Tid: 0, Type: "buy", Amount: goex.RequireDecimal("0.05"), Price: goex.RequireDecimal("6452.4"), Date: 1534614058100},
+	}, trades)
+}
+
+func TestKraken_errorWrapper(t *testing.T) {
+	for krakenErr, errCode := range map[string]goex.ApiError{
+		"EAPI:Invalid nonce":                goex.EX_ERR_NONCE,
+		"EAPI:Invalid signature":            goex.EX_ERR_SIGN,
+		"EAPI:Invalid key":                  goex.EX_ERR_NOT_FIND_APIKEY,
+		"EAPI:Rate limit exceeded":          goex.EX_ERR_API_LIMIT,
+		"EGeneral:Temporary lockout":        goex.EX_ERR_API_LIMIT,
+		"EOrder:Orders limit exceeded":      goex.EX_ERR_API_LIMIT,
+		"EOrder:Insufficient funds":         goex.EX_ERR_INSUFFICIENT_BALANCE,
+		"EFunding:Insufficient funds":       goex.EX_ERR_INSUFFICIENT_BALANCE,
+		"EOrder:Unknown order":              goex.EX_ERR_NOT_FIND_ORDER,
+		"EOrder:Order minimum not met":      goex.EX_ERR_INVALID_PRECISION,
+		"EGeneral:Invalid arguments:volume": goex.EX_ERR_INVALID_PRECISION,
+		"EQuery:Unknown asset pair":         goex.EX_ERR_INVALID_CURRENCY_PAIR,
+		"EService:Unavailable":              goex.EX_ERR_MAINTENANCE,
+		"EService:Busy":                     goex.EX_ERR_MAINTENANCE,
+		"EGeneral:Internal error":           goex.EX_ERR_MAINTENANCE,
+		"EFunding:Invalid amount":           goex.API_ERR,
+	} {
+		srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			w.Write([]byte(`{"error":["` + krakenErr + `"],"result":{}}`))
+		}))
+		_, err := newTestKraken(srv).GetTicker(goex.BTC_USD)
+		srv.Close()
+		assert.True(t, errCode.Is(err), krakenErr)
+		assert.Equal(t, krakenErr, err.(goex.ApiError).OriginErrMsg)
+	}
+}
+
+func TestMain(m *testing.M) {
+	os.Exit(fixtures.Run(m.Run))
 }
 
 // TODO Write more tests
+
+func TestKraken_GetKlineRecords(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		q := r.URL.Query()
+		assert.Equal(t, "/0/public/OHLC", r.URL.Path)
+		assert.Equal(t, "XBTUSD", q.Get("pair"))
+		assert.Equal(t, "60", q.Get("interval"))
+		assert.Equal(t, "1525132799", q.Get("since"))
+		w.Write([]byte(`{"error":[],"result":{"XXBTZUSD":[[1525132800,"9250.0","9300.0","9200.0","9280.0","9262.1","12.5",120],` +
+			`[1525136400,"9280.0","9290.0","9240.0","9260.0","9266.3","8.25",85]],"last":1525136400}}`))
+	}))
+	defer srv.Close()
+
+	klines, err := newTestKraken(srv).GetKlineRecords(goex.BTC_USD, goex.KLINE_PERIOD_60MIN, 2, 1525132800000)
+	assert.Nil(t, err)
+	assert.Equal(t, []goex.Kline{
+		{Timestamp: 1525132800, Open: 9250, Close: 9280, High: 9300, Low: 9200, Vol: 12.5},
+		{Timestamp: 1525136400, Open: 9280, Close: 9260, High: 9290, Low: 9240, Vol: 8.25}}, klines)
+}
+
+func TestKraken_Withdraw_Request(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "/0/private/Withdraw", r.URL.Path)
+		body, _ := ioutil.ReadAll(r.Body)
+		form, _ := url.ParseQuery(string(body))
+		assert.Equal(t, "ETC", form.Get("asset"))
+		assert.Equal(t, "10", form.Get("amount"))
+		assert.Equal(t, "my etc wallet", form.Get("key"))
+		w.Write([]byte(`{"error":[],"result":{"refid":"AGBSO6T-UFMTTQ-I7KGS6"}}`))
+	}))
+	defer srv.Close()
+
+	withdraw, err := newTestKraken(srv).Withdraw(goex.WithdrawRequest{Currency: goex.ETC, Amount: goex.RequireDecimal("10"), Key: "my etc wallet"})
+	assert.Nil(t, err)
+	assert.Equal(t, "AGBSO6T-UFMTTQ-I7KGS6", withdraw.ID)
+	assert.Equal(t, goex.WITHDRAW_PENDING, withdraw.Status)
+}
+
+func TestKraken_GetWithdraw(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "/0/private/WithdrawStatus", r.URL.Path)
+		body, _ := ioutil.ReadAll(r.Body)
+		form, _ := url.ParseQuery(string(body))
+		assert.Equal(t, "ETC", form.Get("asset"))
+		w.Write([]byte(`{"error":[],"result":[` +
+			`{"method":"Ether Classic","aclass":"currency","asset":"XETC","refid":"AGBSO6T-1","txid":"0x9a2fe1","info":"0x5e4f","amount":"9.9900000000","fee":"0.0100000000","time":1525152612,"status":"Success"},` +
+			`{"method":"Ether Classic","aclass":"currency","asset":"XETC","refid":"AGBSO6T-2","txid":"","info":"0x5e4f","amount":"9.9900000000","fee":"0.0100000000","time":1525152612,"status":"Failure"},` +
+			`{"method":"Ether Classic","aclass":"currency","asset":"XETC","refid":"AGBSO6T-3","txid":"","info":"0x5e4f","amount":"9.9900000000","fee":"0.0100000000","time":1525152612,"status":"Settled"},` +
+			`{"method":"Ether Classic","aclass":"currency","asset":"XETC","refid":"AGBSO6T-4","txid":"","info":"0x5e4f","amount":"9.9900000000","fee":"0.0100000000","time":1525152612,"status":"Pending","status-prop":"canceled"},` +
+			`{"method":"Ether Classic","aclass":"currency","asset":"XETC","refid":"AGBSO6T-5","txid":"","info":"0x5e4f","amount":"9.9900000000","fee":"0.0100000000","time":1525152612,"status":"Initial"}]}`))
+	}))
+	defer srv.Close()
+	api := newTestKraken(srv)
+
+	withdraw, err := api.GetWithdraw("AGBSO6T-1", goex.ETC)
+	assert.Nil(t, err)
+	assert.Equal(t, goex.Withdraw{ID: "AGBSO6T-1", Currency: goex.ETC, Amount: goex.RequireDecimal("9.99"), Fee: goex.RequireDecimal("0.01"),
+		Address: "0x5e4f", TxID: "0x9a2fe1", Status: goex.WITHDRAW_COMPLETED, Time: 1525152612}, *withdraw)
+
+	for id, status := range map[string]goex.WithdrawStatus{
+		"AGBSO6T-2": goex.WITHDRAW_FAILED,
+		"AGBSO6T-3": goex.WITHDRAW_PROCESSING,
+		"AGBSO6T-4": goex.WITHDRAW_CANCELED,
+		"AGBSO6T-5": goex.WITHDRAW_PENDING,
+	} {
+		withdraw, err := api.GetWithdraw(id, goex.ETC)
+		assert.Nil(t, err)
+		assert.Equal(t, status, withdraw.Status, id)
+	}
+
+	_, err = api.GetWithdraw("AGBSO6T-6", goex.ETC)
+	assert.True(t, goex.EX_ERR_NOT_FIND_WITHDRAW.Is(err))
+}
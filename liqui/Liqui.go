@@ -25,6 +25,7 @@ type Liqui struct {
 }
 
 func New(client *http.Client, accessKey, secretKey string) *Liqui {
+	client = ExchangeRateLimitedClient(client, EXCHANGE_NAME, accessKey, DefaultRateLimitConfig)
 	return &Liqui{accessKey, secretKey, client}
 }
 
@@ -33,6 +34,7 @@ func (liqui *Liqui) GetExchangeName() string {
 }
 
 func (liqui *Liqui) GetTicker(currency CurrencyPair) (*Ticker, error) {
+	var dp DecimalParser
 	cur := strings.ToLower(currency.ToSymbol("_"))
 	if cur == "nil" {
 		log.Println("Unsupport The CurrencyPair")
@@ -60,53 +62,68 @@ func (liqui *Liqui) GetTicker(currency CurrencyPair) (*Ticker, error) {
 	//	fmt.Println(cur, " tickerMap:", tickerMap)
 	date := tickerMap["updated"].(float64)
 	ticker.Date = uint64(date)
-	ticker.Last = tickerMap["last"].(float64)
-	ticker.Buy = tickerMap["buy"].(float64)
-	ticker.Sell = tickerMap["sell"].(float64)
-	ticker.Low = tickerMap["low"].(float64)
-	ticker.High = tickerMap["high"].(float64)
-	ticker.Vol = tickerMap["vol"].(float64)
-
+	ticker.Last = dp.Decimal(tickerMap["last"])
+	ticker.Buy = dp.Decimal(tickerMap["buy"])
+	ticker.Sell = dp.Decimal(tickerMap["sell"])
+	ticker.Low = dp.Decimal(tickerMap["low"])
+	ticker.High = dp.Decimal(tickerMap["high"])
+	ticker.Vol = dp.Decimal(tickerMap["vol"])
+
+	if dp.Err != nil {
+		return nil, dp.Err
+	}
 	return &ticker, nil
 }
 
-func (liqui *Liqui) GetDepth() string {
-	panic("not implements")
+func (liqui *Liqui) GetDepth(size int, currency CurrencyPair) (*Depth, error) {
+	return nil, ErrNotSupported
+}
+
+func (liqui *Liqui) GetAccount() (*Account, error) {
+	return nil, ErrNotSupported
 }
-func (liqui *Liqui) GetAccount() string {
-	panic("not implements")
+
+func (liqui *Liqui) LimitBuy(amount, price Decimal, currency CurrencyPair) (*Order, error) {
+	return nil, ErrNotSupported
 }
-func (liqui *Liqui) LimitBuy() string {
-	panic("not implements")
+
+func (liqui *Liqui) LimitSell(amount, price Decimal, currency CurrencyPair) (*Order, error) {
+	return nil, ErrNotSupported
 }
-func (liqui *Liqui) LimitSell() string {
-	panic("not implements")
+
+func (liqui *Liqui) MarketBuy(amount, price Decimal, currency CurrencyPair) (*Order, error) {
+	return nil, ErrNotSupported
 }
-func (liqui *Liqui) MarketBuy() string {
-	panic("not implements")
+
+func (liqui *Liqui) MarketSell(amount, price Decimal, currency CurrencyPair) (*Order, error) {
+	return nil, ErrNotSupported
 }
-func (liqui *Liqui) MarketSell() string {
-	panic("not implements")
+
+func (liqui *Liqui) CancelOrder(orderId string, currency CurrencyPair) (bool, error) {
+	return false, ErrNotSupported
 }
-func (liqui *Liqui) CancelOrder() string {
-	panic("not implements")
+
+func (liqui *Liqui) GetOneOrder(orderId string, currency CurrencyPair) (*Order, error) {
+	return nil, ErrNotSupported
 }
-func (liqui *Liqui) GetOneOrder() string {
-	panic("not implements")
+
+func (liqui *Liqui) GetUnfinishOrders(currency CurrencyPair) ([]Order, error) {
+	return nil, ErrNotSupported
 }
-func (liqui *Liqui) GetUnfinishOrders() string {
-	panic("not implements")
+
+func (liqui *Liqui) Capabilities() Capabilities {
+	return Capabilities{}
 }
 
-func (bn *Binance) GetKlineRecords(currency CurrencyPair, period, size, since int) ([]Kline, error) {
-	panic("not implements")
+func (bn *Liqui) GetKlineRecords(currency CurrencyPair, period, size, since int) ([]Kline, error) {
+	return nil, ErrNotSupported
 }
 
 //非个人，整个交易所的交易记录
-func (bn *Binance) GetTrades(currencyPair CurrencyPair, since int64) ([]Trade, error) {
-	panic("not implements")
+func (bn *Liqui) GetTrades(currencyPair CurrencyPair, since int64) ([]Trade, error) {
+	return nil, ErrNotSupported
 }
 
-func (bn *Binance) GetOrderHistorys(currency CurrencyPair, currentPage, pageSize int) ([]Order, error) {
-	panic("not implements")
+func (bn *Liqui) GetOrderHistorys(currency CurrencyPair, currentPage, pageSize int) ([]Order, error) {
+	return nil, ErrNotSupported
 }
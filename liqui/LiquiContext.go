@@ -0,0 +1,68 @@
+package liqui
+
+import (
+	"context"
+
+	. "github.com/nntaoli-project/GoEx"
+)
+
+var _ API = (*Liqui)(nil)
+var _ APIWithContext = (*Liqui)(nil)
+
+func (liqui *Liqui) withContext(ctx context.Context) *Liqui {
+	c := *liqui
+	c.httpClient = HttpClientWithContext(ctx, liqui.httpClient)
+	return &c
+}
+
+func (liqui *Liqui) GetTickerCtx(ctx context.Context, currency CurrencyPair) (*Ticker, error) {
+	return liqui.withContext(ctx).GetTicker(currency)
+}
+
+func (liqui *Liqui) LimitBuyCtx(ctx context.Context, amount, price Decimal, currency CurrencyPair) (*Order, error) {
+	return liqui.withContext(ctx).LimitBuy(amount, price, currency)
+}
+
+func (liqui *Liqui) LimitSellCtx(ctx context.Context, amount, price Decimal, currency CurrencyPair) (*Order, error) {
+	return liqui.withContext(ctx).LimitSell(amount, price, currency)
+}
+
+func (liqui *Liqui) MarketBuyCtx(ctx context.Context, amount, price Decimal, currency CurrencyPair) (*Order, error) {
+	return liqui.withContext(ctx).MarketBuy(amount, price, currency)
+}
+
+func (liqui *Liqui) MarketSellCtx(ctx context.Context, amount, price Decimal, currency CurrencyPair) (*Order, error) {
+	return liqui.withContext(ctx).MarketSell(amount, price, currency)
+}
+
+func (liqui *Liqui) CancelOrderCtx(ctx context.Context, orderId string, currency CurrencyPair) (bool, error) {
+	return liqui.withContext(ctx).CancelOrder(orderId, currency)
+}
+
+func (liqui *Liqui) GetOneOrderCtx(ctx context.Context, orderId string, currency CurrencyPair) (*Order, error) {
+	return liqui.withContext(ctx).GetOneOrder(orderId, currency)
+}
+
+func (liqui *Liqui) GetUnfinishOrdersCtx(ctx context.Context, currency CurrencyPair) ([]Order, error) {
+	return liqui.withContext(ctx).GetUnfinishOrders(currency)
+}
+
+func (liqui *Liqui) GetOrderHistorysCtx(ctx context.Context, currency CurrencyPair, currentPage, pageSize int) ([]Order, error) {
+	return liqui.withContext(ctx).GetOrderHistorys(currency, currentPage, pageSize)
+}
+
+func (liqui *Liqui) GetAccountCtx(ctx context.Context) (*Account, error) {
+	return liqui.withContext(ctx).GetAccount()
+}
+
+func (liqui *Liqui) GetDepthCtx(ctx context.Context, size int, currency CurrencyPair) (*Depth, error) {
+	return liqui.withContext(ctx).GetDepth(size, currency)
+}
+
+func (liqui *Liqui) GetKlineRecordsCtx(ctx context.Context, currency CurrencyPair, period, size, since int) ([]Kline, error) {
+	return liqui.withContext(ctx).GetKlineRecords(currency, period, size, since)
+}
+
+func (liqui *Liqui) GetTradesCtx(ctx context.Context, currencyPair CurrencyPair, since int64) ([]Trade, error) {
+	return liqui.withContext(ctx).GetTrades(currencyPair, since)
+}
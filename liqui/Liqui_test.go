@@ -0,0 +1,33 @@
+package liqui
+
+import (
+	"github.com/nntaoli-project/GoEx"
+	"github.com/stretchr/testify/assert"
+	"os"
+	"testing"
+)
+
+var fixtures = goex.NewFixtureTransport("testdata/fixtures.json")
+var liqui = New(fixtures.Client(), "", "")
+
+func TestLiqui_GetTicker(t *testing.T) {
+	ticker, err := liqui.GetTicker(goex.ETH_BTC)
+	assert.Nil(t, err)
+	assert.Equal(t, uint64(1529920350), ticker.Date)
+	assert.True(t, ticker.Last.Equal(goex.RequireDecimal("0.07196")))
+	assert.True(t, ticker.Buy.Equal(goex.RequireDecimal("0.07190105")))
+	assert.True(t, ticker.Vol.Equal(goex.RequireDecimal("152.38046213")))
+}
+
+func TestLiqui_NotSupported(t *testing.T) {
+	_, err := liqui.GetDepth(5, goex.ETH_BTC)
+	assert.True(t, goex.ErrNotSupported.Is(err))
+	_, err = liqui.LimitBuy(goex.RequireDecimal("1"), goex.RequireDecimal("0.07"), goex.ETH_BTC)
+	assert.True(t, goex.ErrNotSupported.Is(err))
+	_, err = liqui.CancelOrder("1", goex.ETH_BTC)
+	assert.True(t, goex.ErrNotSupported.Is(err))
+}
+
+func TestMain(m *testing.M) {
+	os.Exit(fixtures.Run(m.Run))
+}
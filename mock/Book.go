@@ -0,0 +1,140 @@
+package mock
+
+import (
+	. "github.com/nntaoli-project/GoEx"
+	"sort"
+)
+
+//order is an Order of the account with what the engine needs to match it
+type order struct {
+	Order
+	buy    bool
+	limit  Decimal //the price it matches up to, for a market buy the worst one the book offered when placed
+	ioc    bool    //market orders cancel what they can't fill at once
+	frozen Decimal //what is still frozen for it, of the quote currency for buys and the base for sells
+}
+
+func (o *order) remaining() Decimal {
+	return o.Amount.Sub(o.DealAmount)
+}
+
+func (o *order) open() bool {
+	return o.Status == ORDER_UNFINISH || o.Status == ORDER_PART_FINISH
+}
+
+//entry is an order of the account, or a level of the injected depth, resting in a book
+type entry struct {
+	order  *order //nil for injected depth
+	price  Decimal
+	amount Decimal //what is left of it
+	seq    int64   //time priority, lower is older
+}
+
+//book holds both sides of a pair, each best price first and then oldest first
+type book struct {
+	bids, asks []*entry
+}
+
+func (b *book) side(buy bool) *[]*entry {
+	if buy {
+		return &b.bids
+	}
+	return &b.asks
+}
+
+func (b *book) insert(buy bool, e *entry) {
+	side := b.side(buy)
+	i := sort.Search(len(*side), func(i int) bool {
+		c := (*side)[i].price.Cmp(e.price)
+		if buy {
+			c = -c
+		}
+		return c > 0 || c == 0 && (*side)[i].seq > e.seq
+	})
+	*side = append(*side, nil)
+	copy((*side)[i+1:], (*side)[i:])
+	(*side)[i] = e
+}
+
+func (b *book) remove(buy bool, e *entry) {
+	side := b.side(buy)
+	for i, v := range *side {
+		if v == e {
+			*side = append((*side)[:i], (*side)[i+1:]...)
+			return
+		}
+	}
+}
+
+//depth is the injected depth of a side, in book order
+func (b *book) depth(buy bool) []*entry {
+	var levels []*entry
+	for _, e := range *b.side(buy) {
+		if e.order == nil {
+			levels = append(levels, e)
+		}
+	}
+	return levels
+}
+
+//removeDepth takes the injected depth out of both sides, the orders stay
+func (b *book) removeDepth() {
+	for _, buy := range []bool{true, false} {
+		side := b.side(buy)
+		orders := (*side)[:0]
+		for _, e := range *side {
+			if e.order != nil {
+				orders = append(orders, e)
+			}
+		}
+		*side = orders
+	}
+}
+
+//crosses tells whether a buy, or a sell, limited at limit matches what rests at price
+func crosses(buy bool, limit, price Decimal) bool {
+	if buy {
+		return price.Cmp(limit) <= 0
+	}
+	return price.Cmp(limit) >= 0
+}
+
+//walk returns the worst price a market order of amount reaches on the side, and the amount the side holds
+//up to it, less than amount when the side runs out
+func (b *book) walk(buy bool, amount Decimal) (Decimal, Decimal) {
+	var worst, total Decimal
+	for _, e := range *b.side(buy) {
+		if total.Cmp(amount) >= 0 {
+			break
+		}
+		worst = e.price
+		total = total.Add(e.amount)
+	}
+	if total.GreaterThan(amount) {
+		total = amount
+	}
+	return worst, total
+}
+
+//levels sums the side by price, best first, at most size of them when size > 0
+func (b *book) levels(buy bool, size int) DepthRecords {
+	var records DepthRecords
+	for _, e := range *b.side(buy) {
+		if n := len(records); n > 0 && records[n-1].Price.Equal(e.price) {
+			records[n-1].Amount = records[n-1].Amount.Add(e.amount)
+			continue
+		}
+		if size > 0 && len(records) == size {
+			break
+		}
+		records = append(records, DepthRecord{Price: e.price, Amount: e.amount})
+	}
+	return records
+}
+
+func minDecimal(d, d2 Decimal) Decimal {
+	if d2.LessThan(d) {
+		return d2
+	}
+	return d
+}
@@ -0,0 +1,617 @@
+package mock
+
+import (
+	"fmt"
+	. "github.com/nntaoli-project/GoEx"
+	"sort"
+	"strconv"
+	"sync"
+	"time"
+)
+
+//Exchange is an in-memory exchange implementing API for one account, to test strategies offline. The
+//account's orders rest in a book per pair and match by price, then time, against each other and against
+//the depth injected with SetDepth. Resting orders also fill when SetDepth crosses them or a trade added
+//with AddTrades reaches their price.
+//
+//Fees are taken from what a fill pays out, the base currency of buys and the quote currency of sells.
+//Market orders take amount in the base currency and cancel what the book can't fill at once.
+//
+//With a Latency, orders and cancels reach the book that long after they are made, by Now. Every call runs
+//those arrived first, an order the account can't fund when it arrives is rejected.
+type Exchange struct {
+	Name     string
+	Fee      TradeFee
+	Now      func() time.Time //the clock of orders and trades, set it for a virtual one
+	Latency  time.Duration
+	Slippage Decimal //a fraction of the price, taker fills against the injected depth are that much worse within the order's price
+
+	l        sync.Mutex
+	balances map[Currency]SubAccount
+	books    map[CurrencyPair]*book
+	orders   map[int]*order
+	history  []*order //every order, oldest first
+	tape     map[CurrencyPair][]Trade
+	klines   map[CurrencyPair]map[int][]Kline
+	myTrades []MyTrade
+	arrivals []arrival //in the order they were made
+	seq      int64
+}
+
+//arrival is an order or a cancel on its way to the book
+type arrival struct {
+	at  int64 //unix milliseconds
+	run func()
+}
+
+func New() *Exchange {
+	return &Exchange{
+		Name:     "mock",
+		Now:      time.Now,
+		balances: map[Currency]SubAccount{},
+		books:    map[CurrencyPair]*book{},
+		orders:   map[int]*order{},
+		tape:     map[CurrencyPair][]Trade{},
+		klines:   map[CurrencyPair]map[int][]Kline{}}
+}
+
+func (ex *Exchange) nowMs() int64 {
+	return ex.Now().UnixNano() / int64(time.Millisecond)
+}
+
+//lock locks ex and runs what arrived by Now
+func (ex *Exchange) lock() {
+	ex.l.Lock()
+	ex.settle()
+}
+
+func (ex *Exchange) settle() {
+	now := ex.nowMs()
+	i := 0
+	for ; i < len(ex.arrivals) && ex.arrivals[i].at <= now; i++ {
+		ex.arrivals[i].run()
+	}
+	ex.arrivals = ex.arrivals[i:]
+}
+
+//Settle runs the orders and cancels arrived by Now, every call does it first
+func (ex *Exchange) Settle() {
+	ex.lock()
+	ex.l.Unlock()
+}
+
+//after runs f once Latency passed, at once without one
+func (ex *Exchange) after(f func()) {
+	if ex.Latency <= 0 {
+		f()
+		return
+	}
+	ex.arrivals = append(ex.arrivals, arrival{at: ex.Now().Add(ex.Latency).UnixNano() / int64(time.Millisecond), run: f})
+}
+
+func (ex *Exchange) nextSeq() int64 {
+	ex.seq++
+	return ex.seq
+}
+
+func (ex *Exchange) book(pair CurrencyPair) *book {
+	b, ok := ex.books[pair]
+	if !ok {
+		b = new(book)
+		ex.books[pair] = b
+	}
+	return b
+}
+
+//SetBalance sets what the account has available of currency, frozen amounts are kept
+func (ex *Exchange) SetBalance(currency Currency, amount Decimal) {
+	ex.lock()
+	defer ex.l.Unlock()
+	sub := ex.balances[currency]
+	sub.Currency = currency
+	sub.Amount = amount
+	ex.balances[currency] = sub
+}
+
+func (ex *Exchange) Balance(currency Currency) SubAccount {
+	ex.lock()
+	defer ex.l.Unlock()
+	sub := ex.balances[currency]
+	sub.Currency = currency
+	return sub
+}
+
+//move adds available and frozen, either may be negative, to the balance of currency
+func (ex *Exchange) move(currency Currency, available, frozen Decimal) {
+	sub := ex.balances[currency]
+	sub.Currency = currency
+	sub.Amount = sub.Amount.Add(available)
+	sub.FrozenAmount = sub.FrozenAmount.Add(frozen)
+	ex.balances[currency] = sub
+}
+
+func (ex *Exchange) freeze(o *order, currency Currency, amount Decimal) error {
+	if available := ex.balances[currency].Amount; available.LessThan(amount) {
+		errCode := EX_ERR_INSUFFICIENT_BALANCE
+		errCode.OriginErrMsg = fmt.Sprintf("needs %s %s, %s available", amount, currency, available)
+		return errCode
+	}
+	ex.move(currency, amount.Neg(), amount)
+	o.frozen = amount
+	return nil
+}
+
+//SetDepth replaces the depth injected for pair, what the account's orders match besides each other.
+//Resting orders the new depth crosses fill at their own price.
+//
+//A level at a price already there keeps its place in the queue up to what it still holds, so the depth
+//that was ahead of an order stays ahead of it, and only what the level grew by queues behind the order.
+func (ex *Exchange) SetDepth(pair CurrencyPair, depth *Depth) {
+	ex.lock()
+	defer ex.l.Unlock()
+	b := ex.book(pair)
+	old := map[bool][]*entry{true: b.depth(true), false: b.depth(false)}
+	b.removeDepth()
+	for _, buy := range []bool{true, false} {
+		records := depth.AskList
+		if buy {
+			records = depth.BidList
+		}
+		//the exchanges list the asks either way, insert them best first to keep their time priority
+		records = append(DepthRecords{}, records...)
+		if buy {
+			sort.Sort(sort.Reverse(records))
+		} else {
+			sort.Sort(records)
+		}
+		for _, r := range records {
+			left := r.Amount
+			for i, e := range old[buy] {
+				if left.Sign() <= 0 {
+					break
+				}
+				if e != nil && e.price.Equal(r.Price) {
+					e.amount = minDecimal(e.amount, left)
+					left = left.Sub(e.amount)
+					b.insert(buy, e)
+					old[buy][i] = nil
+				}
+			}
+			if left.Sign() > 0 {
+				b.insert(buy, &entry{price: r.Price, amount: left, seq: ex.nextSeq()})
+			}
+		}
+	}
+	ex.uncross(pair, b)
+}
+
+//uncross matches what crosses in the book, the older entry is the maker and sets the price. Injected
+//depth crossing itself is left alone.
+func (ex *Exchange) uncross(pair CurrencyPair, b *book) {
+	for len(b.bids) > 0 && len(b.asks) > 0 {
+		bid, ask := b.bids[0], b.asks[0]
+		if bid.price.LessThan(ask.price) || bid.order == nil && ask.order == nil {
+			return
+		}
+		maker, takerBuys := bid, false
+		if ask.seq < bid.seq {
+			maker, takerBuys = ask, true
+		}
+		qty := minDecimal(bid.amount, ask.amount)
+		for _, e := range []*entry{bid, ask} {
+			if e.order != nil {
+				ex.fill(e.order, qty, maker.price, e == maker)
+			}
+			e.amount = e.amount.Sub(qty)
+		}
+		ex.printTrade(pair, qty, maker.price, takerBuys)
+		if bid.amount.Sign() <= 0 {
+			b.bids = b.bids[1:]
+		}
+		if ask.amount.Sign() <= 0 {
+			b.asks = b.asks[1:]
+		}
+	}
+}
+
+//AddTrades adds trades of the rest of the market to the tape of pair. Each takes what it reaches of the
+//book, bids at or above its price and asks at or below, in priority and up to its amount. The injected
+//depth ahead of the account's resting orders is taken first, the orders fill with what is left.
+func (ex *Exchange) AddTrades(pair CurrencyPair, trades ...Trade) {
+	ex.lock()
+	defer ex.l.Unlock()
+	b := ex.book(pair)
+	for _, t := range trades {
+		ex.tape[pair] = append(ex.tape[pair], t)
+		left := t.Amount
+		for _, buy := range []bool{true, false} {
+			side := b.side(buy)
+			for i := 0; i < len(*side) && left.Sign() > 0; {
+				e := (*side)[i]
+				if !crosses(!buy, t.Price, e.price) {
+					break
+				}
+				qty := minDecimal(left, e.amount)
+				if e.order != nil {
+					ex.fill(e.order, qty, e.price, true)
+				}
+				left = left.Sub(qty)
+				e.amount = e.amount.Sub(qty)
+				if e.amount.Sign() <= 0 {
+					*side = append((*side)[:i], (*side)[i+1:]...)
+				} else {
+					i++
+				}
+			}
+		}
+	}
+}
+
+//SetKlines replaces the candles GetKlineRecords answers for pair and period
+func (ex *Exchange) SetKlines(pair CurrencyPair, period int, klines []Kline) {
+	ex.lock()
+	defer ex.l.Unlock()
+	if ex.klines[pair] == nil {
+		ex.klines[pair] = map[int][]Kline{}
+	}
+	ex.klines[pair][period] = append([]Kline{}, klines...)
+}
+
+//AddKlines appends candles, newer than those already there, to what GetKlineRecords answers for pair and period
+func (ex *Exchange) AddKlines(pair CurrencyPair, period int, klines ...Kline) {
+	ex.lock()
+	defer ex.l.Unlock()
+	if ex.klines[pair] == nil {
+		ex.klines[pair] = map[int][]Kline{}
+	}
+	ex.klines[pair][period] = append(ex.klines[pair][period], klines...)
+}
+
+//printTrade adds a match of the engine to the tape
+func (ex *Exchange) printTrade(pair CurrencyPair, qty, price Decimal, takerBuys bool) {
+	trade := Trade{Tid: ex.nextSeq(), Type: "sell", Amount: qty, Price: price, Date: ex.nowMs()}
+	if takerBuys {
+		trade.Type = "buy"
+	}
+	ex.tape[pair] = append(ex.tape[pair], trade)
+}
+
+//fill books qty of o filled at price
+func (ex *Exchange) fill(o *order, qty, price Decimal, maker bool) {
+	rate := ex.Fee.Taker
+	if maker {
+		rate = ex.Fee.Maker
+	}
+	base, quote := o.Currency.CurrencyA, o.Currency.CurrencyB
+	value := qty.Mul(price)
+	myTrade := MyTrade{TradeID: strconv.FormatInt(ex.nextSeq(), 10), OrderID: o.OrderID2, Currency: o.Currency, Side: o.Side,
+		Price: price, Amount: qty, IsMaker: maker, Time: ex.nowMs()}
+	if o.buy {
+		myTrade.Fee, myTrade.FeeCurrency = qty.Mul(rate), base
+		frozen := qty.Mul(o.limit)
+		o.frozen = o.frozen.Sub(frozen)
+		ex.move(quote, frozen.Sub(value), frozen.Neg())
+		ex.move(base, qty.Sub(myTrade.Fee), Decimal{})
+	} else {
+		myTrade.Fee, myTrade.FeeCurrency = value.Mul(rate), quote
+		o.frozen = o.frozen.Sub(qty)
+		ex.move(base, Decimal{}, qty.Neg())
+		ex.move(quote, value.Sub(myTrade.Fee), Decimal{})
+	}
+	ex.myTrades = append(ex.myTrades, myTrade)
+
+	deal := o.DealAmount.Add(qty)
+	o.AvgPrice = o.AvgPrice.Mul(o.DealAmount).Add(value).Div(deal)
+	o.DealAmount = deal
+	o.Fee = o.Fee.Add(myTrade.Fee)
+	o.Status = ORDER_PART_FINISH
+	if o.remaining().Sign() <= 0 {
+		o.Status = ORDER_FINISH
+	}
+}
+
+//release unfreezes what is left of o
+func (ex *Exchange) release(o *order) {
+	currency := o.Currency.CurrencyA
+	if o.buy {
+		currency = o.Currency.CurrencyB
+	}
+	ex.move(currency, o.frozen, o.frozen.Neg())
+	o.frozen = Decimal{}
+}
+
+func (ex *Exchange) place(side TradeSide, amount, price Decimal, pair CurrencyPair) (*Order, error) {
+	if amount.Sign() <= 0 || (side == BUY || side == SELL) && price.Sign() <= 0 {
+		errCode := EX_ERR_INVALID_ORDER_SIZE
+		errCode.OriginErrMsg = fmt.Sprintf("amount %s, price %s", amount, price)
+		return nil, errCode
+	}
+	ex.lock()
+	defer ex.l.Unlock()
+	o := &order{Order: Order{Price: price, Amount: amount, Currency: pair, Side: side, Status: ORDER_UNFINISH, OrderTime: int(ex.nowMs())},
+		buy: side == BUY || side == BUY_MARKET, limit: price, ioc: side == BUY_MARKET || side == SELL_MARKET}
+	id := len(ex.history) + 1
+	o.OrderID, o.OrderID2 = id, strconv.Itoa(id)
+
+	if ex.Latency <= 0 {
+		if err := ex.submit(o); err != nil {
+			return nil, err
+		}
+	} else {
+		ex.after(func() {
+			if err := ex.submit(o); err != nil {
+				o.Status = ORDER_REJECT
+			}
+		})
+	}
+	ex.orders[id] = o
+	ex.history = append(ex.history, o)
+	ord := o.Order
+	return &ord, nil
+}
+
+//submit freezes what o needs, matches it and rests what is left of a limit order
+func (ex *Exchange) submit(o *order) error {
+	pair := o.Currency
+	b := ex.book(pair)
+	var err error
+	switch o.Side {
+	case BUY:
+		err = ex.freeze(o, pair.CurrencyB, o.Amount.Mul(o.Price))
+	case SELL, SELL_MARKET:
+		err = ex.freeze(o, pair.CurrencyA, o.Amount)
+		if o.Side == SELL_MARKET {
+			o.limit = Decimal{}
+		}
+	case BUY_MARKET:
+		//pay at most the worst price the book offers for the amount, and only for what it holds
+		worst, available := b.walk(false, o.Amount)
+		o.limit = worst.Mul(NewDecimalFromInt(1).Add(ex.Slippage))
+		err = ex.freeze(o, pair.CurrencyB, available.Mul(o.limit))
+	}
+	if err != nil {
+		return err
+	}
+
+	opposite := b.side(!o.buy)
+	for len(*opposite) > 0 && o.remaining().Sign() > 0 {
+		e := (*opposite)[0]
+		if o.buy && o.limit.Sign() == 0 || !crosses(o.buy, o.limit, e.price) {
+			break
+		}
+		qty := minDecimal(o.remaining(), e.amount)
+		if e.order != nil {
+			ex.fill(o, qty, e.price, false)
+			ex.fill(e.order, qty, e.price, true)
+		} else {
+			ex.fill(o, qty, ex.slipped(o, e.price), false)
+		}
+		ex.printTrade(pair, qty, e.price, o.buy)
+		e.amount = e.amount.Sub(qty)
+		if e.amount.Sign() <= 0 {
+			*opposite = (*opposite)[1:]
+		}
+	}
+
+	if o.remaining().Sign() > 0 {
+		if o.ioc {
+			ex.release(o)
+			o.Status = ORDER_CANCEL
+		} else {
+			b.insert(o.buy, &entry{order: o, price: o.limit, amount: o.remaining(), seq: ex.nextSeq()})
+		}
+	}
+	return nil
+}
+
+//slipped is the price o gets taking price from the injected depth, Slippage worse but within o's limit
+func (ex *Exchange) slipped(o *order, price Decimal) Decimal {
+	if ex.Slippage.Sign() <= 0 {
+		return price
+	}
+	one := NewDecimalFromInt(1)
+	if o.buy {
+		return minDecimal(price.Mul(one.Add(ex.Slippage)), o.limit)
+	}
+	if slipped := price.Mul(one.Sub(ex.Slippage)); slipped.GreaterThan(o.limit) {
+		return slipped
+	}
+	return o.limit
+}
+
+func (ex *Exchange) LimitBuy(amount, price Decimal, currency CurrencyPair) (*Order, error) {
+	return ex.place(BUY, amount, price, currency)
+}
+
+func (ex *Exchange) LimitSell(amount, price Decimal, currency CurrencyPair) (*Order, error) {
+	return ex.place(SELL, amount, price, currency)
+}
+
+func (ex *Exchange) MarketBuy(amount, price Decimal, currency CurrencyPair) (*Order, error) {
+	return ex.place(BUY_MARKET, amount, price, currency)
+}
+
+func (ex *Exchange) MarketSell(amount, price Decimal, currency CurrencyPair) (*Order, error) {
+	return ex.place(SELL_MARKET, amount, price, currency)
+}
+
+func (ex *Exchange) findOrder(orderId string, currency CurrencyPair) (*order, error) {
+	id, _ := strconv.Atoi(orderId)
+	o, ok := ex.orders[id]
+	if !ok || o.Currency != currency {
+		errCode := EX_ERR_NOT_FIND_ORDER
+		errCode.OriginErrMsg = orderId
+		return nil, errCode
+	}
+	return o, nil
+}
+
+func (ex *Exchange) CancelOrder(orderId string, currency CurrencyPair) (bool, error) {
+	ex.lock()
+	defer ex.l.Unlock()
+	o, err := ex.findOrder(orderId, currency)
+	if err != nil {
+		return false, err
+	}
+	if !o.open() {
+		errCode := EX_ERR_CANCEL_ORDER_FAIL
+		errCode.OriginErrMsg = "order " + orderId + " is " + o.Status.String()
+		return false, errCode
+	}
+	//with a Latency the order may fill before the cancel arrives
+	ex.after(func() {
+		if o.open() {
+			ex.cancel(o)
+		}
+	})
+	return true, nil
+}
+
+func (ex *Exchange) cancel(o *order) {
+	b := ex.book(o.Currency)
+	for _, e := range *b.side(o.buy) {
+		if e.order == o {
+			b.remove(o.buy, e)
+			break
+		}
+	}
+	ex.release(o)
+	o.Status = ORDER_CANCEL
+}
+
+func (ex *Exchange) GetOneOrder(orderId string, currency CurrencyPair) (*Order, error) {
+	ex.lock()
+	defer ex.l.Unlock()
+	o, err := ex.findOrder(orderId, currency)
+	if err != nil {
+		return nil, err
+	}
+	ord := o.Order
+	return &ord, nil
+}
+
+func (ex *Exchange) GetUnfinishOrders(currency CurrencyPair) ([]Order, error) {
+	ex.lock()
+	defer ex.l.Unlock()
+	orders := []Order{}
+	for _, o := range ex.history {
+		if o.Currency == currency && o.open() {
+			orders = append(orders, o.Order)
+		}
+	}
+	return orders, nil
+}
+
+//GetOrderHistorys pages the orders of currency no longer open, newest first
+func (ex *Exchange) GetOrderHistorys(currency CurrencyPair, currentPage, pageSize int) ([]Order, error) {
+	ex.lock()
+	defer ex.l.Unlock()
+	var closed []Order
+	for i := len(ex.history) - 1; i >= 0; i-- {
+		if o := ex.history[i]; o.Currency == currency && !o.open() {
+			closed = append(closed, o.Order)
+		}
+	}
+	if currentPage < 1 {
+		currentPage = 1
+	}
+	start := (currentPage - 1) * pageSize
+	if start >= len(closed) {
+		return []Order{}, nil
+	}
+	end := start + pageSize
+	if pageSize <= 0 || end > len(closed) {
+		end = len(closed)
+	}
+	return closed[start:end], nil
+}
+
+func (ex *Exchange) GetAccount() (*Account, error) {
+	ex.lock()
+	defer ex.l.Unlock()
+	account := &Account{Exchange: ex.Name, SubAccounts: make(map[Currency]SubAccount, len(ex.balances))}
+	for currency, sub := range ex.balances {
+		account.SubAccounts[currency] = sub
+	}
+	return account, nil
+}
+
+//GetTicker answers the best prices of the book, and the last price, high, low and volume of the tape's last 24 hours
+func (ex *Exchange) GetTicker(currency CurrencyPair) (*Ticker, error) {
+	ex.lock()
+	defer ex.l.Unlock()
+	now := ex.Now()
+	ticker := &Ticker{Date: uint64(now.Unix())}
+	b := ex.book(currency)
+	if len(b.bids) > 0 {
+		ticker.Buy = b.bids[0].price
+	}
+	if len(b.asks) > 0 {
+		ticker.Sell = b.asks[0].price
+	}
+	dayAgo := now.Add(-24*time.Hour).UnixNano() / int64(time.Millisecond)
+	for _, t := range ex.tape[currency] {
+		ticker.Last = t.Price
+		if t.Date < dayAgo {
+			continue
+		}
+		if ticker.High.IsZero() || t.Price.GreaterThan(ticker.High) {
+			ticker.High = t.Price
+		}
+		if ticker.Low.IsZero() || t.Price.LessThan(ticker.Low) {
+			ticker.Low = t.Price
+		}
+		ticker.Vol = ticker.Vol.Add(t.Amount)
+	}
+	return ticker, nil
+}
+
+//GetDepth answers the injected depth and the account's resting orders, summed by price
+func (ex *Exchange) GetDepth(size int, currency CurrencyPair) (*Depth, error) {
+	ex.lock()
+	defer ex.l.Unlock()
+	b := ex.book(currency)
+	dep := &Depth{BidList: b.levels(true, size), AskList: b.levels(false, size)}
+	sort.Sort(sort.Reverse(dep.AskList))
+	return dep, nil
+}
+
+func (ex *Exchange) GetKlineRecords(currency CurrencyPair, period, size, since int) ([]Kline, error) {
+	ex.lock()
+	defer ex.l.Unlock()
+	klines := append([]Kline{}, ex.klines[currency][period]...)
+	return KlinesSince(klines, int64(since), size), nil
+}
+
+//非个人，整个交易所的交易记录
+func (ex *Exchange) GetTrades(currencyPair CurrencyPair, since int64) ([]Trade, error) {
+	ex.lock()
+	defer ex.l.Unlock()
+	trades := append([]Trade{}, ex.tape[currencyPair]...)
+	return TradesSinceTime(trades, since), nil
+}
+
+func (ex *Exchange) GetMyTrades(pair CurrencyPair, since int64, limit int) ([]MyTrade, error) {
+	ex.lock()
+	defer ex.l.Unlock()
+	var trades []MyTrade
+	for _, t := range ex.myTrades {
+		if t.Currency == pair {
+			trades = append(trades, t)
+		}
+	}
+	return MyTradesSince(trades, since, limit), nil
+}
+
+func (ex *Exchange) GetTradeFee(pair CurrencyPair) (*TradeFee, error) {
+	fee := ex.Fee
+	return &fee, nil
+}
+
+func (ex *Exchange) GetExchangeName() string {
+	return ex.Name
+}
+
+func (ex *Exchange) Capabilities() Capabilities {
+	return Capabilities{MarketOrder: true, Kline: true, Trades: true, OrderHistory: true, TradeFee: true, MyTrades: true}
+}
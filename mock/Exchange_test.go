@@ -0,0 +1,205 @@
+package mock
+
+import (
+	"testing"
+	"time"
+
+	"github.com/nntaoli-project/GoEx"
+	"github.com/stretchr/testify/assert"
+)
+
+var d = goex.RequireDecimal
+
+func newExchange() *Exchange {
+	ex := New()
+	ex.Fee = goex.TradeFee{Maker: d("0.001"), Taker: d("0.002")}
+	ex.Now = func() time.Time { return time.Unix(1530000000, 0) }
+	ex.SetBalance(goex.BTC, d("10"))
+	ex.SetBalance(goex.USDT, d("1000"))
+	ex.SetDepth(goex.BTC_USDT, &goex.Depth{
+		AskList: goex.DepthRecords{{Price: d("101"), Amount: d("2")}, {Price: d("100"), Amount: d("1")}},
+		BidList: goex.DepthRecords{{Price: d("99"), Amount: d("1")}, {Price: d("98"), Amount: d("2")}}})
+	return ex
+}
+
+func assertBalance(t *testing.T, ex *Exchange, currency goex.Currency, available, frozen string) {
+	sub := ex.Balance(currency)
+	assert.True(t, sub.Amount.Equal(d(available)), "%s available %s, want %s", currency, sub.Amount, available)
+	assert.True(t, sub.FrozenAmount.Equal(d(frozen)), "%s frozen %s, want %s", currency, sub.FrozenAmount, frozen)
+}
+
+func TestExchange_LimitBuy(t *testing.T) {
+	ex := newExchange()
+
+	//takes 1@100, pays 100 of the 201 frozen, gets back the 0.5 it saved and rests the other 1@100.5
+	ord, err := ex.LimitBuy(d("2"), d("100.5"), goex.BTC_USDT)
+	assert.Nil(t, err)
+	assert.Equal(t, goex.TradeStatus(goex.ORDER_PART_FINISH), ord.Status)
+	assert.True(t, ord.DealAmount.Equal(d("1")))
+	assert.True(t, ord.AvgPrice.Equal(d("100")))
+	assert.True(t, ord.Fee.Equal(d("0.002")))
+	assertBalance(t, ex, goex.USDT, "799.5", "100.5")
+	assertBalance(t, ex, goex.BTC, "10.998", "0")
+
+	depth, err := ex.GetDepth(5, goex.BTC_USDT)
+	assert.Nil(t, err)
+	assert.Equal(t, goex.DepthRecords{{Price: d("100.5"), Amount: d("1")}, {Price: d("99"), Amount: d("1")}, {Price: d("98"), Amount: d("2")}}, depth.BidList)
+	assert.Equal(t, goex.DepthRecords{{Price: d("101"), Amount: d("2")}}, depth.AskList)
+
+	//cancelling gives back what is still frozen
+	ok, err := ex.CancelOrder(ord.OrderID2, goex.BTC_USDT)
+	assert.True(t, ok)
+	assert.Nil(t, err)
+	assertBalance(t, ex, goex.USDT, "900", "0")
+	_, err = ex.CancelOrder(ord.OrderID2, goex.BTC_USDT)
+	assert.True(t, goex.EX_ERR_CANCEL_ORDER_FAIL.Is(err))
+
+	_, err = ex.LimitBuy(d("100"), d("100"), goex.BTC_USDT)
+	assert.True(t, goex.EX_ERR_INSUFFICIENT_BALANCE.Is(err))
+}
+
+func TestExchange_RestingOrders(t *testing.T) {
+	ex := newExchange()
+	first, _ := ex.LimitSell(d("1"), d("100.5"), goex.BTC_USDT)
+	second, _ := ex.LimitSell(d("1"), d("100.5"), goex.BTC_USDT)
+	assertBalance(t, ex, goex.BTC, "8", "2")
+
+	//the market trades at the price, taking the 1@100 of the depth, then the older order
+	ex.AddTrades(goex.BTC_USDT, goex.Trade{Tid: 1, Type: "buy", Amount: d("2.5"), Price: d("100.5"), Date: 1530000000000})
+	ord, _ := ex.GetOneOrder(first.OrderID2, goex.BTC_USDT)
+	assert.Equal(t, goex.TradeStatus(goex.ORDER_FINISH), ord.Status)
+	ord, _ = ex.GetOneOrder(second.OrderID2, goex.BTC_USDT)
+	assert.Equal(t, goex.TradeStatus(goex.ORDER_PART_FINISH), ord.Status)
+	assert.True(t, ord.DealAmount.Equal(d("0.5")))
+	//1.5 * 100.5 less the maker fee
+	assertBalance(t, ex, goex.USDT, "1150.599250", "0")
+
+	//the depth moves through the rest of it, which fills at its own price
+	ex.SetDepth(goex.BTC_USDT, &goex.Depth{BidList: goex.DepthRecords{{Price: d("102"), Amount: d("5")}}})
+	ord, _ = ex.GetOneOrder(second.OrderID2, goex.BTC_USDT)
+	assert.Equal(t, goex.TradeStatus(goex.ORDER_FINISH), ord.Status)
+	assert.True(t, ord.AvgPrice.Equal(d("100.5")))
+	assertBalance(t, ex, goex.BTC, "8", "0")
+
+	trades, err := ex.GetMyTrades(goex.BTC_USDT, 0, 0)
+	assert.Nil(t, err)
+	assert.Len(t, trades, 3)
+	assert.True(t, trades[2].IsMaker)
+
+	orders, err := ex.GetOrderHistorys(goex.BTC_USDT, 1, 10)
+	assert.Nil(t, err)
+	assert.Equal(t, []int{second.OrderID, first.OrderID}, []int{orders[0].OrderID, orders[1].OrderID})
+}
+
+func TestExchange_AddTradesDepthAhead(t *testing.T) {
+	ex := newExchange()
+	ord, _ := ex.LimitSell(d("1"), d("101"), goex.BTC_USDT)
+
+	//the 1@100 and the 2@101 injected before the order are ahead of it
+	ex.AddTrades(goex.BTC_USDT, goex.Trade{Tid: 1, Type: "buy", Amount: d("2.5"), Price: d("101"), Date: 1530000000000})
+	ord, _ = ex.GetOneOrder(ord.OrderID2, goex.BTC_USDT)
+	assert.Equal(t, goex.TradeStatus(goex.ORDER_UNFINISH), ord.Status)
+
+	ex.AddTrades(goex.BTC_USDT, goex.Trade{Tid: 2, Type: "buy", Amount: d("1"), Price: d("101"), Date: 1530000001000})
+	ord, _ = ex.GetOneOrder(ord.OrderID2, goex.BTC_USDT)
+	assert.Equal(t, goex.TradeStatus(goex.ORDER_PART_FINISH), ord.Status)
+	assert.True(t, ord.DealAmount.Equal(d("0.5")))
+
+	//the depth taken is gone from the book
+	dep, _ := ex.GetDepth(5, goex.BTC_USDT)
+	assert.Len(t, dep.AskList, 1)
+	assert.True(t, dep.AskList[0].Amount.Equal(d("0.5")))
+}
+
+func TestExchange_SetDepthQueue(t *testing.T) {
+	ex := newExchange()
+	ord, _ := ex.LimitSell(d("1"), d("101"), goex.BTC_USDT)
+
+	//the same depth again, the 2@101 stays ahead of the order
+	ex.SetDepth(goex.BTC_USDT, &goex.Depth{AskList: goex.DepthRecords{{Price: d("100"), Amount: d("1")}, {Price: d("101"), Amount: d("2")}}})
+	ex.AddTrades(goex.BTC_USDT, goex.Trade{Tid: 1, Type: "buy", Amount: d("2.5"), Price: d("101"), Date: 1530000000000})
+	ord, _ = ex.GetOneOrder(ord.OrderID2, goex.BTC_USDT)
+	assert.Equal(t, goex.TradeStatus(goex.ORDER_UNFINISH), ord.Status)
+
+	//0.5 is left ahead, the level shrinks to 0.25 of it, what it grows by is behind the order
+	ex.SetDepth(goex.BTC_USDT, &goex.Depth{AskList: goex.DepthRecords{{Price: d("101"), Amount: d("0.25")}}})
+	ex.SetDepth(goex.BTC_USDT, &goex.Depth{AskList: goex.DepthRecords{{Price: d("101"), Amount: d("4")}}})
+	ex.AddTrades(goex.BTC_USDT, goex.Trade{Tid: 2, Type: "buy", Amount: d("1"), Price: d("101"), Date: 1530000001000})
+	ord, _ = ex.GetOneOrder(ord.OrderID2, goex.BTC_USDT)
+	assert.Equal(t, goex.TradeStatus(goex.ORDER_PART_FINISH), ord.Status)
+	assert.True(t, ord.DealAmount.Equal(d("0.75")))
+}
+
+func TestExchange_MarketOrders(t *testing.T) {
+	ex := newExchange()
+
+	//the book holds 3, the rest is cancelled
+	ord, err := ex.MarketBuy(d("5"), goex.Decimal{}, goex.BTC_USDT)
+	assert.Nil(t, err)
+	assert.Equal(t, goex.TradeStatus(goex.ORDER_CANCEL), ord.Status)
+	assert.True(t, ord.DealAmount.Equal(d("3")))
+	assertBalance(t, ex, goex.USDT, "698", "0")
+
+	ord, err = ex.MarketSell(d("2"), goex.Decimal{}, goex.BTC_USDT)
+	assert.Nil(t, err)
+	assert.Equal(t, goex.TradeStatus(goex.ORDER_FINISH), ord.Status)
+	assert.True(t, ord.AvgPrice.Equal(d("98.5")))
+
+	ticker, err := ex.GetTicker(goex.BTC_USDT)
+	assert.Nil(t, err)
+	assert.True(t, ticker.Last.Equal(d("98")))
+	assert.True(t, ticker.High.Equal(d("101")))
+	assert.True(t, ticker.Vol.Equal(d("5")))
+	assert.True(t, ticker.Buy.Equal(d("98")))
+	assert.True(t, ticker.Sell.IsZero())
+}
+
+func TestExchange_SelfMatch(t *testing.T) {
+	ex := New()
+	ex.SetBalance(goex.BTC, d("1"))
+	ex.SetBalance(goex.USDT, d("100"))
+	sell, _ := ex.LimitSell(d("1"), d("50"), goex.BTC_USDT)
+	buy, _ := ex.LimitBuy(d("1"), d("60"), goex.BTC_USDT)
+	assert.Equal(t, goex.TradeStatus(goex.ORDER_FINISH), buy.Status)
+	assert.True(t, buy.AvgPrice.Equal(d("50")))
+	ord, _ := ex.GetOneOrder(sell.OrderID2, goex.BTC_USDT)
+	assert.Equal(t, goex.TradeStatus(goex.ORDER_FINISH), ord.Status)
+	assertBalance(t, ex, goex.BTC, "1", "0")
+	assertBalance(t, ex, goex.USDT, "100", "0")
+}
+
+func TestExchange_LatencyAndSlippage(t *testing.T) {
+	ex := newExchange()
+	now := time.Unix(1530000000, 0)
+	ex.Now = func() time.Time { return now }
+	ex.Latency = 100 * time.Millisecond
+	ex.Slippage = d("0.01")
+
+	ord, err := ex.MarketBuy(d("1"), goex.Decimal{}, goex.BTC_USDT)
+	assert.Nil(t, err)
+	assert.Equal(t, goex.TradeStatus(goex.ORDER_UNFINISH), ord.Status)
+	assertBalance(t, ex, goex.USDT, "1000", "0")
+
+	//the book moves before the order arrives, it takes the new best ask and slips 1%
+	now = now.Add(50 * time.Millisecond)
+	ex.SetDepth(goex.BTC_USDT, &goex.Depth{AskList: goex.DepthRecords{{Price: d("110"), Amount: d("1")}}})
+	now = now.Add(50 * time.Millisecond)
+	ord, _ = ex.GetOneOrder(ord.OrderID2, goex.BTC_USDT)
+	assert.Equal(t, goex.TradeStatus(goex.ORDER_FINISH), ord.Status)
+	assert.True(t, ord.AvgPrice.Equal(d("111.1")))
+
+	ord, _ = ex.LimitBuy(d("1"), d("105"), goex.BTC_USDT)
+	now = now.Add(50 * time.Millisecond)
+	ok, _ := ex.CancelOrder(ord.OrderID2, goex.BTC_USDT)
+	assert.True(t, ok)
+	//the order rests at 105 until the cancel arrives
+	now = now.Add(50 * time.Millisecond)
+	ex.Settle()
+	ord, _ = ex.GetOneOrder(ord.OrderID2, goex.BTC_USDT)
+	assert.Equal(t, goex.TradeStatus(goex.ORDER_UNFINISH), ord.Status)
+	assertBalance(t, ex, goex.USDT, "783.9", "105")
+	now = now.Add(50 * time.Millisecond)
+	ord, _ = ex.GetOneOrder(ord.OrderID2, goex.BTC_USDT)
+	assert.Equal(t, goex.TradeStatus(goex.ORDER_CANCEL), ord.Status)
+	assertBalance(t, ex, goex.USDT, "888.9", "0")
+}
@@ -0,0 +1,145 @@
+package mock
+
+import (
+	. "github.com/nntaoli-project/GoEx"
+	"sync"
+)
+
+//PaperAPI wraps a real API to trade on paper against live market data. GetTicker, GetDepth, GetTrades and
+//GetKlineRecords pass through, the depth and the new trades they answer are fed to Ledger, an Exchange
+//holding the simulated balances and orders every account call goes to. A resting order fills when a depth
+//or a trade observed crosses it, so it only fills while the strategy polls the market, or calls Observe.
+//
+//The live book doesn't know about the paper orders, liquidity they took stays in it until the next depth.
+//A depth fetched again keeps the live queue ahead of a paper order at its price, see Exchange.SetDepth.
+type PaperAPI struct {
+	API
+	Ledger    *Exchange
+	DepthSize int //the depth fetched before placing an order, so it takes what the book holds now
+
+	l     sync.Mutex
+	start int64 //unix milliseconds, trades from before the PaperAPI was made fill nothing
+	marks map[CurrencyPair]*tapeMark
+}
+
+//tapeMark is the newest trade of a pair fed to the ledger
+type tapeMark struct {
+	date int64
+	tids map[int64]bool //the trades fed at date, the next GetTrades answers them again
+}
+
+//NewPaperAPI trades api on paper, fund it with Ledger.SetBalance and set the rates in Ledger.Fee
+func NewPaperAPI(api API) *PaperAPI {
+	ledger := New()
+	ledger.Name = api.GetExchangeName()
+	return &PaperAPI{API: api, Ledger: ledger, DepthSize: 20, start: ledger.nowMs(), marks: map[CurrencyPair]*tapeMark{}}
+}
+
+func (p *PaperAPI) GetDepth(size int, currency CurrencyPair) (*Depth, error) {
+	dep, err := p.API.GetDepth(size, currency)
+	if err != nil {
+		return nil, err
+	}
+	p.Ledger.SetDepth(currency, dep)
+	return dep, nil
+}
+
+//GetTrades feeds the ledger the trades it didn't see yet, made after the PaperAPI
+func (p *PaperAPI) GetTrades(currencyPair CurrencyPair, since int64) ([]Trade, error) {
+	trades, err := p.API.GetTrades(currencyPair, since)
+	if err != nil {
+		return nil, err
+	}
+	p.Ledger.AddTrades(currencyPair, p.unseen(currencyPair, trades)...)
+	return trades, nil
+}
+
+func (p *PaperAPI) unseen(pair CurrencyPair, trades []Trade) []Trade {
+	p.l.Lock()
+	defer p.l.Unlock()
+	mark, ok := p.marks[pair]
+	if !ok {
+		mark = &tapeMark{date: p.start, tids: map[int64]bool{}}
+		p.marks[pair] = mark
+	}
+	var fresh []Trade
+	for _, t := range TradesSinceTime(append([]Trade{}, trades...), mark.date) {
+		if t.Date == mark.date && mark.tids[t.Tid] {
+			continue
+		}
+		if t.Date > mark.date {
+			mark.date, mark.tids = t.Date, map[int64]bool{}
+		}
+		mark.tids[t.Tid] = true
+		fresh = append(fresh, t)
+	}
+	return fresh
+}
+
+//Observe fetches the depth and the latest trades of currency, filling the paper orders they cross, for
+//strategies polling only their orders. The trades go first, they took from the book seen before and the
+//new depth has them taken already.
+func (p *PaperAPI) Observe(currency CurrencyPair) error {
+	if _, err := p.GetTrades(currency, 0); err != nil {
+		return err
+	}
+	_, err := p.GetDepth(p.DepthSize, currency)
+	return err
+}
+
+func (p *PaperAPI) place(place func(amount, price Decimal, currency CurrencyPair) (*Order, error), amount, price Decimal, currency CurrencyPair) (*Order, error) {
+	if _, err := p.GetDepth(p.DepthSize, currency); err != nil {
+		return nil, err
+	}
+	return place(amount, price, currency)
+}
+
+func (p *PaperAPI) LimitBuy(amount, price Decimal, currency CurrencyPair) (*Order, error) {
+	return p.place(p.Ledger.LimitBuy, amount, price, currency)
+}
+
+func (p *PaperAPI) LimitSell(amount, price Decimal, currency CurrencyPair) (*Order, error) {
+	return p.place(p.Ledger.LimitSell, amount, price, currency)
+}
+
+func (p *PaperAPI) MarketBuy(amount, price Decimal, currency CurrencyPair) (*Order, error) {
+	return p.place(p.Ledger.MarketBuy, amount, price, currency)
+}
+
+func (p *PaperAPI) MarketSell(amount, price Decimal, currency CurrencyPair) (*Order, error) {
+	return p.place(p.Ledger.MarketSell, amount, price, currency)
+}
+
+func (p *PaperAPI) CancelOrder(orderId string, currency CurrencyPair) (bool, error) {
+	return p.Ledger.CancelOrder(orderId, currency)
+}
+
+func (p *PaperAPI) GetOneOrder(orderId string, currency CurrencyPair) (*Order, error) {
+	return p.Ledger.GetOneOrder(orderId, currency)
+}
+
+func (p *PaperAPI) GetUnfinishOrders(currency CurrencyPair) ([]Order, error) {
+	return p.Ledger.GetUnfinishOrders(currency)
+}
+
+func (p *PaperAPI) GetOrderHistorys(currency CurrencyPair, currentPage, pageSize int) ([]Order, error) {
+	return p.Ledger.GetOrderHistorys(currency, currentPage, pageSize)
+}
+
+func (p *PaperAPI) GetAccount() (*Account, error) {
+	return p.Ledger.GetAccount()
+}
+
+func (p *PaperAPI) GetMyTrades(pair CurrencyPair, since int64, limit int) ([]MyTrade, error) {
+	return p.Ledger.GetMyTrades(pair, since, limit)
+}
+
+func (p *PaperAPI) GetTradeFee(pair CurrencyPair) (*TradeFee, error) {
+	return p.Ledger.GetTradeFee(pair)
+}
+
+//Capabilities are the exchange's for market data and the ledger's for trading
+func (p *PaperAPI) Capabilities() Capabilities {
+	c := p.API.Capabilities()
+	return Capabilities{MarketOrder: true, Kline: c.Kline, Trades: c.Trades, OrderHistory: true, TradeFee: true, MyTrades: true}
+}
@@ -0,0 +1,87 @@
+package mock
+
+import (
+	"testing"
+	"time"
+
+	"github.com/nntaoli-project/GoEx"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestPaperAPI(t *testing.T) {
+	live := New()
+	live.SetDepth(goex.BTC_USDT, &goex.Depth{
+		AskList: goex.DepthRecords{{Price: d("100"), Amount: d("1")}},
+		BidList: goex.DepthRecords{{Price: d("99"), Amount: d("1")}}})
+	paper := NewPaperAPI(live)
+	paper.Ledger.SetBalance(goex.USDT, d("1000"))
+	now := time.Now().UnixNano() / int64(time.Millisecond)
+
+	ord, err := paper.LimitBuy(d("1"), d("99.5"), goex.BTC_USDT)
+	assert.Nil(t, err)
+	assert.Equal(t, goex.TradeStatus(goex.ORDER_UNFINISH), ord.Status)
+	orders, _ := live.GetUnfinishOrders(goex.BTC_USDT)
+	assert.Empty(t, orders)
+
+	//the trade from before the paper account fills nothing, each of the others once
+	live.AddTrades(goex.BTC_USDT,
+		goex.Trade{Tid: 1, Type: "sell", Amount: d("5"), Price: d("90"), Date: now - 60000},
+		goex.Trade{Tid: 2, Type: "sell", Amount: d("0.4"), Price: d("99.5"), Date: now + 1})
+	for i := 0; i < 2; i++ {
+		trades, err := paper.GetTrades(goex.BTC_USDT, 0)
+		assert.Nil(t, err)
+		assert.Len(t, trades, 2)
+	}
+	ord, _ = paper.GetOneOrder(ord.OrderID2, goex.BTC_USDT)
+	assert.Equal(t, goex.TradeStatus(goex.ORDER_PART_FINISH), ord.Status)
+	assert.True(t, ord.DealAmount.Equal(d("0.4")))
+
+	//the book moves through the rest
+	live.SetDepth(goex.BTC_USDT, &goex.Depth{
+		AskList: goex.DepthRecords{{Price: d("99"), Amount: d("5")}},
+		BidList: goex.DepthRecords{{Price: d("98"), Amount: d("1")}}})
+	assert.Nil(t, paper.Observe(goex.BTC_USDT))
+	ord, _ = paper.GetOneOrder(ord.OrderID2, goex.BTC_USDT)
+	assert.Equal(t, goex.TradeStatus(goex.ORDER_FINISH), ord.Status)
+	assert.True(t, ord.AvgPrice.Equal(d("99.5")))
+
+	//market orders take the book as it is now
+	ord, err = paper.MarketSell(d("1"), goex.Decimal{}, goex.BTC_USDT)
+	assert.Nil(t, err)
+	assert.True(t, ord.AvgPrice.Equal(d("98")))
+
+	account, err := paper.GetAccount()
+	assert.Nil(t, err)
+	assert.Equal(t, "mock", account.Exchange)
+	assert.True(t, account.SubAccounts[goex.BTC].Amount.IsZero())
+	assert.True(t, account.SubAccounts[goex.USDT].Amount.Equal(d("998.5")))
+	liveAccount, _ := live.GetAccount()
+	assert.Empty(t, liveAccount.SubAccounts)
+}
+
+func TestPaperAPI_DepthQueue(t *testing.T) {
+	live := New()
+	live.SetDepth(goex.BTC_USDT, &goex.Depth{
+		AskList: goex.DepthRecords{{Price: d("100"), Amount: d("1")}},
+		BidList: goex.DepthRecords{{Price: d("99"), Amount: d("1")}}})
+	paper := NewPaperAPI(live)
+	paper.Ledger.SetBalance(goex.BTC, d("1"))
+	now := time.Now().UnixNano() / int64(time.Millisecond)
+
+	//the order queues behind the live 1@100, fetching the depth again keeps it there
+	ord, err := paper.LimitSell(d("1"), d("100"), goex.BTC_USDT)
+	assert.Nil(t, err)
+	_, err = paper.GetDepth(5, goex.BTC_USDT)
+	assert.Nil(t, err)
+
+	live.AddTrades(goex.BTC_USDT, goex.Trade{Tid: 1, Type: "buy", Amount: d("1"), Price: d("100"), Date: now + 1})
+	assert.Nil(t, paper.Observe(goex.BTC_USDT))
+	ord, _ = paper.GetOneOrder(ord.OrderID2, goex.BTC_USDT)
+	assert.Equal(t, goex.TradeStatus(goex.ORDER_UNFINISH), ord.Status)
+
+	live.AddTrades(goex.BTC_USDT, goex.Trade{Tid: 2, Type: "buy", Amount: d("0.5"), Price: d("100"), Date: now + 2})
+	assert.Nil(t, paper.Observe(goex.BTC_USDT))
+	ord, _ = paper.GetOneOrder(ord.OrderID2, goex.BTC_USDT)
+	assert.Equal(t, goex.TradeStatus(goex.ORDER_PART_FINISH), ord.Status)
+	assert.True(t, ord.DealAmount.Equal(d("0.5")))
+}
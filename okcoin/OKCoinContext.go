@@ -0,0 +1,172 @@
+package okcoin
+
+import (
+	"context"
+
+	. "github.com/nntaoli-project/GoEx"
+)
+
+var (
+	_ API                      = (*OKCoinCN_API)(nil)
+	_ APIWithContext           = (*OKCoinCN_API)(nil)
+	_ API                      = (*OKCoinCOM_API)(nil)
+	_ APIWithContext           = (*OKCoinCOM_API)(nil)
+	_ API                      = (*OKExSpot)(nil)
+	_ APIWithContext           = (*OKExSpot)(nil)
+	_ FutureRestAPI            = (*OKEx)(nil)
+	_ FutureRestAPIWithContext = (*OKEx)(nil)
+)
+
+func (api *OKCoinCN_API) withContext(ctx context.Context) *OKCoinCN_API {
+	c := *api
+	c.client = HttpClientWithContext(ctx, api.client)
+	return &c
+}
+
+func (api *OKCoinCN_API) LimitBuyCtx(ctx context.Context, amount, price Decimal, currency CurrencyPair) (*Order, error) {
+	return api.withContext(ctx).LimitBuy(amount, price, currency)
+}
+
+func (api *OKCoinCN_API) LimitSellCtx(ctx context.Context, amount, price Decimal, currency CurrencyPair) (*Order, error) {
+	return api.withContext(ctx).LimitSell(amount, price, currency)
+}
+
+func (api *OKCoinCN_API) MarketBuyCtx(ctx context.Context, amount, price Decimal, currency CurrencyPair) (*Order, error) {
+	return api.withContext(ctx).MarketBuy(amount, price, currency)
+}
+
+func (api *OKCoinCN_API) MarketSellCtx(ctx context.Context, amount, price Decimal, currency CurrencyPair) (*Order, error) {
+	return api.withContext(ctx).MarketSell(amount, price, currency)
+}
+
+func (api *OKCoinCN_API) CancelOrderCtx(ctx context.Context, orderId string, currency CurrencyPair) (bool, error) {
+	return api.withContext(ctx).CancelOrder(orderId, currency)
+}
+
+func (api *OKCoinCN_API) GetOneOrderCtx(ctx context.Context, orderId string, currency CurrencyPair) (*Order, error) {
+	return api.withContext(ctx).GetOneOrder(orderId, currency)
+}
+
+func (api *OKCoinCN_API) GetUnfinishOrdersCtx(ctx context.Context, currency CurrencyPair) ([]Order, error) {
+	return api.withContext(ctx).GetUnfinishOrders(currency)
+}
+
+func (api *OKCoinCN_API) GetOrderHistorysCtx(ctx context.Context, currency CurrencyPair, currentPage, pageSize int) ([]Order, error) {
+	return api.withContext(ctx).GetOrderHistorys(currency, currentPage, pageSize)
+}
+
+func (api *OKCoinCN_API) GetAccountCtx(ctx context.Context) (*Account, error) {
+	return api.withContext(ctx).GetAccount()
+}
+
+func (api *OKCoinCN_API) GetTickerCtx(ctx context.Context, currency CurrencyPair) (*Ticker, error) {
+	return api.withContext(ctx).GetTicker(currency)
+}
+
+func (api *OKCoinCN_API) GetDepthCtx(ctx context.Context, size int, currency CurrencyPair) (*Depth, error) {
+	return api.withContext(ctx).GetDepth(size, currency)
+}
+
+func (api *OKCoinCN_API) GetKlineRecordsCtx(ctx context.Context, currency CurrencyPair, period, size, since int) ([]Kline, error) {
+	return api.withContext(ctx).GetKlineRecords(currency, period, size, since)
+}
+
+func (api *OKCoinCN_API) GetTradesCtx(ctx context.Context, currencyPair CurrencyPair, since int64) ([]Trade, error) {
+	return api.withContext(ctx).GetTrades(currencyPair, since)
+}
+
+// OKExSpot and OKCoinCOM_API override GetAccount, so they can not rely on the promoted OKCoinCN_API version.
+
+func (api *OKExSpot) GetAccountCtx(ctx context.Context) (*Account, error) {
+	c := *api
+	c.client = HttpClientWithContext(ctx, api.client)
+	return c.GetAccount()
+}
+
+// OKExSpot overrides GetOneOrder and CancelOrder as well, for client order ids.
+
+func (api *OKExSpot) withContext(ctx context.Context) *OKExSpot {
+	c := *api
+	c.client = HttpClientWithContext(ctx, api.client)
+	return &c
+}
+
+func (api *OKExSpot) CancelOrderCtx(ctx context.Context, orderId string, currency CurrencyPair) (bool, error) {
+	return api.withContext(ctx).CancelOrder(orderId, currency)
+}
+
+func (api *OKExSpot) GetOneOrderCtx(ctx context.Context, orderId string, currency CurrencyPair) (*Order, error) {
+	return api.withContext(ctx).GetOneOrder(orderId, currency)
+}
+
+func (api *OKCoinCOM_API) GetAccountCtx(ctx context.Context) (*Account, error) {
+	c := *api
+	c.client = HttpClientWithContext(ctx, api.client)
+	return c.GetAccount()
+}
+
+func (ok *OKEx) withContext(ctx context.Context) *OKEx {
+	c := *ok
+	c.client = HttpClientWithContext(ctx, ok.client)
+	return &c
+}
+
+func (ok *OKEx) GetFutureEstimatedPriceCtx(ctx context.Context, currencyPair CurrencyPair) (float64, error) {
+	return ok.withContext(ctx).GetFutureEstimatedPrice(currencyPair)
+}
+
+func (ok *OKEx) GetFutureTickerCtx(ctx context.Context, currencyPair CurrencyPair, contractType string) (*Ticker, error) {
+	return ok.withContext(ctx).GetFutureTicker(currencyPair, contractType)
+}
+
+func (ok *OKEx) GetFutureDepthCtx(ctx context.Context, currencyPair CurrencyPair, contractType string, size int) (*Depth, error) {
+	return ok.withContext(ctx).GetFutureDepth(currencyPair, contractType, size)
+}
+
+func (ok *OKEx) GetFutureIndexCtx(ctx context.Context, currencyPair CurrencyPair) (float64, error) {
+	return ok.withContext(ctx).GetFutureIndex(currencyPair)
+}
+
+func (ok *OKEx) GetFutureUserinfoCtx(ctx context.Context) (*FutureAccount, error) {
+	return ok.withContext(ctx).GetFutureUserinfo()
+}
+
+func (ok *OKEx) PlaceFutureOrderCtx(ctx context.Context, currencyPair CurrencyPair, contractType, price, amount string, openType, matchPrice, leverRate int) (string, error) {
+	return ok.withContext(ctx).PlaceFutureOrder(currencyPair, contractType, price, amount, openType, matchPrice, leverRate)
+}
+
+func (ok *OKEx) FutureCancelOrderCtx(ctx context.Context, currencyPair CurrencyPair, contractType, orderId string) (bool, error) {
+	return ok.withContext(ctx).FutureCancelOrder(currencyPair, contractType, orderId)
+}
+
+func (ok *OKEx) GetFuturePositionCtx(ctx context.Context, currencyPair CurrencyPair, contractType string) ([]FuturePosition, error) {
+	return ok.withContext(ctx).GetFuturePosition(currencyPair, contractType)
+}
+
+func (ok *OKEx) GetFutureOrdersCtx(ctx context.Context, orderIds []string, currencyPair CurrencyPair, contractType string) ([]FutureOrder, error) {
+	return ok.withContext(ctx).GetFutureOrders(orderIds, currencyPair, contractType)
+}
+
+func (ok *OKEx) GetUnfinishFutureOrdersCtx(ctx context.Context, currencyPair CurrencyPair, contractType string) ([]FutureOrder, error) {
+	return ok.withContext(ctx).GetUnfinishFutureOrders(currencyPair, contractType)
+}
+
+func (ok *OKEx) GetFeeCtx(ctx context.Context) (float64, error) {
+	return ok.withContext(ctx).GetFee()
+}
+
+func (ok *OKEx) GetExchangeRateCtx(ctx context.Context) (float64, error) {
+	return ok.withContext(ctx).GetExchangeRate()
+}
+
+func (ok *OKEx) GetContractValueCtx(ctx context.Context, currencyPair CurrencyPair) (float64, error) {
+	return ok.withContext(ctx).GetContractValue(currencyPair)
+}
+
+func (ok *OKEx) GetKlineRecordsCtx(ctx context.Context, contract_type string, currency CurrencyPair, period string, size, since int) ([]FutureKline, error) {
+	return ok.withContext(ctx).GetKlineRecords(contract_type, currency, period, size, since)
+}
+
+func (ok *OKEx) GetTradesCtx(ctx context.Context, currencyPair CurrencyPair, since int64) ([]Trade, error) {
+	return ok.withContext(ctx).GetTrades(currencyPair, since)
+}
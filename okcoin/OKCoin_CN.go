@@ -9,9 +9,8 @@ import (
 	"net/url"
 	"strconv"
 	"strings"
+	"time"
 
-	"bitbucket.org/i0n/compounda/exchanges"
-	"bitbucket.org/i0n/compounda/utils"
 	. "github.com/nntaoli-project/GoEx"
 )
 
@@ -22,14 +21,16 @@ const (
 	url_trades       = "trades.do"
 	url_kline        = "kline.do?symbol=%s&type=%s&size=%d&since=%d"
 
-	url_userinfo      = "userinfo.do"
-	url_trade         = "trade.do"
-	url_cancel_order  = "cancel_order.do"
-	url_order_info    = "order_info.do"
-	url_orders_info   = "orders_info.do"
-	order_history_uri = "order_history.do"
-	trade_uri         = "trade_history.do"
-	url_withdraw      = "withdraw.do"
+	url_userinfo        = "userinfo.do"
+	url_trade           = "trade.do"
+	url_cancel_order    = "cancel_order.do"
+	url_order_info      = "order_info.do"
+	url_orders_info     = "orders_info.do"
+	order_history_uri   = "order_history.do"
+	trade_uri           = "trade_history.do"
+	url_withdraw        = "withdraw.do"
+	url_cancel_withdraw = "cancel_withdraw.do"
+	url_withdraw_info   = "withdraw_info.do"
 )
 
 type OKCoinCN_API struct {
@@ -66,6 +67,7 @@ var _INERNAL_KLINE_PERIOD_CONVERTER = map[int]string{
 //}
 
 func New(client *http.Client, api_key, secret_key string) *OKCoinCN_API {
+	client = ExchangeRateLimitedClient(client, EXCHANGE_NAME_CN, api_key, DefaultRateLimitConfig)
 	return &OKCoinCN_API{client, api_key, secret_key, "https://www.okcoin.cn/api/v1/"}
 }
 
@@ -87,26 +89,35 @@ func (ctx *OKCoinCN_API) buildPostForm(postForm *url.Values) error {
 	return nil
 }
 
-func (ctx *OKCoinCN_API) Withdraw(currencyPair CurrencyPair, address CryptoAddress, amount float64, wallet string, adminPassword string) (*Withdraw, error) {
-	var c string
+//isCOM tells okcoin.com from okcoin.cn in the methods OKCoinCOM_API inherits
+func (ctx *OKCoinCN_API) isCOM() bool {
+	return strings.Contains(ctx.api_base_url, "okcoin.com")
+}
+
+//withdrawSymbol is the pair okcoin names the withdrawals of a currency by
+func (ctx *OKCoinCN_API) withdrawSymbol(currency Currency) string {
+	if ctx.isCOM() {
+		return strings.ToLower(currency.String()) + "_usd"
+	}
+	return strings.ToLower(currency.String()) + "_cny"
+}
+
+//Withdraw offers req.Fee as chargefee, or the one in WithdrawFees when zero
+func (ctx *OKCoinCN_API) Withdraw(req WithdrawRequest) (*Withdraw, error) {
+	exchange := EXCHANGE_NAME_CN
+	if ctx.isCOM() {
+		exchange = EXCHANGE_NAME_COM
+	}
+	fee, err := req.WithdrawFee(exchange)
+	if err != nil {
+		return nil, err
+	}
 	postData := url.Values{}
-	s := strings.ToLower(currencyPair.ToSymbol("_"))
-	postData.Set("symbol", s)
-	switch x := currencyPair.CurrencyA; x {
-	case BTC:
-		c = strconv.FormatFloat(exchanges.All["okcoin.com"].WithdrawFees[x], 'f', -1, 64)
-	case LTC:
-		c = strconv.FormatFloat(exchanges.All["okcoin.com"].WithdrawFees[x], 'f', -1, 64)
-	case ETH:
-		c = strconv.FormatFloat(exchanges.All["okcoin.com"].WithdrawFees[x], 'f', -1, 64)
-	case ETC:
-		c = strconv.FormatFloat(exchanges.All["okcoin.com"].WithdrawFees[x], 'f', -1, 64)
-	}
-	postData.Set("chargefee", c)
-	postData.Set("withdraw_address", address.Address)
-	a := strconv.FormatFloat(amount, 'f', -1, 64)
-	postData.Set("withdraw_amount", a)
-	postData.Set("trade_pwd", adminPassword)
+	postData.Set("symbol", ctx.withdrawSymbol(req.Currency))
+	postData.Set("chargefee", fee.String())
+	postData.Set("withdraw_address", req.Address)
+	postData.Set("withdraw_amount", req.Amount.String())
+	postData.Set("trade_pwd", req.TradePassword)
 	postData.Set("target", "address")
 
 	ctx.buildPostForm(&postData)
@@ -117,20 +128,118 @@ func (ctx *OKCoinCN_API) Withdraw(currencyPair CurrencyPair, address CryptoAddre
 		return nil, err
 	}
 
-	var res Withdraw
+	var res struct {
+		Result     bool  `json:"result"`
+		WithdrawId int64 `json:"withdraw_id"`
+		ErrorCode  int   `json:"error_code"`
+	}
 
 	err = json.Unmarshal(body, &res)
 	if err != nil {
-		return &res, err
+		return nil, err
 	}
 	if res.Result == false {
-		return &res, errors.New(strconv.Itoa(res.ErrorCode))
+		return nil, ctx.errorWrapper(res.ErrorCode, string(body))
+	}
+
+	return &Withdraw{
+		ID:       strconv.FormatInt(res.WithdrawId, 10),
+		Currency: req.Currency,
+		Amount:   req.Amount,
+		Fee:      fee,
+		Address:  req.Address,
+		Status:   WITHDRAW_PENDING,
+		Time:     time.Now().Unix()}, nil
+}
+
+func (ctx *OKCoinCN_API) CancelWithdraw(id string, currency Currency, tradePassword string) (bool, error) {
+	postData := url.Values{}
+	postData.Set("symbol", ctx.withdrawSymbol(currency))
+	postData.Set("withdraw_id", id)
+	ctx.buildPostForm(&postData)
+
+	body, err := HttpPostForm(ctx.client, ctx.api_base_url+url_cancel_withdraw, postData)
+	if err != nil {
+		return false, err
+	}
+
+	var respMap map[string]interface{}
+	err = json.Unmarshal(body, &respMap)
+	if err != nil {
+		return false, err
+	}
+	if result, _ := respMap["result"].(bool); !result {
+		return false, ctx.errorWrapper(ToInt(respMap["error_code"]), string(body))
+	}
+	return true, nil
+}
+
+func (ctx *OKCoinCN_API) GetWithdraw(id string, currency Currency) (*Withdraw, error) {
+	var dp DecimalParser
+	postData := url.Values{}
+	postData.Set("symbol", ctx.withdrawSymbol(currency))
+	postData.Set("withdraw_id", id)
+	ctx.buildPostForm(&postData)
+
+	body, err := HttpPostForm(ctx.client, ctx.api_base_url+url_withdraw_info, postData)
+	if err != nil {
+		return nil, err
 	}
 
-	return &res, nil
+	var res struct {
+		Result    bool `json:"result"`
+		ErrorCode int  `json:"error_code"`
+		Withdraw  []struct {
+			Address     string  `json:"address"`
+			Amount      float64 `json:"amount"`
+			ChargeFee   float64 `json:"chargefee"`
+			CreatedDate int64   `json:"created_date"`
+			Status      int     `json:"status"`
+			WithdrawId  int64   `json:"withdraw_id"`
+		} `json:"withdraw"`
+	}
+	err = json.Unmarshal(body, &res)
+	if err != nil {
+		return nil, err
+	}
+	if !res.Result {
+		return nil, ctx.errorWrapper(res.ErrorCode, string(body))
+	}
+	if len(res.Withdraw) == 0 {
+		errCode := EX_ERR_NOT_FIND_WITHDRAW
+		errCode.OriginErrMsg = string(body)
+		return nil, errCode
+	}
+
+	info := res.Withdraw[0]
+	w := &Withdraw{
+		ID:       strconv.FormatInt(info.WithdrawId, 10),
+		Currency: currency,
+		Amount:   dp.Decimal(info.Amount),
+		Fee:      dp.Decimal(info.ChargeFee),
+		Address:  info.Address,
+		Time:     info.CreatedDate / 1000}
+	//-3 cancelling, -2 canceled, -1 failed, 0 pending, 1 processing, 2 completed, 3.. awaiting confirmation
+	switch info.Status {
+	case -2:
+		w.Status = WITHDRAW_CANCELED
+	case -1:
+		w.Status = WITHDRAW_FAILED
+	case 1:
+		w.Status = WITHDRAW_PROCESSING
+	case 2:
+		w.Status = WITHDRAW_COMPLETED
+	default:
+		w.Status = WITHDRAW_PENDING
+	}
+	if dp.Err != nil {
+		return nil, dp.Err
+	}
+	return w, nil
 }
 
 func (ctx *OKCoinCN_API) placeOrder(side, amount, price string, currency CurrencyPair) (*Order, error) {
+	var dp DecimalParser
 	postData := url.Values{}
 	postData.Set("type", side)
 
@@ -161,14 +270,14 @@ func (ctx *OKCoinCN_API) placeOrder(side, amount, price string, currency Currenc
 		return nil, err
 	}
 
-	if err, isok := respMap["error_code"].(float64); isok {
-		return nil, errors.New(fmt.Sprint(err))
+	if errcode, isok := respMap["error_code"].(float64); isok {
+		return nil, ctx.errorWrapper(int(errcode), string(body))
 	}
 
 	order := new(Order)
 	order.OrderID = int(respMap["order_id"].(float64))
-	order.Price, _ = strconv.ParseFloat(price, 64)
-	order.Amount, _ = strconv.ParseFloat(amount, 64)
+	order.Price = dp.Decimal(price)
+	order.Amount = dp.Decimal(amount)
 	order.Currency = currency
 	order.Status = ORDER_UNFINISH
 
@@ -179,23 +288,26 @@ func (ctx *OKCoinCN_API) placeOrder(side, amount, price string, currency Currenc
 		order.Side = SELL
 	}
 
+	if dp.Err != nil {
+		return nil, dp.Err
+	}
 	return order, nil
 }
 
-func (ctx *OKCoinCN_API) LimitBuy(amount, price string, currency CurrencyPair) (*Order, error) {
-	return ctx.placeOrder("buy", amount, price, currency)
+func (ctx *OKCoinCN_API) LimitBuy(amount, price Decimal, currency CurrencyPair) (*Order, error) {
+	return ctx.placeOrder("buy", amount.String(), price.String(), currency)
 }
 
-func (ctx *OKCoinCN_API) LimitSell(amount, price string, currency CurrencyPair) (*Order, error) {
-	return ctx.placeOrder("sell", amount, price, currency)
+func (ctx *OKCoinCN_API) LimitSell(amount, price Decimal, currency CurrencyPair) (*Order, error) {
+	return ctx.placeOrder("sell", amount.String(), price.String(), currency)
 }
 
-func (ctx *OKCoinCN_API) MarketBuy(amount, price string, currency CurrencyPair) (*Order, error) {
-	return ctx.placeOrder("buy_market", amount, price, currency)
+func (ctx *OKCoinCN_API) MarketBuy(amount, price Decimal, currency CurrencyPair) (*Order, error) {
+	return ctx.placeOrder("buy_market", amount.String(), price.String(), currency)
 }
 
-func (ctx *OKCoinCN_API) MarketSell(amount, price string, currency CurrencyPair) (*Order, error) {
-	return ctx.placeOrder("sell_market", amount, price, currency)
+func (ctx *OKCoinCN_API) MarketSell(amount, price Decimal, currency CurrencyPair) (*Order, error) {
+	return ctx.placeOrder("sell_market", amount.String(), price.String(), currency)
 }
 
 func (ctx *OKCoinCN_API) CancelOrder(orderId string, currency CurrencyPair) (bool, error) {
@@ -218,14 +330,15 @@ func (ctx *OKCoinCN_API) CancelOrder(orderId string, currency CurrencyPair) (boo
 		return false, err
 	}
 
-	if err, isok := respMap["error_code"].(float64); isok {
-		return false, errors.New(fmt.Sprint(err))
+	if errcode, isok := respMap["error_code"].(float64); isok {
+		return false, ctx.errorWrapper(int(errcode), string(body))
 	}
 
 	return true, nil
 }
 
 func (ctx *OKCoinCN_API) getOrders(orderId string, currency CurrencyPair) ([]Order, error) {
+	var dp DecimalParser
 	postData := url.Values{}
 	postData.Set("order_id", orderId)
 	postData.Set("symbol", strings.ToLower(currency.ToSymbol("_")))
@@ -245,8 +358,8 @@ func (ctx *OKCoinCN_API) getOrders(orderId string, currency CurrencyPair) ([]Ord
 		return nil, err
 	}
 
-	if err, isok := respMap["error_code"].(float64); isok {
-		return nil, errors.New(fmt.Sprint(err))
+	if errcode, isok := respMap["error_code"].(float64); isok {
+		return nil, ctx.errorWrapper(int(errcode), string(body))
 	}
 
 	orders := respMap["orders"].([]interface{})
@@ -258,10 +371,10 @@ func (ctx *OKCoinCN_API) getOrders(orderId string, currency CurrencyPair) ([]Ord
 		var order Order
 		order.Currency = currency
 		order.OrderID = int(orderMap["order_id"].(float64))
-		order.Amount = orderMap["amount"].(float64)
-		order.Price = orderMap["price"].(float64)
-		order.DealAmount = orderMap["deal_amount"].(float64)
-		order.AvgPrice = orderMap["avg_price"].(float64)
+		order.Amount = dp.Decimal(orderMap["amount"])
+		order.Price = dp.Decimal(orderMap["price"])
+		order.DealAmount = dp.Decimal(orderMap["deal_amount"])
+		order.AvgPrice = dp.Decimal(orderMap["avg_price"])
 		order.OrderTime = int(orderMap["create_date"].(float64))
 
 		//status:-1:已撤销  0:未成交  1:部分成交  2:完全成交 4:撤单处理中
@@ -293,6 +406,9 @@ func (ctx *OKCoinCN_API) getOrders(orderId string, currency CurrencyPair) ([]Ord
 	}
 
 	//fmt.Println(orders);
+	if dp.Err != nil {
+		return nil, dp.Err
+	}
 	return orderAr, nil
 }
 
@@ -314,6 +430,7 @@ func (ctx *OKCoinCN_API) GetUnfinishOrders(currency CurrencyPair) ([]Order, erro
 }
 
 func (ctx *OKCoinCN_API) GetAccount() (*Account, error) {
+	var dp DecimalParser
 	postData := url.Values{}
 	err := ctx.buildPostForm(&postData)
 	if err != nil {
@@ -332,8 +449,8 @@ func (ctx *OKCoinCN_API) GetAccount() (*Account, error) {
 		return nil, err
 	}
 
-	if err, isok := respMap["error_code"].(float64); isok {
-		return nil, errors.New(fmt.Sprint(err))
+	if errcode, isok := respMap["error_code"].(float64); isok {
+		return nil, ctx.errorWrapper(int(errcode), string(body))
 	}
 
 	info, ok := respMap["info"].(map[string]interface{})
@@ -359,34 +476,34 @@ func (ctx *OKCoinCN_API) GetAccount() (*Account, error) {
 	var bccSubAccount SubAccount
 
 	btcSubAccount.Currency = BTC
-	btcSubAccount.Amount, _ = strconv.ParseFloat(free["btc"].(string), 64)
-	btcSubAccount.LoanAmount = 0
-	btcSubAccount.FrozenAmount, _ = strconv.ParseFloat(freezed["btc"].(string), 64)
+	btcSubAccount.Amount = dp.Decimal(free["btc"])
+	btcSubAccount.LoanAmount = Decimal{}
+	btcSubAccount.FrozenAmount = dp.Decimal(freezed["btc"])
 
 	ltcSubAccount.Currency = LTC
-	ltcSubAccount.Amount, _ = strconv.ParseFloat(free["ltc"].(string), 64)
-	ltcSubAccount.LoanAmount = 0
-	ltcSubAccount.FrozenAmount, _ = strconv.ParseFloat(freezed["ltc"].(string), 64)
+	ltcSubAccount.Amount = dp.Decimal(free["ltc"])
+	ltcSubAccount.LoanAmount = Decimal{}
+	ltcSubAccount.FrozenAmount = dp.Decimal(freezed["ltc"])
 
 	ethSubAccount.Currency = ETH
-	ethSubAccount.Amount, _ = strconv.ParseFloat(free["eth"].(string), 64)
-	ethSubAccount.LoanAmount = 0
-	ethSubAccount.FrozenAmount, _ = strconv.ParseFloat(freezed["eth"].(string), 64)
+	ethSubAccount.Amount = dp.Decimal(free["eth"])
+	ethSubAccount.LoanAmount = Decimal{}
+	ethSubAccount.FrozenAmount = dp.Decimal(freezed["eth"])
 
 	etcSubAccount.Currency = ETC
-	etcSubAccount.Amount = ToFloat64(free["etc"])
-	etcSubAccount.LoanAmount = 0
-	etcSubAccount.FrozenAmount = ToFloat64(freezed["etc"])
+	etcSubAccount.Amount = dp.Decimal(free["etc"])
+	etcSubAccount.LoanAmount = Decimal{}
+	etcSubAccount.FrozenAmount = dp.Decimal(freezed["etc"])
 
 	bccSubAccount.Currency = BCC
-	bccSubAccount.Amount = ToFloat64(free["bcc"])
-	bccSubAccount.LoanAmount = 0
-	bccSubAccount.FrozenAmount = ToFloat64(freezed["bcc"])
+	bccSubAccount.Amount = dp.Decimal(free["bcc"])
+	bccSubAccount.LoanAmount = Decimal{}
+	bccSubAccount.FrozenAmount = dp.Decimal(freezed["bcc"])
 
 	cnySubAccount.Currency = CNY
-	cnySubAccount.Amount, _ = strconv.ParseFloat(free["cny"].(string), 64)
-	cnySubAccount.LoanAmount = 0
-	cnySubAccount.FrozenAmount, _ = strconv.ParseFloat(freezed["cny"].(string), 64)
+	cnySubAccount.Amount = dp.Decimal(free["cny"])
+	cnySubAccount.LoanAmount = Decimal{}
+	cnySubAccount.FrozenAmount = dp.Decimal(freezed["cny"])
 
 	account.SubAccounts = make(map[Currency]SubAccount, 3)
 	account.SubAccounts[BTC] = btcSubAccount
@@ -396,10 +513,14 @@ func (ctx *OKCoinCN_API) GetAccount() (*Account, error) {
 	account.SubAccounts[ETC] = etcSubAccount
 	account.SubAccounts[BCC] = bccSubAccount
 
+	if dp.Err != nil {
+		return nil, dp.Err
+	}
 	return account, nil
 }
 
 func (ctx *OKCoinCN_API) GetTicker(currency CurrencyPair) (*Ticker, error) {
+	var dp DecimalParser
 	var tickerMap map[string]interface{}
 	var ticker Ticker
 
@@ -411,17 +532,21 @@ func (ctx *OKCoinCN_API) GetTicker(currency CurrencyPair) (*Ticker, error) {
 
 	tickerMap = bodyDataMap["ticker"].(map[string]interface{})
 	ticker.Date, _ = strconv.ParseUint(bodyDataMap["date"].(string), 10, 64)
-	ticker.Last, _ = strconv.ParseFloat(tickerMap["last"].(string), 64)
-	ticker.Buy, _ = strconv.ParseFloat(tickerMap["buy"].(string), 64)
-	ticker.Sell, _ = strconv.ParseFloat(tickerMap["sell"].(string), 64)
-	ticker.Low, _ = strconv.ParseFloat(tickerMap["low"].(string), 64)
-	ticker.High, _ = strconv.ParseFloat(tickerMap["high"].(string), 64)
-	ticker.Vol, _ = strconv.ParseFloat(tickerMap["vol"].(string), 64)
+	ticker.Last = dp.Decimal(tickerMap["last"])
+	ticker.Buy = dp.Decimal(tickerMap["buy"])
+	ticker.Sell = dp.Decimal(tickerMap["sell"])
+	ticker.Low = dp.Decimal(tickerMap["low"])
+	ticker.High = dp.Decimal(tickerMap["high"])
+	ticker.Vol = dp.Decimal(tickerMap["vol"])
 
+	if dp.Err != nil {
+		return nil, dp.Err
+	}
 	return &ticker, nil
 }
 
 func (ctx *OKCoinCN_API) GetDepth(size int, currency CurrencyPair) (*Depth, error) {
+	var dp DecimalParser
 	var depth Depth
 
 	url := ctx.api_base_url + url_depth + "?symbol=" + strings.ToLower(currency.ToSymbol("_")) + "&size=" + strconv.Itoa(size)
@@ -431,8 +556,8 @@ func (ctx *OKCoinCN_API) GetDepth(size int, currency CurrencyPair) (*Depth, erro
 		return nil, err
 	}
 
-	if err, isok := bodyDataMap["error_code"].(float64); isok {
-		return nil, errors.New(fmt.Sprint(err))
+	if errcode, isok := bodyDataMap["error_code"].(float64); isok {
+		return nil, ctx.errorWrapper(int(errcode), fmt.Sprint(bodyDataMap))
 	}
 
 	dep, isok := bodyDataMap["asks"].([]interface{})
@@ -445,28 +570,33 @@ func (ctx *OKCoinCN_API) GetDepth(size int, currency CurrencyPair) (*Depth, erro
 		for i, vv := range v.([]interface{}) {
 			switch i {
 			case 0:
-				dr.Price = vv.(float64)
+				dr.Price = dp.Price(vv)
 			case 1:
-				dr.Amount = vv.(float64)
+				dr.Amount = dp.Decimal(vv)
 			}
 		}
 		depth.AskList = append(depth.AskList, dr)
 	}
-	utils.ReverseSlice(depth.AskList) //reverse
+	for i, j := 0, len(depth.AskList)-1; i < j; i, j = i+1, j-1 { //reverse
+		depth.AskList.Swap(i, j)
+	}
 
 	for _, v := range bodyDataMap["bids"].([]interface{}) {
 		var dr DepthRecord
 		for i, vv := range v.([]interface{}) {
 			switch i {
 			case 0:
-				dr.Price = vv.(float64)
+				dr.Price = dp.Price(vv)
 			case 1:
-				dr.Amount = vv.(float64)
+				dr.Amount = dp.Decimal(vv)
 			}
 		}
 		depth.BidList = append(depth.BidList, dr)
 	}
 
+	if dp.Err != nil {
+		return nil, dp.Err
+	}
 	return &depth, nil
 }
 
@@ -474,13 +604,17 @@ func (ctx *OKCoinCN_API) GetExchangeName() string {
 	return EXCHANGE_NAME_CN
 }
 
+func (ctx *OKCoinCN_API) Capabilities() Capabilities {
+	return Capabilities{MarketOrder: true, Kline: true, Trades: true, OrderHistory: true, Withdraw: true}
+}
+
 func (ctx *OKCoinCN_API) GetKlineRecords(currency CurrencyPair, period, size, since int) ([]Kline, error) {
 
 	klineUrl := ctx.api_base_url + fmt.Sprintf(url_kline,
 		strings.ToLower(currency.ToSymbol("_")),
 		_INERNAL_KLINE_PERIOD_CONVERTER[period], size, since)
 
-	resp, err := http.Get(klineUrl)
+	resp, err := ctx.client.Get(klineUrl)
 	if err != nil {
 		return nil, err
 	}
@@ -523,6 +657,7 @@ func (ctx *OKCoinCN_API) GetKlineRecords(currency CurrencyPair, period, size, si
 }
 
 func (ctx *OKCoinCN_API) GetOrderHistorys(currency CurrencyPair, currentPage, pageSize int) ([]Order, error) {
+	var dp DecimalParser
 	orderHistoryUrl := ctx.api_base_url + order_history_uri
 
 	postData := url.Values{}
@@ -548,8 +683,8 @@ func (ctx *OKCoinCN_API) GetOrderHistorys(currency CurrencyPair, currentPage, pa
 		return nil, err
 	}
 
-	if err, isok := respMap["error_code"].(float64); isok {
-		return nil, errors.New(fmt.Sprint(err))
+	if errcode, isok := respMap["error_code"].(float64); isok {
+		return nil, ctx.errorWrapper(int(errcode), string(body))
 	}
 
 	orders := respMap["orders"].([]interface{})
@@ -561,10 +696,10 @@ func (ctx *OKCoinCN_API) GetOrderHistorys(currency CurrencyPair, currentPage, pa
 		var order Order
 		order.Currency = currency
 		order.OrderID = int(orderMap["order_id"].(float64))
-		order.Amount = orderMap["amount"].(float64)
-		order.Price = orderMap["price"].(float64)
-		order.DealAmount = orderMap["deal_amount"].(float64)
-		order.AvgPrice = orderMap["avg_price"].(float64)
+		order.Amount = dp.Decimal(orderMap["amount"])
+		order.Price = dp.Decimal(orderMap["price"])
+		order.DealAmount = dp.Decimal(orderMap["deal_amount"])
+		order.AvgPrice = dp.Decimal(orderMap["avg_price"])
 		order.OrderTime = int(orderMap["create_date"].(float64))
 
 		//status:-1:已撤销  0:未成交  1:部分成交  2:完全成交 4:撤单处理中
@@ -591,6 +726,9 @@ func (ctx *OKCoinCN_API) GetOrderHistorys(currency CurrencyPair, currentPage, pa
 		orderAr = append(orderAr, order)
 	}
 
+	if dp.Err != nil {
+		return nil, dp.Err
+	}
 	return orderAr, nil
 }
 
@@ -619,3 +757,36 @@ func (ok *OKCoinCN_API) GetTrades(currencyPair CurrencyPair, since int64) ([]Tra
 
 	return trades, nil
 }
+
+//TradesSince tells TradeIterator that GetTrades pages by trade id
+func (ok *OKCoinCN_API) TradesSince() TradesSince {
+	return TRADES_SINCE_ID
+}
+
+func (ctx *OKCoinCN_API) errorWrapper(errorCode int, originErrMsg string) ApiError {
+	var errCode ApiError
+	switch errorCode {
+	case 10001:
+		errCode = EX_ERR_API_LIMIT
+	case 10005:
+		errCode = EX_ERR_NOT_FIND_SECRETKEY
+	case 10006:
+		errCode = EX_ERR_NOT_FIND_APIKEY
+	case 10007:
+		errCode = EX_ERR_SIGN
+	case 10009, 1009, 1019:
+		errCode = EX_ERR_NOT_FIND_ORDER
+	case 10010, 10016, 1002:
+		errCode = EX_ERR_INSUFFICIENT_BALANCE
+	case 10011, 1003:
+		errCode = EX_ERR_INVALID_PRECISION
+	case 10012:
+		errCode = EX_ERR_INVALID_CURRENCY_PAIR
+	case 10050:
+		errCode = EX_ERR_CANCEL_ORDER_FAIL
+	default:
+		errCode = API_ERR
+	}
+	errCode.OriginErrMsg = originErrMsg
+	return errCode
+}
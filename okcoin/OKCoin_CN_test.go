@@ -2,24 +2,93 @@ package okcoin
 
 import (
 	"net/http"
+	"net/http/httptest"
+	"os"
 	"testing"
 
 	"github.com/nntaoli-project/GoEx"
+	"github.com/stretchr/testify/assert"
 )
 
-var okcn = New(http.DefaultClient, "", "")
+var fixtures = goex.NewFixtureTransport("testdata/fixtures.json")
+var okcn = New(fixtures.Client(), "", "")
 
 func TestOKCoinCN_API_GetTicker(t *testing.T) {
-	ticker, _ := okcn.GetTicker(goex.BTC_CNY)
-	t.Log(ticker)
+	ticker, err := okcn.GetTicker(goex.BTC_CNY)
+	assert.Nil(t, err)
+	assert.Equal(t, goex.Ticker{Last: goex.RequireDecimal("18412.53"), Buy: goex.RequireDecimal("18410"), Sell: goex.RequireDecimal("18414.99"),
+		High: goex.RequireDecimal("18700"), Low: goex.RequireDecimal("18138"), Vol: goex.RequireDecimal("136548.7271"), Date: 1504686312}, *ticker)
 }
 
 func TestOKCoinCN_API_GetDepth(t *testing.T) {
-	dep, _ := okcn.GetDepth(1, goex.ETH_CNY)
-	t.Log(dep)
+	dep, err := okcn.GetDepth(1, goex.ETH_CNY)
+	assert.Nil(t, err)
+	assert.Equal(t, goex.DepthRecords{{Price: goex.RequireDecimal("2150"), Amount: goex.RequireDecimal("0.612")}}, dep.AskList)
+	assert.Equal(t, goex.DepthRecords{{Price: goex.RequireDecimal("2146.02"), Amount: goex.RequireDecimal("3.1")}}, dep.BidList)
 }
 
 //func TestOKCoinCN_API_GetKlineRecords(t *testing.T) {
 //	klines , _ := okcn.GetKlineRecords(goex.BTC_CNY , "1min" , 1000 , -1)
 //	t.Log(klines)
 //}
+
+func TestOKCoinCN_API_Withdraw(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		r.ParseForm()
+		assert.Equal(t, "etc_cny", r.Form.Get("symbol"))
+		switch r.URL.Path {
+		case "/withdraw.do":
+			assert.Equal(t, "0.01", r.Form.Get("chargefee"))
+			assert.Equal(t, "0x5e4f", r.Form.Get("withdraw_address"))
+			assert.Equal(t, "pwd", r.Form.Get("trade_pwd"))
+			w.Write([]byte(`{"withdraw_id":301,"result":true}`))
+		case "/withdraw_info.do":
+			assert.Equal(t, "301", r.Form.Get("withdraw_id"))
+			w.Write([]byte(`{"result":true,"withdraw":[{"address":"0x5e4f","amount":1.5,"created_date":1431680400000,` +
+				`"chargefee":0.01,"status":2,"withdraw_id":301}]}`))
+		case "/cancel_withdraw.do":
+			w.Write([]byte(`{"result":false,"error_code":10010}`))
+		}
+	}))
+	defer srv.Close()
+	api := New(http.DefaultClient, "", "")
+	api.api_base_url = srv.URL + "/"
+
+	withdraw, err := api.Withdraw(goex.WithdrawRequest{Currency: goex.ETC, Amount: goex.RequireDecimal("1.5"),
+		Fee: goex.RequireDecimal("0.01"), Address: "0x5e4f", TradePassword: "pwd"})
+	assert.Nil(t, err)
+	assert.Equal(t, "301", withdraw.ID)
+	assert.Equal(t, goex.WITHDRAW_PENDING, withdraw.Status)
+
+	withdraw, err = api.GetWithdraw("301", goex.ETC)
+	assert.Nil(t, err)
+	assert.Equal(t, goex.Withdraw{ID: "301", Currency: goex.ETC, Amount: goex.RequireDecimal("1.5"), Fee: goex.RequireDecimal("0.01"),
+		Address: "0x5e4f", Status: goex.WITHDRAW_COMPLETED, Time: 1431680400}, *withdraw)
+
+	ok, err := api.CancelWithdraw("301", goex.ETC, "")
+	assert.False(t, ok)
+	assert.NotNil(t, err)
+}
+
+func TestMain(m *testing.M) {
+	os.Exit(fixtures.Run(m.Run))
+}
+
+func TestOKCoinCN_API_errorWrapper(t *testing.T) {
+	for code, errCode := range map[int]goex.ApiError{
+		10001: goex.EX_ERR_API_LIMIT,
+		10005: goex.EX_ERR_NOT_FIND_SECRETKEY,
+		10006: goex.EX_ERR_NOT_FIND_APIKEY,
+		10007: goex.EX_ERR_SIGN,
+		10009: goex.EX_ERR_NOT_FIND_ORDER,
+		10010: goex.EX_ERR_INSUFFICIENT_BALANCE,
+		10011: goex.EX_ERR_INVALID_PRECISION,
+		10012: goex.EX_ERR_INVALID_CURRENCY_PAIR,
+		10050: goex.EX_ERR_CANCEL_ORDER_FAIL,
+		10000: goex.API_ERR,
+	} {
+		err := okcn.errorWrapper(code, "origin")
+		assert.True(t, errCode.Is(err), "%d", code)
+		assert.Equal(t, "origin", err.OriginErrMsg)
+	}
+}
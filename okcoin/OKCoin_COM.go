@@ -2,7 +2,6 @@ package okcoin
 
 import (
 	"encoding/json"
-	"errors"
 	"net/http"
 	"net/url"
 	"strconv"
@@ -19,10 +18,12 @@ type OKCoinCOM_API struct {
 }
 
 func NewCOM(client *http.Client, api_key, secret_key string) *OKCoinCOM_API {
+	client = ExchangeRateLimitedClient(client, EXCHANGE_NAME_COM, api_key, DefaultRateLimitConfig)
 	return &OKCoinCOM_API{OKCoinCN_API{client, api_key, secret_key, "https://www.okcoin.com/api/v1/"}}
 }
 
 func (ctx *OKCoinCOM_API) GetAccount() (*Account, error) {
+	var dp DecimalParser
 	postData := url.Values{}
 	err := ctx.buildPostForm(&postData)
 	if err != nil {
@@ -44,8 +45,7 @@ func (ctx *OKCoinCOM_API) GetAccount() (*Account, error) {
 	}
 
 	if !respMap["result"].(bool) {
-		errcode := strconv.FormatFloat(respMap["error_code"].(float64), 'f', 0, 64)
-		return nil, errors.New(errcode)
+		return nil, ctx.errorWrapper(ToInt(respMap["error_code"]), string(body))
 	}
 
 	info := respMap["info"].(map[string]interface{})
@@ -67,34 +67,34 @@ func (ctx *OKCoinCOM_API) GetAccount() (*Account, error) {
 	var usdSubAccount SubAccount
 
 	btcSubAccount.Currency = BTC
-	btcSubAccount.Amount, _ = strconv.ParseFloat(free["btc"].(string), 64)
-	btcSubAccount.LoanAmount = 0
-	btcSubAccount.FrozenAmount, _ = strconv.ParseFloat(freezed["btc"].(string), 64)
+	btcSubAccount.Amount = dp.Decimal(free["btc"])
+	btcSubAccount.LoanAmount = Decimal{}
+	btcSubAccount.FrozenAmount = dp.Decimal(freezed["btc"])
 
 	bchSubAccount.Currency = BCH
-	bchSubAccount.Amount, _ = strconv.ParseFloat(free["bch"].(string), 64)
-	bchSubAccount.LoanAmount = 0
-	bchSubAccount.FrozenAmount, _ = strconv.ParseFloat(freezed["bch"].(string), 64)
+	bchSubAccount.Amount = dp.Decimal(free["bch"])
+	bchSubAccount.LoanAmount = Decimal{}
+	bchSubAccount.FrozenAmount = dp.Decimal(freezed["bch"])
 
 	ltcSubAccount.Currency = LTC
-	ltcSubAccount.Amount, _ = strconv.ParseFloat(free["ltc"].(string), 64)
-	ltcSubAccount.LoanAmount = 0
-	ltcSubAccount.FrozenAmount, _ = strconv.ParseFloat(freezed["ltc"].(string), 64)
+	ltcSubAccount.Amount = dp.Decimal(free["ltc"])
+	ltcSubAccount.LoanAmount = Decimal{}
+	ltcSubAccount.FrozenAmount = dp.Decimal(freezed["ltc"])
 
 	etcSubAccount.Currency = ETC
-	etcSubAccount.Amount, _ = strconv.ParseFloat(free["etc"].(string), 64)
-	etcSubAccount.LoanAmount = 0
-	etcSubAccount.FrozenAmount, _ = strconv.ParseFloat(freezed["etc"].(string), 64)
+	etcSubAccount.Amount = dp.Decimal(free["etc"])
+	etcSubAccount.LoanAmount = Decimal{}
+	etcSubAccount.FrozenAmount = dp.Decimal(freezed["etc"])
 
 	ethSubAccount.Currency = ETH
-	ethSubAccount.Amount, _ = strconv.ParseFloat(free["eth"].(string), 64)
-	ethSubAccount.LoanAmount = 0
-	ethSubAccount.FrozenAmount, _ = strconv.ParseFloat(freezed["eth"].(string), 64)
+	ethSubAccount.Amount = dp.Decimal(free["eth"])
+	ethSubAccount.LoanAmount = Decimal{}
+	ethSubAccount.FrozenAmount = dp.Decimal(freezed["eth"])
 
 	usdSubAccount.Currency = USD
-	usdSubAccount.Amount, _ = strconv.ParseFloat(free["usd"].(string), 64)
-	usdSubAccount.LoanAmount = 0
-	usdSubAccount.FrozenAmount, _ = strconv.ParseFloat(freezed["usd"].(string), 64)
+	usdSubAccount.Amount = dp.Decimal(free["usd"])
+	usdSubAccount.LoanAmount = Decimal{}
+	usdSubAccount.FrozenAmount = dp.Decimal(freezed["usd"])
 
 	account.SubAccounts = make(map[Currency]SubAccount, 6)
 	account.SubAccounts[BTC] = btcSubAccount
@@ -104,6 +104,9 @@ func (ctx *OKCoinCOM_API) GetAccount() (*Account, error) {
 	account.SubAccounts[BCH] = bchSubAccount
 	account.SubAccounts[USD] = usdSubAccount
 
+	if dp.Err != nil {
+		return nil, dp.Err
+	}
 	return account, nil
 }
 
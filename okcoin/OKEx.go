@@ -36,6 +36,7 @@ type OKEx struct {
 }
 
 func NewOKEx(client *http.Client, api_key, secret_key string) *OKEx {
+	client = ExchangeRateLimitedClient(client, "okex.com", api_key, DefaultRateLimitConfig)
 	ok := new(OKEx)
 	ok.apiKey = api_key
 	ok.apiSecretKey = secret_key
@@ -66,6 +67,10 @@ func (ok *OKEx) GetExchangeName() string {
 	return "okex.com"
 }
 
+func (ok *OKEx) Capabilities() Capabilities {
+	return Capabilities{Kline: true, Future: true}
+}
+
 func (ok *OKEx) GetFutureEstimatedPrice(currencyPair CurrencyPair) (float64, error) {
 	resp, err := ok.client.Get(fmt.Sprintf(FUTURE_API_BASE_URL+FUTURE_ESTIMATED_PRICE, strings.ToLower(currencyPair.ToSymbol("_"))))
 	if err != nil {
@@ -92,6 +97,7 @@ func (ok *OKEx) GetFutureEstimatedPrice(currencyPair CurrencyPair) (float64, err
 }
 
 func (ok *OKEx) GetFutureTicker(currencyPair CurrencyPair, contractType string) (*Ticker, error) {
+	var dp DecimalParser
 	url := FUTURE_API_BASE_URL + FUTURE_TICKER_URI
 	//fmt.Println(fmt.Sprintf(url, strings.ToLower(currencyPair.ToSymbol("_")), contractType));
 	resp, err := ok.client.Get(fmt.Sprintf(url, strings.ToLower(currencyPair.ToSymbol("_")), contractType))
@@ -117,25 +123,29 @@ func (ok *OKEx) GetFutureTicker(currencyPair CurrencyPair, contractType string)
 	}
 
 	if bodyMap["result"] != nil && !bodyMap["result"].(bool) {
-		return nil, errors.New(string(body))
+		return nil, ok.errorWrapper(ToInt(bodyMap["error_code"]), string(body))
 	}
 
 	tickerMap := bodyMap["ticker"].(map[string]interface{})
 
 	ticker := new(Ticker)
 	ticker.Date, _ = strconv.ParseUint(bodyMap["date"].(string), 10, 64)
-	ticker.Buy = tickerMap["buy"].(float64)
-	ticker.Sell = tickerMap["sell"].(float64)
-	ticker.Last = tickerMap["last"].(float64)
-	ticker.High = tickerMap["high"].(float64)
-	ticker.Low = tickerMap["low"].(float64)
-	ticker.Vol = tickerMap["vol"].(float64)
+	ticker.Buy = dp.Decimal(tickerMap["buy"])
+	ticker.Sell = dp.Decimal(tickerMap["sell"])
+	ticker.Last = dp.Decimal(tickerMap["last"])
+	ticker.High = dp.Decimal(tickerMap["high"])
+	ticker.Low = dp.Decimal(tickerMap["low"])
+	ticker.Vol = dp.Decimal(tickerMap["vol"])
 
 	//fmt.Println(bodyMap)
+	if dp.Err != nil {
+		return nil, dp.Err
+	}
 	return ticker, nil
 }
 
 func (ok *OKEx) GetFutureDepth(currencyPair CurrencyPair, contractType string, size int) (*Depth, error) {
+	var dp DecimalParser
 	url := FUTURE_API_BASE_URL + FUTURE_DEPTH_URI
 	//fmt.Println(fmt.Sprintf(url, strings.ToLower(currencyPair.ToSymbol("_")), contractType));
 	resp, err := ok.client.Get(fmt.Sprintf(url, strings.ToLower(strings.ToLower(currencyPair.ToSymbol("_"))), contractType))
@@ -162,7 +172,7 @@ func (ok *OKEx) GetFutureDepth(currencyPair CurrencyPair, contractType string, s
 
 	if bodyMap["error_code"] != nil {
 		log.Println(bodyMap)
-		return nil, errors.New(string(body))
+		return nil, ok.errorWrapper(ToInt(bodyMap["error_code"]), string(body))
 	}
 
 	depth := new(Depth)
@@ -182,9 +192,9 @@ func (ok *OKEx) GetFutureDepth(currencyPair CurrencyPair, contractType string, s
 		for i, vv := range v.([]interface{}) {
 			switch i {
 			case 0:
-				dr.Price = vv.(float64)
+				dr.Price = dp.Price(vv)
 			case 1:
-				dr.Amount = vv.(float64)
+				dr.Amount = dp.Decimal(vv)
 			}
 		}
 		depth.AskList = append(depth.AskList, dr)
@@ -195,9 +205,9 @@ func (ok *OKEx) GetFutureDepth(currencyPair CurrencyPair, contractType string, s
 		for i, vv := range v.([]interface{}) {
 			switch i {
 			case 0:
-				dr.Price = vv.(float64)
+				dr.Price = dp.Price(vv)
 			case 1:
-				dr.Amount = vv.(float64)
+				dr.Amount = dp.Decimal(vv)
 			}
 		}
 		depth.BidList = append(depth.BidList, dr)
@@ -209,6 +219,9 @@ func (ok *OKEx) GetFutureDepth(currencyPair CurrencyPair, contractType string, s
 	}
 
 	//fmt.Println(bodyMap)
+	if dp.Err != nil {
+		return nil, dp.Err
+	}
 	return depth, nil
 }
 
@@ -248,7 +261,7 @@ func (ok *OKEx) GetFutureUserinfo() (*FutureAccount, error) {
 	}
 
 	if !resp.Result && resp.Error_code > 0 {
-		return nil, ok.errorWrapper(resp.Error_code)
+		return nil, ok.errorWrapper(resp.Error_code, string(body))
 	}
 
 	account := new(FutureAccount)
@@ -297,7 +310,7 @@ func (ok *OKEx) PlaceFutureOrder(currencyPair CurrencyPair, contractType, price,
 	//println(string(body));
 
 	if !respMap["result"].(bool) {
-		return "", errors.New(string(body))
+		return "", ok.errorWrapper(ToInt(respMap["error_code"]), string(body))
 	}
 
 	return fmt.Sprintf("%.0f", respMap["order_id"].(float64)), nil
@@ -325,7 +338,7 @@ func (ok *OKEx) FutureCancelOrder(currencyPair CurrencyPair, contractType, order
 	}
 
 	if respMap["result"] != nil && !respMap["result"].(bool) {
-		return false, errors.New(string(body))
+		return false, ok.errorWrapper(ToInt(respMap["error_code"]), string(body))
 	}
 
 	return true, nil
@@ -354,7 +367,7 @@ func (ok *OKEx) GetFuturePosition(currencyPair CurrencyPair, contractType string
 	}
 
 	if !respMap["result"].(bool) {
-		return nil, errors.New(string(body))
+		return nil, ok.errorWrapper(ToInt(respMap["error_code"]), string(body))
 	}
 
 	//println(string(body))
@@ -402,7 +415,7 @@ func (ok *OKEx) parseOrders(body []byte, currencyPair CurrencyPair) ([]FutureOrd
 	}
 
 	if !respMap["result"].(bool) {
-		return nil, errors.New(string(body))
+		return nil, ok.errorWrapper(ToInt(respMap["error_code"]), string(body))
 	}
 
 	var orders []interface{}
@@ -576,20 +589,27 @@ func (ok *OKEx) GetKlineRecords(contract_type string, currencyPair CurrencyPair,
 }
 
 func (okFuture *OKEx) GetTrades(currencyPair CurrencyPair, since int64) ([]Trade, error) {
-	panic("unimplements")
+	return nil, ErrNotSupported
 }
 
-func (okFuture *OKEx) errorWrapper(errorCode int) ApiError {
+func (okFuture *OKEx) errorWrapper(errorCode int, originErrMsg string) ApiError {
+	var errCode ApiError
 	switch errorCode {
 	case 20024:
-		return EX_ERR_SIGN
+		errCode = EX_ERR_SIGN
 	case 20020:
-		return EX_ERR_NOT_FIND_SECRETKEY
+		errCode = EX_ERR_NOT_FIND_SECRETKEY
 	case 20015:
-		return EX_ERR_NOT_FIND_ORDER
+		errCode = EX_ERR_NOT_FIND_ORDER
 	case 20049:
-		return EX_ERR_API_LIMIT
-	}
-	errmsg := fmt.Sprintf("%d", errorCode)
-	return ApiError{ErrCode: errmsg, OriginErrMsg: errmsg}
+		errCode = EX_ERR_API_LIMIT
+	case 20008, 20016, 20029:
+		errCode = EX_ERR_INSUFFICIENT_BALANCE
+	case 20028:
+		errCode = EX_ERR_INVALID_CURRENCY_PAIR
+	default:
+		errCode = API_ERR
+	}
+	errCode.OriginErrMsg = originErrMsg
+	return errCode
 }
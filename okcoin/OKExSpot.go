@@ -3,27 +3,50 @@ package okcoin
 import (
 	"encoding/json"
 	"errors"
+	"fmt"
 	"net/http"
 	"net/url"
 	"strconv"
+	"strings"
+	"time"
 
 	. "github.com/nntaoli-project/GoEx"
 )
 
+//OKExSpot trades on the v1 api. Client order ids and fills only exist in the v3 api, which also needs
+//the passphrase of the api key, see NewOKExSpotV3.
 type OKExSpot struct {
 	OKCoinCN_API
+	passphrase string
+	v3BaseUrl  string
 }
 
 func NewOKExSpot(client *http.Client, accesskey, secretkey string) *OKExSpot {
+	return NewOKExSpotV3(client, accesskey, secretkey, "")
+}
+
+//NewOKExSpotV3 also takes the passphrase given when the api key was created, for the calls of the v3 api
+func NewOKExSpotV3(client *http.Client, accesskey, secretkey, passphrase string) *OKExSpot {
+	client = ExchangeRateLimitedClient(client, "okex.com", accesskey, DefaultRateLimitConfig)
 	return &OKExSpot{
-		OKCoinCN_API{client, accesskey, secretkey, "https://www.okex.com/api/v1/"}}
+		OKCoinCN_API: OKCoinCN_API{client, accesskey, secretkey, "https://www.okex.com/api/v1/"},
+		passphrase:   passphrase,
+		v3BaseUrl:    "https://www.okex.com"}
 }
 
 func (ctx *OKExSpot) GetExchangeName() string {
 	return "okex.com"
 }
 
+func (ctx *OKExSpot) Capabilities() Capabilities {
+	c := ctx.OKCoinCN_API.Capabilities()
+	c.ClientOrder = ctx.passphrase != ""
+	c.MyTrades = ctx.passphrase != ""
+	return c
+}
+
 func (ctx *OKExSpot) GetAccount() (*Account, error) {
+	var dp DecimalParser
 	postData := url.Values{}
 	err := ctx.buildPostForm(&postData)
 	if err != nil {
@@ -43,8 +66,7 @@ func (ctx *OKExSpot) GetAccount() (*Account, error) {
 	}
 
 	if errcode, isok := respMap["error_code"].(float64); isok {
-		errcodeStr := strconv.FormatFloat(errcode, 'f', 0, 64)
-		return nil, errors.New(errcodeStr)
+		return nil, ctx.errorWrapper(int(errcode), string(body))
 	}
 	//log.Println(respMap)
 	info, ok := respMap["info"].(map[string]interface{})
@@ -64,10 +86,314 @@ func (ctx *OKExSpot) GetAccount() (*Account, error) {
 		currencyKey := NewCurrency(k, "")
 		subAcc := SubAccount{
 			Currency:     currencyKey,
-			Amount:       ToFloat64(v),
-			FrozenAmount: ToFloat64(freezed[k])}
+			Amount:       dp.Decimal(v),
+			FrozenAmount: dp.Decimal(freezed[k])}
 		account.SubAccounts[currencyKey] = subAcc
 	}
 
+	if dp.Err != nil {
+		return nil, dp.Err
+	}
 	return account, nil
 }
+
+//PlaceOrderWithClientID places the order with the client_oid of the v3 api, GetOneOrder and CancelOrder take it
+//in place of the order id. okex only accepts letters and digits in a client_oid. A market buy spends price,
+//in the quote currency, as MarketBuy does.
+func (ctx *OKExSpot) PlaceOrderWithClientID(clientOrderId string, side TradeSide, amount, price Decimal, currency CurrencyPair) (*Order, error) {
+	if !ValidClientOrderID(clientOrderId) || strings.ContainsAny(clientOrderId, "_-") {
+		errCode := EX_ERR_INVALID_CLIENT_ORDER_ID
+		errCode.OriginErrMsg = clientOrderId
+		return nil, errCode
+	}
+	body := map[string]interface{}{
+		"client_oid":    clientOrderId,
+		"instrument_id": currency.ToSymbol("-")}
+	switch side {
+	case BUY, SELL:
+		body["type"] = "limit"
+		body["size"] = amount.String()
+		body["price"] = price.String()
+	case BUY_MARKET:
+		body["type"] = "market"
+		body["notional"] = price.String()
+	case SELL_MARKET:
+		body["type"] = "market"
+		body["size"] = amount.String()
+	default:
+		errCode := EX_ERR_PLACE_ORDER_FAIL
+		errCode.OriginErrMsg = "unknown side " + side.String()
+		return nil, errCode
+	}
+	body["side"] = "buy"
+	if side == SELL || side == SELL_MARKET {
+		body["side"] = "sell"
+	}
+
+	var resp struct {
+		OrderId      string `json:"order_id"`
+		Result       bool   `json:"result"`
+		ErrorCode    string `json:"error_code"`
+		ErrorMessage string `json:"error_message"`
+	}
+	if err := ctx.doRequestV3("POST", "/api/spot/v3/orders", body, &resp); err != nil {
+		return nil, err
+	}
+	if !resp.Result {
+		return nil, ctx.errorWrapperV3(ToInt(resp.ErrorCode), resp.ErrorMessage)
+	}
+	return &Order{
+		OrderID:       ToInt(resp.OrderId),
+		OrderID2:      resp.OrderId,
+		ClientOrderID: clientOrderId,
+		Currency:      currency,
+		Amount:        amount,
+		Price:         price,
+		Side:          side,
+		Status:        ORDER_UNFINISH}, nil
+}
+
+//isClientOrderId tells a client_oid from okex's own order ids, which are integers
+func isClientOrderId(orderId string) bool {
+	_, err := strconv.ParseInt(orderId, 10, 64)
+	return err != nil
+}
+
+func (ctx *OKExSpot) GetOneOrder(orderId string, currency CurrencyPair) (*Order, error) {
+	if !isClientOrderId(orderId) {
+		return ctx.OKCoinCN_API.GetOneOrder(orderId, currency)
+	}
+	var resp map[string]interface{}
+	path := "/api/spot/v3/orders/" + url.PathEscape(orderId) + "?instrument_id=" + currency.ToSymbol("-")
+	if err := ctx.doRequestV3("GET", path, nil, &resp); err != nil {
+		return nil, err
+	}
+	return ctx.parseOrderV3(resp, currency)
+}
+
+func (ctx *OKExSpot) CancelOrder(orderId string, currency CurrencyPair) (bool, error) {
+	if !isClientOrderId(orderId) {
+		return ctx.OKCoinCN_API.CancelOrder(orderId, currency)
+	}
+	var resp struct {
+		Result       bool   `json:"result"`
+		ErrorCode    string `json:"error_code"`
+		ErrorMessage string `json:"error_message"`
+	}
+	path := "/api/spot/v3/cancel_orders/" + url.PathEscape(orderId)
+	if err := ctx.doRequestV3("POST", path, map[string]interface{}{"instrument_id": currency.ToSymbol("-")}, &resp); err != nil {
+		return false, err
+	}
+	if !resp.Result {
+		return false, ctx.errorWrapperV3(ToInt(resp.ErrorCode), resp.ErrorMessage)
+	}
+	return true, nil
+}
+
+//parseOrderV3 reads an order of the v3 api, state -2 failed, -1 canceled, 0 open, 1 partially filled,
+//2 filled, 3 submitting, 4 canceling
+func (ctx *OKExSpot) parseOrderV3(ordmap map[string]interface{}, currency CurrencyPair) (*Order, error) {
+	var dp DecimalParser
+	orderId := fmt.Sprint(ordmap["order_id"])
+	ord := &Order{
+		OrderID:    ToInt(orderId),
+		OrderID2:   orderId,
+		Currency:   currency,
+		Amount:     dp.Decimal(ordmap["size"]),
+		Price:      dp.Decimal(ordmap["price"]),
+		DealAmount: dp.Decimal(ordmap["filled_size"]),
+		AvgPrice:   dp.Decimal(ordmap["price_avg"])}
+	ord.ClientOrderID, _ = ordmap["client_oid"].(string)
+	if created, err := time.Parse(time.RFC3339, fmt.Sprint(ordmap["timestamp"])); err == nil {
+		ord.OrderTime = int(created.UnixNano() / int64(time.Millisecond))
+	}
+
+	market := ordmap["type"] == "market"
+	switch {
+	case ordmap["side"] == "buy" && market:
+		ord.Side = BUY_MARKET
+	case ordmap["side"] == "buy":
+		ord.Side = BUY
+	case market:
+		ord.Side = SELL_MARKET
+	default:
+		ord.Side = SELL
+	}
+
+	switch fmt.Sprint(ordmap["state"]) {
+	case "-2":
+		ord.Status = ORDER_REJECT
+	case "-1":
+		ord.Status = ORDER_CANCEL
+	case "1":
+		ord.Status = ORDER_PART_FINISH
+	case "2":
+		ord.Status = ORDER_FINISH
+	case "4":
+		ord.Status = ORDER_CANCEL_ING
+	default:
+		ord.Status = ORDER_UNFINISH
+	}
+	if dp.Err != nil {
+		return nil, dp.Err
+	}
+	return ord, nil
+}
+
+//GetMyTrades pages the v3 fills back from the newest, 100 ledger entries to a page, until since.
+//okex books every fill as two ledger entries with the same trade_id, one of the base currency with
+//the side and size of the fill and one of the quote currency; the fee is on the one it was paid in.
+func (ctx *OKExSpot) GetMyTrades(pair CurrencyPair, since int64, limit int) ([]MyTrade, error) {
+	const pageSize = 100
+	type fill struct {
+		LedgerId  string `json:"ledger_id"`
+		TradeId   string `json:"trade_id"`
+		OrderId   string `json:"order_id"`
+		Currency  string `json:"currency"`
+		Side      string `json:"side"`
+		Price     string `json:"price"`
+		Size      string `json:"size"`
+		Fee       string `json:"fee"`
+		ExecType  string `json:"exec_type"`
+		Timestamp string `json:"timestamp"`
+	}
+	var fills []fill
+	after := ""
+	for {
+		params := url.Values{}
+		params.Set("instrument_id", pair.ToSymbol("-"))
+		params.Set("limit", strconv.Itoa(pageSize))
+		if after != "" {
+			params.Set("after", after)
+		}
+		var page []fill
+		if err := ctx.doRequestV3("GET", "/api/spot/v3/fills?"+params.Encode(), nil, &page); err != nil {
+			return nil, err
+		}
+		fills = append(fills, page...)
+		if len(page) < pageSize {
+			break
+		}
+		oldest := page[len(page)-1]
+		if t, err := time.Parse(time.RFC3339, oldest.Timestamp); err == nil && t.UnixNano()/int64(time.Millisecond) < since {
+			break
+		}
+		after = oldest.LedgerId
+	}
+
+	var dp DecimalParser
+	byTrade := make(map[string]*MyTrade)
+	var ids []string
+	for _, f := range fills {
+		id := f.TradeId
+		if id == "" {
+			id = f.OrderId + ":" + f.Timestamp + ":" + f.Price
+		}
+		trade, isok := byTrade[id]
+		if !isok {
+			trade = &MyTrade{TradeID: f.TradeId, OrderID: f.OrderId, Currency: pair, Price: dp.Decimal(f.Price),
+				IsMaker: f.ExecType == "M"}
+			if t, err := time.Parse(time.RFC3339, f.Timestamp); err == nil {
+				trade.Time = t.UnixNano() / int64(time.Millisecond)
+			}
+			byTrade[id] = trade
+			ids = append(ids, id)
+		}
+		if strings.EqualFold(f.Currency, pair.CurrencyA.Symbol) {
+			trade.Amount = dp.Decimal(f.Size)
+			trade.Side = TradeSide(BUY)
+			if f.Side == "sell" {
+				trade.Side = TradeSide(SELL)
+			}
+		}
+		if fee := dp.Decimal(f.Fee); !fee.IsZero() {
+			trade.Fee = fee.Abs()
+			trade.FeeCurrency = NewCurrency(f.Currency, "")
+		}
+	}
+	if dp.Err != nil {
+		return nil, dp.Err
+	}
+
+	trades := make([]MyTrade, 0, len(ids))
+	for _, id := range ids {
+		trades = append(trades, *byTrade[id])
+	}
+	return MyTradesSince(trades, since, limit), nil
+}
+
+//doRequestV3 sends a request signed for the v3 api: the base64 hmac sha256 of timestamp, method, path
+//with the query and body. Errors come as {"code":..., "message":...} or {"error_code":..., "error_message":...}.
+func (ctx *OKExSpot) doRequestV3(method, path string, body map[string]interface{}, ret interface{}) error {
+	if ctx.passphrase == "" {
+		errCode := EX_ERR_NOT_FIND_APIKEY
+		errCode.OriginErrMsg = "the okex v3 api needs the passphrase of the api key"
+		return errCode
+	}
+	payload := ""
+	if body != nil {
+		p, err := json.Marshal(body)
+		if err != nil {
+			return err
+		}
+		payload = string(p)
+	}
+	timestamp := time.Now().UTC().Format("2006-01-02T15:04:05.000Z")
+	sign, _ := GetParamHmacSHA256Base64Sign(ctx.secret_key, timestamp+method+path+payload)
+
+	resp, err := NewHttpRequest(ctx.client, method, ctx.v3BaseUrl+path, payload, map[string]string{
+		"Content-Type":         "application/json",
+		"OK-ACCESS-KEY":        ctx.api_key,
+		"OK-ACCESS-SIGN":       sign,
+		"OK-ACCESS-TIMESTAMP":  timestamp,
+		"OK-ACCESS-PASSPHRASE": ctx.passphrase})
+	if err != nil {
+		return ctx.adaptErrorV3(err)
+	}
+	return json.Unmarshal(resp, ret)
+}
+
+func (ctx *OKExSpot) adaptErrorV3(err error) error {
+	apiErr, isok := err.(ApiError)
+	if !isok {
+		return err
+	}
+	var resp struct {
+		Code         interface{} `json:"code"`
+		Message      string      `json:"message"`
+		ErrorCode    interface{} `json:"error_code"`
+		ErrorMessage string      `json:"error_message"`
+	}
+	if json.Unmarshal([]byte(apiErr.OriginErrMsg), &resp) != nil {
+		return err
+	}
+	switch {
+	case resp.Code != nil:
+		return ctx.errorWrapperV3(ToInt(resp.Code), resp.Message)
+	case resp.ErrorCode != nil:
+		return ctx.errorWrapperV3(ToInt(resp.ErrorCode), resp.ErrorMessage)
+	}
+	return err
+}
+
+func (ctx *OKExSpot) errorWrapperV3(code int, message string) ApiError {
+	var errCode ApiError
+	switch code {
+	case 30006:
+		errCode = EX_ERR_NOT_FIND_APIKEY
+	case 30008:
+		errCode = EX_ERR_NONCE
+	case 30013:
+		errCode = EX_ERR_SIGN
+	case 30014, 30026:
+		errCode = EX_ERR_API_LIMIT
+	case 33014:
+		errCode = EX_ERR_NOT_FIND_ORDER
+	case 33017:
+		errCode = EX_ERR_INSUFFICIENT_BALANCE
+	default:
+		errCode = API_ERR
+	}
+	errCode.OriginErrMsg = fmt.Sprintf("%d %s", code, message)
+	return errCode
+}
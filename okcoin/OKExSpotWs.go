@@ -0,0 +1,441 @@
+package okcoin
+
+import (
+	"encoding/json"
+	"fmt"
+	. "github.com/nntaoli-project/GoEx"
+	"hash/crc32"
+	"log"
+	"strings"
+	"sync"
+	"time"
+)
+
+const (
+	OKEX_WS_URL    = "wss://real.okex.com:10441/websocket"
+	OKEX_V3_WS_URL = "wss://real.okex.com:8443/ws/v3"
+)
+
+var wsKlinePeriods = map[int]string{
+	KLINE_PERIOD_1MIN:  "1min",
+	KLINE_PERIOD_5MIN:  "5min",
+	KLINE_PERIOD_15MIN: "15min",
+	KLINE_PERIOD_30MIN: "30min",
+	KLINE_PERIOD_60MIN: "1hour",
+	KLINE_PERIOD_4H:    "4hour",
+	KLINE_PERIOD_1DAY:  "day",
+	KLINE_PERIOD_1WEEK: "week",
+}
+
+//okex stamps deals with the time of day in Beijing time only
+var okexDealsLocation = time.FixedZone("CST", 8*60*60)
+
+//OKExSpotWs implements StreamAPI on the okex spot v1 websocket, kept alive with {"event":"ping"}.
+//Order books come from the v3 websocket, the only one with incremental depth.
+type OKExSpotWs struct {
+	wsUrl    string
+	wsConn   *WsConn
+	v3WsUrl  string
+	v3WsConn *WsConn
+	l        sync.Mutex
+	chans    map[string]interface{}
+	handles  map[string]func(data json.RawMessage) error
+	books    map[string]*OrderBook
+	closers  []func()
+	closeCh  chan struct{}
+	once     sync.Once
+}
+
+func NewOKExSpotWs() *OKExSpotWs {
+	return &OKExSpotWs{
+		wsUrl:   OKEX_WS_URL,
+		v3WsUrl: OKEX_V3_WS_URL,
+		chans:   make(map[string]interface{}),
+		handles: make(map[string]func(data json.RawMessage) error),
+		books:   make(map[string]*OrderBook),
+		closeCh: make(chan struct{})}
+}
+
+func (okWs *OKExSpotWs) GetExchangeName() string {
+	return "okex.com"
+}
+
+func (okWs *OKExSpotWs) SubscribeTicker(pair CurrencyPair) (<-chan Ticker, error) {
+	ch := make(chan Ticker, 64)
+	c, err := okWs.subscribe(okWs.channel(pair, "ticker"), ch, func() { close(ch) }, func(data json.RawMessage) error {
+		var t map[string]interface{}
+		if err := json.Unmarshal(data, &t); err != nil {
+			return err
+		}
+		var dp DecimalParser
+		ticker := Ticker{
+			Last: dp.Decimal(t["last"]),
+			Buy:  dp.Decimal(t["buy"]),
+			Sell: dp.Decimal(t["sell"]),
+			High: dp.Decimal(t["high"]),
+			Low:  dp.Decimal(t["low"]),
+			Vol:  dp.Decimal(t["vol"]),
+			Date: ToUint64(t["timestamp"])}
+		if dp.Err != nil {
+			return dp.Err
+		}
+		select {
+		case ch <- ticker:
+		case <-okWs.closeCh:
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return c.(chan Ticker), nil
+}
+
+//size is rounded up to one of the depth channels okex offers: 5, 10 or 20
+func (okWs *OKExSpotWs) SubscribeDepth(pair CurrencyPair, size int) (<-chan Depth, error) {
+	level := 20
+	if size <= 5 {
+		level = 5
+	} else if size <= 10 {
+		level = 10
+	}
+
+	ch := make(chan Depth, 64)
+	c, err := okWs.subscribe(okWs.channel(pair, fmt.Sprintf("depth_%d", level)), ch, func() { close(ch) }, func(data json.RawMessage) error {
+		var d struct {
+			Asks [][]interface{} `json:"asks"`
+			Bids [][]interface{} `json:"bids"`
+		}
+		if err := json.Unmarshal(data, &d); err != nil {
+			return err
+		}
+		var dp DecimalParser
+		var depth Depth
+		//asks come from the highest price, turn them around so both sides start at the best price
+		for i := len(d.Asks) - 1; i >= 0 && len(depth.AskList) < size; i-- {
+			if len(d.Asks[i]) >= 2 {
+				depth.AskList = append(depth.AskList, DepthRecord{Price: dp.Price(d.Asks[i][0]), Amount: dp.Decimal(d.Asks[i][1])})
+			}
+		}
+		for i := 0; i < len(d.Bids) && len(depth.BidList) < size; i++ {
+			if len(d.Bids[i]) >= 2 {
+				depth.BidList = append(depth.BidList, DepthRecord{Price: dp.Price(d.Bids[i][0]), Amount: dp.Decimal(d.Bids[i][1])})
+			}
+		}
+		if dp.Err != nil {
+			return dp.Err
+		}
+		select {
+		case ch <- depth:
+		case <-okWs.closeCh:
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return c.(chan Depth), nil
+}
+
+func (okWs *OKExSpotWs) SubscribeTrades(pair CurrencyPair) (<-chan Trade, error) {
+	ch := make(chan Trade, 64)
+	c, err := okWs.subscribe(okWs.channel(pair, "deals"), ch, func() { close(ch) }, func(data json.RawMessage) error {
+		//[[tid, price, amount, "15:04:05", "ask"|"bid"], ...]
+		var deals [][]interface{}
+		if err := json.Unmarshal(data, &deals); err != nil {
+			return err
+		}
+		var dp DecimalParser
+		var trades []Trade
+		for _, d := range deals {
+			if len(d) < 5 {
+				continue
+			}
+			trade := Trade{
+				Tid:    ToInt64(d[0]),
+				Type:   "buy",
+				Price:  dp.Decimal(d[1]),
+				Amount: dp.Decimal(d[2]),
+				Date:   okWs.adaptDealTime(fmt.Sprint(d[3]), time.Now())}
+			if d[4] == "ask" {
+				trade.Type = "sell"
+			}
+			trades = append(trades, trade)
+		}
+		if dp.Err != nil {
+			return dp.Err
+		}
+		for _, trade := range trades {
+			select {
+			case ch <- trade:
+			case <-okWs.closeCh:
+				return nil
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return c.(chan Trade), nil
+}
+
+func (okWs *OKExSpotWs) SubscribeKline(pair CurrencyPair, period int) (<-chan Kline, error) {
+	periodStr, isok := wsKlinePeriods[period]
+	if !isok {
+		return nil, ErrNotSupported
+	}
+
+	ch := make(chan Kline, 64)
+	c, err := okWs.subscribe(okWs.channel(pair, "kline_"+periodStr), ch, func() { close(ch) }, func(data json.RawMessage) error {
+		//[[timestamp, open, high, low, close, vol], ...]
+		var klines [][]interface{}
+		if err := json.Unmarshal(data, &klines); err != nil {
+			return err
+		}
+		for _, k := range klines {
+			if len(k) < 6 {
+				continue
+			}
+			kline := Kline{
+				Timestamp: ToInt64(k[0]) / 1000,
+				Open:      ToFloat64(k[1]),
+				High:      ToFloat64(k[2]),
+				Low:       ToFloat64(k[3]),
+				Close:     ToFloat64(k[4]),
+				Vol:       ToFloat64(k[5])}
+			select {
+			case ch <- kline:
+			case <-okWs.closeCh:
+				return nil
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return c.(chan Kline), nil
+}
+
+//SubscribeOrderBook keeps a local book from the v3 spot/depth channel, a 200 level snapshot followed by
+//incremental updates that each carry a checksum. On a mismatch the channel is resubscribed for a new snapshot.
+func (okWs *OKExSpotWs) SubscribeOrderBook(pair CurrencyPair) (*OrderBook, error) {
+	okWs.l.Lock()
+	defer okWs.l.Unlock()
+
+	arg := "spot/depth:" + pair.ToSymbol("-")
+	if ob, isok := okWs.books[arg]; isok {
+		return ob, nil
+	}
+
+	if okWs.v3WsConn == nil {
+		wsConn := NewWsConn(okWs.v3WsUrl, okWs.handleV3)
+		wsConn.UnCompressFunc = FlateUnCompress
+		wsConn.HeartbeatInterval = 25 * time.Second
+		wsConn.HeartbeatData = func() []byte { return []byte("ping") }
+		wsConn.ReadTimeout = time.Minute
+		if err := wsConn.Connect(); err != nil {
+			return nil, err
+		}
+		okWs.v3WsConn = wsConn
+	}
+
+	ob := NewOrderBook()
+	ob.ChecksumFunc = okWs.checksum
+	okWs.books[arg] = ob
+	okWs.v3WsConn.Subscribe(map[string]interface{}{"op": "subscribe", "args": []string{arg}})
+	return ob, nil
+}
+
+func (okWs *OKExSpotWs) Close() error {
+	okWs.once.Do(func() { close(okWs.closeCh) })
+	okWs.l.Lock()
+	wsConn, v3WsConn := okWs.wsConn, okWs.v3WsConn
+	okWs.l.Unlock()
+	if v3WsConn != nil {
+		v3WsConn.Close()
+	}
+	if wsConn == nil {
+		return nil
+	}
+	return wsConn.Close()
+}
+
+func (okWs *OKExSpotWs) channel(pair CurrencyPair, name string) string {
+	return fmt.Sprintf("ok_sub_spot_%s_%s", strings.ToLower(pair.ToSymbol("_")), name)
+}
+
+func (okWs *OKExSpotWs) subscribe(channel string, ch interface{}, closer func(), handle func(data json.RawMessage) error) (interface{}, error) {
+	okWs.l.Lock()
+	defer okWs.l.Unlock()
+
+	select {
+	case <-okWs.closeCh:
+		return nil, EX_ERR_STREAM_CLOSED
+	default:
+	}
+	if c, isok := okWs.chans[channel]; isok {
+		return c, nil
+	}
+
+	if okWs.wsConn == nil {
+		wsConn := NewWsConn(okWs.wsUrl, okWs.handle)
+		wsConn.HeartbeatInterval = 25 * time.Second
+		wsConn.HeartbeatData = func() []byte { return []byte(`{"event":"ping"}`) }
+		wsConn.ReadTimeout = time.Minute
+		wsConn.CloseHandleFunc = okWs.closeChans
+		if err := wsConn.Connect(); err != nil {
+			return nil, err
+		}
+		okWs.wsConn = wsConn
+	}
+
+	okWs.chans[channel] = ch
+	okWs.handles[channel] = handle
+	okWs.closers = append(okWs.closers, closer)
+	okWs.wsConn.Subscribe(map[string]interface{}{"event": "addChannel", "channel": channel})
+	return ch, nil
+}
+
+func (okWs *OKExSpotWs) handle(msg []byte) error {
+	if len(msg) > 0 && msg[0] == '{' {
+		return nil //{"event":"pong"}
+	}
+
+	var resps []struct {
+		Channel string          `json:"channel"`
+		Data    json.RawMessage `json:"data"`
+	}
+	if err := json.Unmarshal(msg, &resps); err != nil {
+		return err
+	}
+
+	for _, resp := range resps {
+		if resp.Channel == "addChannel" {
+			var ret map[string]interface{}
+			json.Unmarshal(resp.Data, &ret)
+			if result, _ := ret["result"].(bool); !result {
+				return (&OKCoinCN_API{}).errorWrapper(ToInt(ret["error_code"]), string(resp.Data))
+			}
+			continue
+		}
+
+		okWs.l.Lock()
+		handle := okWs.handles[resp.Channel]
+		okWs.l.Unlock()
+
+		if handle != nil {
+			if err := handle(resp.Data); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+func (okWs *OKExSpotWs) handleV3(msg []byte) error {
+	if string(msg) == "pong" {
+		return nil
+	}
+
+	var resp struct {
+		Event     string `json:"event"`
+		Message   string `json:"message"`
+		ErrorCode int    `json:"errorCode"`
+		Table     string `json:"table"`
+		Action    string `json:"action"`
+		Data      []struct {
+			InstrumentId string          `json:"instrument_id"`
+			Asks         [][]interface{} `json:"asks"`
+			Bids         [][]interface{} `json:"bids"`
+			Checksum     int32           `json:"checksum"`
+		} `json:"data"`
+	}
+	if err := json.Unmarshal(msg, &resp); err != nil {
+		return err
+	}
+	if resp.Event == "error" {
+		errCode := API_ERR
+		errCode.OriginErrMsg = fmt.Sprintf("%d %s", resp.ErrorCode, resp.Message)
+		return errCode
+	}
+	if resp.Table != "spot/depth" {
+		return nil
+	}
+
+	for _, d := range resp.Data {
+		arg := "spot/depth:" + d.InstrumentId
+		okWs.l.Lock()
+		ob, wsConn := okWs.books[arg], okWs.v3WsConn
+		okWs.l.Unlock()
+		if ob == nil {
+			continue
+		}
+
+		//[price, size, number of orders], a size of 0 removes the level
+		var dp DecimalParser
+		u := OrderBookUpdate{Checksum: d.Checksum, HasChecksum: true}
+		for _, r := range d.Bids {
+			if len(r) >= 2 {
+				u.Bids = append(u.Bids, DepthRecord{Price: dp.Price(r[0]), Amount: dp.Decimal(r[1])})
+			}
+		}
+		for _, r := range d.Asks {
+			if len(r) >= 2 {
+				u.Asks = append(u.Asks, DepthRecord{Price: dp.Price(r[0]), Amount: dp.Decimal(r[1])})
+			}
+		}
+		if dp.Err != nil {
+			return dp.Err
+		}
+		if resp.Action == "partial" {
+			ob.Reset(nil, 0)
+		}
+		err := ob.Update(u)
+		if EX_ERR_ORDERBOOK_CHECKSUM.Is(err) {
+			log.Println("[okex ws]", arg, err, ", resubscribing")
+			wsConn.SendJsonMessage(map[string]interface{}{"op": "unsubscribe", "args": []string{arg}})
+			wsConn.SendJsonMessage(map[string]interface{}{"op": "subscribe", "args": []string{arg}})
+		}
+	}
+	return nil
+}
+
+//checksum is the crc32 okex computes over the top 25 levels as "bidPrice:bidSize:askPrice:askSize:...",
+//the numbers printed without trailing zeros
+func (okWs *OKExSpotWs) checksum(top *Depth) int32 {
+	var fields []string
+	for i := 0; i < 25; i++ {
+		if i < len(top.BidList) {
+			fields = append(fields, top.BidList[i].Price.String(), top.BidList[i].Amount.String())
+		}
+		if i < len(top.AskList) {
+			fields = append(fields, top.AskList[i].Price.String(), top.AskList[i].Amount.String())
+		}
+	}
+	return int32(crc32.ChecksumIEEE([]byte(strings.Join(fields, ":"))))
+}
+
+//adaptDealTime turns the "15:04:05" beijing time of a deal into unix milliseconds, relative to now
+func (okWs *OKExSpotWs) adaptDealTime(clock string, now time.Time) int64 {
+	t, err := time.ParseInLocation("15:04:05", clock, okexDealsLocation)
+	if err != nil {
+		return now.UnixNano() / int64(time.Millisecond)
+	}
+	now = now.In(okexDealsLocation)
+	dealTime := time.Date(now.Year(), now.Month(), now.Day(), t.Hour(), t.Minute(), t.Second(), 0, okexDealsLocation)
+	if dealTime.After(now.Add(time.Minute)) { //a deal from before midnight
+		dealTime = dealTime.AddDate(0, 0, -1)
+	}
+	return dealTime.UnixNano() / int64(time.Millisecond)
+}
+
+func (okWs *OKExSpotWs) closeChans() {
+	okWs.l.Lock()
+	defer okWs.l.Unlock()
+	for _, closer := range okWs.closers {
+		closer()
+	}
+	okWs.closers = nil
+}
@@ -0,0 +1,224 @@
+package okcoin
+
+import (
+	"bytes"
+	"compress/flate"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/gorilla/websocket"
+	. "github.com/nntaoli-project/GoEx"
+	"github.com/stretchr/testify/assert"
+)
+
+//newWsStandIn acks every addChannel and sends one canned push per channel, answering pings with pongs.
+//The first connection is dropped after its first push so the client has to reconnect and resubscribe.
+func newWsStandIn(t *testing.T, pushes map[string]string) (*httptest.Server, chan string) {
+	subscribed := make(chan string, 16)
+	upgrader := websocket.Upgrader{}
+	var conns int32
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		conn, err := upgrader.Upgrade(w, r, nil)
+		if err != nil {
+			t.Error(err)
+			return
+		}
+		defer conn.Close()
+		first := atomic.AddInt32(&conns, 1) == 1
+		for {
+			var req map[string]interface{}
+			if err := conn.ReadJSON(&req); err != nil {
+				return
+			}
+			if req["event"] == "ping" {
+				conn.WriteMessage(websocket.TextMessage, []byte(`{"event":"pong"}`))
+				continue
+			}
+			channel := fmt.Sprint(req["channel"])
+			conn.WriteMessage(websocket.TextMessage, []byte(`[{"binary":0,"channel":"addChannel","data":{"result":true,"channel":"`+channel+`"}}]`))
+			select {
+			case subscribed <- channel:
+			default:
+			}
+			conn.WriteMessage(websocket.TextMessage, []byte(`[{"binary":0,"channel":"`+channel+`","data":`+pushes[channel]+`}]`))
+			if first {
+				return
+			}
+		}
+	}))
+	return srv, subscribed
+}
+
+func newTestOKExSpotWs(srv *httptest.Server) *OKExSpotWs {
+	okWs := NewOKExSpotWs()
+	okWs.wsUrl = "ws" + strings.TrimPrefix(srv.URL, "http")
+	return okWs
+}
+
+func TestOKExSpotWs_SubscribeTicker(t *testing.T) {
+	srv, subscribed := newWsStandIn(t, map[string]string{
+		"ok_sub_spot_btc_usdt_ticker": `{"high":"6200","vol":"1234.5","last":"6100.5","low":"6000","buy":"6100.1","sell":"6100.9","timestamp":1530000000000}`})
+	defer srv.Close()
+
+	okWs := newTestOKExSpotWs(srv)
+	ch, err := okWs.SubscribeTicker(BTC_USDT)
+	assert.Nil(t, err)
+
+	ticker := <-ch
+	assert.Equal(t, Ticker{Last: RequireDecimal("6100.5"), Buy: RequireDecimal("6100.1"), Sell: RequireDecimal("6100.9"), High: RequireDecimal("6200"), Low: RequireDecimal("6000"), Vol: RequireDecimal("1234.5"), Date: 1530000000000}, ticker)
+	assert.Equal(t, "ok_sub_spot_btc_usdt_ticker", <-subscribed)
+
+	select {
+	case channel := <-subscribed:
+		assert.Equal(t, "ok_sub_spot_btc_usdt_ticker", channel)
+	case <-time.After(5 * time.Second):
+		t.Fatal("not resubscribed after reconnect")
+	}
+	<-ch
+
+	assert.Nil(t, okWs.Close())
+	for range ch {
+	}
+}
+
+func TestOKExSpotWs_SubscribeDepthTradesKline(t *testing.T) {
+	srv, _ := newWsStandIn(t, map[string]string{
+		"ok_sub_spot_btc_usdt_depth_5":    `{"asks":[["6101","3"],["6100.9","0.5"]],"bids":[["6100.1","1.5"],["6100.0","2"]],"timestamp":1530000000000}`,
+		"ok_sub_spot_btc_usdt_deals":      `[["12345","6100.5","0.01","23:59:59","ask"]]`,
+		"ok_sub_spot_btc_usdt_kline_1min": `[["1530000000000","1","3","0.5","2","10"]]`})
+	defer srv.Close()
+
+	okWs := newTestOKExSpotWs(srv)
+	defer okWs.Close()
+
+	depthCh, err := okWs.SubscribeDepth(BTC_USDT, 1)
+	assert.Nil(t, err)
+	depth := <-depthCh
+	assert.Equal(t, DepthRecords{{Price: RequireDecimal("6100.1"), Amount: RequireDecimal("1.5")}}, depth.BidList)
+	assert.Equal(t, DepthRecords{{Price: RequireDecimal("6100.9"), Amount: RequireDecimal("0.5")}}, depth.AskList)
+
+	tradeCh, err := okWs.SubscribeTrades(BTC_USDT)
+	assert.Nil(t, err)
+	trade := <-tradeCh
+	assert.Equal(t, int64(12345), trade.Tid)
+	assert.Equal(t, "sell", trade.Type)
+	assert.Equal(t, RequireDecimal("0.01"), trade.Amount)
+	assert.Equal(t, RequireDecimal("6100.5"), trade.Price)
+
+	klineCh, err := okWs.SubscribeKline(BTC_USDT, KLINE_PERIOD_1MIN)
+	assert.Nil(t, err)
+	assert.Equal(t, Kline{Timestamp: 1530000000, Open: 1, Close: 2, High: 3, Low: 0.5, Vol: 10}, <-klineCh)
+}
+
+func deflateMessage(msg string) []byte {
+	var buf bytes.Buffer
+	w, _ := flate.NewWriter(&buf, flate.DefaultCompression)
+	w.Write([]byte(msg))
+	w.Close()
+	return buf.Bytes()
+}
+
+//newV3WsStandIn answers "ping" and every subscribe with the deflated pushes of the n-th subscription, or
+//of the last one given. Unsubscribes are reported on the returned chan.
+func newV3WsStandIn(t *testing.T, pushes [][]string) (*httptest.Server, chan string) {
+	ops := make(chan string, 16)
+	upgrader := websocket.Upgrader{}
+	var subs int32
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		conn, err := upgrader.Upgrade(w, r, nil)
+		if err != nil {
+			t.Error(err)
+			return
+		}
+		defer conn.Close()
+		for {
+			_, msg, err := conn.ReadMessage()
+			if err != nil {
+				return
+			}
+			if string(msg) == "ping" {
+				conn.WriteMessage(websocket.BinaryMessage, deflateMessage("pong"))
+				continue
+			}
+			var req struct {
+				Op   string   `json:"op"`
+				Args []string `json:"args"`
+			}
+			json.Unmarshal(msg, &req)
+			conn.WriteMessage(websocket.BinaryMessage, deflateMessage(`{"event":"`+req.Op+`","channel":"`+req.Args[0]+`"}`))
+			select {
+			case ops <- req.Op + ":" + req.Args[0]:
+			default:
+			}
+			if req.Op != "subscribe" {
+				continue
+			}
+			n := int(atomic.AddInt32(&subs, 1))
+			if n > len(pushes) {
+				n = len(pushes)
+			}
+			for _, push := range pushes[n-1] {
+				conn.WriteMessage(websocket.BinaryMessage, deflateMessage(push))
+			}
+		}
+	}))
+	return srv, ops
+}
+
+func TestOKExSpotWs_SubscribeOrderBook(t *testing.T) {
+	partial := `{"table":"spot/depth","action":"partial","data":[{"instrument_id":"BTC-USDT",` +
+		`"asks":[["6100.9","0.5","1"],["6101.0","3","2"]],"bids":[["6100.1","1.5","1"],["6100.0","2","1"]],"timestamp":"2018-06-26T16:00:00.000Z","checksum":343203486}]}`
+	update := `{"table":"spot/depth","action":"update","data":[{"instrument_id":"BTC-USDT",` +
+		`"asks":[["6100.9","0.7","1"]],"bids":[],"timestamp":"2018-06-26T16:00:00.100Z","checksum":%d}]}`
+	//the first update carries a wrong checksum, which has to bring a new snapshot
+	srv, ops := newV3WsStandIn(t, [][]string{
+		{partial, fmt.Sprintf(update, 343203486)},
+		{partial, fmt.Sprintf(update, -1124962545)}})
+	defer srv.Close()
+
+	okWs := NewOKExSpotWs()
+	okWs.v3WsUrl = "ws" + strings.TrimPrefix(srv.URL, "http")
+	defer okWs.Close()
+
+	ob, err := okWs.SubscribeOrderBook(BTC_USDT)
+	assert.Nil(t, err)
+	assert.Equal(t, "subscribe:spot/depth:BTC-USDT", <-ops)
+	select {
+	case op := <-ops:
+		assert.Equal(t, "unsubscribe:spot/depth:BTC-USDT", op)
+	case <-time.After(5 * time.Second):
+		t.Fatal("not resubscribed after a checksum mismatch")
+	}
+	assert.Equal(t, "subscribe:spot/depth:BTC-USDT", <-ops)
+
+	deadline := time.Now().Add(5 * time.Second)
+	for !(ob.Synced() && ob.AmountAt(RequireDecimal("6100.9")) == RequireDecimal("0.7")) && time.Now().Before(deadline) {
+		time.Sleep(10 * time.Millisecond)
+	}
+	assert.True(t, ob.Synced())
+	assert.Equal(t, &Depth{
+		BidList: DepthRecords{{Price: RequireDecimal("6100.1"), Amount: RequireDecimal("1.5")}, {Price: RequireDecimal("6100"), Amount: RequireDecimal("2")}},
+		AskList: DepthRecords{{Price: RequireDecimal("6100.9"), Amount: RequireDecimal("0.7")}, {Price: RequireDecimal("6101"), Amount: RequireDecimal("3")}}}, ob.Depth(0))
+
+	same, err := okWs.SubscribeOrderBook(BTC_USDT)
+	assert.Nil(t, err)
+	assert.True(t, ob == same)
+}
+
+func TestOKExSpotWs_adaptDealTime(t *testing.T) {
+	okWs := NewOKExSpotWs()
+	now := time.Date(2018, 6, 26, 16, 0, 30, 0, time.UTC) //00:00:30 in beijing
+	assert.Equal(t, time.Date(2018, 6, 26, 16, 0, 10, 0, time.UTC).UnixNano()/1e6, okWs.adaptDealTime("00:00:10", now))
+	assert.Equal(t, time.Date(2018, 6, 26, 15, 59, 59, 0, time.UTC).UnixNano()/1e6, okWs.adaptDealTime("23:59:59", now))
+}
+
+func TestOKExSpotWs_handleError(t *testing.T) {
+	err := NewOKExSpotWs().handle([]byte(`[{"binary":0,"channel":"addChannel","data":{"result":false,"error_msg":"param not match.","error_code":10012}}]`))
+	assert.True(t, EX_ERR_INVALID_CURRENCY_PAIR.Is(err))
+}
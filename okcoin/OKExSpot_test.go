@@ -1,23 +1,125 @@
 package okcoin
 
 import (
+	"io/ioutil"
 	"net/http"
+	"net/http/httptest"
 	"testing"
 
 	"github.com/nntaoli-project/GoEx"
 	"github.com/stretchr/testify/assert"
 )
 
-var okexSpot = NewOKExSpot(http.DefaultClient, "", "")
+var okexSpot = NewOKExSpot(fixtures.Client(), "", "")
 
 func TestOKExSpot_GetTicker(t *testing.T) {
 	ticker, err := okexSpot.GetTicker(goex.ETC_BTC)
 	assert.Nil(t, err)
-	t.Log(ticker)
+	assert.Equal(t, goex.Ticker{Last: goex.RequireDecimal("0.001759"), Buy: goex.RequireDecimal("0.001758"), Sell: goex.RequireDecimal("0.00176"),
+		High: goex.RequireDecimal("0.001778"), Low: goex.RequireDecimal("0.001725"), Vol: goex.RequireDecimal("61423.1948"), Date: 1529920350}, *ticker)
 }
 
 func TestOKExSpot_GetDepth(t *testing.T) {
 	dep, err := okexSpot.GetDepth(2, goex.ETC_BTC)
 	assert.Nil(t, err)
-	t.Log(dep)
+	assert.Equal(t, goex.DepthRecords{
+		{Price: goex.RequireDecimal("0.00176"), Amount: goex.RequireDecimal("12.31")},
+		{Price: goex.RequireDecimal("0.001762"), Amount: goex.RequireDecimal("40.5")}}, dep.AskList)
+	assert.Equal(t, goex.DepthRecords{
+		{Price: goex.RequireDecimal("0.001758"), Amount: goex.RequireDecimal("3.2")},
+		{Price: goex.RequireDecimal("0.001755"), Amount: goex.RequireDecimal("120")}}, dep.BidList)
+}
+
+func TestOKExSpot_GetMyTrades(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "/api/spot/v3/fills", r.URL.Path)
+		assert.Equal(t, "ETC-BTC", r.URL.Query().Get("instrument_id"))
+		assert.Equal(t, "pass", r.Header.Get("OK-ACCESS-PASSPHRASE"))
+		assert.NotEmpty(t, r.Header.Get("OK-ACCESS-SIGN"))
+		//two ledger entries per fill, the newest first
+		w.Write([]byte(`[` +
+			`{"ledger_id":"1004","trade_id":"12","order_id":"2482659399697408","currency":"ETC","side":"sell","price":"0.0021",` +
+			`"size":"3","fee":"0","exec_type":"M","timestamp":"2019-03-15T02:53:00.000Z"},` +
+			`{"ledger_id":"1003","trade_id":"12","order_id":"2482659399697408","currency":"BTC","side":"buy","price":"0.0021",` +
+			`"size":"0.0063","fee":"-0.0000063","exec_type":"M","timestamp":"2019-03-15T02:53:00.000Z"},` +
+			`{"ledger_id":"1002","trade_id":"11","order_id":"2482659399697407","currency":"ETC","side":"buy","price":"0.002",` +
+			`"size":"2","fee":"-0.002","exec_type":"T","timestamp":"2019-03-15T02:52:56.000Z"},` +
+			`{"ledger_id":"1001","trade_id":"11","order_id":"2482659399697407","currency":"BTC","side":"sell","price":"0.002",` +
+			`"size":"0.004","fee":"0","exec_type":"T","timestamp":"2019-03-15T02:52:56.000Z"}]`))
+	}))
+	defer srv.Close()
+	api := NewOKExSpotV3(http.DefaultClient, "key", "secret", "pass")
+	api.v3BaseUrl = srv.URL
+	assert.True(t, api.Capabilities().MyTrades)
+
+	trades, err := api.GetMyTrades(goex.ETC_BTC, 1552618376000, 0)
+	assert.Nil(t, err)
+	assert.Equal(t, []goex.MyTrade{
+		{TradeID: "11", OrderID: "2482659399697407", Currency: goex.ETC_BTC, Side: goex.TradeSide(goex.BUY),
+			Price: goex.RequireDecimal("0.002"), Amount: goex.RequireDecimal("2"), Fee: goex.RequireDecimal("0.002"),
+			FeeCurrency: goex.NewCurrency("ETC", ""), Time: 1552618376000},
+		{TradeID: "12", OrderID: "2482659399697408", Currency: goex.ETC_BTC, Side: goex.TradeSide(goex.SELL),
+			Price: goex.RequireDecimal("0.0021"), Amount: goex.RequireDecimal("3"), Fee: goex.RequireDecimal("0.0000063"),
+			FeeCurrency: goex.NewCurrency("BTC", ""), IsMaker: true, Time: 1552618380000},
+	}, trades)
+
+	trades, err = api.GetMyTrades(goex.ETC_BTC, 1552618377000, 0)
+	assert.Nil(t, err)
+	assert.Len(t, trades, 1)
+
+	//without the passphrase there is no v3 api
+	_, err = okexSpot.GetMyTrades(goex.ETC_BTC, 0, 0)
+	assert.True(t, goex.EX_ERR_NOT_FIND_APIKEY.Is(err))
+	assert.False(t, okexSpot.Capabilities().MyTrades)
+}
+
+func TestOKExSpot_ClientOrderID(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := ioutil.ReadAll(r.Body)
+		switch r.Method + " " + r.URL.Path {
+		case "POST /api/spot/v3/orders":
+			assert.JSONEq(t, `{"client_oid":"myOrder1","instrument_id":"ETC-BTC","type":"limit","side":"sell","size":"1","price":"0.002"}`, string(body))
+			w.Write([]byte(`{"client_oid":"myOrder1","order_id":"2510789768709120","result":true,"error_code":"","error_message":""}`))
+		case "GET /api/spot/v3/orders/myOrder1":
+			assert.Equal(t, "ETC-BTC", r.URL.Query().Get("instrument_id"))
+			w.Write([]byte(`{"order_id":"2510789768709120","client_oid":"myOrder1","price":"0.002","size":"1","instrument_id":"ETC-BTC",` +
+				`"side":"sell","type":"limit","timestamp":"2019-03-18T07:26:50.000Z","filled_size":"0.4","filled_notional":"0.0008",` +
+				`"price_avg":"0.002","state":"1"}`))
+		case "GET /api/spot/v3/orders/myOrder2":
+			w.WriteHeader(http.StatusBadRequest)
+			w.Write([]byte(`{"code":33014,"message":"Order does not exist"}`))
+		case "POST /api/spot/v3/cancel_orders/myOrder1":
+			assert.JSONEq(t, `{"instrument_id":"ETC-BTC"}`, string(body))
+			w.Write([]byte(`{"client_oid":"myOrder1","order_id":"2510789768709120","result":true,"error_code":"","error_message":""}`))
+		default:
+			t.Error("unexpected", r.Method, r.URL.Path)
+		}
+	}))
+	defer srv.Close()
+	api := NewOKExSpotV3(http.DefaultClient, "key", "secret", "pass")
+	api.v3BaseUrl = srv.URL
+	assert.True(t, api.Capabilities().ClientOrder)
+
+	order, err := api.PlaceOrderWithClientID("myOrder1", goex.SELL, goex.RequireDecimal("1"), goex.RequireDecimal("0.002"), goex.ETC_BTC)
+	assert.Nil(t, err)
+	assert.Equal(t, "2510789768709120", order.OrderID2)
+	assert.Equal(t, "myOrder1", order.ClientOrderID)
+
+	order, err = api.GetOneOrder("myOrder1", goex.ETC_BTC)
+	assert.Nil(t, err)
+	assert.Equal(t, goex.Order{OrderID: 2510789768709120, OrderID2: "2510789768709120", ClientOrderID: "myOrder1", Currency: goex.ETC_BTC,
+		Amount: goex.RequireDecimal("1"), Price: goex.RequireDecimal("0.002"), DealAmount: goex.RequireDecimal("0.4"),
+		AvgPrice: goex.RequireDecimal("0.002"), OrderTime: 1552894010000, Side: goex.TradeSide(goex.SELL),
+		Status: goex.ORDER_PART_FINISH}, *order)
+
+	_, err = api.GetOneOrder("myOrder2", goex.ETC_BTC)
+	assert.True(t, goex.EX_ERR_NOT_FIND_ORDER.Is(err))
+
+	ok, err := api.CancelOrder("myOrder1", goex.ETC_BTC)
+	assert.Nil(t, err)
+	assert.True(t, ok)
+
+	//okex takes letters and digits only
+	_, err = api.PlaceOrderWithClientID("my_order", goex.SELL, goex.RequireDecimal("1"), goex.RequireDecimal("0.002"), goex.ETC_BTC)
+	assert.True(t, goex.EX_ERR_INVALID_CLIENT_ORDER_ID.Is(err))
 }
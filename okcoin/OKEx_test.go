@@ -1,7 +1,6 @@
 package okcoin
 
 import (
-	"net/http"
 	"testing"
 
 	. "github.com/nntaoli-project/GoEx"
@@ -9,11 +8,28 @@ import (
 )
 
 var (
-	okex = NewOKEx(http.DefaultClient, "", "")
+	okex = NewOKEx(fixtures.Client(), "", "")
 )
 
 func TestOKEx_GetFutureDepth(t *testing.T) {
 	dep, err := okex.GetFutureDepth(BTC_USD, QUARTER_CONTRACT, 1)
 	assert.Nil(t, err)
-	t.Log(dep)
+	assert.Equal(t, DepthRecords{{Price: RequireDecimal("6320.83"), Amount: RequireDecimal("15")}}, dep.AskList)
+	assert.Equal(t, DepthRecords{{Price: RequireDecimal("6318.02"), Amount: RequireDecimal("41")}}, dep.BidList)
+}
+
+func TestOKEx_errorWrapper(t *testing.T) {
+	for code, errCode := range map[int]ApiError{
+		20024: EX_ERR_SIGN,
+		20020: EX_ERR_NOT_FIND_SECRETKEY,
+		20015: EX_ERR_NOT_FIND_ORDER,
+		20049: EX_ERR_API_LIMIT,
+		20016: EX_ERR_INSUFFICIENT_BALANCE,
+		20028: EX_ERR_INVALID_CURRENCY_PAIR,
+		20001: API_ERR,
+	} {
+		err := okex.errorWrapper(code, "origin")
+		assert.True(t, errCode.Is(err), "%d", code)
+		assert.Equal(t, "origin", err.OriginErrMsg)
+	}
 }
@@ -31,6 +31,7 @@ type Poloniex struct {
 }
 
 func New(client *http.Client, accessKey, secretKey string) *Poloniex {
+	client = ExchangeRateLimitedClient(client, EXCHANGE_NAME, accessKey, DefaultRateLimitConfig)
 	return &Poloniex{accessKey, secretKey, client}
 }
 
@@ -38,7 +39,12 @@ func (poloniex *Poloniex) GetExchangeName() string {
 	return EXCHANGE_NAME
 }
 
+func (poloniex *Poloniex) Capabilities() Capabilities {
+	return Capabilities{Kline: true, Trades: true, Withdraw: true, Deposit: true, Margin: true, TradeFee: true, MyTrades: true}
+}
+
 func (poloniex *Poloniex) GetTicker(currency CurrencyPair) (*Ticker, error) {
+	var dp DecimalParser
 	//log.Println(poloniex.adaptCurrencyPair(currency).ToSymbol2("_"))
 	respmap, err := HttpGet(poloniex.client, PUBLIC_URL+TICKER_API)
 	if err != nil {
@@ -54,18 +60,22 @@ func (poloniex *Poloniex) GetTicker(currency CurrencyPair) (*Ticker, error) {
 	}
 
 	ticker := new(Ticker)
-	ticker.High, _ = strconv.ParseFloat(tickermap["high24hr"].(string), 64)
-	ticker.Low, _ = strconv.ParseFloat(tickermap["low24hr"].(string), 64)
-	ticker.Last, _ = strconv.ParseFloat(tickermap["last"].(string), 64)
-	ticker.Buy, _ = strconv.ParseFloat(tickermap["highestBid"].(string), 64)
-	ticker.Sell, _ = strconv.ParseFloat(tickermap["lowestAsk"].(string), 64)
-	ticker.Vol, _ = strconv.ParseFloat(tickermap["quoteVolume"].(string), 64)
+	ticker.High = dp.Decimal(tickermap["high24hr"])
+	ticker.Low = dp.Decimal(tickermap["low24hr"])
+	ticker.Last = dp.Decimal(tickermap["last"])
+	ticker.Buy = dp.Decimal(tickermap["highestBid"])
+	ticker.Sell = dp.Decimal(tickermap["lowestAsk"])
+	ticker.Vol = dp.Decimal(tickermap["quoteVolume"])
 
 	//log.Println(tickermap)
 
+	if dp.Err != nil {
+		return nil, dp.Err
+	}
 	return ticker, nil
 }
 func (poloniex *Poloniex) GetDepth(size int, currency CurrencyPair) (*Depth, error) {
+	var dp DecimalParser
 	respmap, err := HttpGet(poloniex.client, PUBLIC_URL+
 		fmt.Sprintf(ORDER_BOOK_API, poloniex.adaptCurrencyPair(currency).ToSymbol2("_"), size))
 
@@ -92,9 +102,9 @@ func (poloniex *Poloniex) GetDepth(size int, currency CurrencyPair) (*Depth, err
 		for i, vv := range v.([]interface{}) {
 			switch i {
 			case 0:
-				dr.Price, _ = strconv.ParseFloat(vv.(string), 64)
+				dr.Price = dp.Price(vv)
 			case 1:
-				dr.Amount = vv.(float64)
+				dr.Amount = dp.Decimal(vv)
 			}
 		}
 		depth.AskList = append(depth.AskList, dr)
@@ -105,21 +115,84 @@ func (poloniex *Poloniex) GetDepth(size int, currency CurrencyPair) (*Depth, err
 		for i, vv := range v.([]interface{}) {
 			switch i {
 			case 0:
-				dr.Price, _ = strconv.ParseFloat(vv.(string), 64)
+				dr.Price = dp.Price(vv)
 			case 1:
-				dr.Amount = vv.(float64)
+				dr.Amount = dp.Decimal(vv)
 			}
 		}
 		depth.BidList = append(depth.BidList, dr)
 	}
 
+	if dp.Err != nil {
+		return nil, dp.Err
+	}
 	return &depth, nil
 }
-func (Poloniex *Poloniex) GetKlineRecords(currency CurrencyPair, period, size, since int) ([]Kline, error) {
-	return nil, nil
+//klinePeriods are the candle lengths of returnChartData in seconds, it has no 1 minute or 1 hour ones
+var klinePeriods = map[int]int{
+	KLINE_PERIOD_5MIN:  300,
+	KLINE_PERIOD_15MIN: 900,
+	KLINE_PERIOD_30MIN: 1800,
+	KLINE_PERIOD_4H:    14400,
+	KLINE_PERIOD_1DAY:  86400,
+}
+
+func (poloniex *Poloniex) GetKlineRecords(currency CurrencyPair, period, size, since int) ([]Kline, error) {
+	seconds, ok := klinePeriods[period]
+	if !ok {
+		return nil, UnsupportedKlinePeriod(period)
+	}
+	start := int64(since / 1000)
+	end := time.Now().Unix()
+	if since > 0 {
+		end = start + int64(seconds*size)
+	} else {
+		start = end - int64(seconds*size)
+	}
+	params := url.Values{}
+	params.Set("command", "returnChartData")
+	params.Set("currencyPair", poloniex.adaptCurrencyPair(currency).ToSymbol2("_"))
+	params.Set("period", strconv.Itoa(seconds))
+	params.Set("start", strconv.FormatInt(start, 10))
+	params.Set("end", strconv.FormatInt(end, 10))
+
+	resp, err := NewHttpRequest(poloniex.client, "GET", PUBLIC_URL+"?"+params.Encode(), "", nil)
+	if err != nil {
+		return nil, poloniex.adaptError(err)
+	}
+	var errResp struct {
+		Error string `json:"error"`
+	}
+	if json.Unmarshal(resp, &errResp) == nil && errResp.Error != "" {
+		return nil, poloniex.errorWrapper(errResp.Error)
+	}
+	var records []struct {
+		Date        int64   `json:"date"`
+		High        float64 `json:"high"`
+		Low         float64 `json:"low"`
+		Open        float64 `json:"open"`
+		Close       float64 `json:"close"`
+		QuoteVolume float64 `json:"quoteVolume"`
+	}
+	err = json.Unmarshal(resp, &records)
+	if err != nil {
+		return nil, err
+	}
+
+	klines := make([]Kline, 0, len(records))
+	for _, r := range records {
+		//a pair without trades in the range answers one candle dated 0
+		if r.Date == 0 {
+			continue
+		}
+		//volume is counted in the currency quoted, quoteVolume in the one traded
+		klines = append(klines, Kline{Timestamp: r.Date, Open: r.Open, High: r.High, Low: r.Low, Close: r.Close, Vol: r.QuoteVolume})
+	}
+	return KlinesSince(klines, int64(since), size), nil
 }
 
 func (poloniex *Poloniex) placeLimitOrder(command, amount, price string, currency CurrencyPair) (*Order, error) {
+	var dp DecimalParser
 	postData := url.Values{}
 	postData.Set("command", command)
 	postData.Set("currencyPair", poloniex.adaptCurrencyPair(currency).ToSymbol2("_"))
@@ -135,22 +208,26 @@ func (poloniex *Poloniex) placeLimitOrder(command, amount, price string, currenc
 	resp, err := HttpPostForm2(poloniex.client, TRADE_API, postData, headers)
 	if err != nil {
 		log.Println(err)
-		return nil, err
+		return nil, poloniex.adaptError(err)
 	}
 
 	respmap := make(map[string]interface{})
 	err = json.Unmarshal(resp, &respmap)
-	if err != nil || respmap["error"] != nil {
+	if err != nil {
 		log.Println(err, string(resp))
 		return nil, err
 	}
+	if respmap["error"] != nil {
+		log.Println(string(resp))
+		return nil, poloniex.errorWrapper(respmap["error"].(string))
+	}
 
 	orderNumber := respmap["orderNumber"].(string)
 	order := new(Order)
 	order.OrderTime = int(time.Now().Unix() * 1000)
 	order.OrderID, _ = strconv.Atoi(orderNumber)
-	order.Amount, _ = strconv.ParseFloat(amount, 64)
-	order.Price, _ = strconv.ParseFloat(price, 64)
+	order.Amount = dp.Decimal(amount)
+	order.Price = dp.Decimal(price)
 	order.Status = ORDER_UNFINISH
 	order.Currency = currency
 
@@ -162,15 +239,18 @@ func (poloniex *Poloniex) placeLimitOrder(command, amount, price string, currenc
 	}
 
 	//log.Println(string(resp))
+	if dp.Err != nil {
+		return nil, dp.Err
+	}
 	return order, nil
 }
 
-func (poloniex *Poloniex) LimitBuy(amount, price string, currency CurrencyPair) (*Order, error) {
-	return poloniex.placeLimitOrder("buy", amount, price, currency)
+func (poloniex *Poloniex) LimitBuy(amount, price Decimal, currency CurrencyPair) (*Order, error) {
+	return poloniex.placeLimitOrder("buy", amount.String(), price.String(), currency)
 }
 
-func (poloniex *Poloniex) LimitSell(amount, price string, currency CurrencyPair) (*Order, error) {
-	return poloniex.placeLimitOrder("sell", amount, price, currency)
+func (poloniex *Poloniex) LimitSell(amount, price Decimal, currency CurrencyPair) (*Order, error) {
+	return poloniex.placeLimitOrder("sell", amount.String(), price.String(), currency)
 }
 
 func (poloniex *Poloniex) CancelOrder(orderId string, currency CurrencyPair) (bool, error) {
@@ -190,17 +270,19 @@ func (poloniex *Poloniex) CancelOrder(orderId string, currency CurrencyPair) (bo
 	resp, err := HttpPostForm2(poloniex.client, TRADE_API, postData, headers)
 	if err != nil {
 		log.Println(err)
-		return false, err
+		return false, poloniex.adaptError(err)
 	}
 
 	//log.Println(string(resp));
 
 	respmap := make(map[string]interface{})
 	err = json.Unmarshal(resp, &respmap)
-	if err != nil || respmap["error"] != nil {
-		//log.Println(err, string(resp))
+	if err != nil {
 		return false, errors.New(string(resp))
 	}
+	if respmap["error"] != nil {
+		return false, poloniex.errorWrapper(respmap["error"].(string))
+	}
 
 	success := int(respmap["success"].(float64))
 	if success != 1 {
@@ -212,6 +294,7 @@ func (poloniex *Poloniex) CancelOrder(orderId string, currency CurrencyPair) (bo
 }
 
 func (poloniex *Poloniex) GetOneOrder(orderId string, currency CurrencyPair) (*Order, error) {
+	var dp DecimalParser
 	postData := url.Values{}
 	postData.Set("command", "returnOrderTrades")
 	postData.Set("orderNumber", orderId)
@@ -225,7 +308,7 @@ func (poloniex *Poloniex) GetOneOrder(orderId string, currency CurrencyPair) (*O
 	resp, err := HttpPostForm2(poloniex.client, TRADE_API, postData, headers)
 	if err != nil {
 		log.Println(err)
-		return nil, err
+		return nil, poloniex.adaptError(err)
 	}
 	//println(string(resp))
 	if strings.Contains(string(resp), "error") {
@@ -242,6 +325,12 @@ func (poloniex *Poloniex) GetOneOrder(orderId string, currency CurrencyPair) (*O
 			}
 		}
 		//log.Println(string(resp))
+		var errResp struct {
+			Error string `json:"error"`
+		}
+		if json.Unmarshal(resp, &errResp) == nil && errResp.Error != "" {
+			return nil, poloniex.errorWrapper(errResp.Error)
+		}
 		return nil, errors.New(string(resp))
 	}
 
@@ -256,16 +345,16 @@ func (poloniex *Poloniex) GetOneOrder(orderId string, currency CurrencyPair) (*O
 	order.OrderID, _ = strconv.Atoi(orderId)
 	order.Currency = currency
 
-	total := 0.0
+	total := Decimal{}
 
 	for _, v := range respmap {
 		vv := v.(map[string]interface{})
-		_amount, _ := strconv.ParseFloat(vv["amount"].(string), 64)
-		_rate, _ := strconv.ParseFloat(vv["rate"].(string), 64)
-		_fee, _ := strconv.ParseFloat(vv["fee"].(string), 64)
+		_amount := dp.Decimal(vv["amount"])
+		_rate := dp.Decimal(vv["rate"])
+		_fee := dp.Decimal(vv["fee"])
 
-		order.DealAmount += _amount
-		total += (_amount * _rate)
+		order.DealAmount = order.DealAmount.Add(_amount)
+		total = total.Add(_amount.Mul(_rate))
 		order.Fee = _fee
 
 		if strings.Compare("sell", vv["type"].(string)) == 0 {
@@ -275,12 +364,18 @@ func (poloniex *Poloniex) GetOneOrder(orderId string, currency CurrencyPair) (*O
 		}
 	}
 
-	order.AvgPrice = total / order.DealAmount
+	if order.DealAmount.Sign() > 0 {
+		order.AvgPrice = total.Div(order.DealAmount)
+	}
 
+	if dp.Err != nil {
+		return nil, dp.Err
+	}
 	return order, nil
 }
 
 func (poloniex *Poloniex) GetUnfinishOrders(currency CurrencyPair) ([]Order, error) {
+	var dp DecimalParser
 	postData := url.Values{}
 	postData.Set("command", "returnOpenOrders")
 	postData.Set("currencyPair", poloniex.adaptCurrencyPair(currency).ToSymbol2("_"))
@@ -297,7 +392,7 @@ func (poloniex *Poloniex) GetUnfinishOrders(currency CurrencyPair) ([]Order, err
 	resp, err := HttpPostForm2(poloniex.client, TRADE_API, postData, headers)
 	if err != nil {
 		log.Println(err)
-		return nil, err
+		return nil, poloniex.adaptError(err)
 	}
 
 	orderAr := make([]interface{}, 1)
@@ -313,8 +408,8 @@ func (poloniex *Poloniex) GetUnfinishOrders(currency CurrencyPair) ([]Order, err
 		order := Order{}
 		order.Currency = currency
 		order.OrderID, _ = strconv.Atoi(vv["orderNumber"].(string))
-		order.Amount, _ = strconv.ParseFloat(vv["amount"].(string), 64)
-		order.Price, _ = strconv.ParseFloat(vv["rate"].(string), 64)
+		order.Amount = dp.Decimal(vv["amount"])
+		order.Price = dp.Decimal(vv["rate"])
 		order.Status = ORDER_UNFINISH
 
 		side := vv["type"].(string)
@@ -329,13 +424,17 @@ func (poloniex *Poloniex) GetUnfinishOrders(currency CurrencyPair) ([]Order, err
 	}
 
 	//log.Println(orders)
+	if dp.Err != nil {
+		return nil, dp.Err
+	}
 	return orders, nil
 }
 func (Poloniex *Poloniex) GetOrderHistorys(currency CurrencyPair, currentPage, pageSize int) ([]Order, error) {
-	return nil, nil
+	return nil, ErrNotSupported
 }
 
 func (poloniex *Poloniex) GetAccount() (*Account, error) {
+	var dp DecimalParser
 	postData := url.Values{}
 	postData.Add("command", "returnCompleteBalances")
 	sign, err := poloniex.buildPostForm(&postData)
@@ -350,16 +449,19 @@ func (poloniex *Poloniex) GetAccount() (*Account, error) {
 
 	if err != nil {
 		log.Println(err)
-		return nil, err
+		return nil, poloniex.adaptError(err)
 	}
 
 	respmap := make(map[string]interface{})
 	err = json.Unmarshal(resp, &respmap)
 
-	if err != nil || respmap["error"] != nil {
+	if err != nil {
 		log.Println(err)
 		return nil, err
 	}
+	if respmap["error"] != nil {
+		return nil, poloniex.errorWrapper(respmap["error"].(string))
+	}
 
 	acc := new(Account)
 	acc.Exchange = EXCHANGE_NAME
@@ -370,27 +472,36 @@ func (poloniex *Poloniex) GetAccount() (*Account, error) {
 		vv := v.(map[string]interface{})
 		subAcc := SubAccount{}
 		subAcc.Currency = currency
-		subAcc.Amount, _ = strconv.ParseFloat(vv["available"].(string), 64)
-		subAcc.ForzenAmount, _ = strconv.ParseFloat(vv["onOrders"].(string), 64)
+		subAcc.Amount = dp.Decimal(vv["available"])
+		subAcc.FrozenAmount = dp.Decimal(vv["onOrders"])
 		acc.SubAccounts[subAcc.Currency] = subAcc
 	}
 
+	if dp.Err != nil {
+		return nil, dp.Err
+	}
 	return acc, nil
 }
 
-func (p *Poloniex) Withdraw(amount string, currency Currency, fees, receiveAddr, safePwd string) (string, error) {
+//Withdraw sends a withdrawal. Poloniex does not answer with its id, it is looked up in the withdrawals
+//of the last minutes by currency, address and amount and stays empty if none matches.
+func (p *Poloniex) Withdraw(req WithdrawRequest) (*Withdraw, error) {
+	currency := req.Currency
 	if currency == BCC {
 		currency = BCH
 	}
 	params := url.Values{}
 	params.Add("command", "withdraw")
-	params.Add("address", receiveAddr)
-	params.Add("amount", amount)
+	params.Add("address", req.Address)
+	params.Add("amount", req.Amount.String())
 	params.Add("currency", strings.ToUpper(currency.String()))
+	if req.Tag != "" {
+		params.Add("paymentId", req.Tag)
+	}
 
 	sign, err := p.buildPostForm(&params)
 	if err != nil {
-		return "", err
+		return nil, err
 	}
 
 	headers := map[string]string{
@@ -401,52 +512,122 @@ func (p *Poloniex) Withdraw(amount string, currency Currency, fees, receiveAddr,
 
 	if err != nil {
 		log.Println(err)
-		return "", err
+		return nil, p.adaptError(err)
 	}
-	println(string(resp))
 
 	respMap := make(map[string]interface{})
 
 	err = json.Unmarshal(resp, &respMap)
 	if err != nil {
 		log.Println(err)
-		return "", err
+		return nil, err
+	}
+
+	if respMap["error"] != nil {
+		return nil, p.errorWrapper(respMap["error"].(string))
+	}
+
+	withdraw := &Withdraw{
+		Currency: req.Currency,
+		Amount:   req.Amount,
+		Address:  req.Address,
+		Tag:      req.Tag,
+		Status:   WITHDRAW_PENDING,
+		Time:     time.Now().Unix()}
+	records, err := p.GetDepositsWithdrawals(strconv.FormatInt(withdraw.Time-600, 10), "")
+	if err != nil {
+		return withdraw, nil
+	}
+	for i := len(records.Withdrawals) - 1; i >= 0; i-- {
+		w := p.adaptWithdraw(records.Withdrawals[i])
+		if w.Currency.Symbol == currency.Symbol && w.Address == req.Address && w.Amount.Equal(req.Amount) {
+			w.Currency = req.Currency
+			return &w, nil
+		}
 	}
+	return withdraw, nil
+}
 
-	if respMap["error"] == nil {
-		return string(resp), nil
+func (p *Poloniex) CancelWithdraw(id string, currency Currency, tradePassword string) (bool, error) {
+	return false, ErrNotSupported
+}
+
+//GetWithdraw finds the withdrawal in the last 30 days
+func (p *Poloniex) GetWithdraw(id string, currency Currency) (*Withdraw, error) {
+	records, err := p.GetDepositsWithdrawals(strconv.FormatInt(time.Now().AddDate(0, 0, -30).Unix(), 10), "")
+	if err != nil {
+		return nil, err
+	}
+	for _, record := range records.Withdrawals {
+		if strconv.FormatInt(record.WithdrawalNumber, 10) == id {
+			w := p.adaptWithdraw(record)
+			return &w, nil
+		}
 	}
+	errCode := EX_ERR_NOT_FIND_WITHDRAW
+	errCode.OriginErrMsg = id
+	return nil, errCode
+}
 
-	return "", errors.New(string(resp))
+func (p *Poloniex) adaptWithdraw(record PoloniexWithdrawal) Withdraw {
+	w := Withdraw{
+		ID:       strconv.FormatInt(record.WithdrawalNumber, 10),
+		Currency: NewCurrency(record.Currency, ""),
+		Amount:   NewDecimalFromFloat(record.Amount),
+		Address:  record.Address,
+		TxID:     record.TransactionID,
+		Time:     record.Timestamp}
+
+	//the status is "COMPLETE: <txid>" once sent
+	status := strings.ToUpper(record.Status)
+	switch {
+	case strings.HasPrefix(status, "COMPLETE"):
+		w.Status = WITHDRAW_COMPLETED
+		if w.TxID == "" {
+			w.TxID = strings.TrimSpace(strings.TrimPrefix(record.Status[len("COMPLETE"):], ":"))
+		}
+	case strings.Contains(status, "CANCEL"):
+		w.Status = WITHDRAW_CANCELED
+	case strings.Contains(status, "FAIL"), strings.Contains(status, "REJECT"):
+		w.Status = WITHDRAW_FAILED
+	case strings.Contains(status, "PROCESS"):
+		w.Status = WITHDRAW_PROCESSING
+	default:
+		w.Status = WITHDRAW_PENDING
+	}
+	return w
 }
 
 type PoloniexDepositsWithdrawals struct {
-	Deposits []struct {
-		Currency      string    `json:"currency"`
-		Address       string    `json:"address"`
-		Amount        float64   `json:"amount,string"`
-		Confirmations int       `json:"confirmations"`
-		TransactionID string    `json:"txid"`
-		Timestamp     time.Time `json:"timestamp"`
-		Status        string    `json:"status"`
-	} `json:"deposits"`
-	Withdrawals []struct {
-		WithdrawalNumber int64     `json:"withdrawalNumber"`
-		Currency         string    `json:"currency"`
-		Address          string    `json:"address"`
-		Amount           float64   `json:"amount,string"`
-		Confirmations    int       `json:"confirmations"`
-		TransactionID    string    `json:"txid"`
-		Timestamp        time.Time `json:"timestamp"`
-		Status           string    `json:"status"`
-		IPAddress        string    `json:"ipAddress"`
-	} `json:"withdrawals"`
+	Deposits    []PoloniexDeposit    `json:"deposits"`
+	Withdrawals []PoloniexWithdrawal `json:"withdrawals"`
+}
+
+type PoloniexDeposit struct {
+	Currency      string  `json:"currency"`
+	Address       string  `json:"address"`
+	Amount        float64 `json:"amount,string"`
+	Confirmations int     `json:"confirmations"`
+	TransactionID string  `json:"txid"`
+	Timestamp     int64   `json:"timestamp"`
+	Status        string  `json:"status"`
+}
+
+type PoloniexWithdrawal struct {
+	WithdrawalNumber int64   `json:"withdrawalNumber"`
+	Currency         string  `json:"currency"`
+	Address          string  `json:"address"`
+	Amount           float64 `json:"amount,string"`
+	Confirmations    int     `json:"confirmations"`
+	TransactionID    string  `json:"txid"`
+	Timestamp        int64   `json:"timestamp"`
+	Status           string  `json:"status"`
+	IPAddress        string  `json:"ipAddress"`
 }
 
 func (poloniex *Poloniex) GetDepositsWithdrawals(start, end string) (*PoloniexDepositsWithdrawals, error) {
 	params := url.Values{}
 	params.Set("command", "returnDepositsWithdrawals")
-	println(start)
 	if start != "" {
 		params.Set("start", start)
 	} else {
@@ -471,17 +652,145 @@ func (poloniex *Poloniex) GetDepositsWithdrawals(start, end string) (*PoloniexDe
 	resp, err := HttpPostForm2(poloniex.client, TRADE_API, params, headers)
 	if err != nil {
 		log.Println(err)
-		return nil, err
+		return nil, poloniex.adaptError(err)
 	}
 
-	println(string(resp))
-
 	records := new(PoloniexDepositsWithdrawals)
 	err = json.Unmarshal(resp, records)
 
 	return records, err
 }
 
+//GetDepositAddress returns the deposit address, generating one the first time. For currencies deposited
+//to one shared address, as XMR, the address is the shared one and Tag the payment id.
+func (poloniex *Poloniex) GetDepositAddress(currency Currency) (*CryptoAddress, error) {
+	symbol := strings.ToUpper(currency.String())
+	var addresses map[string]string
+	err := poloniex.tradingApi(url.Values{"command": {"returnDepositAddresses"}}, &addresses)
+	if err != nil {
+		return nil, err
+	}
+	address, ok := addresses[symbol]
+	if !ok {
+		var res struct {
+			Success  int    `json:"success"`
+			Response string `json:"response"`
+		}
+		err = poloniex.tradingApi(url.Values{"command": {"generateNewAddress"}, "currency": {symbol}}, &res)
+		if err != nil {
+			return nil, err
+		}
+		if res.Success != 1 {
+			return nil, poloniex.errorWrapper(res.Response)
+		}
+		address = res.Response
+	}
+
+	addr := &CryptoAddress{Currency: currency, Address: address, ExchangeName: EXCHANGE_NAME}
+	respmap, err := HttpGet(poloniex.client, PUBLIC_URL+"?command=returnCurrencies")
+	if err != nil {
+		return nil, err
+	}
+	if info, ok := respmap[symbol].(map[string]interface{}); ok {
+		if shared, ok := info["depositAddress"].(string); ok && shared != "" {
+			addr.Address, addr.Tag = shared, address
+		}
+	}
+	return addr, nil
+}
+
+//GetTradeFee returns the account's rates of returnFeeInfo, the same on every pair
+func (poloniex *Poloniex) GetTradeFee(pair CurrencyPair) (*TradeFee, error) {
+	var dp DecimalParser
+	var res struct {
+		MakerFee string `json:"makerFee"`
+		TakerFee string `json:"takerFee"`
+	}
+	err := poloniex.tradingApi(url.Values{"command": {"returnFeeInfo"}}, &res)
+	if err != nil {
+		return nil, err
+	}
+	fee := &TradeFee{Maker: dp.Decimal(res.MakerFee), Taker: dp.Decimal(res.TakerFee)}
+	if dp.Err != nil {
+		return nil, dp.Err
+	}
+	return fee, nil
+}
+
+//GetWithdrawFee returns the txFee of returnCurrencies, poloniex takes it out of the amount withdrawn
+func (poloniex *Poloniex) GetWithdrawFee(currency Currency) (Decimal, error) {
+	symbol := strings.ToUpper(currency.String())
+	if currency == BCC {
+		symbol = BCH.Symbol
+	}
+	respmap, err := HttpGet(poloniex.client, PUBLIC_URL+"?command=returnCurrencies")
+	if err != nil {
+		return Decimal{}, err
+	}
+	info, ok := respmap[symbol].(map[string]interface{})
+	if !ok {
+		errCode := EX_ERR_UNKNOWN_FEE
+		errCode.OriginErrMsg = EXCHANGE_NAME + " " + symbol
+		return Decimal{}, errCode
+	}
+	return ToDecimalE(info["txFee"])
+}
+
+//GetDeposits pages the whole deposit history of the currency
+func (poloniex *Poloniex) GetDeposits(currency Currency, currentPage, pageSize int) ([]Deposit, error) {
+	var dp DecimalParser
+	records, err := poloniex.GetDepositsWithdrawals("", "")
+	if err != nil {
+		return nil, err
+	}
+	var deposits []Deposit
+	for i := len(records.Deposits) - 1; i >= 0; i-- {
+		r := records.Deposits[i]
+		if !strings.EqualFold(r.Currency, currency.Symbol) {
+			continue
+		}
+		d := Deposit{
+			ID:            r.TransactionID,
+			Currency:      currency,
+			Amount:        dp.Decimal(r.Amount),
+			Address:       r.Address,
+			TxID:          r.TransactionID,
+			Confirmations: r.Confirmations,
+			Time:          r.Timestamp}
+		if strings.HasPrefix(strings.ToUpper(r.Status), "COMPLETE") {
+			d.Status = DEPOSIT_COMPLETED
+		} else {
+			d.Status = DEPOSIT_PENDING
+		}
+		deposits = append(deposits, d)
+	}
+	if dp.Err != nil {
+		return nil, dp.Err
+	}
+	return DepositsPage(deposits, currentPage, pageSize), nil
+}
+
+//tradingApi posts the signed command and decodes the answer into ret
+func (poloniex *Poloniex) tradingApi(params url.Values, ret interface{}) error {
+	sign, err := poloniex.buildPostForm(&params)
+	if err != nil {
+		return err
+	}
+	resp, err := HttpPostForm2(poloniex.client, TRADE_API, params, map[string]string{
+		"Key":  poloniex.accessKey,
+		"Sign": sign})
+	if err != nil {
+		return poloniex.adaptError(err)
+	}
+	var errResp struct {
+		Error string `json:"error"`
+	}
+	if json.Unmarshal(resp, &errResp) == nil && errResp.Error != "" {
+		return poloniex.errorWrapper(errResp.Error)
+	}
+	return json.Unmarshal(resp, ret)
+}
+
 func (poloniex *Poloniex) buildPostForm(postForm *url.Values) (string, error) {
 	postForm.Add("nonce", fmt.Sprintf("%d", time.Now().UnixNano()))
 	payload := postForm.Encode()
@@ -512,14 +821,186 @@ func (poloniex *Poloniex) adaptCurrencyPair(pair CurrencyPair) CurrencyPair {
 	return NewCurrencyPair(currencyA, currencyB)
 }
 
+//GetTrades answers the trades from since on, or the latest 200 when since is 0. Poloniex caps a range
+//keeping its newest trades, so a short range is asked first and widened only while it holds none.
 func (poloniex *Poloniex) GetTrades(currencyPair CurrencyPair, since int64) ([]Trade, error) {
-	panic("unimplements")
+	params := url.Values{}
+	params.Set("command", "returnTradeHistory")
+	params.Set("currencyPair", poloniex.adaptCurrencyPair(currencyPair).ToSymbol2("_"))
+	if since <= 0 {
+		return poloniex.getTrades(params, 0)
+	}
+
+	start := since / 1000
+	now := time.Now().Unix()
+	for _, span := range []int64{3600, 24 * 3600, 0} {
+		end := start + span
+		if span == 0 || end > now {
+			end = now
+		}
+		params.Set("start", strconv.FormatInt(start, 10))
+		params.Set("end", strconv.FormatInt(end, 10))
+		trades, err := poloniex.getTrades(params, since)
+		if err != nil || len(trades) > 0 || end == now {
+			return trades, err
+		}
+	}
+	return nil, nil
 }
 
-func (poloniex *Poloniex) MarketBuy(amount, price string, currency CurrencyPair) (*Order, error) {
-	panic("unsupport the market order")
+func (poloniex *Poloniex) getTrades(params url.Values, since int64) ([]Trade, error) {
+	resp, err := NewHttpRequest(poloniex.client, "GET", PUBLIC_URL+"?"+params.Encode(), "", nil)
+	if err != nil {
+		return nil, poloniex.adaptError(err)
+	}
+	var errResp struct {
+		Error string `json:"error"`
+	}
+	if json.Unmarshal(resp, &errResp) == nil && errResp.Error != "" {
+		return nil, poloniex.errorWrapper(errResp.Error)
+	}
+	var records []struct {
+		TradeID int64   `json:"tradeID"`
+		Date    string  `json:"date"`
+		Type    string  `json:"type"`
+		Rate    Decimal `json:"rate"`
+		Amount  Decimal `json:"amount"`
+	}
+	err = json.Unmarshal(resp, &records)
+	if err != nil {
+		return nil, err
+	}
+
+	trades := make([]Trade, 0, len(records))
+	for _, r := range records {
+		date, _ := time.Parse("2006-01-02 15:04:05", r.Date)
+		trades = append(trades, Trade{
+			Tid:    r.TradeID,
+			Type:   r.Type,
+			Amount: r.Amount,
+			Price:  r.Rate,
+			Date:   date.UnixNano() / int64(time.Millisecond)})
+	}
+	return TradesSinceTime(trades, since), nil
 }
 
-func (poloniex *Poloniex) MarketSell(amount, price string, currency CurrencyPair) (*Order, error) {
-	panic("unsupport the market order")
+//GetMyTrades reads returnTradeHistory from since, 10000 fills a request newest first. Poloniex gives the
+//fee as a rate of what the fill brought in and does not tell maker from taker, IsMaker stays false.
+func (poloniex *Poloniex) GetMyTrades(pair CurrencyPair, since int64, limit int) ([]MyTrade, error) {
+	var dp DecimalParser
+	const pageSize = 10000
+	var trades []MyTrade
+	seen := make(map[string]bool)
+	end := time.Now().Unix()
+	for {
+		params := url.Values{}
+		params.Set("command", "returnTradeHistory")
+		params.Set("currencyPair", poloniex.adaptCurrencyPair(pair).ToSymbol2("_"))
+		params.Set("start", strconv.FormatInt(since/1000, 10))
+		params.Set("end", strconv.FormatInt(end, 10))
+		params.Set("limit", strconv.Itoa(pageSize))
+		var fills []struct {
+			TradeID     json.Number `json:"tradeID"` //a number, or a string sometimes
+			OrderNumber string      `json:"orderNumber"`
+			Date        string      `json:"date"`
+			Rate        string      `json:"rate"`
+			Amount      string      `json:"amount"`
+			Total       string      `json:"total"`
+			Fee         string      `json:"fee"`
+			Type        string      `json:"type"`
+		}
+		err := poloniex.tradingApi(params, &fills)
+		if err != nil {
+			return nil, err
+		}
+
+		n := len(trades)
+		for _, f := range fills {
+			id := f.TradeID.String()
+			if seen[id] {
+				continue
+			}
+			seen[id] = true
+			date, _ := time.Parse("2006-01-02 15:04:05", f.Date)
+			t := MyTrade{
+				TradeID:  id,
+				OrderID:  f.OrderNumber,
+				Currency: pair,
+				Side:     SELL,
+				Price:    dp.Decimal(f.Rate),
+				Amount:   dp.Decimal(f.Amount),
+				Time:     date.Unix() * 1000}
+			//a buy pays in the currency bought, a sell in the one received for it
+			if f.Type == "buy" {
+				t.Side = BUY
+				t.Fee = t.Amount.Mul(dp.Decimal(f.Fee))
+				t.FeeCurrency = pair.CurrencyA
+			} else {
+				t.Fee = dp.Decimal(f.Total).Mul(dp.Decimal(f.Fee))
+				t.FeeCurrency = pair.CurrencyB
+			}
+			trades = append(trades, t)
+			if date.Unix() < end {
+				end = date.Unix()
+			}
+		}
+		if len(fills) < pageSize || len(trades) == n {
+			break
+		}
+	}
+	if dp.Err != nil {
+		return nil, dp.Err
+	}
+	return MyTradesSince(trades, since, limit), nil
+}
+
+func (poloniex *Poloniex) MarketBuy(amount, price Decimal, currency CurrencyPair) (*Order, error) {
+	return nil, ErrNotSupported
+}
+
+func (poloniex *Poloniex) MarketSell(amount, price Decimal, currency CurrencyPair) (*Order, error) {
+	return nil, ErrNotSupported
+}
+
+func (poloniex *Poloniex) errorWrapper(message string) ApiError {
+	var errCode ApiError
+	msg := strings.ToLower(message)
+	switch {
+	case strings.HasPrefix(msg, "nonce must be greater"):
+		errCode = EX_ERR_NONCE
+	case strings.Contains(msg, "api calls per second"):
+		errCode = EX_ERR_API_LIMIT
+	case strings.HasPrefix(msg, "not enough"):
+		errCode = EX_ERR_INSUFFICIENT_BALANCE
+	case strings.HasPrefix(msg, "invalid order number"):
+		errCode = EX_ERR_NOT_FIND_ORDER
+	case strings.Contains(msg, "must be at least"), strings.Contains(msg, "precision"):
+		errCode = EX_ERR_INVALID_PRECISION
+	case strings.HasPrefix(msg, "invalid currency pair"):
+		errCode = EX_ERR_INVALID_CURRENCY_PAIR
+	case strings.HasPrefix(msg, "invalid api key"):
+		errCode = EX_ERR_NOT_FIND_APIKEY
+	case strings.Contains(msg, "maintenance"):
+		errCode = EX_ERR_MAINTENANCE
+	default:
+		errCode = API_ERR
+	}
+	errCode.OriginErrMsg = message
+	return errCode
+}
+
+//poloniex sometimes answers with a non-200 status and {"error":"..."}
+func (poloniex *Poloniex) adaptError(err error) error {
+	apiErr, isok := err.(ApiError)
+	if !isok {
+		return err
+	}
+
+	var resp struct {
+		Error string `json:"error"`
+	}
+	if json.Unmarshal([]byte(apiErr.OriginErrMsg), &resp) != nil || resp.Error == "" {
+		return err
+	}
+	return poloniex.errorWrapper(resp.Error)
 }
@@ -0,0 +1,68 @@
+package poloniex
+
+import (
+	"context"
+
+	. "github.com/nntaoli-project/GoEx"
+)
+
+var _ API = (*Poloniex)(nil)
+var _ APIWithContext = (*Poloniex)(nil)
+
+func (poloniex *Poloniex) withContext(ctx context.Context) *Poloniex {
+	c := *poloniex
+	c.client = HttpClientWithContext(ctx, poloniex.client)
+	return &c
+}
+
+func (poloniex *Poloniex) LimitBuyCtx(ctx context.Context, amount, price Decimal, currency CurrencyPair) (*Order, error) {
+	return poloniex.withContext(ctx).LimitBuy(amount, price, currency)
+}
+
+func (poloniex *Poloniex) LimitSellCtx(ctx context.Context, amount, price Decimal, currency CurrencyPair) (*Order, error) {
+	return poloniex.withContext(ctx).LimitSell(amount, price, currency)
+}
+
+func (poloniex *Poloniex) MarketBuyCtx(ctx context.Context, amount, price Decimal, currency CurrencyPair) (*Order, error) {
+	return poloniex.withContext(ctx).MarketBuy(amount, price, currency)
+}
+
+func (poloniex *Poloniex) MarketSellCtx(ctx context.Context, amount, price Decimal, currency CurrencyPair) (*Order, error) {
+	return poloniex.withContext(ctx).MarketSell(amount, price, currency)
+}
+
+func (poloniex *Poloniex) CancelOrderCtx(ctx context.Context, orderId string, currency CurrencyPair) (bool, error) {
+	return poloniex.withContext(ctx).CancelOrder(orderId, currency)
+}
+
+func (poloniex *Poloniex) GetOneOrderCtx(ctx context.Context, orderId string, currency CurrencyPair) (*Order, error) {
+	return poloniex.withContext(ctx).GetOneOrder(orderId, currency)
+}
+
+func (poloniex *Poloniex) GetUnfinishOrdersCtx(ctx context.Context, currency CurrencyPair) ([]Order, error) {
+	return poloniex.withContext(ctx).GetUnfinishOrders(currency)
+}
+
+func (poloniex *Poloniex) GetOrderHistorysCtx(ctx context.Context, currency CurrencyPair, currentPage, pageSize int) ([]Order, error) {
+	return poloniex.withContext(ctx).GetOrderHistorys(currency, currentPage, pageSize)
+}
+
+func (poloniex *Poloniex) GetAccountCtx(ctx context.Context) (*Account, error) {
+	return poloniex.withContext(ctx).GetAccount()
+}
+
+func (poloniex *Poloniex) GetTickerCtx(ctx context.Context, currency CurrencyPair) (*Ticker, error) {
+	return poloniex.withContext(ctx).GetTicker(currency)
+}
+
+func (poloniex *Poloniex) GetDepthCtx(ctx context.Context, size int, currency CurrencyPair) (*Depth, error) {
+	return poloniex.withContext(ctx).GetDepth(size, currency)
+}
+
+func (poloniex *Poloniex) GetKlineRecordsCtx(ctx context.Context, currency CurrencyPair, period, size, since int) ([]Kline, error) {
+	return poloniex.withContext(ctx).GetKlineRecords(currency, period, size, since)
+}
+
+func (poloniex *Poloniex) GetTradesCtx(ctx context.Context, currencyPair CurrencyPair, since int64) ([]Trade, error) {
+	return poloniex.withContext(ctx).GetTrades(currencyPair, since)
+}
@@ -53,7 +53,7 @@ func (poloniex *Poloniex) CloseMarginPosition(currency CurrencyPair) (bool, erro
 		return false, err
 	}
 	if result.Success == 0 {
-		return false, errors.New(result.Error)
+		return false, poloniex.errorWrapper(result.Error)
 	}
 	return true, nil
 }
@@ -68,7 +68,7 @@ func (poloniex *Poloniex) sendAuthenticatedRequest(values url.Values, result int
 	resp, err := HttpPostForm2(poloniex.client, TRADE_API, values, headers)
 	if err != nil {
 		log.Println(err)
-		return err
+		return poloniex.adaptError(err)
 	}
 
 	err = json.Unmarshal(resp, &result)
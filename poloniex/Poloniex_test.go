@@ -0,0 +1,261 @@
+package poloniex
+
+import (
+	"bytes"
+	"github.com/nntaoli-project/GoEx"
+	"github.com/stretchr/testify/assert"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"os/exec"
+	"strings"
+	"testing"
+)
+
+var fixtures = goex.NewFixtureTransport("testdata/fixtures.json")
+var polo = New(fixtures.Client(), "", "")
+
+func TestPoloniex_GetTicker(t *testing.T) {
+	ticker, err := polo.GetTicker(goex.BTC_USDT)
+	assert.Nil(t, err)
+	assert.True(t, ticker.Last.Equal(goex.RequireDecimal("6263")))
+	assert.True(t, ticker.Buy.Equal(goex.RequireDecimal("6262.51000001")))
+	assert.True(t, ticker.Sell.Equal(goex.RequireDecimal("6264")))
+	assert.True(t, ticker.Vol.Equal(goex.RequireDecimal("2391.73614417")))
+}
+
+func TestPoloniex_GetDepth(t *testing.T) {
+	dep, err := polo.GetDepth(2, goex.BTC_USDT)
+	assert.Nil(t, err)
+	assert.Len(t, dep.AskList, 2)
+	assert.Len(t, dep.BidList, 2)
+	assert.True(t, dep.AskList[0].Price.Equal(goex.RequireDecimal("6264")))
+	assert.True(t, dep.BidList[1].Amount.Equal(goex.RequireDecimal("2.5")))
+}
+
+func TestPoloniex_GetAccount(t *testing.T) {
+	acc, err := polo.GetAccount()
+	assert.Nil(t, err)
+	assert.Equal(t, "poloniex.com", acc.Exchange)
+	assert.True(t, acc.SubAccounts[goex.BTC].Amount.Equal(goex.RequireDecimal("0.0150211")))
+	assert.True(t, acc.SubAccounts[goex.USDT].FrozenAmount.Equal(goex.RequireDecimal("62.64")))
+}
+
+//rewriteTransport sends every request to the stand-in server
+type rewriteTransport struct {
+	host string
+}
+
+func (rt rewriteTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	req.URL.Scheme = "http"
+	req.URL.Host = rt.host
+	return http.DefaultTransport.RoundTrip(req)
+}
+
+func TestPoloniex_GetMyTrades(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "/tradingApi", r.URL.Path)
+		r.ParseForm()
+		assert.Equal(t, "returnTradeHistory", r.PostForm.Get("command"))
+		assert.Equal(t, "USDT_BTC", r.PostForm.Get("currencyPair"))
+		assert.Equal(t, "1525132800", r.PostForm.Get("start"))
+		assert.Equal(t, "10000", r.PostForm.Get("limit"))
+		w.Write([]byte(`[{"globalTradeID":394700861,"tradeID":45210255,"date":"2018-05-01 00:10:00","rate":"9250.00000000",` +
+			`"amount":"0.10000000","total":"925.00000000","fee":"0.00150000","orderNumber":"104768235081","type":"sell","category":"exchange"},` +
+			`{"globalTradeID":394700800,"tradeID":45210250,"date":"2018-05-01 00:05:00","rate":"9240.00000000",` +
+			`"amount":"0.20000000","total":"1848.00000000","fee":"0.00250000","orderNumber":"104768235000","type":"buy","category":"exchange"}]`))
+	}))
+	defer srv.Close()
+	polo := New(&http.Client{Transport: rewriteTransport{strings.TrimPrefix(srv.URL, "http://")}}, "", "")
+
+	trades, err := polo.GetMyTrades(goex.BTC_USDT, 1525132800000, 10)
+	assert.Nil(t, err)
+	assert.Equal(t, 2, len(trades))
+	assert.Equal(t, "45210250", trades[0].TradeID)
+	assert.Equal(t, "104768235000", trades[0].OrderID)
+	assert.Equal(t, goex.TradeSide(goex.BUY), trades[0].Side)
+	assert.Equal(t, int64(1525133100000), trades[0].Time)
+	//a buy pays the fee in btc, a sell in usdt
+	assert.True(t, trades[0].Fee.Equal(goex.RequireDecimal("0.0005")))
+	assert.Equal(t, goex.BTC, trades[0].FeeCurrency)
+	assert.Equal(t, goex.TradeSide(goex.SELL), trades[1].Side)
+	assert.True(t, trades[1].Fee.Equal(goex.RequireDecimal("1.3875")))
+	assert.Equal(t, goex.USDT, trades[1].FeeCurrency)
+}
+
+func TestPoloniex_GetTrades(t *testing.T) {
+	var spans []string
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		q := r.URL.Query()
+		assert.Equal(t, "/public", r.URL.Path)
+		assert.Equal(t, "returnTradeHistory", q.Get("command"))
+		assert.Equal(t, "USDT_BTC", q.Get("currencyPair"))
+		assert.Equal(t, "1525132800", q.Get("start"))
+		spans = append(spans, q.Get("end"))
+		//nothing in the first hour, the day holds a trade
+		if q.Get("end") == "1525136400" {
+			w.Write([]byte(`[]`))
+			return
+		}
+		w.Write([]byte(`[{"globalTradeID":394698946,"tradeID":23549421,"date":"2018-05-01 05:30:12","type":"buy",` +
+			`"rate":"9250.00000000","amount":"0.01000000","total":"92.50000000"}]`))
+	}))
+	defer srv.Close()
+	polo := New(&http.Client{Transport: rewriteTransport{strings.TrimPrefix(srv.URL, "http://")}}, "", "")
+
+	trades, err := polo.GetTrades(goex.BTC_USDT, 1525132800000)
+	assert.Nil(t, err)
+	assert.Equal(t, []string{"1525136400", "1525219200"}, spans)
+	assert.Equal(t, []goex.Trade{{Tid: 23549421, Type: "buy", Amount: goex.RequireDecimal("0.01"), Price: goex.RequireDecimal("9250"),
+		Date: 1525152612000}}, trades)
+}
+
+func TestMain(m *testing.M) {
+	os.Exit(fixtures.Run(m.Run))
+}
+
+func TestPoloniex_errorWrapper(t *testing.T) {
+	for message, errCode := range map[string]goex.ApiError{
+		"Nonce must be greater than 1514264404426000. You provided 1514264404000.": goex.EX_ERR_NONCE,
+		"Please do not make more than 8 API calls per second.":                     goex.EX_ERR_API_LIMIT,
+		"Not enough BTC.": goex.EX_ERR_INSUFFICIENT_BALANCE,
+		"Invalid order number, or you are not the person who placed the order.": goex.EX_ERR_NOT_FIND_ORDER,
+		"Total must be at least 0.0001.":                                        goex.EX_ERR_INVALID_PRECISION,
+		"Invalid currency pair.":                                                goex.EX_ERR_INVALID_CURRENCY_PAIR,
+		"Invalid API key/secret pair.":                                          goex.EX_ERR_NOT_FIND_APIKEY,
+		"Poloniex is undergoing scheduled maintenance.":                         goex.EX_ERR_MAINTENANCE,
+		"Invalid command.":                                                      goex.API_ERR,
+	} {
+		err := polo.errorWrapper(message)
+		assert.True(t, errCode.Is(err), message)
+		assert.Equal(t, message, err.OriginErrMsg)
+	}
+}
+
+func TestPoloniex_adaptError(t *testing.T) {
+	httpErr := goex.HTTP_ERR_CODE
+	httpErr.OriginErrMsg = `{"error":"Invalid currency pair."}`
+	assert.True(t, goex.EX_ERR_INVALID_CURRENCY_PAIR.Is(polo.adaptError(httpErr)))
+
+	httpErr.OriginErrMsg = "error code: 1020"
+	assert.Equal(t, error(httpErr), polo.adaptError(httpErr))
+}
+
+func TestPoloniex_GetKlineRecords(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		q := r.URL.Query()
+		assert.Equal(t, "/public", r.URL.Path)
+		assert.Equal(t, "returnChartData", q.Get("command"))
+		assert.Equal(t, "USDT_BTC", q.Get("currencyPair"))
+		assert.Equal(t, "14400", q.Get("period"))
+		assert.Equal(t, "1525132800", q.Get("start"))
+		assert.Equal(t, "1525161600", q.Get("end"))
+		w.Write([]byte(`[{"date":1525132800,"high":9300,"low":9200,"open":9250,"close":9280,"volume":115625,"quoteVolume":12.5,"weightedAverage":9250},` +
+			`{"date":1525147200,"high":9290,"low":9240,"open":9280,"close":9260,"volume":76395,"quoteVolume":8.25,"weightedAverage":9260}]`))
+	}))
+	defer srv.Close()
+	polo := New(&http.Client{Transport: rewriteTransport{strings.TrimPrefix(srv.URL, "http://")}}, "", "")
+
+	klines, err := polo.GetKlineRecords(goex.BTC_USDT, goex.KLINE_PERIOD_4H, 2, 1525132800000)
+	assert.Nil(t, err)
+	assert.Equal(t, []goex.Kline{
+		{Timestamp: 1525132800, Open: 9250, Close: 9280, High: 9300, Low: 9200, Vol: 12.5},
+		{Timestamp: 1525147200, Open: 9280, Close: 9260, High: 9290, Low: 9240, Vol: 8.25}}, klines)
+}
+
+func TestPoloniex_Withdraw(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "/tradingApi", r.URL.Path)
+		r.ParseForm()
+		switch r.PostForm.Get("command") {
+		case "withdraw":
+			assert.Equal(t, "BCH", r.PostForm.Get("currency"))
+			assert.Equal(t, "0.5", r.PostForm.Get("amount"))
+			assert.Equal(t, "1BvBMSEYstWetqTFn5Au4m4GFg7xJaNVN2", r.PostForm.Get("address"))
+			assert.Equal(t, "", r.PostForm.Get("paymentId"))
+			w.Write([]byte(`{"response":"Withdrew 0.50000000 BCH."}`))
+		case "returnDepositsWithdrawals":
+			w.Write([]byte(`{"deposits":[],"withdrawals":[{"withdrawalNumber":134933,"currency":"BCH",` +
+				`"address":"1BvBMSEYstWetqTFn5Au4m4GFg7xJaNVN2","amount":"0.50000000","fee":"0.00010000","timestamp":1525152612,` +
+				`"status":"AWAITING APPROVAL","ipAddress":"127.0.0.1"}]}`))
+		default:
+			t.Errorf("unexpected command %s", r.PostForm.Get("command"))
+		}
+	}))
+	defer srv.Close()
+	polo := New(&http.Client{Transport: rewriteTransport{strings.TrimPrefix(srv.URL, "http://")}}, "", "")
+
+	withdraw, err := polo.Withdraw(goex.WithdrawRequest{Currency: goex.BCC, Amount: goex.RequireDecimal("0.5"), Address: "1BvBMSEYstWetqTFn5Au4m4GFg7xJaNVN2"})
+	assert.Nil(t, err)
+	assert.Equal(t, goex.Withdraw{ID: "134933", Currency: goex.BCC, Amount: goex.RequireDecimal("0.5"), Address: "1BvBMSEYstWetqTFn5Au4m4GFg7xJaNVN2",
+		Status: goex.WITHDRAW_PENDING, Time: 1525152612}, *withdraw)
+}
+
+func TestPoloniex_GetWithdraw(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "/tradingApi", r.URL.Path)
+		r.ParseForm()
+		assert.Equal(t, "returnDepositsWithdrawals", r.PostForm.Get("command"))
+		assert.NotEmpty(t, r.PostForm.Get("start"))
+		w.Write([]byte(`{"deposits":[],"withdrawals":[` +
+			`{"withdrawalNumber":1001,"currency":"ETH","address":"0x5e4f","amount":"1.00000000","timestamp":1525152612,"status":"COMPLETE: 0x9a2fe1"},` +
+			`{"withdrawalNumber":1002,"currency":"ETH","address":"0x5e4f","amount":"1.00000000","timestamp":1525152612,"status":"CANCELED"},` +
+			`{"withdrawalNumber":1003,"currency":"ETH","address":"0x5e4f","amount":"1.00000000","timestamp":1525152612,"status":"REJECTED"},` +
+			`{"withdrawalNumber":1004,"currency":"ETH","address":"0x5e4f","amount":"1.00000000","timestamp":1525152612,"status":"PROCESSING"},` +
+			`{"withdrawalNumber":1005,"currency":"ETH","address":"0x5e4f","amount":"1.00000000","timestamp":1525152612,"status":"AWAITING APPROVAL"}]}`))
+	}))
+	defer srv.Close()
+	polo := New(&http.Client{Transport: rewriteTransport{strings.TrimPrefix(srv.URL, "http://")}}, "", "")
+
+	withdraw, err := polo.GetWithdraw("1001", goex.ETH)
+	assert.Nil(t, err)
+	assert.Equal(t, goex.Withdraw{ID: "1001", Currency: goex.ETH, Amount: goex.RequireDecimal("1"), Address: "0x5e4f", TxID: "0x9a2fe1",
+		Status: goex.WITHDRAW_COMPLETED, Time: 1525152612}, *withdraw)
+
+	for id, status := range map[string]goex.WithdrawStatus{
+		"1002": goex.WITHDRAW_CANCELED,
+		"1003": goex.WITHDRAW_FAILED,
+		"1004": goex.WITHDRAW_PROCESSING,
+		"1005": goex.WITHDRAW_PENDING,
+	} {
+		withdraw, err := polo.GetWithdraw(id, goex.ETH)
+		assert.Nil(t, err)
+		assert.Equal(t, status, withdraw.Status, id)
+	}
+
+	_, err = polo.GetWithdraw("1006", goex.ETH)
+	assert.True(t, goex.EX_ERR_NOT_FIND_WITHDRAW.Is(err))
+}
+
+//TestPoloniex_Withdraw_printsNothing runs itself again in a child process, as builtin println writes
+//to the stderr file descriptor directly, and asserts the child prints nothing but the test verdict
+func TestPoloniex_Withdraw_printsNothing(t *testing.T) {
+	if os.Getenv("POLONIEX_PRINT_CHILD") != "1" {
+		cmd := exec.Command(os.Args[0], "-test.run=^TestPoloniex_Withdraw_printsNothing$")
+		cmd.Env = append(os.Environ(), "POLONIEX_PRINT_CHILD=1")
+		var stdout, stderr bytes.Buffer
+		cmd.Stdout, cmd.Stderr = &stdout, &stderr
+		assert.Nil(t, cmd.Run())
+		assert.Equal(t, "PASS\n", stdout.String())
+		assert.Equal(t, "", stderr.String())
+		return
+	}
+
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		r.ParseForm()
+		if r.PostForm.Get("command") == "withdraw" {
+			w.Write([]byte(`{"response":"Withdrew 0.50000000 BCH."}`))
+			return
+		}
+		w.Write([]byte(`{"deposits":[],"withdrawals":[{"withdrawalNumber":134933,"currency":"BCH",` +
+			`"address":"1BvBMSEYstWetqTFn5Au4m4GFg7xJaNVN2","amount":"0.50000000","timestamp":1525152612,` +
+			`"status":"COMPLETE: 0x9a2fe1","ipAddress":"127.0.0.1"}]}`))
+	}))
+	defer srv.Close()
+	polo := New(&http.Client{Transport: rewriteTransport{strings.TrimPrefix(srv.URL, "http://")}}, "", "")
+
+	_, err := polo.Withdraw(goex.WithdrawRequest{Currency: goex.BCC, Amount: goex.RequireDecimal("0.5"), Address: "1BvBMSEYstWetqTFn5Au4m4GFg7xJaNVN2"})
+	assert.Nil(t, err)
+	_, err = polo.GetWithdraw("134933", goex.BCC)
+	assert.Nil(t, err)
+}
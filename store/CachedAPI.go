@@ -0,0 +1,122 @@
+package store
+
+import (
+	"context"
+	. "github.com/nntaoli-project/GoEx"
+	"time"
+)
+
+//CachedAPI wraps an API and serves GetKlineRecords from the Store, asking the exchange only for the
+//candles of ranges it never fetched before. GetTrades and GetDepth pass through and are recorded.
+type CachedAPI struct {
+	API
+	Store *Store
+}
+
+func NewCachedAPI(api API, store *Store) *CachedAPI {
+	return &CachedAPI{API: api, Store: store}
+}
+
+//GetKlineRecords caches the candles already closed. The latest ones, since 0, those of months and years,
+//whose length varies, and those of the exchanges in KlineLatestOnly come from the exchange every time.
+func (c *CachedAPI) GetKlineRecords(currency CurrencyPair, period, size, since int) ([]Kline, error) {
+	exchange := c.API.GetExchangeName()
+	periodMs := int64(KlinePeriodDuration(period) / time.Millisecond)
+	if since <= 0 || size <= 0 || periodMs <= 0 || period == KLINE_PERIOD_1MONTH || period == KLINE_PERIOD_1YEAR ||
+		KlineLatestOnly[exchange] {
+		return c.API.GetKlineRecords(currency, period, size, since)
+	}
+
+	start := int64(since)
+	end := start + periodMs*int64(size)
+	closed := time.Now().UnixNano()/int64(time.Millisecond) - periodMs //candles opened before are closed
+	cacheEnd := end
+	if cacheEnd > closed {
+		cacheEnd = closed
+	}
+
+	if start < cacheEnd {
+		ranges, err := c.Store.KlineRanges(exchange, currency, period)
+		if err != nil {
+			return nil, err
+		}
+		for _, r := range missingRanges(ranges, start, cacheEnd) {
+			if err := c.fetchKlines(currency, period, r[0], r[1]); err != nil {
+				return nil, err
+			}
+		}
+	}
+
+	klines, err := c.Store.Klines(exchange, currency, period, start, cacheEnd)
+	if err != nil {
+		return nil, err
+	}
+	if end > cacheEnd && len(klines) < size {
+		from := cacheEnd
+		if from < start {
+			from = start
+		}
+		live, err := c.API.GetKlineRecords(currency, period, size-len(klines), int(from))
+		if err != nil {
+			return nil, err
+		}
+		klines = append(klines, KlinesSince(live, from, 0)...)
+	}
+	return KlinesSince(klines, start, size), nil
+}
+
+//fetchKlines reads the candles from start until before end into the Store. It records the range from the
+//first candle read to the end of the last, a part the exchange answered nothing for is asked again next time.
+func (c *CachedAPI) fetchKlines(currency CurrencyPair, period int, start, end int64) error {
+	exchange := c.API.GetExchangeName()
+	periodMs := int64(KlinePeriodDuration(period) / time.Millisecond)
+	first, last := int64(-1), int64(-1)
+	it := NewKlineIterator(c.API, currency, period, start, end)
+	for !it.Done() {
+		klines, err := it.Next(context.Background())
+		if err != nil {
+			return err
+		}
+		if err = c.Store.PutKlines(exchange, currency, period, klines); err != nil {
+			return err
+		}
+		for _, k := range klines {
+			if first < 0 || k.Timestamp*1000 < first {
+				first = k.Timestamp * 1000
+			}
+			if k.Timestamp*1000+periodMs > last {
+				last = k.Timestamp*1000 + periodMs
+			}
+		}
+	}
+	if first < 0 {
+		return nil
+	}
+	if last > end {
+		last = end
+	}
+	return c.Store.AddKlineRange(exchange, currency, period, first, last)
+}
+
+func (c *CachedAPI) GetTrades(currencyPair CurrencyPair, since int64) ([]Trade, error) {
+	trades, err := c.API.GetTrades(currencyPair, since)
+	if err != nil {
+		return nil, err
+	}
+	if err = c.Store.PutTrades(c.API.GetExchangeName(), currencyPair, trades); err != nil {
+		return nil, err
+	}
+	return trades, nil
+}
+
+func (c *CachedAPI) GetDepth(size int, currency CurrencyPair) (*Depth, error) {
+	depth, err := c.API.GetDepth(size, currency)
+	if err != nil {
+		return nil, err
+	}
+	at := time.Now().UnixNano() / int64(time.Millisecond)
+	if err = c.Store.PutDepth(c.API.GetExchangeName(), currency, at, depth); err != nil {
+		return nil, err
+	}
+	return depth, nil
+}
@@ -0,0 +1,250 @@
+package store
+
+import (
+	"encoding/binary"
+	"encoding/json"
+	"fmt"
+	"github.com/btcsuite/goleveldb/leveldb"
+	"github.com/btcsuite/goleveldb/leveldb/util"
+	. "github.com/nntaoli-project/GoEx"
+	"sync"
+)
+
+//Store keeps the candles, trades and depth snapshots of any exchange in a leveldb database, keyed by
+//exchange, pair and unix milliseconds so that a range reads back oldest first
+type Store struct {
+	db *leveldb.DB
+	mu sync.Mutex //guards the read-modify-write of the fetched ranges
+}
+
+//DepthSnapshot is a Depth as it was at Time, unix milliseconds
+type DepthSnapshot struct {
+	Time int64
+	Depth
+}
+
+//Open opens the database in the directory path, creating it when missing
+func Open(path string) (*Store, error) {
+	db, err := leveldb.OpenFile(path, nil)
+	if err != nil {
+		return nil, err
+	}
+	return NewStore(db), nil
+}
+
+func NewStore(db *leveldb.DB) *Store {
+	return &Store{db: db}
+}
+
+func (s *Store) Close() error {
+	return s.db.Close()
+}
+
+func klinePrefix(exchange string, pair CurrencyPair, period int) string {
+	return fmt.Sprintf("kline/%s/%s/%d/", exchange, pair.ToSymbol("_"), period)
+}
+
+func tradePrefix(exchange string, pair CurrencyPair) string {
+	return fmt.Sprintf("trade/%s/%s/", exchange, pair.ToSymbol("_"))
+}
+
+func depthPrefix(exchange string, pair CurrencyPair) string {
+	return fmt.Sprintf("depth/%s/%s/", exchange, pair.ToSymbol("_"))
+}
+
+func rangesKey(exchange string, pair CurrencyPair, period int) []byte {
+	return []byte(fmt.Sprintf("ranges/%s/%s/%d", exchange, pair.ToSymbol("_"), period))
+}
+
+//key appends the numbers big endian to the prefix, so that leveldb orders the keys by them
+func key(prefix string, nums ...int64) []byte {
+	k := make([]byte, len(prefix), len(prefix)+8*len(nums))
+	copy(k, prefix)
+	for _, n := range nums {
+		var b [8]byte
+		binary.BigEndian.PutUint64(b[:], uint64(n))
+		k = append(k, b[:]...)
+	}
+	return k
+}
+
+//scan calls each with the time and value of the keys from start until before end, unix milliseconds with
+//end 0 for no end
+func (s *Store) scan(prefix string, start, end int64, each func(at int64, value []byte) error) error {
+	r := &util.Range{Start: key(prefix, start), Limit: key(prefix, end)}
+	if end <= 0 {
+		r.Limit = util.BytesPrefix([]byte(prefix)).Limit
+	}
+	iter := s.db.NewIterator(r, nil)
+	defer iter.Release()
+	for iter.Next() {
+		at := int64(binary.BigEndian.Uint64(iter.Key()[len(prefix):]))
+		if err := each(at, iter.Value()); err != nil {
+			return err
+		}
+	}
+	return iter.Error()
+}
+
+func (s *Store) PutKlines(exchange string, pair CurrencyPair, period int, klines []Kline) error {
+	prefix := klinePrefix(exchange, pair, period)
+	batch := new(leveldb.Batch)
+	for _, k := range klines {
+		data, err := json.Marshal(k)
+		if err != nil {
+			return err
+		}
+		batch.Put(key(prefix, k.Timestamp*1000), data)
+	}
+	return s.db.Write(batch, nil)
+}
+
+//Klines returns the candles opened from start until before end, unix milliseconds with end 0 for no end
+func (s *Store) Klines(exchange string, pair CurrencyPair, period int, start, end int64) ([]Kline, error) {
+	var klines []Kline
+	err := s.scan(klinePrefix(exchange, pair, period), start, end, func(_ int64, value []byte) error {
+		var k Kline
+		if err := json.Unmarshal(value, &k); err != nil {
+			return err
+		}
+		klines = append(klines, k)
+		return nil
+	})
+	return klines, err
+}
+
+//PutTrades keeps the trades by Date and Tid, storing one again replaces it
+func (s *Store) PutTrades(exchange string, pair CurrencyPair, trades []Trade) error {
+	prefix := tradePrefix(exchange, pair)
+	batch := new(leveldb.Batch)
+	for _, t := range trades {
+		data, err := json.Marshal(t)
+		if err != nil {
+			return err
+		}
+		batch.Put(key(prefix, t.Date, t.Tid), data)
+	}
+	return s.db.Write(batch, nil)
+}
+
+//Trades returns the trades made from start until before end, unix milliseconds with end 0 for no end
+func (s *Store) Trades(exchange string, pair CurrencyPair, start, end int64) ([]Trade, error) {
+	var trades []Trade
+	err := s.scan(tradePrefix(exchange, pair), start, end, func(_ int64, value []byte) error {
+		var t Trade
+		if err := json.Unmarshal(value, &t); err != nil {
+			return err
+		}
+		trades = append(trades, t)
+		return nil
+	})
+	return trades, err
+}
+
+func (s *Store) PutDepth(exchange string, pair CurrencyPair, at int64, depth *Depth) error {
+	data, err := json.Marshal(depth)
+	if err != nil {
+		return err
+	}
+	return s.db.Put(key(depthPrefix(exchange, pair), at), data, nil)
+}
+
+//Depths returns the snapshots taken from start until before end, unix milliseconds with end 0 for no end
+func (s *Store) Depths(exchange string, pair CurrencyPair, start, end int64) ([]DepthSnapshot, error) {
+	var snapshots []DepthSnapshot
+	err := s.scan(depthPrefix(exchange, pair), start, end, func(at int64, value []byte) error {
+		snapshot := DepthSnapshot{Time: at}
+		if err := json.Unmarshal(value, &snapshot.Depth); err != nil {
+			return err
+		}
+		snapshots = append(snapshots, snapshot)
+		return nil
+	})
+	return snapshots, err
+}
+
+//DepthAt returns the latest snapshot taken at or before at, nil when there is none
+func (s *Store) DepthAt(exchange string, pair CurrencyPair, at int64) (*DepthSnapshot, error) {
+	prefix := depthPrefix(exchange, pair)
+	iter := s.db.NewIterator(&util.Range{Start: key(prefix, 0), Limit: key(prefix, at+1)}, nil)
+	defer iter.Release()
+	if !iter.Last() {
+		return nil, iter.Error()
+	}
+	snapshot := &DepthSnapshot{Time: int64(binary.BigEndian.Uint64(iter.Key()[len(prefix):]))}
+	if err := json.Unmarshal(iter.Value(), &snapshot.Depth); err != nil {
+		return nil, err
+	}
+	return snapshot, nil
+}
+
+//KlineRanges returns the ranges of candles already fetched from the exchange, sorted and apart, as
+//[start, end) in unix milliseconds. A range may hold no candles where the exchange had none.
+func (s *Store) KlineRanges(exchange string, pair CurrencyPair, period int) ([][2]int64, error) {
+	data, err := s.db.Get(rangesKey(exchange, pair, period), nil)
+	if err == leveldb.ErrNotFound {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	var ranges [][2]int64
+	err = json.Unmarshal(data, &ranges)
+	return ranges, err
+}
+
+//AddKlineRange records the candles from start until before end as fetched, merged with the ranges it touches
+func (s *Store) AddKlineRange(exchange string, pair CurrencyPair, period int, start, end int64) error {
+	if end <= start {
+		return nil
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	ranges, err := s.KlineRanges(exchange, pair, period)
+	if err != nil {
+		return err
+	}
+
+	merged := make([][2]int64, 0, len(ranges)+1)
+	i := 0
+	for ; i < len(ranges) && ranges[i][1] < start; i++ {
+		merged = append(merged, ranges[i])
+	}
+	for ; i < len(ranges) && ranges[i][0] <= end; i++ {
+		if ranges[i][0] < start {
+			start = ranges[i][0]
+		}
+		if ranges[i][1] > end {
+			end = ranges[i][1]
+		}
+	}
+	merged = append(merged, [2]int64{start, end})
+	merged = append(merged, ranges[i:]...)
+
+	data, err := json.Marshal(merged)
+	if err != nil {
+		return err
+	}
+	return s.db.Put(rangesKey(exchange, pair, period), data, nil)
+}
+
+//missingRanges returns the parts of [start, end) the sorted ranges don't cover
+func missingRanges(ranges [][2]int64, start, end int64) [][2]int64 {
+	var missing [][2]int64
+	for _, r := range ranges {
+		if r[1] <= start {
+			continue
+		}
+		if r[0] >= end {
+			break
+		}
+		if r[0] > start {
+			missing = append(missing, [2]int64{start, r[0]})
+		}
+		start = r[1]
+	}
+	if start < end {
+		missing = append(missing, [2]int64{start, end})
+	}
+	return missing
+}
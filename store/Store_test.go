@@ -0,0 +1,198 @@
+package store
+
+import (
+	"testing"
+	"time"
+
+	"github.com/btcsuite/goleveldb/leveldb"
+	"github.com/btcsuite/goleveldb/leveldb/storage"
+	"github.com/nntaoli-project/GoEx"
+	"github.com/stretchr/testify/assert"
+)
+
+func memStore(t *testing.T) *Store {
+	db, err := leveldb.Open(storage.NewMemStorage(), nil)
+	assert.Nil(t, err)
+	return NewStore(db)
+}
+
+//klineAPI has a minute candle every minute from first, and counts what is asked of it
+type klineAPI struct {
+	goex.API
+	first  int64
+	sinces []int
+}
+
+func (k *klineAPI) GetKlineRecords(currency goex.CurrencyPair, period, size, since int) ([]goex.Kline, error) {
+	k.sinces = append(k.sinces, since)
+	var klines []goex.Kline
+	now := time.Now().Unix()
+	for ts := k.first; ts <= now && len(klines) < size; ts += 60 {
+		if ts*1000 >= int64(since) {
+			klines = append(klines, goex.Kline{Timestamp: ts, Close: float64(ts)})
+		}
+	}
+	return klines, nil
+}
+
+func (k *klineAPI) GetExchangeName() string {
+	return "kline.test"
+}
+
+func timestamps(klines []goex.Kline) (ts []int64) {
+	for _, k := range klines {
+		ts = append(ts, k.Timestamp)
+	}
+	return ts
+}
+
+func TestStore_Klines(t *testing.T) {
+	s := memStore(t)
+	defer s.Close()
+
+	assert.Nil(t, s.PutKlines("kline.test", goex.BTC_USD, goex.KLINE_PERIOD_1MIN, []goex.Kline{{Timestamp: 120}, {Timestamp: 60}, {Timestamp: 180}}))
+	assert.Nil(t, s.PutKlines("kline.test", goex.BTC_USD, goex.KLINE_PERIOD_5MIN, []goex.Kline{{Timestamp: 300}}))
+	assert.Nil(t, s.PutKlines("kline.test", goex.LTC_BTC, goex.KLINE_PERIOD_1MIN, []goex.Kline{{Timestamp: 90}}))
+
+	klines, err := s.Klines("kline.test", goex.BTC_USD, goex.KLINE_PERIOD_1MIN, 60000, 180000)
+	assert.Nil(t, err)
+	assert.Equal(t, []int64{60, 120}, timestamps(klines))
+	klines, err = s.Klines("kline.test", goex.BTC_USD, goex.KLINE_PERIOD_1MIN, 0, 0)
+	assert.Nil(t, err)
+	assert.Equal(t, []int64{60, 120, 180}, timestamps(klines))
+}
+
+func TestStore_Trades(t *testing.T) {
+	s := memStore(t)
+	defer s.Close()
+
+	trades := []goex.Trade{{Tid: 2, Type: "sell", Amount: goex.RequireDecimal("1.5"), Price: goex.RequireDecimal("100"), Date: 1000},
+		{Tid: 1, Type: "buy", Amount: goex.RequireDecimal("0.5"), Price: goex.RequireDecimal("99"), Date: 1000},
+		{Tid: 3, Type: "buy", Amount: goex.RequireDecimal("2"), Price: goex.RequireDecimal("101"), Date: 2000}}
+	assert.Nil(t, s.PutTrades("kline.test", goex.BTC_USD, trades))
+
+	stored, err := s.Trades("kline.test", goex.BTC_USD, 1000, 2000)
+	assert.Nil(t, err)
+	assert.Equal(t, []goex.Trade{trades[1], trades[0]}, stored)
+}
+
+func TestStore_DepthAt(t *testing.T) {
+	s := memStore(t)
+	defer s.Close()
+
+	depth := &goex.Depth{AskList: goex.DepthRecords{{Price: goex.RequireDecimal("101"), Amount: goex.RequireDecimal("1")}},
+		BidList: goex.DepthRecords{{Price: goex.RequireDecimal("99"), Amount: goex.RequireDecimal("2")}}}
+	assert.Nil(t, s.PutDepth("kline.test", goex.BTC_USD, 1000, depth))
+	assert.Nil(t, s.PutDepth("kline.test", goex.BTC_USD, 3000, &goex.Depth{}))
+
+	snapshot, err := s.DepthAt("kline.test", goex.BTC_USD, 2999)
+	assert.Nil(t, err)
+	assert.Equal(t, &DepthSnapshot{Time: 1000, Depth: *depth}, snapshot)
+	snapshot, err = s.DepthAt("kline.test", goex.BTC_USD, 999)
+	assert.Nil(t, err)
+	assert.Nil(t, snapshot)
+
+	snapshots, err := s.Depths("kline.test", goex.BTC_USD, 0, 0)
+	assert.Nil(t, err)
+	assert.Len(t, snapshots, 2)
+	assert.Equal(t, int64(3000), snapshots[1].Time)
+}
+
+func TestStore_AddKlineRange(t *testing.T) {
+	s := memStore(t)
+	defer s.Close()
+
+	for _, r := range [][2]int64{{100, 200}, {400, 500}, {700, 800}, {150, 450}} {
+		assert.Nil(t, s.AddKlineRange("kline.test", goex.BTC_USD, goex.KLINE_PERIOD_1MIN, r[0], r[1]))
+	}
+	ranges, err := s.KlineRanges("kline.test", goex.BTC_USD, goex.KLINE_PERIOD_1MIN)
+	assert.Nil(t, err)
+	assert.Equal(t, [][2]int64{{100, 500}, {700, 800}}, ranges)
+
+	assert.Equal(t, [][2]int64{{0, 100}, {500, 700}, {800, 900}}, missingRanges(ranges, 0, 900))
+	assert.Equal(t, [][2]int64{{500, 600}}, missingRanges(ranges, 300, 600))
+	assert.Nil(t, missingRanges(ranges, 100, 500))
+}
+
+func TestCachedAPI_GetKlineRecords(t *testing.T) {
+	s := memStore(t)
+	defer s.Close()
+	first := time.Now().Unix()/60*60 - 600*60
+	inner := &klineAPI{first: first}
+	api := NewCachedAPI(inner, s)
+
+	since := int((first + 100*60) * 1000)
+	klines, err := api.GetKlineRecords(goex.BTC_USD, goex.KLINE_PERIOD_1MIN, 10, since)
+	assert.Nil(t, err)
+	assert.Len(t, klines, 10)
+	assert.Equal(t, first+100*60, klines[0].Timestamp)
+	assert.Equal(t, []int{since}, inner.sinces)
+
+	//served from disk
+	klines, err = api.GetKlineRecords(goex.BTC_USD, goex.KLINE_PERIOD_1MIN, 5, since+60000)
+	assert.Nil(t, err)
+	assert.Equal(t, first+101*60, klines[0].Timestamp)
+	assert.Len(t, klines, 5)
+	assert.Len(t, inner.sinces, 1)
+
+	//only the missing part is fetched
+	klines, err = api.GetKlineRecords(goex.BTC_USD, goex.KLINE_PERIOD_1MIN, 20, since)
+	assert.Nil(t, err)
+	assert.Len(t, klines, 20)
+	assert.Equal(t, []int{since, since + 10*60000}, inner.sinces)
+
+	//the open candle comes from the exchange every time
+	inner.sinces = nil
+	since = int((first + 595*60) * 1000)
+	klines, err = api.GetKlineRecords(goex.BTC_USD, goex.KLINE_PERIOD_1MIN, 10, since)
+	assert.Nil(t, err)
+	assert.Equal(t, first+600*60, klines[len(klines)-1].Timestamp)
+	assert.Len(t, inner.sinces, 2)
+	klines, err = api.GetKlineRecords(goex.BTC_USD, goex.KLINE_PERIOD_1MIN, 10, since)
+	assert.Nil(t, err)
+	assert.Equal(t, first+600*60, klines[len(klines)-1].Timestamp)
+	assert.Len(t, inner.sinces, 3)
+}
+
+func TestCachedAPI_KlineRanges(t *testing.T) {
+	s := memStore(t)
+	defer s.Close()
+	first := time.Now().Unix()/60*60 - 600*60
+	inner := &klineAPI{first: first}
+	api := NewCachedAPI(inner, s)
+
+	//the exchange has nothing before first, only what it answered is recorded as fetched
+	since := int((first - 5*60) * 1000)
+	klines, err := api.GetKlineRecords(goex.BTC_USD, goex.KLINE_PERIOD_1MIN, 10, since)
+	assert.Nil(t, err)
+	assert.Equal(t, first, klines[0].Timestamp)
+	ranges, err := s.KlineRanges("kline.test", goex.BTC_USD, goex.KLINE_PERIOD_1MIN)
+	assert.Nil(t, err)
+	assert.Equal(t, [][2]int64{{first * 1000, int64(since) + 10*60000}}, ranges)
+
+	inner.sinces = nil
+	_, err = api.GetKlineRecords(goex.BTC_USD, goex.KLINE_PERIOD_1MIN, 10, since)
+	assert.Nil(t, err)
+	assert.Equal(t, []int{since}, inner.sinces)
+}
+
+func TestCachedAPI_KlineLatestOnly(t *testing.T) {
+	s := memStore(t)
+	defer s.Close()
+	goex.KlineLatestOnly["kline.test"] = true
+	defer delete(goex.KlineLatestOnly, "kline.test")
+	first := time.Now().Unix()/60*60 - 600*60
+	inner := &klineAPI{first: first}
+	api := NewCachedAPI(inner, s)
+
+	since := int((first + 100*60) * 1000)
+	for i := 0; i < 2; i++ {
+		klines, err := api.GetKlineRecords(goex.BTC_USD, goex.KLINE_PERIOD_1MIN, 10, since)
+		assert.Nil(t, err)
+		assert.Len(t, klines, 10)
+	}
+	assert.Equal(t, []int{since, since}, inner.sinces)
+	ranges, err := s.KlineRanges("kline.test", goex.BTC_USD, goex.KLINE_PERIOD_1MIN)
+	assert.Nil(t, err)
+	assert.Empty(t, ranges)
+}
@@ -0,0 +1,68 @@
+package wex
+
+import (
+	"context"
+
+	. "github.com/nntaoli-project/GoEx"
+)
+
+var _ API = (*Wex)(nil)
+var _ APIWithContext = (*Wex)(nil)
+
+func (wex *Wex) withContext(ctx context.Context) *Wex {
+	c := *wex
+	c.client = HttpClientWithContext(ctx, wex.client)
+	return &c
+}
+
+func (wex *Wex) LimitBuyCtx(ctx context.Context, amount, price Decimal, currency CurrencyPair) (*Order, error) {
+	return wex.withContext(ctx).LimitBuy(amount, price, currency)
+}
+
+func (wex *Wex) LimitSellCtx(ctx context.Context, amount, price Decimal, currency CurrencyPair) (*Order, error) {
+	return wex.withContext(ctx).LimitSell(amount, price, currency)
+}
+
+func (wex *Wex) MarketBuyCtx(ctx context.Context, amount, price Decimal, currency CurrencyPair) (*Order, error) {
+	return wex.withContext(ctx).MarketBuy(amount, price, currency)
+}
+
+func (wex *Wex) MarketSellCtx(ctx context.Context, amount, price Decimal, currency CurrencyPair) (*Order, error) {
+	return wex.withContext(ctx).MarketSell(amount, price, currency)
+}
+
+func (wex *Wex) CancelOrderCtx(ctx context.Context, orderId string, currency CurrencyPair) (bool, error) {
+	return wex.withContext(ctx).CancelOrder(orderId, currency)
+}
+
+func (wex *Wex) GetOneOrderCtx(ctx context.Context, orderId string, currency CurrencyPair) (*Order, error) {
+	return wex.withContext(ctx).GetOneOrder(orderId, currency)
+}
+
+func (wex *Wex) GetUnfinishOrdersCtx(ctx context.Context, currency CurrencyPair) ([]Order, error) {
+	return wex.withContext(ctx).GetUnfinishOrders(currency)
+}
+
+func (wex *Wex) GetOrderHistorysCtx(ctx context.Context, currency CurrencyPair, currentPage, pageSize int) ([]Order, error) {
+	return wex.withContext(ctx).GetOrderHistorys(currency, currentPage, pageSize)
+}
+
+func (wex *Wex) GetAccountCtx(ctx context.Context) (*Account, error) {
+	return wex.withContext(ctx).GetAccount()
+}
+
+func (wex *Wex) GetTickerCtx(ctx context.Context, currency CurrencyPair) (*Ticker, error) {
+	return wex.withContext(ctx).GetTicker(currency)
+}
+
+func (wex *Wex) GetDepthCtx(ctx context.Context, size int, currency CurrencyPair) (*Depth, error) {
+	return wex.withContext(ctx).GetDepth(size, currency)
+}
+
+func (wex *Wex) GetKlineRecordsCtx(ctx context.Context, currency CurrencyPair, period, size, since int) ([]Kline, error) {
+	return wex.withContext(ctx).GetKlineRecords(currency, period, size, since)
+}
+
+func (wex *Wex) GetTradesCtx(ctx context.Context, currencyPair CurrencyPair, since int64) ([]Trade, error) {
+	return wex.withContext(ctx).GetTrades(currencyPair, since)
+}
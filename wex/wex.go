@@ -1,7 +1,6 @@
 package wex
 
 import (
-	"github.com/btcsuite/goleveldb/leveldb/errors"
 	. "github.com/nntaoli-project/GoEx"
 	"log"
 	"net/http"
@@ -21,45 +20,47 @@ var (
 )
 
 func New(client *http.Client, accesskey, secretkey string) *Wex {
+	client = ExchangeRateLimitedClient(client, "wex.nz", accesskey, DefaultRateLimitConfig)
 	return &Wex{client, accesskey, secretkey}
 }
 
-func (wex *Wex) LimitBuy(amount, price string, currency CurrencyPair) (*Order, error) {
-	panic("not implements")
+func (wex *Wex) LimitBuy(amount, price Decimal, currency CurrencyPair) (*Order, error) {
+	return nil, ErrNotSupported
 }
 
-func (wex *Wex) LimitSell(amount, price string, currency CurrencyPair) (*Order, error) {
-	panic("not implements")
+func (wex *Wex) LimitSell(amount, price Decimal, currency CurrencyPair) (*Order, error) {
+	return nil, ErrNotSupported
 }
 
-func (wex *Wex) MarketBuy(amount, price string, currency CurrencyPair) (*Order, error) {
-	panic("not implements")
+func (wex *Wex) MarketBuy(amount, price Decimal, currency CurrencyPair) (*Order, error) {
+	return nil, ErrNotSupported
 }
 
-func (wex *Wex) MarketSell(amount, price string, currency CurrencyPair) (*Order, error) {
-	panic("not implements")
+func (wex *Wex) MarketSell(amount, price Decimal, currency CurrencyPair) (*Order, error) {
+	return nil, ErrNotSupported
 }
 
 func (wex *Wex) CancelOrder(orderId string, currency CurrencyPair) (bool, error) {
-	panic("not implements")
+	return false, ErrNotSupported
 }
 
 func (wex *Wex) GetOneOrder(orderId string, currency CurrencyPair) (*Order, error) {
-	panic("not implements")
+	return nil, ErrNotSupported
 }
 func (wex *Wex) GetUnfinishOrders(currency CurrencyPair) ([]Order, error) {
-	panic("not implements")
+	return nil, ErrNotSupported
 }
 
 func (wex *Wex) GetOrderHistorys(currency CurrencyPair, currentPage, pageSize int) ([]Order, error) {
-	panic("not implements")
+	return nil, ErrNotSupported
 }
 
 func (wex *Wex) GetAccount() (*Account, error) {
-	panic("not implements")
+	return nil, ErrNotSupported
 }
 
 func (wex *Wex) GetTicker(currency CurrencyPair) (*Ticker, error) {
+	var dp DecimalParser
 	respmap, err := HttpGet(wex.client, baseurl+"/ticker/"+strings.ToLower(currency.ToSymbol("_")))
 	if err != nil {
 		return nil, err
@@ -67,36 +68,85 @@ func (wex *Wex) GetTicker(currency CurrencyPair) (*Ticker, error) {
 
 	if errmsg, isok := respmap["error"].(string); isok {
 		log.Println(errmsg)
-		return nil, errors.New(errmsg)
+		errCode := API_ERR
+		if strings.Contains(errmsg, "Invalid pair") {
+			errCode = EX_ERR_INVALID_CURRENCY_PAIR
+		}
+		errCode.OriginErrMsg = errmsg
+		return nil, errCode
 	}
 
 	for _, v := range respmap {
 		tickermap := v.(map[string]interface{})
-		return &Ticker{
-			Low:  ToFloat64(tickermap["low"]),
-			Buy:  ToFloat64(tickermap["buy"]),
-			Sell: ToFloat64(tickermap["sell"]),
-			Last: ToFloat64(tickermap["last"]),
-			Vol:  ToFloat64(tickermap["vol_cur"]),
-			High: ToFloat64(tickermap["high"])}, nil
+		ticker := &Ticker{
+			Low:  dp.Decimal(tickermap["low"]),
+			Buy:  dp.Decimal(tickermap["buy"]),
+			Sell: dp.Decimal(tickermap["sell"]),
+			Last: dp.Decimal(tickermap["last"]),
+			Vol:  dp.Decimal(tickermap["vol_cur"]),
+			High: dp.Decimal(tickermap["high"])}
+		if dp.Err != nil {
+			return nil, dp.Err
+		}
+		return ticker, nil
 	}
 
 	return nil, nil
 }
 
 func (wex *Wex) GetDepth(size int, currency CurrencyPair) (*Depth, error) {
-	return nil, nil
+	return nil, ErrNotSupported
 }
 
 func (wex *Wex) GetKlineRecords(currency CurrencyPair, period, size, since int) ([]Kline, error) {
-	panic("not implements")
+	return nil, ErrNotSupported
 }
 
 //非个人，整个交易所的交易记录
+//GetTrades answers the trades from since on among the latest 2000, wex keeps no older ones to page
 func (wex *Wex) GetTrades(currencyPair CurrencyPair, since int64) ([]Trade, error) {
-	panic("not implements")
+	var dp DecimalParser
+	pair := strings.ToLower(currencyPair.ToSymbol("_"))
+	respmap, err := HttpGet(wex.client, baseurl+"/trades/"+pair+"?limit=2000")
+	if err != nil {
+		return nil, err
+	}
+
+	if errmsg, isok := respmap["error"].(string); isok {
+		log.Println(errmsg)
+		errCode := API_ERR
+		if strings.Contains(errmsg, "Invalid pair") {
+			errCode = EX_ERR_INVALID_CURRENCY_PAIR
+		}
+		errCode.OriginErrMsg = errmsg
+		return nil, errCode
+	}
+
+	records, _ := respmap[pair].([]interface{})
+	trades := make([]Trade, 0, len(records))
+	for _, v := range records {
+		t := v.(map[string]interface{})
+		trade := Trade{
+			Tid:    ToInt64(t["tid"]),
+			Type:   "buy",
+			Amount: dp.Decimal(t["amount"]),
+			Price:  dp.Decimal(t["price"]),
+			Date:   ToInt64(t["timestamp"]) * 1000}
+		if t["type"] == "ask" {
+			trade.Type = "sell"
+		}
+		trades = append(trades, trade)
+	}
+	if dp.Err != nil {
+		return nil, dp.Err
+	}
+	return TradesSinceTime(trades, since), nil
 }
 
 func (wex *Wex) GetExchangeName() string {
 	return "wex.nz"
 }
+
+func (wex *Wex) Capabilities() Capabilities {
+	return Capabilities{Trades: true}
+}
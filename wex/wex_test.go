@@ -2,14 +2,50 @@ package wex
 
 import (
 	"net/http"
+	"net/http/httptest"
+	"os"
+	"strings"
 	"testing"
 	"github.com/nntaoli-project/GoEx"
+	"github.com/stretchr/testify/assert"
 )
 
-var wex = New(http.DefaultClient, "", "")
+var fixtures = goex.NewFixtureTransport("testdata/fixtures.json")
+var wex = New(fixtures.Client(), "", "")
 
 func TestWex_GetTicker(t *testing.T) {
 	ticker, err := wex.GetTicker(goex.BTC_USD)
-	t.Log("err=>", err)
-	t.Log("ticker=>", ticker)
+	assert.Nil(t, err)
+	assert.Equal(t, goex.Ticker{Last: goex.RequireDecimal("6404.919"), Buy: goex.RequireDecimal("6404.919"), Sell: goex.RequireDecimal("6399.001"),
+		High: goex.RequireDecimal("6512.998"), Low: goex.RequireDecimal("6190.01"), Vol: goex.RequireDecimal("475.58721")}, *ticker)
+}
+
+func TestMain(m *testing.M) {
+	os.Exit(fixtures.Run(m.Run))
+}
+
+//rewriteTransport sends every request to the stand-in server
+type rewriteTransport struct {
+	host string
+}
+
+func (rt rewriteTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	req.URL.Scheme = "http"
+	req.URL.Host = rt.host
+	return http.DefaultTransport.RoundTrip(req)
+}
+
+func TestWex_GetTrades(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "/api/3/trades/btc_usd", r.URL.Path)
+		assert.Equal(t, "2000", r.URL.Query().Get("limit"))
+		w.Write([]byte(`{"btc_usd":[{"type":"ask","price":9250,"amount":0.01,"tid":23549421,"timestamp":1525152612}]}`))
+	}))
+	defer srv.Close()
+	api := New(&http.Client{Transport: rewriteTransport{strings.TrimPrefix(srv.URL, "http://")}}, "", "")
+
+	trades, err := api.GetTrades(goex.BTC_USD, 0)
+	assert.Nil(t, err)
+	assert.Equal(t, []goex.Trade{{Tid: 23549421, Type: "sell", Amount: goex.RequireDecimal("0.01"), Price: goex.RequireDecimal("9250"),
+		Date: 1525152612000}}, trades)
 }
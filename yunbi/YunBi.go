@@ -7,10 +7,8 @@ import (
 	"log"
 	"io/ioutil"
 	"encoding/json"
-	"strconv"
 	"time"
 	"net/url"
-	"errors"
 	"strings"
 )
 
@@ -34,6 +32,7 @@ type YunBi struct {
 }
 
 func New(client *http.Client, apikey, secretkey string) *YunBi {
+	client = ExchangeRateLimitedClient(client, _EXCHANGE_NAME, apikey, DefaultRateLimitConfig)
 	return &YunBi{apikey, secretkey, client}
 }
 
@@ -41,15 +40,19 @@ func (yunbi *YunBi) GetExchangeName() string {
 	return _EXCHANGE_NAME
 }
 
+func (yunbi *YunBi) Capabilities() Capabilities {
+	return Capabilities{}
+}
+
 type _TickerResponse struct {
 	At     uint64 `json:"at"`
 	Ticker *struct {
-		Buy  float64 `json:"buy,string"`
-		Sell float64 `json:"sell,string"`
-		Low  float64 `json:"low,string"`
-		High float64 `json:"high,string"`
-		Last float64 `json:"last,string"`
-		Vol  float64 `json:"vol,string"`
+		Buy  Decimal `json:"buy"`
+		Sell Decimal `json:"sell"`
+		Low  Decimal `json:"low"`
+		High Decimal `json:"high"`
+		Last Decimal `json:"last"`
+		Vol  Decimal `json:"vol"`
 	}`json:"ticker"`
 }
 
@@ -88,6 +91,7 @@ func (yunbi *YunBi)GetTicker(currency CurrencyPair) (*Ticker, error) {
 }
 
 func (yunbi *YunBi)GetDepth(size int, currency CurrencyPair) (*Depth, error) {
+	var dp DecimalParser
 	urlStr := fmt.Sprintf(API_URL + API_URI_PREFIX + DEPTH_URL, convertCurrencyPair(currency), size)
 	respMap, err := HttpGet(yunbi.client, urlStr)
 	if err != nil {
@@ -102,9 +106,9 @@ func (yunbi *YunBi)GetDepth(size int, currency CurrencyPair) (*Depth, error) {
 		for i, vv := range v.([]interface{}) {
 			switch i {
 			case 0:
-				dr.Price, _ = strconv.ParseFloat(vv.(string), 64);
+				dr.Price = dp.Price(vv);
 			case 1:
-				dr.Amount, _ = strconv.ParseFloat(vv.(string), 64);
+				dr.Amount = dp.Decimal(vv);
 			}
 		}
 		depth.AskList = append(depth.AskList, dr);
@@ -115,18 +119,22 @@ func (yunbi *YunBi)GetDepth(size int, currency CurrencyPair) (*Depth, error) {
 		for i, vv := range v.([]interface{}) {
 			switch i {
 			case 0:
-				dr.Price, _ = strconv.ParseFloat(vv.(string), 64);
+				dr.Price = dp.Price(vv);
 			case 1:
-				dr.Amount, _ = strconv.ParseFloat(vv.(string), 64);
+				dr.Amount = dp.Decimal(vv);
 			}
 		}
 		depth.BidList = append(depth.BidList, dr);
 	}
 	
+	if dp.Err != nil {
+		return nil, dp.Err
+	}
 	return depth, nil
 }
 
 func (yunbi *YunBi)GetAccount() (*Account, error) {
+	var dp DecimalParser
 	urlStr := API_URL + API_URI_PREFIX + USER_INFO_URL;
 	postParams := url.Values{}
 	yunbi.buildPostForm("GET", API_URI_PREFIX + USER_INFO_URL, &postParams)
@@ -139,10 +147,7 @@ func (yunbi *YunBi)GetAccount() (*Account, error) {
 	
 	//log.Println(resp)
 	if resp["error"] != nil{
-		errmap := resp["error"].(map[string]interface{})
-		errcode := errmap["code"].(float64)
-		errmsg := errmap["message"].(string)
-		return nil , errors.New(fmt.Sprintf("%.0f:%s" , errcode , errmsg))
+		return nil, yunbi.errorWrapper(resp["error"])
 	}
 
 	acc := new(Account)
@@ -152,8 +157,8 @@ func (yunbi *YunBi)GetAccount() (*Account, error) {
 	for _, v := range accountsMap {
 		vv := v.(map[string]interface{})
 		subAcc := SubAccount{}
-		subAcc.Amount, _ = strconv.ParseFloat(vv["balance"].(string), 64)
-		subAcc.ForzenAmount, _ = strconv.ParseFloat(vv["locked"].(string), 64)
+		subAcc.Amount = dp.Decimal(vv["balance"])
+		subAcc.FrozenAmount = dp.Decimal(vv["locked"])
 		
 		var
 		(
@@ -188,26 +193,30 @@ func (yunbi *YunBi)GetAccount() (*Account, error) {
 		}
 	}
 	
+	if dp.Err != nil {
+		return nil, dp.Err
+	}
 	return acc, nil
 }
 
-func (yunbi *YunBi)LimitBuy(amount, price string, currency CurrencyPair) (*Order, error) {
-	return yunbi.placeOrder("buy", amount, price, currency)
+func (yunbi *YunBi)LimitBuy(amount, price Decimal, currency CurrencyPair) (*Order, error) {
+	return yunbi.placeOrder("buy", amount.String(), price.String(), currency)
 }
 
-func (yunbi *YunBi) LimitSell(amount, price string, currency CurrencyPair) (*Order, error) {
-	return yunbi.placeOrder("sell", amount, price, currency)
+func (yunbi *YunBi) LimitSell(amount, price Decimal, currency CurrencyPair) (*Order, error) {
+	return yunbi.placeOrder("sell", amount.String(), price.String(), currency)
 }
 
-func (yunbi *YunBi) MarketBuy(amount, price string, currency CurrencyPair) (*Order, error) {
-	return nil, nil
+func (yunbi *YunBi) MarketBuy(amount, price Decimal, currency CurrencyPair) (*Order, error) {
+	return nil, ErrNotSupported
 }
 
-func (yunbi *YunBi) MarketSell(amount, price string, currency CurrencyPair) (*Order, error) {
-	return nil, nil
+func (yunbi *YunBi) MarketSell(amount, price Decimal, currency CurrencyPair) (*Order, error) {
+	return nil, ErrNotSupported
 }
 
 func (yunbi *YunBi)placeOrder(side, amount, price string, currencyPair CurrencyPair) (*Order, error) {
+	var dp DecimalParser
 	params := url.Values{}
 	params.Set("market", convertCurrencyPair(currencyPair))
 	params.Set("side", side)
@@ -232,14 +241,14 @@ func (yunbi *YunBi)placeOrder(side, amount, price string, currencyPair CurrencyP
 	}
 	
 	if respMap["error"] != nil {
-		return nil, errors.New(string(resp))
+		return nil, yunbi.errorWrapper(respMap["error"])
 	}
 	
 	ord := new(Order)
 	ord.OrderID = int(respMap["id"].(float64))
 	ord.Currency = currencyPair
-	ord.Price, _ = strconv.ParseFloat(price, 64);
-	ord.Amount, _ = strconv.ParseFloat(amount, 64);
+	ord.Price = dp.Decimal(price);
+	ord.Amount = dp.Decimal(amount);
 	ord.Status = ORDER_UNFINISH;
 	ord.OrderTime = int(time.Now().Unix())
 	
@@ -250,6 +259,9 @@ func (yunbi *YunBi)placeOrder(side, amount, price string, currencyPair CurrencyP
 		ord.Side = SELL;
 	}
 	
+	if dp.Err != nil {
+		return nil, dp.Err
+	}
 	return ord, nil
 }
 
@@ -289,9 +301,12 @@ func (yunbi *YunBi)GetOneOrder(orderId string, currency CurrencyPair) (*Order, e
 
 	log.Println(respMap)
 
-	ord := yunbi.parseOrder(respMap)
+	ord, err := yunbi.parseOrder(respMap)
+	if err != nil {
+		return nil, err
+	}
 	ord.Currency = currency
-	return &ord, err
+	return &ord, nil
 }
 
 func (yunbi *YunBi) GetUnfinishOrders(currency CurrencyPair) ([]Order, error) {
@@ -318,7 +333,10 @@ func (yunbi *YunBi) GetUnfinishOrders(currency CurrencyPair) ([]Order, error) {
 
 	orders := make([]Order, 0)
 	for _, v := range ordersMap {
-		ord := yunbi.parseOrder(v)
+		ord, err := yunbi.parseOrder(v)
+		if err != nil {
+			return nil, err
+		}
 		ord.Currency = currency
 		orders = append(orders, ord)
 	}
@@ -329,24 +347,25 @@ func (yunbi *YunBi) GetUnfinishOrders(currency CurrencyPair) ([]Order, error) {
 }
 
 func (yunbi *YunBi) GetOrderHistorys(currency CurrencyPair, currentPage, pageSize int) ([]Order, error) {
-	return nil, nil
+	return nil, ErrNotSupported
 }
 
 func (yunbi *YunBi) GetKlineRecords(currency CurrencyPair, period , size, since int) ([]Kline, error) {
-	return nil, nil
+	return nil, ErrNotSupported
 }
 
 func (yunbi *YunBi) GetTrades(currencyPair CurrencyPair, since int64) ([]Trade, error) {
-	panic("unimplements")
+	return nil, ErrNotSupported
 }
 
-func (yunbi *YunBi)parseOrder(orderMap map[string]interface{}) Order {
+func (yunbi *YunBi)parseOrder(orderMap map[string]interface{}) (Order, error) {
+	var dp DecimalParser
 	ord := Order{}
 	ord.OrderID = int(orderMap["id"].(float64))
-	ord.Amount, _ = strconv.ParseFloat(orderMap["volume"].(string), 64)
-	ord.Price, _ = strconv.ParseFloat(orderMap["price"].(string), 64)
-	ord.AvgPrice, _ = strconv.ParseFloat(orderMap["avg_price"].(string), 64)
-	ord.DealAmount, _ = strconv.ParseFloat(orderMap["executed_volume"].(string), 64)
+	ord.Amount = dp.Decimal(orderMap["volume"])
+	ord.Price = dp.Decimal(orderMap["price"])
+	ord.AvgPrice = dp.Decimal(orderMap["avg_price"])
+	ord.DealAmount = dp.Decimal(orderMap["executed_volume"])
 
 	switch orderMap["state"].(string) {
 	case "wait":
@@ -365,7 +384,7 @@ func (yunbi *YunBi)parseOrder(orderMap map[string]interface{}) Order {
 	case "sell":
 		ord.Side = SELL
 	}
-	return ord
+	return ord, dp.Err
 }
 
 func (yunbi *YunBi) buildPostForm(httpMethod, apiURI string, postForm *url.Values) error {
@@ -388,4 +407,30 @@ func (yunbi *YunBi) buildPostForm(httpMethod, apiURI string, postForm *url.Value
 
 func convertCurrencyPair(currencyPair CurrencyPair) string {
 	return strings.ToLower(currencyPair.ToSymbol(""))
-}
\ No newline at end of file
+}
+
+func (yunbi *YunBi) errorWrapper(respErr interface{}) ApiError {
+	errmap, _ := respErr.(map[string]interface{})
+	errcode := ToInt(errmap["code"])
+	errmsg, _ := errmap["message"].(string)
+
+	var errCode ApiError
+	switch errcode {
+	case 2002:
+		errCode = EX_ERR_INSUFFICIENT_BALANCE
+	case 2003:
+		errCode = EX_ERR_CANCEL_ORDER_FAIL
+	case 2004:
+		errCode = EX_ERR_NOT_FIND_ORDER
+	case 2005:
+		errCode = EX_ERR_SIGN
+	case 2006:
+		errCode = EX_ERR_NONCE
+	case 2008:
+		errCode = EX_ERR_NOT_FIND_APIKEY
+	default:
+		errCode = API_ERR
+	}
+	errCode.OriginErrMsg = fmt.Sprintf("%d:%s", errcode, errmsg)
+	return errCode
+}
@@ -0,0 +1,68 @@
+package yunbi
+
+import (
+	"context"
+
+	. "github.com/nntaoli-project/GoEx"
+)
+
+var _ API = (*YunBi)(nil)
+var _ APIWithContext = (*YunBi)(nil)
+
+func (yunbi *YunBi) withContext(ctx context.Context) *YunBi {
+	c := *yunbi
+	c.client = HttpClientWithContext(ctx, yunbi.client)
+	return &c
+}
+
+func (yunbi *YunBi) LimitBuyCtx(ctx context.Context, amount, price Decimal, currency CurrencyPair) (*Order, error) {
+	return yunbi.withContext(ctx).LimitBuy(amount, price, currency)
+}
+
+func (yunbi *YunBi) LimitSellCtx(ctx context.Context, amount, price Decimal, currency CurrencyPair) (*Order, error) {
+	return yunbi.withContext(ctx).LimitSell(amount, price, currency)
+}
+
+func (yunbi *YunBi) MarketBuyCtx(ctx context.Context, amount, price Decimal, currency CurrencyPair) (*Order, error) {
+	return yunbi.withContext(ctx).MarketBuy(amount, price, currency)
+}
+
+func (yunbi *YunBi) MarketSellCtx(ctx context.Context, amount, price Decimal, currency CurrencyPair) (*Order, error) {
+	return yunbi.withContext(ctx).MarketSell(amount, price, currency)
+}
+
+func (yunbi *YunBi) CancelOrderCtx(ctx context.Context, orderId string, currency CurrencyPair) (bool, error) {
+	return yunbi.withContext(ctx).CancelOrder(orderId, currency)
+}
+
+func (yunbi *YunBi) GetOneOrderCtx(ctx context.Context, orderId string, currency CurrencyPair) (*Order, error) {
+	return yunbi.withContext(ctx).GetOneOrder(orderId, currency)
+}
+
+func (yunbi *YunBi) GetUnfinishOrdersCtx(ctx context.Context, currency CurrencyPair) ([]Order, error) {
+	return yunbi.withContext(ctx).GetUnfinishOrders(currency)
+}
+
+func (yunbi *YunBi) GetOrderHistorysCtx(ctx context.Context, currency CurrencyPair, currentPage, pageSize int) ([]Order, error) {
+	return yunbi.withContext(ctx).GetOrderHistorys(currency, currentPage, pageSize)
+}
+
+func (yunbi *YunBi) GetAccountCtx(ctx context.Context) (*Account, error) {
+	return yunbi.withContext(ctx).GetAccount()
+}
+
+func (yunbi *YunBi) GetTickerCtx(ctx context.Context, currency CurrencyPair) (*Ticker, error) {
+	return yunbi.withContext(ctx).GetTicker(currency)
+}
+
+func (yunbi *YunBi) GetDepthCtx(ctx context.Context, size int, currency CurrencyPair) (*Depth, error) {
+	return yunbi.withContext(ctx).GetDepth(size, currency)
+}
+
+func (yunbi *YunBi) GetKlineRecordsCtx(ctx context.Context, currency CurrencyPair, period, size, since int) ([]Kline, error) {
+	return yunbi.withContext(ctx).GetKlineRecords(currency, period, size, since)
+}
+
+func (yunbi *YunBi) GetTradesCtx(ctx context.Context, currencyPair CurrencyPair, since int64) ([]Trade, error) {
+	return yunbi.withContext(ctx).GetTrades(currencyPair, since)
+}
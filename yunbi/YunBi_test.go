@@ -1,17 +1,43 @@
 package yunbi
 
 import (
-	"testing"
-	"net/http"
+	"fmt"
 	. "github.com/nntaoli-project/GoEx"
+	"github.com/stretchr/testify/assert"
+	"os"
+	"testing"
 )
 
 var (
-	yb = New(http.DefaultClient, "", "")
+	fixtures = NewFixtureTransport("testdata/fixtures.json")
+	yb       = New(fixtures.Client(), "", "")
 )
 
 func TestYunBi_GetTicker(t *testing.T) {
-	t.Log(yb.GetTicker(BTS_CNY))
-	t.Log(yb.GetTicker(SC_CNY))
-	t.Log(yb.GetTicker(EOS_CNY))
+	for pair, last := range map[CurrencyPair]string{BTS_CNY: "0.2311", SC_CNY: "0.0667", EOS_CNY: "13.52"} {
+		ticker, err := yb.GetTicker(pair)
+		assert.Nil(t, err)
+		assert.Equal(t, RequireDecimal(last), ticker.Last, pair.String())
+		assert.Equal(t, uint64(1504686312), ticker.Date)
+	}
+}
+
+func TestMain(m *testing.M) {
+	os.Exit(fixtures.Run(m.Run))
+}
+
+func TestYunBi_errorWrapper(t *testing.T) {
+	for code, errCode := range map[int]ApiError{
+		2002: EX_ERR_INSUFFICIENT_BALANCE,
+		2003: EX_ERR_CANCEL_ORDER_FAIL,
+		2004: EX_ERR_NOT_FIND_ORDER,
+		2005: EX_ERR_SIGN,
+		2006: EX_ERR_NONCE,
+		2008: EX_ERR_NOT_FIND_APIKEY,
+		1001: API_ERR,
+	} {
+		err := yb.errorWrapper(map[string]interface{}{"code": float64(code), "message": "error"})
+		assert.True(t, errCode.Is(err), "%d", code)
+		assert.Equal(t, fmt.Sprintf("%d:error", code), err.OriginErrMsg)
+	}
 }
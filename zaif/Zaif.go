@@ -1,6 +1,7 @@
 package zaif
 
 import (
+	"encoding/json"
 	"fmt"
 	. "github.com/nntaoli-project/GoEx"
 	"log"
@@ -17,6 +18,7 @@ type Zaif struct {
 }
 
 func New(httpClient *http.Client, accessKey, secretKey string) *Zaif {
+	httpClient = ExchangeRateLimitedClient(httpClient, "zaif.jp", accessKey, DefaultRateLimitConfig)
 	zaif := new(Zaif)
 	zaif.accessKey = accessKey
 	zaif.secretKey = secretKey
@@ -29,31 +31,46 @@ func (zf *Zaif) GetExchangeName() string {
 	return "zaif.jp"
 }
 
+func (zf *Zaif) Capabilities() Capabilities {
+	return Capabilities{}
+}
+
 func (zf *Zaif) GetTicker(currency CurrencyPair) (*Ticker, error) {
+	var dp DecimalParser
 	tickerUrl := fmt.Sprintf(zf.baseUrl+"1/ticker/%s_jpy", strings.ToLower(currency.CurrencyA.Symbol))
 	//println(tickerUrl)
 	resp, err := HttpGet(zf.client, tickerUrl)
 	if err != nil {
 		log.Print(err)
-		return nil, err
+		return nil, zf.adaptError(err)
+	}
+	if errmsg, isok := resp["error"].(string); isok {
+		return nil, zf.errorWrapper(errmsg)
 	}
 	//log.Println(resp)
 	ticker := new(Ticker)
-	ticker.Buy = resp["bid"].(float64)
-	ticker.Sell = resp["ask"].(float64)
-	ticker.Last = resp["last"].(float64)
-	ticker.High = resp["high"].(float64)
-	ticker.Low = resp["low"].(float64)
-	ticker.Vol = resp["volume"].(float64)
+	ticker.Buy = dp.Decimal(resp["bid"])
+	ticker.Sell = dp.Decimal(resp["ask"])
+	ticker.Last = dp.Decimal(resp["last"])
+	ticker.High = dp.Decimal(resp["high"])
+	ticker.Low = dp.Decimal(resp["low"])
+	ticker.Vol = dp.Decimal(resp["volume"])
+	if dp.Err != nil {
+		return nil, dp.Err
+	}
 	return ticker, nil
 }
 
 func (zf *Zaif) GetDepth(size int, currency CurrencyPair) (*Depth, error) {
+	var dp DecimalParser
 	depthUrl := fmt.Sprintf(zf.baseUrl+"1/depth/%s_jpy", strings.ToLower(currency.CurrencyA.Symbol))
 	resp, err := HttpGet(zf.client, depthUrl)
 	if err != nil {
 		log.Println(err)
-		return nil, err
+		return nil, zf.adaptError(err)
+	}
+	if errmsg, isok := resp["error"].(string); isok {
+		return nil, zf.errorWrapper(errmsg)
 	}
 	//log.Println(resp)
 	var depth Depth
@@ -68,9 +85,9 @@ func (zf *Zaif) GetDepth(size int, currency CurrencyPair) (*Depth, error) {
 		for i, vv := range v.([]interface{}) {
 			switch i {
 			case 0:
-				dr.Price = vv.(float64)
+				dr.Price = dp.Price(vv)
 			case 1:
-				dr.Amount = vv.(float64)
+				dr.Amount = dp.Decimal(vv)
 			}
 		}
 		depth.AskList = append(depth.AskList, dr)
@@ -88,9 +105,9 @@ func (zf *Zaif) GetDepth(size int, currency CurrencyPair) (*Depth, error) {
 		for i, vv := range v.([]interface{}) {
 			switch i {
 			case 0:
-				dr.Price = vv.(float64)
+				dr.Price = dp.Price(vv)
 			case 1:
-				dr.Amount = vv.(float64)
+				dr.Amount = dp.Decimal(vv)
 			}
 		}
 		depth.BidList = append(depth.BidList, dr)
@@ -101,50 +118,86 @@ func (zf *Zaif) GetDepth(size int, currency CurrencyPair) (*Depth, error) {
 		}
 	}
 
+	if dp.Err != nil {
+		return nil, dp.Err
+	}
 	return &depth, nil
 }
 
-func (zf *Zaif) LimitBuy(amount, price string, currency CurrencyPair) (*Order, error) {
-	return nil, nil
+func (zf *Zaif) LimitBuy(amount, price Decimal, currency CurrencyPair) (*Order, error) {
+	return nil, ErrNotSupported
 }
 
-func (zf *Zaif) LimitSell(amount, price string, currency CurrencyPair) (*Order, error) {
-	return nil, nil
+func (zf *Zaif) LimitSell(amount, price Decimal, currency CurrencyPair) (*Order, error) {
+	return nil, ErrNotSupported
 }
 
-func (zf *Zaif) MarketBuy(amount, price string, currency CurrencyPair) (*Order, error) {
-	return nil, nil
+func (zf *Zaif) MarketBuy(amount, price Decimal, currency CurrencyPair) (*Order, error) {
+	return nil, ErrNotSupported
 }
 
-func (zf *Zaif) MarketSell(amount, price string, currency CurrencyPair) (*Order, error) {
-	return nil, nil
+func (zf *Zaif) MarketSell(amount, price Decimal, currency CurrencyPair) (*Order, error) {
+	return nil, ErrNotSupported
 }
 
 func (zf *Zaif) CancelOrder(orderId string, currency CurrencyPair) (bool, error) {
-	return false, nil
+	return false, ErrNotSupported
 }
 
 func (zf *Zaif) GetOneOrder(orderId string, currency CurrencyPair) (*Order, error) {
-	return nil, nil
+	return nil, ErrNotSupported
 }
 
 func (zf *Zaif) GetUnfinishOrders(currency CurrencyPair) ([]Order, error) {
-	return nil, nil
+	return nil, ErrNotSupported
 }
 
 func (zf *Zaif) GetOrderHistorys(currency CurrencyPair, currentPage, pageSize int) ([]Order, error) {
-	return nil, nil
+	return nil, ErrNotSupported
 }
 
 func (zf *Zaif) GetAccount() (*Account, error) {
-	return nil, nil
+	return nil, ErrNotSupported
 }
 
 func (zf *Zaif) GetKlineRecords(currency CurrencyPair , period int, size, since int) ([]Kline, error) {
-	return nil, nil
+	return nil, ErrNotSupported
 }
 
 //非个人，整个交易所的交易记录
 func (zf *Zaif) GetTrades(currencyPair CurrencyPair, since int64) ([]Trade, error) {
-	return nil, nil
+	return nil, ErrNotSupported
+}
+
+//zaif reports failures as {"error":"unsupported currency_pair"}
+func (zf *Zaif) errorWrapper(message string) ApiError {
+	var errCode ApiError
+	switch {
+	case strings.Contains(message, "currency_pair"):
+		errCode = EX_ERR_INVALID_CURRENCY_PAIR
+	case strings.Contains(message, "time wait restriction"), strings.Contains(message, "too many"):
+		errCode = EX_ERR_API_LIMIT
+	case strings.Contains(message, "maintenance"):
+		errCode = EX_ERR_MAINTENANCE
+	default:
+		errCode = API_ERR
+	}
+	errCode.OriginErrMsg = message
+	return errCode
+}
+
+//zaif sends the same {"error":"..."} with a non-200 status too
+func (zf *Zaif) adaptError(err error) error {
+	apiErr, isok := err.(ApiError)
+	if !isok {
+		return err
+	}
+
+	var resp struct {
+		Error string `json:"error"`
+	}
+	if json.Unmarshal([]byte(apiErr.OriginErrMsg), &resp) != nil || resp.Error == "" {
+		return err
+	}
+	return zf.errorWrapper(resp.Error)
 }
@@ -0,0 +1,68 @@
+package zaif
+
+import (
+	"context"
+
+	. "github.com/nntaoli-project/GoEx"
+)
+
+var _ API = (*Zaif)(nil)
+var _ APIWithContext = (*Zaif)(nil)
+
+func (zf *Zaif) withContext(ctx context.Context) *Zaif {
+	c := *zf
+	c.client = HttpClientWithContext(ctx, zf.client)
+	return &c
+}
+
+func (zf *Zaif) LimitBuyCtx(ctx context.Context, amount, price Decimal, currency CurrencyPair) (*Order, error) {
+	return zf.withContext(ctx).LimitBuy(amount, price, currency)
+}
+
+func (zf *Zaif) LimitSellCtx(ctx context.Context, amount, price Decimal, currency CurrencyPair) (*Order, error) {
+	return zf.withContext(ctx).LimitSell(amount, price, currency)
+}
+
+func (zf *Zaif) MarketBuyCtx(ctx context.Context, amount, price Decimal, currency CurrencyPair) (*Order, error) {
+	return zf.withContext(ctx).MarketBuy(amount, price, currency)
+}
+
+func (zf *Zaif) MarketSellCtx(ctx context.Context, amount, price Decimal, currency CurrencyPair) (*Order, error) {
+	return zf.withContext(ctx).MarketSell(amount, price, currency)
+}
+
+func (zf *Zaif) CancelOrderCtx(ctx context.Context, orderId string, currency CurrencyPair) (bool, error) {
+	return zf.withContext(ctx).CancelOrder(orderId, currency)
+}
+
+func (zf *Zaif) GetOneOrderCtx(ctx context.Context, orderId string, currency CurrencyPair) (*Order, error) {
+	return zf.withContext(ctx).GetOneOrder(orderId, currency)
+}
+
+func (zf *Zaif) GetUnfinishOrdersCtx(ctx context.Context, currency CurrencyPair) ([]Order, error) {
+	return zf.withContext(ctx).GetUnfinishOrders(currency)
+}
+
+func (zf *Zaif) GetOrderHistorysCtx(ctx context.Context, currency CurrencyPair, currentPage, pageSize int) ([]Order, error) {
+	return zf.withContext(ctx).GetOrderHistorys(currency, currentPage, pageSize)
+}
+
+func (zf *Zaif) GetAccountCtx(ctx context.Context) (*Account, error) {
+	return zf.withContext(ctx).GetAccount()
+}
+
+func (zf *Zaif) GetTickerCtx(ctx context.Context, currency CurrencyPair) (*Ticker, error) {
+	return zf.withContext(ctx).GetTicker(currency)
+}
+
+func (zf *Zaif) GetDepthCtx(ctx context.Context, size int, currency CurrencyPair) (*Depth, error) {
+	return zf.withContext(ctx).GetDepth(size, currency)
+}
+
+func (zf *Zaif) GetKlineRecordsCtx(ctx context.Context, currency CurrencyPair, period, size, since int) ([]Kline, error) {
+	return zf.withContext(ctx).GetKlineRecords(currency, period, size, since)
+}
+
+func (zf *Zaif) GetTradesCtx(ctx context.Context, currencyPair CurrencyPair, since int64) ([]Trade, error) {
+	return zf.withContext(ctx).GetTrades(currencyPair, since)
+}
@@ -3,21 +3,58 @@ package zaif
 import (
 	"github.com/nntaoli-project/GoEx"
 	"github.com/stretchr/testify/assert"
-	"net/http"
+	"os"
 	"testing"
 )
 
-var api = New(http.DefaultClient, "", "")
-var BTC_JPY = goex.NewCurrencyPair(goex.BTC, goex.NewCurrency("JPY", ""))
+var fixtures = goex.NewFixtureTransport("testdata/fixtures.json")
+var api = New(fixtures.Client(), "", "")
 
 func TestZaif_GetTicker(t *testing.T) {
-	ticker, err := api.GetTicker(BTC_JPY)
+	ticker, err := api.GetTicker(goex.BTC_JPY)
 	assert.Empty(t, err)
-	t.Log(ticker)
+	assert.Equal(t, goex.Ticker{Last: goex.RequireDecimal("686060"), Buy: goex.RequireDecimal("686030"), Sell: goex.RequireDecimal("686060"),
+		High: goex.RequireDecimal("693585"), Low: goex.RequireDecimal("667000"), Vol: goex.RequireDecimal("9301.2841")}, *ticker)
 }
 
 func TestZaif_GetDepth(t *testing.T) {
-	depth, err := api.GetDepth(4, BTC_JPY)
+	depth, err := api.GetDepth(4, goex.BTC_JPY)
 	assert.Empty(t, err)
-	t.Log(depth)
+	//the best ask last
+	assert.Equal(t, goex.DepthRecords{
+		{Price: goex.RequireDecimal("686100"), Amount: goex.RequireDecimal("1.2174")},
+		{Price: goex.RequireDecimal("686090"), Amount: goex.RequireDecimal("0.3")},
+		{Price: goex.RequireDecimal("686065"), Amount: goex.RequireDecimal("0.01")},
+		{Price: goex.RequireDecimal("686060"), Amount: goex.RequireDecimal("0.0199")}}, depth.AskList)
+	assert.Equal(t, goex.DepthRecords{
+		{Price: goex.RequireDecimal("686030"), Amount: goex.RequireDecimal("0.1")},
+		{Price: goex.RequireDecimal("686025"), Amount: goex.RequireDecimal("0.0428")},
+		{Price: goex.RequireDecimal("686005"), Amount: goex.RequireDecimal("0.5")},
+		{Price: goex.RequireDecimal("686000"), Amount: goex.RequireDecimal("2.0355")}}, depth.BidList)
+}
+
+func TestMain(m *testing.M) {
+	os.Exit(fixtures.Run(m.Run))
+}
+
+func TestZaif_errorWrapper(t *testing.T) {
+	for message, errCode := range map[string]goex.ApiError{
+		"unsupported currency_pair":                goex.EX_ERR_INVALID_CURRENCY_PAIR,
+		"time wait restriction, please try later.": goex.EX_ERR_API_LIMIT,
+		"under maintenance":                        goex.EX_ERR_MAINTENANCE,
+		"invalid key":                              goex.API_ERR,
+	} {
+		err := api.errorWrapper(message)
+		assert.True(t, errCode.Is(err), message)
+		assert.Equal(t, message, err.OriginErrMsg)
+	}
+}
+
+func TestZaif_adaptError(t *testing.T) {
+	httpErr := goex.HTTP_ERR_CODE
+	httpErr.OriginErrMsg = `{"error":"unsupported currency_pair"}`
+	assert.True(t, goex.EX_ERR_INVALID_CURRENCY_PAIR.Is(api.adaptError(httpErr)))
+
+	httpErr.OriginErrMsg = "Bad Gateway"
+	assert.Equal(t, error(httpErr), api.adaptError(httpErr))
 }
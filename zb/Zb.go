@@ -1,7 +1,6 @@
 package zb
 
 import (
-	"errors"
 	"fmt"
 	. "github.com/nntaoli-project/GoEx"
 	"net/http"
@@ -31,6 +30,7 @@ type ZB struct {
 }
 
 func New(httpClient *http.Client, accessKey, secretKey string) *ZB {
+	httpClient = ExchangeRateLimitedClient(httpClient, EXCHANGE_NAME, accessKey, DefaultRateLimitConfig)
 	return &ZB{httpClient, accessKey, secretKey}
 }
 
@@ -38,7 +38,12 @@ func (zb *ZB) GetExchangeName() string {
 	return EXCHANGE_NAME
 }
 
+func (zb *ZB) Capabilities() Capabilities {
+	return Capabilities{}
+}
+
 func (zb *ZB) GetTicker(currency CurrencyPair) (*Ticker, error) {
+	var dp DecimalParser
 	//log.Println("ZB###")
 	resp, err := HttpGet(zb.httpClient, MARKET_URL+fmt.Sprintf(TICKER_API, strings.ToLower(currency.ToSymbol("_"))))
 	if err != nil {
@@ -49,22 +54,25 @@ func (zb *ZB) GetTicker(currency CurrencyPair) (*Ticker, error) {
 	result, ok := resp["result"].(bool)
 	if ok == true && result == false {
 		//log.Println("err:", "{\"message\":\"服务端忙碌\",\"result\":false}")
-		return nil, errors.New("server busy")
+		return nil, zb.errorWrapper(resp)
 	}
 
 	tickermap, ok := resp["ticker"].(map[string]interface{})
 	if ok != true {
-		return nil, errors.New("no ticker")
+		return nil, zb.errorWrapper(resp)
 	}
 	ticker := new(Ticker)
 	ticker.Date = ToUint64(resp["date"])
-	ticker.Buy = ToFloat64(tickermap["buy"])
-	ticker.Sell = ToFloat64(tickermap["sell"])
-	ticker.Last = ToFloat64(tickermap["last"])
-	ticker.High = ToFloat64(tickermap["high"])
-	ticker.Low = ToFloat64(tickermap["low"])
-	ticker.Vol = ToFloat64(tickermap["vol"])
+	ticker.Buy = dp.Decimal(tickermap["buy"])
+	ticker.Sell = dp.Decimal(tickermap["sell"])
+	ticker.Last = dp.Decimal(tickermap["last"])
+	ticker.High = dp.Decimal(tickermap["high"])
+	ticker.Low = dp.Decimal(tickermap["low"])
+	ticker.Vol = dp.Decimal(tickermap["vol"])
 	//log.Println("ZB####", ticker)
+	if dp.Err != nil {
+		return nil, dp.Err
+	}
 	return ticker, nil
 }
 func (zb *ZB) GetTickers(currency CurrencyPair) (*Ticker, error) {
@@ -75,50 +83,71 @@ func (zb *ZB) GetTickerInBuf(currency CurrencyPair) (*Ticker, error) {
 	return zb.GetTicker(currency)
 }
 
-func (zb *ZB) LimitBuy(amount, price string, currency CurrencyPair) (*Order, error) {
-	panic("not implements")
+func (zb *ZB) LimitBuy(amount, price Decimal, currency CurrencyPair) (*Order, error) {
+	return nil, ErrNotSupported
 }
 
-func (zb *ZB) LimitSell(amount, price string, currency CurrencyPair) (*Order, error) {
-	panic("not implements")
+func (zb *ZB) LimitSell(amount, price Decimal, currency CurrencyPair) (*Order, error) {
+	return nil, ErrNotSupported
 }
 
-func (zb *ZB) MarketBuy(amount, price string, currency CurrencyPair) (*Order, error) {
-	panic("not implements")
+func (zb *ZB) MarketBuy(amount, price Decimal, currency CurrencyPair) (*Order, error) {
+	return nil, ErrNotSupported
 }
 
-func (zb *ZB) MarketSell(amount, price string, currency CurrencyPair) (*Order, error) {
-	panic("not implements")
+func (zb *ZB) MarketSell(amount, price Decimal, currency CurrencyPair) (*Order, error) {
+	return nil, ErrNotSupported
 }
 
 func (zb *ZB) CancelOrder(orderId string, currency CurrencyPair) (bool, error) {
-	panic("not implements")
+	return false, ErrNotSupported
 }
 
 func (zb *ZB) GetOneOrder(orderId string, currency CurrencyPair) (*Order, error) {
-	panic("not implements")
+	return nil, ErrNotSupported
 }
 func (zb *ZB) GetUnfinishOrders(currency CurrencyPair) ([]Order, error) {
-	panic("not implements")
+	return nil, ErrNotSupported
 }
 
 func (zb *ZB) GetOrderHistorys(currency CurrencyPair, currentPage, pageSize int) ([]Order, error) {
-	panic("not implements")
+	return nil, ErrNotSupported
 }
 
 func (zb *ZB) GetAccount() (*Account, error) {
-	panic("not implements")
+	return nil, ErrNotSupported
 }
 
 func (zb *ZB) GetDepth(size int, currency CurrencyPair) (*Depth, error) {
-	return nil, nil
+	return nil, ErrNotSupported
 }
 
 func (zb *ZB) GetKlineRecords(currency CurrencyPair, period, size, since int) ([]Kline, error) {
-	panic("not implements")
+	return nil, ErrNotSupported
 }
 
 //非个人，整个交易所的交易记录
 func (zb *ZB) GetTrades(currencyPair CurrencyPair, since int64) ([]Trade, error) {
-	panic("not implements")
+	return nil, ErrNotSupported
+}
+
+//zb answers {"result":false,"message":"服务端忙碌"} when busy and {"error":"市场错误"} for an unknown market
+func (zb *ZB) errorWrapper(resp map[string]interface{}) ApiError {
+	var errCode ApiError
+	message, isok := resp["message"].(string)
+	if !isok {
+		message = fmt.Sprint(resp["error"])
+	}
+	switch {
+	case strings.Contains(message, "忙碌"), strings.Contains(message, "维护"):
+		errCode = EX_ERR_MAINTENANCE
+	case strings.Contains(message, "频繁"):
+		errCode = EX_ERR_API_LIMIT
+	case strings.Contains(message, "市场"):
+		errCode = EX_ERR_INVALID_CURRENCY_PAIR
+	default:
+		errCode = API_ERR
+	}
+	errCode.OriginErrMsg = message
+	return errCode
 }
@@ -0,0 +1,68 @@
+package zb
+
+import (
+	"context"
+
+	. "github.com/nntaoli-project/GoEx"
+)
+
+var _ API = (*ZB)(nil)
+var _ APIWithContext = (*ZB)(nil)
+
+func (zb *ZB) withContext(ctx context.Context) *ZB {
+	c := *zb
+	c.httpClient = HttpClientWithContext(ctx, zb.httpClient)
+	return &c
+}
+
+func (zb *ZB) LimitBuyCtx(ctx context.Context, amount, price Decimal, currency CurrencyPair) (*Order, error) {
+	return zb.withContext(ctx).LimitBuy(amount, price, currency)
+}
+
+func (zb *ZB) LimitSellCtx(ctx context.Context, amount, price Decimal, currency CurrencyPair) (*Order, error) {
+	return zb.withContext(ctx).LimitSell(amount, price, currency)
+}
+
+func (zb *ZB) MarketBuyCtx(ctx context.Context, amount, price Decimal, currency CurrencyPair) (*Order, error) {
+	return zb.withContext(ctx).MarketBuy(amount, price, currency)
+}
+
+func (zb *ZB) MarketSellCtx(ctx context.Context, amount, price Decimal, currency CurrencyPair) (*Order, error) {
+	return zb.withContext(ctx).MarketSell(amount, price, currency)
+}
+
+func (zb *ZB) CancelOrderCtx(ctx context.Context, orderId string, currency CurrencyPair) (bool, error) {
+	return zb.withContext(ctx).CancelOrder(orderId, currency)
+}
+
+func (zb *ZB) GetOneOrderCtx(ctx context.Context, orderId string, currency CurrencyPair) (*Order, error) {
+	return zb.withContext(ctx).GetOneOrder(orderId, currency)
+}
+
+func (zb *ZB) GetUnfinishOrdersCtx(ctx context.Context, currency CurrencyPair) ([]Order, error) {
+	return zb.withContext(ctx).GetUnfinishOrders(currency)
+}
+
+func (zb *ZB) GetOrderHistorysCtx(ctx context.Context, currency CurrencyPair, currentPage, pageSize int) ([]Order, error) {
+	return zb.withContext(ctx).GetOrderHistorys(currency, currentPage, pageSize)
+}
+
+func (zb *ZB) GetAccountCtx(ctx context.Context) (*Account, error) {
+	return zb.withContext(ctx).GetAccount()
+}
+
+func (zb *ZB) GetTickerCtx(ctx context.Context, currency CurrencyPair) (*Ticker, error) {
+	return zb.withContext(ctx).GetTicker(currency)
+}
+
+func (zb *ZB) GetDepthCtx(ctx context.Context, size int, currency CurrencyPair) (*Depth, error) {
+	return zb.withContext(ctx).GetDepth(size, currency)
+}
+
+func (zb *ZB) GetKlineRecordsCtx(ctx context.Context, currency CurrencyPair, period, size, since int) ([]Kline, error) {
+	return zb.withContext(ctx).GetKlineRecords(currency, period, size, since)
+}
+
+func (zb *ZB) GetTradesCtx(ctx context.Context, currencyPair CurrencyPair, since int64) ([]Trade, error) {
+	return zb.withContext(ctx).GetTrades(currencyPair, since)
+}
@@ -2,14 +2,40 @@ package zb
 
 import (
 	"github.com/nntaoli-project/GoEx"
-	"net/http"
+	"github.com/stretchr/testify/assert"
+	"os"
 	"testing"
 )
 
-var zb = New(http.DefaultClient, "", "")
+var fixtures = goex.NewFixtureTransport("testdata/fixtures.json")
+var zb = New(fixtures.Client(), "", "")
 
 func TestZb_GetTicker(t *testing.T) {
 	ticker, err := zb.GetTicker(goex.BTC_USDT)
-	t.Log("err=>", err)
-	t.Log("ticker=>", ticker)
+	assert.Nil(t, err)
+	assert.Equal(t, uint64(1529920350498), ticker.Date)
+	assert.Equal(t, goex.RequireDecimal("6270.53"), ticker.Buy)
+	assert.Equal(t, goex.RequireDecimal("6273.86"), ticker.Sell)
+	assert.Equal(t, goex.RequireDecimal("6270.53"), ticker.Last)
+	assert.Equal(t, goex.RequireDecimal("6350"), ticker.High)
+	assert.Equal(t, goex.RequireDecimal("6101.42"), ticker.Low)
+	assert.Equal(t, goex.RequireDecimal("2139.5617"), ticker.Vol)
+}
+
+func TestMain(m *testing.M) {
+	os.Exit(fixtures.Run(m.Run))
+}
+
+func TestZB_errorWrapper(t *testing.T) {
+	for _, c := range []struct {
+		resp    map[string]interface{}
+		errCode goex.ApiError
+	}{
+		{map[string]interface{}{"result": false, "message": "服务端忙碌"}, goex.EX_ERR_MAINTENANCE},
+		{map[string]interface{}{"result": false, "message": "请求过于频繁"}, goex.EX_ERR_API_LIMIT},
+		{map[string]interface{}{"error": "市场错误"}, goex.EX_ERR_INVALID_CURRENCY_PAIR},
+		{map[string]interface{}{}, goex.API_ERR},
+	} {
+		assert.True(t, c.errCode.Is(zb.errorWrapper(c.resp)), "%v", c.resp)
+	}
 }